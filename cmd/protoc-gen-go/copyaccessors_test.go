@@ -0,0 +1,50 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestCopyAccessorsConflict(t *testing.T) {
+	file := new(descriptorpb.FileDescriptorProto)
+	if err := prototext.Unmarshal([]byte(`
+		name: "conflict.proto"
+		package: "goproto.conflict"
+		syntax: "proto3"
+		options: {go_package: "example.com/conflictpb"}
+		message_type: [{
+			name: "Message"
+			field: [
+				{name: "value" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".goproto.conflict.Message" json_name: "value"},
+				{name: "value_copy" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "valueCopy"}
+			]
+		}]
+	`), file); err != nil {
+		t.Fatal(err)
+	}
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"conflict.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gengo.Options{CopyAccessors: true}.GenerateFile(gen, gen.FilesByPath["conflict.proto"])
+	if err := gen.Response().GetError(); err != "" {
+		t.Fatal(err)
+	}
+	warnings := gen.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "goproto.conflict.Message.value") {
+		t.Errorf("Warnings() = %v, want a warning about goproto.conflict.Message.value", warnings)
+	}
+}
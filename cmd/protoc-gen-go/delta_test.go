@@ -0,0 +1,56 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	_ "google.golang.org/protobuf/types/godeltapb"
+)
+
+func TestDeltaEncodedInvalidField(t *testing.T) {
+	for _, tt := range []struct {
+		field string
+		want  string
+	}{{
+		field: `{name: "values" number: 1 label: LABEL_REPEATED type: TYPE_BYTES json_name: "values" options: {[braincorp.protobuf.delta.encoded]: INT64}}`,
+		want:  "only applies to singular bytes fields",
+	}, {
+		field: `{name: "values" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "values" options: {[braincorp.protobuf.delta.encoded]: INT64}}`,
+		want:  "only applies to singular bytes fields",
+	}, {
+		field: `{name: "values" number: 1 label: LABEL_OPTIONAL type: TYPE_BYTES json_name: "values" options: {[braincorp.protobuf.delta.encoded]: ELEMENT_TYPE_UNSPECIFIED}}`,
+		want:  "invalid delta encoded element type",
+	}} {
+		file := new(descriptorpb.FileDescriptorProto)
+		if err := prototext.Unmarshal([]byte(`
+			name: "delta.proto"
+			package: "goproto.delta"
+			syntax: "proto3"
+			options: {go_package: "example.com/deltapb"}
+			message_type: [{name: "Message" field: [`+tt.field+`]}]
+		`), file); err != nil {
+			t.Fatal(err)
+		}
+		gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+			FileToGenerate: []string{"delta.proto"},
+			ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		gengo.Options{}.GenerateFile(gen, gen.FilesByPath["delta.proto"])
+		if err := gen.Response().GetError(); !strings.Contains(err, tt.want) {
+			t.Errorf("GenerateFile(%v) error = %q, want an error containing %q", tt.field, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+func TestParseDeprecationHook(t *testing.T) {
+	for _, tt := range []struct {
+		in      string
+		want    protogen.GoIdent
+		wantErr bool
+	}{
+		{in: "example.com/telemetry.Count", want: protogen.GoIdent{GoName: "Count", GoImportPath: "example.com/telemetry"}},
+		{in: "example.com/v2/pkg.name.Count", want: protogen.GoIdent{GoName: "Count", GoImportPath: "example.com/v2/pkg.name"}},
+		{in: "telemetry.Count", want: protogen.GoIdent{GoName: "Count", GoImportPath: "telemetry"}},
+		{in: "Count", wantErr: true},
+		{in: "example.com/telemetry", wantErr: true},
+		{in: "example.com/telemetry.", wantErr: true},
+		{in: "example.com/telemetry.1Count", wantErr: true},
+	} {
+		got, err := gengo.ParseDeprecationHook(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDeprecationHook(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDeprecationHook(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"testing"
+
+	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+func TestRegisterFlags(t *testing.T) {
+	var flags flag.FlagSet
+	var opts gengo.Options
+	opts.RegisterFlags(&flags)
+	for _, p := range [][2]string{
+		{"copy_accessors", "true"},
+		{"fieldnums", "true"},
+		{"json_tags", "camel"},
+		{"deprecation_hook", "example.com/telemetry.Count"},
+		{"genproto_redirect", "google.golang.org/genproto=example.com/genproto"},
+	} {
+		if err := flags.Set(p[0], p[1]); err != nil {
+			t.Fatalf("Set(%q, %q) error: %v", p[0], p[1], err)
+		}
+	}
+	if !opts.CopyAccessors || !opts.FieldNumbers || opts.JSONStructTags != "camel" {
+		t.Errorf("options after Set = %+v, want CopyAccessors, FieldNumbers and camel JSON tags", opts)
+	}
+	if want := (protogen.GoIdent{GoName: "Count", GoImportPath: "example.com/telemetry"}); opts.DeprecationHook != want {
+		t.Errorf("DeprecationHook = %v, want %v", opts.DeprecationHook, want)
+	}
+	if got, want := opts.GenProtoRedirects.Rewrite("google.golang.org/genproto/foo"), protogen.GoImportPath("example.com/genproto/foo"); got != want {
+		t.Errorf("GenProtoRedirects.Rewrite = %v, want %v", got, want)
+	}
+
+	for _, p := range [][2]string{
+		{"json_tags", "snake"},
+		{"deprecation_hook", "Count"},
+	} {
+		if err := flags.Set(p[0], p[1]); err == nil {
+			t.Errorf("Set(%q, %q) succeeded, want error", p[0], p[1])
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal_gengo
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/godeltapb"
+)
+
+// A bytes field with the (braincorp.protobuf.delta.encoded) field option
+// of braincorp/protobuf/delta.proto stores a sequence of numbers in the
+// encoding of the protodelta package. The struct field is generated as a
+// slice of the numbers, which the runtime encodes when the message is
+// marshaled and decodes when it is unmarshaled.
+
+// deltaGoTypes maps the element types of delta encoded fields
+// to the Go types of the fields.
+var deltaGoTypes = map[godeltapb.ElementType]string{
+	godeltapb.ElementType_INT32:  "[]int32",
+	godeltapb.ElementType_INT64:  "[]int64",
+	godeltapb.ElementType_UINT32: "[]uint32",
+	godeltapb.ElementType_UINT64: "[]uint64",
+	godeltapb.ElementType_FLOAT:  "[]float32",
+	godeltapb.ElementType_DOUBLE: "[]float64",
+}
+
+// checkDeltaEncodedFields reports an error for each field of the message
+// that has the delta encoded option but cannot be delta encoded.
+func checkDeltaEncodedFields(gen *protogen.Plugin, m *messageInfo) {
+	for _, field := range m.Fields {
+		elemType, ok := deltaElementType(field)
+		switch {
+		case !ok:
+		case !canDeltaEncode(field):
+			gen.Errorf(field.Desc, "the delta encoded option only applies to singular bytes fields outside of a oneof and without a default value")
+		case deltaGoTypes[elemType] == "":
+			gen.Errorf(field.Desc, "invalid delta encoded element type %v", elemType)
+		}
+	}
+}
+
+// deltaGoType returns the Go type of a delta encoded field,
+// or the empty string if the field is not delta encoded.
+func deltaGoType(field *protogen.Field) string {
+	elemType, ok := deltaElementType(field)
+	if !ok || !canDeltaEncode(field) {
+		return ""
+	}
+	return deltaGoTypes[elemType]
+}
+
+// deltaElementType returns the element type in the delta encoded option
+// of the field and reports whether the field has the option.
+func deltaElementType(field *protogen.Field) (godeltapb.ElementType, bool) {
+	opts, ok := field.Desc.Options().(*descriptorpb.FieldOptions)
+	if !ok || !proto.HasExtension(opts, godeltapb.E_Encoded) {
+		return 0, false
+	}
+	return proto.GetExtension(opts, godeltapb.E_Encoded).(godeltapb.ElementType), true
+}
+
+// canDeltaEncode reports whether the delta encoded option applies to the field.
+func canDeltaEncode(field *protogen.Field) bool {
+	return field.Desc.Kind() == protoreflect.BytesKind &&
+		!field.Desc.IsList() &&
+		!field.Desc.HasDefault() &&
+		(field.Oneof == nil || field.Oneof.Desc.IsSynthetic())
+}
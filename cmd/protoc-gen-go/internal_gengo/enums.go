@@ -0,0 +1,73 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal_gengo
+
+import (
+	"path"
+	"strconv"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/internal/genid"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// enumsPackageName is the name of the sibling package
+// generated when Options.EnumsPackage is set.
+const enumsPackageName = "enums"
+
+// genEnumsPackageFile generates the file of the sibling enums package for
+// the file, which declares untyped constants for the enum values and the
+// enum value maps without depending on the protobuf runtime.
+func genEnumsPackageFile(gen *protogen.Plugin, f *fileInfo) {
+	if len(f.allEnums) == 0 {
+		return
+	}
+	prefix := f.GeneratedFilenamePrefix
+	filename := path.Join(path.Dir(prefix), enumsPackageName, path.Base(prefix)+".pb.go")
+	g := gen.NewGeneratedFile(filename, f.GoImportPath+"/"+enumsPackageName)
+
+	genStandaloneComments(g, f, int32(genid.FileDescriptorProto_Syntax_field_number))
+	genGeneratedHeader(gen, g, f)
+	g.P("// Package ", enumsPackageName, " declares the values of the enums of the Go package")
+	g.P("// ", string(f.GoImportPath), ",")
+	g.P("// so that they can be used without depending on its message types.")
+	g.P("package ", enumsPackageName)
+	g.P()
+
+	for _, e := range f.allEnums {
+		g.P("// Values of ", e.GoIdent.GoName, ", which is the enum ", e.Desc.FullName(), ".")
+		g.P("const (")
+		for _, value := range e.Values {
+			leadingComments := appendDeprecationSuffix(value.Comments.Leading,
+				value.Desc.ParentFile(),
+				value.Desc.Options().(*descriptorpb.EnumValueOptions).GetDeprecated())
+			g.P(leadingComments,
+				value.GoIdent.GoName, " = ", value.Desc.Number(),
+				trailingComment(value.Comments.Trailing))
+		}
+		g.P(")")
+		g.P()
+
+		g.P("// Enum value maps for ", e.GoIdent.GoName, ".")
+		g.P("var (")
+		g.P(e.GoIdent.GoName+"_name", " = map[int32]string{")
+		for _, value := range e.Values {
+			duplicate := ""
+			if value.Desc != e.Desc.Values().ByNumber(value.Desc.Number()) {
+				duplicate = "// Duplicate value: "
+			}
+			g.P(duplicate, value.Desc.Number(), ": ", strconv.Quote(string(value.Desc.Name())), ",")
+		}
+		g.P("}")
+		g.P(e.GoIdent.GoName+"_value", " = map[string]int32{")
+		for _, value := range e.Values {
+			g.P(strconv.Quote(string(value.Desc.Name())), ": ", value.Desc.Number(), ",")
+		}
+		g.P("}")
+		g.P(")")
+		g.P()
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal_gengo
+
+import (
+	"flag"
+	"fmt"
+)
+
+// RegisterFlags registers the generator parameters that set the options
+// in flags, so that protoc-gen-go and other binaries embedding the generator
+// accept the same parameters. The options are set as the parameters are
+// parsed, for example by passing flags.Set as the protogen.Options.ParamFunc.
+func (o *Options) RegisterFlags(flags *flag.FlagSet) {
+	flags.BoolVar(&o.CopyAccessors, "copy_accessors", false, "generate a GetXCopy method for each message-typed field, which returns a deep copy of the field value")
+	flags.BoolVar(&o.MapAccessors, "map_accessors", false, "generate GetXOrDefault, GetXOrZero, GetXKeys, and GetXValues methods for each map field")
+	flags.BoolVar(&o.PresenceAccessors, "presence_accessors", false, "generate HasX and TryGetX methods for each singular field with explicit presence")
+	flags.BoolVar(&o.ExtensionAccessors, "extension_accessors", false, "generate typed GetX, SetX, HasX, and ClearX functions for each extension field")
+	flags.BoolVar(&o.FieldDescriptors, "field_descriptors", false, "generate an M_Fields variable for each message M containing the descriptors of its fields")
+	flags.BoolVar(&o.FieldNumbers, "fieldnums", false, "generate M_F_field_number and M_F_field_name constants for each field F of each message M")
+	flags.BoolVar(&o.GoFixDirectives, "go_fix", false, "generate //go:fix inline directives for deprecated enum values that are aliases of non-deprecated values; deprecated fields and messages name no replacement, so they get no directives")
+	flags.BoolVar(&o.ValidateMethods, "validate_methods", false, "generate a Validate method for each message, which checks the rules of the (braincorp.protobuf.validate.rules) field option defined in braincorp/protobuf/validate.proto")
+	flags.Func("json_tags", "names in the encoding/json struct tags of generated fields: \"camel\" for the JSON names used by protojson, or \"original\" for the names in the .proto file; both also tag the fields of oneof wrapper types", func(s string) error {
+		switch s {
+		case "", "camel", "original":
+		default:
+			return fmt.Errorf("invalid value for json_tags: %q (must be \"camel\" or \"original\")", s)
+		}
+		o.JSONStructTags = s
+		return nil
+	})
+	flags.Func("deprecation_hook", "call the function IMPORT_PATH.NAME, which has the signature func(field, accessor string), in the getters and setters of deprecated fields to count their uses", func(s string) error {
+		if s == "" {
+			o.DeprecationHook.GoName = ""
+			return nil
+		}
+		hook, err := ParseDeprecationHook(s)
+		if err != nil {
+			return err
+		}
+		o.DeprecationHook = hook
+		return nil
+	})
+	flags.BoolVar(&o.OmitLegacyMethods, "omit_legacy_methods", false, "omit the Enum method of enums and the deprecated EnumDescriptor and Descriptor methods of enums and messages")
+	flags.BoolVar(&o.EnumsPackage, "enums_package", false, "generate a sibling Go package named enums within the Go package of each file with enums, which declares the enum values as untyped constants without depending on the protobuf runtime")
+	flags.Var(&o.GenProtoRedirects, "genproto_redirect", "redirect imports of the Go package FROM and its subpackages to TO, given as FROM=TO; may be repeated")
+}
@@ -16,6 +16,7 @@ import (
 
 type fileInfo struct {
 	*protogen.File
+	opts Options
 
 	allEnums      []*enumInfo
 	allMessages   []*messageInfo
@@ -47,8 +48,8 @@ func (sf *structFields) append(name string) {
 	sf.count++
 }
 
-func newFileInfo(file *protogen.File) *fileInfo {
-	f := &fileInfo{File: file}
+func newFileInfo(file *protogen.File, opts Options) *fileInfo {
+	f := &fileInfo{File: file, opts: opts}
 
 	// Collect all enums, messages, and extensions in "flattened ordering".
 	// See filetype.TypeBuilder.
@@ -113,7 +114,7 @@ type enumInfo struct {
 func newEnumInfo(f *fileInfo, enum *protogen.Enum) *enumInfo {
 	e := &enumInfo{Enum: enum}
 	e.genJSONMethod = true
-	e.genRawDescMethod = true
+	e.genRawDescMethod = !f.opts.OmitLegacyMethods
 	return e
 }
 
@@ -129,7 +130,7 @@ type messageInfo struct {
 
 func newMessageInfo(f *fileInfo, message *protogen.Message) *messageInfo {
 	m := &messageInfo{Message: message}
-	m.genRawDescMethod = true
+	m.genRawDescMethod = !f.opts.OmitLegacyMethods
 	m.genExtRangeMethod = true
 	m.isTracked = isTrackedMessage(m)
 	for _, field := range m.Fields {
@@ -21,6 +21,7 @@ import (
 	"google.golang.org/protobuf/internal/encoding/tag"
 	"google.golang.org/protobuf/internal/filedesc"
 	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/internal/strs"
 	"google.golang.org/protobuf/internal/version"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/runtime/protoimpl"
@@ -38,13 +39,115 @@ var SupportedEditionsMaximum = editionssupport.Maximum
 // GenerateVersionMarkers specifies whether to generate version markers.
 var GenerateVersionMarkers = true
 
+// Options are the options of the generator. The zero value generates
+// the same code as protoc-gen-go without any options.
+type Options struct {
+	// CopyAccessors specifies whether to generate a GetXCopy method
+	// for each message-typed field, which returns a deep copy of the field value.
+	CopyAccessors bool
+
+	// MapAccessors specifies whether to generate GetXOrDefault,
+	// GetXOrZero, GetXKeys, and GetXValues methods for each map field.
+	MapAccessors bool
+
+	// PresenceAccessors specifies whether to generate HasX and TryGetX
+	// methods for each singular field with explicit presence, which report
+	// whether the field is populated without comparing against the zero value.
+	PresenceAccessors bool
+
+	// FieldDescriptors specifies whether to generate an M_Fields variable
+	// for each message M, which contains the descriptors of the fields of M
+	// so that users of protoreflect need not look fields up by name.
+	// Since the variables are populated when the package is initialized,
+	// the descriptors of files that declare them are not lazily initialized.
+	FieldDescriptors bool
+
+	// FieldNumbers specifies whether to generate M_F_field_number and
+	// M_F_field_name constants for each field F of each message M, which contain
+	// the number and the name of the field in the .proto file.
+	FieldNumbers bool
+
+	// ExtensionAccessors specifies whether to generate typed GetX, SetX,
+	// HasX, and ClearX functions for each extension field declared in a file.
+	ExtensionAccessors bool
+
+	// GoFixDirectives specifies whether to generate "//go:fix inline"
+	// directives for deprecated enum values that are aliases of a value
+	// that is not deprecated, so that tools can migrate their usages.
+	//
+	// Deprecated fields, messages, and getters get only the "Deprecated:"
+	// comment. The directive inlines a declaration into its uses, so it
+	// requires the replacement to be expressed in Go, as it is for an alias
+	// constant. A .proto file only marks a field or message as deprecated,
+	// without naming a replacement to inline, and struct fields cannot be
+	// inlined at all.
+	GoFixDirectives bool
+
+	// ValidateMethods specifies whether to generate a Validate method
+	// for each message, which checks the rules declared with the
+	// (braincorp.protobuf.validate.rules) field option
+	// of braincorp/protobuf/validate.proto.
+	ValidateMethods bool
+
+	// JSONStructTags specifies the names in the encoding/json struct tags of
+	// generated fields. If "camel", the tags use the lowerCamelCase JSON names
+	// of the fields, as used by protojson. If "original", the tags use the names
+	// of the fields in the .proto file. In both cases, oneof fields and the fields
+	// of the wrapper types of oneof members are tagged as well. If empty, only
+	// the other fields of messages are tagged, using the names in the .proto file.
+	JSONStructTags string
+
+	// DeprecationHook specifies a function to call in the getters and setters
+	// of fields that are marked as deprecated, so that the uses of deprecated
+	// fields can be counted before the fields are removed. The function must have
+	// the signature func(field, accessor string) and is called with the full name
+	// of the field and the name of the method, such as "GetFoo".
+	// If the GoName is empty, no calls are generated.
+	DeprecationHook protogen.GoIdent
+
+	// OmitLegacyMethods specifies whether to omit the Enum method of enums,
+	// which returns a pointer to the value, and the deprecated EnumDescriptor
+	// and Descriptor methods of enums and messages, which return the legacy
+	// raw descriptor. This reduces the size of generated code for packages
+	// whose users do not set enum fields with explicit presence.
+	OmitLegacyMethods bool
+
+	// EnumsPackage specifies whether to additionally generate a sibling
+	// Go package named "enums" within the Go package of each file that declares
+	// enums. It declares untyped constants for the enum values and the enum value
+	// maps, so that packages needing only the enum values do not depend on the
+	// message types of the file and the protobuf runtime.
+	EnumsPackage bool
+
+	// GenProtoRedirects are the redirects of the genproto_redirect option.
+	// The init function of each file in a package beneath the TO path of a
+	// redirect registers the file as a replacement for the file in the
+	// corresponding genproto package with protoregistry.RegisterGenProtoAlias.
+	GenProtoRedirects ImportRedirects
+}
+
+// ParseDeprecationHook parses the value of the deprecation_hook option,
+// which names the [Options.DeprecationHook] function in the form
+// IMPORT_PATH.NAME, such as "example.com/telemetry.CountDeprecated".
+func ParseDeprecationHook(s string) (protogen.GoIdent, error) {
+	i := strings.LastIndexByte(s, '.')
+	if i <= strings.LastIndexByte(s, '/') || i == len(s)-1 || !token.IsIdentifier(s[i+1:]) {
+		return protogen.GoIdent{}, fmt.Errorf("invalid deprecation hook %q: want IMPORT_PATH.NAME", s)
+	}
+	return protogen.GoIdent{GoName: s[i+1:], GoImportPath: protogen.GoImportPath(s[:i])}, nil
+}
+
 // Standard library dependencies.
 const (
 	base64Package  = protogen.GoImportPath("encoding/base64")
+	bitsPackage    = protogen.GoImportPath("math/bits")
+	fmtPackage     = protogen.GoImportPath("fmt")
 	jsonPackage    = protogen.GoImportPath("encoding/json")
 	mathPackage    = protogen.GoImportPath("math")
 	reflectPackage = protogen.GoImportPath("reflect")
+	regexpPackage  = protogen.GoImportPath("regexp")
 	sortPackage    = protogen.GoImportPath("sort")
+	strconvPackage = protogen.GoImportPath("strconv")
 	stringsPackage = protogen.GoImportPath("strings")
 	syncPackage    = protogen.GoImportPath("sync")
 	timePackage    = protogen.GoImportPath("time")
@@ -57,6 +160,7 @@ const (
 // patched to support unique build environments that impose restrictions
 // on the dependencies of generated source code.
 var (
+	durationpbPackage    goImportPath = protogen.GoImportPath("google.golang.org/protobuf/types/known/durationpb")
 	protoPackage         goImportPath = protogen.GoImportPath("google.golang.org/protobuf/proto")
 	protoifacePackage    goImportPath = protogen.GoImportPath("google.golang.org/protobuf/runtime/protoiface")
 	protoimplPackage     goImportPath = protogen.GoImportPath("google.golang.org/protobuf/runtime/protoimpl")
@@ -70,11 +174,18 @@ type goImportPath interface {
 	Ident(string) protogen.GoIdent
 }
 
-// GenerateFile generates the contents of a .pb.go file.
+// GenerateFile generates the contents of a .pb.go file using the zero
+// Options. It is shorthand for Options{}.GenerateFile, which is kept for
+// the callers that predate Options.
 func GenerateFile(gen *protogen.Plugin, file *protogen.File) *protogen.GeneratedFile {
+	return Options{}.GenerateFile(gen, file)
+}
+
+// GenerateFile generates the contents of a .pb.go file.
+func (opts Options) GenerateFile(gen *protogen.Plugin, file *protogen.File) *protogen.GeneratedFile {
 	filename := file.GeneratedFilenamePrefix + ".pb.go"
 	g := gen.NewGeneratedFile(filename, file.GoImportPath)
-	f := newFileInfo(file)
+	f := newFileInfo(file, opts)
 
 	var packageDoc protogen.Comments
 	if !gen.InternalStripForEditionsDiff() {
@@ -105,9 +216,14 @@ func GenerateFile(gen *protogen.Plugin, file *protogen.File) *protogen.Generated
 		genEnum(g, f, enum)
 	}
 	for _, message := range f.allMessages {
-		genMessage(g, f, message)
+		genMessage(gen, g, f, message)
+		checkDeltaEncodedFields(gen, message)
+		if f.opts.ValidateMethods {
+			genMessageValidateMethod(gen, g, f, message)
+		}
 	}
 	genExtensions(g, f)
+	genFileKnownFunctions(g, f)
 
 	// The descriptor contains a lot of information about the syntax which is
 	// quite different between the proto2/3 version of a file and the equivalent
@@ -126,6 +242,9 @@ func GenerateFile(gen *protogen.Plugin, file *protogen.File) *protogen.Generated
 	if !g.InternalStripForEditionsDiff() {
 		genReflectFileDescriptor(gen, g, f)
 	}
+	if f.opts.EnumsPackage {
+		genEnumsPackageFile(gen, f)
+	}
 
 	return g
 }
@@ -262,11 +381,19 @@ func genEnum(g *protogen.GeneratedFile, f *fileInfo, e *enumInfo) {
 	g.P("const (")
 	for _, value := range e.Values {
 		g.AnnotateSymbol(value.GoIdent.GoName, protogen.Annotation{Location: value.Location})
+		deprecated := value.Desc.Options().(*descriptorpb.EnumValueOptions).GetDeprecated()
 		leadingComments := appendDeprecationSuffix(value.Comments.Leading,
 			value.Desc.ParentFile(),
-			value.Desc.Options().(*descriptorpb.EnumValueOptions).GetDeprecated())
+			deprecated)
+		var rhs any = value.Desc.Number()
+		if f.opts.GoFixDirectives && deprecated {
+			if alias := aliasedEnumValue(e, value); alias != nil {
+				leadingComments += "\ngo:fix inline\n"
+				rhs = alias.GoIdent
+			}
+		}
 		g.P(leadingComments,
-			value.GoIdent, " ", e.GoIdent, " = ", value.Desc.Number(),
+			value.GoIdent, " ", e.GoIdent, " = ", rhs,
 			trailingComment(value.Comments.Trailing))
 	}
 	g.P(")")
@@ -297,12 +424,14 @@ func genEnum(g *protogen.GeneratedFile, f *fileInfo, e *enumInfo) {
 	// NOTE: A pointer value is needed to represent presence in proto2.
 	// Since a proto2 message can reference a proto3 enum, it is useful to
 	// always generate this method (even on proto3 enums) to support that case.
-	g.P("func (x ", e.GoIdent, ") Enum() *", e.GoIdent, " {")
-	g.P("p := new(", e.GoIdent, ")")
-	g.P("*p = x")
-	g.P("return p")
-	g.P("}")
-	g.P()
+	if !f.opts.OmitLegacyMethods {
+		g.P("func (x ", e.GoIdent, ") Enum() *", e.GoIdent, " {")
+		g.P("p := new(", e.GoIdent, ")")
+		g.P("*p = x")
+		g.P("return p")
+		g.P("}")
+		g.P()
+	}
 
 	// String method.
 	g.P("func (x ", e.GoIdent, ") String() string {")
@@ -345,7 +474,17 @@ func genEnum(g *protogen.GeneratedFile, f *fileInfo, e *enumInfo) {
 	}
 }
 
-func genMessage(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
+// aliasedEnumValue returns the value of e that value is an alias of,
+// provided that it is not deprecated. Otherwise, it returns nil.
+func aliasedEnumValue(e *enumInfo, value *protogen.EnumValue) *protogen.EnumValue {
+	vd := e.Desc.Values().ByNumber(value.Desc.Number())
+	if vd == value.Desc || vd.Options().(*descriptorpb.EnumValueOptions).GetDeprecated() {
+		return nil
+	}
+	return e.Values[vd.Index()]
+}
+
+func genMessage(gen *protogen.Plugin, g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
 	if m.Desc.IsMapEntry() {
 		return
 	}
@@ -363,8 +502,14 @@ func genMessage(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
 
 	genMessageKnownFunctions(g, f, m)
 	genMessageDefaultDecls(g, f, m)
-	genMessageMethods(g, f, m)
+	genMessageMethods(gen, g, f, m)
 	genMessageOneofWrapperTypes(g, f, m)
+	if f.opts.FieldDescriptors {
+		genMessageFieldDescriptorsVar(g, f, m)
+	}
+	if f.opts.FieldNumbers {
+		genMessageFieldNumbers(g, f, m)
+	}
 }
 
 func genMessageFields(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
@@ -376,6 +521,8 @@ func genMessageFields(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
 }
 
 func genMessageInternalFields(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, sf *structFields) {
+	// MessageState contains a no-copy marker,
+	// which makes go vet report copies of the message by value.
 	g.P(genid.State_goname, " ", protoimplPackage.Ident("MessageState"))
 	sf.append(genid.State_goname)
 	g.P(genid.SizeCache_goname, " ", protoimplPackage.Ident("SizeCache"))
@@ -408,6 +555,13 @@ func genMessageField(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, fie
 		tags := structTags{
 			{"protobuf_oneof", string(oneof.Desc.Name())},
 		}
+		if f.opts.JSONStructTags != "" {
+			name := string(oneof.Desc.Name())
+			if f.opts.JSONStructTags == "camel" {
+				name = strs.JSONCamelCase(name)
+			}
+			tags = append(tags, structTags{{"json", name + ",omitempty"}}...)
+		}
 		if m.isTracked {
 			tags = append(tags, gotrackTags...)
 		}
@@ -433,7 +587,7 @@ func genMessageField(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, fie
 	}
 	tags := structTags{
 		{"protobuf", fieldProtobufTagValue(field)},
-		{"json", fieldJSONTagValue(field)},
+		{"json", fieldJSONTagValue(f, field)},
 	}
 	if field.Desc.IsMap() {
 		key := field.Message.Fields[0]
@@ -527,9 +681,18 @@ func genMessageDefaultDecls(g *protogen.GeneratedFile, f *fileInfo, m *messageIn
 	g.P()
 }
 
-func genMessageMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
+func genMessageMethods(gen *protogen.Plugin, g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
 	genMessageBaseMethods(g, f, m)
 	genMessageGetterMethods(g, f, m)
+	if f.opts.CopyAccessors {
+		genMessageCopyGetterMethods(gen, g, f, m)
+	}
+	if f.opts.MapAccessors {
+		genMessageMapGetterMethods(g, f, m)
+	}
+	if f.opts.PresenceAccessors {
+		genMessagePresenceMethods(g, f, m)
+	}
 	genMessageSetterMethods(g, f, m)
 }
 
@@ -597,6 +760,7 @@ func genMessageGetterMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageI
 		switch {
 		case field.Desc.IsWeak():
 			g.P(leadingComments, "func (x *", m.GoIdent, ") Get", field.GoName, "() ", protoPackage.Ident("Message"), "{")
+			genDeprecationHookCall(g, f, field, "Get"+field.GoName)
 			g.P("var w ", protoimplPackage.Ident("WeakFields"))
 			g.P("if x != nil {")
 			g.P("w = x.", genid.WeakFields_goname)
@@ -608,6 +772,7 @@ func genMessageGetterMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageI
 			g.P("}")
 		case field.Oneof != nil && !field.Oneof.Desc.IsSynthetic():
 			g.P(leadingComments, "func (x *", m.GoIdent, ") Get", field.GoName, "() ", goType, " {")
+			genDeprecationHookCall(g, f, field, "Get"+field.GoName)
 			g.P("if x, ok := x.Get", field.Oneof.GoName, "().(*", field.GoIdent, "); ok {")
 			g.P("return x.", field.GoName)
 			g.P("}")
@@ -615,6 +780,7 @@ func genMessageGetterMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageI
 			g.P("}")
 		default:
 			g.P(leadingComments, "func (x *", m.GoIdent, ") Get", field.GoName, "() ", goType, " {")
+			genDeprecationHookCall(g, f, field, "Get"+field.GoName)
 			if !field.Desc.HasPresence() || defaultValue == "nil" {
 				g.P("if x != nil {")
 			} else {
@@ -633,6 +799,277 @@ func genMessageGetterMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageI
 	}
 }
 
+func genMessageCopyGetterMethods(gen *protogen.Plugin, g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
+	// A copy accessor must not conflict with the other methods.
+	usedNames := m.GoNames()
+	for _, field := range m.Fields {
+		if field.Desc.IsWeak() || field.Message == nil {
+			continue
+		}
+		valueField := field
+		if field.Desc.IsMap() {
+			valueField = field.Message.Fields[1]
+			if valueField.Message == nil {
+				continue
+			}
+		}
+		name := "Get" + field.GoName + "Copy"
+		if usedNames.Has(name) {
+			gen.Warnf(field.Desc, "not generating the copy accessor of %v, which conflicts with a field", field.Desc.FullName())
+			continue
+		}
+
+		genNoInterfacePragma(g, m.isTracked)
+
+		goType, _ := fieldGoType(g, f, field)
+		valueType := "*" + g.QualifiedGoIdent(valueField.Message.GoIdent)
+		clone := func(v string) string {
+			return g.QualifiedGoIdent(protoPackage.Ident("Clone")) + "(" + v + ").(" + valueType + ")"
+		}
+		g.AnnotateSymbol(m.GoIdent.GoName+"."+name, protogen.Annotation{Location: field.Location})
+		leadingComments := appendDeprecationSuffix(
+			protogen.Comments(" "+name+" returns a deep copy of the value returned by Get"+field.GoName+".\n"),
+			field.Desc.ParentFile(),
+			field.Desc.Options().(*descriptorpb.FieldOptions).GetDeprecated())
+		g.P(leadingComments, "func (x *", m.GoIdent, ") ", name, "() ", goType, " {")
+		genDeprecationHookCall(g, f, field, name)
+		switch {
+		case field.Desc.IsList():
+			g.P("src := x.Get", field.GoName, "()")
+			g.P("if src == nil {")
+			g.P("return nil")
+			g.P("}")
+			g.P("dst := make(", goType, ", len(src))")
+			g.P("for i, v := range src {")
+			g.P("dst[i] = ", clone("v"))
+			g.P("}")
+			g.P("return dst")
+		case field.Desc.IsMap():
+			g.P("src := x.Get", field.GoName, "()")
+			g.P("if src == nil {")
+			g.P("return nil")
+			g.P("}")
+			g.P("dst := make(", goType, ", len(src))")
+			g.P("for k, v := range src {")
+			g.P("dst[k] = ", clone("v"))
+			g.P("}")
+			g.P("return dst")
+		default:
+			g.P("return ", clone("x.Get"+field.GoName+"()"))
+		}
+		g.P("}")
+		g.P()
+	}
+}
+
+func genMessageMapGetterMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
+	// A map accessor must not conflict with the other methods.
+	usedNames := m.GoNames()
+	for _, field := range m.Fields {
+		if !field.Desc.IsMap() {
+			continue
+		}
+		keyType, _ := fieldGoType(g, f, field.Message.Fields[0])
+		valType, _ := fieldGoType(g, f, field.Message.Fields[1])
+		deprecated := field.Desc.Options().(*descriptorpb.FieldOptions).GetDeprecated()
+		genMethod := func(name, comment, signature string, body func()) {
+			if usedNames.Has(name) {
+				return
+			}
+			genNoInterfacePragma(g, m.isTracked)
+			g.AnnotateSymbol(m.GoIdent.GoName+"."+name, protogen.Annotation{Location: field.Location})
+			leadingComments := appendDeprecationSuffix(
+				protogen.Comments(" "+name+" "+comment+"\n"),
+				field.Desc.ParentFile(), deprecated)
+			g.P(leadingComments, "func (x *", m.GoIdent, ") ", name, signature, " {")
+			body()
+			g.P("}")
+			g.P()
+		}
+
+		genMethod("Get"+field.GoName+"OrDefault",
+			"returns the value for key in the "+field.GoName+" map field,\n or def if the key is not present.",
+			"(key "+keyType+", def "+valType+") "+valType,
+			func() {
+				g.P("if v, ok := x.Get", field.GoName, "()[key]; ok {")
+				g.P("return v")
+				g.P("}")
+				g.P("return def")
+			})
+		genMethod("Get"+field.GoName+"OrZero",
+			"returns the value for key in the "+field.GoName+" map field,\n or the zero value if the key is not present.",
+			"(key "+keyType+") "+valType,
+			func() {
+				g.P("return x.Get", field.GoName, "()[key]")
+			})
+
+		// The keys are sorted so that the results are deterministic.
+		less := "keys[i] < keys[j]"
+		if field.Message.Fields[0].Desc.Kind() == protoreflect.BoolKind {
+			less = "!keys[i] && keys[j]"
+		}
+		genMethod("Get"+field.GoName+"Keys",
+			"returns the sorted keys of the "+field.GoName+" map field.\n The result is a new slice, which the caller may modify.",
+			"() []"+keyType,
+			func() {
+				g.P("src := x.Get", field.GoName, "()")
+				g.P("if len(src) == 0 {")
+				g.P("return nil")
+				g.P("}")
+				g.P("keys := make([]", keyType, ", 0, len(src))")
+				g.P("for k := range src {")
+				g.P("keys = append(keys, k)")
+				g.P("}")
+				g.P(sortPackage.Ident("Slice"), "(keys, func(i, j int) bool { return ", less, " })")
+				g.P("return keys")
+			})
+		genMethod("Get"+field.GoName+"Values",
+			"returns the values of the "+field.GoName+" map field\n in the order of their sorted keys.\n The result is a new slice, which the caller may modify.",
+			"() []"+valType,
+			func() {
+				g.P("src := x.Get", field.GoName, "()")
+				g.P("if len(src) == 0 {")
+				g.P("return nil")
+				g.P("}")
+				g.P("keys := make([]", keyType, ", 0, len(src))")
+				g.P("for k := range src {")
+				g.P("keys = append(keys, k)")
+				g.P("}")
+				g.P(sortPackage.Ident("Slice"), "(keys, func(i, j int) bool { return ", less, " })")
+				g.P("vals := make([]", valType, ", len(keys))")
+				g.P("for i, k := range keys {")
+				g.P("vals[i] = src[k]")
+				g.P("}")
+				g.P("return vals")
+			})
+	}
+}
+
+func genMessagePresenceMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
+	// A presence accessor must not conflict with the other methods.
+	usedNames := m.GoNames()
+	for _, field := range m.Fields {
+		if !field.Desc.HasPresence() || field.Desc.IsList() || field.Desc.IsMap() || field.Desc.IsWeak() {
+			continue
+		}
+		hasName, tryName := "Has"+field.GoName, "TryGet"+field.GoName
+		if usedNames.Has(hasName) || usedNames.Has(tryName) {
+			continue
+		}
+
+		goType, pointer := fieldGoType(g, f, field)
+		defaultValue := fieldDefaultValue(g, f, m, field)
+		deprecated := field.Desc.Options().(*descriptorpb.FieldOptions).GetDeprecated()
+		isOneof := field.Oneof != nil && !field.Oneof.Desc.IsSynthetic()
+
+		genNoInterfacePragma(g, m.isTracked)
+		g.AnnotateSymbol(m.GoIdent.GoName+"."+hasName, protogen.Annotation{Location: field.Location})
+		leadingComments := appendDeprecationSuffix(
+			protogen.Comments(" "+hasName+" reports whether the "+field.GoName+" field is populated.\n"),
+			field.Desc.ParentFile(), deprecated)
+		g.P(leadingComments, "func (x *", m.GoIdent, ") ", hasName, "() bool {")
+		if isOneof {
+			g.P("_, ok := x.Get", field.Oneof.GoName, "().(*", field.GoIdent, ")")
+			g.P("return ok")
+		} else {
+			g.P("return x != nil && x.", field.GoName, " != nil")
+		}
+		g.P("}")
+		g.P()
+
+		genNoInterfacePragma(g, m.isTracked)
+		g.AnnotateSymbol(m.GoIdent.GoName+"."+tryName, protogen.Annotation{Location: field.Location})
+		leadingComments = appendDeprecationSuffix(
+			protogen.Comments(" "+tryName+" returns the value of the "+field.GoName+" field and true if it is populated,\n"+
+				" or the value returned by Get"+field.GoName+" and false otherwise.\n"),
+			field.Desc.ParentFile(), deprecated)
+		g.P(leadingComments, "func (x *", m.GoIdent, ") ", tryName, "() (", goType, ", bool) {")
+		if isOneof {
+			g.P("if x, ok := x.Get", field.Oneof.GoName, "().(*", field.GoIdent, "); ok {")
+			g.P("return x.", field.GoName, ", true")
+			g.P("}")
+		} else {
+			star := ""
+			if pointer {
+				star = "*"
+			}
+			g.P("if x != nil && x.", field.GoName, " != nil {")
+			g.P("return ", star, "x.", field.GoName, ", true")
+			g.P("}")
+		}
+		g.P("return ", defaultValue, ", false")
+		g.P("}")
+		g.P()
+	}
+}
+
+func genMessageFieldDescriptorsVar(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
+	name, ok := fieldDescriptorsVarName(f, m)
+	if !ok || len(m.Fields) == 0 {
+		return
+	}
+	g.AnnotateSymbol(name, protogen.Annotation{Location: m.Location})
+	g.P("// ", name, " contains the descriptors of the fields of ", m.GoIdent.GoName, ",")
+	g.P("// which are populated when the file descriptor is initialized.")
+	g.P("var ", name, " struct {")
+	for _, field := range m.Fields {
+		g.P(field.GoName, " ", protoreflectPackage.Ident("FieldDescriptor"))
+	}
+	g.P("}")
+	g.P()
+}
+
+func genMessageFieldNumbers(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
+	if len(m.Fields) == 0 {
+		return
+	}
+	g.P("// Field names for ", m.Desc.FullName(), ".")
+	g.P("const (")
+	for _, field := range m.Fields {
+		g.P(m.GoIdent.GoName, "_", field.GoName, "_field_name ", protoreflectPackage.Ident("Name"), " = ", strconv.Quote(string(field.Desc.Name())))
+	}
+	g.P(")")
+	g.P()
+
+	g.P("// Field numbers for ", m.Desc.FullName(), ".")
+	g.P("const (")
+	for _, field := range m.Fields {
+		g.P(m.GoIdent.GoName, "_", field.GoName, "_field_number ", protoreflectPackage.Ident("FieldNumber"), " = ", field.Desc.Number())
+	}
+	g.P(")")
+	g.P()
+}
+
+// fieldDescriptorsVarName returns the name of the variable generated
+// for a message by Options.FieldDescriptors, reporting false if
+// it conflicts with another declaration generated for the file.
+func fieldDescriptorsVarName(f *fileInfo, m *messageInfo) (string, bool) {
+	name := m.GoIdent.GoName + "_Fields"
+	for _, e := range f.allEnums {
+		if e.GoIdent.GoName == name {
+			return name, false
+		}
+		for _, value := range e.Values {
+			if value.GoIdent.GoName == name {
+				return name, false
+			}
+		}
+	}
+	for _, message := range f.allMessages {
+		if message.GoIdent.GoName == name {
+			return name, false
+		}
+		for _, oneof := range message.Oneofs {
+			for _, field := range oneof.Fields {
+				if field.GoIdent.GoName == name {
+					return name, false
+				}
+			}
+		}
+	}
+	return name, true
+}
+
 func genMessageSetterMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
 	for _, field := range m.Fields {
 		if !field.Desc.IsWeak() {
@@ -649,6 +1086,7 @@ func genMessageSetterMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageI
 			field.Desc.ParentFile(),
 			field.Desc.Options().(*descriptorpb.FieldOptions).GetDeprecated())
 		g.P(leadingComments, "func (x *", m.GoIdent, ") Set", field.GoName, "(v ", protoPackage.Ident("Message"), ") {")
+		genDeprecationHookCall(g, f, field, "Set"+field.GoName)
 		g.P("var w *", protoimplPackage.Ident("WeakFields"))
 		g.P("if x != nil {")
 		g.P("w = &x.", genid.WeakFields_goname)
@@ -662,6 +1100,15 @@ func genMessageSetterMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageI
 	}
 }
 
+// genDeprecationHookCall generates a call to the DeprecationHook function
+// at the start of the accessor method of a deprecated field.
+func genDeprecationHookCall(g *protogen.GeneratedFile, f *fileInfo, field *protogen.Field, accessor string) {
+	if f.opts.DeprecationHook.GoName == "" || !field.Desc.Options().(*descriptorpb.FieldOptions).GetDeprecated() {
+		return
+	}
+	g.P(f.opts.DeprecationHook, "(", strconv.Quote(string(field.Desc.FullName())), ", ", strconv.Quote(accessor), ")")
+}
+
 // fieldGoType returns the Go type used for a field.
 //
 // If it returns pointer=true, the struct field is a pointer to the type.
@@ -670,6 +1117,10 @@ func fieldGoType(g *protogen.GeneratedFile, f *fileInfo, field *protogen.Field)
 		return "struct{}", false
 	}
 
+	if goType := deltaGoType(field); goType != "" {
+		return goType, false // rely on nullability of slices for presence
+	}
+
 	pointer = field.Desc.HasPresence()
 	switch field.Desc.Kind() {
 	case protoreflect.BoolKind:
@@ -749,7 +1200,10 @@ func fieldDefaultValue(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, f
 	}
 }
 
-func fieldJSONTagValue(field *protogen.Field) string {
+func fieldJSONTagValue(f *fileInfo, field *protogen.Field) string {
+	if f.opts.JSONStructTags == "camel" {
+		return field.Desc.JSONName() + ",omitempty"
+	}
 	return string(field.Desc.Name()) + ",omitempty"
 }
 
@@ -818,6 +1272,71 @@ func genExtensions(g *protogen.GeneratedFile, f *fileInfo) {
 		g.P(")")
 		g.P()
 	}
+
+	if f.opts.ExtensionAccessors {
+		genExtensionAccessors(g, f)
+	}
+}
+
+// genExtensionAccessors generates typed functions to access the value of
+// each extension field, which wrap the functions of the proto package.
+// Functions whose names conflict with other declarations in the file
+// are not generated.
+func genExtensionAccessors(g *protogen.GeneratedFile, f *fileInfo) {
+	declared := make(map[string]bool)
+	for _, e := range f.allEnums {
+		declared[e.GoIdent.GoName] = true
+		for _, v := range e.Values {
+			declared[v.GoIdent.GoName] = true
+		}
+	}
+	for _, m := range f.allMessages {
+		declared[m.GoIdent.GoName] = true
+		for _, o := range m.Oneofs {
+			for _, field := range o.Fields {
+				declared[field.GoIdent.GoName] = true
+			}
+		}
+	}
+	for _, x := range f.allExtensions {
+		declared["E_"+x.GoIdent.GoName] = true
+	}
+
+	for _, x := range f.allExtensions {
+		name := x.GoIdent.GoName
+		if declared["Get"+name] || declared["Set"+name] || declared["Has"+name] || declared["Clear"+name] {
+			continue
+		}
+		goType, _ := fieldGoType(g, f, x.Extension)
+		extendee := g.QualifiedGoIdent(x.Extendee.GoIdent)
+		xd := "E_" + name
+		doc := func(format string, args ...any) protogen.Comments {
+			return appendDeprecationSuffix(protogen.Comments(fmt.Sprintf(format, args...)),
+				x.Desc.ParentFile(),
+				x.Desc.Options().(*descriptorpb.FieldOptions).GetDeprecated())
+		}
+
+		g.P(doc(" Get%s returns the value of the %s extension field in m,\n or the default value if it is not populated.\n", name, x.Desc.FullName()),
+			"func Get", name, "(m *", extendee, ") ", goType, " {")
+		g.P("return ", protoPackage.Ident("GetExtension"), "(m, ", xd, ").(", goType, ")")
+		g.P("}")
+		g.P()
+		g.P(doc(" Set%s sets the value of the %s extension field in m.\n", name, x.Desc.FullName()),
+			"func Set", name, "(m *", extendee, ", v ", goType, ") {")
+		g.P(protoPackage.Ident("SetExtension"), "(m, ", xd, ", v)")
+		g.P("}")
+		g.P()
+		g.P(doc(" Has%s reports whether the %s extension field is populated in m.\n", name, x.Desc.FullName()),
+			"func Has", name, "(m *", extendee, ") bool {")
+		g.P("return ", protoPackage.Ident("HasExtension"), "(m, ", xd, ")")
+		g.P("}")
+		g.P()
+		g.P(doc(" Clear%s clears the %s extension field in m.\n", name, x.Desc.FullName()),
+			"func Clear", name, "(m *", extendee, ") {")
+		g.P(protoPackage.Ident("ClearExtension"), "(m, ", xd, ")")
+		g.P("}")
+		g.P()
+	}
 }
 
 // genMessageOneofWrapperTypes generates the oneof wrapper types and
@@ -840,6 +1359,9 @@ func genMessageOneofWrapperTypes(g *protogen.GeneratedFile, f *fileInfo, m *mess
 			tags := structTags{
 				{"protobuf", fieldProtobufTagValue(field)},
 			}
+			if f.opts.JSONStructTags != "" {
+				tags = append(tags, structTags{{"json", fieldJSONTagValue(f, field)}}...)
+			}
 			if m.isTracked {
 				tags = append(tags, gotrackTags...)
 			}
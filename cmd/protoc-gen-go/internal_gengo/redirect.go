@@ -0,0 +1,108 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal_gengo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/internal/genid"
+)
+
+// ImportRedirects is the value of the genproto_redirect option,
+// which redirects the imports of generated packages to other packages.
+//
+// Each use of the option has the form FROM=TO and redirects the import path
+// FROM and all import paths beneath it to the corresponding paths beneath TO:
+//
+//	--go_opt=genproto_redirect=google.golang.org/genproto/googleapis=example.com/googleapis
+//
+// When the option is used, imports of genproto packages for the well-known
+// types are also redirected to their packages in this module,
+// so that generated code does not depend on the genproto module at all.
+//
+// Files generated into a package beneath TO replace the files of the
+// corresponding package beneath FROM. Their init functions register the
+// replacement with protoregistry.RegisterGenProtoAlias, so that linking in
+// both packages results in a panic that names them.
+type ImportRedirects struct {
+	from, to []string
+}
+
+func (r *ImportRedirects) String() string {
+	var ss []string
+	for i := range r.from {
+		ss = append(ss, r.from[i]+"="+r.to[i])
+	}
+	return strings.Join(ss, ",")
+}
+
+func (r *ImportRedirects) Set(s string) error {
+	from, to, ok := strings.Cut(s, "=")
+	if !ok || from == "" || to == "" {
+		return fmt.Errorf("invalid redirect %q: want FROM=TO", s)
+	}
+	r.from = append(r.from, strings.TrimSuffix(from, "/"))
+	r.to = append(r.to, strings.TrimSuffix(to, "/"))
+	return nil
+}
+
+// Rewrite implements the ImportRewriteFunc of [protogen.Options].
+// The longest matching redirect takes precedence.
+func (r *ImportRedirects) Rewrite(path protogen.GoImportPath) protogen.GoImportPath {
+	if len(r.from) == 0 {
+		return path
+	}
+	for _, pkg := range genid.GenProtoPackages {
+		if string(path) == pkg.GenProtoPath {
+			return protogen.GoImportPath(pkg.GoImportPath)
+		}
+	}
+	s := string(path)
+	i := longestPrefix(r.from, s)
+	if i < 0 {
+		return path
+	}
+	return protogen.GoImportPath(r.to[i] + strings.TrimPrefix(s, r.from[i]))
+}
+
+// GenProtoAlias reports the import path of the redirected package
+// that is replaced by the package at path, which is beneath the TO path
+// of the longest matching redirect.
+func (r *ImportRedirects) GenProtoAlias(path protogen.GoImportPath) (protogen.GoImportPath, bool) {
+	s := string(path)
+	i := longestPrefix(r.to, s)
+	if i < 0 {
+		return "", false
+	}
+	return protogen.GoImportPath(r.from[i] + strings.TrimPrefix(s, r.to[i])), true
+}
+
+// longestPrefix returns the index of the longest import path in prefixes
+// that is equal to or a parent of path, or -1 if there is none.
+func longestPrefix(prefixes []string, path string) int {
+	best := -1
+	for i, p := range prefixes {
+		if (path == p || strings.HasPrefix(path, p+"/")) && (best < 0 || len(p) > len(prefixes[best])) {
+			best = i
+		}
+	}
+	return best
+}
+
+// genGenProtoAlias generates a call to protoregistry.RegisterGenProtoAlias
+// if the file replaces a file of a redirected genproto package.
+func genGenProtoAlias(g *protogen.GeneratedFile, f *fileInfo) {
+	from, ok := f.opts.GenProtoRedirects.GenProtoAlias(f.GoImportPath)
+	if !ok {
+		return
+	}
+	g.P(protoregistryPackage.Ident("RegisterGenProtoAlias"), "(",
+		strconv.Quote(f.Desc.Path()), ", ",
+		strconv.Quote(string(from)), ", ",
+		strconv.Quote(string(f.GoImportPath)), ")")
+}
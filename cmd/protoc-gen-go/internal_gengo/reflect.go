@@ -183,6 +183,7 @@ func genReflectFileDescriptor(gen *protogen.Plugin, g *protogen.GeneratedFile, f
 		}
 	}
 
+	genGenProtoAlias(g, f)
 	g.P("type x struct{}")
 	g.P("out := ", protoimplPackage.Ident("TypeBuilder"), "{")
 	g.P("File: ", protoimplPackage.Ident("DescBuilder"), "{")
@@ -206,6 +207,17 @@ func genReflectFileDescriptor(gen *protogen.Plugin, g *protogen.GeneratedFile, f
 	}
 	g.P("}.Build()")
 	g.P(f.GoDescriptorIdent, " = out.File")
+	if f.opts.FieldDescriptors {
+		for i, message := range f.allMessages {
+			name, ok := fieldDescriptorsVarName(f, message)
+			if !ok || message.Desc.IsMapEntry() || len(message.Fields) == 0 {
+				continue
+			}
+			for j, field := range message.Fields {
+				g.P(name, ".", field.GoName, " = ", messageTypesVarName(f), "[", i, "].Descriptor().Fields().Get(", j, ")")
+			}
+		}
+	}
 
 	// Set inputs to nil to allow GC to reclaim resources.
 	g.P(rawDescVarName(f), " = nil")
@@ -0,0 +1,347 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal_gengo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/govalidatepb"
+)
+
+// genMessageValidateMethod generates a Validate method for the message,
+// which checks the rules declared with the (braincorp.protobuf.validate.rules)
+// field option.
+func genMessageValidateMethod(gen *protogen.Plugin, g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
+	if m.Desc.IsMapEntry() {
+		return
+	}
+	for _, field := range m.Fields {
+		if field.GoName == "Validate" || (field.Oneof != nil && field.Oneof.GoName == "Validate") {
+			gen.Warnf(field.Desc, "not generating the Validate method of %v, which conflicts with a field", m.Desc.FullName())
+			return
+		}
+	}
+
+	// Check the rules of each field and declare the compiled patterns.
+	rules := make(map[*protogen.Field]*govalidatepb.FieldRules)
+	patterns := make(map[*protogen.Field]string)
+	for _, field := range m.Fields {
+		r := fieldValidateRules(field)
+		if r == nil {
+			continue
+		}
+		if err := checkValidateRules(field, r); err != nil {
+			gen.Errorf(field.Desc, "invalid validation rules: %v", err)
+			continue
+		}
+		rules[field] = r
+		if r.Pattern != nil {
+			name := fileVarName(f.File, m.GoIdent.GoName+"_"+field.GoName+"_pattern")
+			g.P("var ", name, " = ", regexpPackage.Ident("MustCompile"), "(", strconv.Quote(r.GetPattern()), ")")
+			g.P()
+			patterns[field] = name
+		}
+	}
+
+	genNoInterfacePragma(g, m.isTracked)
+	g.P("// Validate reports whether x satisfies the validation rules declared for its")
+	g.P("// fields, returning an error that describes the first violation.")
+	g.P("// Message fields are validated with their own Validate method, if any.")
+	g.P("func (x *", m.GoIdent, ") Validate() error {")
+	g.P("if x == nil {")
+	g.P("return nil")
+	g.P("}")
+	for _, field := range m.Fields {
+		if field.Desc.IsWeak() {
+			continue
+		}
+		r, pattern := rules[field], patterns[field]
+		if r == nil {
+			r = &govalidatepb.FieldRules{}
+		}
+		name := string(field.Desc.FullName())
+		switch {
+		case field.Oneof != nil && !field.Oneof.Desc.IsSynthetic():
+			if r.GetRequired() {
+				g.P("if _, ok := x.", field.Oneof.GoName, ".(*", field.GoIdent, "); !ok {")
+				genValidateError(g, name+": value is required")
+				g.P("}")
+			}
+			if hasValueRules(field, r) {
+				g.P("if v, ok := x.", field.Oneof.GoName, ".(*", field.GoIdent, "); ok {")
+				genValueValidation(g, field, r, pattern, "v."+field.GoName, name)
+				g.P("}")
+			}
+		case field.Desc.IsList() || field.Desc.IsMap():
+			genLenValidation(g, r, "len(x."+field.GoName+")", name, "elements")
+			valueField, vars := field, "i, v"
+			if field.Desc.IsMap() {
+				valueField, vars = field.Message.Fields[1], "k, v"
+			}
+			// The length rules apply to the field rather than to its values.
+			r = &govalidatepb.FieldRules{Min: r.Min, Max: r.Max, Pattern: r.Pattern}
+			if hasValueRules(valueField, r) {
+				g.P("for ", vars, " := range x.", field.GoName, " {")
+				if field.Desc.IsMap() {
+					genValueValidation(g, valueField, r, pattern, "v", name+"[%v]", "k")
+				} else {
+					genValueValidation(g, valueField, r, pattern, "v", name+"[%d]", "i")
+				}
+				g.P("}")
+			}
+		default:
+			_, pointer := fieldGoType(g, f, field)
+			if r.GetRequired() {
+				g.P("if ", zeroValueCond(field, pointer, "x."+field.GoName), " {")
+				genValidateError(g, name+": value is required")
+				g.P("}")
+			}
+			if !hasValueRules(field, r) {
+				continue
+			}
+			if pointer {
+				g.P("if x.", field.GoName, " != nil {")
+				genValueValidation(g, field, r, pattern, "*x."+field.GoName, name)
+				g.P("}")
+			} else {
+				genValueValidation(g, field, r, pattern, "x."+field.GoName, name)
+			}
+		}
+	}
+	g.P("return nil")
+	g.P("}")
+	g.P()
+}
+
+// fieldValidateRules returns the validation rules of the field,
+// or nil if it has none.
+func fieldValidateRules(field *protogen.Field) *govalidatepb.FieldRules {
+	opts, ok := field.Desc.Options().(*descriptorpb.FieldOptions)
+	if !ok || !proto.HasExtension(opts, govalidatepb.E_Rules) {
+		return nil
+	}
+	return proto.GetExtension(opts, govalidatepb.E_Rules).(*govalidatepb.FieldRules)
+}
+
+// checkValidateRules reports whether the rules apply to the field.
+func checkValidateRules(field *protogen.Field, r *govalidatepb.FieldRules) error {
+	kind := field.Desc.Kind()
+	if field.Desc.IsMap() {
+		kind = field.Desc.MapValue().Kind()
+	}
+	if r.Min != nil || r.Max != nil {
+		if !isNumericKind(kind) {
+			return errors.New("min and max apply only to numeric fields")
+		}
+		for _, b := range []*float64{r.Min, r.Max} {
+			if b == nil {
+				continue
+			}
+			if err := checkValidateBound(kind, *b); err != nil {
+				return err
+			}
+		}
+		if r.Min != nil && r.Max != nil && r.GetMin() > r.GetMax() {
+			return fmt.Errorf("min %v is greater than max %v", r.GetMin(), r.GetMax())
+		}
+	}
+	if r.Pattern != nil {
+		if kind != protoreflect.StringKind && kind != protoreflect.BytesKind {
+			return errors.New("pattern applies only to string and bytes fields")
+		}
+		if deltaGoType(field) != "" {
+			return errors.New("pattern does not apply to delta encoded fields")
+		}
+		if _, err := regexp.Compile(r.GetPattern()); err != nil {
+			return fmt.Errorf("invalid pattern: %v", err)
+		}
+	}
+	if r.MinLen != nil || r.MaxLen != nil {
+		if !field.Desc.IsList() && !field.Desc.IsMap() && kind != protoreflect.StringKind && kind != protoreflect.BytesKind {
+			return errors.New("min_len and max_len apply only to string, bytes, repeated, and map fields")
+		}
+		if r.MinLen != nil && r.MaxLen != nil && r.GetMinLen() > r.GetMaxLen() {
+			return fmt.Errorf("min_len %v is greater than max_len %v", r.GetMinLen(), r.GetMaxLen())
+		}
+	}
+	return nil
+}
+
+// checkValidateBound reports whether b can be compared against values
+// of the given kind as an untyped constant.
+func checkValidateBound(kind protoreflect.Kind, b float64) error {
+	var lo, hi float64 // the range of integers, with hi exclusive
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		lo, hi = math.MinInt32, math.MaxInt32+1
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		lo, hi = 0, math.MaxUint32+1
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		lo, hi = math.MinInt64, -math.MinInt64
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		lo, hi = 0, 2*-math.MinInt64
+	case protoreflect.FloatKind:
+		if math.IsNaN(b) || math.Abs(b) > math.MaxFloat32 {
+			return fmt.Errorf("bound %v is not representable by a %v field", b, kind)
+		}
+		return nil
+	default:
+		if math.IsNaN(b) || math.IsInf(b, 0) {
+			return fmt.Errorf("bound %v is not finite", b)
+		}
+		return nil
+	}
+	if b != math.Trunc(b) || b < lo || b >= hi {
+		return fmt.Errorf("bound %v is not representable by a %v field", b, kind)
+	}
+	return nil
+}
+
+func isNumericKind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.BoolKind, protoreflect.EnumKind, protoreflect.StringKind, protoreflect.BytesKind,
+		protoreflect.MessageKind, protoreflect.GroupKind:
+		return false
+	}
+	return true
+}
+
+// hasValueRules reports whether the values of the field need to be checked,
+// either against the rules or by validating a message recursively.
+func hasValueRules(field *protogen.Field, r *govalidatepb.FieldRules) bool {
+	if field.Message != nil {
+		return true
+	}
+	if r.Min != nil || r.Max != nil || r.Pattern != nil {
+		return true
+	}
+	return !field.Desc.IsList() && !field.Desc.IsMap() && (r.MinLen != nil || r.MaxLen != nil)
+}
+
+// zeroValueCond returns a condition that reports whether the singular field
+// accessed by the expression v is unpopulated.
+func zeroValueCond(field *protogen.Field, pointer bool, v string) string {
+	switch kind := field.Desc.Kind(); {
+	case pointer || field.Message != nil:
+		return v + " == nil"
+	case kind == protoreflect.BytesKind:
+		if field.Desc.HasPresence() {
+			return v + " == nil"
+		}
+		return "len(" + v + ") == 0"
+	case kind == protoreflect.StringKind:
+		return v + ` == ""`
+	case kind == protoreflect.BoolKind:
+		return "!" + v
+	default:
+		return v + " == 0"
+	}
+}
+
+// genLenValidation generates checks of the min_len and max_len rules
+// against the length n of a value described by the label.
+func genLenValidation(g *protogen.GeneratedFile, r *govalidatepb.FieldRules, n, label, unit string, args ...string) {
+	if r.GetRequired() && r.GetMinLen() == 0 {
+		g.P("if ", n, " == 0 {")
+		genValidateError(g, label+": value is required", args...)
+		g.P("}")
+	}
+	if r.MinLen != nil {
+		g.P("if ", n, " < ", r.GetMinLen(), " {")
+		genValidateError(g, fmt.Sprintf("%s: value must have at least %d %s", label, r.GetMinLen(), unit), args...)
+		g.P("}")
+	}
+	if r.MaxLen != nil {
+		g.P("if ", n, " > ", r.GetMaxLen(), " {")
+		genValidateError(g, fmt.Sprintf("%s: value must have at most %d %s", label, r.GetMaxLen(), unit), args...)
+		g.P("}")
+	}
+}
+
+// genValueValidation generates checks of a singular value v of the field,
+// which is described by the label formatted with args.
+func genValueValidation(g *protogen.GeneratedFile, field *protogen.Field, r *govalidatepb.FieldRules, pattern, v, label string, args ...string) {
+	if field.Message != nil {
+		g.P("if v, ok := interface{}(", v, ").(interface{ Validate() error }); ok {")
+		g.P("if err := v.Validate(); err != nil {")
+		genValidateError(g, label+": %w", append(args, "err")...)
+		g.P("}")
+		g.P("}")
+		return
+	}
+	switch kind := field.Desc.Kind(); kind {
+	case protoreflect.StringKind:
+		if r.MinLen != nil || r.MaxLen != nil {
+			genLenValidation(g, &govalidatepb.FieldRules{MinLen: r.MinLen, MaxLen: r.MaxLen},
+				g.QualifiedGoIdent(utf8Package.Ident("RuneCountInString"))+"("+v+")", label, "characters", args...)
+		}
+		if pattern != "" {
+			g.P("if !", pattern, ".MatchString(", v, ") {")
+			genValidateError(g, label+": value must match the pattern "+escapeFormat(strconv.Quote(r.GetPattern())), args...)
+			g.P("}")
+		}
+	case protoreflect.BytesKind:
+		unit := "bytes"
+		if deltaGoType(field) != "" {
+			unit = "elements"
+		}
+		if r.MinLen != nil || r.MaxLen != nil {
+			genLenValidation(g, &govalidatepb.FieldRules{MinLen: r.MinLen, MaxLen: r.MaxLen},
+				"len("+v+")", label, unit, args...)
+		}
+		if pattern != "" {
+			g.P("if !", pattern, ".Match(", v, ") {")
+			genValidateError(g, label+": value must match the pattern "+escapeFormat(strconv.Quote(r.GetPattern())), args...)
+			g.P("}")
+		}
+	default:
+		// Floating-point comparisons are negated so that NaN is rejected.
+		isFloat := kind == protoreflect.FloatKind || kind == protoreflect.DoubleKind
+		format := func(b float64) string {
+			if isFloat {
+				return strconv.FormatFloat(b, 'g', -1, 64)
+			}
+			return strconv.FormatFloat(b, 'f', -1, 64)
+		}
+		if r.Min != nil {
+			if isFloat {
+				g.P("if !(", v, " >= ", format(r.GetMin()), ") {")
+			} else {
+				g.P("if ", v, " < ", format(r.GetMin()), " {")
+			}
+			genValidateError(g, label+": value must be at least "+format(r.GetMin()), args...)
+			g.P("}")
+		}
+		if r.Max != nil {
+			if isFloat {
+				g.P("if !(", v, " <= ", format(r.GetMax()), ") {")
+			} else {
+				g.P("if ", v, " > ", format(r.GetMax()), " {")
+			}
+			genValidateError(g, label+": value must be at most "+format(r.GetMax()), args...)
+			g.P("}")
+		}
+	}
+}
+
+// genValidateError generates a statement returning an error
+// formatted with the args.
+func genValidateError(g *protogen.GeneratedFile, format string, args ...string) {
+	g.P("return ", fmtPackage.Ident("Errorf"), "(", strings.Join(append([]string{strconv.Quote(format)}, args...), ", "), ")")
+}
+
+func escapeFormat(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
@@ -36,6 +36,12 @@ func genPackageKnownComment(f *fileInfo) protogen.Comments {
 	}
 	... // make use of any
 
+ The type URL of the Any message is the full name of the message type
+ prefixed with "type.googleapis.com/". A different prefix, such as that of
+ a private schema registry, can be used by constructing it with NewWithPrefix:
+
+	any, err := anypb.NewWithPrefix(m, "schemas.example.com/")
+
 
  Unmarshaling an Any
 
@@ -83,6 +89,10 @@ func genPackageKnownComment(f *fileInfo) protogen.Comments {
  listed in the case clauses are linked into the Go binary and therefore also
  registered in the global registry.
 
+ The message type is resolved from the full name at the end of the type URL,
+ regardless of its prefix. The TypeURLPrefix method reports the prefix,
+ for example to check that the Any message refers to a trusted registry.
+
 
  Type checking an Any
 
@@ -134,6 +144,10 @@ func genPackageKnownComment(f *fileInfo) protogen.Comments {
 		... // handle error
 	}
 
+ The AsTimeWithPolicy method and the NewWithPolicy function perform
+ the conversion with an explicit policy for invalid timestamps, such as
+ clamping times beyond the year 9999 to the latest valid timestamp.
+
 
  Conversion from a Go Time
 
@@ -178,6 +192,11 @@ func genPackageKnownComment(f *fileInfo) protogen.Comments {
 		... // handle error
 	}
 
+ The AsDurationExact method performs the conversion reporting an error
+ for invalid durations instead of silently saturating the result, and
+ the ParseWithPolicy function parses durations with an explicit policy
+ for out-of-range values.
+
  Note that the documented limitations in duration.proto does not protect a
  Duration from overflowing the representable range of a time.Duration in Go.
  The AsDuration method uses saturation arithmetic such that an overflow clamps
@@ -345,6 +364,18 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P("}")
 		g.P()
 
+		g.P("// NewWithPrefix marshals src into a new Any instance, whose type URL")
+		g.P("// is the full name of the message type prefixed with prefix.")
+		g.P("// A \"/\" is appended to prefix if it does not end with one.")
+		g.P("func NewWithPrefix(src ", protoPackage.Ident("Message"), ", prefix string) (*Any, error) {")
+		g.P("	dst := new(Any)")
+		g.P("	if err := MarshalFromWithPrefix(dst, src, prefix, ", protoPackage.Ident("MarshalOptions"), "{}); err != nil {")
+		g.P("		return nil, err")
+		g.P("	}")
+		g.P("	return dst, nil")
+		g.P("}")
+		g.P()
+
 		g.P("// MarshalFrom marshals src into dst as the underlying message")
 		g.P("// using the provided marshal options.")
 		g.P("//")
@@ -364,6 +395,29 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P("}")
 		g.P()
 
+		g.P("// MarshalFromWithPrefix marshals src into dst as the underlying message")
+		g.P("// using the provided marshal options, with a type URL prefixed with prefix")
+		g.P("// as described by NewWithPrefix.")
+		g.P("func MarshalFromWithPrefix(dst *Any, src ", protoPackage.Ident("Message"), ", prefix string, opts ", protoPackage.Ident("MarshalOptions"), ") error {")
+		g.P("	if prefix == \"\" {")
+		g.P("		return ", protoimplPackage.Ident("X"), ".NewError(\"invalid empty type URL prefix\")")
+		g.P("	}")
+		g.P("	if !", stringsPackage.Ident("HasSuffix"), "(prefix, \"/\") {")
+		g.P("		prefix += \"/\"")
+		g.P("	}")
+		g.P("	if src == nil {")
+		g.P("		return ", protoimplPackage.Ident("X"), ".NewError(\"invalid nil source message\")")
+		g.P("	}")
+		g.P("	b, err := opts.Marshal(src)")
+		g.P("	if err != nil {")
+		g.P("		return err")
+		g.P("	}")
+		g.P("	dst.TypeUrl = prefix + string(src.ProtoReflect().Descriptor().FullName())")
+		g.P("	dst.Value = b")
+		g.P("	return nil")
+		g.P("}")
+		g.P()
+
 		g.P("// UnmarshalTo unmarshals the underlying message from src into dst")
 		g.P("// using the provided unmarshal options.")
 		g.P("// It reports an error if dst is not of the right message type.")
@@ -441,6 +495,15 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P("}")
 		g.P()
 
+		g.P("// TypeURLPrefix reports the prefix of the type URL of x, which precedes")
+		g.P("// the full name of the underlying message and includes the trailing \"/\".")
+		g.P("// It returns an empty string if the type URL has no prefix.")
+		g.P("func (x *Any) TypeURLPrefix() string {")
+		g.P("	url := x.GetTypeUrl()")
+		g.P("	return url[:", stringsPackage.Ident("LastIndexByte"), "(url, '/')+1]")
+		g.P("}")
+		g.P()
+
 		g.P("// MarshalFrom marshals m into x as the underlying message.")
 		g.P("func (x *Any) MarshalFrom(m ", protoPackage.Ident("Message"), ") error {")
 		g.P("	return MarshalFrom(x, m, ", protoPackage.Ident("MarshalOptions"), "{})")
@@ -518,9 +581,18 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P(")")
 		g.P()
 
+		g.P("const (")
+		g.P("	minTimestamp = -62135596800  // Seconds between 1970-01-01T00:00:00Z and 0001-01-01T00:00:00Z, inclusive")
+		g.P("	maxTimestamp = +253402300799 // Seconds between 1970-01-01T00:00:00Z and 9999-12-31T23:59:59Z, inclusive")
+		g.P()
+
+		g.P("	// maxTimeSeconds is the largest number of seconds since the Unix epoch")
+		g.P("	// that a time.Time can represent.")
+		g.P("	maxTimeSeconds = ", mathPackage.Ident("MaxInt64"), " - 62135596800")
+		g.P(")")
+		g.P()
+
 		g.P("func (x *Timestamp) check() uint {")
-		g.P("	const minTimestamp = -62135596800  // Seconds between 1970-01-01T00:00:00Z and 0001-01-01T00:00:00Z, inclusive")
-		g.P("	const maxTimestamp = +253402300799 // Seconds between 1970-01-01T00:00:00Z and 9999-12-31T23:59:59Z, inclusive")
 		g.P("	secs := x.GetSeconds()")
 		g.P("	nanos := x.GetNanos()")
 		g.P("	switch {")
@@ -538,6 +610,156 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P("}")
 		g.P()
 
+		g.P("// RangePolicy specifies how a conversion handles timestamps that are out of")
+		g.P("// the range of 0001-01-01T00:00:00Z to 9999-12-31T23:59:59.999999999Z.")
+		g.P("type RangePolicy int")
+		g.P()
+
+		g.P("const (")
+		g.P("	// RangeError reports an error for out-of-range and otherwise invalid")
+		g.P("	// timestamps, including a nil Timestamp.")
+		g.P("	RangeError RangePolicy = iota")
+		g.P("	// RangeClamp replaces out-of-range timestamps with the nearest valid")
+		g.P("	// timestamp, such as 9999-12-31T23:59:59.999999999Z for the year 10000.")
+		g.P("	RangeClamp")
+		g.P("	// RangeSaturate keeps out-of-range timestamps unless a time.Time cannot")
+		g.P("	// represent them, in which case they are replaced with the nearest")
+		g.P("	// time that it can represent.")
+		g.P("	RangeSaturate")
+		g.P(")")
+		g.P()
+
+		g.P("// NewWithPolicy constructs a new Timestamp from the provided time.Time,")
+		g.P("// handling times out of the range of valid timestamps according to the policy.")
+		g.P("// Like a Timestamp, a time.Time does not represent leap seconds,")
+		g.P("// so no adjustment is made for them.")
+		g.P("func NewWithPolicy(t ", timePackage.Ident("Time"), ", policy RangePolicy) (*Timestamp, error) {")
+		g.P("	return New(t).withPolicy(policy)")
+		g.P("}")
+		g.P()
+
+		g.P("// AsTimeWithPolicy converts x to a time.Time, handling timestamps out of")
+		g.P("// the range of valid timestamps according to the policy. With RangeClamp and")
+		g.P("// RangeSaturate, a nil Timestamp converts to the Unix epoch and out-of-range")
+		g.P("// nanos are normalized, as with AsTime.")
+		g.P("func (x *Timestamp) AsTimeWithPolicy(policy RangePolicy) (", timePackage.Ident("Time"), ", error) {")
+		g.P("	y, err := x.withPolicy(policy)")
+		g.P("	if err != nil {")
+		g.P("		return ", timePackage.Ident("Time"), "{}, err")
+		g.P("	}")
+		g.P("	return y.AsTime(), nil")
+		g.P("}")
+		g.P()
+
+		g.P("// withPolicy returns x, or the timestamp that replaces x according to the policy.")
+		g.P("func (x *Timestamp) withPolicy(policy RangePolicy) (*Timestamp, error) {")
+		g.P("	var lo, hi int64")
+		g.P("	switch policy {")
+		g.P("	case RangeClamp:")
+		g.P("		lo, hi = minTimestamp, maxTimestamp")
+		g.P("	case RangeSaturate:")
+		g.P("		lo, hi = ", mathPackage.Ident("MinInt64"), ", maxTimeSeconds")
+		g.P("	default:")
+		g.P("		if err := x.CheckValid(); err != nil {")
+		g.P("			return nil, err")
+		g.P("		}")
+		g.P("		return x, nil")
+		g.P("	}")
+		g.P("	t := normalizeTimestamp(x.GetSeconds(), int64(x.GetNanos()))")
+		g.P("	switch {")
+		g.P("	case t.Seconds < lo:")
+		g.P("		return &Timestamp{Seconds: lo}, nil")
+		g.P("	case t.Seconds > hi:")
+		g.P("		return &Timestamp{Seconds: hi, Nanos: 999999999}, nil")
+		g.P("	}")
+		g.P("	return t, nil")
+		g.P("}")
+		g.P()
+
+		g.P("// Add returns the timestamp x+d.")
+		g.P("// The result may be invalid if it is out of the range of valid timestamps.")
+		g.P("func (x *Timestamp) Add(d *", durationpbPackage.Ident("Duration"), ") *Timestamp {")
+		g.P("	return normalizeTimestamp(x.GetSeconds()+d.GetSeconds(), int64(x.GetNanos())+int64(d.GetNanos()))")
+		g.P("}")
+		g.P()
+
+		g.P("// Sub returns the duration x-y.")
+		g.P("func (x *Timestamp) Sub(y *Timestamp) *", durationpbPackage.Ident("Duration"), " {")
+		g.P("	t := normalizeTimestamp(x.GetSeconds()-y.GetSeconds(), int64(x.GetNanos())-int64(y.GetNanos()))")
+		g.P("	secs, nanos := t.Seconds, t.Nanos")
+		g.P("	if secs < 0 && nanos > 0 {")
+		g.P("		// The seconds and nanos of a duration must have the same sign.")
+		g.P("		secs++")
+		g.P("		nanos -= 1e9")
+		g.P("	}")
+		g.P("	return &", durationpbPackage.Ident("Duration"), "{Seconds: secs, Nanos: nanos}")
+		g.P("}")
+		g.P()
+
+		g.P("// Compare compares x and y. It returns -1 if x is before y,")
+		g.P("// +1 if x is after y, and 0 if they are the same instant.")
+		g.P("// A nil Timestamp is treated as the Unix epoch.")
+		g.P("func (x *Timestamp) Compare(y *Timestamp) int {")
+		g.P("	a := normalizeTimestamp(x.GetSeconds(), int64(x.GetNanos()))")
+		g.P("	b := normalizeTimestamp(y.GetSeconds(), int64(y.GetNanos()))")
+		g.P("	switch {")
+		g.P("	case a.Seconds < b.Seconds || (a.Seconds == b.Seconds && a.Nanos < b.Nanos):")
+		g.P("		return -1")
+		g.P("	case a.Seconds > b.Seconds || (a.Seconds == b.Seconds && a.Nanos > b.Nanos):")
+		g.P("		return +1")
+		g.P("	default:")
+		g.P("		return 0")
+		g.P("	}")
+		g.P("}")
+		g.P()
+
+		g.P("// Before reports whether x is before y.")
+		g.P("func (x *Timestamp) Before(y *Timestamp) bool {")
+		g.P("	return x.Compare(y) < 0")
+		g.P("}")
+		g.P()
+
+		g.P("// After reports whether x is after y.")
+		g.P("func (x *Timestamp) After(y *Timestamp) bool {")
+		g.P("	return x.Compare(y) > 0")
+		g.P("}")
+		g.P()
+
+		g.P("// Truncate returns the result of rounding x down to a multiple of d")
+		g.P("// since the Unix epoch. If d <= 0, Truncate returns a copy of x.")
+		g.P("// Unlike time.Time.Truncate, the result does not depend on the")
+		g.P("// leap seconds and the proleptic calendar used by the time package.")
+		g.P("func (x *Timestamp) Truncate(d ", timePackage.Ident("Duration"), ") *Timestamp {")
+		g.P("	t := normalizeTimestamp(x.GetSeconds(), int64(x.GetNanos()))")
+		g.P("	if d <= 0 {")
+		g.P("		return t")
+		g.P("	}")
+		g.P("	// Compute the remainder of secs*1e9+nanos divided by d")
+		g.P("	// using 128-bit arithmetic to avoid overflow.")
+		g.P("	n := uint64(d)")
+		g.P("	secs := t.Seconds % int64(n)")
+		g.P("	if secs < 0 {")
+		g.P("		secs += int64(n)")
+		g.P("	}")
+		g.P("	hi, lo := ", bitsPackage.Ident("Mul64"), "(uint64(secs), 1e9)")
+		g.P("	r := (", bitsPackage.Ident("Rem64"), "(hi, lo, n) + uint64(t.Nanos)) % n")
+		g.P("	return normalizeTimestamp(t.Seconds-int64(r/1e9), int64(t.Nanos)-int64(r%1e9))")
+		g.P("}")
+		g.P()
+
+		g.P("// normalizeTimestamp returns the timestamp secs+nanos*1e-9")
+		g.P("// with nanos in the range [0, 1e9).")
+		g.P("func normalizeTimestamp(secs, nanos int64) *Timestamp {")
+		g.P("	secs += nanos / 1e9")
+		g.P("	nanos %= 1e9")
+		g.P("	if nanos < 0 {")
+		g.P("		secs--")
+		g.P("		nanos += 1e9")
+		g.P("	}")
+		g.P("	return &Timestamp{Seconds: secs, Nanos: int32(nanos)}")
+		g.P("}")
+		g.P()
+
 	case genid.Duration_message_fullname:
 		g.P("// New constructs a new Duration from the provided time.Duration.")
 		g.P("func New(d ", timePackage.Ident("Duration"), ") *Duration {")
@@ -609,8 +831,10 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P(")")
 		g.P()
 
+		g.P("const absDuration = 315576000000 // 10000yr * 365.25day/yr * 24hr/day * 60min/hr * 60sec/min")
+		g.P()
+
 		g.P("func (x *Duration) check() uint {")
-		g.P("	const absDuration = 315576000000 // 10000yr * 365.25day/yr * 24hr/day * 60min/hr * 60sec/min")
 		g.P("	secs := x.GetSeconds()")
 		g.P("	nanos := x.GetNanos()")
 		g.P("	switch {")
@@ -630,6 +854,161 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P("}")
 		g.P()
 
+		g.P("// RangePolicy specifies how ParseWithPolicy handles durations that are out")
+		g.P("// of the range of -10000 years to +10000 years or that a Duration cannot")
+		g.P("// represent.")
+		g.P("type RangePolicy int")
+		g.P()
+
+		g.P("const (")
+		g.P("	// RangeError reports an error for such durations.")
+		g.P("	RangeError RangePolicy = iota")
+		g.P("	// RangeClamp replaces such durations with the nearest valid duration.")
+		g.P("	RangeClamp")
+		g.P("	// RangeSaturate keeps such durations unless a Duration cannot represent")
+		g.P("	// them, in which case they are replaced with the nearest one it can.")
+		g.P("	RangeSaturate")
+		g.P(")")
+		g.P()
+
+		g.P("// AsDurationExact converts x to a time.Duration like AsDuration, but reports")
+		g.P("// an error if x is invalid or if a time.Duration cannot represent it.")
+		g.P("//")
+		g.P("// Unlike ParseWithPolicy, it takes no RangePolicy: since the range of")
+		g.P("// a time.Duration (about 292 years) lies within the range of valid durations,")
+		g.P("// both clamping to the range of valid durations and saturating to the range")
+		g.P("// of a time.Duration produce the result of AsDuration.")
+		g.P("func (x *Duration) AsDurationExact() (", timePackage.Ident("Duration"), ", error) {")
+		g.P("	if err := x.CheckValid(); err != nil {")
+		g.P("		return 0, err")
+		g.P("	}")
+		g.P("	d := x.AsDuration()")
+		g.P("	if y := New(d); y.Seconds != x.Seconds || y.Nanos != x.Nanos {")
+		g.P("		return 0, ", protoimplPackage.Ident("X"), ".NewError(\"duration (%v) overflows time.Duration\", x)")
+		g.P("	}")
+		g.P("	return d, nil")
+		g.P("}")
+		g.P()
+
+		g.P("// Parse parses a duration in the format of the JSON mapping of")
+		g.P("// google.protobuf.Duration, which is a decimal number of seconds with an")
+		g.P("// optional sign and up to nine fractional digits, followed by the suffix")
+		g.P("// \"s\", such as \"3.5s\" or \"-0.000001s\". Unlike time.ParseDuration,")
+		g.P("// it supports the full range of valid durations, which exceeds the")
+		g.P("// range of time.Duration.")
+		g.P("// It reports an error if s is malformed or the duration is invalid.")
+		g.P("func Parse(s string) (*Duration, error) {")
+		g.P("	return ParseWithPolicy(s, RangeError, SubnanosError)")
+		g.P("}")
+		g.P()
+
+		g.P("// SubnanosPolicy specifies how ParseWithPolicy handles fractional digits")
+		g.P("// beyond nanosecond precision, such as in \"0.0000000015s\".")
+		g.P("type SubnanosPolicy int")
+		g.P()
+
+		g.P("const (")
+		g.P("	// SubnanosError reports an error for digits beyond nanosecond precision.")
+		g.P("	SubnanosError SubnanosPolicy = iota")
+		g.P("	// SubnanosTruncate discards digits beyond nanosecond precision,")
+		g.P("	// which truncates the duration toward zero.")
+		g.P("	SubnanosTruncate")
+		g.P("	// SubnanosRound rounds the duration to the nearest nanosecond,")
+		g.P("	// rounding halfway cases away from zero.")
+		g.P("	SubnanosRound")
+		g.P(")")
+		g.P()
+
+		g.P("// ParseWithPolicy is like Parse, but handles durations out of the range of")
+		g.P("// valid durations and digits beyond nanosecond precision according to the")
+		g.P("// policies. With RangeClamp, out-of-range durations are replaced with the")
+		g.P("// nearest valid duration. With RangeSaturate, they are kept, and seconds")
+		g.P("// beyond the range of an int64 are replaced with the nearest int64.")
+		g.P("// Parse is equivalent to ParseWithPolicy with RangeError and SubnanosError.")
+		g.P("func ParseWithPolicy(s string, rp RangePolicy, sp SubnanosPolicy) (*Duration, error) {")
+		g.P("	x, ok := parse(s, sp)")
+		g.P("	if !ok {")
+		g.P("		return nil, ", protoimplPackage.Ident("X"), ".NewError(\"invalid duration %q\", s)")
+		g.P("	}")
+		g.P("	switch rp {")
+		g.P("	case RangeClamp:")
+		g.P("		switch {")
+		g.P("		case x.Seconds > +absDuration:")
+		g.P("			x.Seconds, x.Nanos = +absDuration, +999999999")
+		g.P("		case x.Seconds < -absDuration:")
+		g.P("			x.Seconds, x.Nanos = -absDuration, -999999999")
+		g.P("		}")
+		g.P("	case RangeSaturate:")
+		g.P("	default:")
+		g.P("		if err := x.CheckValid(); err != nil {")
+		g.P("			return nil, err")
+		g.P("		}")
+		g.P("	}")
+		g.P("	return x, nil")
+		g.P("}")
+		g.P()
+
+		g.P("func parse(s string, sp SubnanosPolicy) (*Duration, bool) {")
+		g.P("	if len(s) < 2 || s[len(s)-1] != 's' {")
+		g.P("		return nil, false")
+		g.P("	}")
+		g.P("	s = s[:len(s)-1]")
+		g.P("	neg := s[0] == '-'")
+		g.P("	if s[0] == '-' || s[0] == '+' {")
+		g.P("		s = s[1:]")
+		g.P("	}")
+		g.P("	intPart, fracPart, _ := ", stringsPackage.Ident("Cut"), "(s, \".\")")
+		g.P("	switch {")
+		g.P("	case intPart == \"\" && fracPart == \"\",")
+		g.P("		len(intPart) > 1 && intPart[0] == '0',")
+		g.P("		len(fracPart) > 9 && sp != SubnanosTruncate && sp != SubnanosRound,")
+		g.P("		", stringsPackage.Ident("Trim"), "(intPart+fracPart, \"0123456789\") != \"\":")
+		g.P("		return nil, false")
+		g.P("	}")
+		g.P("	var secs, nanos int64")
+		g.P("	if intPart != \"\" {")
+		g.P("		// The digits are valid, so ParseInt only fails if they are out of")
+		g.P("		// range, in which case it returns the nearest int64.")
+		g.P("		secs, _ = ", strconvPackage.Ident("ParseInt"), "(intPart, 10, 64)")
+		g.P("	}")
+		g.P("	for i := 0; i < 9; i++ {")
+		g.P("		nanos *= 10")
+		g.P("		if i < len(fracPart) {")
+		g.P("			nanos += int64(fracPart[i] - '0')")
+		g.P("		}")
+		g.P("	}")
+		g.P("	// Round up by a nanosecond, unless the seconds are already saturated.")
+		g.P("	if len(fracPart) > 9 && sp == SubnanosRound && fracPart[9] >= '5' && secs < ", mathPackage.Ident("MaxInt64"), " {")
+		g.P("		if nanos++; nanos == 1e9 {")
+		g.P("			secs, nanos = secs+1, 0")
+		g.P("		}")
+		g.P("	}")
+		g.P("	if neg {")
+		g.P("		secs, nanos = -secs, -nanos")
+		g.P("	}")
+		g.P("	return &Duration{Seconds: secs, Nanos: int32(nanos)}, true")
+		g.P("}")
+		g.P()
+
+		g.P("// Format returns the duration in the format of the JSON mapping of")
+		g.P("// google.protobuf.Duration, such as \"3.5s\" or \"-0.000001s\",")
+		g.P("// with 0, 3, 6, or 9 fractional digits as required by its precision.")
+		g.P("// The result is unspecified if x is invalid.")
+		g.P("func (x *Duration) Format() string {")
+		g.P("	secs, nanos := x.GetSeconds(), int64(x.GetNanos())")
+		g.P("	var sign string")
+		g.P("	if secs < 0 || nanos < 0 {")
+		g.P("		sign, secs, nanos = \"-\", -secs, -nanos")
+		g.P("	}")
+		g.P("	// The conversion to uint64 handles the negation of math.MinInt64.")
+		g.P("	s := ", fmtPackage.Ident("Sprintf"), "(\"%s%d.%09d\", sign, uint64(secs), nanos)")
+		g.P("	s = ", stringsPackage.Ident("TrimSuffix"), "(s, \"000\")")
+		g.P("	s = ", stringsPackage.Ident("TrimSuffix"), "(s, \"000\")")
+		g.P("	s = ", stringsPackage.Ident("TrimSuffix"), "(s, \".000\")")
+		g.P("	return s + \"s\"")
+		g.P("}")
+		g.P()
+
 	case genid.Struct_message_fullname:
 		g.P("// NewStruct constructs a Struct from a general-purpose Go map.")
 		g.P("// The map keys must be valid UTF-8.")
@@ -662,6 +1041,91 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P("}")
 		g.P()
 
+		g.P("// Merge merges the fields of src into x according to the policy.")
+		g.P("// Values that are structs in both x and src are merged recursively.")
+		g.P("// The values of src are copied, so x does not alias src after the merge.")
+		g.P("func (x *Struct) Merge(src *Struct, policy MergePolicy) {")
+		g.P("	if len(src.GetFields()) == 0 {")
+		g.P("		return")
+		g.P("	}")
+		g.P("	if x.Fields == nil {")
+		g.P("		x.Fields = make(map[string]*Value, len(src.GetFields()))")
+		g.P("	}")
+		g.P("	for k, sv := range src.GetFields() {")
+		g.P("		dv, ok := x.Fields[k]")
+		g.P("		switch {")
+		g.P("		case ok && dv.GetStructValue() != nil && sv.GetStructValue() != nil:")
+		g.P("			dv.GetStructValue().Merge(sv.GetStructValue(), policy)")
+		g.P("		case ok && policy == MergeKeepExisting:")
+		g.P("		case ok && policy == MergeAppendLists && dv.GetListValue() != nil && sv.GetListValue() != nil:")
+		g.P("			dl := dv.GetListValue()")
+		g.P("			for _, v := range sv.GetListValue().GetValues() {")
+		g.P("				dl.Values = append(dl.Values, ", protoPackage.Ident("Clone"), "(v).(*Value))")
+		g.P("			}")
+		g.P("		default:")
+		g.P("			x.Fields[k] = ", protoPackage.Ident("Clone"), "(sv).(*Value)")
+		g.P("		}")
+		g.P("	}")
+		g.P("}")
+		g.P()
+		g.P("// GetPath returns the value at the path within x,")
+		g.P("// or nil if the path is malformed or there is no such value.")
+		g.P("//")
+		g.P("// A path is a sequence of keys separated by periods, each of which may be")
+		g.P("// followed by the indexes of list elements in brackets, such as \"a.b[0].c\".")
+		g.P("// Keys that contain periods or brackets may be given as quoted strings")
+		g.P("// in brackets, such as `a[\"b.c\"]`.")
+		g.P("func (x *Struct) GetPath(path string) *Value {")
+		g.P("	elems, ok := parsePath(path)")
+		g.P("	if !ok {")
+		g.P("		return nil")
+		g.P("	}")
+		g.P("	v := &Value{Kind: &Value_StructValue{StructValue: x}}")
+		g.P("	for _, e := range elems {")
+		g.P("		if v = e.get(v); v == nil {")
+		g.P("			return nil")
+		g.P("		}")
+		g.P("	}")
+		g.P("	return v")
+		g.P("}")
+		g.P()
+		g.P("// SetPath sets the value at the path within x to v.")
+		g.P("// The syntax of the path is described by GetPath.")
+		g.P("// Structs and lists are created for elements of the path")
+		g.P("// that are not present, and an index equal to the length of a list")
+		g.P("// appends to the list. It reports an error if the path is malformed")
+		g.P("// or does not match the kinds of the values in x.")
+		g.P("func (x *Struct) SetPath(path string, v *Value) error {")
+		g.P("	elems, ok := parsePath(path)")
+		g.P("	if !ok {")
+		g.P("		return ", protoimplPackage.Ident("X"), ".NewError(\"invalid path %q\", path)")
+		g.P("	}")
+		g.P("	cur := &Value{Kind: &Value_StructValue{StructValue: x}}")
+		g.P("	for i, e := range elems {")
+		g.P("		next := v")
+		g.P("		if i < len(elems)-1 {")
+		g.P("			if next = e.get(cur); next != nil {")
+		g.P("				cur = next")
+		g.P("				continue")
+		g.P("			}")
+		g.P("			next = &Value{Kind: &Value_StructValue{StructValue: &Struct{}}}")
+		g.P("			if elems[i+1].index >= 0 {")
+		g.P("				next = &Value{Kind: &Value_ListValue{ListValue: &ListValue{}}}")
+		g.P("			}")
+		g.P("		}")
+		g.P("		if !e.set(cur, next) {")
+		g.P("			var start int")
+		g.P("			if i > 0 {")
+		g.P("				start = elems[i-1].end")
+		g.P("			}")
+		g.P("			return ", protoimplPackage.Ident("X"), ".NewError(\"invalid path %q: no struct or list for %q at %q\", path, path[start:e.end], path[:start])")
+		g.P("		}")
+		g.P("		cur = next")
+		g.P("	}")
+		g.P("	return nil")
+		g.P("}")
+		g.P()
+
 		g.P("func (x *Struct) MarshalJSON() ([]byte, error) {")
 		g.P("	return ", protojsonPackage.Ident("Marshal"), "(x)")
 		g.P("}")
@@ -672,6 +1136,113 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P("}")
 		g.P()
 
+		g.P("// MergePolicy specifies how Struct.Merge combines values")
+		g.P("// that are present in both structs.")
+		g.P("type MergePolicy int")
+		g.P()
+		g.P("const (")
+		g.P("	// MergeReplace replaces existing values with the values being merged.")
+		g.P("	MergeReplace MergePolicy = iota")
+		g.P("	// MergeAppendLists replaces existing values with the values being merged,")
+		g.P("	// except that lists are appended to existing lists.")
+		g.P("	MergeAppendLists")
+		g.P("	// MergeKeepExisting keeps existing values, adding only the values")
+		g.P("	// for keys that are not present.")
+		g.P("	MergeKeepExisting")
+		g.P(")")
+		g.P()
+		g.P("// pathElem is an element of a path accepted by Struct.GetPath.")
+		g.P("type pathElem struct {")
+		g.P("	key   string")
+		g.P("	index int // index of a list element, or -1 for a key")
+		g.P("	end   int // offset of the end of the element in the path")
+		g.P("}")
+		g.P()
+		g.P("// parsePath parses a path of the syntax described by Struct.GetPath.")
+		g.P("func parsePath(path string) ([]pathElem, bool) {")
+		g.P("	var elems []pathElem")
+		g.P("	for i := 0; i < len(path); {")
+		g.P("		e := pathElem{index: -1}")
+		g.P("		if path[i] == '[' {")
+		g.P("			rest := path[i+1:]")
+		g.P("			if q, err := ", strconvPackage.Ident("QuotedPrefix"), "(rest); err == nil {")
+		g.P("				e.key, _ = ", strconvPackage.Ident("Unquote"), "(q)")
+		g.P("				rest = rest[len(q):]")
+		g.P("			} else {")
+		g.P("				n := 0")
+		g.P("				for n < len(rest) && '0' <= rest[n] && rest[n] <= '9' {")
+		g.P("					n++")
+		g.P("				}")
+		g.P("				if e.index, err = ", strconvPackage.Ident("Atoi"), "(rest[:n]); err != nil {")
+		g.P("					return nil, false")
+		g.P("				}")
+		g.P("				rest = rest[n:]")
+		g.P("			}")
+		g.P("			if !", stringsPackage.Ident("HasPrefix"), "(rest, \"]\") {")
+		g.P("				return nil, false")
+		g.P("			}")
+		g.P("			i = len(path) - len(rest) + 1")
+		g.P("		} else {")
+		g.P("			if i > 0 {")
+		g.P("				if path[i] != '.' {")
+		g.P("					return nil, false")
+		g.P("				}")
+		g.P("				i++")
+		g.P("			}")
+		g.P("			n := ", stringsPackage.Ident("IndexAny"), "(path[i:], \".[\")")
+		g.P("			if n < 0 {")
+		g.P("				n = len(path) - i")
+		g.P("			}")
+		g.P("			if n == 0 {")
+		g.P("				return nil, false")
+		g.P("			}")
+		g.P("			e.key = path[i : i+n]")
+		g.P("			i += n")
+		g.P("		}")
+		g.P("		e.end = i")
+		g.P("		elems = append(elems, e)")
+		g.P("	}")
+		g.P("	return elems, len(elems) > 0")
+		g.P("}")
+		g.P()
+		g.P("// get returns the element of v, or nil if there is none.")
+		g.P("func (e pathElem) get(v *Value) *Value {")
+		g.P("	if e.index < 0 {")
+		g.P("		return v.GetStructValue().GetFields()[e.key]")
+		g.P("	}")
+		g.P("	if vs := v.GetListValue().GetValues(); e.index < len(vs) {")
+		g.P("		return vs[e.index]")
+		g.P("	}")
+		g.P("	return nil")
+		g.P("}")
+		g.P()
+		g.P("// set sets the element of v to w. It reports false if v is not a struct")
+		g.P("// for a key, or a list with at least as many elements as the index.")
+		g.P("func (e pathElem) set(v, w *Value) bool {")
+		g.P("	if e.index < 0 {")
+		g.P("		s := v.GetStructValue()")
+		g.P("		if s == nil {")
+		g.P("			return false")
+		g.P("		}")
+		g.P("		if s.Fields == nil {")
+		g.P("			s.Fields = make(map[string]*Value)")
+		g.P("		}")
+		g.P("		s.Fields[e.key] = w")
+		g.P("		return true")
+		g.P("	}")
+		g.P("	l := v.GetListValue()")
+		g.P("	switch {")
+		g.P("	case l == nil || e.index > len(l.Values):")
+		g.P("		return false")
+		g.P("	case e.index == len(l.Values):")
+		g.P("		l.Values = append(l.Values, w)")
+		g.P("	default:")
+		g.P("		l.Values[e.index] = w")
+		g.P("	}")
+		g.P("	return true")
+		g.P("}")
+		g.P()
+
 	case genid.ListValue_message_fullname:
 		g.P("// NewList constructs a ListValue from a general-purpose Go slice.")
 		g.P("// The slice elements are converted using NewValue.")
@@ -890,6 +1461,19 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P("}")
 		g.P()
 
+		g.P("// Diff constructs a field mask of the fields that differ between")
+		g.P("// the old and new messages, as reported by ", protoPackage.Ident("Diff"), ".")
+		g.P("// Applying the fields in the mask from the new message to the old message")
+		g.P("// makes them equal, except for any extension and unknown fields.")
+		g.P("func Diff(old, new ", protoPackage.Ident("Message"), ") *FieldMask {")
+		g.P("	x := &FieldMask{}")
+		g.P("	for _, c := range ", protoPackage.Ident("Diff"), "(old, new) {")
+		g.P("		x.Paths = append(x.Paths, c.Path)")
+		g.P("	}")
+		g.P("	return x")
+		g.P("}")
+		g.P()
+
 		g.P("// Union returns the union of all the paths in the input field masks.")
 		g.P("func Union(mx *FieldMask, my *FieldMask, ms ...*FieldMask) *FieldMask {")
 		g.P("	var out []string")
@@ -960,6 +1544,42 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P("}")
 		g.P()
 
+		g.P("// NewFromJSON constructs a field mask from a list of paths in the JSON format,")
+		g.P("// where fields are named by their lowerCamelCase JSON names (e.g., \"fooBar.baz\"),")
+		g.P("// and verifies that each one is valid according to the specified message type.")
+		g.P("// Fields may also be named by their proto names, such that paths in either")
+		g.P("// format are converted to the canonical format used by the FieldMask message.")
+		g.P("func NewFromJSON(m ", protoPackage.Ident("Message"), ", paths ...string) (*FieldMask, error) {")
+		g.P("	x := new(FieldMask)")
+		g.P("	md := m.ProtoReflect().Descriptor()")
+		g.P("	for _, path := range paths {")
+		g.P("		p, ok := convertPath(md, path, false)")
+		g.P("		if !ok {")
+		g.P("			return x, ", protoimplPackage.Ident("X"), ".NewError(\"invalid path %q for message %q\", path, md.FullName())")
+		g.P("		}")
+		g.P("		x.Paths = append(x.Paths, p)")
+		g.P("	}")
+		g.P("	return x, nil")
+		g.P("}")
+		g.P()
+
+		g.P("// JSONPaths returns the paths in the JSON format, where fields are named by")
+		g.P("// their lowerCamelCase JSON names according to the specified message type.")
+		g.P("// It reports an error if any path is not valid for the message type.")
+		g.P("func (x *FieldMask) JSONPaths(m ", protoPackage.Ident("Message"), ") ([]string, error) {")
+		g.P("	md := m.ProtoReflect().Descriptor()")
+		g.P("	var out []string")
+		g.P("	for _, path := range x.GetPaths() {")
+		g.P("		p, ok := convertPath(md, path, true)")
+		g.P("		if !ok {")
+		g.P("			return nil, ", protoimplPackage.Ident("X"), ".NewError(\"invalid path %q for message %q\", path, md.FullName())")
+		g.P("		}")
+		g.P("		out = append(out, p)")
+		g.P("	}")
+		g.P("	return out, nil")
+		g.P("}")
+		g.P()
+
 		g.P("func numValidPaths(m ", protoPackage.Ident("Message"), ", paths []string) int {")
 		g.P("	md0 := m.ProtoReflect().Descriptor()")
 		g.P("	for i, path := range paths {")
@@ -969,16 +1589,7 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P("			if md == nil {")
 		g.P("				return false // not within a message")
 		g.P("			}")
-		g.P("			fd := md.Fields().ByName(", protoreflectPackage.Ident("Name"), "(field))")
-		g.P("			// The real field name of a group is the message name.")
-		g.P("			if fd == nil {")
-		g.P("				gd := md.Fields().ByName(", protoreflectPackage.Ident("Name"), "(", stringsPackage.Ident("ToLower"), "(field)))")
-		g.P("				if gd != nil && gd.Kind() == ", protoreflectPackage.Ident("GroupKind"), " && string(gd.Message().Name()) == field {")
-		g.P("					fd = gd")
-		g.P("				}")
-		g.P("			} else if fd.Kind() == ", protoreflectPackage.Ident("GroupKind"), " && string(fd.Message().Name()) != field {")
-		g.P("				fd = nil")
-		g.P("			}")
+		g.P("			fd := fieldByName(md, field)")
 		g.P("			if fd == nil {")
 		g.P("				return false // message has does not have this field")
 		g.P("			}")
@@ -1000,6 +1611,57 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P("}")
 		g.P()
 
+		g.P("// fieldByName returns the field in the message with the specified name.")
+		g.P("func fieldByName(md ", protoreflectPackage.Ident("MessageDescriptor"), ", field string) ", protoreflectPackage.Ident("FieldDescriptor"), " {")
+		g.P("	fd := md.Fields().ByName(", protoreflectPackage.Ident("Name"), "(field))")
+		g.P("	// The real field name of a group is the message name.")
+		g.P("	if fd == nil {")
+		g.P("		gd := md.Fields().ByName(", protoreflectPackage.Ident("Name"), "(", stringsPackage.Ident("ToLower"), "(field)))")
+		g.P("		if gd != nil && gd.Kind() == ", protoreflectPackage.Ident("GroupKind"), " && string(gd.Message().Name()) == field {")
+		g.P("			fd = gd")
+		g.P("		}")
+		g.P("	} else if fd.Kind() == ", protoreflectPackage.Ident("GroupKind"), " && string(fd.Message().Name()) != field {")
+		g.P("		fd = nil")
+		g.P("	}")
+		g.P("	return fd")
+		g.P("}")
+		g.P()
+
+		g.P("// convertPath converts a path between the proto names and the JSON names")
+		g.P("// of fields in the specified message type.")
+		g.P("// It reports false if the path is not valid for the message type.")
+		g.P("func convertPath(md ", protoreflectPackage.Ident("MessageDescriptor"), ", path string, toJSON bool) (string, bool) {")
+		g.P("	var b []byte")
+		g.P("	ok := rangeFields(path, func(field string) bool {")
+		g.P("		if md == nil {")
+		g.P("			return false // not within a message")
+		g.P("		}")
+		g.P("		fd := fieldByName(md, field)")
+		g.P("		if fd == nil && !toJSON {")
+		g.P("			fd = md.Fields().ByJSONName(field)")
+		g.P("		}")
+		g.P("		if fd == nil {")
+		g.P("			return false // message does not have this field")
+		g.P("		}")
+		g.P("		if len(b) > 0 {")
+		g.P("			b = append(b, '.')")
+		g.P("		}")
+		g.P("		if toJSON {")
+		g.P("			b = append(b, fd.JSONName()...)")
+		g.P("		} else {")
+		g.P("			b = append(b, fd.TextName()...)")
+		g.P("		}")
+		g.P()
+		g.P("		// Repeated fields are only allowed at the last position.")
+		g.P("		md = fd.Message() // may be nil")
+		g.P("		if fd.IsList() || fd.IsMap() {")
+		g.P("			md = nil")
+		g.P("		}")
+		g.P("		return true")
+		g.P("	})")
+		g.P("	return string(b), ok")
+		g.P("}")
+		g.P()
 		g.P("// Normalize converts the mask to its canonical form where all paths are sorted")
 		g.P("// and redundant paths are removed.")
 		g.P("func (x *FieldMask) Normalize() {")
@@ -1075,15 +1737,7 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		genid.StringValue_message_fullname,
 		genid.BytesValue_message_fullname:
 		funcName := strings.TrimSuffix(m.GoIdent.GoName, "Value")
-		typeName := strings.ToLower(funcName)
-		switch typeName {
-		case "float":
-			typeName = "float32"
-		case "double":
-			typeName = "float64"
-		case "bytes":
-			typeName = "[]byte"
-		}
+		typeName := wrapperValueType(m)
 
 		g.P("// ", funcName, " stores v in a new ", m.GoIdent, " and returns a pointer to it.")
 		g.P("func ", funcName, "(v ", typeName, ") *", m.GoIdent, " {")
@@ -1092,3 +1746,85 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P()
 	}
 }
+
+// wrapperValueType returns the Go type of the value of a wrapper message.
+func wrapperValueType(m *messageInfo) string {
+	typeName := strings.ToLower(strings.TrimSuffix(m.GoIdent.GoName, "Value"))
+	switch typeName {
+	case "float":
+		typeName = "float32"
+	case "double":
+		typeName = "float64"
+	case "bytes":
+		typeName = "[]byte"
+	}
+	return typeName
+}
+
+// genFileKnownFunctions generates functions that apply to the messages
+// of a well-known types file as a whole.
+func genFileKnownFunctions(g *protogen.GeneratedFile, f *fileInfo) {
+	switch f.Desc.Path() {
+	case genid.File_google_protobuf_wrappers_proto:
+		var msgTypes, valTypes []string
+		for _, m := range f.allMessages {
+			msgTypes = append(msgTypes, "*"+m.GoIdent.GoName)
+			valTypes = append(valTypes, wrapperValueType(m))
+		}
+
+		g.P("// Wrapped is a constraint for the Go types of the values of wrapper messages.")
+		g.P("type Wrapped interface {")
+		g.P("	", strings.Join(valTypes, " | "))
+		g.P("}")
+		g.P()
+
+		g.P("// Message is a constraint for pointers to wrapper messages")
+		g.P("// whose values are of type T.")
+		g.P("type Message[T Wrapped] interface {")
+		g.P("	", strings.Join(msgTypes, " | "))
+		g.P("	GetValue() T")
+		g.P("}")
+		g.P()
+
+		g.P("// New stores v in a new wrapper message of type M and returns a pointer to it.")
+		g.P("// For example, New[*Int32Value](5) is equivalent to Int32(5).")
+		g.P("func New[M Message[T], T Wrapped](v T) M {")
+		g.P("	var m M")
+		g.P("	switch p := any(&m).(type) {")
+		for _, m := range f.allMessages {
+			g.P("	case **", m.GoIdent.GoName, ":")
+			g.P("		*p = &", m.GoIdent.GoName, "{Value: any(v).(", wrapperValueType(m), ")}")
+		}
+		g.P("	}")
+		g.P("	return m")
+		g.P("}")
+		g.P()
+
+		g.P("// Value returns the value of m, or the zero value of T if m is nil.")
+		g.P("func Value[M Message[T], T Wrapped](m M) T {")
+		g.P("	return m.GetValue()")
+		g.P("}")
+		g.P()
+
+		g.P("// FromPtr stores *p in a new wrapper message of type M and returns")
+		g.P("// a pointer to it. It returns nil if p is nil.")
+		g.P("func FromPtr[M Message[T], T Wrapped](p *T) M {")
+		g.P("	if p == nil {")
+		g.P("		return nil")
+		g.P("	}")
+		g.P("	return New[M](*p)")
+		g.P("}")
+		g.P()
+
+		g.P("// ToPtr returns a pointer to a new variable holding the value of m.")
+		g.P("// It returns nil if m is nil.")
+		g.P("func ToPtr[M Message[T], T Wrapped](m M) *T {")
+		g.P("	if m == nil {")
+		g.P("		return nil")
+		g.P("	}")
+		g.P("	v := m.GetValue()")
+		g.P("	return &v")
+		g.P("}")
+		g.P()
+	}
+}
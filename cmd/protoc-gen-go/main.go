@@ -36,11 +36,14 @@ func main() {
 
 	var (
 		flags                                 flag.FlagSet
+		opts                                  gengo.Options
 		plugins                               = flags.String("plugins", "", "deprecated option")
 		experimentalStripNonFunctionalCodegen = flags.Bool("experimental_strip_nonfunctional_codegen", false, "experimental_strip_nonfunctional_codegen true means that the plugin will not emit certain parts of the generated code in order to make it possible to compare a proto2/proto3 file with its equivalent (according to proto spec) editions file. Primarily, this is the encoded descriptor.")
 	)
+	opts.RegisterFlags(&flags)
 	protogen.Options{
 		ParamFunc:                    flags.Set,
+		ImportRewriteFunc:            opts.GenProtoRedirects.Rewrite,
 		InternalStripForEditionsDiff: experimentalStripNonFunctionalCodegen,
 	}.Run(func(gen *protogen.Plugin) error {
 		if *plugins != "" {
@@ -49,7 +52,7 @@ func main() {
 		}
 		for _, f := range gen.Files {
 			if f.Generate {
-				gengo.GenerateFile(gen, f)
+				opts.GenerateFile(gen, f)
 			}
 		}
 		gen.SupportedFeatures = gengo.SupportedFeatures
@@ -0,0 +1,46 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	omitpb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/omitlegacy"
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TestNoCopy tests that generated messages contain a sync.Mutex,
+// which the copylocks check of go vet looks for to report copies by value.
+func TestNoCopy(t *testing.T) {
+	for _, m := range []any{
+		(*omitpb.Message)(nil),
+		(*testpb.TestAllTypes)(nil),
+		(*timestamppb.Timestamp)(nil),
+	} {
+		if typ := reflect.TypeOf(m).Elem(); !containsMutex(typ) {
+			t.Errorf("%v does not contain a sync.Mutex", typ)
+		}
+	}
+}
+
+func containsMutex(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Struct:
+		if typ == reflect.TypeOf(sync.Mutex{}) {
+			return true
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			if containsMutex(typ.Field(i).Type) {
+				return true
+			}
+		}
+	case reflect.Array:
+		return containsMutex(typ.Elem())
+	}
+	return false
+}
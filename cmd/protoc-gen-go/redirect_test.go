@@ -0,0 +1,145 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestImportRedirects(t *testing.T) {
+	var r gengo.ImportRedirects
+	for _, s := range []string{
+		"google.golang.org/genproto/googleapis=example.com/googleapis",
+		"google.golang.org/genproto/googleapis/rpc/status/=example.com/statuspb/",
+	} {
+		if err := r.Set(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, s := range []string{"", "foo", "=foo", "foo="} {
+		if err := new(gengo.ImportRedirects).Set(s); err == nil {
+			t.Errorf("Set(%q) succeeded, want error", s)
+		}
+	}
+
+	for _, tt := range []struct {
+		in, want protogen.GoImportPath
+	}{
+		{"google.golang.org/genproto/googleapis/type/date", "example.com/googleapis/type/date"},
+		{"google.golang.org/genproto/googleapis/rpc/status", "example.com/statuspb"},
+		{"google.golang.org/genproto/googleapis/rpc/status/sub", "example.com/statuspb/sub"},
+		{"google.golang.org/genproto/googleapisx", "google.golang.org/genproto/googleapisx"},
+		{"google.golang.org/genproto/protobuf/field_mask", "google.golang.org/protobuf/types/known/fieldmaskpb"},
+		{"google.golang.org/genproto/protobuf/ptype", "google.golang.org/protobuf/types/known/typepb"},
+		{"example.com/other", "example.com/other"},
+	} {
+		if got := r.Rewrite(tt.in); got != tt.want {
+			t.Errorf("Rewrite(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		in, want protogen.GoImportPath
+		ok       bool
+	}{
+		{"example.com/googleapis/type/date", "google.golang.org/genproto/googleapis/type/date", true},
+		{"example.com/statuspb", "google.golang.org/genproto/googleapis/rpc/status", true},
+		{"example.com/googleapisx", "", false},
+		{"example.com/other", "", false},
+	} {
+		if got, ok := r.GenProtoAlias(tt.in); got != tt.want || ok != tt.ok {
+			t.Errorf("GenProtoAlias(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	// Without any redirects, import paths are left unchanged.
+	const path = "google.golang.org/genproto/protobuf/field_mask"
+	if got := new(gengo.ImportRedirects).Rewrite(path); got != path {
+		t.Errorf("Rewrite(%q) without redirects = %q, want it unchanged", path, got)
+	}
+}
+
+func TestImportRedirectsGenerate(t *testing.T) {
+	dep := new(descriptorpb.FileDescriptorProto)
+	if err := prototext.Unmarshal([]byte(`
+		name: "google/rpc/status.proto"
+		package: "google.rpc"
+		syntax: "proto3"
+		options: {go_package: "google.golang.org/genproto/googleapis/rpc/status;status"}
+		message_type: [{name: "Status"}]
+	`), dep); err != nil {
+		t.Fatal(err)
+	}
+	file := new(descriptorpb.FileDescriptorProto)
+	if err := prototext.Unmarshal([]byte(`
+		name: "redirect.proto"
+		package: "goproto.redirect"
+		syntax: "proto3"
+		dependency: ["google/rpc/status.proto", "google/protobuf/field_mask.proto"]
+		options: {go_package: "example.com/redirectpb"}
+		message_type: [{
+			name: "Message"
+			field: [
+				{name: "status" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.rpc.Status" json_name: "status"},
+				{name: "mask" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.FieldMask" json_name: "mask"}
+			]
+		}]
+	`), file); err != nil {
+		t.Fatal(err)
+	}
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"redirect.proto"},
+		Parameter: proto.String(strings.Join([]string{
+			"Mgoogle/protobuf/field_mask.proto=google.golang.org/genproto/protobuf/field_mask;field_mask",
+			"genproto_redirect=google.golang.org/genproto/googleapis=example.com/googleapis",
+		}, ",")),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(fieldmaskpb.File_google_protobuf_field_mask_proto),
+			dep,
+			file,
+		},
+	}
+
+	var r gengo.ImportRedirects
+	gen, err := protogen.Options{
+		ParamFunc: func(name, value string) error {
+			if name != "genproto_redirect" {
+				t.Fatalf("unexpected parameter %q", name)
+			}
+			return r.Set(value)
+		},
+		ImportRewriteFunc: r.Rewrite,
+	}.New(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gengo.Options{}.GenerateFile(gen, gen.FilesByPath["redirect.proto"])
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatal(resp.GetError())
+	}
+	src := resp.File[0].GetContent()
+	for _, want := range []string{
+		`status "example.com/googleapis/rpc/status"`,
+		`field_mask "google.golang.org/protobuf/types/known/fieldmaskpb"`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code does not contain import %s", want)
+		}
+	}
+	if strings.Contains(src, "google.golang.org/genproto") {
+		t.Errorf("generated code imports the genproto module:\n%s", src)
+	}
+}
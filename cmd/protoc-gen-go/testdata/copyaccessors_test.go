@@ -0,0 +1,76 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	copypb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/copyaccessors"
+)
+
+func TestCopyAccessors(t *testing.T) {
+	nested := func(s string) *copypb.Message_Nested {
+		return &copypb.Message_Nested{S: s, Values: []int32{1, 2}}
+	}
+	m := &copypb.Message{
+		Singular: nested("singular"),
+		Repeated: []*copypb.Message_Nested{nested("r0"), nil},
+		Map:      map[string]*copypb.Message_Nested{"k": nested("map")},
+		Union:    &copypb.Message_OneofMessage{OneofMessage: nested("oneof")},
+	}
+
+	singular := m.GetSingularCopy()
+	if !proto.Equal(singular, m.Singular) || singular == m.Singular {
+		t.Errorf("GetSingularCopy() = %v, want a copy of %v", singular, m.Singular)
+	}
+	repeated := m.GetRepeatedCopy()
+	if len(repeated) != 2 || !proto.Equal(repeated[0], m.Repeated[0]) || repeated[0] == m.Repeated[0] || repeated[1] != nil {
+		t.Errorf("GetRepeatedCopy() = %v, want a copy of %v", repeated, m.Repeated)
+	}
+	mp := m.GetMapCopy()
+	if len(mp) != 1 || !proto.Equal(mp["k"], m.Map["k"]) || mp["k"] == m.Map["k"] {
+		t.Errorf("GetMapCopy() = %v, want a copy of %v", mp, m.Map)
+	}
+	oneof := m.GetOneofMessageCopy()
+	if !proto.Equal(oneof, m.GetOneofMessage()) || oneof == m.GetOneofMessage() {
+		t.Errorf("GetOneofMessageCopy() = %v, want a copy of %v", oneof, m.GetOneofMessage())
+	}
+
+	// Modifying a copy does not affect the original.
+	singular.Values[0] = 100
+	if m.Singular.Values[0] != 1 {
+		t.Errorf("modifying the result of GetSingularCopy modified the original")
+	}
+
+	// Unset fields and nil messages result in nil values.
+	var empty *copypb.Message
+	if got := empty.GetSingularCopy(); got != nil {
+		t.Errorf("GetSingularCopy() on nil message = %v, want nil", got)
+	}
+	if got := empty.GetRepeatedCopy(); got != nil {
+		t.Errorf("GetRepeatedCopy() on nil message = %v, want nil", got)
+	}
+	if got := empty.GetMapCopy(); got != nil {
+		t.Errorf("GetMapCopy() on nil message = %v, want nil", got)
+	}
+	if got := empty.GetOneofMessageCopy(); got != nil {
+		t.Errorf("GetOneofMessageCopy() on nil message = %v, want nil", got)
+	}
+
+	// Copy accessors are only generated for message-typed fields
+	// and only if they do not conflict with other methods.
+	typ := reflect.TypeOf(m)
+	for _, name := range []string{"GetNameCopy", "GetScalarMapCopy"} {
+		if _, ok := typ.MethodByName(name); ok {
+			t.Errorf("unexpected method %v", name)
+		}
+	}
+	if got := reflect.TypeOf(m.GetConflictCopy()); got.Kind() != reflect.String {
+		t.Errorf("GetConflictCopy() returns %v, want the getter for conflict_copy", got)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protodelta"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	deltapb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/deltaencoded"
+)
+
+func TestDeltaEncoded(t *testing.T) {
+	want := &deltapb.Series{
+		Timestamps: []int64{1700000000, 1700000001, 1700000003, math.MinInt64, math.MaxInt64},
+		Readings:   []float64{-5, -6.5, math.Inf(1)},
+		Offsets:    []int32{math.MaxInt32, math.MinInt32, 0},
+		Counts:     []uint32{3, 2, math.MaxUint32},
+		Totals:     []uint64{math.MaxUint64, 0},
+		Levels:     []float32{0.5, -0.25},
+		Raw:        []byte{0x80},
+	}
+	b, err := proto.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(deltapb.Series)
+	if err := proto.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(seriesValues(got), seriesValues(want)) {
+		t.Errorf("Unmarshal(Marshal(m)) = %v, want %v", seriesValues(got), seriesValues(want))
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("proto.Equal(Unmarshal(Marshal(m)), m) = false, want true")
+	}
+	if clone := proto.Clone(want).(*deltapb.Series); !reflect.DeepEqual(seriesValues(clone), seriesValues(want)) {
+		t.Errorf("proto.Clone(m) = %v, want %v", seriesValues(clone), seriesValues(want))
+	}
+
+	// The fields are ordinary bytes fields in the wire format.
+	raw := new(deltapb.RawSeries)
+	if err := proto.Unmarshal(b, raw); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := raw.Timestamps, protodelta.Append(nil, want.Timestamps); !bytes.Equal(got, want) {
+		t.Errorf("RawSeries.Timestamps = %x, want %x", got, want)
+	}
+	if got, want := raw.Levels, protodelta.Append(nil, want.Levels); !bytes.Equal(got, want) {
+		t.Errorf("RawSeries.Levels = %x, want %x", got, want)
+	}
+
+	// Protobuf reflection accesses the encoded bytes.
+	m := want.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName("offsets")
+	if got, want := m.Get(fd).Bytes(), protodelta.Append(nil, want.Offsets); !bytes.Equal(got, want) {
+		t.Errorf("Get(offsets) = %x, want %x", got, want)
+	}
+	m.Set(fd, protoreflect.ValueOfBytes(protodelta.Append(nil, []int32{7, -7})))
+	if got, want := want.Offsets, []int32{7, -7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Offsets after Set = %v, want %v", got, want)
+	}
+}
+
+func seriesValues(m *deltapb.Series) []any {
+	return []any{m.Timestamps, m.Readings, m.Offsets, m.Counts, m.Totals, m.Levels, m.Raw}
+}
+
+func TestDeltaEncodedPresence(t *testing.T) {
+	// An empty sequence is encoded for a field with explicit presence,
+	// but not for a field with implicit presence.
+	b, err := proto.Marshal(&deltapb.Series{Timestamps: []int64{}, Readings: []float64{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(deltapb.Series)
+	if err := proto.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Timestamps == nil {
+		t.Errorf("Timestamps = nil, want empty")
+	}
+	if got.Readings != nil {
+		t.Errorf("Readings = %v, want nil", got.Readings)
+	}
+
+	m := new(deltapb.Series).ProtoReflect()
+	fd := m.Descriptor().Fields().ByName("totals")
+	m.Set(fd, protoreflect.ValueOfBytes(nil))
+	if !m.Has(fd) {
+		t.Errorf("Has(totals) = false after Set, want true")
+	}
+}
+
+func TestDeltaEncodedInvalid(t *testing.T) {
+	for _, raw := range []*deltapb.RawSeries{
+		{Timestamps: []byte{0x80}},
+		{Readings: []byte{0x02, 0xff}},
+		{Counts: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+	} {
+		b, err := proto.Marshal(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := proto.Unmarshal(b, new(deltapb.Series)); err == nil {
+			t.Errorf("Unmarshal(%x) succeeded, want error", b)
+		}
+	}
+}
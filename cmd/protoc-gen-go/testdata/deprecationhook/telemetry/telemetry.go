@@ -0,0 +1,31 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package telemetry counts the uses of deprecated fields
+// for the deprecationhook test.
+package telemetry
+
+import "sync"
+
+var (
+	mu     sync.Mutex
+	counts = make(map[string]int)
+)
+
+// Count records a call of the accessor method of the deprecated field.
+func Count(field, accessor string) {
+	mu.Lock()
+	defer mu.Unlock()
+	counts[field+"."+accessor]++
+}
+
+// Counts returns the counts recorded by Count since the last call,
+// keyed by the field name and accessor separated by a dot.
+func Counts() map[string]int {
+	mu.Lock()
+	defer mu.Unlock()
+	c := counts
+	counts = make(map[string]int)
+	return c
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/cmd/protoc-gen-go/testdata/deprecationhook/telemetry"
+
+	hookpb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/deprecationhook"
+)
+
+func TestDeprecationHook(t *testing.T) {
+	telemetry.Counts()
+	m := &hookpb.Message{
+		Name:      "name",
+		OldCount:  1,
+		OldNested: &hookpb.Message_Nested{OldValue: 2},
+		Choice:    &hookpb.Message_OldText{OldText: "text"},
+	}
+	m.GetName()
+	m.GetFlag()
+	m.GetOldCount()
+	m.GetOldCount()
+	m.GetOldNested().GetOldValue()
+	m.GetOldTags()
+	m.GetOldText()
+	// The copy accessor reads the field with GetOldNested.
+	m.GetOldNestedCopy()
+	// The hook is called for nil messages as well.
+	(*hookpb.Message)(nil).GetOldCount()
+
+	want := map[string]int{
+		"goproto.protoc.deprecationhook.Message.old_count.GetOldCount":        3,
+		"goproto.protoc.deprecationhook.Message.old_nested.GetOldNested":      2,
+		"goproto.protoc.deprecationhook.Message.old_nested.GetOldNestedCopy":  1,
+		"goproto.protoc.deprecationhook.Message.Nested.old_value.GetOldValue": 1,
+		"goproto.protoc.deprecationhook.Message.old_tags.GetOldTags":          1,
+		"goproto.protoc.deprecationhook.Message.old_text.GetOldText":          1,
+	}
+	if diff := cmp.Diff(want, telemetry.Counts()); diff != "" {
+		t.Errorf("deprecated field uses mismatch (-want +got):\n%s", diff)
+	}
+}
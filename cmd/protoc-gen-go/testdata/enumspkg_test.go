@@ -0,0 +1,53 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/build"
+	"testing"
+
+	"google.golang.org/protobuf/cmd/protoc-gen-go/testdata/enumspkg/enums"
+
+	enumspb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/enumspkg"
+)
+
+func TestEnumsPackage(t *testing.T) {
+	// The untyped constants can be used as values of the enum types.
+	m := &enumspb.Message{E: enums.Enum_ENUM_TWO, Nested: enums.Message_NESTED_ONE}
+	if m.GetE() != enumspb.Enum_ENUM_TWO || m.GetNested() != enumspb.Message_NESTED_ONE {
+		t.Errorf("got %v, want values of the constants of the enums package", m)
+	}
+
+	for _, tt := range []struct {
+		gotName, wantName   map[int32]string
+		gotValue, wantValue map[string]int32
+	}{
+		{enums.Enum_name, enumspb.Enum_name, enums.Enum_value, enumspb.Enum_value},
+		{enums.Message_Nested_name, enumspb.Message_Nested_name, enums.Message_Nested_value, enumspb.Message_Nested_value},
+	} {
+		if len(tt.gotName) != len(tt.wantName) || len(tt.gotValue) != len(tt.wantValue) {
+			t.Errorf("enum value maps have different lengths: got %v and %v, want %v and %v", tt.gotName, tt.gotValue, tt.wantName, tt.wantValue)
+		}
+		for n, s := range tt.wantName {
+			if tt.gotName[n] != s {
+				t.Errorf("name of %v = %q, want %q", n, tt.gotName[n], s)
+			}
+		}
+		for s, n := range tt.wantValue {
+			if v, ok := tt.gotValue[s]; !ok || v != n {
+				t.Errorf("value of %q = %v, want %v", s, v, n)
+			}
+		}
+	}
+
+	// The enums package does not depend on the protobuf module.
+	pkg, err := build.Import("google.golang.org/protobuf/cmd/protoc-gen-go/testdata/enumspkg/enums", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkg.Imports) > 0 {
+		t.Errorf("enums package imports %v, want no imports", pkg.Imports)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	extpb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/extaccessors"
+)
+
+func TestExtensionAccessors(t *testing.T) {
+	m := &extpb.Base{}
+	if extpb.HasCount(m) || extpb.HasTags(m) || extpb.HasColor(m) {
+		t.Errorf("Has of empty message reports populated extensions")
+	}
+	if got := extpb.GetColor(m); got != extpb.Color_GREEN {
+		t.Errorf("GetColor() = %v, want default %v", got, extpb.Color_GREEN)
+	}
+	if got := extpb.GetBase(m); got != nil {
+		t.Errorf("GetBase() = %v, want nil", got)
+	}
+
+	extpb.SetCount(m, 5)
+	extpb.SetTags(m, []string{"a", "b"})
+	extpb.SetColor(m, extpb.Color_RED)
+	extpb.SetData(m, []byte("data"))
+	extpb.SetScope_Scoped(m, "scoped")
+	nested := &extpb.Base{}
+	extpb.SetCount(nested, 6)
+	extpb.SetBase(m, nested)
+
+	if got := extpb.GetCount(m); got != 5 {
+		t.Errorf("GetCount() = %v, want 5", got)
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, extpb.GetTags(m)); diff != "" {
+		t.Errorf("GetTags() mismatch (-want +got):\n%s", diff)
+	}
+	if got := extpb.GetColor(m); got != extpb.Color_RED {
+		t.Errorf("GetColor() = %v, want %v", got, extpb.Color_RED)
+	}
+	if got := string(extpb.GetData(m)); got != "data" {
+		t.Errorf("GetData() = %q, want %q", got, "data")
+	}
+	if got := extpb.GetScope_Scoped(m); got != "scoped" {
+		t.Errorf("GetScope_Scoped() = %q, want %q", got, "scoped")
+	}
+	if got := extpb.GetBase(m); !proto.Equal(got, nested) {
+		t.Errorf("GetBase() = %v, want %v", got, nested)
+	}
+	if got := proto.GetExtension(m, extpb.E_Count).(int32); got != 5 {
+		t.Errorf("GetExtension(E_Count) = %v, want 5", got)
+	}
+
+	extpb.ClearCount(m)
+	extpb.ClearColor(m)
+	if extpb.HasCount(m) || extpb.HasColor(m) {
+		t.Errorf("Has after Clear reports populated extensions")
+	}
+	if !extpb.HasTags(m) || !extpb.HasScope_Scoped(m) {
+		t.Errorf("Clear of one extension cleared others")
+	}
+
+	opts := &descriptorpb.FieldOptions{}
+	extpb.SetExtaccessorsSensitive(opts, true)
+	if !extpb.HasExtaccessorsSensitive(opts) || !extpb.GetExtaccessorsSensitive(opts) {
+		t.Errorf("GetExtaccessorsSensitive() = false, want true")
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	fielddescspb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/fielddescs"
+)
+
+func TestFieldDescriptors(t *testing.T) {
+	for _, tt := range []struct {
+		md   protoreflect.MessageDescriptor
+		name protoreflect.Name
+		got  protoreflect.FieldDescriptor
+	}{
+		{(&fielddescspb.Message{}).ProtoReflect().Descriptor(), "count", fielddescspb.Message_Fields.Count},
+		{(&fielddescspb.Message{}).ProtoReflect().Descriptor(), "label", fielddescspb.Message_Fields.Label},
+		{(&fielddescspb.Message{}).ProtoReflect().Descriptor(), "nested", fielddescspb.Message_Fields.Nested},
+		{(&fielddescspb.Message{}).ProtoReflect().Descriptor(), "values", fielddescspb.Message_Fields.Values},
+		{(&fielddescspb.Message{}).ProtoReflect().Descriptor(), "flag", fielddescspb.Message_Fields.Flag},
+		{(&fielddescspb.Message{}).ProtoReflect().Descriptor(), "child", fielddescspb.Message_Fields.Child},
+		{(&fielddescspb.Message_Nested{}).ProtoReflect().Descriptor(), "name", fielddescspb.Message_Nested_Fields.Name},
+	} {
+		if want := tt.md.Fields().ByName(tt.name); tt.got != want {
+			t.Errorf("descriptor of field %v = %v, want %v", tt.name, tt.got, want)
+		}
+	}
+
+	// Setting a field through its descriptor affects the generated field.
+	m := &fielddescspb.Message{}
+	m.ProtoReflect().Set(fielddescspb.Message_Fields.Count, protoreflect.ValueOfInt32(5))
+	if m.GetCount() != 5 {
+		t.Errorf("GetCount() = %v, want 5", m.GetCount())
+	}
+}
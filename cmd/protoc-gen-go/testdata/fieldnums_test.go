@@ -0,0 +1,39 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	fieldnumspb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/fieldnums"
+)
+
+func TestFieldNumbers(t *testing.T) {
+	md := (&fieldnumspb.Message{}).ProtoReflect().Descriptor()
+	nestedMD := (&fieldnumspb.Message_Nested{}).ProtoReflect().Descriptor()
+	for _, tt := range []struct {
+		md     protoreflect.MessageDescriptor
+		name   protoreflect.Name
+		number protoreflect.FieldNumber
+	}{
+		{md, fieldnumspb.Message_Count_field_name, fieldnumspb.Message_Count_field_number},
+		{md, fieldnumspb.Message_Nested_field_name, fieldnumspb.Message_Nested_field_number},
+		{md, fieldnumspb.Message_Values_field_name, fieldnumspb.Message_Values_field_number},
+		{md, fieldnumspb.Message_Flag_field_name, fieldnumspb.Message_Flag_field_number},
+		{md, fieldnumspb.Message_Text_field_name, fieldnumspb.Message_Text_field_number},
+		{nestedMD, fieldnumspb.Message_Nested_FirstName_field_name, fieldnumspb.Message_Nested_FirstName_field_number},
+	} {
+		fd := tt.md.Fields().ByNumber(tt.number)
+		if fd == nil {
+			t.Errorf("%v has no field numbered %v", tt.md.FullName(), tt.number)
+			continue
+		}
+		if fd.Name() != tt.name {
+			t.Errorf("field %v of %v is named %v, want %v", tt.number, tt.md.FullName(), fd.Name(), tt.name)
+		}
+	}
+}
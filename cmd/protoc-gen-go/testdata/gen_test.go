@@ -9,11 +9,20 @@ package main
 import (
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/annotations"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/comments"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/copyaccessors"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/deprecationhook"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/enumspkg"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/enumspkg/enums"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/extaccessors"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/extensions/base"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/extensions/ext"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/extensions/extra"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/extensions/proto3"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/fielddescs"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/fieldnames"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/fieldnums"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/genprotoredirect"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/gofix"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/import_public"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/import_public/sub"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/import_public/sub2"
@@ -23,9 +32,14 @@ import (
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/imports/test_a_2"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/imports/test_b_1"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/issue780_oneof_conflict"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/jsontags"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/mapaccessors"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/nopackage"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/omitlegacy"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/presence"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/proto2"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/proto3"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/protoeditions"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/retention"
+	_ "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/validate"
 )
@@ -0,0 +1,45 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/filedesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	redirectpb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/genprotoredirect"
+)
+
+func TestGenProtoRedirect(t *testing.T) {
+	fd := redirectpb.File_cmd_protoc_gen_go_testdata_genprotoredirect_genprotoredirect_proto
+	if got, want := fd.Messages().Get(0).Fields().Get(0).Message().FullName(), "google.protobuf.FieldMask"; string(got) != want {
+		t.Errorf("field type = %v, want %v", got, want)
+	}
+
+	// Registering the file a second time, as the genproto package would,
+	// panics with a message that names the redirected package.
+	b := protowire.AppendTag(nil, 1, protowire.BytesType) // FileDescriptorProto.name
+	b = protowire.AppendString(b, fd.Path())
+	dup := filedesc.Builder{
+		RawDescriptor: b,
+		FileRegistry:  new(protoregistry.Files),
+	}.Build().File
+	defer func() {
+		got := fmt.Sprint(recover())
+		for _, want := range []string{
+			"google.golang.org/genproto/googleapis/example",
+			"google.golang.org/protobuf/cmd/protoc-gen-go/testdata/genprotoredirect",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("RegisterFile() panic = %q, want it to mention %q", got, want)
+			}
+		}
+	}()
+	protoregistry.GlobalFiles.RegisterFile(dup)
+}
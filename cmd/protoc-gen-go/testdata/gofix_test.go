@@ -0,0 +1,69 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	gofixpb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/gofix"
+)
+
+func TestGoFixDirectives(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "gofix/gofix.pb.go", nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// inlined reports whether each declared constant
+	// is preceded by a //go:fix inline directive.
+	inlined := make(map[string]bool)
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			var directive bool
+			if vs.Doc != nil {
+				for _, c := range vs.Doc.List {
+					directive = directive || c.Text == "//go:fix inline"
+				}
+			}
+			for _, name := range vs.Names {
+				inlined[name.Name] = directive
+			}
+		}
+	}
+
+	// Only deprecated aliases of non-deprecated values are inlined.
+	for _, tt := range []struct {
+		name string
+		want bool
+	}{
+		{"Enum_ONE", false},
+		{"Enum_UNO", true},
+		{"Enum_TWO", false},
+		{"Enum_DOS", false},
+	} {
+		got, ok := inlined[tt.name]
+		if !ok {
+			t.Errorf("missing declaration of %v", tt.name)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("declaration of %v preceded by //go:fix directive = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if gofixpb.Enum_UNO != gofixpb.Enum_ONE {
+		t.Errorf("Enum_UNO = %v, want %v", gofixpb.Enum_UNO, gofixpb.Enum_ONE)
+	}
+	if got := gofixpb.Enum_UNO.String(); got != "ONE" {
+		t.Errorf("Enum_UNO.String() = %q, want %q", got, "ONE")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	jsontagspb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/jsontags"
+)
+
+func TestJSONTags(t *testing.T) {
+	m := &jsontagspb.Message{
+		FieldOne:       "one",
+		RepeatedValues: []int32{1, 2},
+		StringMap:      map[string]string{"k": "v"},
+		ChildMessage:   &jsontagspb.Message{FieldOne: "child"},
+		Custom:         "custom",
+	}
+
+	// Without a oneof set, encoding/json and protojson produce the same names.
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	b, err = protojson.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want map[string]interface{}
+	if err := json.Unmarshal(b, &want); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("encoding/json output mismatch with protojson (-want +got):\n%s", diff)
+	}
+
+	// The oneof field is tagged with the name of the oneof
+	// and its members with their JSON names.
+	m = &jsontagspb.Message{Choice: &jsontagspb.Message_OneofInt{OneofInt: 5}}
+	b, err = json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"choice":{"oneofInt":5}}`; got != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	mappb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/mapaccessors"
+)
+
+func TestMapAccessors(t *testing.T) {
+	nested := &mappb.Message_Nested{S: "nested"}
+	m := &mappb.Message{
+		StringToInt32:  map[string]int32{"b": 2, "a": 1, "c": 0},
+		Int64ToString:  map[int64]string{3: "three", -1: "minus one", 0: "zero"},
+		BoolToBytes:    map[bool][]byte{true: []byte("t"), false: []byte("f")},
+		Uint32ToNested: map[uint32]*mappb.Message_Nested{7: nested},
+	}
+
+	if got := m.GetStringToInt32OrDefault("a", 10); got != 1 {
+		t.Errorf("GetStringToInt32OrDefault(a, 10) = %v, want 1", got)
+	}
+	if got := m.GetStringToInt32OrDefault("c", 10); got != 0 {
+		t.Errorf("GetStringToInt32OrDefault(c, 10) = %v, want 0", got)
+	}
+	if got := m.GetStringToInt32OrDefault("z", 10); got != 10 {
+		t.Errorf("GetStringToInt32OrDefault(z, 10) = %v, want 10", got)
+	}
+	if got := m.GetUint32ToNestedOrZero(7); got != nested {
+		t.Errorf("GetUint32ToNestedOrZero(7) = %v, want %v", got, nested)
+	}
+	if got := m.GetUint32ToNestedOrZero(8); got != nil {
+		t.Errorf("GetUint32ToNestedOrZero(8) = %v, want nil", got)
+	}
+
+	if diff := cmp.Diff([]string{"a", "b", "c"}, m.GetStringToInt32Keys()); diff != "" {
+		t.Errorf("GetStringToInt32Keys() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int32{1, 2, 0}, m.GetStringToInt32Values()); diff != "" {
+		t.Errorf("GetStringToInt32Values() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{-1, 0, 3}, m.GetInt64ToStringKeys()); diff != "" {
+		t.Errorf("GetInt64ToStringKeys() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"minus one", "zero", "three"}, m.GetInt64ToStringValues()); diff != "" {
+		t.Errorf("GetInt64ToStringValues() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]bool{false, true}, m.GetBoolToBytesKeys()); diff != "" {
+		t.Errorf("GetBoolToBytesKeys() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Modifying a snapshot does not affect the map.
+	m.GetStringToInt32Keys()[0] = "x"
+	if _, ok := m.StringToInt32["x"]; ok {
+		t.Errorf("modifying the result of GetStringToInt32Keys modified the map")
+	}
+
+	// Empty maps and nil messages result in the default values.
+	var empty *mappb.Message
+	if got := empty.GetStringToInt32OrDefault("a", 10); got != 10 {
+		t.Errorf("GetStringToInt32OrDefault(a, 10) on nil message = %v, want 10", got)
+	}
+	if got := empty.GetStringToEnumOrZero("a"); got != mappb.Message_ZERO {
+		t.Errorf("GetStringToEnumOrZero(a) on nil message = %v, want %v", got, mappb.Message_ZERO)
+	}
+	if got := empty.GetStringToInt32Keys(); got != nil {
+		t.Errorf("GetStringToInt32Keys() on nil message = %v, want nil", got)
+	}
+	if got := empty.GetStringToInt32Values(); got != nil {
+		t.Errorf("GetStringToInt32Values() on nil message = %v, want nil", got)
+	}
+
+	// Map accessors are only generated for map fields
+	// and only if they do not conflict with other methods.
+	typ := reflect.TypeOf(m)
+	for _, name := range []string{"GetListOrDefault", "GetListKeys"} {
+		if _, ok := typ.MethodByName(name); ok {
+			t.Errorf("unexpected method %v", name)
+		}
+	}
+	if got := reflect.TypeOf(m.GetConflictKeys()); got.Kind() != reflect.String {
+		t.Errorf("GetConflictKeys() returns %v, want the getter for conflict_keys", got)
+	}
+	if _, ok := typ.MethodByName("GetConflictValues"); !ok {
+		t.Errorf("missing method GetConflictValues")
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	omitpb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/omitlegacy"
+)
+
+func TestOmitLegacyMethods(t *testing.T) {
+	for _, tt := range []struct {
+		typ     reflect.Type
+		methods []string
+	}{
+		{reflect.TypeOf(omitpb.Enum_ENUM_ONE), []string{"Enum", "EnumDescriptor"}},
+		{reflect.TypeOf(omitpb.Message_NESTED_ONE), []string{"Enum", "EnumDescriptor"}},
+		{reflect.TypeOf(&omitpb.Message{}), []string{"Descriptor"}},
+	} {
+		for _, name := range tt.methods {
+			if _, ok := tt.typ.MethodByName(name); ok {
+				t.Errorf("%v has method %v, want it omitted", tt.typ, name)
+			}
+		}
+	}
+
+	m := &omitpb.Message{E: omitpb.Enum_ENUM_ONE, Nested: omitpb.Message_NESTED_ONE, S: "s"}
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(omitpb.Message)
+	if err := proto.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, m) {
+		t.Errorf("round trip = %v, want %v", got, m)
+	}
+	if got, want := omitpb.Enum_ENUM_ONE.String(), "ENUM_ONE"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
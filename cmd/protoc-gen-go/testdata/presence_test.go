@@ -0,0 +1,65 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	presencepb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/presence"
+)
+
+func TestPresenceAccessors(t *testing.T) {
+	for _, m := range []*presencepb.Message{nil, {}} {
+		if m.HasCount() || m.HasName() || m.HasData() || m.HasColor() || m.HasChild() || m.HasChoiceInt() || m.HasChoiceMessage() {
+			t.Errorf("Has of empty message reports populated fields")
+		}
+		if v, ok := m.TryGetCount(); v != 5 || ok {
+			t.Errorf("TryGetCount() = %v, %v, want default 5, false", v, ok)
+		}
+		if v, ok := m.TryGetColor(); v != presencepb.Color_GREEN || ok {
+			t.Errorf("TryGetColor() = %v, %v, want default %v, false", v, ok, presencepb.Color_GREEN)
+		}
+		if v, ok := m.TryGetChild(); v != nil || ok {
+			t.Errorf("TryGetChild() = %v, %v, want nil, false", v, ok)
+		}
+	}
+
+	// Fields set to the zero value are populated.
+	m := &presencepb.Message{
+		Count:  proto.Int32(0),
+		Name:   proto.String(""),
+		Data:   []byte{},
+		Color:  presencepb.Color_RED.Enum(),
+		Child:  &presencepb.Message{},
+		Choice: &presencepb.Message_ChoiceInt{ChoiceInt: 0},
+	}
+	if !m.HasCount() || !m.HasName() || !m.HasData() || !m.HasColor() || !m.HasChild() || !m.HasChoiceInt() {
+		t.Errorf("Has of populated message reports unpopulated fields")
+	}
+	if m.HasChoiceMessage() {
+		t.Errorf("HasChoiceMessage() = true for other oneof member")
+	}
+	if v, ok := m.TryGetCount(); v != 0 || !ok {
+		t.Errorf("TryGetCount() = %v, %v, want 0, true", v, ok)
+	}
+	if v, ok := m.TryGetColor(); v != presencepb.Color_RED || !ok {
+		t.Errorf("TryGetColor() = %v, %v, want %v, true", v, ok, presencepb.Color_RED)
+	}
+	if v, ok := m.TryGetChoiceInt(); v != 0 || !ok {
+		t.Errorf("TryGetChoiceInt() = %v, %v, want 0, true", v, ok)
+	}
+
+	// Accessors are not generated for repeated and map fields,
+	// nor when they conflict with other fields.
+	typ := reflect.TypeOf(m)
+	for _, name := range []string{"HasValues", "TryGetValues", "HasCounts", "HasSize", "TryGetSize"} {
+		if _, ok := typ.MethodByName(name); ok {
+			t.Errorf("method %v is generated", name)
+		}
+	}
+}
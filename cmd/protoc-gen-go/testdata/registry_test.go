@@ -12,15 +12,33 @@ import (
 	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
-func TestRegistry(t *testing.T) {
-	var hasFiles bool
+// eagerFiles are the files which went through lazy initialization
+// during package initialization, before any test could access them.
+var eagerFiles []string
+
+// eagerFilesAllowed are the files whose generated code deliberately
+// initializes them when their package is initialized.
+var eagerFilesAllowed = map[string]bool{
+	// Generated with field_descriptors.
+	"cmd/protoc-gen-go/testdata/fielddescs/fielddescs.proto": true,
+}
+
+var hasFiles bool
+
+func init() {
 	protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
-		if fd.(*filedesc.File).L2 != nil {
-			t.Errorf("file %q eagerly went through lazy initialization", fd.Path())
+		if fd.(*filedesc.File).L2 != nil && !eagerFilesAllowed[fd.Path()] {
+			eagerFiles = append(eagerFiles, fd.Path())
 		}
 		hasFiles = true
 		return true
 	})
+}
+
+func TestRegistry(t *testing.T) {
+	for _, path := range eagerFiles {
+		t.Errorf("file %q eagerly went through lazy initialization", path)
+	}
 	if !hasFiles {
 		t.Errorf("protoregistry.GlobalFiles is empty")
 	}
@@ -0,0 +1,77 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	validatepb "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/validate"
+)
+
+func TestValidateMethods(t *testing.T) {
+	valid := func() *validatepb.Message {
+		return &validatepb.Message{
+			Name:       "abc",
+			Count:      proto.Int64(-5),
+			Size:       100,
+			Ratio:      0.5,
+			Data:       []byte("xy"),
+			Tags:       []string{"#a", "#b"},
+			Scores:     map[string]int32{"a": 1},
+			Nested:     &validatepb.Message_Nested{Value: 10},
+			NestedList: []*validatepb.Message_Nested{{}, nil},
+			NestedMap:  map[string]*validatepb.Message_Nested{"k": {Value: 1}},
+			Choice:     &validatepb.Message_Amount{Amount: 1.5},
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Validate() of valid message = %v, want nil", err)
+	}
+	if err := (*validatepb.Message)(nil).Validate(); err != nil {
+		t.Errorf("Validate() of nil message = %v, want nil", err)
+	}
+
+	for _, tt := range []struct {
+		desc   string
+		mutate func(*validatepb.Message)
+		want   string
+	}{
+		{"required string", func(m *validatepb.Message) { m.Name = "" }, "Message.name: value is required"},
+		{"string length", func(m *validatepb.Message) { m.Name = "abcdefghi" }, "Message.name: value must have at most 8 characters"},
+		{"pattern", func(m *validatepb.Message) { m.Name = "ABC" }, `Message.name: value must match the pattern "^[a-z]+$"`},
+		{"optional min", func(m *validatepb.Message) { m.Count = proto.Int64(-6) }, "Message.count: value must be at least -5"},
+		{"unsigned max", func(m *validatepb.Message) { m.Size = 101 }, "Message.size: value must be at most 100"},
+		{"float max", func(m *validatepb.Message) { m.Ratio = 1.25 }, "Message.ratio: value must be at most 1"},
+		{"NaN", func(m *validatepb.Message) { m.Ratio = math.NaN() }, "Message.ratio: value must be at least 0"},
+		{"bytes length", func(m *validatepb.Message) { m.Data = nil }, "Message.data: value must have at least 2 bytes"},
+		{"list length", func(m *validatepb.Message) { m.Tags = append(m.Tags, "#c") }, "Message.tags: value must have at most 2 elements"},
+		{"list element", func(m *validatepb.Message) { m.Tags[1] = "b" }, `Message.tags[1]: value must match the pattern "^#"`},
+		{"map value", func(m *validatepb.Message) { m.Scores["a"] = 0 }, "Message.scores[a]: value must be at least 1"},
+		{"required message", func(m *validatepb.Message) { m.Nested = nil }, "Message.nested: value is required"},
+		{"nested message", func(m *validatepb.Message) { m.Nested.Value = 11 }, "Message.nested: goproto.protoc.validate.Message.Nested.value: value must be at most 10"},
+		{"nested list", func(m *validatepb.Message) { m.NestedList[0].Value = -1 }, "Message.nested_list[0]: goproto.protoc.validate.Message.Nested.value: value must be at least 0"},
+		{"nested map", func(m *validatepb.Message) { m.NestedMap["k"].Value = -1 }, "Message.nested_map[k]: goproto.protoc.validate.Message.Nested.value: value must be at least 0"},
+		{"oneof", func(m *validatepb.Message) { m.Choice = &validatepb.Message_Text{} }, "Message.text: value must have at least 1 characters"},
+	} {
+		m := valid()
+		tt.mutate(m)
+		err := m.Validate()
+		if err == nil || !strings.HasSuffix(err.Error(), tt.want) {
+			t.Errorf("%s: Validate() = %v, want error ending in %q", tt.desc, err, tt.want)
+		}
+	}
+
+	// Errors of nested messages are wrapped.
+	m := valid()
+	m.Nested.Value = 11
+	if err := m.Validate(); err == nil || errors.Unwrap(err) == nil {
+		t.Errorf("Validate() = %v, want a wrapped error", err)
+	}
+}
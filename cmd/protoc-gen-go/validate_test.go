@@ -0,0 +1,63 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/govalidatepb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestValidateMethodsInvalidRules(t *testing.T) {
+	for _, tt := range []struct {
+		field string
+		want  string
+	}{
+		{`type: TYPE_INT32 options: {[braincorp.protobuf.validate.rules]: {pattern: "x"}}`, "pattern applies only to string and bytes fields"},
+		{`type: TYPE_STRING options: {[braincorp.protobuf.validate.rules]: {min: 1}}`, "min and max apply only to numeric fields"},
+		{`type: TYPE_BOOL options: {[braincorp.protobuf.validate.rules]: {max_len: 1}}`, "min_len and max_len apply only to"},
+		{`type: TYPE_UINT32 options: {[braincorp.protobuf.validate.rules]: {min: -1}}`, "bound -1 is not representable"},
+		{`type: TYPE_INT64 options: {[braincorp.protobuf.validate.rules]: {max: 1.5}}`, "bound 1.5 is not representable"},
+		{`type: TYPE_DOUBLE options: {[braincorp.protobuf.validate.rules]: {min: 2 max: 1}}`, "min 2 is greater than max 1"},
+		{`type: TYPE_STRING options: {[braincorp.protobuf.validate.rules]: {pattern: "("}}`, "invalid pattern"},
+	} {
+		file := new(descriptorpb.FileDescriptorProto)
+		if err := prototext.Unmarshal([]byte(`
+			name: "rules.proto"
+			package: "goproto.rules"
+			syntax: "proto3"
+			dependency: "braincorp/protobuf/validate.proto"
+			options: {go_package: "example.com/rulespb"}
+			message_type: [{
+				name: "Message"
+				field: [{name: "f" number: 1 label: LABEL_OPTIONAL json_name: "f" `+tt.field+`}]
+			}]
+		`), file); err != nil {
+			t.Fatal(err)
+		}
+		gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+			FileToGenerate: []string{"rules.proto"},
+			ProtoFile: []*descriptorpb.FileDescriptorProto{
+				protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+				protodesc.ToFileDescriptorProto(govalidatepb.File_braincorp_protobuf_validate_proto),
+				file,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		gengo.Options{ValidateMethods: true}.GenerateFile(gen, gen.FilesByPath["rules.proto"])
+		if got := gen.Response().GetError(); !strings.Contains(got, tt.want) {
+			t.Errorf("generating field {%s}: error = %q, want it to contain %q", tt.field, got, tt.want)
+		}
+	}
+}
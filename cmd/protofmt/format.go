@@ -0,0 +1,560 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The formatter operates on a lightweight syntax tree of the text format
+// that retains comments, unlike the prototext package which discards them.
+
+// document is a parsed textproto file.
+type document struct {
+	header  []string // comments at the start of the file
+	root    *message
+	trailer []string // comments after the last field
+}
+
+// message is the body of a message value.
+type message struct {
+	fields []*field
+	end    []string // comments before the closing brace
+}
+
+// field is a single "name: value" entry.
+type field struct {
+	leading  []string // comment lines preceding the field
+	blank    bool     // whether a blank line preceded the field
+	name     string   // field name, or bracketed extension or type URL
+	value    *value
+	trailing string // comment following the field on the same line
+	number   protoreflect.FieldNumber
+}
+
+// value is a scalar, message, or list value.
+type value struct {
+	leading  []string
+	scalar   string // raw text of a scalar value
+	msg      *message
+	list     []*value
+	isList   bool
+	open     string   // comment following the opening brace or bracket
+	end      []string // comments before the closing bracket of a list
+	trailing string
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokSymbol
+)
+
+type tok struct {
+	kind tokKind
+	text string
+	line int
+}
+
+// syntaxError is an error in the textproto source.
+type syntaxError struct {
+	line int
+	msg  string
+}
+
+func (e *syntaxError) Error() string {
+	return fmt.Sprintf("%d: %s", e.line, e.msg)
+}
+
+// parser parses the text format into a document.
+type parser struct {
+	src      string
+	off      int
+	line     int
+	prevLine int // line of the previous token
+
+	tok      tok
+	comments []comment // comments preceding tok
+	blank    bool      // whether a blank line precedes tok or its comments
+}
+
+type comment struct {
+	text string
+	line int
+}
+
+func parse(src []byte) (doc *document, err error) {
+	p := &parser{src: string(src), line: 1}
+	defer func() {
+		if r := recover(); r != nil {
+			se, ok := r.(*syntaxError)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, se
+		}
+	}()
+	p.next()
+	doc = &document{root: p.parseFields(false)}
+	doc.trailer = p.leading()
+	if len(doc.root.fields) > 0 {
+		f := doc.root.fields[0]
+		doc.header, f.leading = splitHeader(f.leading)
+		f.blank = false
+	}
+	return doc, nil
+}
+
+// splitHeader splits the comments before the first field into the file header
+// and the comments of the field. The header consists of all comments before
+// the last blank line, along with any "# proto-" directives.
+func splitHeader(lines []string) (header, rest []string) {
+	n := 0
+	for i, line := range lines {
+		if line == "" {
+			n = i + 1
+		}
+	}
+	for n < len(lines) && strings.HasPrefix(lines[n], "# proto-") {
+		n++
+	}
+	return lines[:n], lines[n:]
+}
+
+func (p *parser) errorf(f string, x ...any) {
+	panic(&syntaxError{p.tok.line, fmt.Sprintf(f, x...)})
+}
+
+// next advances to the next token, collecting the comments before it.
+func (p *parser) next() {
+	p.prevLine = p.tok.line
+	p.comments = nil
+	p.blank = false
+	newlines := 0
+	hasLeading := false
+	for p.off < len(p.src) {
+		c := p.src[p.off]
+		switch {
+		case c == '\n':
+			p.line++
+			p.off++
+			newlines++
+			if newlines > 1 && !hasLeading {
+				p.blank = true
+			}
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\v' || c == '\f':
+			p.off++
+			continue
+		case c == '#':
+			n := strings.IndexByte(p.src[p.off:], '\n')
+			if n < 0 {
+				n = len(p.src) - p.off
+			}
+			if newlines > 1 && hasLeading {
+				p.comments = append(p.comments, comment{"", p.line}) // blank line
+			}
+			hasLeading = hasLeading || p.line != p.prevLine
+			p.comments = append(p.comments, comment{strings.TrimRight(p.src[p.off:p.off+n], " \t\r"), p.line})
+			p.off += n
+			newlines = 0
+			continue
+		}
+		break
+	}
+
+	p.tok = tok{line: p.line}
+	if p.off >= len(p.src) {
+		p.tok.kind = tokEOF
+		return
+	}
+	start := p.off
+	c := p.src[p.off]
+	switch {
+	case isLetter(c):
+		for p.off < len(p.src) && (isLetter(p.src[p.off]) || isDigit(p.src[p.off])) {
+			p.off++
+		}
+		p.tok.kind = tokIdent
+	case isDigit(c) || (c == '.' && p.off+1 < len(p.src) && isDigit(p.src[p.off+1])):
+		for p.off < len(p.src) {
+			c := p.src[p.off]
+			if isLetter(c) || isDigit(c) || c == '.' {
+				p.off++
+			} else if (c == '+' || c == '-') && (p.src[p.off-1] == 'e' || p.src[p.off-1] == 'E') && !strings.HasPrefix(strings.ToLower(p.src[start:]), "0x") {
+				p.off++
+			} else {
+				break
+			}
+		}
+		p.tok.kind = tokNumber
+	case c == '"' || c == '\'':
+		p.off++
+		for {
+			if p.off >= len(p.src) || p.src[p.off] == '\n' {
+				p.errorf("unterminated string")
+			}
+			if p.src[p.off] == '\\' {
+				p.off += 2
+				continue
+			}
+			p.off++
+			if p.src[p.off-1] == c {
+				break
+			}
+		}
+		p.tok.kind = tokString
+	case strings.IndexByte("{}[]<>:,;/.-", c) >= 0:
+		p.off++
+		p.tok.kind = tokSymbol
+	default:
+		p.errorf("unexpected character %q", c)
+	}
+	p.tok.text = p.src[start:p.off]
+}
+
+// leading returns the comment lines before the current token.
+func (p *parser) leading() []string {
+	var lines []string
+	for _, c := range p.comments {
+		if c.line == p.prevLine && p.prevLine > 0 {
+			continue // trailing comment of the previous token
+		}
+		lines = append(lines, c.text)
+	}
+	return lines
+}
+
+// trailing returns the comment following the previous token on the same line.
+func (p *parser) trailing() string {
+	if len(p.comments) > 0 && p.comments[0].line == p.prevLine {
+		return p.comments[0].text
+	}
+	return ""
+}
+
+func (p *parser) isSymbol(s string) bool {
+	return p.tok.kind == tokSymbol && p.tok.text == s
+}
+
+func (p *parser) expect(s string) {
+	if !p.isSymbol(s) {
+		p.errorf("unexpected %q, expected %q", p.tok.text, s)
+	}
+	p.next()
+}
+
+// parseFields parses a sequence of fields, up to a closing brace if nested.
+func (p *parser) parseFields(nested bool) *message {
+	m := new(message)
+	for {
+		switch {
+		case p.tok.kind == tokEOF:
+			if nested {
+				p.errorf("unexpected end of file")
+			}
+			return m
+		case nested && (p.isSymbol("}") || p.isSymbol(">")):
+			m.end = p.leading()
+			return m
+		}
+		m.fields = append(m.fields, p.parseField())
+	}
+}
+
+func (p *parser) parseField() *field {
+	f := &field{leading: p.leading(), blank: p.blank}
+	switch {
+	case p.tok.kind == tokIdent:
+		f.name = p.tok.text
+		p.next()
+	case p.isSymbol("["):
+		var b strings.Builder
+		b.WriteString("[")
+		p.next()
+		for !p.isSymbol("]") {
+			if p.tok.kind != tokIdent && !p.isSymbol(".") && !p.isSymbol("/") {
+				p.errorf("unexpected %q in extension name", p.tok.text)
+			}
+			b.WriteString(p.tok.text)
+			p.next()
+		}
+		b.WriteString("]")
+		f.name = b.String()
+		p.next()
+	default:
+		p.errorf("unexpected %q, expected field name", p.tok.text)
+	}
+	if p.isSymbol(":") {
+		p.next()
+	}
+	f.value = p.parseValue(true)
+	if p.isSymbol(",") || p.isSymbol(";") {
+		p.next()
+	}
+	f.trailing = p.trailing()
+	return f
+}
+
+func (p *parser) parseValue(allowList bool) *value {
+	v := &value{leading: p.leading()}
+	switch {
+	case p.isSymbol("{") || p.isSymbol("<"):
+		closing := map[string]string{"{": "}", "<": ">"}[p.tok.text]
+		p.next()
+		v.open = p.trailing()
+		v.msg = p.parseFields(true)
+		p.expect(closing)
+	case allowList && p.isSymbol("["):
+		v.isList = true
+		p.next()
+		v.open = p.trailing()
+		for !p.isSymbol("]") {
+			if p.tok.kind == tokEOF {
+				p.errorf("unexpected end of file")
+			}
+			elem := p.parseValue(false)
+			if p.isSymbol(",") {
+				p.next()
+			}
+			elem.trailing = p.trailing()
+			v.list = append(v.list, elem)
+		}
+		v.end = p.leading()
+		p.next()
+	case p.isSymbol("-"):
+		p.next()
+		if p.tok.kind != tokNumber && p.tok.kind != tokIdent {
+			p.errorf("unexpected %q after '-'", p.tok.text)
+		}
+		v.scalar = "-" + p.tok.text
+		p.next()
+	case p.tok.kind == tokString:
+		var parts []string
+		for p.tok.kind == tokString {
+			parts = append(parts, p.tok.text)
+			p.next()
+		}
+		v.scalar = strings.Join(parts, " ")
+	case p.tok.kind == tokNumber || p.tok.kind == tokIdent:
+		v.scalar = p.tok.text
+		p.next()
+	default:
+		p.errorf("unexpected %q, expected value", p.tok.text)
+	}
+	return v
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// resolver looks up extensions and message types by name.
+type resolver interface {
+	FindExtensionByName(protoreflect.FullName) (protoreflect.ExtensionType, error)
+	FindMessageByURL(string) (protoreflect.MessageType, error)
+}
+
+// resolve associates the fields of m with the fields of md,
+// reporting any unknown field names.
+func resolve(m *message, md protoreflect.MessageDescriptor, r resolver) error {
+	var errs []error
+	for _, f := range m.fields {
+		var fd protoreflect.FieldDescriptor
+		switch {
+		case strings.HasPrefix(f.name, "[") && strings.Contains(f.name, "/"):
+			// An expanded google.protobuf.Any.
+			mt, err := r.FindMessageByURL(strings.Trim(f.name, "[]"))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("unknown message type %s", f.name))
+				continue
+			}
+			f.number = 1
+			if f.value.msg != nil {
+				if err := resolve(f.value.msg, mt.Descriptor(), r); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			continue
+		case strings.HasPrefix(f.name, "["):
+			xt, err := r.FindExtensionByName(protoreflect.FullName(strings.Trim(f.name, "[]")))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("unknown extension %s", f.name))
+				continue
+			}
+			fd = xt.TypeDescriptor()
+		default:
+			fd = md.Fields().ByTextName(f.name)
+			if fd == nil {
+				errs = append(errs, fmt.Errorf("unknown field %q in message %v", f.name, md.FullName()))
+				continue
+			}
+		}
+		f.number = fd.Number()
+		if fd.Message() == nil {
+			continue
+		}
+		sub := fd.Message()
+		for _, v := range append([]*value{f.value}, f.value.list...) {
+			if v.msg != nil {
+				if err := resolve(v.msg, sub, r); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// formatOptions configures the formatting of a document.
+type formatOptions struct {
+	// sortFields orders fields by field number, which requires that
+	// the fields have been resolved against a message descriptor.
+	sortFields bool
+}
+
+func (o formatOptions) format(doc *document) []byte {
+	var b strings.Builder
+	writeComments(&b, doc.header, 0)
+	o.formatFields(&b, doc.root, 0)
+	writeComments(&b, doc.trailer, 0)
+	return []byte(b.String())
+}
+
+func (o formatOptions) formatFields(b *strings.Builder, m *message, depth int) {
+	fields := m.fields
+	if o.sortFields {
+		fields = append([]*field(nil), fields...)
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].number < fields[j].number
+		})
+	}
+	for i, f := range fields {
+		if f.blank && i > 0 {
+			b.WriteString("\n")
+		}
+		writeComments(b, f.leading, depth)
+		indent(b, depth)
+		b.WriteString(f.name)
+		switch v := f.value; {
+		case v.msg != nil:
+			b.WriteString(" ")
+			o.formatValue(b, v, depth)
+		case v.isList:
+			b.WriteString(": ")
+			o.formatValue(b, v, depth)
+		default:
+			b.WriteString(": ")
+			b.WriteString(v.scalar)
+		}
+		if f.trailing != "" {
+			b.WriteString(" ")
+			b.WriteString(f.trailing)
+		}
+		b.WriteString("\n")
+	}
+	writeComments(b, m.end, depth)
+}
+
+func (o formatOptions) formatValue(b *strings.Builder, v *value, depth int) {
+	switch {
+	case v.msg != nil:
+		if len(v.msg.fields) == 0 && len(v.msg.end) == 0 && v.open == "" {
+			b.WriteString("{}")
+			return
+		}
+		b.WriteString("{")
+		writeOpenComment(b, v.open)
+		o.formatFields(b, v.msg, depth+1)
+		indent(b, depth)
+		b.WriteString("}")
+	case v.isList:
+		if isInlineList(v) {
+			b.WriteString("[")
+			for i, e := range v.list {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(e.scalar)
+			}
+			b.WriteString("]")
+			return
+		}
+		b.WriteString("[")
+		writeOpenComment(b, v.open)
+		for i, e := range v.list {
+			writeComments(b, e.leading, depth+1)
+			indent(b, depth+1)
+			o.formatValue(b, e, depth+1)
+			if i < len(v.list)-1 {
+				b.WriteString(",")
+			}
+			if e.trailing != "" {
+				b.WriteString(" ")
+				b.WriteString(e.trailing)
+			}
+			b.WriteString("\n")
+		}
+		writeComments(b, v.end, depth+1)
+		indent(b, depth)
+		b.WriteString("]")
+	default:
+		b.WriteString(v.scalar)
+	}
+}
+
+// isInlineList reports whether a list can be printed on a single line.
+func isInlineList(v *value) bool {
+	if v.open != "" || len(v.end) > 0 {
+		return false
+	}
+	n := 0
+	for _, e := range v.list {
+		if e.msg != nil || len(e.leading) > 0 || e.trailing != "" {
+			return false
+		}
+		n += len(e.scalar) + 2
+	}
+	return n <= 80
+}
+
+func writeOpenComment(b *strings.Builder, c string) {
+	if c != "" {
+		b.WriteString(" ")
+		b.WriteString(c)
+	}
+	b.WriteString("\n")
+}
+
+func writeComments(b *strings.Builder, lines []string, depth int) {
+	for _, line := range lines {
+		if line == "" {
+			b.WriteString("\n")
+			continue
+		}
+		indent(b, depth)
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+}
+
+func indent(b *strings.Builder, depth int) {
+	for i := 0; i < depth; i++ {
+		b.WriteString("  ")
+	}
+}
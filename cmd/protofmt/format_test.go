@@ -0,0 +1,201 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/compiler/protoparse"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{{
+		name: "empty",
+		in:   "",
+		want: "",
+	}, {
+		name: "scalars",
+		in:   `a:1 b : "x" 'y';c:-inf,d:ENUM`,
+		want: "a: 1\nb: \"x\" 'y'\nc: -inf\nd: ENUM\n",
+	}, {
+		name: "messages",
+		in:   "m:<a:1> n{}\nm2 {\n      x {y: 2}}",
+		want: "m {\n  a: 1\n}\nn {}\nm2 {\n  x {\n    y: 2\n  }\n}\n",
+	}, {
+		name: "lists",
+		in:   "a: [1,2 ,3] m: [{a: 1}, {}]",
+		want: "a: [1, 2, 3]\nm: [\n  {\n    a: 1\n  },\n  {}\n]\n",
+	}, {
+		name: "extensions and any",
+		in:   "[foo.bar]: 1 [type.googleapis.com/foo.Msg] {a: 1}",
+		want: "[foo.bar]: 1\n[type.googleapis.com/foo.Msg] {\n  a: 1\n}\n",
+	}, {
+		name: "comments",
+		in: `# proto-message: foo.M
+
+# Leading comment.
+a: 1  # Trailing comment.
+
+
+b { # Open comment.
+  # Inner comment.
+  c: 2
+  # End comment.
+}
+l: [
+  1, # One.
+  # Two.
+  2
+]
+# Final comment.
+`,
+		want: `# proto-message: foo.M
+
+# Leading comment.
+a: 1 # Trailing comment.
+
+b { # Open comment.
+  # Inner comment.
+  c: 2
+  # End comment.
+}
+l: [
+  1, # One.
+  # Two.
+  2
+]
+# Final comment.
+`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parse([]byte(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := string(formatOptions{}.format(doc))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("format mismatch (-want +got):\n%s", diff)
+			}
+
+			// Formatting must be idempotent.
+			doc, err = parse([]byte(got))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again := string(formatOptions{}.format(doc)); again != got {
+				t.Errorf("format is not idempotent:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{
+		`a: "unterminated`,
+		`a {`,
+		`a: [1, 2`,
+		`: 1`,
+		`a: }`,
+	} {
+		if _, err := parse([]byte(in)); err == nil {
+			t.Errorf("parse(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestSchema(t *testing.T) {
+	const schema = `
+		syntax = "proto3";
+		package foo;
+		message M {
+			int32 a = 1;
+			string b = 2;
+			repeated N n = 3;
+			map<string, N> m = 4;
+		}
+		message N {
+			int32 x = 1;
+			int32 y = 2;
+		}
+	`
+	p := protoparse.Parser{
+		Accessor: func(string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(schema)), nil
+		},
+	}
+	fds, err := p.ParseFiles("foo.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := new(protoregistry.Files)
+	if err := files.RegisterFile(fds[0]); err != nil {
+		t.Fatal(err)
+	}
+	f := &formatter{
+		files: files,
+		types: dynamicpb.NewTypes(files),
+		opts:  formatOptions{sortFields: true},
+	}
+
+	var out bytes.Buffer
+	in := "# proto-message: foo.M\nm {key: \"k\" value {y: 1 x: 2}}\nn {y: 3}\nb: \"s\"\na: 1\n"
+	if err := f.process("test.textproto", []byte(in), &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "# proto-message: foo.M\na: 1\nb: \"s\"\nn {\n  y: 3\n}\nm {\n  key: \"k\"\n  value {\n    x: 2\n    y: 1\n  }\n}\n"
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("process mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, in := range []string{
+		"# proto-message: foo.M\nc: 1\n",
+		"# proto-message: foo.M\na: \"not a number\"\n",
+		"# proto-message: foo.Missing\n",
+		"a: 1\n",
+	} {
+		if err := f.process("test.textproto", []byte(in), io.Discard); err == nil {
+			t.Errorf("process(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestWritePreservesMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "in.textproto")
+	if err := os.WriteFile(path, []byte("a:1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	f := &formatter{write: true}
+	if err := f.process(path, []byte("a:1"), io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "a: 1\n" {
+		t.Errorf("file contents = %q, want %q", got, "a: 1\n")
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fi.Mode().Perm(); got != 0600 {
+		t.Errorf("file mode = %v, want %v", got, os.FileMode(0600))
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The protofmt command formats protobuf text format (.textproto) files.
+//
+// Usage:
+//
+//	protofmt [flags] [path ...]
+//
+// Without a schema, protofmt reformats files purely syntactically:
+// it normalizes indentation, separators, and brace styles while keeping
+// all comments. If a schema is provided with -descriptor_set_in or -proto,
+// each file is also validated against the message type named by -message
+// or by a "# proto-message: <name>" comment at the top of the file,
+// and the -sort flag may be used to order fields by field number.
+//
+// With no paths, protofmt reads from standard input and
+// writes to standard output.
+//
+// The flags are:
+//
+//	-w
+//		Write the result to the source file instead of standard output.
+//	-l
+//		List the files whose formatting differs from protofmt's.
+//	-check
+//		Only validate the files; do not print the formatted output.
+//	-sort
+//		Order fields by field number (requires a schema).
+//	-descriptor_set_in=FILES
+//		Load the schema from the given binary FileDescriptorSet files,
+//		separated by the OS path list separator.
+//	-proto=FILES
+//		Load the schema by parsing the given comma-separated .proto files,
+//		resolved relative to -proto_path.
+//	-proto_path=DIRS
+//		Directories to search for .proto files,
+//		separated by the OS path list separator.
+//	-message=NAME
+//		The full name of the message type of the files.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protoparse"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var (
+	write           = flag.Bool("w", false, "write result to the source file instead of standard output")
+	list            = flag.Bool("l", false, "list files whose formatting differs from protofmt's")
+	check           = flag.Bool("check", false, "only validate the files")
+	sortFields      = flag.Bool("sort", false, "order fields by field number")
+	descriptorSetIn = flag.String("descriptor_set_in", "", "binary FileDescriptorSet files to load the schema from")
+	protoFiles      = flag.String("proto", "", "comma-separated .proto files to load the schema from")
+	protoPath       = flag.String("proto_path", "", "directories to search for .proto files")
+	messageName     = flag.String("message", "", "full name of the message type of the files")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: protofmt [flags] [path ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	files, err := loadSchema()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "protofmt: %v\n", err)
+		os.Exit(2)
+	}
+	f := &formatter{
+		files:   files,
+		message: protoreflect.FullName(*messageName),
+		opts:    formatOptions{sortFields: *sortFields},
+		write:   *write,
+		list:    *list,
+		check:   *check,
+	}
+	if files != nil {
+		f.types = dynamicpb.NewTypes(files)
+	}
+	if *sortFields && files == nil {
+		fmt.Fprintf(os.Stderr, "protofmt: -sort requires a schema\n")
+		os.Exit(2)
+	}
+
+	exitCode := 0
+	if flag.NArg() == 0 {
+		if *write {
+			fmt.Fprintf(os.Stderr, "protofmt: cannot use -w with standard input\n")
+			os.Exit(2)
+		}
+		src, err := io.ReadAll(os.Stdin)
+		if err == nil {
+			err = f.process("<standard input>", src, os.Stdout)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			exitCode = 1
+		}
+	}
+	for _, path := range flag.Args() {
+		src, err := os.ReadFile(path)
+		if err == nil {
+			err = f.process(path, src, os.Stdout)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// loadSchema loads the files specified by the -descriptor_set_in
+// and -proto flags, or returns nil if no schema was provided.
+func loadSchema() (*protoregistry.Files, error) {
+	if *descriptorSetIn == "" && *protoFiles == "" {
+		return nil, nil
+	}
+	fdset := new(descriptorpb.FileDescriptorSet)
+	if *descriptorSetIn != "" {
+		for _, path := range filepath.SplitList(*descriptorSetIn) {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			set := new(descriptorpb.FileDescriptorSet)
+			if err := proto.Unmarshal(b, set); err != nil {
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+			fdset.File = append(fdset.File, set.File...)
+		}
+	}
+	if *protoFiles != "" {
+		p := protoparse.Parser{ImportPaths: filepath.SplitList(*protoPath)}
+		fds, err := p.ParseFiles(strings.Split(*protoFiles, ",")...)
+		if err != nil {
+			return nil, err
+		}
+		seen := map[string]bool{}
+		var add func(fd protoreflect.FileDescriptor)
+		add = func(fd protoreflect.FileDescriptor) {
+			if seen[fd.Path()] {
+				return
+			}
+			seen[fd.Path()] = true
+			for i := 0; i < fd.Imports().Len(); i++ {
+				add(fd.Imports().Get(i).FileDescriptor)
+			}
+			fdset.File = append(fdset.File, protodesc.ToFileDescriptorProto(fd))
+		}
+		for _, fd := range fds {
+			add(fd)
+		}
+	}
+	return protodesc.NewFiles(dedupFiles(fdset))
+}
+
+// dedupFiles removes duplicate files, which occur when multiple
+// descriptor sets contain the same dependencies.
+func dedupFiles(fdset *descriptorpb.FileDescriptorSet) *descriptorpb.FileDescriptorSet {
+	seen := map[string]bool{}
+	out := new(descriptorpb.FileDescriptorSet)
+	for _, fd := range fdset.GetFile() {
+		if !seen[fd.GetName()] {
+			seen[fd.GetName()] = true
+			out.File = append(out.File, fd)
+		}
+	}
+	return out
+}
+
+// formatter formats and validates textproto files.
+type formatter struct {
+	files   *protoregistry.Files // nil if no schema was provided
+	types   *dynamicpb.Types
+	message protoreflect.FullName
+	opts    formatOptions
+
+	write, list, check bool
+}
+
+// messageHeader matches the conventional header comment that
+// names the message type of a textproto file.
+var messageHeader = regexp.MustCompile(`(?m)^#\s*proto-message:\s*([\w.]+)\s*$`)
+
+// process formats the file at path with the given contents.
+func (f *formatter) process(path string, src []byte, out io.Writer) error {
+	doc, err := parse(src)
+	if err != nil {
+		return fmt.Errorf("%s:%v", path, err)
+	}
+
+	if f.files != nil {
+		name := f.message
+		if m := messageHeader.FindSubmatch(src); m != nil && name == "" {
+			name = protoreflect.FullName(m[1])
+		}
+		if name == "" {
+			return fmt.Errorf("%s: unknown message type; use -message or a \"# proto-message:\" header", path)
+		}
+		d, err := f.files.FindDescriptorByName(name)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		md, ok := d.(protoreflect.MessageDescriptor)
+		if !ok {
+			return fmt.Errorf("%s: %v is not a message", path, name)
+		}
+		m := dynamicpb.NewMessage(md)
+		if err := (prototext.UnmarshalOptions{Resolver: f.types}).Unmarshal(src, m); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		if err := resolve(doc.root, md, f.types); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	if f.check {
+		return nil
+	}
+
+	res := f.opts.format(doc)
+	changed := !bytes.Equal(src, res)
+	if f.list && changed {
+		fmt.Fprintln(out, path)
+	}
+	if f.write && changed {
+		return writeFile(path, res)
+	}
+	if !f.list && !f.write {
+		_, err := out.Write(res)
+		return err
+	}
+	return nil
+}
+
+// writeFile replaces the contents of the file at path with b,
+// preserving the permissions of the original file.
+// The new contents are written to a temporary file in the same directory
+// which is then renamed over the original so that a failed write
+// never leaves a truncated file behind.
+func writeFile(path string, b []byte) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".protofmt")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(fi.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
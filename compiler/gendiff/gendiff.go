@@ -0,0 +1,162 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gendiff compares generated files with the files they would replace,
+// such as to report what a code generator would change without writing
+// its output.
+//
+// The comparison is implemented in Go and does not depend on
+// a diff program being installed.
+package gendiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/protobuf/internal/linediff"
+)
+
+// ChangeKind is the kind of change made to a file.
+type ChangeKind int
+
+const (
+	// Added indicates that the file does not exist.
+	Added ChangeKind = iota + 1
+	// Removed indicates that the file is no longer generated.
+	Removed
+	// Modified indicates that the contents of the file differ.
+	Modified
+)
+
+// String returns a single-letter code for the kind of change: "A", "D", or "M".
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "A"
+	case Removed:
+		return "D"
+	case Modified:
+		return "M"
+	default:
+		return fmt.Sprintf("<unknown:%d>", int(k))
+	}
+}
+
+// MarshalText encodes the kind of change as its string representation.
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Change is a change made to a file.
+type Change struct {
+	// Path is the path of the file.
+	Path string `json:"path"`
+	// Kind is the kind of change.
+	Kind ChangeKind `json:"kind"`
+	// Added and Removed are the number of lines added and removed.
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	// Diff is the change in the unified diff format.
+	Diff string `json:"-"`
+}
+
+// Compare compares the old and new contents of the file at path,
+// where a nil slice means that the file does not exist.
+// It reports nil if the contents are identical.
+func Compare(path string, old, new []byte) *Change {
+	if bytes.Equal(old, new) && (old == nil) == (new == nil) {
+		return nil
+	}
+	c := &Change{Path: path, Kind: Modified}
+	switch {
+	case old == nil:
+		c.Kind = Added
+	case new == nil:
+		c.Kind = Removed
+	}
+	edits := linediff.Lines(linediff.Split(string(old)), linediff.Split(string(new)))
+	for _, e := range edits {
+		switch e.Op {
+		case linediff.Insert:
+			c.Added++
+		case linediff.Delete:
+			c.Removed++
+		}
+	}
+	c.Diff = linediff.Unified("a/"+path, "b/"+path, edits, contextLines)
+	return c
+}
+
+// ReportOptions configures the output of [ReportOptions.Write].
+type ReportOptions struct {
+	// Color specifies whether to color the output with ANSI escape sequences.
+	Color bool
+	// Summary specifies whether to only write a line summarizing each change,
+	// rather than the summary followed by the unified diff.
+	Summary bool
+}
+
+// Write writes a human-readable report of the changes to w.
+func (o ReportOptions) Write(w io.Writer, changes []*Change) error {
+	var b strings.Builder
+	for _, c := range changes {
+		o.colored(&b, colorBold, fmt.Sprintf("%v %v (+%d -%d)", c.Kind, c.Path, c.Added, c.Removed))
+		if o.Summary {
+			continue
+		}
+		for _, line := range strings.SplitAfter(c.Diff, "\n") {
+			switch {
+			case line == "":
+			case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+				o.colored(&b, colorBold, strings.TrimSuffix(line, "\n"))
+			case strings.HasPrefix(line, "@@"):
+				o.colored(&b, colorCyan, strings.TrimSuffix(line, "\n"))
+			case strings.HasPrefix(line, "-"):
+				o.colored(&b, colorRed, strings.TrimSuffix(line, "\n"))
+			case strings.HasPrefix(line, "+"):
+				o.colored(&b, colorGreen, strings.TrimSuffix(line, "\n"))
+			default:
+				b.WriteString(line)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+const (
+	colorBold  = "\x1b[1m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+	colorReset = "\x1b[0m"
+)
+
+func (o ReportOptions) colored(b *strings.Builder, color, line string) {
+	if o.Color {
+		b.WriteString(color + line + colorReset + "\n")
+	} else {
+		b.WriteString(line + "\n")
+	}
+}
+
+// WriteJSON writes the changes to w as a JSON array of objects with
+// the path, kind, and number of added and removed lines of each change.
+func WriteJSON(w io.Writer, changes []*Change) error {
+	if changes == nil {
+		changes = []*Change{}
+	}
+	b, err := json.MarshalIndent(changes, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gendiff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		desc     string
+		old, new string
+		noOld    bool
+		want     string
+	}{{
+		desc: "modified",
+		old:  "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n",
+		new:  "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\nn2\n",
+		want: `--- a/file.go
++++ b/file.go
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -12,3 +12,4 @@
+ l
+ m
+ n
++n2
+`,
+	}, {
+		desc: "merged hunks",
+		old:  "a\nb\nc\nd\ne\nf\ng\nh\n",
+		new:  "b\nc\nd\ne\nf\ng\n",
+		want: `--- a/file.go
++++ b/file.go
+@@ -1,8 +1,6 @@
+-a
+ b
+ c
+ d
+ e
+ f
+ g
+-h
+`,
+	}, {
+		desc:  "added",
+		noOld: true,
+		new:   "a\nb",
+		want: `--- a/file.go
++++ b/file.go
+@@ -0,0 +1,2 @@
++a
++b
+\ No newline at end of file
+`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			old := []byte(tt.old)
+			if tt.noOld {
+				old = nil
+			}
+			c := Compare("file.go", old, []byte(tt.new))
+			if c == nil {
+				t.Fatalf("Compare() = nil, want change")
+			}
+			if c.Diff != tt.want {
+				t.Errorf("Compare() diff mismatch:\ngot:\n%s\nwant:\n%s", c.Diff, tt.want)
+			}
+		})
+	}
+
+	if c := Compare("file.go", []byte("a\n"), []byte("a\n")); c != nil {
+		t.Errorf("Compare() of identical files = %+v, want nil", c)
+	}
+	if c := Compare("file.go", nil, []byte("a\nb\n")); c.Kind != Added || c.Added != 2 || c.Removed != 0 {
+		t.Errorf("Compare() of new file = %v (+%d -%d), want A (+2 -0)", c.Kind, c.Added, c.Removed)
+	}
+	if c := Compare("file.go", []byte("a\n"), nil); c.Kind != Removed || c.Added != 0 || c.Removed != 1 {
+		t.Errorf("Compare() of removed file = %v (+%d -%d), want D (+0 -1)", c.Kind, c.Added, c.Removed)
+	}
+}
+
+func TestReport(t *testing.T) {
+	changes := []*Change{
+		Compare("a.go", []byte("x\n"), []byte("y\n")),
+		Compare("b.go", nil, []byte("z\n")),
+	}
+	var b bytes.Buffer
+	if err := (ReportOptions{Summary: true}).Write(&b, changes); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := b.String(), "M a.go (+1 -1)\nA b.go (+1 -0)\n"; got != want {
+		t.Errorf("Write() = %q, want %q", got, want)
+	}
+
+	b.Reset()
+	if err := (ReportOptions{Color: true}).Write(&b, changes[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); !strings.Contains(got, colorRed+"-x"+colorReset+"\n") || !strings.Contains(got, colorGreen+"+y"+colorReset+"\n") {
+		t.Errorf("Write() with color = %q, want colored lines", got)
+	}
+
+	b.Reset()
+	if err := WriteJSON(&b, changes); err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+	{
+		"path": "a.go",
+		"kind": "M",
+		"added": 1,
+		"removed": 1
+	},
+	{
+		"path": "b.go",
+		"kind": "A",
+		"added": 1,
+		"removed": 0
+	}
+]
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteJSON() = %s, want %s", got, want)
+	}
+}
@@ -81,6 +81,9 @@ func run(opts Options, f func(*Plugin) error) error {
 		// to stderr.
 		gen.Error(err)
 	}
+	for _, w := range gen.warnings {
+		fmt.Fprintf(os.Stderr, "%s: warning: %v\n", filepath.Base(os.Args[0]), w)
+	}
 	resp := gen.Response()
 	out, err := proto.Marshal(resp)
 	if err != nil {
@@ -116,10 +119,13 @@ type Plugin struct {
 	messagesByName map[protoreflect.FullName]*Message
 	annotateCode   bool
 	pathType       pathType
+	naming         naming
 	module         string
+	moduleRoots    map[string]string // module path -> output directory
 	genFiles       []*GeneratedFile
 	opts           Options
 	err            error
+	warnings       []*DescriptorError
 }
 
 type Options struct {
@@ -196,8 +202,28 @@ func (opts Options) New(req *pluginpb.CodeGeneratorRequest) (*Plugin, error) {
 				gen.pathType = pathTypeImport
 			case "source_relative":
 				gen.pathType = pathTypeSourceRelative
+			case "module_root":
+				gen.pathType = pathTypeModuleRoot
+			default:
+				return nil, fmt.Errorf(`unknown path type %q: want "import", "source_relative", or "module_root"`, value)
+			}
+		case "module_root":
+			module, dir, ok := strings.Cut(value, "=")
+			if !ok || module == "" {
+				return nil, fmt.Errorf(`bad value for parameter %q: want "MODULE=DIR"`, param)
+			}
+			if gen.moduleRoots == nil {
+				gen.moduleRoots = make(map[string]string)
+			}
+			gen.moduleRoots[module] = dir
+		case "naming":
+			switch value {
+			case "v1.4":
+				gen.naming = namingV14
+			case "v1.3":
+				gen.naming = namingV13
 			default:
-				return nil, fmt.Errorf(`unknown path type %q: want "import" or "source_relative"`, value)
+				return nil, fmt.Errorf(`unknown naming %q: want "v1.4" or "v1.3"`, value)
 			}
 		case "annotate_code":
 			switch value {
@@ -232,6 +258,12 @@ func (opts Options) New(req *pluginpb.CodeGeneratorRequest) (*Plugin, error) {
 	if gen.module != "" && gen.pathType == pathTypeSourceRelative {
 		return nil, fmt.Errorf("cannot use module= with paths=source_relative")
 	}
+	if gen.module != "" && gen.pathType == pathTypeModuleRoot {
+		return nil, fmt.Errorf("cannot use module= with paths=module_root")
+	}
+	if (len(gen.moduleRoots) > 0) != (gen.pathType == pathTypeModuleRoot) {
+		return nil, fmt.Errorf("module_root= must be used with paths=module_root")
+	}
 
 	// Figure out the import path and package name for each file.
 	//
@@ -338,6 +370,9 @@ func (opts Options) New(req *pluginpb.CodeGeneratorRequest) (*Plugin, error) {
 			return nil, fmt.Errorf("no descriptor for generated file: %v", filename)
 		}
 		f.Generate = true
+		if _, _, ok := gen.moduleRoot(f.GoImportPath); gen.pathType == pathTypeModuleRoot && !ok {
+			return nil, fmt.Errorf("%v: Go import path %q is not within any module_root", filename, f.GoImportPath)
+		}
 	}
 
 	// Create fully-linked descriptors if new extensions were found
@@ -373,6 +408,63 @@ func (gen *Plugin) Error(err error) {
 	}
 }
 
+// Errorf records an error in code generation caused by the declaration d,
+// as with [Plugin.Error]. The recorded error is a [*DescriptorError],
+// which reports the location of d in its .proto source file.
+func (gen *Plugin) Errorf(d protoreflect.Descriptor, format string, args ...any) {
+	gen.Error(&DescriptorError{Desc: d, Err: fmt.Errorf(format, args...)})
+}
+
+// Warnf records a warning about the declaration d.
+// Warnings do not prevent the generator from producing output.
+// When the plugin is executed with [Options.Run], they are printed to
+// [os.Stderr] along with the location of d in its .proto source file.
+func (gen *Plugin) Warnf(d protoreflect.Descriptor, format string, args ...any) {
+	gen.warnings = append(gen.warnings, &DescriptorError{Desc: d, Err: fmt.Errorf(format, args...)})
+}
+
+// Warnings returns the warnings recorded with [Plugin.Warnf].
+func (gen *Plugin) Warnings() []*DescriptorError {
+	return gen.warnings
+}
+
+// A DescriptorError is an error or warning about a declaration
+// in a .proto source file.
+type DescriptorError struct {
+	Desc protoreflect.Descriptor // the offending declaration; may be nil
+	Err  error
+}
+
+// Error formats the error, prefixed with the location of the declaration.
+// The location is reported as "file.proto:line:column" if the file includes
+// source code info, or "file.proto: full.Name" otherwise.
+func (e *DescriptorError) Error() string {
+	if e.Desc == nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v: %v", descriptorPosition(e.Desc), e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DescriptorError) Unwrap() error {
+	return e.Err
+}
+
+func descriptorPosition(d protoreflect.Descriptor) string {
+	fd := d.ParentFile()
+	if fd == nil {
+		return string(d.FullName())
+	}
+	if d == protoreflect.Descriptor(fd) {
+		return fd.Path()
+	}
+	loc := fd.SourceLocations().ByDescriptor(d)
+	if loc.Path == nil {
+		return fmt.Sprintf("%v: %v", fd.Path(), d.FullName())
+	}
+	return fmt.Sprintf("%v:%d:%d", fd.Path(), loc.StartLine+1, loc.StartColumn+1)
+}
+
 // Response returns the generator output.
 func (gen *Plugin) Response() *pluginpb.CodeGeneratorResponse {
 	resp := &pluginpb.CodeGeneratorResponse{}
@@ -418,7 +510,7 @@ func (gen *Plugin) Response() *pluginpb.CodeGeneratorResponse {
 			Name:    proto.String(filename),
 			Content: proto.String(string(content)),
 		})
-		if gen.annotateCode && strings.HasSuffix(g.filename, ".go") {
+		if gen.annotateCode && !g.raw && strings.HasSuffix(g.filename, ".go") {
 			meta, err := g.metaFile(content)
 			if err != nil {
 				return &pluginpb.CodeGeneratorResponse{
@@ -457,7 +549,11 @@ type File struct {
 	// of "dir/foo". Appending ".pb.go" produces an output file of "dir/foo.pb.go".
 	GeneratedFilenamePrefix string
 
-	location Location
+	// OptionComments are the comments of the option statements of this file.
+	OptionComments []StatementComments
+
+	location  Location
+	commented []protoreflect.SourceLocation // source locations with comments
 }
 
 func newFile(gen *Plugin, p *descriptorpb.FileDescriptorProto, packageName GoPackageName, importPath GoImportPath) (*File, error) {
@@ -488,6 +584,16 @@ func newFile(gen *Plugin, p *descriptorpb.FileDescriptorProto, packageName GoPac
 	case pathTypeSourceRelative:
 		// If paths=source_relative, the output filename is derived from
 		// the input filename.
+	case pathTypeModuleRoot:
+		// If paths=module_root, the output filename is derived from the
+		// Go import path relative to the longest matching module path,
+		// placed in the directory of that module. Files that are not
+		// generated need not be in any module.
+		if rel, dir, ok := gen.moduleRoot(f.GoImportPath); ok {
+			prefix = path.Join(dir, rel, path.Base(prefix))
+		} else {
+			prefix = path.Join(string(f.GoImportPath), path.Base(prefix))
+		}
 	}
 	f.GoDescriptorIdent = GoIdent{
 		GoName:       "File_" + strs.GoSanitized(p.GetName()),
@@ -495,6 +601,14 @@ func newFile(gen *Plugin, p *descriptorpb.FileDescriptorProto, packageName GoPac
 	}
 	f.GeneratedFilenamePrefix = prefix
 
+	for i, locs := 0, desc.SourceLocations(); i < locs.Len(); i++ {
+		loc := locs.Get(i)
+		if loc.LeadingComments != "" || loc.TrailingComments != "" || len(loc.LeadingDetachedComments) > 0 {
+			f.commented = append(f.commented, loc)
+		}
+	}
+	f.OptionComments = f.statementComments(gen, f.location, genid.FileDescriptorProto_Options_field_number)
+
 	for i, eds := 0, desc.Enums(); i < eds.Len(); i++ {
 		f.Enums = append(f.Enums, newEnum(gen, f, nil, eds.Get(i)))
 	}
@@ -546,6 +660,9 @@ type Enum struct {
 
 	Location Location   // location of this enum
 	Comments CommentSet // comments associated with this enum
+
+	ReservedComments []StatementComments // comments of the reserved statements
+	OptionComments   []StatementComments // comments of the option statements
 }
 
 func newEnum(gen *Plugin, f *File, parent *Message, desc protoreflect.EnumDescriptor) *Enum {
@@ -560,6 +677,11 @@ func newEnum(gen *Plugin, f *File, parent *Message, desc protoreflect.EnumDescri
 		GoIdent:  newGoIdent(f, desc),
 		Location: loc,
 		Comments: makeCommentSet(gen, f.Desc.SourceLocations().ByDescriptor(desc)),
+
+		ReservedComments: f.statementComments(gen, loc,
+			genid.EnumDescriptorProto_ReservedRange_field_number,
+			genid.EnumDescriptorProto_ReservedName_field_number),
+		OptionComments: f.statementComments(gen, loc, genid.EnumDescriptorProto_Options_field_number),
 	}
 	gen.enumsByName[desc.FullName()] = enum
 	for i, vds := 0, enum.Desc.Values(); i < vds.Len(); i++ {
@@ -615,6 +737,11 @@ type Message struct {
 
 	Location Location   // location of this message
 	Comments CommentSet // comments associated with this message
+
+	ReservedComments []StatementComments // comments of the reserved statements
+	OptionComments   []StatementComments // comments of the option statements
+
+	goNames *NameSet
 }
 
 func newMessage(gen *Plugin, f *File, parent *Message, desc protoreflect.MessageDescriptor) *Message {
@@ -629,6 +756,11 @@ func newMessage(gen *Plugin, f *File, parent *Message, desc protoreflect.Message
 		GoIdent:  newGoIdent(f, desc),
 		Location: loc,
 		Comments: makeCommentSet(gen, f.Desc.SourceLocations().ByDescriptor(desc)),
+
+		ReservedComments: f.statementComments(gen, loc,
+			genid.DescriptorProto_ReservedRange_field_number,
+			genid.DescriptorProto_ReservedName_field_number),
+		OptionComments: f.statementComments(gen, loc, genid.DescriptorProto_Options_field_number),
 	}
 	gen.messagesByName[desc.FullName()] = message
 	for i, eds := 0, desc.Enums(); i < eds.Len(); i++ {
@@ -671,35 +803,29 @@ func newMessage(gen *Plugin, f *File, parent *Message, desc protoreflect.Message
 	// in which fields appear in the .proto source file can change the
 	// names of fields in generated code), and does not adapt well to
 	// adding new per-field methods such as setters.
-	usedNames := map[string]bool{
-		"Reset":               true,
-		"String":              true,
-		"ProtoMessage":        true,
-		"Marshal":             true,
-		"Unmarshal":           true,
-		"ExtensionRangeArray": true,
-		"ExtensionMap":        true,
-		"Descriptor":          true,
-	}
-	makeNameUnique := func(name string, hasGetter bool) string {
-		for usedNames[name] || (hasGetter && usedNames["Get"+name]) {
-			name += "_"
-		}
-		usedNames[name] = true
-		usedNames["Get"+name] = hasGetter
-		return name
-	}
+	names := NewMessageNameSet()
+	// The generator of v1.3 and earlier never releases a name once it is
+	// used, whereas the current rules release the getter name reserved for
+	// a field when a oneof is given the Go name that the getter is for.
+	names.keepGetters = gen.naming == namingV13
 	for _, field := range message.Fields {
-		field.GoName = makeNameUnique(field.GoName, true)
+		field.GoName = names.Unique(field.GoName, true)
 		field.GoIdent.GoName = message.GoIdent.GoName + "_" + field.GoName
 		if field.Oneof != nil && field.Oneof.Fields[0] == field {
 			// Make the name for a oneof unique as well. For historical reasons,
 			// this assumes that a getter method is not generated for oneofs.
 			// This is incorrect, but fixing it breaks existing code.
-			field.Oneof.GoName = makeNameUnique(field.Oneof.GoName, false)
+			field.Oneof.GoName = names.Unique(field.Oneof.GoName, false)
 			field.Oneof.GoIdent.GoName = message.GoIdent.GoName + "_" + field.Oneof.GoName
 		}
 	}
+	// The getters of oneofs and the ProtoReflect method are not considered
+	// when naming fields for historical reasons, but are still generated.
+	names.Reserve("ProtoReflect")
+	for _, oneof := range message.Oneofs {
+		names.Reserve("Get" + oneof.GoName)
+	}
+	message.goNames = names
 
 	// Oneof field name conflict resolution.
 	//
@@ -729,6 +855,88 @@ func newMessage(gen *Plugin, f *File, parent *Message, desc protoreflect.Message
 	return message
 }
 
+// GoNames returns the set of names of the fields and methods of the Go type
+// of the message that were reserved when naming its fields and oneofs,
+// as well as the getters of its oneofs and its ProtoReflect method.
+// Generators that add fields or methods to the type may use it to pick
+// names which do not conflict with those chosen by protogen.
+// The returned set is a copy, which the caller may modify.
+func (message *Message) GoNames() *NameSet {
+	return message.goNames.Clone()
+}
+
+// A NameSet is a set of Go identifiers in use within a scope, such as the
+// fields and methods of a generated message type. Conflicts are resolved
+// as for the fields of generated messages, by appending underscores to
+// a name until it is unused.
+//
+// The zero value is an empty set.
+type NameSet struct {
+	used        map[string]bool
+	keepGetters bool
+}
+
+// NewMessageNameSet returns a set containing the names of the methods which
+// protogen assumes may be attached to a generated message type when
+// naming its fields. Any change to this set is a potential incompatible
+// API change, since it may change the names of generated fields.
+func NewMessageNameSet() *NameSet {
+	s := new(NameSet)
+	s.Reserve(
+		"Reset",
+		"String",
+		"ProtoMessage",
+		"Marshal",
+		"Unmarshal",
+		"ExtensionRangeArray",
+		"ExtensionMap",
+		"Descriptor",
+	)
+	return s
+}
+
+// Reserve adds the names to the set.
+func (s *NameSet) Reserve(names ...string) {
+	if s.used == nil {
+		s.used = make(map[string]bool)
+	}
+	for _, name := range names {
+		s.used[name] = true
+	}
+}
+
+// Has reports whether name is in the set.
+func (s *NameSet) Has(name string) bool {
+	return s.used[name]
+}
+
+// Unique returns name, with underscores appended until neither it nor,
+// if hasGetter is set, its getter "Get"+name is in the set, and adds the
+// result to the set. The getter is added if hasGetter is set, and is
+// otherwise removed from the set so that it may be used by a later name.
+func (s *NameSet) Unique(name string, hasGetter bool) string {
+	for s.Has(name) || (hasGetter && s.Has("Get"+name)) {
+		name += "_"
+	}
+	s.Reserve(name)
+	switch {
+	case hasGetter:
+		s.Reserve("Get" + name)
+	case !s.keepGetters:
+		delete(s.used, "Get"+name)
+	}
+	return name
+}
+
+// Clone returns a copy of the set.
+func (s *NameSet) Clone() *NameSet {
+	c := &NameSet{keepGetters: s.keepGetters}
+	for name := range s.used {
+		c.Reserve(name)
+	}
+	return c
+}
+
 func (message *Message) resolveDependencies(gen *Plugin) error {
 	for _, field := range message.Fields {
 		if err := field.resolveDependencies(gen); err != nil {
@@ -850,6 +1058,8 @@ type Oneof struct {
 
 	Location Location   // location of this oneof
 	Comments CommentSet // comments associated with this oneof
+
+	OptionComments []StatementComments // comments of the option statements
 }
 
 func newOneof(gen *Plugin, f *File, message *Message, desc protoreflect.OneofDescriptor) *Oneof {
@@ -866,6 +1076,8 @@ func newOneof(gen *Plugin, f *File, message *Message, desc protoreflect.OneofDes
 		},
 		Location: loc,
 		Comments: makeCommentSet(gen, f.Desc.SourceLocations().ByDescriptor(desc)),
+
+		OptionComments: f.statementComments(gen, loc, genid.OneofDescriptorProto_Options_field_number),
 	}
 }
 
@@ -882,6 +1094,8 @@ type Service struct {
 
 	Location Location   // location of this service
 	Comments CommentSet // comments associated with this service
+
+	OptionComments []StatementComments // comments of the option statements
 }
 
 func newService(gen *Plugin, f *File, desc protoreflect.ServiceDescriptor) *Service {
@@ -891,6 +1105,8 @@ func newService(gen *Plugin, f *File, desc protoreflect.ServiceDescriptor) *Serv
 		GoName:   strs.GoCamelCase(string(desc.Name())),
 		Location: loc,
 		Comments: makeCommentSet(gen, f.Desc.SourceLocations().ByDescriptor(desc)),
+
+		OptionComments: f.statementComments(gen, loc, genid.ServiceDescriptorProto_Options_field_number),
 	}
 	for i, mds := 0, desc.Methods(); i < mds.Len(); i++ {
 		service.Methods = append(service.Methods, newMethod(gen, f, service, mds.Get(i)))
@@ -911,6 +1127,8 @@ type Method struct {
 
 	Location Location   // location of this method
 	Comments CommentSet // comments associated with this method
+
+	OptionComments []StatementComments // comments of the option statements
 }
 
 func newMethod(gen *Plugin, f *File, service *Service, desc protoreflect.MethodDescriptor) *Method {
@@ -921,6 +1139,8 @@ func newMethod(gen *Plugin, f *File, service *Service, desc protoreflect.MethodD
 		Parent:   service,
 		Location: loc,
 		Comments: makeCommentSet(gen, f.Desc.SourceLocations().ByDescriptor(desc)),
+
+		OptionComments: f.statementComments(gen, loc, genid.MethodDescriptorProto_Options_field_number),
 	}
 	return method
 }
@@ -949,6 +1169,7 @@ func (method *Method) resolveDependencies(gen *Plugin) error {
 type GeneratedFile struct {
 	gen                  *Plugin
 	skip                 bool
+	raw                  bool
 	filename             string
 	goImportPath         GoImportPath
 	buf                  bytes.Buffer
@@ -982,6 +1203,19 @@ func (gen *Plugin) NewGeneratedFile(filename string, goImportPath GoImportPath)
 	return g
 }
 
+// NewRawGeneratedFile creates a new generated file with the given filename,
+// such as a documentation or JSON file produced alongside the Go output.
+//
+// The content of a raw file is emitted exactly as written, even if the
+// filename has a ".go" suffix: it is not formatted, imports are not added
+// for the identifiers printed by [GeneratedFile.P], and no annotations are
+// produced for it.
+func (gen *Plugin) NewRawGeneratedFile(filename string) *GeneratedFile {
+	g := gen.NewGeneratedFile(filename, "")
+	g.raw = true
+	return g
+}
+
 // P prints a line to the generated output. It converts each parameter to a
 // string following the same rules as [fmt.Print]. It never inserts spaces
 // between parameters.
@@ -1090,7 +1324,7 @@ func (g *GeneratedFile) AnnotateSymbol(symbol string, info Annotation) {
 
 // Content returns the contents of the generated file.
 func (g *GeneratedFile) Content() ([]byte, error) {
-	if !strings.HasSuffix(g.filename, ".go") {
+	if g.raw || !strings.HasSuffix(g.filename, ".go") {
 		return g.buf.Bytes(), nil
 	}
 
@@ -1301,11 +1535,41 @@ func cleanPackageName(name string) GoPackageName {
 	return GoPackageName(strs.GoSanitized(name))
 }
 
+// moduleRoot returns the path of importPath relative to the longest
+// module path of the module_root parameters containing it,
+// and the output directory of that module.
+func (gen *Plugin) moduleRoot(importPath GoImportPath) (rel, dir string, ok bool) {
+	var module string
+	for m, d := range gen.moduleRoots {
+		if len(m) <= len(module) {
+			continue
+		}
+		if r, found := strings.CutPrefix(string(importPath), m); found && (r == "" || r[0] == '/') {
+			module, rel, dir, ok = m, r, d, true
+		}
+	}
+	return rel, dir, ok
+}
+
 type pathType int
 
 const (
 	pathTypeImport pathType = iota
 	pathTypeSourceRelative
+	pathTypeModuleRoot
+)
+
+// naming is the value of the naming parameter, which selects the rules used
+// to resolve conflicts between the Go names of fields and oneofs.
+//
+// With naming=v1.3, conflicts are resolved as by the protoc-gen-go generator
+// of github.com/golang/protobuf v1.3 and earlier, so that regenerating old
+// .proto files does not change the names of their fields.
+type naming int
+
+const (
+	namingV14 naming = iota
+	namingV13
 )
 
 // A Location is a location in a .proto source file.
@@ -1347,6 +1611,43 @@ func makeCommentSet(gen *Plugin, loc protoreflect.SourceLocation) CommentSet {
 	}
 }
 
+// StatementComments are the comments of a statement that does not declare
+// a descriptor of its own, such as a reserved or option statement.
+type StatementComments struct {
+	// Location is the location of the statement. Its path ends with the
+	// field number of the statement within the enclosing descriptor,
+	// such as the number of the options field, and for an option statement
+	// it may continue with the path to the option that is set.
+	Location Location
+	Comments CommentSet
+}
+
+// statementComments returns the comments of the statements within the
+// declaration at loc that set the fields with the given numbers.
+func (f *File) statementComments(gen *Plugin, loc Location, nums ...protoreflect.FieldNumber) []StatementComments {
+	var cs []StatementComments
+Loop:
+	for _, l := range f.commented {
+		if len(l.Path) <= len(loc.Path) {
+			continue
+		}
+		for i, n := range loc.Path {
+			if l.Path[i] != n {
+				continue Loop
+			}
+		}
+		for _, num := range nums {
+			if l.Path[len(loc.Path)] == int32(num) {
+				cs = append(cs, StatementComments{
+					Location: Location{SourceFile: loc.SourceFile, Path: l.Path},
+					Comments: makeCommentSet(gen, l),
+				})
+			}
+		}
+	}
+	return cs
+}
+
 // Comments is a comments string as provided by protoc.
 type Comments string
 
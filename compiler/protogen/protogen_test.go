@@ -162,6 +162,42 @@ func TestPackageNamesAndPaths(t *testing.T) {
 			wantImportPath:  "golang.org/x/bar",
 			wantFilename:    "golang.org/x/bar/filename",
 		},
+		{
+			desc:            "paths=module_root places files in the directory of the module",
+			parameter:       "paths=module_root,module_root=golang.org/x=x",
+			goPackageOption: "golang.org/x/foo",
+			generate:        true,
+			wantPackageName: "foo",
+			wantImportPath:  "golang.org/x/foo",
+			wantFilename:    "x/foo/filename",
+		},
+		{
+			desc:            "paths=module_root uses the longest matching module",
+			parameter:       "paths=module_root,module_root=golang.org/x=x,module_root=golang.org/x/foo=services/foo",
+			goPackageOption: "golang.org/x/foo/bar",
+			generate:        true,
+			wantPackageName: "bar",
+			wantImportPath:  "golang.org/x/foo/bar",
+			wantFilename:    "services/foo/bar/filename",
+		},
+		{
+			desc:            "paths=module_root does not match partial path elements",
+			parameter:       "paths=module_root,module_root=golang.org/x=x,module_root=golang.org/x/fo=services/fo",
+			goPackageOption: "golang.org/x/foo",
+			generate:        true,
+			wantPackageName: "foo",
+			wantImportPath:  "golang.org/x/foo",
+			wantFilename:    "x/foo/filename",
+		},
+		{
+			desc:            "paths=module_root uses the import path for files not in a module",
+			parameter:       "paths=module_root,module_root=golang.org/y=y",
+			goPackageOption: "golang.org/x/foo",
+			generate:        false,
+			wantPackageName: "foo",
+			wantImportPath:  "golang.org/x/foo",
+			wantFilename:    "golang.org/x/foo/filename",
+		},
 		{
 			desc:            "module option implies paths=import",
 			parameter:       "module=golang.org/x,Mdir/filename.proto=golang.org/x/foo",
@@ -416,3 +452,298 @@ func TestAnnotations(t *testing.T) {
 		t.Fatalf("GeneratedCodeInfo mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestDiagnostics(t *testing.T) {
+	gen, err := Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"a.proto", "b.proto"},
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("a.proto"),
+				Package: proto.String("pkg"),
+				Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/a")},
+				MessageType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("M"),
+					Field: []*descriptorpb.FieldDescriptorProto{{
+						Name:     proto.String("f"),
+						Number:   proto.Int32(1),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+						JsonName: proto.String("f"),
+					}},
+				}},
+				SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+					Location: []*descriptorpb.SourceCodeInfo_Location{
+						{Path: []int32{4, 0}, Span: []int32{2, 0, 4, 1}},
+						{Path: []int32{4, 0, 2, 0}, Span: []int32{3, 2, 14}},
+					},
+				},
+			},
+			{
+				Name:        proto.String("b.proto"),
+				Package:     proto.String("pkg"),
+				Options:     &descriptorpb.FileOptions{GoPackage: proto.String("example.com/b")},
+				MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("N")}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := gen.FilesByPath["a.proto"], gen.FilesByPath["b.proto"]
+
+	gen.Warnf(a.Messages[0].Fields[0].Desc, "field %q is unused", "f")
+	gen.Warnf(b.Messages[0].Desc, "message is empty")
+	gen.Warnf(b.Desc, "no services")
+	var got []string
+	for _, w := range gen.Warnings() {
+		got = append(got, w.Error())
+	}
+	want := []string{
+		`a.proto:4:3: field "f" is unused`,
+		`b.proto: pkg.N: message is empty`,
+		`b.proto: no services`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Warnings() mismatch (-want +got):\n%s", diff)
+	}
+
+	gen.Errorf(a.Messages[0].Desc, "unsupported message")
+	gen.Errorf(b.Messages[0].Desc, "ignored, since an error was already recorded")
+	resp := gen.Response()
+	if got, want := resp.GetError(), "a.proto:3:1: unsupported message"; got != want {
+		t.Errorf("Response().Error = %q, want %q", got, want)
+	}
+}
+
+func TestModuleRootErrors(t *testing.T) {
+	for _, param := range []string{
+		"paths=module_root",
+		"module_root=golang.org/x=x",
+		"paths=source_relative,module_root=golang.org/x=x",
+		"paths=module_root,module_root=golang.org/x=x,module=golang.org/x",
+		"paths=module_root,module_root=golang.org/x",
+		"paths=module_root,module_root==x",
+		"paths=module_root,module_root=golang.org/y=y",
+	} {
+		_, err := Options{}.New(&pluginpb.CodeGeneratorRequest{
+			Parameter:      proto.String(param),
+			FileToGenerate: []string{"a.proto"},
+			ProtoFile: []*descriptorpb.FileDescriptorProto{{
+				Name:    proto.String("a.proto"),
+				Options: &descriptorpb.FileOptions{GoPackage: proto.String("golang.org/x/a")},
+			}},
+		})
+		if err == nil {
+			t.Errorf("New(%v) succeeded, want error", param)
+		}
+	}
+}
+
+func TestNaming(t *testing.T) {
+	field := func(name string, number int32, oneof *int32) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:       proto.String(name),
+			Number:     proto.Int32(number),
+			Label:      descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:       descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+			OneofIndex: oneof,
+		}
+	}
+	// The oneof GetX reuses the name of the field get_x, whose getter name
+	// is released by the oneof x unless the v1.3 naming rules are used.
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("a.proto"),
+		Package: proto.String("pkg"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/a")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("M"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("get_x", 1, nil),
+				field("y", 2, proto.Int32(0)),
+				field("z", 3, proto.Int32(1)),
+			},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{
+				{Name: proto.String("x")},
+				{Name: proto.String("GetX")},
+			},
+		}},
+	}
+	for _, tt := range []struct {
+		param string
+		want  []string
+	}{
+		{"", []string{"GetX", "X", "GetX"}},
+		{"naming=v1.4", []string{"GetX", "X", "GetX"}},
+		{"naming=v1.3", []string{"GetX", "X", "GetX_"}},
+	} {
+		gen, err := Options{}.New(&pluginpb.CodeGeneratorRequest{
+			Parameter: proto.String(tt.param),
+			ProtoFile: []*descriptorpb.FileDescriptorProto{file},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := gen.FilesByPath["a.proto"].Messages[0]
+		got := []string{m.Fields[0].GoName, m.Oneofs[0].GoName, m.Oneofs[1].GoName}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("names with parameter %q mismatch (-want +got):\n%s", tt.param, diff)
+		}
+	}
+
+	if _, err := (Options{}).New(&pluginpb.CodeGeneratorRequest{Parameter: proto.String("naming=v2")}); err == nil {
+		t.Errorf("New(naming=v2) succeeded, want error")
+	}
+}
+
+func TestNameSet(t *testing.T) {
+	names := NewMessageNameSet()
+	for _, tt := range []struct {
+		name      string
+		hasGetter bool
+		want      string
+	}{
+		{"Descriptor", true, "Descriptor_"},
+		{"Name", true, "Name"},
+		{"GetName", true, "GetName_"},
+		{"Name", false, "Name_"},
+		{"Value", false, "Value"},
+		{"GetValue", true, "GetValue"},
+	} {
+		if got := names.Unique(tt.name, tt.hasGetter); got != tt.want {
+			t.Errorf("Unique(%q, %v) = %q, want %q", tt.name, tt.hasGetter, got, tt.want)
+		}
+	}
+
+	var zero NameSet
+	if got := zero.Unique("Reset", true); got != "Reset" {
+		t.Errorf("zero NameSet: Unique(Reset) = %q, want Reset", got)
+	}
+
+	c := names.Clone()
+	c.Reserve("Extra")
+	if !c.Has("Extra") || names.Has("Extra") {
+		t.Errorf("Reserve on clone: Has(Extra) = %v, original Has(Extra) = %v; want true, false", c.Has("Extra"), names.Has("Extra"))
+	}
+}
+
+func TestMessageGoNames(t *testing.T) {
+	gen, err := Options{}.New(&pluginpb.CodeGeneratorRequest{
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("a.proto"),
+			Package: proto.String("pkg"),
+			Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/a")},
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("M"),
+				Field: []*descriptorpb.FieldDescriptorProto{{
+					Name:   proto.String("reset"),
+					Number: proto.Int32(1),
+					Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:   descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+				}},
+			}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := gen.FilesByPath["a.proto"].Messages[0]
+	if got, want := m.Fields[0].GoName, "Reset_"; got != want {
+		t.Errorf("field GoName = %q, want %q", got, want)
+	}
+	names := m.GoNames()
+	for _, name := range []string{"Reset", "Reset_", "GetReset_", "ProtoMessage", "ProtoReflect"} {
+		if !names.Has(name) {
+			t.Errorf("GoNames().Has(%q) = false, want true", name)
+		}
+	}
+	// A secondary generator picks the name protoc-gen-go would have picked.
+	if got, want := names.Unique("Reset", true), "Reset__"; got != want {
+		t.Errorf("GoNames().Unique(Reset) = %q, want %q", got, want)
+	}
+	if m.GoNames().Has("Reset__") {
+		t.Errorf("GoNames returned a set shared with the message")
+	}
+}
+
+func TestStatementComments(t *testing.T) {
+	gen, err := Options{}.New(&pluginpb.CodeGeneratorRequest{
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("a.proto"),
+			Package: proto.String("pkg"),
+			Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/a")},
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name:          proto.String("M"),
+				ReservedRange: []*descriptorpb.DescriptorProto_ReservedRange{{Start: proto.Int32(1), End: proto.Int32(2)}},
+				ReservedName:  []string{"x"},
+				Options:       &descriptorpb.MessageOptions{Deprecated: proto.Bool(true)},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name:    proto.String("N"),
+					Options: &descriptorpb.MessageOptions{Deprecated: proto.Bool(true)},
+				}},
+			}},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+				Location: []*descriptorpb.SourceCodeInfo_Location{
+					{Path: []int32{8}, Span: []int32{0, 0, 10}},
+					{Path: []int32{8, 11}, Span: []int32{0, 0, 40}, LeadingComments: proto.String(" go package\n")},
+					{Path: []int32{4, 0}, Span: []int32{2, 0, 9, 1}, LeadingComments: proto.String(" message\n")},
+					{Path: []int32{4, 0, 9}, Span: []int32{3, 2, 13}, LeadingComments: proto.String(" numbers\n")},
+					{Path: []int32{4, 0, 9, 0}, Span: []int32{3, 11, 12}},
+					{Path: []int32{4, 0, 10}, Span: []int32{4, 2, 15}, TrailingComments: proto.String(" names\n")},
+					{Path: []int32{4, 0, 7, 3}, Span: []int32{5, 2, 27}, LeadingDetachedComments: []string{" detached\n"}},
+					{Path: []int32{4, 0, 3, 0, 7, 3}, Span: []int32{7, 4, 29}, LeadingComments: proto.String(" nested\n")},
+				},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := gen.FilesByPath["a.proto"]
+	m := f.Messages[0]
+	loc := func(path ...int32) Location {
+		return Location{SourceFile: "a.proto", Path: path}
+	}
+	for _, tt := range []struct {
+		name      string
+		got, want []StatementComments
+	}{
+		{"File.OptionComments", f.OptionComments, []StatementComments{
+			{loc(8, 11), CommentSet{Leading: " go package\n"}},
+		}},
+		{"Message.ReservedComments", m.ReservedComments, []StatementComments{
+			{loc(4, 0, 9), CommentSet{Leading: " numbers\n"}},
+			{loc(4, 0, 10), CommentSet{Trailing: " names\n"}},
+		}},
+		{"Message.OptionComments", m.OptionComments, []StatementComments{
+			{loc(4, 0, 7, 3), CommentSet{LeadingDetached: []Comments{" detached\n"}}},
+		}},
+		{"nested Message.OptionComments", m.Messages[0].OptionComments, []StatementComments{
+			{loc(4, 0, 3, 0, 7, 3), CommentSet{Leading: " nested\n"}},
+		}},
+	} {
+		if diff := cmp.Diff(tt.want, tt.got); diff != "" {
+			t.Errorf("%s mismatch (-want +got):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestRawGeneratedFile(t *testing.T) {
+	gen, err := Options{}.New(&pluginpb.CodeGeneratorRequest{
+		Parameter: proto.String("annotate_code"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const content = "package   foo\nvar x = 1 {\n"
+	g := gen.NewRawGeneratedFile("foo.go")
+	g.P("package   foo")
+	g.P("var x = ", 1, " {")
+	g.Annotate("x", Location{SourceFile: "foo.proto"})
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatalf("Response().Error = %q", resp.GetError())
+	}
+	if len(resp.File) != 1 || resp.File[0].GetName() != "foo.go" || resp.File[0].GetContent() != content {
+		t.Errorf("Response().File = %v, want a single unformatted foo.go file", resp.File)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protogentest tests protoc plugins written with package protogen
+// by comparing their output with golden files.
+//
+// The input files are provided as compiled descriptors rather than
+// .proto sources, so that the output of a plugin may be checked without
+// invoking protoc and is independent of the version of protoc in use.
+package protogentest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// NewRequest returns a request to generate code for the given files
+// with the given generator parameter (e.g., "paths=source_relative").
+// The request includes the files and all of their transitive dependencies,
+// each listed after its dependencies as protoc does.
+func NewRequest(parameter string, files ...protoreflect.FileDescriptor) *pluginpb.CodeGeneratorRequest {
+	req := &pluginpb.CodeGeneratorRequest{}
+	if parameter != "" {
+		req.Parameter = proto.String(parameter)
+	}
+	seen := make(map[string]bool)
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		req.ProtoFile = append(req.ProtoFile, protodesc.ToFileDescriptorProto(fd))
+	}
+	for _, fd := range files {
+		add(fd)
+		req.FileToGenerate = append(req.FileToGenerate, fd.Path())
+	}
+	return req
+}
+
+// Run executes the plugin function f on the request as [protogen.Options.Run]
+// does, and returns the response.
+func Run(opts protogen.Options, req *pluginpb.CodeGeneratorRequest, f func(*protogen.Plugin) error) *pluginpb.CodeGeneratorResponse {
+	gen, err := opts.New(req)
+	if err != nil {
+		return &pluginpb.CodeGeneratorResponse{Error: proto.String(err.Error())}
+	}
+	if err := f(gen); err != nil {
+		// Errors from the plugin function are reported by setting the
+		// error field in the CodeGeneratorResponse.
+		gen.Error(err)
+	}
+	return gen.Response()
+}
+
+// Golden compares the output of a plugin with golden files.
+type Golden struct {
+	// Options are the options used to create the plugin.
+	Options protogen.Options
+
+	// Parameter is the generator parameter passed to the plugin.
+	Parameter string
+
+	// Dir is the directory containing the golden files,
+	// each stored under the name of the generated file.
+	// If empty, this defaults to "testdata".
+	Dir string
+
+	// Update specifies that the golden files are to be written
+	// with the output of the plugin rather than compared with it.
+	// Tests typically set this from a command-line flag.
+	Update bool
+}
+
+// Test runs the plugin function f to generate code for the given files,
+// and reports an error if the plugin fails or if its output differs
+// from the golden files.
+func (g Golden) Test(t testing.TB, f func(*protogen.Plugin) error, files ...protoreflect.FileDescriptor) {
+	t.Helper()
+	dir := g.Dir
+	if dir == "" {
+		dir = "testdata"
+	}
+	resp := Run(g.Options, NewRequest(g.Parameter, files...), f)
+	if resp.Error != nil {
+		t.Fatalf("plugin error: %v", resp.GetError())
+	}
+	for _, file := range resp.File {
+		if file.GetInsertionPoint() != "" {
+			t.Errorf("%v: insertion points are not supported", file.GetName())
+			continue
+		}
+		path := filepath.Join(dir, filepath.FromSlash(file.GetName()))
+		if g.Update {
+			if err := os.MkdirAll(filepath.Dir(path), 0775); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(file.GetContent()), 0664); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("%v: missing golden file: %v", file.GetName(), err)
+			continue
+		}
+		if diff := cmp.Diff(strings.Split(string(want), "\n"), strings.Split(file.GetContent(), "\n")); diff != "" {
+			t.Errorf("%v: output differs from golden file %v (-want +got):\n%s", file.GetName(), path, diff)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protogentest_test
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/compiler/protogen/protogentest"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test3"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// generateNames generates a function listing the names of the messages
+// declared in each file.
+func generateNames(gen *protogen.Plugin) error {
+	for _, f := range gen.Files {
+		if !f.Generate {
+			continue
+		}
+		g := gen.NewGeneratedFile(f.GeneratedFilenamePrefix+"_names.pb.go", f.GoImportPath)
+		g.P("// Copyright 2024 The Go Authors. All rights reserved.")
+		g.P("// Use of this source code is governed by a BSD-style")
+		g.P("// license that can be found in the LICENSE file.")
+		g.P()
+		g.P("// Code generated by protogentest_test. DO NOT EDIT.")
+		g.P()
+		g.P("package ", f.GoPackageName)
+		g.P()
+		g.P("func MessageNames() []string {")
+		g.P("return []string{")
+		for _, m := range f.Messages {
+			g.P(`"`, m.Desc.FullName(), `",`)
+		}
+		g.P("}")
+		g.P("}")
+	}
+	return nil
+}
+
+func TestGolden(t *testing.T) {
+	protogentest.Golden{
+		Parameter: "paths=source_relative",
+		Update:    *update,
+	}.Test(t, generateNames, testpb.File_internal_testprotos_test3_test_proto)
+}
+
+func TestNewRequest(t *testing.T) {
+	req := protogentest.NewRequest("", testpb.File_internal_testprotos_test3_test_proto)
+	if got, want := req.GetFileToGenerate(), []string{"internal/testprotos/test3/test.proto"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("FileToGenerate = %v, want %v", got, want)
+	}
+	// Each file is listed after its dependencies.
+	seen := make(map[string]bool)
+	for _, fd := range req.GetProtoFile() {
+		for _, dep := range fd.GetDependency() {
+			if !seen[dep] {
+				t.Errorf("%v is listed before its dependency %v", fd.GetName(), dep)
+			}
+		}
+		seen[fd.GetName()] = true
+	}
+	if !seen["internal/testprotos/test3/test_import.proto"] {
+		t.Errorf("request is missing transitive dependency internal/testprotos/test/test_import.proto")
+	}
+}
+
+func TestRunError(t *testing.T) {
+	req := protogentest.NewRequest("", testpb.File_internal_testprotos_test3_test_proto)
+	resp := protogentest.Run(protogen.Options{}, req, func(*protogen.Plugin) error {
+		return errors.New("failure")
+	})
+	if got, want := resp.GetError(), "failure"; got != want {
+		t.Errorf("Run() error = %q, want %q", got, want)
+	}
+	if len(resp.GetFile()) != 0 {
+		t.Errorf("Run() generated files despite error")
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/internal/errors"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenInt
+	tokenFloat
+	tokenString
+	tokenSymbol
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of file"
+	case tokenIdent:
+		return "identifier"
+	case tokenInt:
+		return "integer"
+	case tokenFloat:
+		return "float"
+	case tokenString:
+		return "string"
+	default:
+		return "symbol"
+	}
+}
+
+// position is a zero-indexed location in the source file.
+type position struct {
+	line, col int
+}
+
+// comment is a block of adjacent comments.
+type comment struct {
+	text       string
+	start, end position // end is exclusive
+	isLine     bool     // whether the block consists of // comments
+}
+
+// token is a lexical token together with the comments that precede it.
+type token struct {
+	kind       tokenKind
+	text       string // raw text; for strings, the unquoted value
+	start, end position
+	off, eoff  int       // byte offsets of the start and end of the token
+	comments   []comment // comments between the previous token and this one
+}
+
+// lexer tokenizes .proto source.
+type lexer struct {
+	path    string
+	src     string
+	off     int
+	pos     position
+	prevEnd position // end of the previously returned token
+}
+
+func (l *lexer) errorf(pos position, f string, x ...any) error {
+	return errors.New("%s:%d:%d: %s", l.path, pos.line+1, pos.col+1, fmt.Sprintf(f, x...))
+}
+
+func (l *lexer) peekByte(i int) byte {
+	if l.off+i < len(l.src) {
+		return l.src[l.off+i]
+	}
+	return 0
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.off < len(l.src); i++ {
+		if l.src[l.off] == '\n' {
+			l.pos.line++
+			l.pos.col = 0
+		} else {
+			l.pos.col++
+		}
+		l.off++
+	}
+}
+
+// next returns the next token.
+func (l *lexer) next() (token, error) {
+	var comments []comment
+	for {
+		// Skip whitespace.
+		for l.off < len(l.src) && strings.IndexByte(" \t\r\n\v\f", l.src[l.off]) >= 0 {
+			l.advance(1)
+		}
+		switch {
+		case strings.HasPrefix(l.src[l.off:], "//"):
+			start := l.pos
+			n := strings.IndexByte(l.src[l.off:], '\n')
+			if n < 0 {
+				n = len(l.src) - l.off
+			}
+			text := l.src[l.off+2 : l.off+n]
+			l.advance(n)
+			c := comment{text: text + "\n", start: start, end: l.pos, isLine: true}
+			// Adjacent line comments form a single block, unless the
+			// block started on the same line as the previous token.
+			if k := len(comments) - 1; k >= 0 && comments[k].isLine && comments[k].end.line+1 == start.line &&
+				(k > 0 || l.prevEnd == (position{}) || comments[k].start.line != l.prevEnd.line) {
+				comments[k].text += c.text
+				comments[k].end = c.end
+			} else {
+				comments = append(comments, c)
+			}
+			continue
+		case strings.HasPrefix(l.src[l.off:], "/*"):
+			start := l.pos
+			n := strings.Index(l.src[l.off+2:], "*/")
+			if n < 0 {
+				return token{}, l.errorf(start, "unterminated block comment")
+			}
+			text := l.src[l.off+2 : l.off+2+n]
+			l.advance(n + 4)
+			comments = append(comments, comment{text: blockCommentText(text), start: start, end: l.pos})
+			continue
+		}
+		break
+	}
+
+	tok := token{start: l.pos, off: l.off, comments: comments}
+	if l.off >= len(l.src) {
+		tok.kind = tokenEOF
+		tok.end, tok.eoff = l.pos, l.off
+		l.prevEnd = tok.end
+		return tok, nil
+	}
+
+	c := l.src[l.off]
+	switch {
+	case isLetter(c):
+		n := 1
+		for l.off+n < len(l.src) && (isLetter(l.src[l.off+n]) || isDigit(l.src[l.off+n])) {
+			n++
+		}
+		tok.kind, tok.text = tokenIdent, l.src[l.off:l.off+n]
+		l.advance(n)
+	case isDigit(c) || (c == '.' && isDigit(l.peekByte(1))):
+		n, isFloat := scanNumber(l.src[l.off:])
+		tok.kind, tok.text = tokenInt, l.src[l.off:l.off+n]
+		if isFloat {
+			tok.kind = tokenFloat
+		}
+		l.advance(n)
+		if l.off < len(l.src) && (isLetter(l.src[l.off]) || isDigit(l.src[l.off])) {
+			return token{}, l.errorf(tok.start, "invalid number %q", tok.text+string(l.src[l.off]))
+		}
+	case c == '"' || c == '\'':
+		s, n, err := unquote(l.src[l.off:])
+		if err != nil {
+			return token{}, l.errorf(tok.start, "%v", err)
+		}
+		tok.kind, tok.text = tokenString, s
+		l.advance(n)
+	default:
+		r, n := utf8.DecodeRuneInString(l.src[l.off:])
+		if strings.IndexRune("{}[]()<>;,.=-+:/", r) < 0 {
+			return token{}, l.errorf(tok.start, "unexpected character %q", r)
+		}
+		tok.kind, tok.text = tokenSymbol, l.src[l.off:l.off+n]
+		l.advance(n)
+	}
+	tok.end, tok.eoff = l.pos, l.off
+	l.prevEnd = tok.end
+	return tok, nil
+}
+
+// blockCommentText normalizes the content of a /* */ comment by stripping
+// the leading whitespace and asterisk from each line.
+func blockCommentText(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if i > 0 {
+			line = strings.TrimLeft(line, " \t")
+			if strings.HasPrefix(line, "*") {
+				line = line[1:]
+			}
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+// scanNumber returns the length of the numeric literal at the start of s
+// and whether it is a floating-point literal.
+func scanNumber(s string) (n int, isFloat bool) {
+	if len(s) > 1 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		n = 2
+		for n < len(s) && isHexDigit(s[n]) {
+			n++
+		}
+		return n, false
+	}
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	if n < len(s) && s[n] == '.' {
+		isFloat = true
+		n++
+		for n < len(s) && isDigit(s[n]) {
+			n++
+		}
+	}
+	if n < len(s) && (s[n] == 'e' || s[n] == 'E') {
+		m := n + 1
+		if m < len(s) && (s[m] == '+' || s[m] == '-') {
+			m++
+		}
+		if m < len(s) && isDigit(s[m]) {
+			isFloat = true
+			for m < len(s) && isDigit(s[m]) {
+				m++
+			}
+			n = m
+		}
+	}
+	return n, isFloat
+}
+
+// unquote parses the quoted string literal at the start of s,
+// returning the unescaped value and the length of the literal.
+func unquote(s string) (string, int, error) {
+	quote := s[0]
+	var b []byte
+	i := 1
+	for {
+		if i >= len(s) || s[i] == '\n' {
+			return "", 0, fmt.Errorf("unterminated string literal")
+		}
+		c := s[i]
+		switch {
+		case c == quote:
+			return string(b), i + 1, nil
+		case c == 0:
+			return "", 0, fmt.Errorf("invalid null byte in string literal")
+		case c != '\\':
+			b = append(b, c)
+			i++
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return "", 0, fmt.Errorf("unterminated string literal")
+		}
+		c = s[i]
+		i++
+		switch c {
+		case 'a':
+			b = append(b, '\a')
+		case 'b':
+			b = append(b, '\b')
+		case 'f':
+			b = append(b, '\f')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'v':
+			b = append(b, '\v')
+		case '?':
+			b = append(b, '?')
+		case '\\', '\'', '"':
+			b = append(b, c)
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			j := i - 1
+			for i < len(s) && i-j < 3 && '0' <= s[i] && s[i] <= '7' {
+				i++
+			}
+			v, err := strconv.ParseUint(s[j:i], 8, 9)
+			if err != nil || v > 0xff {
+				return "", 0, fmt.Errorf("invalid octal escape %q", s[j-1:i])
+			}
+			b = append(b, byte(v))
+		case 'x', 'X':
+			j := i
+			for i < len(s) && i-j < 2 && isHexDigit(s[i]) {
+				i++
+			}
+			if i == j {
+				return "", 0, fmt.Errorf("invalid hex escape")
+			}
+			v, _ := strconv.ParseUint(s[j:i], 16, 8)
+			b = append(b, byte(v))
+		case 'u', 'U':
+			size := 4
+			if c == 'U' {
+				size = 8
+			}
+			if i+size > len(s) {
+				return "", 0, fmt.Errorf("invalid unicode escape")
+			}
+			v, err := strconv.ParseUint(s[i:i+size], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(v)) {
+				return "", 0, fmt.Errorf("invalid unicode escape %q", s[i-2:i+size])
+			}
+			b = utf8.AppendRune(b, rune(v))
+			i += size
+		default:
+			return "", 0, fmt.Errorf("invalid escape sequence %q", s[i-2:i])
+		}
+	}
+}
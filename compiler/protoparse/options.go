@@ -0,0 +1,286 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoparse
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// parseOptionStatement parses an "option" statement into opts.
+func (p *parser) parseOptionStatement(opts proto.Message) {
+	p.expectKeyword("option")
+	p.parseOption(opts)
+	p.expectSymbol(";")
+}
+
+// parseOption parses a single "name = value" option into opts.
+// Options that only reference fields of the options message itself are
+// interpreted immediately, while options referencing extensions are
+// recorded as uninterpreted options to be resolved when linking.
+func (p *parser) parseOption(opts proto.Message) {
+	tok := p.tok
+	uo := new(descriptorpb.UninterpretedOption)
+	isExt := false
+	for {
+		if p.consumeSymbol("(") {
+			name := p.expectTypeName()
+			p.expectSymbol(")")
+			uo.Name = append(uo.Name, &descriptorpb.UninterpretedOption_NamePart{
+				NamePart:    proto.String(name),
+				IsExtension: proto.Bool(true),
+			})
+			isExt = true
+		} else {
+			uo.Name = append(uo.Name, &descriptorpb.UninterpretedOption_NamePart{
+				NamePart:    proto.String(p.expectIdent()),
+				IsExtension: proto.Bool(false),
+			})
+		}
+		if !p.consumeSymbol(".") {
+			break
+		}
+	}
+	p.expectSymbol("=")
+	p.parseOptionValue(uo)
+
+	if isExt {
+		m := opts.ProtoReflect()
+		fd := m.Descriptor().Fields().ByName("uninterpreted_option")
+		m.Mutable(fd).List().Append(protoreflect.ValueOfMessage(uo.ProtoReflect()))
+		return
+	}
+	if err := interpretOption(opts.ProtoReflect(), uo, nil); err != nil {
+		p.errorf(tok, "%v", err)
+	}
+}
+
+// parseOptionValue parses the value of an option into uo.
+func (p *parser) parseOptionValue(uo *descriptorpb.UninterpretedOption) {
+	switch {
+	case p.tok.kind == tokenString:
+		uo.StringValue = []byte(p.expectString())
+		return
+	case p.isSymbol("{"):
+		start := p.tok.eoff
+		depth := 0
+		for {
+			switch {
+			case p.tok.kind == tokenEOF:
+				p.unexpected(`"}"`)
+			case p.isSymbol("{"):
+				depth++
+			case p.isSymbol("}"):
+				depth--
+			}
+			end := p.tok.off
+			p.next()
+			if depth == 0 {
+				uo.AggregateValue = proto.String(strings.TrimSpace(p.lex.src[start:end]))
+				return
+			}
+		}
+	case p.tok.kind == tokenIdent:
+		uo.IdentifierValue = proto.String(p.expectFullIdent())
+		return
+	}
+
+	neg := p.consumeSymbol("-")
+	if !neg {
+		p.consumeSymbol("+")
+	}
+	switch p.tok.kind {
+	case tokenInt:
+		u, err := strconv.ParseUint(p.tok.text, 0, 64)
+		if err != nil {
+			p.errorf(p.tok, "integer %q out of range", p.tok.text)
+		}
+		switch {
+		case !neg:
+			uo.PositiveIntValue = proto.Uint64(u)
+		case u <= 1<<63:
+			uo.NegativeIntValue = proto.Int64(int64(-u))
+		default:
+			p.errorf(p.tok, "integer -%s out of range", p.tok.text)
+		}
+	case tokenFloat:
+		f, err := strconv.ParseFloat(p.tok.text, 64)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			p.errorf(p.tok, "invalid number %q", p.tok.text)
+		}
+		if neg {
+			f = -f
+		}
+		uo.DoubleValue = proto.Float64(f)
+	case tokenIdent:
+		var f float64
+		switch p.tok.text {
+		case "inf":
+			f = math.Inf(+1)
+		case "nan":
+			f = math.NaN()
+		default:
+			p.unexpected("number")
+		}
+		if neg {
+			f = -f
+		}
+		uo.DoubleValue = proto.Float64(f)
+	default:
+		p.unexpected("option value")
+	}
+	p.next()
+}
+
+// interpretOption sets the option described by uo on the options message m.
+// Extension name parts are resolved using findExtension,
+// which may be nil if uo does not reference any extensions.
+func interpretOption(m protoreflect.Message, uo *descriptorpb.UninterpretedOption, findExtension func(name string) (protoreflect.ExtensionType, error)) error {
+	var names []string
+	for i, part := range uo.GetName() {
+		name := part.GetNamePart()
+		var fd protoreflect.FieldDescriptor
+		if part.GetIsExtension() {
+			names = append(names, "("+name+")")
+			xt, err := findExtension(name)
+			if err != nil {
+				return err
+			}
+			fd = xt.TypeDescriptor()
+			if fd.ContainingMessage().FullName() != m.Descriptor().FullName() {
+				return errors.New("option %s: extension %v does not extend %v", strings.Join(names, "."), fd.FullName(), m.Descriptor().FullName())
+			}
+		} else {
+			names = append(names, name)
+			fd = m.Descriptor().Fields().ByName(protoreflect.Name(name))
+			if fd == nil || fd.Name() == "uninterpreted_option" {
+				return errors.New("option %s: unknown option %q", strings.Join(names, "."), name)
+			}
+		}
+		if i < len(uo.GetName())-1 {
+			if fd.Message() == nil || fd.Cardinality() == protoreflect.Repeated {
+				return errors.New("option %s: %v is not a singular message", strings.Join(names, "."), fd.FullName())
+			}
+			m = m.Mutable(fd).Message()
+			continue
+		}
+		v, err := optionValue(m, fd, uo)
+		if err != nil {
+			return errors.New("option %s: %v", strings.Join(names, "."), err)
+		}
+		switch {
+		case fd.IsList():
+			m.Mutable(fd).List().Append(v)
+		case fd.Message() != nil && m.Has(fd):
+			proto.Merge(m.Mutable(fd).Message().Interface(), v.Message().Interface())
+		case m.Has(fd):
+			return errors.New("option %s: already set", strings.Join(names, "."))
+		default:
+			m.Set(fd, v)
+		}
+	}
+	return nil
+}
+
+// optionValue converts the value of uo to a value of the field fd in m.
+func optionValue(m protoreflect.Message, fd protoreflect.FieldDescriptor, uo *descriptorpb.UninterpretedOption) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		switch uo.GetIdentifierValue() {
+		case "true":
+			return protoreflect.ValueOfBool(true), nil
+		case "false":
+			return protoreflect.ValueOfBool(false), nil
+		}
+	case protoreflect.EnumKind:
+		if uo.IdentifierValue != nil {
+			ev := fd.Enum().Values().ByName(protoreflect.Name(uo.GetIdentifierValue()))
+			if ev == nil {
+				return protoreflect.Value{}, errors.New("unknown value %q for enum %v", uo.GetIdentifierValue(), fd.Enum().FullName())
+			}
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+	case protoreflect.StringKind:
+		if uo.StringValue != nil {
+			return protoreflect.ValueOfString(string(uo.GetStringValue())), nil
+		}
+	case protoreflect.BytesKind:
+		if uo.StringValue != nil {
+			return protoreflect.ValueOfBytes(uo.GetStringValue()), nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if v, ok := intValue(uo, math.MinInt32, math.MaxInt32); ok {
+			return protoreflect.ValueOfInt32(int32(v)), nil
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if v, ok := intValue(uo, math.MinInt64, math.MaxInt64); ok {
+			return protoreflect.ValueOfInt64(v), nil
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if uo.PositiveIntValue != nil && uo.GetPositiveIntValue() <= math.MaxUint32 {
+			return protoreflect.ValueOfUint32(uint32(uo.GetPositiveIntValue())), nil
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if uo.PositiveIntValue != nil {
+			return protoreflect.ValueOfUint64(uo.GetPositiveIntValue()), nil
+		}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		var f float64
+		switch {
+		case uo.DoubleValue != nil:
+			f = uo.GetDoubleValue()
+		case uo.PositiveIntValue != nil:
+			f = float64(uo.GetPositiveIntValue())
+		case uo.NegativeIntValue != nil:
+			f = float64(uo.GetNegativeIntValue())
+		case uo.GetIdentifierValue() == "inf":
+			f = math.Inf(+1)
+		case uo.GetIdentifierValue() == "nan":
+			f = math.NaN()
+		default:
+			return protoreflect.Value{}, errors.New("invalid value for %v field", fd.Kind())
+		}
+		if fd.Kind() == protoreflect.FloatKind {
+			return protoreflect.ValueOfFloat32(float32(f)), nil
+		}
+		return protoreflect.ValueOfFloat64(f), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if uo.AggregateValue != nil {
+			var v protoreflect.Value
+			if fd.IsList() {
+				v = m.NewField(fd).List().NewElement()
+			} else {
+				v = m.NewField(fd)
+			}
+			o := prototext.UnmarshalOptions{Resolver: protoregistry.GlobalTypes}
+			if err := o.Unmarshal([]byte(uo.GetAggregateValue()), v.Message().Interface()); err != nil {
+				return protoreflect.Value{}, err
+			}
+			return v, nil
+		}
+	}
+	return protoreflect.Value{}, errors.New("invalid value for %v field", fd.Kind())
+}
+
+// intValue returns the integer value of uo if it is within [min, max].
+func intValue(uo *descriptorpb.UninterpretedOption, min, max int64) (int64, bool) {
+	switch {
+	case uo.PositiveIntValue != nil:
+		v := uo.GetPositiveIntValue()
+		return int64(v), v <= uint64(max)
+	case uo.NegativeIntValue != nil:
+		v := uo.GetNegativeIntValue()
+		return v, v >= min
+	}
+	return 0, false
+}
@@ -0,0 +1,1012 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoparse
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/encoding/defval"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Field numbers of the descriptor messages used to construct
+// source location paths.
+const (
+	fileMessagePath   = 4
+	fileEnumPath      = 5
+	fileServicePath   = 6
+	fileExtensionPath = 7
+	filePackagePath   = 2
+	fileImportPath    = 3
+	fileSyntaxPath    = 12
+
+	messageFieldPath     = 2
+	messageNestedPath    = 3
+	messageEnumPath      = 4
+	messageExtensionPath = 6
+	messageOneofPath     = 8
+
+	enumValuePath     = 2
+	serviceMethodPath = 2
+)
+
+// Maximum values of the reserved and extension ranges.
+const (
+	maxFieldNumber = int32(protowire.MaxValidNumber)
+	maxEnumNumber  = math.MaxInt32
+)
+
+// parseError is used to unwind the parser upon the first error.
+type parseError struct{ err error }
+
+// parser is a recursive-descent parser for .proto source.
+type parser struct {
+	lex  lexer
+	tok  token // current token
+	prev token // previously consumed token
+	peek []token
+
+	fd   *descriptorpb.FileDescriptorProto
+	locs []*descriptorpb.SourceCodeInfo_Location
+}
+
+// Parse parses the contents of a .proto source file.
+// The path is the name of the file relative to the import paths.
+//
+// The returned descriptor is not linked: type references are recorded as
+// they appear in the source (possibly relative to the enclosing scope),
+// the type of fields referencing named types is left unset, and options
+// referencing extensions are left as uninterpreted options.
+// Use a [Parser] to produce fully linked descriptors.
+func Parse(path string, src []byte) (fd *descriptorpb.FileDescriptorProto, err error) {
+	p := &parser{
+		lex: lexer{path: path, src: string(src)},
+		fd:  &descriptorpb.FileDescriptorProto{Name: proto.String(path)},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			pe, ok := r.(parseError)
+			if !ok {
+				panic(r)
+			}
+			fd, err = nil, pe.err
+		}
+	}()
+	p.next()
+	p.parseFile()
+	p.fd.SourceCodeInfo = &descriptorpb.SourceCodeInfo{Location: p.locs}
+	return p.fd, nil
+}
+
+func (p *parser) fail(err error) {
+	panic(parseError{err})
+}
+
+func (p *parser) errorf(tok token, f string, x ...any) {
+	p.fail(p.lex.errorf(tok.start, f, x...))
+}
+
+// next advances to the next token.
+func (p *parser) next() {
+	p.prev = p.tok
+	if len(p.peek) > 0 {
+		p.tok, p.peek = p.peek[0], p.peek[1:]
+		return
+	}
+	tok, err := p.lex.next()
+	if err != nil {
+		p.fail(err)
+	}
+	p.tok = tok
+}
+
+// lookahead returns the i-th token after the current one.
+func (p *parser) lookahead(i int) token {
+	for len(p.peek) < i {
+		tok, err := p.lex.next()
+		if err != nil {
+			p.fail(err)
+		}
+		p.peek = append(p.peek, tok)
+	}
+	return p.peek[i-1]
+}
+
+func (p *parser) unexpected(want string) {
+	switch p.tok.kind {
+	case tokenEOF:
+		p.errorf(p.tok, "unexpected end of file, expected %s", want)
+	default:
+		p.errorf(p.tok, "unexpected %s %q, expected %s", p.tok.kind, p.tok.text, want)
+	}
+}
+
+func (p *parser) isSymbol(s string) bool {
+	return p.tok.kind == tokenSymbol && p.tok.text == s
+}
+
+func (p *parser) isKeyword(s string) bool {
+	return p.tok.kind == tokenIdent && p.tok.text == s
+}
+
+func (p *parser) consumeSymbol(s string) bool {
+	if p.isSymbol(s) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectSymbol(s string) {
+	if !p.consumeSymbol(s) {
+		p.unexpected(strconv.Quote(s))
+	}
+}
+
+func (p *parser) expectKeyword(s string) {
+	if !p.isKeyword(s) {
+		p.unexpected(strconv.Quote(s))
+	}
+	p.next()
+}
+
+func (p *parser) expectIdent() string {
+	if p.tok.kind != tokenIdent {
+		p.unexpected("identifier")
+	}
+	s := p.tok.text
+	p.next()
+	return s
+}
+
+// expectFullIdent parses a dot-separated identifier.
+func (p *parser) expectFullIdent() string {
+	s := p.expectIdent()
+	for p.consumeSymbol(".") {
+		s += "." + p.expectIdent()
+	}
+	return s
+}
+
+// expectTypeName parses a possibly fully-qualified type name.
+func (p *parser) expectTypeName() string {
+	if p.consumeSymbol(".") {
+		return "." + p.expectFullIdent()
+	}
+	return p.expectFullIdent()
+}
+
+// expectString parses one or more adjacent string literals.
+func (p *parser) expectString() string {
+	if p.tok.kind != tokenString {
+		p.unexpected("string")
+	}
+	var s string
+	for p.tok.kind == tokenString {
+		s += p.tok.text
+		p.next()
+	}
+	return s
+}
+
+// expectInt parses an integer literal within the range [min, max].
+func (p *parser) expectInt(min, max int64) int64 {
+	tok := p.tok
+	neg := p.consumeSymbol("-")
+	if p.tok.kind != tokenInt {
+		p.unexpected("integer")
+	}
+	text := p.tok.text
+	if neg {
+		text = "-" + text
+	}
+	u, err := strconv.ParseUint(p.tok.text, 0, 64)
+	v := int64(u)
+	if neg {
+		v = -v
+	}
+	if err != nil || u > math.MaxInt64 || v < min || v > max {
+		p.errorf(tok, "integer %s out of range", text)
+	}
+	p.next()
+	return v
+}
+
+func (p *parser) expectFieldNumber() int32 {
+	return int32(p.expectInt(1, int64(maxFieldNumber)))
+}
+
+// expectDeclaredFieldNumber is like expectFieldNumber, but also rejects the
+// numbers reserved for the protocol buffer implementation, which may be
+// covered by ranges but not used by fields.
+func (p *parser) expectDeclaredFieldNumber() int32 {
+	tok := p.tok
+	n := p.expectFieldNumber()
+	if protowire.FirstReservedNumber <= protowire.Number(n) && protowire.Number(n) <= protowire.LastReservedNumber {
+		p.errorf(tok, "field numbers %d through %d are reserved for the protocol buffer library implementation",
+			protowire.FirstReservedNumber, protowire.LastReservedNumber)
+	}
+	return n
+}
+
+// declStart records the state of the parser at the start of a declaration.
+type declStart struct {
+	tok     token
+	prevEnd position
+}
+
+func (p *parser) start() declStart {
+	return declStart{tok: p.tok, prevEnd: p.prev.end}
+}
+
+// trailingComment returns the comment following the previously consumed
+// token on the same line, if any.
+func (p *parser) trailingComment() *string {
+	if cs := p.tok.comments; len(cs) > 0 && cs[0].start.line == p.prev.end.line {
+		return proto.String(cs[0].text)
+	}
+	return nil
+}
+
+// addLocation records the source location of the declaration that started
+// at ds and ended with the previously consumed token.
+func (p *parser) addLocation(path []int32, ds declStart, trailing *string) {
+	loc := &descriptorpb.SourceCodeInfo_Location{
+		Path:             append([]int32(nil), path...),
+		Span:             makeSpan(ds.tok.start, p.prev.end),
+		TrailingComments: trailing,
+	}
+	leading := leadingBlocks(ds.tok.comments, ds.prevEnd)
+	if n := len(leading); n > 0 && ds.tok.start.line-leading[n-1].end.line <= 1 {
+		loc.LeadingComments = proto.String(leading[n-1].text)
+		leading = leading[:n-1]
+	}
+	for _, c := range leading {
+		loc.LeadingDetachedComments = append(loc.LeadingDetachedComments, c.text)
+	}
+	p.locs = append(p.locs, loc)
+}
+
+// leadingBlocks returns the comment blocks that may serve as leading comments,
+// which excludes a block starting on the same line as the previous token.
+func leadingBlocks(cs []comment, prevEnd position) []comment {
+	if len(cs) > 0 && cs[0].start.line == prevEnd.line && prevEnd != (position{}) {
+		cs = cs[1:]
+	}
+	return cs
+}
+
+func makeSpan(start, end position) []int32 {
+	if start.line == end.line {
+		return []int32{int32(start.line), int32(start.col), int32(end.col)}
+	}
+	return []int32{int32(start.line), int32(start.col), int32(end.line), int32(end.col)}
+}
+
+func (p *parser) parseFile() {
+	fd := p.fd
+	if p.isKeyword("syntax") || p.isKeyword("edition") {
+		ds := p.start()
+		kw := p.expectIdent()
+		p.expectSymbol("=")
+		tok := p.tok
+		s := p.expectString()
+		p.expectSymbol(";")
+		switch {
+		case kw == "syntax" && (s == "proto2" || s == "proto3"):
+			fd.Syntax = proto.String(s)
+			p.addLocation([]int32{fileSyntaxPath}, ds, p.trailingComment())
+		case kw == "edition":
+			e, ok := descriptorpb.Edition_value["EDITION_"+s]
+			if !ok {
+				p.errorf(tok, "unknown edition %q", s)
+			}
+			fd.Syntax = proto.String("editions")
+			fd.Edition = descriptorpb.Edition(e).Enum()
+			// Like protoc, record the edition declaration under the
+			// syntax field so that comments preceding it are found
+			// in the same place regardless of the file's syntax.
+			p.addLocation([]int32{fileSyntaxPath}, ds, p.trailingComment())
+		default:
+			p.errorf(tok, "unknown syntax %q", s)
+		}
+	}
+	for p.tok.kind != tokenEOF {
+		ds := p.start()
+		switch {
+		case p.consumeSymbol(";"):
+		case p.isKeyword("import"):
+			p.next()
+			idx := int32(len(fd.Dependency))
+			switch {
+			case p.isKeyword("public"):
+				p.next()
+				fd.PublicDependency = append(fd.PublicDependency, idx)
+			case p.isKeyword("weak"):
+				p.next()
+				fd.WeakDependency = append(fd.WeakDependency, idx)
+			}
+			fd.Dependency = append(fd.Dependency, p.expectString())
+			p.expectSymbol(";")
+			p.addLocation([]int32{fileImportPath, idx}, ds, p.trailingComment())
+		case p.isKeyword("package"):
+			if fd.Package != nil {
+				p.errorf(p.tok, "multiple package declarations")
+			}
+			p.next()
+			fd.Package = proto.String(p.expectFullIdent())
+			p.expectSymbol(";")
+			p.addLocation([]int32{filePackagePath}, ds, p.trailingComment())
+		case p.isKeyword("option"):
+			if fd.Options == nil {
+				fd.Options = new(descriptorpb.FileOptions)
+			}
+			p.parseOptionStatement(fd.Options)
+		case p.isKeyword("message"):
+			path := []int32{fileMessagePath, int32(len(fd.MessageType))}
+			fd.MessageType = append(fd.MessageType, p.parseMessage(path))
+		case p.isKeyword("enum"):
+			path := []int32{fileEnumPath, int32(len(fd.EnumType))}
+			fd.EnumType = append(fd.EnumType, p.parseEnum(path))
+		case p.isKeyword("service"):
+			path := []int32{fileServicePath, int32(len(fd.Service))}
+			fd.Service = append(fd.Service, p.parseService(path))
+		case p.isKeyword("extend"):
+			p.parseExtend(&fd.Extension, []int32{fileExtensionPath}, &fd.MessageType, []int32{fileMessagePath})
+		default:
+			p.unexpected("top-level declaration")
+		}
+	}
+}
+
+// isProto3 reports whether the file uses proto3 syntax.
+func (p *parser) isProto3() bool {
+	return p.fd.GetSyntax() == "proto3"
+}
+
+// isEditions reports whether the file uses editions syntax.
+func (p *parser) isEditions() bool {
+	return p.fd.GetSyntax() == "editions"
+}
+
+func (p *parser) parseMessage(path []int32) *descriptorpb.DescriptorProto {
+	ds := p.start()
+	p.expectKeyword("message")
+	md := &descriptorpb.DescriptorProto{Name: proto.String(p.expectIdent())}
+	p.expectSymbol("{")
+	trailing := p.trailingComment()
+	p.parseMessageBody(md, path)
+	p.addLocation(path, ds, trailing)
+	return md
+}
+
+// parseMessageBody parses the declarations of a message up to and
+// including the closing brace.
+func (p *parser) parseMessageBody(md *descriptorpb.DescriptorProto, path []int32) {
+	for !p.consumeSymbol("}") {
+		ds := p.start()
+		switch {
+		case p.tok.kind == tokenEOF:
+			p.unexpected(`"}"`)
+		case p.consumeSymbol(";"):
+		case p.isKeyword("option"):
+			if md.Options == nil {
+				md.Options = new(descriptorpb.MessageOptions)
+			}
+			p.parseOptionStatement(md.Options)
+		case p.isKeyword("reserved"):
+			p.parseMessageReserved(md)
+		case p.isKeyword("extensions"):
+			p.parseExtensionRanges(md)
+		case p.isDecl("message"):
+			md.NestedType = append(md.NestedType, p.parseMessage(appendPath(path, messageNestedPath, len(md.NestedType))))
+		case p.isDecl("enum"):
+			md.EnumType = append(md.EnumType, p.parseEnum(appendPath(path, messageEnumPath, len(md.EnumType))))
+		case p.isDecl("extend"):
+			p.parseExtend(&md.Extension, appendPath(path, messageExtensionPath), &md.NestedType, appendPath(path, messageNestedPath))
+		case p.isDecl("oneof"):
+			p.parseOneof(md, path)
+		default:
+			p.parseField(ds, md, path, nil)
+		}
+	}
+	p.addProto3Optionals(md)
+}
+
+// isDecl reports whether the current token is the keyword kw
+// introducing a named block, as opposed to a field of a type named kw.
+func (p *parser) isDecl(kw string) bool {
+	if !p.isKeyword(kw) {
+		return false
+	}
+	next := p.lookahead(1)
+	if kw == "extend" {
+		return next.kind == tokenIdent || (next.kind == tokenSymbol && next.text == ".")
+	}
+	return next.kind == tokenIdent && p.lookahead(2).kind == tokenSymbol && p.lookahead(2).text == "{"
+}
+
+func appendPath(path []int32, x ...int) []int32 {
+	out := append([]int32(nil), path...)
+	for _, v := range x {
+		out = append(out, int32(v))
+	}
+	return out
+}
+
+func (p *parser) parseOneof(md *descriptorpb.DescriptorProto, msgPath []int32) {
+	ds := p.start()
+	p.expectKeyword("oneof")
+	idx := int32(len(md.OneofDecl))
+	od := &descriptorpb.OneofDescriptorProto{Name: proto.String(p.expectIdent())}
+	md.OneofDecl = append(md.OneofDecl, od)
+	p.expectSymbol("{")
+	trailing := p.trailingComment()
+	for !p.consumeSymbol("}") {
+		fds := p.start()
+		switch {
+		case p.tok.kind == tokenEOF:
+			p.unexpected(`"}"`)
+		case p.consumeSymbol(";"):
+		case p.isKeyword("option"):
+			if od.Options == nil {
+				od.Options = new(descriptorpb.OneofOptions)
+			}
+			p.parseOptionStatement(od.Options)
+		default:
+			p.parseField(fds, md, msgPath, &idx)
+		}
+	}
+	p.addLocation(appendPath(msgPath, messageOneofPath, int(idx)), ds, trailing)
+}
+
+// addProto3Optionals adds the synthetic oneofs for proto3 optional fields
+// after all other oneofs in the message.
+func (p *parser) addProto3Optionals(md *descriptorpb.DescriptorProto) {
+	names := map[string]bool{}
+	for _, f := range md.Field {
+		names[f.GetName()] = true
+	}
+	for _, od := range md.OneofDecl {
+		names[od.GetName()] = true
+	}
+	for _, f := range md.Field {
+		if !f.GetProto3Optional() {
+			continue
+		}
+		name := "_" + f.GetName()
+		for names[name] {
+			name = "X" + name
+		}
+		names[name] = true
+		f.OneofIndex = proto.Int32(int32(len(md.OneofDecl)))
+		md.OneofDecl = append(md.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(name)})
+	}
+}
+
+// scalarTypes maps the names of scalar types to their field type.
+var scalarTypes = map[string]descriptorpb.FieldDescriptorProto_Type{
+	"double":   descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	"float":    descriptorpb.FieldDescriptorProto_TYPE_FLOAT,
+	"int64":    descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	"uint64":   descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+	"int32":    descriptorpb.FieldDescriptorProto_TYPE_INT32,
+	"fixed64":  descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+	"fixed32":  descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+	"bool":     descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	"string":   descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	"bytes":    descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+	"uint32":   descriptorpb.FieldDescriptorProto_TYPE_UINT32,
+	"sfixed32": descriptorpb.FieldDescriptorProto_TYPE_SFIXED32,
+	"sfixed64": descriptorpb.FieldDescriptorProto_TYPE_SFIXED64,
+	"sint32":   descriptorpb.FieldDescriptorProto_TYPE_SINT32,
+	"sint64":   descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+}
+
+// parseField parses a field, group, or map field and adds it to md.
+// If oneofIdx is non-nil, the field is a member of that oneof.
+func (p *parser) parseField(ds declStart, md *descriptorpb.DescriptorProto, msgPath []int32, oneofIdx *int32) {
+	path := appendPath(msgPath, messageFieldPath, len(md.Field))
+	fd := new(descriptorpb.FieldDescriptorProto)
+	md.Field = append(md.Field, fd)
+	if oneofIdx != nil {
+		fd.OneofIndex = proto.Int32(*oneofIdx)
+	}
+	p.parseFieldDecl(ds, fd, path, func(nested *descriptorpb.DescriptorProto) []int32 {
+		md.NestedType = append(md.NestedType, nested)
+		return appendPath(msgPath, messageNestedPath, len(md.NestedType)-1)
+	})
+}
+
+// parseFieldDecl parses a field declaration into fd. Nested message types
+// implied by groups and map fields are passed to addNested, which returns
+// the source location path of the added message.
+func (p *parser) parseFieldDecl(ds declStart, fd *descriptorpb.FieldDescriptorProto, path []int32, addNested func(*descriptorpb.DescriptorProto) []int32) {
+	labelTok := p.tok
+	var label descriptorpb.FieldDescriptorProto_Label
+	switch {
+	case p.isKeyword("optional"):
+		label = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	case p.isKeyword("required"):
+		label = descriptorpb.FieldDescriptorProto_LABEL_REQUIRED
+	case p.isKeyword("repeated"):
+		label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	}
+	if label != 0 {
+		p.next()
+	}
+	switch {
+	case label == 0 && p.isKeyword("map") && p.lookahead(1).kind == tokenSymbol && p.lookahead(1).text == "<":
+		p.parseMapField(ds, fd, path, addNested)
+		return
+	case (label != 0 || fd.OneofIndex != nil) && p.isKeyword("group") && p.lookahead(1).kind == tokenIdent:
+		if p.isProto3() || p.isEditions() {
+			p.errorf(p.tok, "groups are not supported in %s", p.fd.GetSyntax())
+		}
+		if label == 0 {
+			label = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		}
+		fd.Label = label.Enum()
+		p.parseGroupField(ds, fd, path, addNested)
+		return
+	}
+
+	switch {
+	case fd.OneofIndex != nil && label != 0:
+		p.errorf(labelTok, "fields in oneofs must not have labels")
+	case label == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED && (p.isProto3() || p.isEditions()):
+		p.errorf(labelTok, "required fields are not supported in %s", p.fd.GetSyntax())
+	case label == descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL && p.isEditions():
+		p.errorf(labelTok, "label \"optional\" is not supported in editions")
+	case label == descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL && p.isProto3():
+		fd.Proto3Optional = proto.Bool(true)
+	case label == 0 && fd.OneofIndex == nil && !p.isProto3() && !p.isEditions():
+		p.errorf(p.tok, "fields must have a label in proto2")
+	}
+	if label == 0 {
+		label = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	}
+	fd.Label = label.Enum()
+
+	typeName := p.expectTypeName()
+	if t, ok := scalarTypes[typeName]; ok {
+		fd.Type = t.Enum()
+	} else {
+		fd.TypeName = proto.String(typeName)
+	}
+	fd.Name = proto.String(p.expectIdent())
+	p.expectSymbol("=")
+	fd.Number = proto.Int32(p.expectDeclaredFieldNumber())
+	p.parseFieldOptions(fd)
+	p.expectSymbol(";")
+	p.addLocation(path, ds, p.trailingComment())
+}
+
+func (p *parser) parseGroupField(ds declStart, fd *descriptorpb.FieldDescriptorProto, path []int32, addNested func(*descriptorpb.DescriptorProto) []int32) {
+	p.expectKeyword("group")
+	nameTok := p.tok
+	name := p.expectIdent()
+	if c := name[0]; c < 'A' || c > 'Z' {
+		p.errorf(nameTok, "group name %q must start with a capital letter", name)
+	}
+	fd.Name = proto.String(strings.ToLower(name))
+	fd.Type = descriptorpb.FieldDescriptorProto_TYPE_GROUP.Enum()
+	fd.TypeName = proto.String(name)
+	p.expectSymbol("=")
+	fd.Number = proto.Int32(p.expectDeclaredFieldNumber())
+	p.parseFieldOptions(fd)
+	md := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+	msgPath := addNested(md)
+	p.expectSymbol("{")
+	trailing := p.trailingComment()
+	p.parseMessageBody(md, msgPath)
+	p.addLocation(path, ds, trailing)
+}
+
+func (p *parser) parseMapField(ds declStart, fd *descriptorpb.FieldDescriptorProto, path []int32, addNested func(*descriptorpb.DescriptorProto) []int32) {
+	if fd.OneofIndex != nil {
+		p.errorf(p.tok, "map fields are not allowed in oneofs")
+	}
+	p.expectKeyword("map")
+	p.expectSymbol("<")
+	keyTok := p.tok
+	keyType := p.expectIdent()
+	kt, ok := scalarTypes[keyType]
+	if !ok || keyType == "double" || keyType == "float" || keyType == "bytes" {
+		p.errorf(keyTok, "invalid map key type %q", keyType)
+	}
+	p.expectSymbol(",")
+	valType := p.expectTypeName()
+	p.expectSymbol(">")
+	name := p.expectIdent()
+	entryName := mapEntryName(name)
+
+	key := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("key"),
+		JsonName: proto.String("key"),
+		Number:   proto.Int32(1),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     kt.Enum(),
+	}
+	val := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("value"),
+		JsonName: proto.String("value"),
+		Number:   proto.Int32(2),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	if t, ok := scalarTypes[valType]; ok {
+		val.Type = t.Enum()
+	} else {
+		val.TypeName = proto.String(valType)
+	}
+	addNested(&descriptorpb.DescriptorProto{
+		Name:    proto.String(entryName),
+		Field:   []*descriptorpb.FieldDescriptorProto{key, val},
+		Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+	})
+
+	fd.Name = proto.String(name)
+	fd.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	fd.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	fd.TypeName = proto.String(entryName)
+	p.expectSymbol("=")
+	fd.Number = proto.Int32(p.expectDeclaredFieldNumber())
+	p.parseFieldOptions(fd)
+	p.expectSymbol(";")
+	p.addLocation(path, ds, p.trailingComment())
+}
+
+// mapEntryName returns the name of the synthetic message for a map field,
+// which is the camel-cased field name followed by "Entry".
+func mapEntryName(s string) string {
+	var b []byte
+	upperNext := true
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '_':
+			upperNext = true
+		case upperNext && 'a' <= c && c <= 'z':
+			b = append(b, c-'a'+'A')
+			upperNext = false
+		default:
+			b = append(b, c)
+			upperNext = false
+		}
+	}
+	return string(b) + "Entry"
+}
+
+// parseFieldOptions parses the optional bracketed list of field options,
+// including the pseudo-options "default" and "json_name".
+func (p *parser) parseFieldOptions(fd *descriptorpb.FieldDescriptorProto) {
+	if !p.consumeSymbol("[") {
+		return
+	}
+	for {
+		switch {
+		case p.isKeyword("default") && p.lookahead(1).kind == tokenSymbol && p.lookahead(1).text == "=":
+			if fd.DefaultValue != nil {
+				p.errorf(p.tok, "duplicate default value")
+			}
+			p.next()
+			p.next()
+			fd.DefaultValue = proto.String(p.parseDefaultValue(fd))
+		case p.isKeyword("json_name") && p.lookahead(1).kind == tokenSymbol && p.lookahead(1).text == "=":
+			if fd.JsonName != nil {
+				p.errorf(p.tok, "duplicate json_name")
+			}
+			p.next()
+			p.next()
+			fd.JsonName = proto.String(p.expectString())
+		default:
+			if fd.Options == nil {
+				fd.Options = new(descriptorpb.FieldOptions)
+			}
+			p.parseOption(fd.Options)
+		}
+		if p.consumeSymbol("]") {
+			return
+		}
+		p.expectSymbol(",")
+	}
+}
+
+// parseDefaultValue parses the value of a default pseudo-option
+// and returns it in the format used by FieldDescriptorProto.default_value.
+func (p *parser) parseDefaultValue(fd *descriptorpb.FieldDescriptorProto) string {
+	tok := p.tok
+	switch fd.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		return p.expectString()
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		s, err := defval.Marshal(protoreflect.ValueOfBytes([]byte(p.expectString())), nil, protoreflect.BytesKind, defval.Descriptor)
+		if err != nil {
+			p.errorf(tok, "%v", err)
+		}
+		return s
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		if s := p.expectIdent(); s == "true" || s == "false" {
+			return s
+		}
+		p.errorf(tok, "invalid default value for bool field")
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		p.errorf(tok, "default values are not allowed for message fields")
+	}
+
+	neg := p.consumeSymbol("-")
+	sign := ""
+	if neg {
+		sign = "-"
+	}
+	switch p.tok.kind {
+	case tokenInt:
+		u, err := strconv.ParseUint(p.tok.text, 0, 64)
+		if err != nil {
+			p.errorf(p.tok, "invalid integer %q", p.tok.text)
+		}
+		p.next()
+		return sign + strconv.FormatUint(u, 10)
+	case tokenFloat:
+		s := p.tok.text
+		p.next()
+		return sign + s
+	case tokenIdent:
+		s := p.tok.text
+		if fd.Type != nil && (s == "inf" || s == "nan") {
+			p.next()
+			return sign + s
+		}
+		if !neg && fd.Type == nil {
+			p.next()
+			return s // enum value name
+		}
+	}
+	p.unexpected("default value")
+	panic("unreachable")
+}
+
+func (p *parser) parseExtensionRanges(md *descriptorpb.DescriptorProto) {
+	p.expectKeyword("extensions")
+	var ranges []*descriptorpb.DescriptorProto_ExtensionRange
+	for {
+		start := int32(p.expectFieldNumber())
+		end := start
+		if p.isKeyword("to") {
+			p.next()
+			if p.isKeyword("max") {
+				p.next()
+				end = maxFieldNumber
+			} else {
+				end = p.expectFieldNumber()
+			}
+		}
+		ranges = append(ranges, &descriptorpb.DescriptorProto_ExtensionRange{
+			Start: proto.Int32(start),
+			End:   proto.Int32(end + 1),
+		})
+		if !p.consumeSymbol(",") {
+			break
+		}
+	}
+	if p.consumeSymbol("[") {
+		opts := new(descriptorpb.ExtensionRangeOptions)
+		for {
+			p.parseOption(opts)
+			if p.consumeSymbol("]") {
+				break
+			}
+			p.expectSymbol(",")
+		}
+		for _, r := range ranges {
+			r.Options = opts
+		}
+	}
+	p.expectSymbol(";")
+	md.ExtensionRange = append(md.ExtensionRange, ranges...)
+}
+
+func (p *parser) parseMessageReserved(md *descriptorpb.DescriptorProto) {
+	names, ranges := p.parseReserved(maxFieldNumber)
+	md.ReservedName = append(md.ReservedName, names...)
+	for _, r := range ranges {
+		md.ReservedRange = append(md.ReservedRange, &descriptorpb.DescriptorProto_ReservedRange{
+			Start: proto.Int32(r[0]),
+			End:   proto.Int32(r[1] + 1), // exclusive
+		})
+	}
+}
+
+// parseReserved parses a reserved statement, which contains either
+// a list of names or a list of inclusive number ranges.
+func (p *parser) parseReserved(max int32) (names []string, ranges [][2]int32) {
+	p.expectKeyword("reserved")
+	min := int64(1)
+	if max == maxEnumNumber {
+		min = math.MinInt32
+	}
+	for {
+		switch {
+		case len(ranges) == 0 && p.tok.kind == tokenString:
+			names = append(names, p.expectString())
+		case len(ranges) == 0 && p.tok.kind == tokenIdent && p.isEditions():
+			names = append(names, p.expectIdent())
+		case len(names) == 0:
+			start := int32(p.expectInt(min, int64(max)))
+			end := start
+			if p.isKeyword("to") {
+				p.next()
+				if p.isKeyword("max") {
+					p.next()
+					end = max
+				} else {
+					end = int32(p.expectInt(min, int64(max)))
+				}
+			}
+			ranges = append(ranges, [2]int32{start, end})
+		default:
+			p.unexpected("reserved number")
+		}
+		if !p.consumeSymbol(",") {
+			break
+		}
+	}
+	p.expectSymbol(";")
+	return names, ranges
+}
+
+func (p *parser) parseExtend(exts *[]*descriptorpb.FieldDescriptorProto, extPath []int32, msgs *[]*descriptorpb.DescriptorProto, msgPath []int32) {
+	p.expectKeyword("extend")
+	extendee := p.expectTypeName()
+	p.expectSymbol("{")
+	for !p.consumeSymbol("}") {
+		ds := p.start()
+		switch {
+		case p.tok.kind == tokenEOF:
+			p.unexpected(`"}"`)
+		case p.consumeSymbol(";"):
+		default:
+			fd := &descriptorpb.FieldDescriptorProto{Extendee: proto.String(extendee)}
+			path := appendPath(extPath, len(*exts))
+			*exts = append(*exts, fd)
+			p.parseFieldDecl(ds, fd, path, func(nested *descriptorpb.DescriptorProto) []int32 {
+				*msgs = append(*msgs, nested)
+				return appendPath(msgPath, len(*msgs)-1)
+			})
+			if fd.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED && fd.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE && fd.Options.GetPacked() {
+				p.errorf(ds.tok, "message extensions cannot be packed")
+			}
+		}
+	}
+}
+
+func (p *parser) parseEnum(path []int32) *descriptorpb.EnumDescriptorProto {
+	ds := p.start()
+	p.expectKeyword("enum")
+	ed := &descriptorpb.EnumDescriptorProto{Name: proto.String(p.expectIdent())}
+	p.expectSymbol("{")
+	trailing := p.trailingComment()
+	for !p.consumeSymbol("}") {
+		vds := p.start()
+		switch {
+		case p.tok.kind == tokenEOF:
+			p.unexpected(`"}"`)
+		case p.consumeSymbol(";"):
+		case p.isKeyword("option") && p.lookahead(1).text != "=":
+			if ed.Options == nil {
+				ed.Options = new(descriptorpb.EnumOptions)
+			}
+			p.parseOptionStatement(ed.Options)
+		case p.isKeyword("reserved") && p.lookahead(1).text != "=":
+			names, ranges := p.parseReserved(maxEnumNumber)
+			ed.ReservedName = append(ed.ReservedName, names...)
+			for _, r := range ranges {
+				ed.ReservedRange = append(ed.ReservedRange, &descriptorpb.EnumDescriptorProto_EnumReservedRange{
+					Start: proto.Int32(r[0]),
+					End:   proto.Int32(r[1]), // inclusive
+				})
+			}
+		default:
+			vpath := appendPath(path, enumValuePath, len(ed.Value))
+			vd := &descriptorpb.EnumValueDescriptorProto{Name: proto.String(p.expectIdent())}
+			ed.Value = append(ed.Value, vd)
+			p.expectSymbol("=")
+			vd.Number = proto.Int32(int32(p.expectInt(math.MinInt32, math.MaxInt32)))
+			if p.consumeSymbol("[") {
+				vd.Options = new(descriptorpb.EnumValueOptions)
+				for {
+					p.parseOption(vd.Options)
+					if p.consumeSymbol("]") {
+						break
+					}
+					p.expectSymbol(",")
+				}
+			}
+			p.expectSymbol(";")
+			p.addLocation(vpath, vds, p.trailingComment())
+		}
+	}
+	p.addLocation(path, ds, trailing)
+	return ed
+}
+
+func (p *parser) parseService(path []int32) *descriptorpb.ServiceDescriptorProto {
+	ds := p.start()
+	p.expectKeyword("service")
+	sd := &descriptorpb.ServiceDescriptorProto{Name: proto.String(p.expectIdent())}
+	p.expectSymbol("{")
+	trailing := p.trailingComment()
+	for !p.consumeSymbol("}") {
+		mds := p.start()
+		switch {
+		case p.tok.kind == tokenEOF:
+			p.unexpected(`"}"`)
+		case p.consumeSymbol(";"):
+		case p.isKeyword("option"):
+			if sd.Options == nil {
+				sd.Options = new(descriptorpb.ServiceOptions)
+			}
+			p.parseOptionStatement(sd.Options)
+		case p.isKeyword("rpc"):
+			mpath := appendPath(path, serviceMethodPath, len(sd.Method))
+			sd.Method = append(sd.Method, p.parseMethod(mds, mpath))
+		default:
+			p.unexpected(`"rpc"`)
+		}
+	}
+	p.addLocation(path, ds, trailing)
+	return sd
+}
+
+func (p *parser) parseMethod(ds declStart, path []int32) *descriptorpb.MethodDescriptorProto {
+	p.expectKeyword("rpc")
+	md := &descriptorpb.MethodDescriptorProto{Name: proto.String(p.expectIdent())}
+	parseType := func() (string, bool) {
+		p.expectSymbol("(")
+		// The keyword "stream" may also be the first component of a type name.
+		next := p.lookahead(1)
+		stream := p.isKeyword("stream") && (next.kind == tokenIdent || next.kind == tokenSymbol && next.text == "." && next.off > p.tok.eoff)
+		if stream {
+			p.next()
+		}
+		s := p.expectTypeName()
+		p.expectSymbol(")")
+		return s, stream
+	}
+	in, inStream := parseType()
+	p.expectKeyword("returns")
+	out, outStream := parseType()
+	md.InputType, md.OutputType = proto.String(in), proto.String(out)
+	if inStream {
+		md.ClientStreaming = proto.Bool(true)
+	}
+	if outStream {
+		md.ServerStreaming = proto.Bool(true)
+	}
+	var trailing *string
+	if p.consumeSymbol("{") {
+		trailing = p.trailingComment()
+		for !p.consumeSymbol("}") {
+			switch {
+			case p.tok.kind == tokenEOF:
+				p.unexpected(`"}"`)
+			case p.consumeSymbol(";"):
+			default:
+				if md.Options == nil {
+					md.Options = new(descriptorpb.MethodOptions)
+				}
+				p.parseOptionStatement(md.Options)
+			}
+		}
+	} else {
+		p.expectSymbol(";")
+	}
+	p.addLocation(path, ds, trailing)
+	return md
+}
@@ -0,0 +1,363 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoparse parses .proto source files into descriptors
+// without invoking protoc.
+//
+// The [Parse] function converts a single source file into an unlinked
+// [descriptorpb.FileDescriptorProto]. A [Parser] additionally loads
+// imported files, resolves type references, interprets custom options,
+// and produces linked [protoreflect.FileDescriptor] values.
+//
+// The parser supports proto2, proto3, and editions syntax.
+// Source code info is limited to the locations of declarations
+// (such as messages, fields, enum values, and methods) together with
+// their comments; it does not record the locations of individual tokens.
+package protoparse
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/strs"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Parser parses and links .proto source files.
+type Parser struct {
+	// ImportPaths is the list of directories searched for source files.
+	// If empty, files are looked up relative to the current directory.
+	// It is ignored if Accessor is set.
+	ImportPaths []string
+
+	// Accessor opens the source file with the given import path.
+	// If the returned error matches [fs.ErrNotExist],
+	// the file is looked up in the Resolver instead.
+	// If nil, files are opened from the ImportPaths.
+	Accessor func(path string) (io.ReadCloser, error)
+
+	// Resolver is used to look up imported files that cannot be found
+	// by the Accessor, such as well-known types compiled into the binary.
+	// If nil, [protoregistry.GlobalFiles] is used.
+	Resolver protodesc.Resolver
+
+	// IncludeSourceCodeInfo specifies whether the source code info
+	// is retained in the resulting descriptors.
+	IncludeSourceCodeInfo bool
+}
+
+// ParseFiles parses and links the source files with the given import paths,
+// along with all of their transitive imports.
+// It returns a file descriptor for each path in the order provided.
+func (p Parser) ParseFiles(paths ...string) ([]protoreflect.FileDescriptor, error) {
+	l := &linker{
+		parser:   p,
+		files:    new(protoregistry.Files),
+		fallback: p.Resolver,
+		loading:  map[string]bool{},
+	}
+	if l.fallback == nil {
+		l.fallback = protoregistry.GlobalFiles
+	}
+	var fds []protoreflect.FileDescriptor
+	for _, path := range paths {
+		fd, err := l.load(path)
+		if err != nil {
+			return nil, err
+		}
+		fds = append(fds, fd)
+	}
+	return fds, nil
+}
+
+func (p Parser) open(path string) (io.ReadCloser, error) {
+	if p.Accessor != nil {
+		return p.Accessor(path)
+	}
+	dirs := p.ImportPaths
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+	for _, dir := range dirs {
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(path)))
+		if err == nil || !errors.Is(err, fs.ErrNotExist) {
+			return f, err
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+// linker loads and links files on behalf of a Parser.
+type linker struct {
+	parser   Parser
+	files    *protoregistry.Files // files parsed from source
+	fallback protodesc.Resolver
+	loading  map[string]bool // files currently being loaded, to detect cycles
+}
+
+func (l *linker) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := l.files.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return l.fallback.FindFileByPath(path)
+}
+
+func (l *linker) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := l.files.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return l.fallback.FindDescriptorByName(name)
+}
+
+// load returns the linked descriptor for the file with the given path.
+func (l *linker) load(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := l.files.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	if l.loading[path] {
+		return nil, errors.New("import cycle involving %q", path)
+	}
+	r, err := l.parser.open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if fd, err := l.fallback.FindFileByPath(path); err == nil {
+			return fd, nil
+		}
+		return nil, errors.New("could not find file %q", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	src, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		return nil, err
+	}
+	fdp, err := Parse(path, src)
+	if err != nil {
+		return nil, err
+	}
+
+	l.loading[path] = true
+	for _, dep := range fdp.GetDependency() {
+		if _, err := l.load(dep); err != nil {
+			return nil, err
+		}
+	}
+	delete(l.loading, path)
+
+	if !l.parser.IncludeSourceCodeInfo {
+		fdp.SourceCodeInfo = nil
+	}
+	fd, err := l.link(fdp)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.files.RegisterFile(fd); err != nil {
+		return nil, err
+	}
+	return fd, nil
+}
+
+// link resolves the type references and custom options of fdp in place
+// and returns the resulting file descriptor.
+func (l *linker) link(fdp *descriptorpb.FileDescriptorProto) (protoreflect.FileDescriptor, error) {
+	// Build a preliminary descriptor to resolve relative type names.
+	fd, err := protodesc.NewFile(fdp, l)
+	if err != nil {
+		return nil, errors.New("%s: %v", fdp.GetName(), err)
+	}
+	resolveMessages(fdp.GetMessageType(), fd.Messages())
+	resolveFields(fdp.GetExtension(), fd.Extensions())
+	for i, sdp := range fdp.GetService() {
+		sd := fd.Services().Get(i)
+		for j, mdp := range sdp.GetMethod() {
+			md := sd.Methods().Get(j)
+			mdp.InputType = proto.String("." + string(md.Input().FullName()))
+			mdp.OutputType = proto.String("." + string(md.Output().FullName()))
+		}
+	}
+
+	// Interpret options referencing extensions.
+	if err := l.interpretOptions(fdp, fd); err != nil {
+		return nil, errors.New("%s: %v", fdp.GetName(), err)
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(fdp)
+	if err != nil {
+		return nil, err
+	}
+	proto.Reset(fdp)
+	if err := proto.Unmarshal(b, fdp); err != nil {
+		return nil, err
+	}
+
+	fd, err = protodesc.NewFile(fdp, l)
+	if err != nil {
+		return nil, errors.New("%s: %v", fdp.GetName(), err)
+	}
+	return fd, nil
+}
+
+func resolveMessages(mdps []*descriptorpb.DescriptorProto, mds protoreflect.MessageDescriptors) {
+	for i, mdp := range mdps {
+		md := mds.Get(i)
+		resolveFields(mdp.GetField(), md.Fields())
+		resolveFields(mdp.GetExtension(), md.Extensions())
+		resolveMessages(mdp.GetNestedType(), md.Messages())
+	}
+}
+
+func resolveFields(fdps []*descriptorpb.FieldDescriptorProto, fds protoreflect.ExtensionDescriptors) {
+	for i, fdp := range fdps {
+		fd := fds.Get(i)
+		switch {
+		case fd.Enum() != nil:
+			fdp.Type = descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum()
+			fdp.TypeName = proto.String("." + string(fd.Enum().FullName()))
+		case fd.Message() != nil:
+			if fdp.Type == nil {
+				fdp.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+			}
+			fdp.TypeName = proto.String("." + string(fd.Message().FullName()))
+		}
+		if fdp.Extendee != nil {
+			fdp.Extendee = proto.String("." + string(fd.ContainingMessage().FullName()))
+		}
+		if fdp.JsonName == nil {
+			fdp.JsonName = proto.String(strs.JSONCamelCase(fdp.GetName()))
+		}
+	}
+}
+
+// optionsScope is an options message along with the full name of
+// the scope used to resolve extension names within it.
+type optionsScope struct {
+	opts  proto.Message
+	scope protoreflect.FullName
+}
+
+// interpretOptions interprets all uninterpreted options in fdp.
+func (l *linker) interpretOptions(fdp *descriptorpb.FileDescriptorProto, fd protoreflect.FileDescriptor) error {
+	// Options may reference extensions declared in the file itself.
+	self := new(protoregistry.Files)
+	if err := self.RegisterFile(fd); err != nil {
+		return err
+	}
+
+	pkg := fd.Package()
+	all := []optionsScope{{fdp.Options, pkg}}
+	var addFields func([]*descriptorpb.FieldDescriptorProto, protoreflect.FullName)
+	addFields = func(fdps []*descriptorpb.FieldDescriptorProto, scope protoreflect.FullName) {
+		for _, fdp := range fdps {
+			all = append(all, optionsScope{fdp.Options, scope})
+		}
+	}
+	var addEnums func([]*descriptorpb.EnumDescriptorProto, protoreflect.FullName)
+	addEnums = func(edps []*descriptorpb.EnumDescriptorProto, scope protoreflect.FullName) {
+		for _, edp := range edps {
+			all = append(all, optionsScope{edp.Options, scope.Append(protoreflect.Name(edp.GetName()))})
+			for _, vdp := range edp.GetValue() {
+				all = append(all, optionsScope{vdp.Options, scope.Append(protoreflect.Name(edp.GetName()))})
+			}
+		}
+	}
+	var addMessages func([]*descriptorpb.DescriptorProto, protoreflect.FullName)
+	addMessages = func(mdps []*descriptorpb.DescriptorProto, scope protoreflect.FullName) {
+		for _, mdp := range mdps {
+			name := scope.Append(protoreflect.Name(mdp.GetName()))
+			all = append(all, optionsScope{mdp.Options, name})
+			addFields(mdp.GetField(), name)
+			addFields(mdp.GetExtension(), name)
+			for _, odp := range mdp.GetOneofDecl() {
+				all = append(all, optionsScope{odp.Options, name})
+			}
+			for _, rdp := range mdp.GetExtensionRange() {
+				all = append(all, optionsScope{rdp.Options, name})
+			}
+			addEnums(mdp.GetEnumType(), name)
+			addMessages(mdp.GetNestedType(), name)
+		}
+	}
+	addMessages(fdp.GetMessageType(), pkg)
+	addEnums(fdp.GetEnumType(), pkg)
+	addFields(fdp.GetExtension(), pkg)
+	for _, sdp := range fdp.GetService() {
+		name := pkg.Append(protoreflect.Name(sdp.GetName()))
+		all = append(all, optionsScope{sdp.Options, name})
+		for _, mdp := range sdp.GetMethod() {
+			all = append(all, optionsScope{mdp.Options, name})
+		}
+	}
+
+	seen := map[proto.Message]bool{} // extension range options may be shared
+	for _, o := range all {
+		m := o.opts.ProtoReflect()
+		if !m.IsValid() || seen[o.opts] {
+			continue
+		}
+		seen[o.opts] = true
+		fd := m.Descriptor().Fields().ByName("uninterpreted_option")
+		list := m.Get(fd).List()
+		if list.Len() == 0 {
+			continue
+		}
+		for i := 0; i < list.Len(); i++ {
+			uo := list.Get(i).Message().Interface().(*descriptorpb.UninterpretedOption)
+			err := interpretOption(m, uo, func(name string) (protoreflect.ExtensionType, error) {
+				return l.findExtension(self, o.scope, name)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		m.Clear(fd)
+	}
+	return nil
+}
+
+// findExtension resolves the extension with the given name,
+// which is relative to scope unless it begins with a dot.
+// Declarations in self take precedence over those in other files.
+func (l *linker) findExtension(self *protoregistry.Files, scope protoreflect.FullName, name string) (protoreflect.ExtensionType, error) {
+	var candidates []protoreflect.FullName
+	if name[0] == '.' {
+		candidates = append(candidates, protoreflect.FullName(name[1:]))
+	} else {
+		for s := scope; ; s = s.Parent() {
+			if s == "" {
+				candidates = append(candidates, protoreflect.FullName(name))
+				break
+			}
+			candidates = append(candidates, s+"."+protoreflect.FullName(name))
+		}
+	}
+	for _, c := range candidates {
+		d, err := self.FindDescriptorByName(c)
+		if err != nil {
+			d, err = l.FindDescriptorByName(c)
+		}
+		if err != nil {
+			continue
+		}
+		xd, ok := d.(protoreflect.ExtensionDescriptor)
+		if !ok || !xd.IsExtension() {
+			return nil, errors.New("%v is not an extension", c)
+		}
+		if xtd, ok := xd.(protoreflect.ExtensionTypeDescriptor); ok {
+			return xtd.Type(), nil
+		}
+		if xt, err := protoregistry.GlobalTypes.FindExtensionByName(c); err == nil && xt.TypeDescriptor().ParentFile().Path() == xd.ParentFile().Path() {
+			return xt, nil
+		}
+		return dynamicpb.NewExtensionType(xd), nil
+	}
+	return nil, errors.New("unknown extension %q", name)
+}
@@ -0,0 +1,349 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoparse_test
+
+import (
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/compiler/protoparse"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	annotationpb "google.golang.org/protobuf/internal/testprotos/annotation"
+	editionsfuzzpb "google.golang.org/protobuf/internal/testprotos/editionsfuzztest"
+	enumspb "google.golang.org/protobuf/internal/testprotos/enums"
+	newspb "google.golang.org/protobuf/internal/testprotos/news"
+	requiredpb "google.golang.org/protobuf/internal/testprotos/required"
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+	test3pb "google.golang.org/protobuf/internal/testprotos/test3"
+	testeditionspb "google.golang.org/protobuf/internal/testprotos/testeditions"
+	"google.golang.org/protobuf/types/gofeaturespb"
+)
+
+// TestGenerated verifies that parsing the source of test protos yields
+// the same descriptors as the ones embedded in the generated code.
+func TestGenerated(t *testing.T) {
+	for _, want := range []protoreflect.FileDescriptor{
+		annotationpb.File_internal_testprotos_annotation_annotation_proto,
+		enumspb.File_internal_testprotos_enums_enums_proto,
+		editionsfuzzpb.File_internal_testprotos_editionsfuzztest_test2_proto,
+		editionsfuzzpb.File_internal_testprotos_editionsfuzztest_test2editions_proto,
+		editionsfuzzpb.File_internal_testprotos_editionsfuzztest_test3_proto,
+		editionsfuzzpb.File_internal_testprotos_editionsfuzztest_test3editions_proto,
+		newspb.File_internal_testprotos_news_news_proto,
+		requiredpb.File_internal_testprotos_required_required_proto,
+		testpb.File_internal_testprotos_test_test_import_proto,
+		test3pb.File_internal_testprotos_test3_test_proto,
+		test3pb.File_internal_testprotos_test3_test_extension_proto,
+		testeditionspb.File_internal_testprotos_testeditions_test_proto,
+		testeditionspb.File_internal_testprotos_testeditions_test_extension_proto,
+		testeditionspb.File_internal_testprotos_testeditions_test_extension2_proto,
+		gofeaturespb.File_google_protobuf_go_features_proto,
+	} {
+		t.Run(want.Path(), func(t *testing.T) {
+			p := protoparse.Parser{ImportPaths: []string{"../..", "../../src"}}
+			fds, err := p.ParseFiles(want.Path())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := protodesc.ToFileDescriptorProto(fds[0])
+			if diff := cmp.Diff(protodesc.ToFileDescriptorProto(want), got, protocmp.Transform()); diff != "" {
+				t.Errorf("descriptor mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	const src = `// Detached comment
+// spanning two lines.
+
+// Leading comment for syntax.
+syntax = "proto3";
+
+package foo.bar;
+
+import "google/protobuf/descriptor.proto";
+
+// Leading comment for M.
+message M { // Trailing comment for M.
+  // Leading comment for a.
+  optional int32 a = 1; // Trailing comment for a.
+  map<string, M> b_c = 2 [json_name = "BC", deprecated = true];
+  oneof o {
+    bytes d = 3;
+    E e = 4;
+  }
+  reserved 5 to 7, 10;
+  reserved "x", "y";
+}
+
+enum E {
+  option allow_alias = true;
+  E_ZERO = 0;
+  E_ONE = 1;
+  E_UNO = 1 [deprecated = true];
+  reserved -2 to -1;
+}
+
+service S {
+  rpc Get(M) returns (stream .foo.bar.M) { option idempotency_level = NO_SIDE_EFFECTS; }
+}
+
+extend google.protobuf.FieldOptions {
+  string opt = 50000;
+}
+`
+	got, err := protoparse.Parse("foo.proto", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &descriptorpb.FileDescriptorProto{}
+	if err := prototext.Unmarshal([]byte(`
+		name: "foo.proto"
+		package: "foo.bar"
+		dependency: "google/protobuf/descriptor.proto"
+		syntax: "proto3"
+		message_type: {
+			name: "M"
+			field: {name: "a" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32 proto3_optional: true oneof_index: 1}
+			field: {name: "b_c" number: 2 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: "BCEntry" json_name: "BC" options: {deprecated: true}}
+			field: {name: "d" number: 3 label: LABEL_OPTIONAL type: TYPE_BYTES oneof_index: 0}
+			field: {name: "e" number: 4 label: LABEL_OPTIONAL type_name: "E" oneof_index: 0}
+			nested_type: {
+				name: "BCEntry"
+				field: {name: "key" json_name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING}
+				field: {name: "value" json_name: "value" number: 2 label: LABEL_OPTIONAL type_name: "M"}
+				options: {map_entry: true}
+			}
+			oneof_decl: {name: "o"}
+			oneof_decl: {name: "_a"}
+			reserved_range: {start: 5 end: 8}
+			reserved_range: {start: 10 end: 11}
+			reserved_name: ["x", "y"]
+		}
+		enum_type: {
+			name: "E"
+			value: {name: "E_ZERO" number: 0}
+			value: {name: "E_ONE" number: 1}
+			value: {name: "E_UNO" number: 1 options: {deprecated: true}}
+			options: {allow_alias: true}
+			reserved_range: {start: -2 end: -1}
+		}
+		service: {
+			name: "S"
+			method: {name: "Get" input_type: "M" output_type: ".foo.bar.M" server_streaming: true options: {idempotency_level: NO_SIDE_EFFECTS}}
+		}
+		extension: {name: "opt" number: 50000 label: LABEL_OPTIONAL type: TYPE_STRING extendee: "google.protobuf.FieldOptions"}
+	`), want); err != nil {
+		t.Fatal(err)
+	}
+	locs := got.GetSourceCodeInfo().GetLocation()
+	got.SourceCodeInfo = nil
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+	}
+
+	wantLocs := []*descriptorpb.SourceCodeInfo_Location{{
+		Path:                    []int32{12},
+		Span:                    []int32{4, 0, 18},
+		LeadingComments:         proto.String(" Leading comment for syntax.\n"),
+		LeadingDetachedComments: []string{" Detached comment\n spanning two lines.\n"},
+	}, {
+		Path: []int32{4, 0, 2, 0},
+		Span: []int32{13, 2, 23},
+
+		LeadingComments:  proto.String(" Leading comment for a.\n"),
+		TrailingComments: proto.String(" Trailing comment for a.\n"),
+	}, {
+		Path:             []int32{4, 0},
+		Span:             []int32{11, 0, 21, 1},
+		LeadingComments:  proto.String(" Leading comment for M.\n"),
+		TrailingComments: proto.String(" Trailing comment for M.\n"),
+	}}
+	for _, want := range wantLocs {
+		var found *descriptorpb.SourceCodeInfo_Location
+		for _, loc := range locs {
+			if cmp.Equal(loc.GetPath(), want.GetPath()) {
+				found = loc
+			}
+		}
+		if diff := cmp.Diff(want, found, protocmp.Transform()); diff != "" {
+			t.Errorf("location %v mismatch (-want +got):\n%s", want.GetPath(), diff)
+		}
+	}
+}
+
+func TestParseEditionLocation(t *testing.T) {
+	const src = "// Comment for edition.\nedition = \"2023\";\n"
+	fd, err := protoparse.Parse("foo.proto", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*descriptorpb.SourceCodeInfo_Location{{
+		Path:            []int32{12},
+		Span:            []int32{1, 0, 17},
+		LeadingComments: proto.String(" Comment for edition.\n"),
+	}}
+	got := fd.GetSourceCodeInfo().GetLocation()
+	if len(got) > 0 && len(got[0].GetPath()) == 0 {
+		got = got[1:] // the location of the entire file
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("locations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		wantErr string
+	}{
+		{`syntax = "proto4";`, `test.proto:1:10: unknown syntax "proto4"`},
+		{`message M { int32 a = 1; }`, `test.proto:1:13: fields must have a label in proto2`},
+		{`syntax = "proto3"; message M { required int32 a = 1; }`, `required fields are not supported in proto3`},
+		{`edition = "2023"; message M { optional int32 a = 1; }`, `label "optional" is not supported in editions`},
+		{`syntax = "proto3"; message M { int32 a = 0; }`, `test.proto:1:42: integer 0 out of range`},
+		{`message M {`, `unexpected end of file, expected "}"`},
+		{`message M { optional string s = 1 [default = 'abc]; }`, `test.proto:1:46: unterminated string literal`},
+		{`/* unterminated`, `test.proto:1:1: unterminated block comment`},
+		{`option java_package = 5;`, `option java_package: invalid value for string field`},
+		{`option no_such_option = true;`, `option no_such_option: unknown option "no_such_option"`},
+		{`syntax = "proto3"; message M { map<float, int32> m = 1; }`, `invalid map key type "float"`},
+		{`syntax = "proto3"; message M { int32 a = 19000; }`, `test.proto:1:42: field numbers 19000 through 19999 are reserved`},
+		{`message M { extensions 1 to max; } extend M { optional int32 x = 19999; }`, `field numbers 19000 through 19999 are reserved`},
+		{`syntax = "proto3"; message M { map<string, int32> m = 19500; }`, `field numbers 19000 through 19999 are reserved`},
+	}
+	for _, tt := range tests {
+		_, err := protoparse.Parse("test.proto", []byte(tt.src))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.src, err, tt.wantErr)
+		}
+	}
+
+	// Ranges may cover the reserved numbers.
+	src := `message M { reserved 18000 to 19500; extensions 19501 to max; optional int32 a = 1; }`
+	if _, err := protoparse.Parse("test.proto", []byte(src)); err != nil {
+		t.Errorf("Parse(%q) error: %v", src, err)
+	}
+}
+
+func TestParseFiles(t *testing.T) {
+	files := map[string]string{
+		"options.proto": `
+			syntax = "proto2";
+			package opts;
+			import "google/protobuf/descriptor.proto";
+			message Rule {
+				optional string name = 1;
+				repeated int32 values = 2;
+			}
+			extend google.protobuf.MessageOptions {
+				optional Rule rule = 50000;
+				repeated string tag = 50001;
+			}
+			extend google.protobuf.FieldOptions {
+				optional int64 limit = 50000;
+			}
+			extend google.protobuf.FileOptions {
+				optional string file_tag = 50000;
+			}
+			option (file_tag) = "self";
+		`,
+		"test.proto": `
+			edition = "2023";
+			package test;
+			import "options.proto";
+			option features.field_presence = IMPLICIT;
+			message M {
+				option (opts.rule) = { name: "r" values: [1, 2] };
+				option (opts.rule).values = 3;
+				option (.opts.tag) = "a";
+				option (opts.tag) = "b";
+				int64 x = 1 [(opts.limit) = -5, features.field_presence = EXPLICIT];
+				N n = 2 [features.message_encoding = DELIMITED];
+				message N {}
+			}
+		`,
+	}
+	p := protoparse.Parser{
+		Accessor: func(path string) (io.ReadCloser, error) {
+			src, ok := files[path]
+			if !ok {
+				return nil, fs.ErrNotExist
+			}
+			return io.NopCloser(strings.NewReader(src)), nil
+		},
+	}
+	fds, err := p.ParseFiles("test.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd := fds[0]
+	md := fd.Messages().ByName("M")
+	if got := md.Fields().ByName("x").HasPresence(); !got {
+		t.Errorf("field x: HasPresence() = false, want true")
+	}
+	if got := md.Fields().ByName("n").Kind(); got != protoreflect.GroupKind {
+		t.Errorf("field n: Kind() = %v, want %v", got, protoreflect.GroupKind)
+	}
+	if got := md.Fields().ByName("n").Message().FullName(); got != "test.M.N" {
+		t.Errorf("field n: Message().FullName() = %v, want test.M.N", got)
+	}
+
+	// Custom options are not linked into this binary,
+	// so they are retained as unknown fields.
+	linked := new(protoregistry.Files)
+	if err := linked.RegisterFile(fd.Imports().Get(0).FileDescriptor); err != nil {
+		t.Fatal(err)
+	}
+	types := dynamicpb.NewTypes(linked)
+	b, err := proto.Marshal(md.Options())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(descriptorpb.MessageOptions)
+	if err := (proto.UnmarshalOptions{Resolver: types}).Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	want := new(descriptorpb.MessageOptions)
+	if err := (prototext.UnmarshalOptions{Resolver: types}).Unmarshal([]byte(`
+		[opts.rule]: {name: "r" values: [1, 2, 3]}
+		[opts.tag]: ["a", "b"]
+	`), want); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("message options mismatch (-want +got):\n%s", diff)
+	}
+
+	// Options may use extensions declared in the same file.
+	xt, err := types.FindExtensionByName("opts.file_tag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = proto.Marshal(fd.Imports().Get(0).Options())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileOpts := new(descriptorpb.FileOptions)
+	if err := (proto.UnmarshalOptions{Resolver: types}).Unmarshal(b, fileOpts); err != nil {
+		t.Fatal(err)
+	}
+	if got := proto.GetExtension(fileOpts, xt); got != "self" {
+		t.Errorf("file option (file_tag) = %v, want self", got)
+	}
+}
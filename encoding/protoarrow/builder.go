@@ -0,0 +1,291 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoarrow
+
+import (
+	"encoding/binary"
+	"math"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/internal/order"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Array is an Arrow array, which holds the values of a column or of a
+// child field of a nested type.
+type Array struct {
+	Type      *DataType
+	Len       int
+	NullCount int
+
+	// Buffers are the buffers of the array in the Arrow columnar format.
+	// The first buffer is the validity bitmap, which is nil if NullCount
+	// is zero. It is followed by:
+	//   - the bitmap of the values for Bool arrays,
+	//   - the little-endian values for other fixed-width types,
+	//     including Timestamp,
+	//   - the int32 offsets and the data for String and Binary arrays,
+	//   - the int32 offsets for List and Map arrays.
+	// Struct arrays have no other buffers.
+	Buffers [][]byte
+
+	// Children are the arrays of the child fields of List, Struct,
+	// and Map arrays, which correspond to the Fields of the Type.
+	Children []*Array
+}
+
+// IsNull reports whether the value at index i is null.
+func (a *Array) IsNull(i int) bool {
+	return a.NullCount > 0 && a.Buffers[0][i/8]&(1<<(i%8)) == 0
+}
+
+// RecordBatch is an Arrow record batch,
+// which holds a column for each field of its schema.
+type RecordBatch struct {
+	Schema  *Schema
+	NumRows int
+	Columns []*Array
+}
+
+// Builder builds record batches from messages of a single type.
+type Builder struct {
+	md     protoreflect.MessageDescriptor
+	schema *Schema
+	root   *arrayBuilder
+}
+
+// NewBuilder returns a Builder for record batches holding messages
+// of type md. It reports an error if md is recursive.
+func NewBuilder(md protoreflect.MessageDescriptor) (*Builder, error) {
+	s, err := NewSchema(md)
+	if err != nil {
+		return nil, err
+	}
+	return &Builder{
+		md:     md,
+		schema: s,
+		root:   newArrayBuilder(Field{Type: &DataType{ID: Struct, Fields: s.Fields}}),
+	}, nil
+}
+
+// Schema returns the schema of the record batches.
+func (b *Builder) Schema() *Schema {
+	return b.schema
+}
+
+// Len reports the number of messages appended since the last record batch.
+func (b *Builder) Len() int {
+	return b.root.len
+}
+
+// Append appends a row for m, which must be of the type of the Builder
+// and must have all required fields set.
+func (b *Builder) Append(m proto.Message) error {
+	mr := m.ProtoReflect()
+	if md := mr.Descriptor(); md.FullName() != b.md.FullName() {
+		return errors.New("cannot append %v message to batch of %v messages", md.FullName(), b.md.FullName())
+	}
+	if err := proto.CheckInitialized(m); err != nil {
+		return err
+	}
+	b.root.appendMessage(mr)
+	return nil
+}
+
+// NewRecordBatch returns a record batch holding the messages appended since
+// the last record batch, and resets the Builder for the next one.
+func (b *Builder) NewRecordBatch() *RecordBatch {
+	a := b.root.finish()
+	return &RecordBatch{Schema: b.schema, NumRows: a.Len, Columns: a.Children}
+}
+
+// arrayBuilder builds an Array.
+type arrayBuilder struct {
+	typ      *DataType
+	nullable bool
+	len      int
+	nulls    int
+	validity []byte
+	values   []byte // values or offsets
+	data     []byte // data of String and Binary arrays
+	children []*arrayBuilder
+}
+
+func newArrayBuilder(f Field) *arrayBuilder {
+	b := &arrayBuilder{typ: f.Type, nullable: f.Nullable}
+	b.reset()
+	for _, cf := range f.Type.Fields {
+		b.children = append(b.children, newArrayBuilder(cf))
+	}
+	return b
+}
+
+func (b *arrayBuilder) reset() {
+	b.len, b.nulls = 0, 0
+	b.validity, b.values, b.data = nil, nil, nil
+	if hasOffsets(b.typ.ID) {
+		b.values = binary.LittleEndian.AppendUint32(nil, 0)
+	}
+}
+
+func (b *arrayBuilder) finish() *Array {
+	a := &Array{Type: b.typ, Len: b.len, NullCount: b.nulls}
+	var validity []byte
+	if b.nulls > 0 {
+		validity = b.validity
+	}
+	switch b.typ.ID {
+	case Struct:
+		a.Buffers = [][]byte{validity}
+	case String, Binary:
+		a.Buffers = [][]byte{validity, b.values, b.data}
+	default:
+		a.Buffers = [][]byte{validity, b.values}
+	}
+	for _, c := range b.children {
+		a.Children = append(a.Children, c.finish())
+	}
+	b.reset()
+	return a
+}
+
+// appendValidity appends an entry to the validity bitmap,
+// completing the value at the current index.
+func (b *arrayBuilder) appendValidity(valid bool) {
+	b.validity = appendBit(b.validity, b.len, valid)
+	if !valid {
+		b.nulls++
+	}
+	b.len++
+}
+
+func (b *arrayBuilder) appendOffset(n int) {
+	b.values = binary.LittleEndian.AppendUint32(b.values, uint32(n))
+}
+
+func (b *arrayBuilder) appendMessage(m protoreflect.Message) {
+	fds := m.Descriptor().Fields()
+	for i, c := range b.children {
+		c.appendField(m, fds.Get(i))
+	}
+	b.appendValidity(true)
+}
+
+func (b *arrayBuilder) appendField(m protoreflect.Message, fd protoreflect.FieldDescriptor) {
+	switch {
+	case fd.IsList():
+		list := m.Get(fd).List()
+		item := b.children[0]
+		for i := 0; i < list.Len(); i++ {
+			item.appendValue(fd, list.Get(i))
+		}
+		b.appendOffset(item.len)
+		b.appendValidity(true)
+	case fd.IsMap():
+		entries := b.children[0]
+		keys, values := entries.children[0], entries.children[1]
+		order.RangeEntries(m.Get(fd).Map(), order.GenericKeyOrder, func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			keys.appendValue(fd.MapKey(), k.Value())
+			values.appendValue(fd.MapValue(), v)
+			entries.appendValidity(true)
+			return true
+		})
+		b.appendOffset(entries.len)
+		b.appendValidity(true)
+	case b.nullable && !m.Has(fd):
+		b.appendAbsent()
+	default:
+		b.appendValue(fd, m.Get(fd))
+	}
+}
+
+// appendValue appends a singular value of the field.
+func (b *arrayBuilder) appendValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+	if md := fd.Message(); md != nil && isWrapper(md) {
+		fd = md.Fields().ByNumber(genid.WrapperValue_Value_field_number)
+		v = v.Message().Get(fd)
+	}
+	switch b.typ.ID {
+	case Bool:
+		b.values = appendBit(b.values, b.len, v.Bool())
+	case Int32:
+		if fd.Kind() == protoreflect.EnumKind {
+			b.values = binary.LittleEndian.AppendUint32(b.values, uint32(v.Enum()))
+		} else {
+			b.values = binary.LittleEndian.AppendUint32(b.values, uint32(v.Int()))
+		}
+	case Int64:
+		b.values = binary.LittleEndian.AppendUint64(b.values, uint64(v.Int()))
+	case Uint32:
+		b.values = binary.LittleEndian.AppendUint32(b.values, uint32(v.Uint()))
+	case Uint64:
+		b.values = binary.LittleEndian.AppendUint64(b.values, v.Uint())
+	case Float32:
+		b.values = binary.LittleEndian.AppendUint32(b.values, math.Float32bits(float32(v.Float())))
+	case Float64:
+		b.values = binary.LittleEndian.AppendUint64(b.values, math.Float64bits(v.Float()))
+	case String:
+		b.data = append(b.data, v.String()...)
+		b.appendOffset(len(b.data))
+	case Binary:
+		b.data = append(b.data, v.Bytes()...)
+		b.appendOffset(len(b.data))
+	case Timestamp:
+		m := v.Message()
+		fds := m.Descriptor().Fields()
+		secs := m.Get(fds.ByNumber(genid.Timestamp_Seconds_field_number)).Int()
+		nanos := m.Get(fds.ByNumber(genid.Timestamp_Nanos_field_number)).Int()
+		b.values = binary.LittleEndian.AppendUint64(b.values, uint64(secs*1e6+nanos/1e3))
+	case Struct:
+		b.appendMessage(v.Message())
+		return
+	}
+	b.appendValidity(true)
+}
+
+// appendAbsent appends a null if the array is nullable and a zero value
+// otherwise. It is used for unpopulated fields and for the child fields
+// of null structs, whose lengths must match the length of the struct.
+func (b *arrayBuilder) appendAbsent() {
+	switch b.typ.ID {
+	case Bool:
+		b.values = appendBit(b.values, b.len, false)
+	case Int32, Uint32, Float32:
+		b.values = append(b.values, 0, 0, 0, 0)
+	case Int64, Uint64, Float64, Timestamp:
+		b.values = append(b.values, 0, 0, 0, 0, 0, 0, 0, 0)
+	case String, Binary:
+		b.appendOffset(len(b.data))
+	case List, Map:
+		b.appendOffset(b.children[0].len)
+	case Struct:
+		for _, c := range b.children {
+			c.appendAbsent()
+		}
+	}
+	b.appendValidity(!b.nullable)
+}
+
+// hasOffsets reports whether arrays of the type have an offsets buffer.
+func hasOffsets(id TypeID) bool {
+	switch id {
+	case String, Binary, List, Map:
+		return true
+	}
+	return false
+}
+
+// appendBit sets bit i of the bitmap b, which has i bits.
+func appendBit(b []byte, i int, bit bool) []byte {
+	if i%8 == 0 {
+		b = append(b, 0)
+	}
+	if bit {
+		b[i/8] |= 1 << (i % 8)
+	}
+	return b
+}
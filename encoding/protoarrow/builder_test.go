@@ -0,0 +1,164 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoarrow_test
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"google.golang.org/protobuf/encoding/protoarrow"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
+)
+
+func int32s(vs ...int32) []byte {
+	var b []byte
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint32(b, uint32(v))
+	}
+	return b
+}
+
+func int64s(vs ...int64) []byte {
+	var b []byte
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint64(b, uint64(v))
+	}
+	return b
+}
+
+func TestBuilder(t *testing.T) {
+	mt := rowType(t)
+	b, err := protoarrow.NewBuilder(mt.Descriptor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`{"id": 1, "name": "a", "values": ["1", "2"], "counts": {"y": 2, "x": 1},
+		  "inner": {"flag": true, "color": "GREEN"}, "time": "1970-01-01T00:00:01.000002Z", "wrapped": "5"}`,
+		`{"id": 2}`,
+		`{"id": 3, "name": "", "values": ["3"], "inner": {}, "wrapped": "0"}`,
+	} {
+		m := mt.New().Interface()
+		if err := protojson.Unmarshal([]byte(s), m); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Append(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if b.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", b.Len())
+	}
+	rb := b.NewRecordBatch()
+	if b.Len() != 0 {
+		t.Errorf("Len() after NewRecordBatch = %d, want 0", b.Len())
+	}
+	if rb.NumRows != 3 || rb.Schema != b.Schema() {
+		t.Errorf("NewRecordBatch() = {NumRows: %d, Schema: %v}, want 3 rows of the builder schema", rb.NumRows, rb.Schema)
+	}
+
+	want := []*protoarrow.Array{{
+		// id
+		Len:     3,
+		Buffers: [][]byte{nil, int32s(1, 2, 3)},
+	}, {
+		// name
+		Len: 3, NullCount: 1,
+		Buffers: [][]byte{{0b101}, int32s(0, 1, 1, 1), []byte("a")},
+	}, {
+		// values
+		Len:     3,
+		Buffers: [][]byte{nil, int32s(0, 2, 2, 3)},
+		Children: []*protoarrow.Array{{
+			Len:     3,
+			Buffers: [][]byte{nil, int64s(1, 2, 3)},
+		}},
+	}, {
+		// counts
+		Len:     3,
+		Buffers: [][]byte{nil, int32s(0, 2, 2, 2)},
+		Children: []*protoarrow.Array{{
+			Len:     2,
+			Buffers: [][]byte{nil},
+			Children: []*protoarrow.Array{{
+				Len:     2,
+				Buffers: [][]byte{nil, int32s(0, 1, 2), []byte("xy")},
+			}, {
+				Len:     2,
+				Buffers: [][]byte{nil, int32s(1, 2)},
+			}},
+		}},
+	}, {
+		// inner
+		Len: 3, NullCount: 1,
+		Buffers: [][]byte{{0b101}},
+		Children: []*protoarrow.Array{{
+			Len:     3,
+			Buffers: [][]byte{nil, {0b001}},
+		}, {
+			Len:     3,
+			Buffers: [][]byte{nil, int32s(1, 0, 0)},
+		}},
+	}, {
+		// time
+		Len: 3, NullCount: 2,
+		Buffers: [][]byte{{0b001}, int64s(1000002, 0, 0)},
+	}, {
+		// wrapped
+		Len: 3, NullCount: 1,
+		Buffers: [][]byte{{0b101}, int64s(5, 0, 0)},
+	}}
+	if diff := cmp.Diff(want, rb.Columns, cmpopts.IgnoreFields(protoarrow.Array{}, "Type"), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("columns mismatch (-want +got):\n%s", diff)
+	}
+	for i, a := range rb.Columns {
+		if a.Type != rb.Schema.Fields[i].Type {
+			t.Errorf("column %d has type %v, want %v", i, a.Type, rb.Schema.Fields[i].Type)
+		}
+	}
+	if !rb.Columns[1].IsNull(1) || rb.Columns[1].IsNull(2) || rb.Columns[0].IsNull(1) {
+		t.Errorf("IsNull reports wrong values")
+	}
+
+	// The builder is reset for the next batch.
+	if err := b.Append(mt.New().Interface()); err != nil {
+		t.Fatal(err)
+	}
+	if rb := b.NewRecordBatch(); rb.NumRows != 1 || rb.Columns[2].Len != 1 || string(rb.Columns[2].Buffers[1]) != string(int32s(0, 0)) {
+		t.Errorf("second record batch = %+v, want a single empty row", rb.Columns[2])
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	b, err := protoarrow.NewBuilder((&pb2.IndirectRequired{}).ProtoReflect().Descriptor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		msg     proto.Message
+		wantErr string
+	}{
+		{&pb2.Scalars{}, "cannot append pb2.Scalars message"},
+		{&pb2.IndirectRequired{OptNested: &pb2.NestedWithRequired{}}, "required field"},
+	} {
+		if err := b.Append(tt.msg); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Append(%v) error = %v, want %q", tt.msg, err, tt.wantErr)
+		}
+	}
+	if b.Len() != 0 {
+		t.Errorf("Len() = %d after failed appends, want 0", b.Len())
+	}
+
+	if _, err := protoarrow.NewBuilder((&pb2.Nests{}).ProtoReflect().Descriptor()); err == nil {
+		t.Errorf("NewBuilder(pb2.Nests) succeeded, want recursive message error")
+	}
+}
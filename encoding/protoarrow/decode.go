@@ -0,0 +1,149 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoarrow
+
+import (
+	"encoding/binary"
+	"math"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Unmarshal stores the values of a row of the record batch in m.
+// It will clear the message first before setting the fields.
+// The provided message must be mutable (e.g., a non-nil pointer to a message).
+//
+// The columns are matched to the fields of m by name and must have the types
+// of the fields in the schema returned by [NewSchema]. Null values leave the
+// field unpopulated. Only flat record batches are supported, whose columns
+// are not of List, Struct, or Map types.
+func Unmarshal(rb *RecordBatch, row int, m proto.Message) error {
+	proto.Reset(m)
+	if row < 0 || row >= rb.NumRows {
+		return errors.New("row %d out of range for record batch of %d rows", row, rb.NumRows)
+	}
+	mr := m.ProtoReflect()
+	md := mr.Descriptor()
+	for i, f := range rb.Schema.Fields {
+		fd := md.Fields().ByName(protoreflect.Name(f.Name))
+		if fd == nil {
+			return errors.New("column %v has no field in %v", f.Name, md.FullName())
+		}
+		switch f.Type.ID {
+		case List, Struct, Map:
+			return errors.New("cannot unmarshal column %v of type %v: only flat record batches are supported", f.Name, f.Type)
+		}
+		if t, err := newType(fd, make(map[protoreflect.FullName]bool)); err != nil || t.ID != f.Type.ID || fd.IsList() || fd.IsMap() {
+			return errors.New("column %v of type %v does not match field %v", f.Name, f.Type, fd.FullName())
+		}
+		a := rb.Columns[i]
+		if a.IsNull(row) {
+			continue
+		}
+		v, err := arrayValue(a, row, fd, func() protoreflect.Value { return mr.NewField(fd) })
+		if err != nil {
+			return errors.New("column %v: %v", f.Name, err)
+		}
+		mr.Set(fd, v)
+	}
+	return proto.CheckInitialized(m)
+}
+
+// arrayValue returns the value at index i of the array as a value of
+// the field. For message fields, newValue returns a new message value.
+func arrayValue(a *Array, i int, fd protoreflect.FieldDescriptor, newValue func() protoreflect.Value) (protoreflect.Value, error) {
+	if md := fd.Message(); md != nil {
+		v := newValue()
+		m := v.Message()
+		fds := md.Fields()
+		if md.FullName() == genid.Timestamp_message_fullname {
+			n, err := fixed(a, i, 8)
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			secs, micros := int64(n)/1e6, int64(n)%1e6
+			if micros < 0 {
+				secs, micros = secs-1, micros+1e6
+			}
+			m.Set(fds.ByNumber(genid.Timestamp_Seconds_field_number), protoreflect.ValueOfInt64(secs))
+			m.Set(fds.ByNumber(genid.Timestamp_Nanos_field_number), protoreflect.ValueOfInt32(int32(micros*1e3)))
+			return v, nil
+		}
+		// The message is a wrapper.
+		vfd := fds.ByNumber(genid.WrapperValue_Value_field_number)
+		wv, err := arrayValue(a, i, vfd, nil)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		m.Set(vfd, wv)
+		return v, nil
+	}
+
+	switch a.Type.ID {
+	case Bool:
+		if len(a.Buffers[1]) <= i/8 {
+			return protoreflect.Value{}, errors.New("truncated values buffer")
+		}
+		return protoreflect.ValueOfBool(a.Buffers[1][i/8]&(1<<(i%8)) != 0), nil
+	case String, Binary:
+		if len(a.Buffers[1]) < 4*(i+2) {
+			return protoreflect.Value{}, errors.New("truncated offsets buffer")
+		}
+		start := binary.LittleEndian.Uint32(a.Buffers[1][4*i:])
+		end := binary.LittleEndian.Uint32(a.Buffers[1][4*(i+1):])
+		if start > end || int(end) > len(a.Buffers[2]) {
+			return protoreflect.Value{}, errors.New("invalid offsets %d to %d", start, end)
+		}
+		b := a.Buffers[2][start:end]
+		if a.Type.ID == String {
+			return protoreflect.ValueOfString(string(b)), nil
+		}
+		return protoreflect.ValueOfBytes(append([]byte(nil), b...)), nil
+	case Int32, Uint32, Float32:
+		n, err := fixed(a, i, 4)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		switch {
+		case fd.Kind() == protoreflect.EnumKind:
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(int32(n))), nil
+		case a.Type.ID == Int32:
+			return protoreflect.ValueOfInt32(int32(n)), nil
+		case a.Type.ID == Uint32:
+			return protoreflect.ValueOfUint32(uint32(n)), nil
+		default:
+			return protoreflect.ValueOfFloat32(math.Float32frombits(uint32(n))), nil
+		}
+	default:
+		n, err := fixed(a, i, 8)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		switch a.Type.ID {
+		case Int64:
+			return protoreflect.ValueOfInt64(int64(n)), nil
+		case Uint64:
+			return protoreflect.ValueOfUint64(n), nil
+		default:
+			return protoreflect.ValueOfFloat64(math.Float64frombits(n)), nil
+		}
+	}
+}
+
+// fixed returns the little-endian value of the given size
+// at index i of the values buffer of the array.
+func fixed(a *Array, i, size int) (uint64, error) {
+	b := a.Buffers[1]
+	if len(b) < size*(i+1) {
+		return 0, errors.New("truncated values buffer")
+	}
+	if size == 4 {
+		return uint64(binary.LittleEndian.Uint32(b[4*i:])), nil
+	}
+	return binary.LittleEndian.Uint64(b[8*i:]), nil
+}
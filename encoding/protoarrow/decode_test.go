@@ -0,0 +1,157 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoarrow_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/protoarrow"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
+)
+
+// newRecordBatch returns a record batch of the messages,
+// which are all of the same type.
+func newRecordBatch(t *testing.T, msgs ...proto.Message) *protoarrow.RecordBatch {
+	t.Helper()
+	b, err := protoarrow.NewBuilder(msgs[0].ProtoReflect().Descriptor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range msgs {
+		if err := b.Append(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return b.NewRecordBatch()
+}
+
+func TestUnmarshal(t *testing.T) {
+	flatType := testMessageType(t, "Flat")
+	var flats []proto.Message
+	for _, s := range []string{
+		`{}`,
+		`{"id": -1, "name": "", "color": "GREEN", "time": "1969-12-31T23:59:59.000999Z",
+		  "wrapped": "0", "ok": true, "data": "AQI=", "score": -0.5, "big": "18446744073709551615"}`,
+		`{"name": "héllo", "color": 42, "wrapped": "-7", "ok": false}`,
+	} {
+		m := flatType.New().Interface()
+		if err := protojson.Unmarshal([]byte(s), m); err != nil {
+			t.Fatal(err)
+		}
+		flats = append(flats, m)
+	}
+
+	tests := []struct {
+		desc string
+		msgs []proto.Message
+	}{{
+		desc: "proto2 scalars",
+		msgs: []proto.Message{
+			&pb2.Scalars{},
+			&pb2.Scalars{
+				OptBool:     proto.Bool(true),
+				OptInt32:    proto.Int32(math.MinInt32),
+				OptInt64:    proto.Int64(math.MinInt64),
+				OptUint32:   proto.Uint32(math.MaxUint32),
+				OptUint64:   proto.Uint64(math.MaxUint64),
+				OptSint32:   proto.Int32(-1),
+				OptSint64:   proto.Int64(-2),
+				OptFixed32:  proto.Uint32(3),
+				OptFixed64:  proto.Uint64(4),
+				OptSfixed32: proto.Int32(-5),
+				OptSfixed64: proto.Int64(-6),
+				OptFloat:    proto.Float32(1.5),
+				OptDouble:   proto.Float64(math.Inf(-1)),
+				OptBytes:    []byte("bytes"),
+				OptString:   proto.String("héllo"),
+			},
+			&pb2.Scalars{OptBool: proto.Bool(false), OptString: proto.String(""), OptBytes: []byte{}},
+		},
+	}, {
+		desc: "enums, timestamps, and wrappers",
+		msgs: flats,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			rb := newRecordBatch(t, tt.msgs...)
+			for i, want := range tt.msgs {
+				got := want.ProtoReflect().New().Interface()
+				if err := protoarrow.Unmarshal(rb, i, got); err != nil {
+					t.Fatalf("Unmarshal(row %d) error: %v", i, err)
+				}
+				if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+					t.Errorf("Unmarshal(row %d) mismatch (-want +got):\n%s", i, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	rowType := rowType(t)
+	flatType := testMessageType(t, "Flat")
+	flats := newRecordBatch(t, flatType.New().Interface())
+	rows := newRecordBatch(t, rowType.New().Interface())
+	int32Type := &protoarrow.DataType{ID: protoarrow.Int32}
+	int64Type := &protoarrow.DataType{ID: protoarrow.Int64}
+
+	tests := []struct {
+		desc    string
+		rb      *protoarrow.RecordBatch
+		row     int
+		msg     proto.Message
+		wantErr string
+	}{{
+		desc:    "nested columns",
+		rb:      rows,
+		msg:     rowType.New().Interface(),
+		wantErr: "only flat record batches are supported",
+	}, {
+		desc:    "row out of range",
+		rb:      flats,
+		row:     1,
+		msg:     flatType.New().Interface(),
+		wantErr: "row 1 out of range",
+	}, {
+		desc:    "column without field",
+		rb:      flats,
+		msg:     &pb2.Scalars{},
+		wantErr: "column id has no field in pb2.Scalars",
+	}, {
+		desc: "column type mismatch",
+		rb: &protoarrow.RecordBatch{
+			Schema:  &protoarrow.Schema{Fields: []protoarrow.Field{{Name: "opt_int32", Type: int64Type}}},
+			NumRows: 1,
+			Columns: []*protoarrow.Array{{Type: int64Type, Len: 1, Buffers: [][]byte{nil, make([]byte, 8)}}},
+		},
+		msg:     &pb2.Scalars{},
+		wantErr: "column opt_int32 of type int64 does not match field pb2.Scalars.opt_int32",
+	}, {
+		desc: "truncated buffer",
+		rb: &protoarrow.RecordBatch{
+			Schema:  &protoarrow.Schema{Fields: []protoarrow.Field{{Name: "opt_int32", Type: int32Type}}},
+			NumRows: 1,
+			Columns: []*protoarrow.Array{{Type: int32Type, Len: 1, Buffers: [][]byte{nil, make([]byte, 2)}}},
+		},
+		msg:     &pb2.Scalars{},
+		wantErr: "column opt_int32: truncated values buffer",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := protoarrow.Unmarshal(tt.rb, tt.row, tt.msg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Unmarshal() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoarrow converts protocol buffer messages to Apache Arrow
+// record batches, for analytics pipelines that process messages in the
+// Arrow columnar format.
+//
+// The package does not depend on an Arrow implementation. The buffers of
+// the arrays of a [RecordBatch] use the memory layout of the Arrow columnar
+// format, so they can be handed to an Arrow library without copying.
+//
+// The Arrow schema for a message type is derived from its descriptor:
+//
+//	╔══════════════════════════════╤═════════════════════════════════════════╗
+//	║ Protobuf                     │ Arrow                                   ║
+//	╠══════════════════════════════╪═════════════════════════════════════════╣
+//	║ message, group               │ struct with a field per message field   ║
+//	║ field with presence          │ nullable field                          ║
+//	║ repeated                     │ list                                    ║
+//	║ map                          │ map                                     ║
+//	║ bool                         │ bool                                    ║
+//	║ int32, sint32, sfixed32      │ int32                                   ║
+//	║ int64, sint64, sfixed64      │ int64                                   ║
+//	║ uint32, fixed32              │ uint32                                  ║
+//	║ uint64, fixed64              │ uint64                                  ║
+//	║ float, double                │ float32, float64                        ║
+//	║ string, bytes                │ utf8, binary                            ║
+//	║ enum                         │ int32 holding the enum number           ║
+//	║ google.protobuf.Timestamp    │ timestamp with microsecond unit and UTC ║
+//	║ wrappers such as             │ the wrapped type                        ║
+//	║ google.protobuf.Int64Value   │                                         ║
+//	╚══════════════════════════════╧═════════════════════════════════════════╝
+//
+// Fields are named by their proto field name and appear in the order of
+// their declaration. Extensions and unknown fields are not represented.
+// Timestamps are truncated to microseconds. Since Arrow schemas cannot be
+// recursive, recursive message types have no Arrow schema.
+package protoarrow
+
+import (
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// TypeID identifies an Arrow data type.
+type TypeID int
+
+const (
+	Bool TypeID = iota + 1
+	Int32
+	Int64
+	Uint32
+	Uint64
+	Float32
+	Float64
+	String // UTF-8 encoded string
+	Binary
+	Timestamp // microseconds since the Unix epoch in UTC, stored as int64
+	List
+	Struct
+	Map
+)
+
+// String returns the name of the type as used by Arrow.
+func (id TypeID) String() string {
+	switch id {
+	case Bool:
+		return "bool"
+	case Int32:
+		return "int32"
+	case Int64:
+		return "int64"
+	case Uint32:
+		return "uint32"
+	case Uint64:
+		return "uint64"
+	case Float32:
+		return "float32"
+	case Float64:
+		return "float64"
+	case String:
+		return "utf8"
+	case Binary:
+		return "binary"
+	case Timestamp:
+		return "timestamp"
+	case List:
+		return "list"
+	case Struct:
+		return "struct"
+	case Map:
+		return "map"
+	default:
+		return "<unknown>"
+	}
+}
+
+// DataType is an Arrow data type.
+type DataType struct {
+	ID TypeID
+
+	// Fields are the child fields of nested types. A List has a single
+	// field named "item". A Struct has a field per member. A Map has a
+	// single field named "entries", which is a Struct with the fields
+	// "key" and "value".
+	Fields []Field
+}
+
+// String formats the type as Arrow does, e.g. "list<item: int32>".
+func (t *DataType) String() string {
+	switch t.ID {
+	case Timestamp:
+		return "timestamp[us, tz=UTC]"
+	case List:
+		return "list<item: " + t.Fields[0].Type.String() + ">"
+	case Struct:
+		var ss []string
+		for _, f := range t.Fields {
+			ss = append(ss, f.Name+": "+f.Type.String())
+		}
+		return "struct<" + strings.Join(ss, ", ") + ">"
+	case Map:
+		entry := t.Fields[0].Type
+		return "map<" + entry.Fields[0].Type.String() + ", " + entry.Fields[1].Type.String() + ">"
+	}
+	return t.ID.String()
+}
+
+// Field is a named field of an Arrow schema or of a nested type.
+type Field struct {
+	Name     string
+	Type     *DataType
+	Nullable bool
+}
+
+// Schema is an Arrow schema, which describes the columns of a record batch.
+type Schema struct {
+	Fields []Field
+}
+
+// String formats the schema as Arrow does.
+func (s *Schema) String() string {
+	var b strings.Builder
+	b.WriteString("schema:\n  fields: ")
+	b.WriteString(strconv.Itoa(len(s.Fields)))
+	for _, f := range s.Fields {
+		b.WriteString("\n    - " + f.Name + ": type=" + f.Type.String())
+		if f.Nullable {
+			b.WriteString(", nullable")
+		}
+	}
+	return b.String()
+}
+
+// NewSchema returns the Arrow schema of record batches holding messages
+// of type md, which has a column for each field of md.
+// It reports an error if md is recursive.
+func NewSchema(md protoreflect.MessageDescriptor) (*Schema, error) {
+	t, err := newStructType(md, make(map[protoreflect.FullName]bool))
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{Fields: t.Fields}, nil
+}
+
+// newStructType returns the type of the struct for messages of type md.
+// The active map holds the message types whose fields are being converted.
+func newStructType(md protoreflect.MessageDescriptor, active map[protoreflect.FullName]bool) (*DataType, error) {
+	if active[md.FullName()] {
+		return nil, errors.New("recursive message %v cannot be represented in an Arrow schema", md.FullName())
+	}
+	active[md.FullName()] = true
+	defer delete(active, md.FullName())
+
+	t := &DataType{ID: Struct}
+	fds := md.Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		f, err := newField(fd, active)
+		if err != nil {
+			return nil, err
+		}
+		t.Fields = append(t.Fields, f)
+	}
+	return t, nil
+}
+
+func newField(fd protoreflect.FieldDescriptor, active map[protoreflect.FullName]bool) (Field, error) {
+	f := Field{Name: string(fd.Name())}
+	switch {
+	case fd.IsMap():
+		k, err := newType(fd.MapKey(), active)
+		if err != nil {
+			return Field{}, err
+		}
+		v, err := newType(fd.MapValue(), active)
+		if err != nil {
+			return Field{}, err
+		}
+		entry := &DataType{ID: Struct, Fields: []Field{{Name: "key", Type: k}, {Name: "value", Type: v}}}
+		f.Type = &DataType{ID: Map, Fields: []Field{{Name: "entries", Type: entry}}}
+	case fd.IsList():
+		elem, err := newType(fd, active)
+		if err != nil {
+			return Field{}, err
+		}
+		f.Type = &DataType{ID: List, Fields: []Field{{Name: "item", Type: elem}}}
+	default:
+		t, err := newType(fd, active)
+		if err != nil {
+			return Field{}, err
+		}
+		f.Type = t
+		f.Nullable = isNullable(fd)
+	}
+	return f, nil
+}
+
+// newType returns the type of a singular value of the field.
+func newType(fd protoreflect.FieldDescriptor, active map[protoreflect.FullName]bool) (*DataType, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return &DataType{ID: Bool}, nil
+	case protoreflect.EnumKind, protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &DataType{ID: Int32}, nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return &DataType{ID: Int64}, nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &DataType{ID: Uint32}, nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &DataType{ID: Uint64}, nil
+	case protoreflect.FloatKind:
+		return &DataType{ID: Float32}, nil
+	case protoreflect.DoubleKind:
+		return &DataType{ID: Float64}, nil
+	case protoreflect.StringKind:
+		return &DataType{ID: String}, nil
+	case protoreflect.BytesKind:
+		return &DataType{ID: Binary}, nil
+	}
+	md := fd.Message()
+	switch {
+	case md.FullName() == genid.Timestamp_message_fullname:
+		return &DataType{ID: Timestamp}, nil
+	case isWrapper(md):
+		return newType(md.Fields().ByNumber(genid.WrapperValue_Value_field_number), active)
+	}
+	return newStructType(md, active)
+}
+
+// isNullable reports whether a singular field is nullable, which is the case
+// for fields with presence other than required fields.
+func isNullable(fd protoreflect.FieldDescriptor) bool {
+	return fd.HasPresence() && fd.Cardinality() != protoreflect.Required
+}
+
+// isWrapper reports whether md is one of the wrapper types,
+// such as google.protobuf.Int64Value.
+func isWrapper(md protoreflect.MessageDescriptor) bool {
+	switch md.FullName() {
+	case genid.BoolValue_message_fullname,
+		genid.Int32Value_message_fullname,
+		genid.Int64Value_message_fullname,
+		genid.UInt32Value_message_fullname,
+		genid.UInt64Value_message_fullname,
+		genid.FloatValue_message_fullname,
+		genid.DoubleValue_message_fullname,
+		genid.StringValue_message_fullname,
+		genid.BytesValue_message_fullname:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoarrow_test
+
+import (
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protoparse"
+	"google.golang.org/protobuf/encoding/protoarrow"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	pb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const rowProto = `
+syntax = "proto3";
+
+package protoarrow.test;
+
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+
+message Row {
+  enum Color {
+    RED = 0;
+    GREEN = 1;
+  }
+  message Inner {
+    bool flag = 1;
+    Color color = 2;
+  }
+
+  int32 id = 1;
+  optional string name = 2;
+  repeated int64 values = 3;
+  map<string, int32> counts = 4;
+  Inner inner = 5;
+  google.protobuf.Timestamp time = 6;
+  google.protobuf.Int64Value wrapped = 7;
+}
+
+message Flat {
+  int32 id = 1;
+  optional string name = 2;
+  Row.Color color = 3;
+  google.protobuf.Timestamp time = 4;
+  google.protobuf.Int64Value wrapped = 5;
+  google.protobuf.BoolValue ok = 6;
+  bytes data = 7;
+  double score = 8;
+  uint64 big = 9;
+}
+`
+
+// rowType returns the type of the Row message declared in rowProto.
+func rowType(t *testing.T) protoreflect.MessageType {
+	return testMessageType(t, "Row")
+}
+
+// testMessageType returns the type of a message declared in rowProto.
+func testMessageType(t *testing.T, name protoreflect.Name) protoreflect.MessageType {
+	t.Helper()
+	p := protoparse.Parser{
+		Accessor: func(path string) (io.ReadCloser, error) {
+			if path != "row.proto" {
+				return nil, fs.ErrNotExist
+			}
+			return io.NopCloser(strings.NewReader(rowProto)), nil
+		},
+	}
+	fds, err := p.ParseFiles("row.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dynamicpb.NewMessageType(fds[0].Messages().ByName(name))
+}
+
+func TestNewSchema(t *testing.T) {
+	tests := []struct {
+		md   protoreflect.MessageDescriptor
+		want string
+	}{{
+		md: rowType(t).Descriptor(),
+		want: `schema:
+  fields: 7
+    - id: type=int32
+    - name: type=utf8, nullable
+    - values: type=list<item: int64>
+    - counts: type=map<utf8, int32>
+    - inner: type=struct<flag: bool, color: int32>, nullable
+    - time: type=timestamp[us, tz=UTC], nullable
+    - wrapped: type=int64, nullable`,
+	}, {
+		md: (&pb2.Scalars{}).ProtoReflect().Descriptor(),
+		want: `schema:
+  fields: 15
+    - opt_bool: type=bool, nullable
+    - opt_int32: type=int32, nullable
+    - opt_int64: type=int64, nullable
+    - opt_uint32: type=uint32, nullable
+    - opt_uint64: type=uint64, nullable
+    - opt_sint32: type=int32, nullable
+    - opt_sint64: type=int64, nullable
+    - opt_fixed32: type=uint32, nullable
+    - opt_fixed64: type=uint64, nullable
+    - opt_sfixed32: type=int32, nullable
+    - opt_sfixed64: type=int64, nullable
+    - opt_float: type=float32, nullable
+    - opt_double: type=float64, nullable
+    - opt_bytes: type=binary, nullable
+    - opt_string: type=utf8, nullable`,
+	}, {
+		md: (&pb2.IndirectRequired{}).ProtoReflect().Descriptor(),
+		want: `schema:
+  fields: 4
+    - opt_nested: type=struct<req_string: utf8>, nullable
+    - rpt_nested: type=list<item: struct<req_string: utf8>>
+    - str_to_nested: type=map<utf8, struct<req_string: utf8>>
+    - oneof_nested: type=struct<req_string: utf8>, nullable`,
+	}}
+	for _, tt := range tests {
+		s, err := protoarrow.NewSchema(tt.md)
+		if err != nil {
+			t.Errorf("NewSchema(%v) error: %v", tt.md.FullName(), err)
+			continue
+		}
+		if got := s.String(); got != tt.want {
+			t.Errorf("NewSchema(%v) = %s\nwant %s", tt.md.FullName(), got, tt.want)
+		}
+	}
+
+	for _, m := range []proto.Message{&pb2.Nests{}, &pb2.KnownTypes{}} {
+		md := m.ProtoReflect().Descriptor()
+		if _, err := protoarrow.NewSchema(md); err == nil || !strings.Contains(err.Error(), "recursive message") {
+			t.Errorf("NewSchema(%v) error = %v, want recursive message error", md.FullName(), err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoavro
+
+import (
+	"crypto/rand"
+	"io"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// blockSize is the size of the encoded records at which a Writer writes
+// a block.
+const blockSize = 64 << 10
+
+// A Writer writes messages to an Avro object container file, which holds
+// the schema of the records along with blocks of records. The blocks are
+// not compressed.
+type Writer struct {
+	w    io.Writer
+	md   protoreflect.MessageDescriptor
+	opts MarshalOptions
+	sync [16]byte
+
+	block []byte // records of the current block
+	n     int    // number of records in block
+}
+
+// NewWriter writes the header of an object container file holding records
+// for messages of type md to w, and returns a Writer for the records.
+func NewWriter(w io.Writer, md protoreflect.MessageDescriptor) (*Writer, error) {
+	return MarshalOptions{}.NewWriter(w, md)
+}
+
+// NewWriter is like [NewWriter], but the Writer marshals the records
+// using the options in o.
+func (o MarshalOptions) NewWriter(w io.Writer, md protoreflect.MessageDescriptor) (*Writer, error) {
+	cw := &Writer{w: w, md: md, opts: o}
+	if _, err := rand.Read(cw.sync[:]); err != nil {
+		return nil, err
+	}
+
+	b := []byte("Obj\x01")
+	// The metadata is a map with a single block.
+	b = appendLong(b, 2)
+	b = appendString(b, "avro.schema")
+	b = appendString(b, Schema(md))
+	b = appendString(b, "avro.codec")
+	b = appendString(b, "null")
+	b = appendLong(b, 0)
+	b = append(b, cw.sync[:]...)
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+// Write writes the record for m, which must be of the type of the
+// Writer. Records are buffered in memory until a block is complete,
+// so Flush must be called after the last record.
+func (w *Writer) Write(m proto.Message) error {
+	if md := m.ProtoReflect().Descriptor(); md.FullName() != w.md.FullName() {
+		return errors.New("cannot write %v message to file of %v records", md.FullName(), w.md.FullName())
+	}
+	b, err := w.opts.MarshalAppend(w.block, m)
+	if err != nil {
+		return err
+	}
+	w.block = b
+	w.n++
+	if len(w.block) >= blockSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+// Flush writes the records that have been written since the last block
+// as a block.
+func (w *Writer) Flush() error {
+	if w.n == 0 {
+		return nil
+	}
+	b := appendLong(nil, int64(w.n))
+	b = appendLong(b, int64(len(w.block)))
+	if _, err := w.w.Write(b); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(w.block); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(w.sync[:]); err != nil {
+		return err
+	}
+	w.block, w.n = w.block[:0], 0
+	return nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = appendLong(b, int64(len(s)))
+	return append(b, s...)
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoavro_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/protoavro"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	pb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
+)
+
+// containerReader reads the parts of an object container file.
+type containerReader struct {
+	t *testing.T
+	b []byte
+}
+
+func (r *containerReader) long() int64 {
+	v, n := protowire.ConsumeVarint(r.b)
+	if n < 0 {
+		r.t.Fatalf("invalid long: %v", protowire.ParseError(n))
+	}
+	r.b = r.b[n:]
+	return protowire.DecodeZigZag(v)
+}
+
+func (r *containerReader) fixed(n int) []byte {
+	if len(r.b) < n {
+		r.t.Fatalf("unexpected end of file")
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+func (r *containerReader) bytes() []byte {
+	return r.fixed(int(r.long()))
+}
+
+func TestWriter(t *testing.T) {
+	md := (&pb2.Nested{}).ProtoReflect().Descriptor()
+	msgs := []*pb2.Nested{
+		{OptString: proto.String("a")},
+		{},
+		{OptNested: &pb2.Nested{OptString: proto.String("b")}},
+	}
+
+	var buf bytes.Buffer
+	w, err := protoavro.NewWriter(&buf, md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Write the first two records in one block and the last in another.
+	for i, m := range msgs {
+		if err := w.Write(m); err != nil {
+			t.Fatal(err)
+		}
+		if i > 0 {
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := w.Write(&pb2.Scalars{}); err == nil {
+		t.Errorf("Write(&pb2.Scalars{}) succeeded, want error")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &containerReader{t: t, b: buf.Bytes()}
+	if magic := r.fixed(4); string(magic) != "Obj\x01" {
+		t.Fatalf("magic = %q, want %q", magic, "Obj\x01")
+	}
+	meta := make(map[string]string)
+	for n := r.long(); n != 0; n = r.long() {
+		for ; n > 0; n-- {
+			k := r.bytes()
+			meta[string(k)] = string(r.bytes())
+		}
+	}
+	wantMeta := map[string]string{
+		"avro.schema": protoavro.Schema(md),
+		"avro.codec":  "null",
+	}
+	if diff := cmp.Diff(wantMeta, meta); diff != "" {
+		t.Errorf("metadata mismatch (-want +got):\n%s", diff)
+	}
+	sync := string(r.fixed(16))
+
+	var got []*pb2.Nested
+	var counts []int64
+	for len(r.b) > 0 {
+		n := r.long()
+		counts = append(counts, n)
+		data := r.bytes()
+		for ; n > 0; n-- {
+			// Records are not delimited, so take the shortest
+			// prefix of the data that is a complete record.
+			m := &pb2.Nested{}
+			for i := 1; i <= len(data); i++ {
+				if protoavro.Unmarshal(data[:i], m) == nil {
+					data = data[i:]
+					break
+				}
+			}
+			got = append(got, m)
+		}
+		if len(data) > 0 {
+			t.Errorf("unexpected data at end of block: %x", data)
+		}
+		if s := string(r.fixed(16)); s != sync {
+			t.Errorf("sync marker = %x, want %x", s, sync)
+		}
+	}
+	if diff := cmp.Diff([]int64{2, 1}, counts); diff != "" {
+		t.Errorf("block counts mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(msgs, got, protocmp.Transform()); diff != "" {
+		t.Errorf("records mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoavro
+
+import (
+	"encoding/binary"
+	"math"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Unmarshal parses the Avro binary encoding of a record of the schema
+// returned by [Schema] for the type of m, and stores the result in m.
+// It will clear the message first before setting the fields.
+// The provided message must be mutable (e.g., a non-nil pointer to a message).
+func Unmarshal(b []byte, m proto.Message) error {
+	proto.Reset(m)
+	d := decoder{b: b}
+	if err := d.readMessage(m.ProtoReflect(), protowire.DefaultRecursionLimit); err != nil {
+		return err
+	}
+	if d.pos < len(d.b) {
+		return d.newError("unexpected data after record")
+	}
+	return proto.CheckInitialized(m)
+}
+
+type decoder struct {
+	b   []byte
+	pos int
+}
+
+func (d *decoder) newError(f string, x ...any) error {
+	return errors.New("invalid Avro record (offset %d): "+f, append([]any{d.pos}, x...)...)
+}
+
+func (d *decoder) readLong() (int64, error) {
+	v, n := protowire.ConsumeVarint(d.b[d.pos:])
+	if n < 0 {
+		return 0, d.newError("truncated long")
+	}
+	d.pos += n
+	return protowire.DecodeZigZag(v), nil
+}
+
+func (d *decoder) readFixed(n int) ([]byte, error) {
+	if len(d.b)-d.pos < n {
+		return nil, d.newError("unexpected end of data")
+	}
+	b := d.b[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *decoder) readBytes() ([]byte, error) {
+	n, err := d.readLong()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > int64(len(d.b)-d.pos) {
+		return nil, d.newError("invalid length %d", n)
+	}
+	return d.readFixed(int(n))
+}
+
+// readBlocks reads the blocks of an array or map, calling f for each item.
+func (d *decoder) readBlocks(f func() error) error {
+	for {
+		n, err := d.readLong()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+		if n < 0 {
+			// A negative count is followed by the size of the block in bytes.
+			n = -n
+			if _, err := d.readLong(); err != nil {
+				return err
+			}
+		}
+		for ; n > 0; n-- {
+			if err := f(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (d *decoder) readMessage(m protoreflect.Message, depth int) error {
+	if depth--; depth < 0 {
+		return errors.New("exceeded maximum recursion depth")
+	}
+	fds := m.Descriptor().Fields()
+	for i := 0; i < fds.Len(); i++ {
+		if err := d.readField(m, fds.Get(i), depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *decoder) readField(m protoreflect.Message, fd protoreflect.FieldDescriptor, depth int) error {
+	switch {
+	case fd.IsList():
+		list := m.Mutable(fd).List()
+		return d.readBlocks(func() error {
+			v, err := d.readValue(fd, list.NewElement, depth)
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+			return nil
+		})
+	case fd.IsMap():
+		mmap := m.Mutable(fd).Map()
+		return d.readBlocks(func() error {
+			k, err := d.readValue(fd.MapKey(), nil, depth)
+			if err != nil {
+				return err
+			}
+			v, err := d.readValue(fd.MapValue(), mmap.NewValue, depth)
+			if err != nil {
+				return err
+			}
+			mmap.Set(k.MapKey(), v)
+			return nil
+		})
+	case isNullable(fd):
+		switch branch, err := d.readLong(); {
+		case err != nil:
+			return err
+		case branch == 0:
+			return nil
+		case branch != 1:
+			return d.newError("invalid union branch %d for field %v", branch, fd.Name())
+		}
+	}
+	v, err := d.readValue(fd, func() protoreflect.Value { return m.NewField(fd) }, depth)
+	if err != nil {
+		return err
+	}
+	m.Set(fd, v)
+	return nil
+}
+
+// readValue reads a singular value of the field.
+// For message fields, newValue returns a new message value.
+func (d *decoder) readValue(fd protoreflect.FieldDescriptor, newValue func() protoreflect.Value, depth int) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		b, err := d.readFixed(1)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfBool(b[0] != 0), nil
+	case protoreflect.FloatKind:
+		b, err := d.readFixed(4)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfFloat32(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case protoreflect.DoubleKind:
+		b, err := d.readFixed(8)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfFloat64(math.Float64frombits(binary.LittleEndian.Uint64(b))), nil
+	case protoreflect.StringKind:
+		b, err := d.readBytes()
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		if !utf8.Valid(b) {
+			return protoreflect.Value{}, errors.InvalidUTF8(string(fd.FullName()))
+		}
+		return protoreflect.ValueOfString(string(b)), nil
+	case protoreflect.BytesKind:
+		b, err := d.readBytes()
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfBytes(append([]byte(nil), b...)), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		v := newValue()
+		m := v.Message()
+		md := m.Descriptor()
+		switch {
+		case md.FullName() == genid.Timestamp_message_fullname:
+			n, err := d.readLong()
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			secs, micros := n/1e6, n%1e6
+			if micros < 0 {
+				secs, micros = secs-1, micros+1e6
+			}
+			m.Set(md.Fields().ByNumber(genid.Timestamp_Seconds_field_number), protoreflect.ValueOfInt64(secs))
+			m.Set(md.Fields().ByNumber(genid.Timestamp_Nanos_field_number), protoreflect.ValueOfInt32(int32(micros*1e3)))
+		case isWrapper(md):
+			vfd := md.Fields().ByNumber(genid.WrapperValue_Value_field_number)
+			wv, err := d.readValue(vfd, nil, depth)
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			m.Set(vfd, wv)
+		default:
+			if err := d.readMessage(m, depth); err != nil {
+				return protoreflect.Value{}, err
+			}
+		}
+		return v, nil
+	}
+
+	// The remaining kinds are encoded as longs.
+	n, err := d.readLong()
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if n == int64(int32(n)) {
+			return protoreflect.ValueOfInt32(int32(n)), nil
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(n), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n == int64(uint32(n)) {
+			return protoreflect.ValueOfUint32(uint32(n)), nil
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(uint64(n)), nil
+	case protoreflect.EnumKind:
+		if values := fd.Enum().Values(); n >= 0 && n < int64(values.Len()) {
+			return protoreflect.ValueOfEnum(values.Get(int(n)).Number()), nil
+		}
+	}
+	return protoreflect.Value{}, d.newError("value %d out of range for field %v", n, fd.Name())
+}
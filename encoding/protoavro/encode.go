@@ -0,0 +1,161 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoavro
+
+import (
+	"encoding/binary"
+	"math"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/internal/order"
+	"google.golang.org/protobuf/internal/pragma"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Marshal returns the Avro binary encoding of m as a record of the schema
+// returned by [Schema] for its type.
+func Marshal(m proto.Message) ([]byte, error) {
+	return MarshalOptions{}.Marshal(m)
+}
+
+// MarshalOptions configures the marshaler.
+type MarshalOptions struct {
+	pragma.NoUnkeyedLiterals
+
+	// AllowPartial allows messages that have missing required fields to marshal
+	// without returning an error. If AllowPartial is false (the default),
+	// Marshal will return error if there are any missing required fields.
+	AllowPartial bool
+}
+
+// Marshal returns the Avro binary encoding of m as a record of the schema
+// returned by [Schema] for its type.
+func (o MarshalOptions) Marshal(m proto.Message) ([]byte, error) {
+	return o.MarshalAppend(nil, m)
+}
+
+// MarshalAppend appends the Avro binary encoding of m to b,
+// returning the result.
+func (o MarshalOptions) MarshalAppend(b []byte, m proto.Message) ([]byte, error) {
+	if !o.AllowPartial {
+		if err := proto.CheckInitialized(m); err != nil {
+			return nil, err
+		}
+	}
+	return appendMessage(b, m.ProtoReflect())
+}
+
+func appendMessage(b []byte, m protoreflect.Message) ([]byte, error) {
+	fds := m.Descriptor().Fields()
+	for i := 0; i < fds.Len(); i++ {
+		var err error
+		if b, err = appendField(b, m, fds.Get(i)); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func appendField(b []byte, m protoreflect.Message, fd protoreflect.FieldDescriptor) ([]byte, error) {
+	var err error
+	switch {
+	case fd.IsList():
+		list := m.Get(fd).List()
+		if list.Len() > 0 {
+			b = appendLong(b, int64(list.Len()))
+			for i := 0; i < list.Len(); i++ {
+				if b, err = appendValue(b, fd, list.Get(i)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		b = appendLong(b, 0)
+	case fd.IsMap():
+		mmap := m.Get(fd).Map()
+		if mmap.Len() > 0 {
+			b = appendLong(b, int64(mmap.Len()))
+			order.RangeEntries(mmap, order.GenericKeyOrder, func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				if b, err = appendValue(b, fd.MapKey(), k.Value()); err != nil {
+					return false
+				}
+				b, err = appendValue(b, fd.MapValue(), v)
+				return err == nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		b = appendLong(b, 0)
+	case isNullable(fd):
+		if !m.Has(fd) {
+			return appendLong(b, 0), nil
+		}
+		b = appendLong(b, 1)
+		return appendValue(b, fd, m.Get(fd))
+	default:
+		return appendValue(b, fd, m.Get(fd))
+	}
+	return b, nil
+}
+
+// appendValue appends a singular value of the field.
+func appendValue(b []byte, fd protoreflect.FieldDescriptor, v protoreflect.Value) ([]byte, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if v.Bool() {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return appendLong(b, v.Int()), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return appendLong(b, int64(v.Uint())), nil
+	case protoreflect.FloatKind:
+		return binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(v.Float()))), nil
+	case protoreflect.DoubleKind:
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(v.Float())), nil
+	case protoreflect.StringKind:
+		if !utf8.ValidString(v.String()) {
+			return nil, errors.InvalidUTF8(string(fd.FullName()))
+		}
+		b = appendLong(b, int64(len(v.String())))
+		return append(b, v.String()...), nil
+	case protoreflect.BytesKind:
+		b = appendLong(b, int64(len(v.Bytes())))
+		return append(b, v.Bytes()...), nil
+	case protoreflect.EnumKind:
+		ev := fd.Enum().Values().ByNumber(v.Enum())
+		if ev == nil {
+			return nil, errors.New("%v: enum value %d is not declared in %v", fd.FullName(), v.Enum(), fd.Enum().FullName())
+		}
+		return appendLong(b, int64(ev.Index())), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		m := v.Message()
+		md := m.Descriptor()
+		switch {
+		case md.FullName() == genid.Timestamp_message_fullname:
+			secs := m.Get(md.Fields().ByNumber(genid.Timestamp_Seconds_field_number)).Int()
+			nanos := m.Get(md.Fields().ByNumber(genid.Timestamp_Nanos_field_number)).Int()
+			return appendLong(b, secs*1e6+nanos/1e3), nil
+		case isWrapper(md):
+			vfd := md.Fields().ByNumber(genid.WrapperValue_Value_field_number)
+			return appendValue(b, vfd, m.Get(vfd))
+		}
+		return appendMessage(b, m)
+	}
+	return nil, errors.New("%v: invalid kind %v", fd.FullName(), fd.Kind())
+}
+
+// appendLong appends an Avro long, which is a zig-zag encoded varint
+// as for the protobuf sint64 type.
+func appendLong(b []byte, n int64) []byte {
+	return protowire.AppendVarint(b, protowire.EncodeZigZag(n))
+}
@@ -0,0 +1,233 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoavro_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/protoavro"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test3"
+	pb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		desc string
+		msg  proto.Message
+	}{{
+		desc: "empty proto3",
+		msg:  &testpb.TestAllTypes{},
+	}, {
+		desc: "proto3 scalars",
+		msg: &testpb.TestAllTypes{
+			SingularInt32:       -1,
+			SingularInt64:       -2,
+			SingularUint32:      0xffffffff,
+			SingularUint64:      0xffffffffffffffff,
+			SingularSint32:      -5,
+			SingularSint64:      -6,
+			SingularFixed32:     7,
+			SingularFixed64:     8,
+			SingularSfixed32:    -9,
+			SingularSfixed64:    -10,
+			SingularFloat:       11.5,
+			SingularDouble:      -12.25,
+			SingularBool:        true,
+			SingularString:      "string",
+			SingularBytes:       []byte("bytes"),
+			SingularNestedEnum:  testpb.TestAllTypes_BAR,
+			SingularForeignEnum: testpb.ForeignEnum_FOREIGN_BAZ,
+			OptionalInt32:       proto.Int32(0),
+			OptionalString:      proto.String(""),
+			SingularNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A: 1,
+			},
+			OneofField: &testpb.TestAllTypes_OneofString{OneofString: "oneof"},
+		},
+	}, {
+		desc: "proto3 repeated and maps",
+		msg: &testpb.TestAllTypes{
+			RepeatedInt32:      []int32{1, -2, 3},
+			RepeatedString:     []string{"a", "", "c"},
+			RepeatedNestedEnum: []testpb.TestAllTypes_NestedEnum{testpb.TestAllTypes_NEG, testpb.TestAllTypes_FOO},
+			RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+				{A: 1}, {}, {Corecursive: &testpb.TestAllTypes{SingularInt32: 2}},
+			},
+			MapInt32Int32:   map[int32]int32{1: 2, -3: 4},
+			MapStringString: map[string]string{"a": "b", "": ""},
+			MapBoolBool:     map[bool]bool{true: false},
+			MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"x": {A: 5},
+			},
+		},
+	}, {
+		desc: "proto2 presence",
+		msg: &pb2.Scalars{
+			OptBool:   proto.Bool(false),
+			OptInt32:  proto.Int32(0),
+			OptUint64: proto.Uint64(1 << 63),
+			OptString: proto.String("héllo"),
+			OptBytes:  []byte{},
+		},
+	}, {
+		desc: "well-known types",
+		msg: &pb2.KnownTypes{
+			OptBool:      wrapperspb.Bool(false),
+			OptInt64:     wrapperspb.Int64(-1),
+			OptDouble:    wrapperspb.Double(1.5),
+			OptString:    wrapperspb.String("s"),
+			OptBytes:     wrapperspb.Bytes(nil),
+			OptTimestamp: &timestamppb.Timestamp{Seconds: -1, Nanos: 999000},
+			OptStruct: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"a": structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{
+					structpb.NewNumberValue(1), structpb.NewStringValue("b"), structpb.NewNullValue(),
+				}}),
+			}},
+		},
+	}, {
+		desc: "required fields",
+		msg: &pb2.IndirectRequired{
+			RptNested:   []*pb2.NestedWithRequired{{ReqString: proto.String("a")}},
+			StrToNested: map[string]*pb2.NestedWithRequired{"b": {ReqString: proto.String("")}},
+			Union:       &pb2.IndirectRequired_OneofNested{OneofNested: &pb2.NestedWithRequired{ReqString: proto.String("c")}},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := protoavro.Marshal(tt.msg)
+			if err != nil {
+				t.Fatalf("Marshal() error: %v", err)
+			}
+			got := tt.msg.ProtoReflect().New().Interface()
+			if err := protoavro.Unmarshal(b, got); err != nil {
+				t.Fatalf("Unmarshal() error: %v", err)
+			}
+			if diff := cmp.Diff(tt.msg, got, protocmp.Transform()); diff != "" {
+				t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	b, err := protoavro.Marshal(&pb2.Enums{
+		OptEnum:       pb2.Enum_TEN.Enum(),
+		RptNestedEnum: []pb2.Enums_NestedEnum{pb2.Enums_DOS, pb2.Enums_UNO},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{
+		0x02, 0x04, // opt_enum: branch 1, index 2
+		0x00,                   // rpt_enum: empty
+		0x00,                   // opt_nested_enum: null
+		0x04, 0x02, 0x00, 0x00, // rpt_nested_enum: 2 items [1, 0], end
+	}
+	if !bytes.Equal(b, want) {
+		t.Errorf("Marshal() = %x, want %x", b, want)
+	}
+
+	ts := &timestamppb.Timestamp{Seconds: 1, Nanos: 1999}
+	b, err = protoavro.Marshal(&pb2.KnownTypes{OptTimestamp: ts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &pb2.KnownTypes{}
+	if err := protoavro.Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if want := (&timestamppb.Timestamp{Seconds: 1, Nanos: 1000}); !proto.Equal(got.OptTimestamp, want) {
+		t.Errorf("timestamp %v round tripped to %v, want %v", ts, got.OptTimestamp, want)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		opts    protoavro.MarshalOptions
+		msg     proto.Message
+		wantErr string
+	}{{
+		desc:    "undeclared enum value",
+		msg:     &testpb.TestAllTypes{SingularNestedEnum: 42},
+		wantErr: "enum value 42 is not declared",
+	}, {
+		desc:    "missing required field",
+		msg:     &pb2.Requireds{},
+		wantErr: "required field",
+	}, {
+		desc:    "missing nested required field",
+		msg:     &pb2.IndirectRequired{OptNested: &pb2.NestedWithRequired{}},
+		wantErr: "required field",
+	}, {
+		desc:    "invalid UTF-8",
+		msg:     &testpb.TestAllTypes{SingularString: "\xff"},
+		wantErr: "invalid UTF-8",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := tt.opts.Marshal(tt.msg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Marshal() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := (protoavro.MarshalOptions{AllowPartial: true}).Marshal(&pb2.IndirectRequired{OptNested: &pb2.NestedWithRequired{}}); err != nil {
+		t.Errorf("Marshal() with AllowPartial error: %v", err)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		in      []byte
+		msg     proto.Message
+		wantErr string
+	}{{
+		desc:    "truncated",
+		in:      []byte{0x02},
+		msg:     &pb2.Enums{},
+		wantErr: "truncated long",
+	}, {
+		desc:    "invalid union branch",
+		in:      []byte{0x04, 0x00, 0x00, 0x00, 0x00},
+		msg:     &pb2.Enums{},
+		wantErr: "invalid union branch 2",
+	}, {
+		desc:    "enum index out of range",
+		in:      []byte{0x02, 0x06, 0x00, 0x00, 0x00},
+		msg:     &pb2.Enums{},
+		wantErr: "value 3 out of range",
+	}, {
+		desc:    "trailing data",
+		in:      []byte{0x00, 0x00, 0x00, 0x00, 0x00},
+		msg:     &pb2.Enums{},
+		wantErr: "unexpected data after record",
+	}, {
+		desc:    "string too long",
+		in:      []byte{0x02, 0x0a, 'a'},
+		msg:     &pb2.Nested{},
+		wantErr: "invalid length 5",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := protoavro.Unmarshal(tt.in, tt.msg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Unmarshal() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoavro
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ParquetSchema returns the Parquet schema corresponding to the Avro schema
+// returned by [Schema], in the message type syntax of the Parquet tools.
+// Records produced by [Marshal] can be written to Parquet files of this
+// schema by Parquet writers that accept Avro records.
+//
+// Nullable fields are optional and other fields are required. Arrays and
+// maps use the three-level LIST and MAP structures of the Parquet format
+// specification. Since Parquet schemas cannot be recursive, ParquetSchema
+// reports an error for recursive message types.
+func ParquetSchema(md protoreflect.MessageDescriptor) (string, error) {
+	p := parquetWriter{active: make(map[protoreflect.FullName]bool)}
+	p.WriteString("message " + string(md.FullName()) + " {\n")
+	if err := p.writeFields(md, 1); err != nil {
+		return "", err
+	}
+	p.WriteString("}\n")
+	return p.String(), nil
+}
+
+type parquetWriter struct {
+	strings.Builder
+	// active holds the message types whose fields are being written.
+	active map[protoreflect.FullName]bool
+}
+
+func (p *parquetWriter) writeFields(md protoreflect.MessageDescriptor, depth int) error {
+	if p.active[md.FullName()] {
+		return errors.New("recursive message %v cannot be represented in a Parquet schema", md.FullName())
+	}
+	p.active[md.FullName()] = true
+	defer delete(p.active, md.FullName())
+
+	fds := md.Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		name := string(fd.Name())
+		var err error
+		switch {
+		case fd.IsList():
+			p.writeGroup(depth, "required", name, "LIST")
+			p.writeGroup(depth+1, "repeated", "list", "")
+			err = p.writeType(fd, depth+2, "required", "element")
+			p.writeEnd(depth + 1)
+			p.writeEnd(depth)
+		case fd.IsMap():
+			p.writeGroup(depth, "required", name, "MAP")
+			p.writeGroup(depth+1, "repeated", "key_value", "")
+			if err = p.writeType(fd.MapKey(), depth+2, "required", "key"); err == nil {
+				err = p.writeType(fd.MapValue(), depth+2, "required", "value")
+			}
+			p.writeEnd(depth + 1)
+			p.writeEnd(depth)
+		case isNullable(fd):
+			err = p.writeType(fd, depth, "optional", name)
+		default:
+			err = p.writeType(fd, depth, "required", name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeType writes a field for a singular value of fd.
+func (p *parquetWriter) writeType(fd protoreflect.FieldDescriptor, depth int, repetition, name string) error {
+	var typ string
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		typ = "boolean"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		typ = "int32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		typ = "int64"
+	case protoreflect.FloatKind:
+		typ = "float"
+	case protoreflect.DoubleKind:
+		typ = "double"
+	case protoreflect.StringKind:
+		typ = "binary"
+		name += " (STRING)"
+	case protoreflect.BytesKind:
+		typ = "binary"
+	case protoreflect.EnumKind:
+		typ = "binary"
+		name += " (ENUM)"
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		md := fd.Message()
+		switch {
+		case md.FullName() == genid.Timestamp_message_fullname:
+			typ = "int64"
+			name += " (TIMESTAMP(MICROS,true))"
+		case isWrapper(md):
+			return p.writeType(md.Fields().ByNumber(genid.WrapperValue_Value_field_number), depth, repetition, name)
+		default:
+			p.writeGroup(depth, repetition, name, "")
+			if err := p.writeFields(md, depth+1); err != nil {
+				return err
+			}
+			p.writeEnd(depth)
+			return nil
+		}
+	}
+	p.writeIndent(depth)
+	p.WriteString(repetition + " " + typ + " " + name + ";\n")
+	return nil
+}
+
+func (p *parquetWriter) writeGroup(depth int, repetition, name, annotation string) {
+	p.writeIndent(depth)
+	p.WriteString(repetition + " group " + name)
+	if annotation != "" {
+		p.WriteString(" (" + annotation + ")")
+	}
+	p.WriteString(" {\n")
+}
+
+func (p *parquetWriter) writeEnd(depth int) {
+	p.writeIndent(depth)
+	p.WriteString("}\n")
+}
+
+func (p *parquetWriter) writeIndent(depth int) {
+	p.WriteString(strings.Repeat("  ", depth))
+}
@@ -0,0 +1,226 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoavro converts protocol buffer messages to Apache Avro records,
+// for exporting messages to data lakes and other systems built on Avro or
+// Parquet files.
+//
+// The Avro schema for a message type is derived from its descriptor:
+//
+//	╔══════════════════════════════╤═════════════════════════════════════════╗
+//	║ Protobuf                     │ Avro                                    ║
+//	╠══════════════════════════════╪═════════════════════════════════════════╣
+//	║ message, group               │ record with the same full name          ║
+//	║ field with presence          │ union of null and the field type,       ║
+//	║                              │ with a null default                     ║
+//	║ repeated                     │ array                                   ║
+//	║ map with string keys         │ map                                     ║
+//	║ map with other keys          │ array of records with key and value     ║
+//	║                              │ fields, named as the map entry message  ║
+//	║ bool                         │ boolean                                 ║
+//	║ int32, sint32, sfixed32      │ int                                     ║
+//	║ other integer types          │ long                                    ║
+//	║ float, double                │ float, double                           ║
+//	║ string, bytes                │ string, bytes                           ║
+//	║ enum                         │ enum with the names of the values       ║
+//	║ google.protobuf.Timestamp    │ long with logical type timestamp-micros ║
+//	║ wrappers such as             │ the wrapped type                        ║
+//	║ google.protobuf.Int64Value   │                                         ║
+//	╚══════════════════════════════╧═════════════════════════════════════════╝
+//
+// Fields are named by their proto field name and appear in the order of
+// their declaration. Extensions and unknown fields are not represented.
+//
+// Values of uint64 and fixed64 fields are stored in long fields as their
+// two's complement bit pattern, so values of 2^63 and larger are negative in
+// Avro. Timestamps are truncated to microseconds. Enum values that are not
+// declared in the enum cannot be represented.
+package protoavro
+
+import (
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Schema returns the Avro schema of records for messages of type md,
+// in its JSON form.
+func Schema(md protoreflect.MessageDescriptor) string {
+	s := schemaWriter{seen: make(map[protoreflect.FullName]bool)}
+	s.writeRecord(md)
+	return s.String()
+}
+
+type schemaWriter struct {
+	strings.Builder
+	// seen holds the named types that have been defined.
+	// Later occurrences refer to them by name.
+	seen map[protoreflect.FullName]bool
+}
+
+// define reports whether the named type is to be defined,
+// otherwise writing a reference to it.
+func (s *schemaWriter) define(name protoreflect.FullName) bool {
+	if s.seen[name] {
+		s.WriteString(strconv.Quote(string(name)))
+		return false
+	}
+	s.seen[name] = true
+	return true
+}
+
+// writeName writes the name and namespace members of a named type.
+func (s *schemaWriter) writeName(name protoreflect.FullName) {
+	s.WriteString(`"name":` + strconv.Quote(string(name.Name())))
+	if ns := name.Parent(); ns != "" {
+		s.WriteString(`,"namespace":` + strconv.Quote(string(ns)))
+	}
+}
+
+func (s *schemaWriter) writeRecord(md protoreflect.MessageDescriptor) {
+	if !s.define(md.FullName()) {
+		return
+	}
+	s.WriteString(`{"type":"record",`)
+	s.writeName(md.FullName())
+	s.WriteString(`,"fields":[`)
+	fds := md.Fields()
+	for i := 0; i < fds.Len(); i++ {
+		if i > 0 {
+			s.WriteByte(',')
+		}
+		s.writeField(fds.Get(i))
+	}
+	s.WriteString("]}")
+}
+
+func (s *schemaWriter) writeField(fd protoreflect.FieldDescriptor) {
+	s.WriteString(`{"name":` + strconv.Quote(string(fd.Name())) + `,"type":`)
+	switch {
+	case fd.IsMap() && fd.MapKey().Kind() == protoreflect.StringKind:
+		s.WriteString(`{"type":"map","values":`)
+		s.writeType(fd.MapValue())
+		s.WriteString(`},"default":{}`)
+	case fd.IsMap():
+		s.WriteString(`{"type":"array","items":`)
+		if md := fd.Message(); s.define(md.FullName()) {
+			s.WriteString(`{"type":"record",`)
+			s.writeName(md.FullName())
+			s.WriteString(`,"fields":[{"name":"key","type":`)
+			s.writeType(fd.MapKey())
+			s.WriteString(`},{"name":"value","type":`)
+			s.writeType(fd.MapValue())
+			s.WriteString("}]}")
+		}
+		s.WriteString(`},"default":[]`)
+	case fd.IsList():
+		s.WriteString(`{"type":"array","items":`)
+		s.writeType(fd)
+		s.WriteString(`},"default":[]`)
+	case isNullable(fd):
+		s.WriteString(`["null",`)
+		s.writeType(fd)
+		s.WriteString(`],"default":null`)
+	default:
+		s.writeType(fd)
+		if fd.Cardinality() != protoreflect.Required {
+			s.WriteString(`,"default":`)
+			s.writeDefault(fd)
+		}
+	}
+	s.WriteByte('}')
+}
+
+// writeType writes the type of a singular value of the field.
+func (s *schemaWriter) writeType(fd protoreflect.FieldDescriptor) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		s.WriteString(`"boolean"`)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		s.WriteString(`"int"`)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		s.WriteString(`"long"`)
+	case protoreflect.FloatKind:
+		s.WriteString(`"float"`)
+	case protoreflect.DoubleKind:
+		s.WriteString(`"double"`)
+	case protoreflect.StringKind:
+		s.WriteString(`"string"`)
+	case protoreflect.BytesKind:
+		s.WriteString(`"bytes"`)
+	case protoreflect.EnumKind:
+		ed := fd.Enum()
+		if !s.define(ed.FullName()) {
+			return
+		}
+		s.WriteString(`{"type":"enum",`)
+		s.writeName(ed.FullName())
+		s.WriteString(`,"symbols":[`)
+		for i := 0; i < ed.Values().Len(); i++ {
+			if i > 0 {
+				s.WriteByte(',')
+			}
+			s.WriteString(strconv.Quote(string(ed.Values().Get(i).Name())))
+		}
+		s.WriteString("]}")
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		md := fd.Message()
+		switch {
+		case md.FullName() == genid.Timestamp_message_fullname:
+			s.WriteString(`{"type":"long","logicalType":"timestamp-micros"}`)
+		case isWrapper(md):
+			s.writeType(md.Fields().ByNumber(genid.WrapperValue_Value_field_number))
+		default:
+			s.writeRecord(md)
+		}
+	}
+}
+
+// writeDefault writes the default value of a field without presence,
+// which is the zero value of its type.
+func (s *schemaWriter) writeDefault(fd protoreflect.FieldDescriptor) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		s.WriteString("false")
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		s.WriteString(`""`)
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(fd.Default().Enum()); ev != nil {
+			s.WriteString(strconv.Quote(string(ev.Name())))
+		} else {
+			s.WriteString(strconv.Quote(string(fd.Enum().Values().Get(0).Name())))
+		}
+	default:
+		s.WriteString("0")
+	}
+}
+
+// isNullable reports whether a singular field is represented by a union
+// with null, which is the case for fields with presence other than
+// required fields.
+func isNullable(fd protoreflect.FieldDescriptor) bool {
+	return fd.HasPresence() && fd.Cardinality() != protoreflect.Required
+}
+
+// isWrapper reports whether md is one of the wrapper types,
+// such as google.protobuf.Int64Value.
+func isWrapper(md protoreflect.MessageDescriptor) bool {
+	switch md.FullName() {
+	case genid.BoolValue_message_fullname,
+		genid.Int32Value_message_fullname,
+		genid.Int64Value_message_fullname,
+		genid.UInt32Value_message_fullname,
+		genid.UInt64Value_message_fullname,
+		genid.FloatValue_message_fullname,
+		genid.DoubleValue_message_fullname,
+		genid.StringValue_message_fullname,
+		genid.BytesValue_message_fullname:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoavro_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/protoavro"
+	"google.golang.org/protobuf/proto"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test3"
+	pb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
+)
+
+func TestSchema(t *testing.T) {
+	tests := []struct {
+		msg  proto.Message
+		want string
+	}{{
+		msg: &pb2.Enums{},
+		want: `{"type":"record","name":"Enums","namespace":"pb2","fields":[
+			{"name":"opt_enum","type":["null",{"type":"enum","name":"Enum","namespace":"pb2","symbols":["ONE","TWO","TEN"]}],"default":null},
+			{"name":"rpt_enum","type":{"type":"array","items":"pb2.Enum"},"default":[]},
+			{"name":"opt_nested_enum","type":["null",{"type":"enum","name":"NestedEnum","namespace":"pb2.Enums","symbols":["UNO","DOS","DIEZ"]}],"default":null},
+			{"name":"rpt_nested_enum","type":{"type":"array","items":"pb2.Enums.NestedEnum"},"default":[]}]}`,
+	}, {
+		msg: &pb2.IndirectRequired{},
+		want: `{"type":"record","name":"IndirectRequired","namespace":"pb2","fields":[
+			{"name":"opt_nested","type":["null",{"type":"record","name":"NestedWithRequired","namespace":"pb2","fields":[{"name":"req_string","type":"string"}]}],"default":null},
+			{"name":"rpt_nested","type":{"type":"array","items":"pb2.NestedWithRequired"},"default":[]},
+			{"name":"str_to_nested","type":{"type":"map","values":"pb2.NestedWithRequired"},"default":{}},
+			{"name":"oneof_nested","type":["null","pb2.NestedWithRequired"],"default":null}]}`,
+	}, {
+		msg: &pb2.Maps{},
+		want: `{"type":"record","name":"Maps","namespace":"pb2","fields":[
+			{"name":"int32_to_str","type":{"type":"array","items":{"type":"record","name":"Int32ToStrEntry","namespace":"pb2.Maps","fields":[{"name":"key","type":"int"},{"name":"value","type":"string"}]}},"default":[]},
+			{"name":"str_to_nested","type":{"type":"map","values":{"type":"record","name":"Nested","namespace":"pb2","fields":[
+				{"name":"opt_string","type":["null","string"],"default":null},
+				{"name":"opt_nested","type":["null","pb2.Nested"],"default":null}]}},"default":{}}]}`,
+	}, {
+		msg:  &pb2.KnownTypes{},
+		want: `"opt_timestamp","type":["null",{"type":"long","logicalType":"timestamp-micros"}],"default":null}`,
+	}, {
+		msg:  &testpb.TestAllTypes{},
+		want: `{"name":"singular_nested_enum","type":{"type":"enum","name":"NestedEnum","namespace":"goproto.proto.test3.TestAllTypes","symbols":["FOO","BAR","BAZ","NEG"]},"default":"FOO"}`,
+	}}
+	for _, tt := range tests {
+		md := tt.msg.ProtoReflect().Descriptor()
+		got := protoavro.Schema(md)
+		if !json.Valid([]byte(got)) {
+			t.Errorf("Schema(%v) is not valid JSON: %s", md.FullName(), got)
+		}
+		want := strings.NewReplacer("\n", "", "\t", "").Replace(tt.want)
+		if !strings.Contains(got, want) {
+			t.Errorf("Schema(%v) = %s, want %s", md.FullName(), got, want)
+		}
+	}
+}
+
+func TestParquetSchema(t *testing.T) {
+	got, err := protoavro.ParquetSchema((&pb2.IndirectRequired{}).ProtoReflect().Descriptor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `message pb2.IndirectRequired {
+  optional group opt_nested {
+    required binary req_string (STRING);
+  }
+  required group rpt_nested (LIST) {
+    repeated group list {
+      required group element {
+        required binary req_string (STRING);
+      }
+    }
+  }
+  required group str_to_nested (MAP) {
+    repeated group key_value {
+      required binary key (STRING);
+      required group value {
+        required binary req_string (STRING);
+      }
+    }
+  }
+  optional group oneof_nested {
+    required binary req_string (STRING);
+  }
+}
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ParquetSchema() mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = protoavro.ParquetSchema((&pb2.Scalars{}).ProtoReflect().Descriptor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"  optional int32 opt_sint32;\n",
+		"  optional int64 opt_uint32;\n",
+		"  optional float opt_float;\n",
+		"  optional binary opt_bytes;\n",
+		"  optional binary opt_string (STRING);\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ParquetSchema(pb2.Scalars) = %s, want it to contain %q", got, want)
+		}
+	}
+
+	if _, err := protoavro.ParquetSchema((&pb2.Nests{}).ProtoReflect().Descriptor()); err == nil || !strings.Contains(err.Error(), "recursive message pb2.Nested") {
+		t.Errorf("ParquetSchema(pb2.Nests) error = %v, want recursive message error", err)
+	}
+}
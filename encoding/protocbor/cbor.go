@@ -0,0 +1,282 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocbor
+
+import (
+	"encoding/binary"
+	"math"
+
+	"google.golang.org/protobuf/internal/errors"
+)
+
+// Major types of CBOR data items (RFC 8949, section 3.1).
+const (
+	majorUint   = 0
+	majorNegInt = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+)
+
+// Additional information of major type 7 items (RFC 8949, section 3.3).
+const (
+	simpleFalse   = 20
+	simpleTrue    = 21
+	simpleNull    = 22
+	simpleFloat16 = 25
+	simpleFloat32 = 26
+	simpleFloat64 = 27
+)
+
+// appendHead appends the head of a data item with the given major type
+// and argument, using the shortest encoding of the argument.
+func appendHead(b []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(b, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(b, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, major|27), arg)
+	}
+}
+
+func appendInt(b []byte, v int64) []byte {
+	if v < 0 {
+		return appendHead(b, majorNegInt, uint64(-1-v))
+	}
+	return appendHead(b, majorUint, uint64(v))
+}
+
+func appendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, majorSimple<<5|simpleTrue)
+	}
+	return append(b, majorSimple<<5|simpleFalse)
+}
+
+// appendFloat appends v using the shortest floating-point encoding
+// that preserves its value, as required for deterministic encoding.
+// NaN is always encoded as the half-precision quiet NaN 0x7e00.
+func appendFloat(b []byte, v float64) []byte {
+	if math.IsNaN(v) {
+		return append(b, majorSimple<<5|simpleFloat16, 0x7e, 0x00)
+	}
+	if f := float32(v); float64(f) == v {
+		if h, ok := float16Bits(f); ok {
+			return binary.BigEndian.AppendUint16(append(b, majorSimple<<5|simpleFloat16), h)
+		}
+		return binary.BigEndian.AppendUint32(append(b, majorSimple<<5|simpleFloat32), math.Float32bits(f))
+	}
+	return binary.BigEndian.AppendUint64(append(b, majorSimple<<5|simpleFloat64), math.Float64bits(v))
+}
+
+// float16Bits returns the IEEE 754 half-precision encoding of f,
+// reporting whether f is exactly representable in half precision.
+// It does not handle NaN.
+func float16Bits(f float32) (uint16, bool) {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int(bits>>23) & 0xff
+	mant := bits & 0x7fffff
+	switch {
+	case exp == 0xff: // infinity
+		return sign | 0x7c00, true
+	case exp == 0 && mant == 0: // zero
+		return sign, true
+	case exp == 0: // single-precision subnormal, too small for half precision
+		return 0, false
+	}
+	e := exp - 127
+	switch {
+	case -14 <= e && e <= 15: // normal
+		if mant&0x1fff != 0 {
+			return 0, false
+		}
+		return sign | uint16(e+15)<<10 | uint16(mant>>13), true
+	case -24 <= e && e < -14: // half-precision subnormal
+		m := mant | 1<<23
+		shift := -e - 1
+		if m&(1<<shift-1) != 0 {
+			return 0, false
+		}
+		return sign | uint16(m>>shift), true
+	default:
+		return 0, false
+	}
+}
+
+// float16Value returns the value of the half-precision encoding h.
+func float16Value(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var v float64
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant != 0 {
+			v = math.NaN()
+		} else {
+			v = math.Inf(1)
+		}
+	default:
+		v = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		v = -v
+	}
+	return v
+}
+
+// item is the head of a data item read by the reader.
+type item struct {
+	major byte
+	info  byte   // additional information in the initial byte
+	arg   uint64 // argument, or the bits of a floating-point value
+	pos   int    // offset of the initial byte
+}
+
+// reader reads CBOR data items from an input buffer.
+type reader struct {
+	b   []byte
+	pos int
+}
+
+func (r *reader) newError(pos int, f string, x ...any) error {
+	return errors.New("(offset %d): "+f, append([]any{pos}, x...)...)
+}
+
+// readHead reads the head of the next data item.
+// Indefinite-length items are rejected since they are not permitted
+// by the deterministic encoding, and are rarely produced in practice.
+func (r *reader) readHead() (item, error) {
+	if r.pos >= len(r.b) {
+		return item{}, r.newError(r.pos, "unexpected EOF")
+	}
+	it := item{major: r.b[r.pos] >> 5, info: r.b[r.pos] & 0x1f, pos: r.pos}
+	r.pos++
+	var n int
+	switch {
+	case it.info < 24:
+		it.arg = uint64(it.info)
+		return it, nil
+	case it.info <= 27:
+		n = 1 << (it.info - 24)
+	case it.info == 31:
+		return item{}, r.newError(it.pos, "indefinite-length items are not supported")
+	default:
+		return item{}, r.newError(it.pos, "invalid additional information %d", it.info)
+	}
+	if len(r.b)-r.pos < n {
+		return item{}, r.newError(it.pos, "unexpected EOF")
+	}
+	for _, c := range r.b[r.pos : r.pos+n] {
+		it.arg = it.arg<<8 | uint64(c)
+	}
+	r.pos += n
+	return it, nil
+}
+
+// readString reads the contents of the byte or text string with head it.
+func (r *reader) readString(it item) ([]byte, error) {
+	if it.arg > uint64(len(r.b)-r.pos) {
+		return nil, r.newError(it.pos, "unexpected EOF")
+	}
+	v := r.b[r.pos : r.pos+int(it.arg)]
+	r.pos += int(it.arg)
+	return v, nil
+}
+
+// skip skips the remainder of the data item with head it,
+// which may contain nested items up to the given depth.
+func (r *reader) skip(it item, depth int) error {
+	switch it.major {
+	case majorBytes, majorText:
+		_, err := r.readString(it)
+		return err
+	case majorArray, majorMap, majorTag:
+		if depth--; depth < 0 {
+			return r.newError(it.pos, "exceeded max recursion depth")
+		}
+		n := it.arg
+		switch it.major {
+		case majorMap:
+			if n > math.MaxUint64/2 {
+				return r.newError(it.pos, "unexpected EOF")
+			}
+			n *= 2
+		case majorTag:
+			n = 1
+		}
+		for ; n > 0; n-- {
+			it, err := r.readHead()
+			if err != nil {
+				return err
+			}
+			if err := r.skip(it, depth); err != nil {
+				return err
+			}
+		}
+	case majorSimple:
+		if it.info == 24 && it.arg < 32 {
+			return r.newError(it.pos, "invalid simple value %d", it.arg)
+		}
+	}
+	return nil
+}
+
+// describe returns a description of the kind of the data item with head it
+// for use in error messages.
+func (it item) describe() string {
+	switch it.major {
+	case majorUint, majorNegInt:
+		return "integer"
+	case majorBytes:
+		return "byte string"
+	case majorText:
+		return "text string"
+	case majorArray:
+		return "array"
+	case majorMap:
+		return "map"
+	case majorTag:
+		return "tag"
+	}
+	switch it.info {
+	case simpleFalse, simpleTrue:
+		return "bool"
+	case simpleNull:
+		return "null"
+	case simpleFloat16, simpleFloat32, simpleFloat64:
+		return "float"
+	}
+	return "simple value"
+}
+
+// isNull reports whether it is the null simple value.
+func (it item) isNull() bool {
+	return it.major == majorSimple && it.info == simpleNull
+}
+
+// float returns the value of a floating-point item.
+func (it item) float() float64 {
+	switch it.info {
+	case simpleFloat16:
+		return float16Value(uint16(it.arg))
+	case simpleFloat32:
+		return float64(math.Float32frombits(uint32(it.arg)))
+	default:
+		return math.Float64frombits(it.arg)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocbor
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+)
+
+// Examples from RFC 8949, appendix A, of the preferred serialization
+// of floating-point numbers.
+var floatTests = []struct {
+	v    float64
+	want string
+}{
+	{0.0, "f90000"},
+	{math.Copysign(0, -1), "f98000"},
+	{1.0, "f93c00"},
+	{1.1, "fb3ff199999999999a"},
+	{1.5, "f93e00"},
+	{65504.0, "f97bff"},
+	{100000.0, "fa47c35000"},
+	{3.4028234663852886e+38, "fa7f7fffff"},
+	{1.0e+300, "fb7e37e43c8800759c"},
+	{5.960464477539063e-8, "f90001"},
+	{0.00006103515625, "f90400"},
+	{-4.0, "f9c400"},
+	{-4.1, "fbc010666666666666"},
+	{math.Inf(1), "f97c00"},
+	{math.NaN(), "f97e00"},
+	{math.Inf(-1), "f9fc00"},
+}
+
+func TestAppendFloat(t *testing.T) {
+	for _, tt := range floatTests {
+		if got := hex.EncodeToString(appendFloat(nil, tt.v)); got != tt.want {
+			t.Errorf("appendFloat(%v) = %v, want %v", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestReadFloat(t *testing.T) {
+	for _, tt := range floatTests {
+		b, _ := hex.DecodeString(tt.want)
+		r := &reader{b: b}
+		it, err := r.readHead()
+		if err != nil {
+			t.Errorf("readHead(%v) error: %v", tt.want, err)
+			continue
+		}
+		got := it.float()
+		if got != tt.v && !(math.IsNaN(got) && math.IsNaN(tt.v)) || math.Signbit(got) != math.Signbit(tt.v) {
+			t.Errorf("readHead(%v).float() = %v, want %v", tt.want, got, tt.v)
+		}
+	}
+}
+
+func TestAppendHead(t *testing.T) {
+	for _, tt := range []struct {
+		major byte
+		arg   uint64
+		want  string
+	}{
+		{majorUint, 0, "00"},
+		{majorUint, 23, "17"},
+		{majorUint, 24, "1818"},
+		{majorUint, 255, "18ff"},
+		{majorUint, 256, "190100"},
+		{majorUint, 1000000, "1a000f4240"},
+		{majorUint, 1000000000000, "1b000000e8d4a51000"},
+		{majorNegInt, 99, "3863"},
+		{majorText, 4, "64"},
+		{majorMap, 2, "a2"},
+	} {
+		got := hex.EncodeToString(appendHead(nil, tt.major, tt.arg))
+		if got != tt.want {
+			t.Errorf("appendHead(%d, %d) = %v, want %v", tt.major, tt.arg, got, tt.want)
+		}
+		b, _ := hex.DecodeString(got)
+		r := &reader{b: b}
+		if it, err := r.readHead(); err != nil || it.major != tt.major || it.arg != tt.arg || r.pos != len(b) {
+			t.Errorf("readHead(%v) = (%+v, %v), want major %d, argument %d", got, it, err, tt.major, tt.arg)
+		}
+	}
+}
@@ -0,0 +1,460 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocbor
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/encoding/messageset"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/flags"
+	"google.golang.org/protobuf/internal/pragma"
+	"google.golang.org/protobuf/internal/set"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Unmarshal reads the given []byte into the given [proto.Message].
+// The provided message must be mutable (e.g., a non-nil pointer to a message).
+func Unmarshal(b []byte, m proto.Message) error {
+	return UnmarshalOptions{}.Unmarshal(b, m)
+}
+
+// UnmarshalOptions is a configurable CBOR format parser.
+//
+// Map keys of fields may be the lowerCamelCase name, the proto field name,
+// or the integer field number, regardless of the options used to marshal.
+// Enum values may be either text strings or integers.
+type UnmarshalOptions struct {
+	pragma.NoUnkeyedLiterals
+
+	// If AllowPartial is set, input for messages that will result in missing
+	// required fields will not return an error.
+	AllowPartial bool
+
+	// If DiscardUnknown is set, unknown fields and enum name values are ignored.
+	DiscardUnknown bool
+
+	// Resolver is used for looking up types when unmarshaling extension fields.
+	// If nil, this defaults to using protoregistry.GlobalTypes.
+	Resolver protoregistry.ExtensionTypeResolver
+
+	// RecursionLimit limits how deeply messages may be nested.
+	// If zero, a default limit is applied.
+	RecursionLimit int
+}
+
+// Unmarshal reads the given []byte and populates the given [proto.Message]
+// using options in the UnmarshalOptions object.
+// It will clear the message first before setting the fields.
+// If it returns an error, the given message may be partially set.
+// The provided message must be mutable (e.g., a non-nil pointer to a message).
+func (o UnmarshalOptions) Unmarshal(b []byte, m proto.Message) error {
+	return o.unmarshal(b, m)
+}
+
+// unmarshal is a centralized function that all unmarshal operations go through.
+// For profiling purposes, avoid changing the name of this function or
+// introducing other code paths for unmarshal that do not go through this.
+func (o UnmarshalOptions) unmarshal(b []byte, m proto.Message) error {
+	proto.Reset(m)
+
+	if o.Resolver == nil {
+		o.Resolver = protoregistry.GlobalTypes
+	}
+	if o.RecursionLimit == 0 {
+		o.RecursionLimit = protowire.DefaultRecursionLimit
+	}
+
+	dec := decoder{&reader{b: b}, o}
+	it, err := dec.readHead()
+	if err != nil {
+		return err
+	}
+	if err := dec.unmarshalMessage(it, m.ProtoReflect()); err != nil {
+		return err
+	}
+	if dec.pos < len(dec.b) {
+		return dec.newError(dec.pos, "unexpected data after the message")
+	}
+
+	if o.AllowPartial {
+		return nil
+	}
+	return proto.CheckInitialized(m)
+}
+
+type decoder struct {
+	*reader
+	opts UnmarshalOptions
+}
+
+// unexpectedItemError returns a syntax error for the given unexpected item.
+func (d decoder) unexpectedItemError(it item, fd protoreflect.FieldDescriptor) error {
+	if fd == nil {
+		return d.newError(it.pos, "unexpected %v", it.describe())
+	}
+	return d.newError(it.pos, "invalid value for %v field %v: %v", fd.Kind(), fd.Name(), it.describe())
+}
+
+// unmarshalMessage unmarshals a message from the CBOR map with head it.
+func (d decoder) unmarshalMessage(it item, m protoreflect.Message) error {
+	d.opts.RecursionLimit--
+	if d.opts.RecursionLimit < 0 {
+		return errors.New("exceeded max recursion depth")
+	}
+	if it.major != majorMap {
+		return d.unexpectedItemError(it, nil)
+	}
+
+	messageDesc := m.Descriptor()
+	if !flags.ProtoLegacy && messageset.IsMessageSet(messageDesc) {
+		return errors.New("no support for proto1 MessageSets")
+	}
+
+	var seenNums set.Ints
+	var seenOneofs set.Ints
+	for n := it.arg; n > 0; n-- {
+		// Read field key.
+		key, err := d.readHead()
+		if err != nil {
+			return err
+		}
+		fd, name, err := d.findField(messageDesc, key)
+		if err != nil {
+			return err
+		}
+		val, err := d.readHead()
+		if err != nil {
+			return err
+		}
+
+		if fd == nil {
+			// Field is unknown.
+			if d.opts.DiscardUnknown {
+				if err := d.skip(val, d.opts.RecursionLimit); err != nil {
+					return err
+				}
+				continue
+			}
+			return d.newError(key.pos, "unknown field %v", name)
+		}
+
+		// Do not allow duplicate fields.
+		num := uint64(fd.Number())
+		if seenNums.Has(num) {
+			return d.newError(key.pos, "duplicate field %v", name)
+		}
+		seenNums.Set(num)
+
+		// Null is treated as an unpopulated field.
+		if val.isNull() {
+			continue
+		}
+
+		switch {
+		case fd.IsList():
+			if err := d.unmarshalList(val, m.Mutable(fd).List(), fd); err != nil {
+				return err
+			}
+		case fd.IsMap():
+			if err := d.unmarshalMap(val, m.Mutable(fd).Map(), fd); err != nil {
+				return err
+			}
+		default:
+			// If field is a oneof, check if it has already been set.
+			if od := fd.ContainingOneof(); od != nil {
+				idx := uint64(od.Index())
+				if seenOneofs.Has(idx) {
+					return d.newError(key.pos, "error parsing %v, oneof %v is already set", fd.FullName(), od.FullName())
+				}
+				seenOneofs.Set(idx)
+			}
+
+			if err := d.unmarshalSingular(val, m, fd); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// findField returns the field of md identified by the map key with head it,
+// or nil if the field is unknown, along with the key for use in errors.
+func (d decoder) findField(md protoreflect.MessageDescriptor, it item) (fd protoreflect.FieldDescriptor, key string, err error) {
+	switch it.major {
+	case majorUint:
+		key = strconv.FormatUint(it.arg, 10)
+		if it.arg > uint64(protowire.MaxValidNumber) {
+			return nil, key, nil
+		}
+		num := protoreflect.FieldNumber(it.arg)
+		if fd = md.Fields().ByNumber(num); fd != nil || !md.ExtensionRanges().Has(num) {
+			break
+		}
+		xt, err := d.opts.Resolver.FindExtensionByNumber(md.FullName(), num)
+		if err != nil && err != protoregistry.NotFound {
+			return nil, key, d.newError(it.pos, "unable to resolve extension %d: %v", num, err)
+		}
+		if xt != nil {
+			fd = xt.TypeDescriptor()
+		}
+	case majorText:
+		b, err := d.readString(it)
+		if err != nil {
+			return nil, "", err
+		}
+		name := string(b)
+		key = strconv.Quote(name)
+		if strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]") {
+			// Only extension names are in [name] format.
+			extName := protoreflect.FullName(name[1 : len(name)-1])
+			xt, err := d.opts.Resolver.FindExtensionByName(extName)
+			if err != nil && err != protoregistry.NotFound {
+				return nil, key, d.newError(it.pos, "unable to resolve %s: %v", key, err)
+			}
+			if xt != nil {
+				fd = xt.TypeDescriptor()
+				if !md.ExtensionRanges().Has(fd.Number()) || fd.ContainingMessage().FullName() != md.FullName() {
+					return nil, key, d.newError(it.pos, "message %v cannot be extended by %v", md.FullName(), fd.FullName())
+				}
+			}
+		} else {
+			// The name can either be the JSON name or the proto field name.
+			fd = md.Fields().ByJSONName(name)
+			if fd == nil {
+				fd = md.Fields().ByTextName(name)
+			}
+		}
+	default:
+		return nil, "", d.newError(it.pos, "invalid field key: %v", it.describe())
+	}
+	if flags.ProtoLegacy {
+		if fd != nil && fd.IsWeak() && fd.Message().IsPlaceholder() {
+			fd = nil // reset since the weak reference is not linked in
+		}
+	}
+	return fd, key, nil
+}
+
+// unmarshalSingular unmarshals to the non-repeated field specified
+// by the given FieldDescriptor.
+func (d decoder) unmarshalSingular(it item, m protoreflect.Message, fd protoreflect.FieldDescriptor) error {
+	var val protoreflect.Value
+	var err error
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		val = m.NewField(fd)
+		err = d.unmarshalMessage(it, val.Message())
+	default:
+		val, err = d.unmarshalScalar(it, fd)
+	}
+	if err != nil {
+		return err
+	}
+	if val.IsValid() {
+		m.Set(fd, val)
+	}
+	return nil
+}
+
+// unmarshalScalar unmarshals a scalar or enum value with head it.
+// It returns an invalid value for unknown enum names with DiscardUnknown.
+func (d decoder) unmarshalScalar(it item, fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+	switch kind := fd.Kind(); kind {
+	case protoreflect.BoolKind:
+		if it.major == majorSimple && (it.info == simpleFalse || it.info == simpleTrue) {
+			return protoreflect.ValueOfBool(it.info == simpleTrue), nil
+		}
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if v, ok := intValue(it); ok && math.MinInt32 <= v && v <= math.MaxInt32 {
+			return protoreflect.ValueOfInt32(int32(v)), nil
+		}
+
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if v, ok := intValue(it); ok {
+			return protoreflect.ValueOfInt64(v), nil
+		}
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if it.major == majorUint && it.arg <= math.MaxUint32 {
+			return protoreflect.ValueOfUint32(uint32(it.arg)), nil
+		}
+
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if it.major == majorUint {
+			return protoreflect.ValueOfUint64(it.arg), nil
+		}
+
+	case protoreflect.FloatKind:
+		if v, ok := floatValue(it); ok {
+			f := float32(v)
+			if !math.IsInf(v, 0) && math.IsInf(float64(f), 0) {
+				break // out of range
+			}
+			return protoreflect.ValueOfFloat32(f), nil
+		}
+
+	case protoreflect.DoubleKind:
+		if v, ok := floatValue(it); ok {
+			return protoreflect.ValueOfFloat64(v), nil
+		}
+
+	case protoreflect.StringKind:
+		if it.major == majorText {
+			b, err := d.readString(it)
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			if !utf8.Valid(b) {
+				return protoreflect.Value{}, errors.InvalidUTF8(string(fd.FullName()))
+			}
+			return protoreflect.ValueOfString(string(b)), nil
+		}
+
+	case protoreflect.BytesKind:
+		if it.major == majorBytes {
+			b, err := d.readString(it)
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			return protoreflect.ValueOfBytes(append([]byte{}, b...)), nil
+		}
+
+	case protoreflect.EnumKind:
+		switch it.major {
+		case majorText:
+			b, err := d.readString(it)
+			if err != nil {
+				return protoreflect.Value{}, err
+			}
+			if ev := fd.Enum().Values().ByName(protoreflect.Name(b)); ev != nil {
+				return protoreflect.ValueOfEnum(ev.Number()), nil
+			}
+			if d.opts.DiscardUnknown {
+				return protoreflect.Value{}, nil
+			}
+			return protoreflect.Value{}, d.newError(it.pos, "invalid value for enum field %v: %q", fd.Name(), b)
+		default:
+			if v, ok := intValue(it); ok && math.MinInt32 <= v && v <= math.MaxInt32 {
+				return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), nil
+			}
+		}
+
+	default:
+		panic(errors.New("unmarshalScalar: invalid scalar kind %v", kind))
+	}
+
+	return protoreflect.Value{}, d.unexpectedItemError(it, fd)
+}
+
+// intValue returns the value of an integer item,
+// reporting whether it is an integer in the range of int64.
+func intValue(it item) (int64, bool) {
+	switch {
+	case it.major == majorUint && it.arg <= math.MaxInt64:
+		return int64(it.arg), true
+	case it.major == majorNegInt && it.arg <= math.MaxInt64:
+		return -1 - int64(it.arg), true
+	}
+	return 0, false
+}
+
+// floatValue returns the value of a floating-point or integer item,
+// reporting whether it is a number.
+func floatValue(it item) (float64, bool) {
+	switch {
+	case it.major == majorUint:
+		return float64(it.arg), true
+	case it.major == majorNegInt:
+		return -1 - float64(it.arg), true
+	case it.major == majorSimple && (it.info == simpleFloat16 || it.info == simpleFloat32 || it.info == simpleFloat64):
+		return it.float(), true
+	}
+	return 0, false
+}
+
+// unmarshalList unmarshals into the given protoreflect.List
+// from the CBOR array with head it.
+func (d decoder) unmarshalList(it item, list protoreflect.List, fd protoreflect.FieldDescriptor) error {
+	if it.major != majorArray {
+		return d.unexpectedItemError(it, fd)
+	}
+	for n := it.arg; n > 0; n-- {
+		elem, err := d.readHead()
+		if err != nil {
+			return err
+		}
+		switch fd.Kind() {
+		case protoreflect.MessageKind, protoreflect.GroupKind:
+			val := list.NewElement()
+			if err := d.unmarshalMessage(elem, val.Message()); err != nil {
+				return err
+			}
+			list.Append(val)
+		default:
+			val, err := d.unmarshalScalar(elem, fd)
+			if err != nil {
+				return err
+			}
+			if val.IsValid() {
+				list.Append(val)
+			}
+		}
+	}
+	return nil
+}
+
+// unmarshalMap unmarshals into the given protoreflect.Map
+// from the CBOR map with head it.
+func (d decoder) unmarshalMap(it item, mmap protoreflect.Map, fd protoreflect.FieldDescriptor) error {
+	if it.major != majorMap {
+		return d.unexpectedItemError(it, fd)
+	}
+	for n := it.arg; n > 0; n-- {
+		keyItem, err := d.readHead()
+		if err != nil {
+			return err
+		}
+		key, err := d.unmarshalScalar(keyItem, fd.MapKey())
+		if err != nil {
+			return err
+		}
+		mkey := key.MapKey()
+
+		// Check for duplicate map key.
+		if mmap.Has(mkey) {
+			return d.newError(keyItem.pos, "duplicate map key %v", key)
+		}
+
+		valItem, err := d.readHead()
+		if err != nil {
+			return err
+		}
+		switch fd.MapValue().Kind() {
+		case protoreflect.MessageKind, protoreflect.GroupKind:
+			val := mmap.NewValue()
+			if err := d.unmarshalMessage(valItem, val.Message()); err != nil {
+				return err
+			}
+			mmap.Set(mkey, val)
+		default:
+			val, err := d.unmarshalScalar(valItem, fd.MapValue())
+			if err != nil {
+				return err
+			}
+			if val.IsValid() {
+				mmap.Set(mkey, val)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocbor_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"google.golang.org/protobuf/encoding/protocbor"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test3"
+	pb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
+)
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		desc    string
+		umo     protocbor.UnmarshalOptions
+		input   string
+		want    proto.Message
+		wantErr bool
+	}{{
+		desc:  "empty map",
+		input: "a0",
+		want:  &testpb.TestAllTypes{},
+	}, {
+		desc:  "JSON name, proto name, and field number keys",
+		input: "a3 6d 73696e67756c6172496e743332 01 6e 73696e67756c61725f696e743634 02 1853 03",
+		want:  &testpb.TestAllTypes{SingularInt32: 1, SingularInt64: 2, SingularUint32: 3},
+	}, {
+		desc:  "half-precision float and integer double",
+		input: "a2 185b f9 3e00 185c 20",
+		want:  &testpb.TestAllTypes{SingularFloat: 1.5, SingularDouble: -1},
+	}, {
+		desc:  "NaN",
+		input: "a1 185c f9 7e00",
+		want:  &testpb.TestAllTypes{SingularDouble: math.NaN()},
+	}, {
+		desc:  "enum name and number",
+		input: "a2 1865 63 424152 1866 20",
+		want:  &testpb.TestAllTypes{SingularNestedEnum: testpb.TestAllTypes_BAR, SingularForeignEnum: -1},
+	}, {
+		desc:  "repeated and map fields",
+		input: "a2 181f 82 01 02 1845 a2 6161 6132 6162 6131",
+		want:  &testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}, MapStringString: map[string]string{"a": "2", "b": "1"}},
+	}, {
+		desc:  "nested message",
+		input: "a1 1862 a1 61 61 03",
+		want:  &testpb.TestAllTypes{SingularNestedMessage: &testpb.TestAllTypes_NestedMessage{A: 3}},
+	}, {
+		desc:  "null is unpopulated",
+		input: "a1 1862 f6",
+		want:  &testpb.TestAllTypes{},
+	}, {
+		desc:  "extension by name and number",
+		input: "a2 72 5b7062322e6f70745f6578745f626f6f6c5d f5 16 63 616263",
+		want: func() proto.Message {
+			m := &pb2.Extensions{}
+			proto.SetExtension(m, pb2.E_OptExtBool, true)
+			proto.SetExtension(m, pb2.E_OptExtString, "abc")
+			return m
+		}(),
+	}, {
+		desc:    "unknown field",
+		input:   "a1 1901f4 01",
+		wantErr: true,
+	}, {
+		desc:  "unknown field with DiscardUnknown",
+		umo:   protocbor.UnmarshalOptions{DiscardUnknown: true},
+		input: "a2 1901f4 a1 01 82 01 02 1851 01",
+		want:  &testpb.TestAllTypes{SingularInt32: 1},
+	}, {
+		desc:    "duplicate field",
+		input:   "a2 1851 01 6d 73696e67756c6172496e743332 01",
+		wantErr: true,
+	}, {
+		desc:    "oneof already set",
+		input:   "a2 186f 01 1870 a0",
+		wantErr: true,
+	}, {
+		desc:    "int32 out of range",
+		input:   "a1 1851 1a 80000000",
+		wantErr: true,
+	}, {
+		desc:    "negative uint32",
+		input:   "a1 1853 20",
+		wantErr: true,
+	}, {
+		desc:    "text string for bytes",
+		input:   "a1 185f 61 61",
+		wantErr: true,
+	}, {
+		desc:    "invalid UTF-8",
+		input:   "a1 1857 61 ff",
+		wantErr: true,
+	}, {
+		desc:    "truncated",
+		input:   "a2 1851 01",
+		wantErr: true,
+	}, {
+		desc:    "trailing data",
+		input:   "a0 00",
+		wantErr: true,
+	}, {
+		desc:    "indefinite-length map",
+		input:   "bf ff",
+		wantErr: true,
+	}, {
+		desc:    "not a map",
+		input:   "80",
+		wantErr: true,
+	}, {
+		desc:    "missing required field",
+		input:   "a0",
+		want:    &pb2.PartialRequired{},
+		wantErr: true,
+	}, {
+		desc:  "missing required field with AllowPartial",
+		umo:   protocbor.UnmarshalOptions{AllowPartial: true},
+		input: "a0",
+		want:  &pb2.PartialRequired{},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var got proto.Message = &testpb.TestAllTypes{}
+			if tt.want != nil {
+				got = tt.want.ProtoReflect().New().Interface()
+			}
+			err := tt.umo.Unmarshal(dhex(tt.input), got)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Unmarshal() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal() error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform(), cmpopts.EquateNaNs()); diff != "" {
+				t.Errorf("Unmarshal() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		SingularInt32:      -5,
+		SingularUint64:     math.MaxUint64,
+		SingularFloat:      float32(math.Inf(-1)),
+		SingularDouble:     0.1,
+		SingularString:     "héllo",
+		SingularBytes:      []byte{0, 1},
+		SingularNestedEnum: testpb.TestAllTypes_BAZ,
+		RepeatedDouble:     []float64{1e300, 5.960464477539063e-8, 65504},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"x": {A: 1, Corecursive: &testpb.TestAllTypes{OptionalInt32: proto.Int32(0)}},
+		},
+		OneofField: &testpb.TestAllTypes_OneofString{OneofString: "o"},
+	}
+	for _, mo := range []protocbor.MarshalOptions{
+		{},
+		{UseProtoNames: true},
+		{UseFieldNumbers: true, UseEnumNumbers: true},
+	} {
+		b, err := mo.Marshal(m)
+		if err != nil {
+			t.Fatalf("Marshal() error: %v", err)
+		}
+		got := &testpb.TestAllTypes{}
+		if err := protocbor.Unmarshal(b, got); err != nil {
+			t.Fatalf("Unmarshal(%x) error: %v", b, err)
+		}
+		if diff := cmp.Diff(m, got, protocmp.Transform()); diff != "" {
+			t.Errorf("round trip with %+v mismatch (-want +got):\n%s", mo, diff)
+		}
+	}
+}
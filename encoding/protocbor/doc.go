@@ -0,0 +1,39 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protocbor marshals and unmarshals protocol buffer messages as
+// CBOR (Concise Binary Object Representation, RFC 8949) data items.
+//
+// A message is represented as a CBOR map from field keys to field values,
+// following the structure of the JSON mapping implemented by package
+// protojson, but with the native CBOR types for numbers and bytes:
+//
+//	╔══════════════════════════╤════════════════════════════════════════╗
+//	║ Protobuf field           │ CBOR data item                         ║
+//	╠══════════════════════════╪════════════════════════════════════════╣
+//	║ message, group           │ map                                    ║
+//	║ repeated                 │ array                                  ║
+//	║ map                      │ map, keyed by text string, integer,    ║
+//	║                          │ or bool according to the key type      ║
+//	║ bool                     │ true or false                          ║
+//	║ integer types            │ unsigned or negative integer           ║
+//	║ float, double            │ floating-point number                  ║
+//	║ string                   │ text string                            ║
+//	║ bytes                    │ byte string                            ║
+//	║ enum                     │ text string of the value name, or      ║
+//	║                          │ integer if the value is not declared   ║
+//	╚══════════════════════════╧════════════════════════════════════════╝
+//
+// Fields are keyed by their lowerCamelCase JSON name by default, and
+// extension fields by their full name in brackets (e.g., "[pkg.ext]").
+// Unknown fields are not marshaled. Well-known types such as
+// google.protobuf.Timestamp are represented as ordinary messages.
+//
+// The marshaler always produces the deterministic encoding specified in
+// RFC 8949, section 4.2.1: integers and lengths use their shortest form,
+// floating-point numbers use the shortest form that preserves their value,
+// map entries are sorted by the bytewise order of their encoded keys,
+// and indefinite-length items are not used. Since such items are rarely
+// produced by other implementations either, the unmarshaler rejects them.
+package protocbor
@@ -0,0 +1,246 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocbor
+
+import (
+	"bytes"
+	"sort"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/internal/encoding/messageset"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/flags"
+	"google.golang.org/protobuf/internal/pragma"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Marshal writes the given [proto.Message] in CBOR format using default options.
+func Marshal(m proto.Message) ([]byte, error) {
+	return MarshalOptions{}.Marshal(m)
+}
+
+// MarshalOptions is a configurable CBOR format marshaler.
+//
+// The output always uses the deterministic encoding of RFC 8949,
+// section 4.2.1, so the output for a given message and set of options
+// is stable across builds of the program.
+type MarshalOptions struct {
+	pragma.NoUnkeyedLiterals
+
+	// AllowPartial allows messages that have missing required fields to marshal
+	// without returning an error. If AllowPartial is false (the default),
+	// Marshal will return error if there are any missing required fields.
+	AllowPartial bool
+
+	// UseProtoNames uses proto field name instead of lowerCamelCase name
+	// as the map key of each field.
+	UseProtoNames bool
+
+	// UseFieldNumbers uses the field number as an integer map key for every
+	// field, including extension fields, which produces a more compact
+	// output that is robust against fields being renamed.
+	// It takes precedence over UseProtoNames.
+	UseFieldNumbers bool
+
+	// UseEnumNumbers emits enum values as integers instead of text strings.
+	UseEnumNumbers bool
+}
+
+// Marshal marshals the given [proto.Message] in the CBOR format using options in
+// MarshalOptions.
+func (o MarshalOptions) Marshal(m proto.Message) ([]byte, error) {
+	return o.marshal(nil, m)
+}
+
+// MarshalAppend appends the CBOR format encoding of m to b,
+// returning the result.
+func (o MarshalOptions) MarshalAppend(b []byte, m proto.Message) ([]byte, error) {
+	return o.marshal(b, m)
+}
+
+// marshal is a centralized function that all marshal operations go through.
+// For profiling purposes, avoid changing the name of this function or
+// introducing other code paths for marshal that do not go through this.
+func (o MarshalOptions) marshal(b []byte, m proto.Message) ([]byte, error) {
+	// Treat nil message interface as an empty message,
+	// in which case the output in an empty CBOR map.
+	if m == nil {
+		return appendHead(b, majorMap, 0), nil
+	}
+
+	enc := encoder{opts: o}
+	b, err := enc.marshalMessage(b, m.ProtoReflect())
+	if err != nil {
+		return nil, err
+	}
+	if o.AllowPartial {
+		return b, nil
+	}
+	return b, proto.CheckInitialized(m)
+}
+
+type encoder struct {
+	opts MarshalOptions
+}
+
+// entry is an entry of a CBOR map, whose key is already encoded.
+type entry struct {
+	key []byte
+	fd  protoreflect.FieldDescriptor
+	v   protoreflect.Value
+}
+
+// sortEntries sorts the entries in the bytewise lexicographic order
+// of their encoded keys, as required for deterministic encoding.
+func sortEntries(entries []entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+}
+
+// marshalMessage marshals the fields in the given protoreflect.Message
+// as a CBOR map.
+func (e encoder) marshalMessage(b []byte, m protoreflect.Message) ([]byte, error) {
+	if !flags.ProtoLegacy && messageset.IsMessageSet(m.Descriptor()) {
+		return nil, errors.New("no support for proto1 MessageSets")
+	}
+
+	var entries []entry
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		entries = append(entries, entry{key: e.fieldKey(fd), fd: fd, v: v})
+		return true
+	})
+	sortEntries(entries)
+
+	b = appendHead(b, majorMap, uint64(len(entries)))
+	for _, ent := range entries {
+		b = append(b, ent.key...)
+		var err error
+		if b, err = e.marshalValue(b, ent.v, ent.fd); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// fieldKey returns the encoded map key of the field.
+func (e encoder) fieldKey(fd protoreflect.FieldDescriptor) []byte {
+	if e.opts.UseFieldNumbers {
+		return appendHead(nil, majorUint, uint64(fd.Number()))
+	}
+	var name string
+	switch {
+	case fd.IsExtension():
+		name = "[" + string(fd.FullName()) + "]"
+	case e.opts.UseProtoNames:
+		name = fd.TextName()
+	default:
+		name = fd.JSONName()
+	}
+	return appendText(nil, name)
+}
+
+// marshalValue marshals the given protoreflect.Value.
+func (e encoder) marshalValue(b []byte, v protoreflect.Value, fd protoreflect.FieldDescriptor) ([]byte, error) {
+	switch {
+	case fd.IsList():
+		return e.marshalList(b, v.List(), fd)
+	case fd.IsMap():
+		return e.marshalMap(b, v.Map(), fd)
+	default:
+		return e.marshalSingular(b, v, fd)
+	}
+}
+
+// marshalSingular marshals the given non-repeated field value. This includes
+// all scalar types, enums, messages, and groups.
+func (e encoder) marshalSingular(b []byte, val protoreflect.Value, fd protoreflect.FieldDescriptor) ([]byte, error) {
+	switch kind := fd.Kind(); kind {
+	case protoreflect.BoolKind:
+		return appendBool(b, val.Bool()), nil
+
+	case protoreflect.StringKind:
+		if !utf8.ValidString(val.String()) {
+			return nil, errors.InvalidUTF8(string(fd.FullName()))
+		}
+		return appendText(b, val.String()), nil
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return appendInt(b, val.Int()), nil
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return appendHead(b, majorUint, val.Uint()), nil
+
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return appendFloat(b, val.Float()), nil
+
+	case protoreflect.BytesKind:
+		return append(appendHead(b, majorBytes, uint64(len(val.Bytes()))), val.Bytes()...), nil
+
+	case protoreflect.EnumKind:
+		num := val.Enum()
+		if !e.opts.UseEnumNumbers {
+			if desc := fd.Enum().Values().ByNumber(num); desc != nil {
+				return appendText(b, string(desc.Name())), nil
+			}
+		}
+		// Use numeric value if there is no enum value descriptor.
+		return appendInt(b, int64(num)), nil
+
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return e.marshalMessage(b, val.Message())
+
+	default:
+		panic(errors.New("%v has unknown kind: %v", fd.FullName(), kind))
+	}
+}
+
+// marshalList marshals the given protoreflect.List as a CBOR array.
+func (e encoder) marshalList(b []byte, list protoreflect.List, fd protoreflect.FieldDescriptor) ([]byte, error) {
+	b = appendHead(b, majorArray, uint64(list.Len()))
+	for i := 0; i < list.Len(); i++ {
+		var err error
+		if b, err = e.marshalSingular(b, list.Get(i), fd); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// marshalMap marshals given protoreflect.Map as a CBOR map, whose keys
+// are CBOR text strings, integers, or booleans according to the key kind.
+func (e encoder) marshalMap(b []byte, mmap protoreflect.Map, fd protoreflect.FieldDescriptor) ([]byte, error) {
+	keyDesc := fd.MapKey()
+	var entries []entry
+	var err error
+	mmap.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		var key []byte
+		if key, err = e.marshalSingular(nil, k.Value(), keyDesc); err != nil {
+			return false
+		}
+		entries = append(entries, entry{key: key, v: v})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortEntries(entries)
+
+	b = appendHead(b, majorMap, uint64(len(entries)))
+	for _, ent := range entries {
+		b = append(b, ent.key...)
+		if b, err = e.marshalSingular(b, ent.v, fd.MapValue()); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func appendText(b []byte, s string) []byte {
+	return append(appendHead(b, majorText, uint64(len(s))), s...)
+}
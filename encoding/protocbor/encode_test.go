@@ -0,0 +1,157 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocbor_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protocbor"
+	"google.golang.org/protobuf/proto"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test3"
+	pb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
+)
+
+// dhex decodes a hex string, ignoring spaces.
+func dhex(s string) []byte {
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		desc    string
+		mo      protocbor.MarshalOptions
+		input   proto.Message
+		want    string
+		wantErr bool
+	}{{
+		desc:  "nil message",
+		input: nil,
+		want:  "a0",
+	}, {
+		desc:  "empty message",
+		input: &testpb.TestAllTypes{},
+		want:  "a0",
+	}, {
+		desc:  "JSON name",
+		input: &testpb.TestAllTypes{SingularInt32: 1},
+		want:  "a1 6d 73696e67756c6172496e743332 01",
+	}, {
+		desc:  "proto name",
+		mo:    protocbor.MarshalOptions{UseProtoNames: true},
+		input: &testpb.TestAllTypes{SingularInt32: 1},
+		want:  "a1 6e 73696e67756c61725f696e743332 01",
+	}, {
+		desc:  "negative integer",
+		mo:    protocbor.MarshalOptions{UseFieldNumbers: true},
+		input: &testpb.TestAllTypes{SingularInt32: -2},
+		want:  "a1 1851 21",
+	}, {
+		desc:  "64-bit integers",
+		mo:    protocbor.MarshalOptions{UseFieldNumbers: true},
+		input: &testpb.TestAllTypes{SingularInt64: -1 << 63, SingularUint64: 1<<64 - 1},
+		want:  "a2 1852 3b 7fffffffffffffff 1854 1b ffffffffffffffff",
+	}, {
+		desc:  "floats",
+		mo:    protocbor.MarshalOptions{UseFieldNumbers: true},
+		input: &testpb.TestAllTypes{SingularFloat: 1.5, SingularDouble: 1.1},
+		want:  "a2 185b f9 3e00 185c fb 3ff199999999999a",
+	}, {
+		desc:  "bytes",
+		mo:    protocbor.MarshalOptions{UseFieldNumbers: true},
+		input: &testpb.TestAllTypes{SingularBytes: []byte{1, 2}},
+		want:  "a1 185f 42 0102",
+	}, {
+		desc:  "enum name",
+		mo:    protocbor.MarshalOptions{UseFieldNumbers: true},
+		input: &testpb.TestAllTypes{SingularNestedEnum: testpb.TestAllTypes_BAR},
+		want:  "a1 1865 63 424152",
+	}, {
+		desc:  "enum number",
+		mo:    protocbor.MarshalOptions{UseFieldNumbers: true, UseEnumNumbers: true},
+		input: &testpb.TestAllTypes{SingularNestedEnum: testpb.TestAllTypes_NEG},
+		want:  "a1 1865 20",
+	}, {
+		desc:  "undeclared enum value",
+		mo:    protocbor.MarshalOptions{UseFieldNumbers: true},
+		input: &testpb.TestAllTypes{SingularNestedEnum: 42},
+		want:  "a1 1865 182a",
+	}, {
+		desc: "keys sorted by encoding",
+		mo:   protocbor.MarshalOptions{UseFieldNumbers: true},
+		input: &testpb.TestAllTypes{
+			SingularInt32: 1,
+			RepeatedInt32: []int32{1, 2},
+			SingularNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A: 3,
+			},
+		},
+		want: "a3 181f 82 01 02 1851 01 1862 a1 01 03",
+	}, {
+		desc:  "map keys sorted by encoding",
+		mo:    protocbor.MarshalOptions{UseFieldNumbers: true},
+		input: &testpb.TestAllTypes{MapStringString: map[string]string{"b": "1", "a": "2", "aa": "3"}},
+		want:  "a1 1845 a3 6161 6132 6162 6131 626161 6133",
+	}, {
+		desc:  "integer and bool map keys",
+		mo:    protocbor.MarshalOptions{UseFieldNumbers: true},
+		input: &testpb.TestAllTypes{MapInt32Int32: map[int32]int32{-1: 1, 1: 2}, MapBoolBool: map[bool]bool{true: false}},
+		want:  "a2 1838 a2 01 02 20 01 1844 a1 f5 f4",
+	}, {
+		desc: "extension",
+		input: func() proto.Message {
+			m := &pb2.Extensions{}
+			proto.SetExtension(m, pb2.E_OptExtBool, true)
+			return m
+		}(),
+		want: "a1 72 5b7062322e6f70745f6578745f626f6f6c5d f5",
+	}, {
+		desc: "extension number",
+		mo:   protocbor.MarshalOptions{UseFieldNumbers: true},
+		input: func() proto.Message {
+			m := &pb2.Extensions{}
+			proto.SetExtension(m, pb2.E_OptExtBool, true)
+			return m
+		}(),
+		want: "a1 15 f5",
+	}, {
+		desc:    "invalid UTF-8",
+		input:   &testpb.TestAllTypes{SingularString: "\xff"},
+		wantErr: true,
+	}, {
+		desc:    "missing required field",
+		input:   &pb2.PartialRequired{},
+		wantErr: true,
+	}, {
+		desc:  "missing required field with AllowPartial",
+		mo:    protocbor.MarshalOptions{AllowPartial: true},
+		input: &pb2.PartialRequired{},
+		want:  "a0",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.mo.Marshal(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Marshal() = %x, want error", b)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Marshal() error: %v", err)
+			}
+			if want := dhex(tt.want); string(b) != string(want) {
+				t.Errorf("Marshal() = %x, want %x", b, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protodelta encodes sequences of numbers in the format of
+// bytes fields with the (braincorp.protobuf.delta.encoded) field option.
+//
+// The sequence is encoded as the zigzag varint encoding of the difference
+// between the bits of each element and the bits of the preceding element
+// (or zero, for the first element), which is compact for sequences of nearby
+// values such as timestamps or counters. The bits of an integer are its two's
+// complement representation and the bits of a floating-point number are its
+// IEEE 754 representation. Differences are computed with wrapping arithmetic
+// in the width of the element type, so that every sequence round-trips.
+// When decoding a sequence of 32-bit elements, only the low 32 bits of each
+// difference are used, just as protobuf decodes int32 fields.
+//
+// The code generated for a field with the encoded option declares the
+// field as a slice of numbers, which the Go protobuf runtime encodes with this
+// package when the message is marshaled and decodes when it is unmarshaled.
+// The package is exported for programs that process the wire format directly.
+package protodelta
+
+import (
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/errors"
+)
+
+// Number is the set of element types of delta encoded sequences.
+type Number interface {
+	int32 | int64 | uint32 | uint64 | float32 | float64
+}
+
+// Size returns the encoded size of s.
+func Size[T Number](s []T) (n int) {
+	var prev uint64
+	for _, v := range s {
+		x, narrow := bitsOf(v)
+		n += protowire.SizeVarint(encodeDelta(x-prev, narrow))
+		prev = x
+	}
+	return n
+}
+
+// Append appends the encoding of s to b.
+func Append[T Number](b []byte, s []T) []byte {
+	var prev uint64
+	for _, v := range s {
+		x, narrow := bitsOf(v)
+		b = protowire.AppendVarint(b, encodeDelta(x-prev, narrow))
+		prev = x
+	}
+	return b
+}
+
+// Decode returns the sequence encoded in b.
+// It returns nil if b is empty.
+func Decode[T Number](b []byte) ([]T, error) {
+	var zero T
+	_, narrow := bitsOf(zero)
+	var s []T
+	var prev uint64
+	for len(b) > 0 {
+		d, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return nil, errors.New("invalid delta encoding at element %d: %v", len(s), protowire.ParseError(n))
+		}
+		b = b[n:]
+		prev += decodeDelta(d, narrow)
+		if narrow {
+			prev = uint64(uint32(prev))
+		}
+		s = append(s, fromBits[T](prev))
+	}
+	return s, nil
+}
+
+// Valid reports whether b is a valid encoding of a sequence.
+func Valid(b []byte) bool {
+	for len(b) > 0 {
+		_, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+	}
+	return true
+}
+
+// encodeDelta returns the zigzag encoding of the difference d,
+// which is interpreted as a 32-bit difference if narrow is set.
+func encodeDelta(d uint64, narrow bool) uint64 {
+	if narrow {
+		return protowire.EncodeZigZag(int64(int32(uint32(d))))
+	}
+	return protowire.EncodeZigZag(int64(d))
+}
+
+// decodeDelta returns the difference encoded by x,
+// which is truncated to a 32-bit difference if narrow is set.
+func decodeDelta(x uint64, narrow bool) uint64 {
+	if narrow {
+		return uint64(uint32(protowire.DecodeZigZag(uint64(uint32(x)))))
+	}
+	return uint64(protowire.DecodeZigZag(x))
+}
+
+// bitsOf returns the bits of v and reports whether v has a 32-bit type.
+func bitsOf[T Number](v T) (x uint64, narrow bool) {
+	switch v := any(v).(type) {
+	case int32:
+		return uint64(uint32(v)), true
+	case uint32:
+		return uint64(v), true
+	case float32:
+		return uint64(math.Float32bits(v)), true
+	case int64:
+		return uint64(v), false
+	case uint64:
+		return v, false
+	case float64:
+		return math.Float64bits(v), false
+	}
+	panic("unreachable")
+}
+
+// fromBits returns the number with the bits x.
+func fromBits[T Number](x uint64) T {
+	var v T
+	switch p := any(&v).(type) {
+	case *int32:
+		*p = int32(uint32(x))
+	case *uint32:
+		*p = uint32(x)
+	case *float32:
+		*p = math.Float32frombits(uint32(x))
+	case *int64:
+		*p = int64(x)
+	case *uint64:
+		*p = x
+	case *float64:
+		*p = math.Float64frombits(x)
+	}
+	return v
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protodelta_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/protodelta"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		in   []int64
+		want []byte
+	}{
+		{in: nil, want: nil},
+		{in: []int64{0}, want: []byte{0x00}},
+		{in: []int64{1000, 1001, 999}, want: []byte{0xd0, 0x0f, 0x02, 0x03}},
+		{in: []int64{-1, 5, 3}, want: []byte{0x01, 0x0c, 0x03}},
+		{in: []int64{math.MaxInt64, math.MinInt64, 0}},
+		{in: []int64{math.MinInt64, math.MaxInt64}},
+	}
+	for _, tt := range tests {
+		b := protodelta.Append(nil, tt.in)
+		if tt.want != nil && !bytes.Equal(b, tt.want) {
+			t.Errorf("Append(nil, %v) = %x, want %x", tt.in, b, tt.want)
+		}
+		if got := protodelta.Size(tt.in); got != len(b) {
+			t.Errorf("Size(%v) = %v, want %v", tt.in, got, len(b))
+		}
+		got, err := protodelta.Decode[int64](b)
+		if err != nil {
+			t.Errorf("Decode(%x) error: %v", b, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.in, got); diff != "" {
+			t.Errorf("Decode(%x) mismatch (-want +got):\n%s", b, diff)
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, b := range [][]byte{
+		{0x80},
+		{0x02, 0xff, 0xff},
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+	} {
+		if got, err := protodelta.Decode[int64](b); err == nil {
+			t.Errorf("Decode(%x) = %v, want error", b, got)
+		}
+		if protodelta.Valid(b) {
+			t.Errorf("Valid(%x) = true, want false", b)
+		}
+	}
+}
+
+func TestRoundTripKinds(t *testing.T) {
+	testRoundTrip(t, []int32{0, math.MaxInt32, math.MinInt32, -1, 1})
+	testRoundTrip(t, []uint32{0, math.MaxUint32, 7, 3})
+	testRoundTrip(t, []uint64{0, math.MaxUint64, 1 << 63, 3})
+	testRoundTrip(t, []float32{0, 1.5, float32(math.Inf(-1)), -0.25, math.MaxFloat32})
+	testRoundTrip(t, []float64{0, 1.5, math.Inf(1), -0.25, math.SmallestNonzeroFloat64})
+
+	// Differences of 32-bit elements wrap around in 32 bits,
+	// so that they never take more than five bytes.
+	if got, want := protodelta.Append(nil, []int32{math.MinInt32, math.MaxInt32}), []byte{0xff, 0xff, 0xff, 0xff, 0x0f, 0x01}; !bytes.Equal(got, want) {
+		t.Errorf("Append(nil, [MinInt32 MaxInt32]) = %x, want %x", got, want)
+	}
+}
+
+func testRoundTrip[T protodelta.Number](t *testing.T, in []T) {
+	t.Helper()
+	b := protodelta.Append(nil, in)
+	if got := protodelta.Size(in); got != len(b) {
+		t.Errorf("Size(%v) = %v, want %v", in, got, len(b))
+	}
+	got, err := protodelta.Decode[T](b)
+	if err != nil {
+		t.Errorf("Decode(%x) error: %v", b, err)
+		return
+	}
+	if diff := cmp.Diff(in, got); diff != "" {
+		t.Errorf("Decode(%x) mismatch (-want +got):\n%s", b, diff)
+	}
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	// Only the low 32 bits of the differences of 32-bit elements are used.
+	got, err := protodelta.Decode[uint32]([]byte{0x82, 0x80, 0x80, 0x80, 0x10})
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if want := []uint32{1}; !cmp.Equal(got, want) {
+		t.Errorf("Decode = %v, want %v", got, want)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 
+	"google.golang.org/protobuf/encoding/protoknown"
 	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/internal/encoding/json"
 	"google.golang.org/protobuf/internal/encoding/messageset"
@@ -41,6 +42,35 @@ type UnmarshalOptions struct {
 	// If DiscardUnknown is set, unknown fields and enum name values are ignored.
 	DiscardUnknown bool
 
+	// If AllowFieldNumbers is set, JSON object keys consisting of a decimal
+	// field number are accepted in place of field names, as produced by
+	// MarshalOptions.UseFieldNumbers. Extension fields are resolved by
+	// number using the Resolver.
+	AllowFieldNumbers bool
+
+	// If UnknownFieldsStruct is set, unknown members of a JSON object are
+	// stored in the field of the message with this name instead of being
+	// rejected or discarded, provided that the field is a singular
+	// google.protobuf.Struct field. Marshaling with the same
+	// MarshalOptions.UnknownFieldsStruct restores the original members.
+	UnknownFieldsStruct protoreflect.Name
+
+	// If UnknownFieldsNumber is set, unknown members of a JSON object are
+	// stored in the unknown fields of the message instead of being rejected
+	// or discarded. Each member is stored as a length-delimited field with
+	// this number, containing a JSON object with the single member.
+	// Messages that declare a field with this number are not affected.
+	// Marshaling with the same MarshalOptions.UnknownFieldsNumber restores
+	// the original members. UnknownFieldsStruct takes precedence over
+	// UnknownFieldsNumber.
+	UnknownFieldsNumber protowire.Number
+
+	// Behaviors specifies custom behaviors of message types, which are
+	// unmarshaled from JSON strings parsed by the behavior, similarly to the
+	// special handling of the well-known types. If nil, no custom behaviors
+	// are applied.
+	Behaviors *protoknown.Behaviors
+
 	// Resolver is used for looking up types when unmarshaling
 	// google.protobuf.Any messages or extension fields.
 	// If nil, this defaults to using protoregistry.GlobalTypes.
@@ -52,6 +82,31 @@ type UnmarshalOptions struct {
 	// RecursionLimit limits how deeply messages may be nested.
 	// If zero, a default limit is applied.
 	RecursionLimit int
+
+	// If StrictEnums is set, the value of an enum field must be the name or
+	// number of a value declared in the enum. Otherwise, any 32-bit number
+	// is accepted, and names of undeclared values are ignored if
+	// DiscardUnknown is set. StrictEnums takes precedence over DiscardUnknown.
+	StrictEnums bool
+
+	// ResolveEnumName, if set, is called for the name of a value that is not
+	// declared in the enum, such as the name of a value that has since been
+	// renamed. It reports the number of the value with that name, if any.
+	// The number is used even if StrictEnums is set.
+	ResolveEnumName func(ed protoreflect.EnumDescriptor, name string) (protoreflect.EnumNumber, bool)
+
+	// NonFiniteFloats specifies which representation of the non-finite
+	// values of float and double fields is accepted, as produced by
+	// MarshalOptions.NonFiniteFloats. Numbers that are not in the range of
+	// the field are always rejected.
+	NonFiniteFloats NonFiniteFloats
+
+	// If AllErrors is set, unmarshaling continues after an invalid field,
+	// such as an unknown field or a value of the wrong type, by skipping
+	// the field. The returned error then lists the error for every invalid
+	// field along with its position, and wraps each of them. Syntax errors
+	// still stop unmarshaling.
+	AllErrors bool
 }
 
 // Unmarshal reads the given []byte and populates the given [proto.Message]
@@ -76,31 +131,86 @@ func (o UnmarshalOptions) unmarshal(b []byte, m proto.Message) error {
 		o.RecursionLimit = protowire.DefaultRecursionLimit
 	}
 
-	dec := decoder{json.NewDecoder(b), o}
+	dec := decoder{Decoder: json.NewDecoder(b), opts: o}
+	if o.AllErrors {
+		dec.errs = new([]error)
+	}
 	if err := dec.unmarshalMessage(m.ProtoReflect(), false); err != nil {
-		return err
+		return dec.allErrors(err)
 	}
 
 	// Check for EOF.
 	tok, err := dec.Read()
 	if err != nil {
-		return err
+		return dec.allErrors(err)
 	}
 	if tok.Kind() != json.EOF {
-		return dec.unexpectedTokenError(tok)
+		return dec.allErrors(dec.unexpectedTokenError(tok))
 	}
 
 	if o.AllowPartial {
-		return nil
+		return dec.allErrors(nil)
 	}
-	return proto.CheckInitialized(m)
+	return dec.allErrors(proto.CheckInitialized(m))
 }
 
 type decoder struct {
 	*json.Decoder
 	opts UnmarshalOptions
+
+	// errs holds the errors for invalid fields if AllErrors is set.
+	errs *[]error
 }
 
+// fieldError returns an error with position info for an invalid field value.
+// If AllErrors is set, it records the error and returns nil instead,
+// in which case the caller must skip the value.
+func (d decoder) fieldError(pos int, f string, x ...any) error {
+	err := d.newError(pos, f, x...)
+	if d.errs == nil {
+		return err
+	}
+	*d.errs = append(*d.errs, err)
+	return nil
+}
+
+// skipField returns an error for the field whose name was just read,
+// as for fieldError. If AllErrors is set, it skips the field value instead.
+func (d decoder) skipField(pos int, f string, x ...any) error {
+	if err := d.fieldError(pos, f, x...); err != nil {
+		return err
+	}
+	return d.skipJSONValue()
+}
+
+// allErrors returns err along with the errors recorded for invalid fields.
+func (d decoder) allErrors(err error) error {
+	if d.errs == nil || len(*d.errs) == 0 {
+		return err
+	}
+	errs := *d.errs
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errorList(errs)
+}
+
+// errorList is a list of errors reported for a message if AllErrors is set.
+type errorList []error
+
+func (e errorList) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+func (e errorList) Unwrap() []error { return e }
+
 // newError returns an error object with position info.
 func (d decoder) newError(pos int, f string, x ...any) error {
 	line, column := d.Position(pos)
@@ -126,7 +236,7 @@ func (d decoder) unmarshalMessage(m protoreflect.Message, skipTypeURL bool) erro
 	if d.opts.RecursionLimit < 0 {
 		return errors.New("exceeded max recursion depth")
 	}
-	if unmarshal := wellKnownTypeUnmarshaler(m.Descriptor().FullName()); unmarshal != nil {
+	if unmarshal := d.wellKnownTypeUnmarshaler(m.Descriptor().FullName()); unmarshal != nil {
 		return unmarshal(d, m)
 	}
 
@@ -182,7 +292,10 @@ func (d decoder) unmarshalMessage(m protoreflect.Message, skipTypeURL bool) erro
 			if extType != nil {
 				fd = extType.TypeDescriptor()
 				if !messageDesc.ExtensionRanges().Has(fd.Number()) || fd.ContainingMessage().FullName() != messageDesc.FullName() {
-					return d.newError(tok.Pos(), "message %v cannot be extended by %v", messageDesc.FullName(), fd.FullName())
+					if err := d.skipField(tok.Pos(), "message %v cannot be extended by %v", messageDesc.FullName(), fd.FullName()); err != nil {
+						return err
+					}
+					continue
 				}
 			}
 		} else {
@@ -191,6 +304,11 @@ func (d decoder) unmarshalMessage(m protoreflect.Message, skipTypeURL bool) erro
 			if fd == nil {
 				fd = fieldDescs.ByTextName(name)
 			}
+			if fd == nil && d.opts.AllowFieldNumbers {
+				if fd, err = d.findFieldByNumber(messageDesc, name); err != nil {
+					return d.newError(tok.Pos(), "unable to resolve %s: %v", tok.RawString(), err)
+				}
+			}
 		}
 		if flags.ProtoLegacy {
 			if fd != nil && fd.IsWeak() && fd.Message().IsPlaceholder() {
@@ -200,19 +318,30 @@ func (d decoder) unmarshalMessage(m protoreflect.Message, skipTypeURL bool) erro
 
 		if fd == nil {
 			// Field is unknown.
+			if ok, err := d.stashUnknown(m, name); err != nil {
+				return err
+			} else if ok {
+				continue
+			}
 			if d.opts.DiscardUnknown {
 				if err := d.skipJSONValue(); err != nil {
 					return err
 				}
 				continue
 			}
-			return d.newError(tok.Pos(), "unknown field %v", tok.RawString())
+			if err := d.skipField(tok.Pos(), "unknown field %v", tok.RawString()); err != nil {
+				return err
+			}
+			continue
 		}
 
 		// Do not allow duplicate fields.
 		num := uint64(fd.Number())
 		if seenNums.Has(num) {
-			return d.newError(tok.Pos(), "duplicate field %v", tok.RawString())
+			if err := d.skipField(tok.Pos(), "duplicate field %v", tok.RawString()); err != nil {
+				return err
+			}
+			continue
 		}
 		seenNums.Set(num)
 
@@ -239,7 +368,10 @@ func (d decoder) unmarshalMessage(m protoreflect.Message, skipTypeURL bool) erro
 			if od := fd.ContainingOneof(); od != nil {
 				idx := uint64(od.Index())
 				if seenOneofs.Has(idx) {
-					return d.newError(tok.Pos(), "error parsing %s, oneof %v is already set", tok.RawString(), od.FullName())
+					if err := d.skipField(tok.Pos(), "error parsing %s, oneof %v is already set", tok.RawString(), od.FullName()); err != nil {
+						return err
+					}
+					continue
 				}
 				seenOneofs.Set(idx)
 			}
@@ -252,6 +384,35 @@ func (d decoder) unmarshalMessage(m protoreflect.Message, skipTypeURL bool) erro
 	}
 }
 
+// findFieldByNumber returns the field or extension field of md whose number
+// is given by the decimal string s. It returns nil if s is not a field number
+// or if no such field is known.
+func (d decoder) findFieldByNumber(md protoreflect.MessageDescriptor, s string) (protoreflect.FieldDescriptor, error) {
+	if s == "" || s[0] < '1' || s[0] > '9' {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return nil, nil
+	}
+	num := protoreflect.FieldNumber(n)
+	if fd := md.Fields().ByNumber(num); fd != nil {
+		return fd, nil
+	}
+	if !md.ExtensionRanges().Has(num) {
+		return nil, nil
+	}
+	xt, err := d.opts.Resolver.FindExtensionByNumber(md.FullName(), num)
+	switch err {
+	case nil:
+		return xt.TypeDescriptor(), nil
+	case protoregistry.NotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
 func isKnownValue(fd protoreflect.FieldDescriptor) bool {
 	md := fd.Message()
 	return md != nil && md.FullName() == genid.Value_message_fullname
@@ -323,12 +484,12 @@ func (d decoder) unmarshalScalar(fd protoreflect.FieldDescriptor) (protoreflect.
 		}
 
 	case protoreflect.FloatKind:
-		if v, ok := unmarshalFloat(tok, b32); ok {
+		if v, ok := d.unmarshalFloat(tok, b32); ok {
 			return v, nil
 		}
 
 	case protoreflect.DoubleKind:
-		if v, ok := unmarshalFloat(tok, b64); ok {
+		if v, ok := d.unmarshalFloat(tok, b64); ok {
 			return v, nil
 		}
 
@@ -343,7 +504,7 @@ func (d decoder) unmarshalScalar(fd protoreflect.FieldDescriptor) (protoreflect.
 		}
 
 	case protoreflect.EnumKind:
-		if v, ok := unmarshalEnum(tok, fd, d.opts.DiscardUnknown); ok {
+		if v, ok := d.unmarshalEnum(tok, fd); ok {
 			return v, nil
 		}
 
@@ -351,7 +512,10 @@ func (d decoder) unmarshalScalar(fd protoreflect.FieldDescriptor) (protoreflect.
 		panic(fmt.Sprintf("unmarshalScalar: invalid scalar kind %v", kind))
 	}
 
-	return protoreflect.Value{}, d.newError(tok.Pos(), "invalid value for %v field %v: %v", kind, fd.JSONName(), tok.RawString())
+	if err := d.fieldError(tok.Pos(), "invalid value for %v field %v: %v", kind, fd.JSONName(), tok.RawString()); err != nil {
+		return protoreflect.Value{}, err
+	}
+	return protoreflect.Value{}, d.skipRest(tok)
 }
 
 func unmarshalInt(tok json.Token, bitSize int) (protoreflect.Value, bool) {
@@ -418,13 +582,28 @@ func getUint(tok json.Token, bitSize int) (protoreflect.Value, bool) {
 	return protoreflect.ValueOfUint64(n), true
 }
 
-func unmarshalFloat(tok json.Token, bitSize int) (protoreflect.Value, bool) {
+func (d decoder) unmarshalFloat(tok json.Token, bitSize int) (protoreflect.Value, bool) {
 	switch tok.Kind() {
 	case json.Number:
 		return getFloat(tok, bitSize)
 
+	case json.Null:
+		if d.opts.NonFiniteFloats != NonFiniteNull {
+			return protoreflect.Value{}, false
+		}
+		if bitSize == 32 {
+			return protoreflect.ValueOfFloat32(float32(math.NaN())), true
+		}
+		return protoreflect.ValueOfFloat64(math.NaN()), true
+
 	case json.String:
 		s := tok.ParsedString()
+		if d.opts.NonFiniteFloats != NonFiniteStrings {
+			switch s {
+			case "NaN", "Infinity", "-Infinity":
+				return protoreflect.Value{}, false
+			}
+		}
 		switch s {
 		case "NaN":
 			if bitSize == 32 {
@@ -488,7 +667,7 @@ func unmarshalBytes(tok json.Token) (protoreflect.Value, bool) {
 	return protoreflect.ValueOfBytes(b), true
 }
 
-func unmarshalEnum(tok json.Token, fd protoreflect.FieldDescriptor, discardUnknown bool) (protoreflect.Value, bool) {
+func (d decoder) unmarshalEnum(tok json.Token, fd protoreflect.FieldDescriptor) (protoreflect.Value, bool) {
 	switch tok.Kind() {
 	case json.String:
 		// Lookup EnumNumber based on name.
@@ -496,12 +675,20 @@ func unmarshalEnum(tok json.Token, fd protoreflect.FieldDescriptor, discardUnkno
 		if enumVal := fd.Enum().Values().ByName(protoreflect.Name(s)); enumVal != nil {
 			return protoreflect.ValueOfEnum(enumVal.Number()), true
 		}
-		if discardUnknown {
+		if d.opts.ResolveEnumName != nil {
+			if n, ok := d.opts.ResolveEnumName(fd.Enum(), s); ok {
+				return protoreflect.ValueOfEnum(n), true
+			}
+		}
+		if d.opts.DiscardUnknown && !d.opts.StrictEnums {
 			return protoreflect.Value{}, true
 		}
 
 	case json.Number:
 		if n, ok := tok.Int(32); ok {
+			if d.opts.StrictEnums && fd.Enum().Values().ByNumber(protoreflect.EnumNumber(n)) == nil {
+				return protoreflect.Value{}, false
+			}
 			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), true
 		}
 
@@ -617,10 +804,19 @@ Loop:
 		if err != nil {
 			return err
 		}
+		if !pkey.IsValid() {
+			if err := d.skipJSONValue(); err != nil {
+				return err
+			}
+			continue
+		}
 
 		// Check for duplicate field name.
 		if mmap.Has(pkey) {
-			return d.newError(tok.Pos(), "duplicate map key %v", tok.RawString())
+			if err := d.skipField(tok.Pos(), "duplicate map key %v", tok.RawString()); err != nil {
+				return err
+			}
+			continue
 		}
 
 		// Read and unmarshal field value.
@@ -681,5 +877,5 @@ func (d decoder) unmarshalMapKey(tok json.Token, fd protoreflect.FieldDescriptor
 		panic(fmt.Sprintf("invalid kind for map key: %v", kind))
 	}
 
-	return protoreflect.MapKey{}, d.newError(tok.Pos(), "invalid value for %v key: %s", kind, tok.RawString())
+	return protoreflect.MapKey{}, d.fieldError(tok.Pos(), "invalid value for %v key: %s", kind, tok.RawString())
 }
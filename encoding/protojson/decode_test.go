@@ -13,7 +13,9 @@ import (
 	"google.golang.org/protobuf/internal/errors"
 	"google.golang.org/protobuf/internal/flags"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/testing/protopack"
 
 	testpb "google.golang.org/protobuf/internal/testprotos/test"
 	weakpb "google.golang.org/protobuf/internal/testprotos/test/weak1"
@@ -43,6 +45,120 @@ func TestUnmarshal(t *testing.T) {
 		inputMessage: &pb2.Scalars{},
 		inputText:    "{}",
 		wantMessage:  &pb2.Scalars{},
+	}, {
+		desc:         "field numbers as keys",
+		umo:          protojson.UnmarshalOptions{AllowFieldNumbers: true},
+		inputMessage: &pb2.Extensions{},
+		inputText: `{
+  "1": "non-extension field",
+  "optInt32": 42,
+  "21": true,
+  "24": {"1": "nested in an extension"}
+}`,
+		wantMessage: func() proto.Message {
+			m := &pb2.Extensions{
+				OptString: proto.String("non-extension field"),
+				OptInt32:  proto.Int32(42),
+			}
+			proto.SetExtension(m, pb2.E_OptExtBool, true)
+			proto.SetExtension(m, pb2.E_OptExtNested, &pb2.Nested{
+				OptString: proto.String("nested in an extension"),
+			})
+			return m
+		}(),
+	}, {
+		desc:         "field numbers as keys not allowed",
+		inputMessage: &pb2.Extensions{},
+		inputText:    `{"1": "non-extension field"}`,
+		wantErr:      `unknown field "1"`,
+	}, {
+		desc:         "field numbers as keys with unknown number",
+		umo:          protojson.UnmarshalOptions{AllowFieldNumbers: true},
+		inputMessage: &pb2.Extensions{},
+		inputText:    `{"99": true}`,
+		wantErr:      `unknown field "99"`,
+	}, {
+		desc:         "field numbers as keys with leading zero",
+		umo:          protojson.UnmarshalOptions{AllowFieldNumbers: true},
+		inputMessage: &pb2.Extensions{},
+		inputText:    `{"01": "x"}`,
+		wantErr:      `unknown field "01"`,
+	}, {
+		desc:         "field numbers as keys with duplicate name",
+		umo:          protojson.UnmarshalOptions{AllowFieldNumbers: true},
+		inputMessage: &pb2.Extensions{},
+		inputText:    `{"1": "x", "optString": "y"}`,
+		wantErr:      `duplicate field "optString"`,
+	}, {
+		desc:         "unknown fields stored in Struct field",
+		umo:          protojson.UnmarshalOptions{UnknownFieldsStruct: "opt_struct"},
+		inputMessage: &pb2.KnownTypes{},
+		inputText: `{
+  "optBool": true,
+  "foo": 1,
+  "bar": {"x": [1, "s", null]}
+}`,
+		wantMessage: &pb2.KnownTypes{
+			OptBool: wrapperspb.Bool(true),
+			OptStruct: &structpb.Struct{
+				Fields: map[string]*structpb.Value{
+					"foo": structpb.NewNumberValue(1),
+					"bar": structpb.NewStructValue(&structpb.Struct{
+						Fields: map[string]*structpb.Value{
+							"x": structpb.NewListValue(&structpb.ListValue{
+								Values: []*structpb.Value{
+									structpb.NewNumberValue(1),
+									structpb.NewStringValue("s"),
+									structpb.NewNullValue(),
+								},
+							}),
+						},
+					}),
+				},
+			},
+		},
+	}, {
+		desc:         "unknown fields stored in non-Struct field",
+		umo:          protojson.UnmarshalOptions{UnknownFieldsStruct: "opt_bool"},
+		inputMessage: &pb2.KnownTypes{},
+		inputText:    `{"foo": 1}`,
+		wantErr:      `unknown field "foo"`,
+	}, {
+		desc:         "unknown fields stored in unknown fields",
+		umo:          protojson.UnmarshalOptions{UnknownFieldsNumber: 1000},
+		inputMessage: &pb2.Scalars{},
+		inputText: `{
+  "optBool": true,
+  "foo": {"a": [1, -2.5, true]},
+  "bar": "x"
+}`,
+		wantMessage: func() proto.Message {
+			m := &pb2.Scalars{OptBool: proto.Bool(true)}
+			m.ProtoReflect().SetUnknown(protopack.Message{
+				protopack.Tag{1000, protopack.BytesType}, protopack.String(`{"foo":{"a":[1,-2.5,true]}}`),
+				protopack.Tag{1000, protopack.BytesType}, protopack.String(`{"bar":"x"}`),
+			}.Marshal())
+			return m
+		}(),
+	}, {
+		desc:         "unknown numbers stored in unknown fields without loss of precision",
+		umo:          protojson.UnmarshalOptions{UnknownFieldsNumber: 1000},
+		inputMessage: &pb2.Scalars{},
+		inputText:    `{"big": 123456789012345678901234567890, "huge": [1e400, -0.10000000000000000001]}`,
+		wantMessage: func() proto.Message {
+			m := &pb2.Scalars{}
+			m.ProtoReflect().SetUnknown(protopack.Message{
+				protopack.Tag{1000, protopack.BytesType}, protopack.String(`{"big":123456789012345678901234567890}`),
+				protopack.Tag{1000, protopack.BytesType}, protopack.String(`{"huge":[1e400,-0.10000000000000000001]}`),
+			}.Marshal())
+			return m
+		}(),
+	}, {
+		desc:         "unknown fields stored with known field number",
+		umo:          protojson.UnmarshalOptions{UnknownFieldsNumber: 1},
+		inputMessage: &pb2.Scalars{},
+		inputText:    `{"foo": 1}`,
+		wantErr:      `unknown field "foo"`,
 	}, {
 		desc:         "unexpected value instead of EOF",
 		inputMessage: &pb2.Scalars{},
@@ -384,6 +500,38 @@ func TestUnmarshal(t *testing.T) {
 			SFloat:  float32(math.Inf(+1)),
 			SDouble: math.Inf(-1),
 		},
+	}, {
+		desc:         "NonFiniteReject with infinity",
+		inputMessage: &pb3.Scalars{},
+		inputText:    `{"sFloat": "Infinity"}`,
+		umo:          protojson.UnmarshalOptions{NonFiniteFloats: protojson.NonFiniteReject},
+		wantErr:      `invalid value for float field sFloat: "Infinity"`,
+	}, {
+		desc:         "NonFiniteReject with finite values",
+		inputMessage: &pb3.Scalars{},
+		inputText:    `{"sFloat": "1.5", "sDouble": -2.5}`,
+		umo:          protojson.UnmarshalOptions{NonFiniteFloats: protojson.NonFiniteReject},
+		wantMessage:  &pb3.Scalars{SFloat: 1.5, SDouble: -2.5},
+	}, {
+		desc:         "NonFiniteNull with NaN string",
+		inputMessage: &pb3.Scalars{},
+		inputText:    `{"sDouble": "NaN"}`,
+		umo:          protojson.UnmarshalOptions{NonFiniteFloats: protojson.NonFiniteNull},
+		wantErr:      `invalid value for double field sDouble: "NaN"`,
+	}, {
+		desc:         "NonFiniteNull",
+		inputMessage: &pb3.Repeats{},
+		inputText:    `{"rptFloat": [null, 1], "rptDouble": [null]}`,
+		umo:          protojson.UnmarshalOptions{NonFiniteFloats: protojson.NonFiniteNull},
+		wantMessage: &pb3.Repeats{
+			RptFloat:  []float32{float32(math.NaN()), 1},
+			RptDouble: []float64{math.NaN()},
+		},
+	}, {
+		desc:         "null in list without NonFiniteNull",
+		inputMessage: &pb3.Repeats{},
+		inputText:    `{"rptDouble": [null]}`,
+		wantErr:      `invalid value for double field rptDouble: null`,
 	}, {
 		desc:         "float string with leading space",
 		inputMessage: &pb3.Scalars{},
@@ -585,6 +733,41 @@ func TestUnmarshal(t *testing.T) {
 			SEnum:       101,
 			SNestedEnum: -101,
 		},
+	}, {
+		desc:         "StrictEnums: unnamed numeric value",
+		umo:          protojson.UnmarshalOptions{StrictEnums: true},
+		inputMessage: &pb3.Enums{},
+		inputText:    `{"sEnum": 101}`,
+		wantErr:      `invalid value for enum field sEnum: 101`,
+	}, {
+		desc:         "StrictEnums: named numeric value",
+		umo:          protojson.UnmarshalOptions{StrictEnums: true},
+		inputMessage: &pb3.Enums{},
+		inputText:    `{"sEnum": 2, "sNestedEnum": "DIEZ"}`,
+		wantMessage: &pb3.Enums{
+			SEnum:       pb3.Enum_TWO,
+			SNestedEnum: pb3.Enums_DIEZ,
+		},
+	}, {
+		desc:         "StrictEnums: invalid name with DiscardUnknown",
+		umo:          protojson.UnmarshalOptions{StrictEnums: true, DiscardUnknown: true},
+		inputMessage: &pb3.Enums{},
+		inputText:    `{"sEnum": "UNKNOWN"}`,
+		wantErr:      `invalid value for enum field sEnum: "UNKNOWN"`,
+	}, {
+		desc: "ResolveEnumName",
+		umo: protojson.UnmarshalOptions{
+			StrictEnums: true,
+			ResolveEnumName: func(ed protoreflect.EnumDescriptor, name string) (protoreflect.EnumNumber, bool) {
+				if ed.FullName() == "pb3.Enum" && name == "LEGACY_TWO" {
+					return protoreflect.EnumNumber(pb3.Enum_TWO), true
+				}
+				return 0, false
+			},
+		},
+		inputMessage: &pb3.Enums{},
+		inputText:    `{"sEnum": "LEGACY_TWO"}`,
+		wantMessage:  &pb3.Enums{SEnum: pb3.Enum_TWO},
 	}, {
 		desc:         "enum set to number string",
 		inputMessage: &pb3.Enums{},
@@ -1164,7 +1347,7 @@ func TestUnmarshal(t *testing.T) {
 		desc:         "required fields not set",
 		inputMessage: &pb2.Requireds{},
 		inputText:    `{}`,
-		wantErr:      errors.RequiredNotSet("pb2.Requireds.req_bool").Error(),
+		wantErr:      errors.RequiredNotSet(`(pb2.Requireds).req_bool`).Error(),
 	}, {
 		desc:         "required field set",
 		inputMessage: &pb2.PartialRequired{},
@@ -1189,7 +1372,7 @@ func TestUnmarshal(t *testing.T) {
 			ReqString:   proto.String("hello"),
 			ReqEnum:     pb2.Enum_ONE.Enum(),
 		},
-		wantErr: errors.RequiredNotSet("pb2.Requireds.req_double").Error(),
+		wantErr: errors.RequiredNotSet(`(pb2.Requireds).req_double`).Error(),
 	}, {
 		desc:         "required fields partially set with AllowPartial",
 		umo:          protojson.UnmarshalOptions{AllowPartial: true},
@@ -1234,7 +1417,7 @@ func TestUnmarshal(t *testing.T) {
 		wantMessage: &pb2.IndirectRequired{
 			OptNested: &pb2.NestedWithRequired{},
 		},
-		wantErr: errors.RequiredNotSet("pb2.NestedWithRequired.req_string").Error(),
+		wantErr: errors.RequiredNotSet(`(pb2.IndirectRequired).opt_nested.req_string`).Error(),
 	}, {
 		desc:         "indirect required field with AllowPartial",
 		umo:          protojson.UnmarshalOptions{AllowPartial: true},
@@ -1262,7 +1445,7 @@ func TestUnmarshal(t *testing.T) {
 				{},
 			},
 		},
-		wantErr: errors.RequiredNotSet("pb2.NestedWithRequired.req_string").Error(),
+		wantErr: errors.RequiredNotSet(`(pb2.IndirectRequired).rpt_nested[1].req_string`).Error(),
 	}, {
 		desc:         "indirect required field in repeated with AllowPartial",
 		umo:          protojson.UnmarshalOptions{AllowPartial: true},
@@ -1300,7 +1483,7 @@ func TestUnmarshal(t *testing.T) {
 				},
 			},
 		},
-		wantErr: errors.RequiredNotSet("pb2.NestedWithRequired.req_string").Error(),
+		wantErr: errors.RequiredNotSet(`(pb2.IndirectRequired).str_to_nested["missing"].req_string`).Error(),
 	}, {
 		desc:         "indirect required field in map with AllowPartial",
 		umo:          protojson.UnmarshalOptions{AllowPartial: true},
@@ -1332,7 +1515,7 @@ func TestUnmarshal(t *testing.T) {
 				OneofNested: &pb2.NestedWithRequired{},
 			},
 		},
-		wantErr: errors.RequiredNotSet("pb2.NestedWithRequired.req_string").Error(),
+		wantErr: errors.RequiredNotSet(`(pb2.IndirectRequired).oneof_nested.req_string`).Error(),
 	}, {
 		desc:         "indirect required field in oneof with AllowPartial",
 		umo:          protojson.UnmarshalOptions{AllowPartial: true},
@@ -2815,3 +2998,116 @@ func TestUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalAllErrors(t *testing.T) {
+	tests := []struct {
+		desc         string
+		inputMessage proto.Message
+		inputText    string
+		wantErrs     []string
+		wantMessage  proto.Message
+	}{{
+		desc:         "valid",
+		inputMessage: &testpb.TestAllTypes{},
+		inputText:    `{"optionalInt32": 1}`,
+		wantMessage:  &testpb.TestAllTypes{OptionalInt32: proto.Int32(1)},
+	}, {
+		desc:         "single error",
+		inputMessage: &testpb.TestAllTypes{},
+		inputText:    `{"unknown": {"a": [1]}, "optionalInt32": 1}`,
+		wantErrs:     []string{`(line 1:2): unknown field "unknown"`},
+	}, {
+		desc:         "field errors",
+		inputMessage: &testpb.TestAllTypes{},
+		inputText: `{
+  "optionalInt32": "one",
+  "optionalString": {"nested": [true]},
+  "optionalNestedMessage": {"a": 1, "b": 2},
+  "repeatedInt64": [1, "two", 3],
+  "mapInt32Int32": {"x": 1, "1": 2, "1": 3},
+  "optionalNestedEnum": "QUX",
+  "optionalBool": true,
+  "optional_bool": false,
+  "oneofUint32": 1,
+  "oneofString": "s",
+  "optionalUint32": 2
+}`,
+		wantErrs: []string{
+			`(line 2:20): invalid value for int32 field optionalInt32: "one"`,
+			`(line 3:21): invalid value for string field optionalString: {`,
+			`(line 4:37): unknown field "b"`,
+			`(line 5:24): invalid value for int64 field repeatedInt64: "two"`,
+			`(line 6:21): invalid value for int32 key: "x"`,
+			`(line 6:37): duplicate map key "1"`,
+			`(line 7:25): invalid value for enum field optionalNestedEnum: "QUX"`,
+			`(line 9:3): duplicate field "optional_bool"`,
+			`(line 11:3): error parsing "oneofString", oneof goproto.proto.test.TestAllTypes.oneof_field is already set`,
+		},
+		wantMessage: &testpb.TestAllTypes{
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)},
+			RepeatedInt64:         []int64{1, 3},
+			MapInt32Int32:         map[int32]int32{1: 2},
+			OptionalBool:          proto.Bool(true),
+			OneofField:            &testpb.TestAllTypes_OneofUint32{OneofUint32: 1},
+			OptionalUint32:        proto.Uint32(2),
+		},
+	}, {
+		desc:         "well-known types",
+		inputMessage: &pb2.KnownTypes{},
+		inputText:    `{"optTimestamp": "now", "optDuration": "1h", "optFieldmask": "a,b_c", "optInt32": 1.5}`,
+		wantErrs: []string{
+			`(line 1:18): invalid google.protobuf.Timestamp value "now"`,
+			`(line 1:40): invalid google.protobuf.Duration value "1h"`,
+			`(line 1:62): google.protobuf.FieldMask.paths contains invalid path: "b_c"`,
+			`(line 1:83): invalid value for int32 field value: 1.5`,
+		},
+	}, {
+		desc:         "syntax error after field errors",
+		inputMessage: &testpb.TestAllTypes{},
+		inputText:    `{"optionalInt32": "one", "optionalInt64": }`,
+		wantErrs: []string{
+			`(line 1:19): invalid value for int32 field optionalInt32: "one"`,
+			`(line 1:43): unexpected token }`,
+		},
+	}, {
+		desc:         "required field",
+		inputMessage: &pb2.Requireds{},
+		inputText:    `{"reqBool": 1}`,
+		wantErrs: []string{
+			`(line 1:13): invalid value for bool field reqBool: 1`,
+			`required field (pb2.Requireds).req_bool not set`,
+		},
+	}}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			err := protojson.UnmarshalOptions{AllErrors: true}.Unmarshal([]byte(tt.inputText), tt.inputMessage)
+			var errs []error
+			switch e := err.(type) {
+			case nil:
+			case interface{ Unwrap() []error }:
+				errs = e.Unwrap()
+			default:
+				errs = []error{err}
+			}
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("Unmarshal() returned %d errors, want %d:\n%v", len(errs), len(tt.wantErrs), err)
+			}
+			for i, err := range errs {
+				if !strings.Contains(err.Error(), tt.wantErrs[i]) {
+					t.Errorf("error %d = %q, want %q", i, err, tt.wantErrs[i])
+				}
+				if !errors.Is(err, proto.Error) {
+					t.Errorf("error %d = %v, want proto.Error", i, err)
+				}
+			}
+			if err != nil && !errors.Is(err, proto.Error) {
+				t.Errorf("Unmarshal() error = %v, want proto.Error", err)
+			}
+			if tt.wantMessage != nil && !proto.Equal(tt.inputMessage, tt.wantMessage) {
+				t.Errorf("Unmarshal()\n<got>\n%v\n<want>\n%v\n", tt.inputMessage, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -7,7 +7,11 @@ package protojson
 import (
 	"encoding/base64"
 	"fmt"
+	"math"
+	"strconv"
 
+	"google.golang.org/protobuf/encoding/protoknown"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/internal/encoding/json"
 	"google.golang.org/protobuf/internal/encoding/messageset"
 	"google.golang.org/protobuf/internal/errors"
@@ -67,6 +71,27 @@ type MarshalOptions struct {
 	// UseEnumNumbers emits enum values as numbers.
 	UseEnumNumbers bool
 
+	// UseIntegersForInt64 emits the values of 64-bit integer fields
+	// (int64, sint64, sfixed64, uint64, and fixed64), including those of
+	// the google.protobuf.Int64Value and google.protobuf.UInt64Value
+	// wrappers, as JSON numbers instead of strings. Map keys are always
+	// emitted as strings.
+	//
+	// The JSON mapping specifies strings for 64-bit integers since many
+	// JSON parsers, such as JavaScript's JSON.parse, decode all numbers as
+	// double-precision floats, which exactly represent integers only up to
+	// 2^53 in magnitude. Larger values silently lose precision with such
+	// parsers, so this option must only be used with consumers that decode
+	// JSON numbers as 64-bit integers. Unmarshal accepts both forms.
+	UseIntegersForInt64 bool
+
+	// UseFieldNumbers uses the decimal field number instead of the field name
+	// as the JSON object key for every field, including extension fields.
+	// The output is robust against fields being renamed, but can only be
+	// parsed by an unmarshaler with UnmarshalOptions.AllowFieldNumbers set.
+	// It takes precedence over UseProtoNames.
+	UseFieldNumbers bool
+
 	// EmitUnpopulated specifies whether to emit unpopulated fields. It does not
 	// emit unpopulated oneof fields or unpopulated extension fields.
 	// The JSON value emitted for unpopulated fields are as follows:
@@ -102,6 +127,56 @@ type MarshalOptions struct {
 	// a strict superset of the latter.
 	EmitDefaultValues bool
 
+	// If UnknownFieldsStruct is set, the members of the message's field with
+	// this name are emitted as members of the message's own JSON object,
+	// provided that the field is a singular google.protobuf.Struct field.
+	// This reverses the effect of UnmarshalOptions.UnknownFieldsStruct.
+	// Members whose names conflict with a field of the message are omitted.
+	UnknownFieldsStruct protoreflect.Name
+
+	// If UnknownFieldsNumber is set, the JSON object members stored in the
+	// unknown fields of the message with this field number are emitted as
+	// members of the message's own JSON object, reversing the effect of
+	// UnmarshalOptions.UnknownFieldsNumber. Members whose names conflict
+	// with a field of the message are omitted.
+	UnknownFieldsNumber protowire.Number
+
+	// EmitUnresolvableAny specifies that a google.protobuf.Any message whose
+	// type cannot be found by the Resolver is emitted as a JSON object with
+	// the "@type" member and a "value" member holding the base64 encoding of
+	// the embedded message's wire format, instead of returning an error.
+	// The output does not conform to the JSON mapping of Any and
+	// cannot be parsed by Unmarshal unless the type is resolvable then.
+	EmitUnresolvableAny bool
+
+	// NonFiniteFloats specifies how the non-finite values NaN, Infinity, and
+	// -Infinity of float and double fields are emitted. By default, they are
+	// emitted as the strings specified by the JSON mapping, which some
+	// strict JSON parsers do not accept in place of numbers.
+	NonFiniteFloats NonFiniteFloats
+
+	// SortKeys specifies the order of the members of the JSON objects for
+	// messages and maps. If set, the output is also stable across builds of
+	// the program, such as for comparison with golden files: the marshaler
+	// does not insert the random whitespace that it otherwise does to
+	// discourage depending on the exact output.
+	// By default, fields are emitted in declaration order, followed by
+	// extension fields, and map entries are sorted by key.
+	SortKeys SortOrder
+
+	// RecursionLimit limits how deeply messages may be nested.
+	// If zero, a default limit is applied.
+	// Marshal returns an error for messages nested more deeply, such as
+	// programmatically constructed google.protobuf.Struct values that
+	// contain themselves, instead of overflowing the stack.
+	RecursionLimit int
+
+	// Behaviors specifies custom behaviors of message types, which are
+	// marshaled as JSON strings produced by the behavior, similarly to the
+	// special handling of the well-known types. If nil, no custom behaviors
+	// are applied.
+	Behaviors *protoknown.Behaviors
+
 	// Resolver is used for looking up types when expanding google.protobuf.Any
 	// messages. If nil, this defaults to using protoregistry.GlobalTypes.
 	Resolver interface {
@@ -110,6 +185,56 @@ type MarshalOptions struct {
 	}
 }
 
+// NonFiniteFloats specifies the representation of the non-finite values of
+// float and double fields in JSON.
+type NonFiniteFloats int
+
+const (
+	// NonFiniteStrings represents non-finite values as the JSON strings
+	// "NaN", "Infinity", and "-Infinity", as specified by the JSON mapping.
+	NonFiniteStrings NonFiniteFloats = iota
+
+	// NonFiniteReject rejects non-finite values. Marshal returns an error
+	// for fields populated with such values, and Unmarshal returns an error
+	// for the strings "NaN", "Infinity", and "-Infinity".
+	NonFiniteReject
+
+	// NonFiniteNull represents non-finite values as JSON null, which loses
+	// the distinction between NaN and the infinities. Unmarshal rejects the
+	// strings "NaN", "Infinity", and "-Infinity" and decodes null elements of
+	// lists and null map values as NaN. As usual, a null singular field is
+	// treated as unpopulated, so the value of such a field does not
+	// round-trip.
+	NonFiniteNull
+)
+
+// SortOrder specifies the order of the members of the JSON objects
+// emitted for messages and maps.
+type SortOrder int
+
+const (
+	// SortUnspecified uses the default order without stabilizing the output.
+	SortUnspecified SortOrder = iota
+
+	// SortByDeclaration emits fields in the order they are declared in the
+	// message, followed by extension fields sorted by full name.
+	// Map entries are sorted by key, with numeric keys in numeric order.
+	// The "@type" member of a google.protobuf.Any is always emitted first.
+	SortByDeclaration
+
+	// SortByNumber emits fields sorted by field number.
+	// Map entries are sorted as for SortByDeclaration.
+	SortByNumber
+
+	// SortByName sorts the members of all objects by their names as
+	// emitted, comparing the UTF-8 encoded names bytewise, such that numeric
+	// map keys are sorted as strings. The "@type" member of a
+	// google.protobuf.Any is still emitted first. The members emitted for
+	// UnknownFieldsStruct and UnknownFieldsNumber are not sorted along with
+	// the fields of the message.
+	SortByName
+)
+
 // Format formats the message as a string.
 // This method is only intended for human consumption and ignores errors.
 // Do not depend on the output being stable. Its output will change across
@@ -148,11 +273,17 @@ func (o MarshalOptions) marshal(b []byte, m proto.Message) ([]byte, error) {
 	if o.Resolver == nil {
 		o.Resolver = protoregistry.GlobalTypes
 	}
+	if o.RecursionLimit == 0 {
+		o.RecursionLimit = protowire.DefaultRecursionLimit
+	}
 
 	internalEnc, err := json.NewEncoder(b, o.Indent)
 	if err != nil {
 		return nil, err
 	}
+	if o.SortKeys != SortUnspecified {
+		internalEnc.SetStable()
+	}
 
 	// Treat nil message interface as an empty message,
 	// in which case the output in an empty JSON object.
@@ -160,7 +291,7 @@ func (o MarshalOptions) marshal(b []byte, m proto.Message) ([]byte, error) {
 		return append(b, '{', '}'), nil
 	}
 
-	enc := encoder{internalEnc, o}
+	enc := encoder{Encoder: internalEnc, opts: o}
 	if err := enc.marshalMessage(m.ProtoReflect(), ""); err != nil {
 		return nil, err
 	}
@@ -173,6 +304,10 @@ func (o MarshalOptions) marshal(b []byte, m proto.Message) ([]byte, error) {
 type encoder struct {
 	*json.Encoder
 	opts MarshalOptions
+
+	// active is the set of google.protobuf.Struct, ListValue, and Value
+	// messages being marshaled, which is used to detect cycles.
+	active map[protoreflect.Message]bool
 }
 
 // typeFieldDesc is a synthetic field descriptor used for the "@type" field.
@@ -233,11 +368,15 @@ func (m unpopulatedFieldRanger) Range(f func(protoreflect.FieldDescriptor, proto
 // If the typeURL is non-empty, then a synthetic "@type" field is injected
 // containing the URL as the value.
 func (e encoder) marshalMessage(m protoreflect.Message, typeURL string) error {
+	e.opts.RecursionLimit--
+	if e.opts.RecursionLimit < 0 {
+		return errors.New("exceeded max recursion depth")
+	}
 	if !flags.ProtoLegacy && messageset.IsMessageSet(m.Descriptor()) {
 		return errors.New("no support for proto1 MessageSets")
 	}
 
-	if marshal := wellKnownTypeMarshaler(m.Descriptor().FullName()); marshal != nil {
+	if marshal := e.wellKnownTypeMarshaler(m.Descriptor().FullName()); marshal != nil {
 		return marshal(e, m)
 	}
 
@@ -255,14 +394,32 @@ func (e encoder) marshalMessage(m protoreflect.Message, typeURL string) error {
 		fields = typeURLFieldRanger{fields, typeURL}
 	}
 
+	md := m.Descriptor()
+	structField := unknownStructField(md, e.opts.UnknownFieldsStruct)
+
+	fieldOrder := order.IndexNameFieldOrder
+	switch e.opts.SortKeys {
+	case SortByNumber:
+		fieldOrder = order.NumberFieldOrder
+	case SortByName:
+		fieldOrder = func(x, y protoreflect.FieldDescriptor) bool {
+			if x == typeFieldDesc || y == typeFieldDesc {
+				return x == typeFieldDesc && y != typeFieldDesc
+			}
+			return e.fieldName(x) < e.fieldName(y)
+		}
+	}
+
 	var err error
-	order.RangeFields(fields, order.IndexNameFieldOrder, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
-		name := fd.JSONName()
-		if e.opts.UseProtoNames {
-			name = fd.TextName()
+	order.RangeFields(fields, fieldOrder, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if structField != nil && fd.Number() == structField.Number() && !fd.IsExtension() {
+			if v.IsValid() {
+				err = e.marshalUnknownStruct(md, v.Message())
+			}
+			return err == nil
 		}
 
-		if err = e.WriteName(name); err != nil {
+		if err = e.WriteName(e.fieldName(fd)); err != nil {
 			return false
 		}
 		if err = e.marshalValue(v, fd); err != nil {
@@ -270,7 +427,25 @@ func (e encoder) marshalMessage(m protoreflect.Message, typeURL string) error {
 		}
 		return true
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	if num := unknownFieldsNumber(md, e.opts.UnknownFieldsNumber); num != 0 {
+		return e.marshalUnknownFields(m, num)
+	}
+	return nil
+}
+
+// fieldName returns the name of the JSON object member for the field.
+func (e encoder) fieldName(fd protoreflect.FieldDescriptor) string {
+	switch {
+	case e.opts.UseFieldNumbers && fd != typeFieldDesc:
+		return strconv.FormatInt(int64(fd.Number()), 10)
+	case e.opts.UseProtoNames:
+		return fd.TextName()
+	default:
+		return fd.JSONName()
+	}
 }
 
 // marshalValue marshals the given protoreflect.Value.
@@ -308,18 +483,37 @@ func (e encoder) marshalSingular(val protoreflect.Value, fd protoreflect.FieldDe
 	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
 		e.WriteUint(val.Uint())
 
-	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Uint64Kind,
-		protoreflect.Sfixed64Kind, protoreflect.Fixed64Kind:
-		// 64-bit integers are written out as JSON string.
-		e.WriteString(val.String())
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		// 64-bit integers are written out as JSON string by default.
+		if e.opts.UseIntegersForInt64 {
+			e.WriteInt(val.Int())
+		} else {
+			e.WriteString(val.String())
+		}
 
-	case protoreflect.FloatKind:
-		// Encoder.WriteFloat handles the special numbers NaN and infinites.
-		e.WriteFloat(val.Float(), 32)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if e.opts.UseIntegersForInt64 {
+			e.WriteUint(val.Uint())
+		} else {
+			e.WriteString(val.String())
+		}
 
-	case protoreflect.DoubleKind:
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		bitSize := 64
+		if kind == protoreflect.FloatKind {
+			bitSize = 32
+		}
+		if n := val.Float(); math.IsNaN(n) || math.IsInf(n, 0) {
+			switch e.opts.NonFiniteFloats {
+			case NonFiniteReject:
+				return errors.New("%v: non-finite value %v is not allowed", fd.FullName(), n)
+			case NonFiniteNull:
+				e.WriteNull()
+				return nil
+			}
+		}
 		// Encoder.WriteFloat handles the special numbers NaN and infinites.
-		e.WriteFloat(val.Float(), 64)
+		e.WriteFloat(val.Float(), bitSize)
 
 	case protoreflect.BytesKind:
 		e.WriteString(base64.StdEncoding.EncodeToString(val.Bytes()))
@@ -366,8 +560,13 @@ func (e encoder) marshalMap(mmap protoreflect.Map, fd protoreflect.FieldDescript
 	e.StartObject()
 	defer e.EndObject()
 
+	keyOrder := order.GenericKeyOrder
+	if e.opts.SortKeys == SortByName {
+		keyOrder = func(x, y protoreflect.MapKey) bool { return x.String() < y.String() }
+	}
+
 	var err error
-	order.RangeEntries(mmap, order.GenericKeyOrder, func(k protoreflect.MapKey, v protoreflect.Value) bool {
+	order.RangeEntries(mmap, keyOrder, func(k protoreflect.MapKey, v protoreflect.Value) bool {
 		if err = e.WriteName(k.String()); err != nil {
 			return false
 		}
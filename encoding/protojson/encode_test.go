@@ -941,6 +941,82 @@ func TestMarshal(t *testing.T) {
   },
   "[pb2.opt_ext_string]": "extension field"
 }`,
+	}, {
+		desc: "UseFieldNumbers",
+		mo:   protojson.MarshalOptions{UseFieldNumbers: true, UseProtoNames: true},
+		input: func() proto.Message {
+			m := &pb2.Extensions{
+				OptString: proto.String("non-extension field"),
+				OptInt32:  proto.Int32(42),
+			}
+			proto.SetExtension(m, pb2.E_OptExtBool, true)
+			proto.SetExtension(m, pb2.E_OptExtNested, &pb2.Nested{
+				OptString: proto.String("nested in an extension"),
+			})
+			return m
+		}(),
+		want: `{
+  "1": "non-extension field",
+  "2": 42,
+  "21": true,
+  "24": {
+    "1": "nested in an extension"
+  }
+}`,
+	}, {
+		desc: "UnknownFieldsStruct",
+		mo:   protojson.MarshalOptions{UnknownFieldsStruct: "opt_struct"},
+		input: &pb2.KnownTypes{
+			OptBool: wrapperspb.Bool(true),
+			OptStruct: &structpb.Struct{
+				Fields: map[string]*structpb.Value{
+					"foo":     structpb.NewNumberValue(1),
+					"bar":     structpb.NewListValue(&structpb.ListValue{}),
+					"optBool": structpb.NewStringValue("conflicts with a field"),
+				},
+			},
+			OptString: wrapperspb.String("after"),
+		},
+		want: `{
+  "optBool": true,
+  "optString": "after",
+  "bar": [],
+  "foo": 1
+}`,
+	}, {
+		desc: "UnknownFieldsNumber",
+		mo:   protojson.MarshalOptions{UnknownFieldsNumber: 1000},
+		input: func() proto.Message {
+			m := &pb2.Scalars{OptBool: proto.Bool(true)}
+			m.ProtoReflect().SetUnknown(protopack.Message{
+				protopack.Tag{1000, protopack.BytesType}, protopack.String(`{"foo":{"a":[1,-2.5,true]}}`),
+				protopack.Tag{1001, protopack.VarintType}, protopack.Varint(1),
+				protopack.Tag{1000, protopack.BytesType}, protopack.String(`{"bar":"x","optBool":false}`),
+			}.Marshal())
+			return m
+		}(),
+		want: `{
+  "optBool": true,
+  "foo": {
+    "a": [
+      1,
+      -2.5,
+      true
+    ]
+  },
+  "bar": "x"
+}`,
+	}, {
+		desc: "UnknownFieldsNumber with invalid JSON",
+		mo:   protojson.MarshalOptions{UnknownFieldsNumber: 1000},
+		input: func() proto.Message {
+			m := &pb2.Scalars{}
+			m.ProtoReflect().SetUnknown(protopack.Message{
+				protopack.Tag{1000, protopack.BytesType}, protopack.String(`["foo"]`),
+			}.Marshal())
+			return m
+		}(),
+		wantErr: true,
 	}, {
 		desc: "extensions of repeated fields",
 		input: func() proto.Message {
@@ -1588,6 +1664,28 @@ func TestMarshal(t *testing.T) {
 		mo:      protojson.MarshalOptions{Resolver: new(protoregistry.Types)},
 		input:   &anypb.Any{TypeUrl: "foo/pb2.Nested"},
 		wantErr: true,
+	}, {
+		desc: "Any without registered type with EmitUnresolvableAny",
+		mo: protojson.MarshalOptions{
+			Resolver:            new(protoregistry.Types),
+			EmitUnresolvableAny: true,
+		},
+		input: &anypb.Any{
+			TypeUrl: "foo/pb2.Nested",
+			Value:   []byte("\x0a\x03abc\x80"),
+		},
+		want: `{
+  "@type": "foo/pb2.Nested",
+  "value": "CgNhYmOA"
+}`,
+	}, {
+		desc: "Any with invalid value and EmitUnresolvableAny",
+		mo:   protojson.MarshalOptions{EmitUnresolvableAny: true},
+		input: &anypb.Any{
+			TypeUrl: "foo/pb2.Nested",
+			Value:   []byte("\x80"),
+		},
+		wantErr: true,
 	}, {
 		desc: "Any with missing required",
 		input: func() proto.Message {
@@ -2409,6 +2507,123 @@ func TestMarshal(t *testing.T) {
   "optFloat": 1.02,
   "optBytes": "6LC35q2M"
 }`,
+	}, {
+		desc: "UseIntegersForInt64 in singular fields",
+		mo:   protojson.MarshalOptions{UseIntegersForInt64: true},
+		input: &pb3.Scalars{
+			SInt64:    -9007199254740993,
+			SUint64:   math.MaxUint64,
+			SSint64:   math.MinInt64,
+			SFixed64:  64,
+			SSfixed64: -64,
+		},
+		want: `{
+  "sInt64": -9007199254740993,
+  "sUint64": 18446744073709551615,
+  "sSint64": -9223372036854775808,
+  "sFixed64": 64,
+  "sSfixed64": -64
+}`,
+	}, {
+		desc: "UseIntegersForInt64 in map field",
+		mo:   protojson.MarshalOptions{UseIntegersForInt64: true},
+		input: &pb3.Maps{
+			Uint64ToEnum: map[uint64]pb3.Enum{10: pb3.Enum_TEN},
+		},
+		want: `{
+  "uint64ToEnum": {
+    "10": "TEN"
+  }
+}`,
+	}, {
+		desc:  "UseIntegersForInt64 in Int64Value",
+		mo:    protojson.MarshalOptions{UseIntegersForInt64: true},
+		input: &wrapperspb.Int64Value{Value: 1 << 60},
+		want:  `1152921504606846976`,
+	}, {
+		desc:    "NonFiniteReject with NaN",
+		mo:      protojson.MarshalOptions{NonFiniteFloats: protojson.NonFiniteReject},
+		input:   &pb3.Scalars{SDouble: math.NaN()},
+		wantErr: true,
+	}, {
+		desc:    "NonFiniteReject with infinity in list",
+		mo:      protojson.MarshalOptions{NonFiniteFloats: protojson.NonFiniteReject},
+		input:   &pb3.Repeats{RptFloat: []float32{1, float32(math.Inf(-1))}},
+		wantErr: true,
+	}, {
+		desc:  "NonFiniteReject with finite values",
+		mo:    protojson.MarshalOptions{NonFiniteFloats: protojson.NonFiniteReject},
+		input: &pb3.Scalars{SFloat: 1.5, SDouble: -2.5},
+		want: `{
+  "sFloat": 1.5,
+  "sDouble": -2.5
+}`,
+	}, {
+		desc: "NonFiniteNull",
+		mo:   protojson.MarshalOptions{NonFiniteFloats: protojson.NonFiniteNull},
+		input: &pb3.Repeats{
+			RptFloat:  []float32{float32(math.NaN()), 1},
+			RptDouble: []float64{math.Inf(1), math.Inf(-1)},
+		},
+		want: `{
+  "rptFloat": [
+    null,
+    1
+  ],
+  "rptDouble": [
+    null,
+    null
+  ]
+}`,
+	}, {
+		desc:  "NonFiniteNull in DoubleValue",
+		mo:    protojson.MarshalOptions{NonFiniteFloats: protojson.NonFiniteNull},
+		input: &wrapperspb.DoubleValue{Value: math.NaN()},
+		want:  `null`,
+	}, {
+		desc: "RecursionLimit",
+		mo:   protojson.MarshalOptions{RecursionLimit: 2},
+		input: &pb2.Nested{
+			OptNested: &pb2.Nested{
+				OptNested: &pb2.Nested{},
+			},
+		},
+		wantErr: true,
+	}, {
+		desc: "RecursionLimit not exceeded",
+		mo:   protojson.MarshalOptions{RecursionLimit: 2},
+		input: &pb2.Nested{
+			OptNested: &pb2.Nested{},
+		},
+		want: `{
+  "optNested": {}
+}`,
+	}, {
+		desc: "Struct containing itself",
+		input: func() proto.Message {
+			s := &structpb.Struct{Fields: map[string]*structpb.Value{}}
+			s.Fields["self"] = structpb.NewStructValue(s)
+			return s
+		}(),
+		wantErr: true,
+	}, {
+		desc: "ListValue containing itself",
+		input: func() proto.Message {
+			l := &structpb.ListValue{}
+			l.Values = append(l.Values, structpb.NewNullValue(), structpb.NewListValue(l))
+			return &structpb.Struct{Fields: map[string]*structpb.Value{"list": structpb.NewListValue(l)}}
+		}(),
+		wantErr: true,
+	}, {
+		desc: "Value aliased in ListValue",
+		input: func() proto.Message {
+			v := structpb.NewStringValue("x")
+			return &structpb.ListValue{Values: []*structpb.Value{v, v}}
+		}(),
+		want: `[
+  "x",
+  "x"
+]`,
 	}, {
 		desc: "UseEnumNumbers in singular field",
 		mo:   protojson.MarshalOptions{UseEnumNumbers: true},
@@ -2527,6 +2742,71 @@ func TestMarshal(t *testing.T) {
 	}
 }
 
+func TestMarshalSortKeys(t *testing.T) {
+	scalars := &pb3.Scalars{SBool: true, SInt32: 2, SBytes: []byte("b"), SString: "s"}
+	maps := &pb3.Maps{Int32ToStr: map[int32]string{9: "nine", 10: "ten"}}
+	anyMsg, err := anypb.New(&pb3.Nested{SString: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		desc  string
+		mo    protojson.MarshalOptions
+		input proto.Message
+		want  string
+	}{{
+		desc:  "declaration order",
+		mo:    protojson.MarshalOptions{SortKeys: protojson.SortByDeclaration},
+		input: scalars,
+		want:  `{"sBool":true,"sInt32":2,"sBytes":"Yg==","sString":"s"}`,
+	}, {
+		desc:  "number order",
+		mo:    protojson.MarshalOptions{SortKeys: protojson.SortByNumber},
+		input: scalars,
+		want:  `{"sBool":true,"sInt32":2,"sString":"s","sBytes":"Yg=="}`,
+	}, {
+		desc:  "name order",
+		mo:    protojson.MarshalOptions{SortKeys: protojson.SortByName},
+		input: scalars,
+		want:  `{"sBool":true,"sBytes":"Yg==","sInt32":2,"sString":"s"}`,
+	}, {
+		desc:  "name order with field numbers",
+		mo:    protojson.MarshalOptions{SortKeys: protojson.SortByName, UseFieldNumbers: true},
+		input: scalars,
+		want:  `{"1":true,"13":"s","14":"Yg==","2":2}`,
+	}, {
+		desc:  "map in number order",
+		mo:    protojson.MarshalOptions{SortKeys: protojson.SortByNumber},
+		input: maps,
+		want:  `{"int32ToStr":{"9":"nine","10":"ten"}}`,
+	}, {
+		desc:  "map in name order",
+		mo:    protojson.MarshalOptions{SortKeys: protojson.SortByName},
+		input: maps,
+		want:  `{"int32ToStr":{"10":"ten","9":"nine"}}`,
+	}, {
+		desc:  "Any in name order",
+		mo:    protojson.MarshalOptions{SortKeys: protojson.SortByName},
+		input: anyMsg,
+		want:  `{"@type":"type.googleapis.com/pb3.Nested","sString":"s"}`,
+	}, {
+		desc:  "tab indent",
+		mo:    protojson.MarshalOptions{SortKeys: protojson.SortByName, Indent: "\t"},
+		input: maps,
+		want:  "{\n\t\"int32ToStr\": {\n\t\t\"10\": \"ten\",\n\t\t\"9\": \"nine\"\n\t}\n}",
+	}} {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.mo.Marshal(tt.input)
+			if err != nil {
+				t.Fatalf("Marshal() error: %v", err)
+			}
+			if got := string(b); got != tt.want {
+				t.Errorf("Marshal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestEncodeAppend(t *testing.T) {
 	want := []byte("prefix")
 	got := append([]byte(nil), want...)
@@ -0,0 +1,221 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protojson
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/encoding/json"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/internal/order"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// This file implements the preservation of unknown JSON object members
+// as configured by the UnknownFieldsStruct and UnknownFieldsNumber options.
+
+// unknownStructField returns the field of md named name if it is a singular
+// google.protobuf.Struct field, and nil otherwise.
+func unknownStructField(md protoreflect.MessageDescriptor, name protoreflect.Name) protoreflect.FieldDescriptor {
+	if name == "" {
+		return nil
+	}
+	fd := md.Fields().ByName(name)
+	if fd == nil || fd.Cardinality() == protoreflect.Repeated || fd.Message() == nil ||
+		fd.Message().FullName() != genid.Struct_message_fullname {
+		return nil
+	}
+	return fd
+}
+
+// unknownFieldsNumber returns the number under which unknown JSON object
+// members are stored in the unknown fields of a message of type md,
+// or zero if they are not.
+func unknownFieldsNumber(md protoreflect.MessageDescriptor, num protowire.Number) protowire.Number {
+	if num <= 0 || md.Fields().ByNumber(num) != nil {
+		return 0
+	}
+	return num
+}
+
+// isKnownName reports whether name refers to a field of md.
+func isKnownName(md protoreflect.MessageDescriptor, name string) bool {
+	return md.Fields().ByJSONName(name) != nil || md.Fields().ByTextName(name) != nil
+}
+
+// stashUnknown reads the value of the unknown object member name and stores
+// it in m as configured by the options. It reports false if the options
+// do not specify a place to store the member in m.
+func (d decoder) stashUnknown(m protoreflect.Message, name string) (bool, error) {
+	md := m.Descriptor()
+	if fd := unknownStructField(md, d.opts.UnknownFieldsStruct); fd != nil {
+		st := m.Mutable(fd).Message()
+		fields := st.Mutable(st.Descriptor().Fields().ByNumber(genid.Struct_Fields_field_number)).Map()
+		val := fields.NewValue()
+		if err := d.unmarshalMessage(val.Message(), false); err != nil {
+			return false, err
+		}
+		fields.Set(protoreflect.ValueOfString(name).MapKey(), val)
+		return true, nil
+	}
+	if num := unknownFieldsNumber(md, d.opts.UnknownFieldsNumber); num != 0 {
+		e, _ := json.NewEncoder(nil, "")
+		e.StartObject()
+		if err := e.WriteName(name); err != nil {
+			return false, err
+		}
+		if err := d.copyJSONValue(e); err != nil {
+			return false, err
+		}
+		e.EndObject()
+		b := protowire.AppendTag(m.GetUnknown(), num, protowire.BytesType)
+		m.SetUnknown(protowire.AppendBytes(b, e.Bytes()))
+		return true, nil
+	}
+	return false, nil
+}
+
+// copyJSONValue reads a single JSON value and writes it to e.
+func (d decoder) copyJSONValue(e *json.Encoder) error {
+	var open int
+	for {
+		tok, err := d.Read()
+		if err != nil {
+			return err
+		}
+		switch tok.Kind() {
+		case json.Null:
+			e.WriteNull()
+		case json.Bool:
+			e.WriteBool(tok.Bool())
+		case json.Number:
+			// Copy the number literally so that no precision is lost.
+			e.WriteNumber(tok.RawString())
+		case json.String:
+			if err := e.WriteString(tok.ParsedString()); err != nil {
+				return err
+			}
+		case json.Name:
+			if err := e.WriteName(tok.Name()); err != nil {
+				return err
+			}
+		case json.ObjectOpen:
+			e.StartObject()
+			open++
+		case json.ObjectClose:
+			e.EndObject()
+			open--
+		case json.ArrayOpen:
+			e.StartArray()
+			open++
+		case json.ArrayClose:
+			e.EndArray()
+			open--
+		case json.EOF:
+			// This can only happen if there's a bug in Decoder.Read.
+			// Avoid an infinite loop if this does happen.
+			return errors.New("unexpected EOF")
+		}
+		if open > d.opts.RecursionLimit {
+			return errors.New("exceeded max recursion depth")
+		}
+		if open == 0 && tok.Kind() != json.Name {
+			return nil
+		}
+	}
+}
+
+// marshalUnknownStruct writes the members of the google.protobuf.Struct
+// value st as members of the current object, skipping those that
+// conflict with fields of md.
+func (e encoder) marshalUnknownStruct(md protoreflect.MessageDescriptor, st protoreflect.Message) error {
+	fields := st.Get(st.Descriptor().Fields().ByNumber(genid.Struct_Fields_field_number)).Map()
+	var err error
+	order.RangeEntries(fields, order.GenericKeyOrder, func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		name := k.String()
+		if isKnownName(md, name) {
+			return true
+		}
+		if err = e.WriteName(name); err != nil {
+			return false
+		}
+		if err = e.marshalMessage(v.Message(), ""); err != nil {
+			return false
+		}
+		return true
+	})
+	return err
+}
+
+// marshalUnknownFields writes the object members stored in the unknown
+// fields of m with the given number as members of the current object,
+// skipping those that conflict with fields of m.
+func (e encoder) marshalUnknownFields(m protoreflect.Message, num protowire.Number) error {
+	md := m.Descriptor()
+	b := m.GetUnknown()
+	for len(b) > 0 {
+		n, typ, tagLen := protowire.ConsumeTag(b)
+		if tagLen < 0 {
+			return protowire.ParseError(tagLen)
+		}
+		b = b[tagLen:]
+		if n != num || typ != protowire.BytesType {
+			valLen := protowire.ConsumeFieldValue(n, typ, b)
+			if valLen < 0 {
+				return protowire.ParseError(valLen)
+			}
+			b = b[valLen:]
+			continue
+		}
+		v, valLen := protowire.ConsumeBytes(b)
+		if valLen < 0 {
+			return protowire.ParseError(valLen)
+		}
+		b = b[valLen:]
+
+		d := decoder{Decoder: json.NewDecoder(v), opts: UnmarshalOptions{RecursionLimit: protowire.DefaultRecursionLimit}}
+		if err := e.copyUnknownMembers(d, md); err != nil {
+			return errors.New("invalid unknown JSON field %d in %v: %v", num, md.FullName(), err)
+		}
+	}
+	return nil
+}
+
+// copyUnknownMembers copies the members of the JSON object read by d
+// to the current object, skipping those that conflict with fields of md.
+func (e encoder) copyUnknownMembers(d decoder, md protoreflect.MessageDescriptor) error {
+	tok, err := d.Read()
+	if err != nil {
+		return err
+	}
+	if tok.Kind() != json.ObjectOpen {
+		return d.unexpectedTokenError(tok)
+	}
+	for {
+		tok, err := d.Read()
+		if err != nil {
+			return err
+		}
+		switch tok.Kind() {
+		case json.ObjectClose:
+			return nil
+		case json.Name:
+		default:
+			return d.unexpectedTokenError(tok)
+		}
+		if isKnownName(md, tok.Name()) {
+			if err := d.skipJSONValue(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := e.WriteName(tok.Name()); err != nil {
+			return err
+		}
+		if err := d.copyJSONValue(e.Encoder); err != nil {
+			return err
+		}
+	}
+}
@@ -6,25 +6,28 @@ package protojson
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"math"
 	"strconv"
 	"strings"
 	"time"
 
+	"google.golang.org/protobuf/encoding/protoknown"
 	"google.golang.org/protobuf/internal/encoding/json"
 	"google.golang.org/protobuf/internal/errors"
 	"google.golang.org/protobuf/internal/genid"
 	"google.golang.org/protobuf/internal/strs"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
 type marshalFunc func(encoder, protoreflect.Message) error
 
 // wellKnownTypeMarshaler returns a marshal function if the message type
 // has specialized serialization behavior. It returns nil otherwise.
-func wellKnownTypeMarshaler(name protoreflect.FullName) marshalFunc {
+func (e encoder) wellKnownTypeMarshaler(name protoreflect.FullName) marshalFunc {
 	if name.Parent() == genid.GoogleProtobuf_package {
 		switch name.Name() {
 		case genid.Any_message_name:
@@ -55,6 +58,11 @@ func wellKnownTypeMarshaler(name protoreflect.FullName) marshalFunc {
 			return encoder.marshalEmpty
 		}
 	}
+	if b, ok := e.opts.Behaviors.Lookup(name); ok {
+		return func(e encoder, m protoreflect.Message) error {
+			return e.marshalCustom(b, m)
+		}
+	}
 	return nil
 }
 
@@ -62,7 +70,7 @@ type unmarshalFunc func(decoder, protoreflect.Message) error
 
 // wellKnownTypeUnmarshaler returns a unmarshal function if the message type
 // has specialized serialization behavior. It returns nil otherwise.
-func wellKnownTypeUnmarshaler(name protoreflect.FullName) unmarshalFunc {
+func (d decoder) wellKnownTypeUnmarshaler(name protoreflect.FullName) unmarshalFunc {
 	if name.Parent() == genid.GoogleProtobuf_package {
 		switch name.Name() {
 		case genid.Any_message_name:
@@ -93,6 +101,11 @@ func wellKnownTypeUnmarshaler(name protoreflect.FullName) unmarshalFunc {
 			return decoder.unmarshalEmpty
 		}
 	}
+	if b, ok := d.opts.Behaviors.Lookup(name); ok {
+		return func(d decoder, m protoreflect.Message) error {
+			return d.unmarshalCustom(b, m)
+		}
+	}
 	return nil
 }
 
@@ -125,6 +138,19 @@ func (e encoder) marshalAny(m protoreflect.Message) error {
 	// Resolve the type in order to unmarshal value field.
 	typeURL := typeVal.String()
 	emt, err := e.opts.Resolver.FindMessageByURL(typeURL)
+	if err == protoregistry.NotFound && e.opts.EmitUnresolvableAny {
+		e.StartObject()
+		defer e.EndObject()
+
+		e.WriteName("@type")
+		if err := e.WriteString(typeURL); err != nil {
+			return err
+		}
+
+		e.WriteName("value")
+		e.WriteString(base64.StdEncoding.EncodeToString(valueVal.Bytes()))
+		return nil
+	}
 	if err != nil {
 		return errors.New("%s: unable to resolve %q: %v", genid.Any_message_fullname, typeURL, err)
 	}
@@ -141,7 +167,7 @@ func (e encoder) marshalAny(m protoreflect.Message) error {
 	// If type of value has custom JSON encoding, marshal out a field "value"
 	// with corresponding custom JSON encoding of the embedded message as a
 	// field.
-	if marshal := wellKnownTypeMarshaler(emt.Descriptor().FullName()); marshal != nil {
+	if marshal := e.wellKnownTypeMarshaler(emt.Descriptor().FullName()); marshal != nil {
 		e.StartObject()
 		defer e.EndObject()
 
@@ -176,7 +202,7 @@ func (d decoder) unmarshalAny(m protoreflect.Message) error {
 	// Use another decoder to parse the unread bytes for @type field. This
 	// avoids advancing a read from current decoder because the current JSON
 	// object may contain the fields of the embedded type.
-	dec := decoder{d.Clone(), UnmarshalOptions{RecursionLimit: d.opts.RecursionLimit}}
+	dec := decoder{Decoder: d.Clone(), opts: UnmarshalOptions{RecursionLimit: d.opts.RecursionLimit}}
 	tok, err := findTypeURL(dec)
 	switch err {
 	case errEmptyObject:
@@ -207,7 +233,7 @@ func (d decoder) unmarshalAny(m protoreflect.Message) error {
 
 	// Create new message for the embedded message type and unmarshal into it.
 	em := emt.New()
-	if unmarshal := wellKnownTypeUnmarshaler(emt.Descriptor().FullName()); unmarshal != nil {
+	if unmarshal := d.wellKnownTypeUnmarshaler(emt.Descriptor().FullName()); unmarshal != nil {
 		// If embedded message is a custom type,
 		// unmarshal the JSON "value" field into it.
 		if err := d.unmarshalAnyValue(unmarshal, em); err != nil {
@@ -308,12 +334,18 @@ Loop:
 // array) in order to advance the read to the next JSON value. It relies on
 // the decoder returning an error if the types are not in valid sequence.
 func (d decoder) skipJSONValue() error {
+	tok, err := d.Read()
+	if err != nil {
+		return err
+	}
+	return d.skipRest(tok)
+}
+
+// skipRest advances the read to the end of the JSON value starting with the
+// given token, which was just read.
+func (d decoder) skipRest(tok json.Token) error {
 	var open int
 	for {
-		tok, err := d.Read()
-		if err != nil {
-			return err
-		}
 		switch tok.Kind() {
 		case json.ObjectClose, json.ArrayClose:
 			open--
@@ -330,6 +362,10 @@ func (d decoder) skipJSONValue() error {
 		if open == 0 {
 			return nil
 		}
+		var err error
+		if tok, err = d.Read(); err != nil {
+			return err
+		}
 	}
 }
 
@@ -399,7 +435,9 @@ func (d decoder) unmarshalWrapperType(m protoreflect.Message) error {
 	if err != nil {
 		return err
 	}
-	m.Set(fd, val)
+	if val.IsValid() {
+		m.Set(fd, val)
+	}
 	return nil
 }
 
@@ -448,10 +486,29 @@ func (d decoder) unmarshalEmpty(protoreflect.Message) error {
 // Struct.fields map and follows the serialization rules for a map.
 
 func (e encoder) marshalStruct(m protoreflect.Message) error {
+	if err := e.enterValue(m); err != nil {
+		return err
+	}
+	defer delete(e.active, m)
 	fd := m.Descriptor().Fields().ByNumber(genid.Struct_Fields_field_number)
 	return e.marshalMap(m.Get(fd).Map(), fd)
 }
 
+// enterValue records that the google.protobuf.Struct, ListValue, or Value
+// message m is being marshaled. Since these messages are often constructed
+// programmatically, they may accidentally contain themselves, in which case
+// an error is returned instead of recursing until the depth limit.
+func (e *encoder) enterValue(m protoreflect.Message) error {
+	if e.active[m] {
+		return errors.New("%v: cycle detected: message contains itself", m.Descriptor().FullName())
+	}
+	if e.active == nil {
+		e.active = make(map[protoreflect.Message]bool)
+	}
+	e.active[m] = true
+	return nil
+}
+
 func (d decoder) unmarshalStruct(m protoreflect.Message) error {
 	fd := m.Descriptor().Fields().ByNumber(genid.Struct_Fields_field_number)
 	return d.unmarshalMap(m.Mutable(fd).Map(), fd)
@@ -462,6 +519,10 @@ func (d decoder) unmarshalStruct(m protoreflect.Message) error {
 // repeated field.
 
 func (e encoder) marshalListValue(m protoreflect.Message) error {
+	if err := e.enterValue(m); err != nil {
+		return err
+	}
+	defer delete(e.active, m)
 	fd := m.Descriptor().Fields().ByNumber(genid.ListValue_Values_field_number)
 	return e.marshalList(m.Get(fd).List(), fd)
 }
@@ -476,6 +537,10 @@ func (d decoder) unmarshalListValue(m protoreflect.Message) error {
 // Value message needs to be a oneof field set, else it is an error.
 
 func (e encoder) marshalKnownValue(m protoreflect.Message) error {
+	if err := e.enterValue(m); err != nil {
+		return err
+	}
+	defer delete(e.active, m)
 	od := m.Descriptor().Oneofs().ByName(genid.Value_Kind_oneof_name)
 	fd := m.WhichOneof(od)
 	if fd == nil {
@@ -518,7 +583,7 @@ func (d decoder) unmarshalKnownValue(m protoreflect.Message) error {
 		}
 		fd = m.Descriptor().Fields().ByNumber(genid.Value_NumberValue_field_number)
 		var ok bool
-		val, ok = unmarshalFloat(tok, 64)
+		val, ok = d.unmarshalFloat(tok, 64)
 		if !ok {
 			return d.newError(tok.Pos(), "invalid %v: %v", genid.Value_message_fullname, tok.RawString())
 		}
@@ -619,12 +684,12 @@ func (d decoder) unmarshalDuration(m protoreflect.Message) error {
 
 	secs, nanos, ok := parseDuration(tok.ParsedString())
 	if !ok {
-		return d.newError(tok.Pos(), "invalid %v value %v", genid.Duration_message_fullname, tok.RawString())
+		return d.fieldError(tok.Pos(), "invalid %v value %v", genid.Duration_message_fullname, tok.RawString())
 	}
 	// Validate seconds. No need to validate nanos because parseDuration would
 	// have covered that already.
 	if secs < -maxSecondsInDuration || secs > maxSecondsInDuration {
-		return d.newError(tok.Pos(), "%v value out of range: %v", genid.Duration_message_fullname, tok.RawString())
+		return d.fieldError(tok.Pos(), "%v value out of range: %v", genid.Duration_message_fullname, tok.RawString())
 	}
 
 	fds := m.Descriptor().Fields()
@@ -802,18 +867,18 @@ func (d decoder) unmarshalTimestamp(m protoreflect.Message) error {
 	s := tok.ParsedString()
 	t, err := time.Parse(time.RFC3339Nano, s)
 	if err != nil {
-		return d.newError(tok.Pos(), "invalid %v value %v", genid.Timestamp_message_fullname, tok.RawString())
+		return d.fieldError(tok.Pos(), "invalid %v value %v", genid.Timestamp_message_fullname, tok.RawString())
 	}
 	// Validate seconds.
 	secs := t.Unix()
 	if secs < minTimestampSeconds || secs > maxTimestampSeconds {
-		return d.newError(tok.Pos(), "%v value out of range: %v", genid.Timestamp_message_fullname, tok.RawString())
+		return d.fieldError(tok.Pos(), "%v value out of range: %v", genid.Timestamp_message_fullname, tok.RawString())
 	}
 	// Validate subseconds.
 	i := strings.LastIndexByte(s, '.')  // start of subsecond field
 	j := strings.LastIndexAny(s, "Z-+") // start of timezone field
 	if i >= 0 && j >= i && j-i > len(".999999999") {
-		return d.newError(tok.Pos(), "invalid %v value %v", genid.Timestamp_message_fullname, tok.RawString())
+		return d.fieldError(tok.Pos(), "invalid %v value %v", genid.Timestamp_message_fullname, tok.RawString())
 	}
 
 	fds := m.Descriptor().Fields()
@@ -872,9 +937,35 @@ func (d decoder) unmarshalFieldMask(m protoreflect.Message) error {
 	for _, s0 := range paths {
 		s := strs.JSONSnakeCase(s0)
 		if strings.Contains(s0, "_") || !protoreflect.FullName(s).IsValid() {
-			return d.newError(tok.Pos(), "%v contains invalid path: %q", genid.FieldMask_Paths_field_fullname, s0)
+			return d.fieldError(tok.Pos(), "%v contains invalid path: %q", genid.FieldMask_Paths_field_fullname, s0)
 		}
 		list.Append(protoreflect.ValueOfString(s))
 	}
 	return nil
 }
+
+// The JSON representation of a message type with a custom behavior in
+// the Behaviors option is a JSON string, which is produced and parsed by
+// the behavior.
+
+func (e encoder) marshalCustom(b protoknown.Behavior, m protoreflect.Message) error {
+	s, err := b.Marshal(m)
+	if err != nil {
+		return errors.New("%v: %v", m.Descriptor().FullName(), err)
+	}
+	return e.WriteString(s)
+}
+
+func (d decoder) unmarshalCustom(b protoknown.Behavior, m protoreflect.Message) error {
+	tok, err := d.Read()
+	if err != nil {
+		return err
+	}
+	if tok.Kind() != json.String {
+		return d.unexpectedTokenError(tok)
+	}
+	if err := b.Unmarshal(tok.ParsedString(), m); err != nil {
+		return d.fieldError(tok.Pos(), "invalid %v value %v: %v", m.Descriptor().FullName(), tok.RawString(), err)
+	}
+	return nil
+}
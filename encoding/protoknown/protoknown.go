@@ -0,0 +1,116 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoknown defines custom behaviors for message types,
+// which the protojson and prototext packages apply to them similarly to
+// the special handling of the well-known types.
+//
+// For example, a Decimal message type with a string field can be given
+// a behavior to be represented as a JSON string such as "12.50" rather than
+// a JSON object, and to be validated whenever it is marshaled or unmarshaled:
+//
+//	var behaviors protoknown.Behaviors
+//	behaviors.Register("example.money.Decimal", protoknown.Behavior{
+//		Format: func(m protoreflect.Message) (string, error) {
+//			return m.Get(valueField).String(), nil
+//		},
+//		Parse: func(s string, m protoreflect.Message) error {
+//			m.Set(valueField, protoreflect.ValueOfString(s))
+//			return nil
+//		},
+//		Validate: validateDecimal,
+//	})
+//	b, err := protojson.MarshalOptions{Behaviors: &behaviors}.Marshal(m)
+//
+// Behaviors are identified by the full name of the message type, so they apply
+// to generated and dynamic messages alike. They only apply when a set of
+// behaviors is passed in the options of an encoding.
+package protoknown
+
+import (
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Behavior is the custom behavior of a message type.
+type Behavior struct {
+	// Format returns the string that represents m. The protojson package
+	// marshals it as a JSON string, and the prototext package marshals it
+	// as a string in place of the message. Format must not be nil.
+	Format func(m protoreflect.Message) (string, error)
+
+	// Parse parses the string produced by Format into m, which is empty.
+	// Parse must not be nil.
+	Parse func(s string, m protoreflect.Message) error
+
+	// Validate reports an error if m is invalid. If non-nil, it is called
+	// before m is marshaled and after m is unmarshaled.
+	Validate func(m protoreflect.Message) error
+}
+
+// Behaviors is a set of custom behaviors of message types,
+// which is used by the Behaviors option of the protojson and
+// prototext packages. The zero value is an empty set ready for use,
+// and a nil *Behaviors is an empty set.
+//
+// Behaviors may be looked up concurrently, but must not be registered
+// concurrently with other use of the set.
+type Behaviors struct {
+	m map[protoreflect.FullName]Behavior
+}
+
+// Register registers the custom behavior of the named message type.
+// It reports an error if the message type already has a behavior,
+// if it is a well-known type, or if Format or Parse is nil.
+func (r *Behaviors) Register(name protoreflect.FullName, b Behavior) error {
+	switch {
+	case !name.IsValid():
+		return errors.New("invalid message name %q", name)
+	case name.Parent() == genid.GoogleProtobuf_package:
+		return errors.New("cannot register a behavior for well-known type %v", name)
+	case b.Format == nil || b.Parse == nil:
+		return errors.New("behavior for %v must have Format and Parse functions", name)
+	}
+	if _, ok := r.m[name]; ok {
+		return errors.New("behavior for %v is already registered", name)
+	}
+	if r.m == nil {
+		r.m = make(map[protoreflect.FullName]Behavior)
+	}
+	r.m[name] = b
+	return nil
+}
+
+// Lookup returns the behavior registered for the named message type.
+func (r *Behaviors) Lookup(name protoreflect.FullName) (Behavior, bool) {
+	if r == nil {
+		return Behavior{}, false
+	}
+	b, ok := r.m[name]
+	return b, ok
+}
+
+// Marshal returns the string that represents m according to b,
+// after validating m if b has a Validate function.
+func (b Behavior) Marshal(m protoreflect.Message) (string, error) {
+	if b.Validate != nil {
+		if err := b.Validate(m); err != nil {
+			return "", err
+		}
+	}
+	return b.Format(m)
+}
+
+// Unmarshal parses s into m according to b,
+// and then validates m if b has a Validate function.
+func (b Behavior) Unmarshal(s string, m protoreflect.Message) error {
+	if err := b.Parse(s, m); err != nil {
+		return err
+	}
+	if b.Validate != nil {
+		return b.Validate(m)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoknown_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protoknown"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func mustParseFile(t *testing.T, s string) protoreflect.FileDescriptor {
+	t.Helper()
+	fdp := new(descriptorpb.FileDescriptorProto)
+	if err := prototext.Unmarshal([]byte(s), fdp); err != nil {
+		t.Fatal(err)
+	}
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd
+}
+
+func TestBehavior(t *testing.T) {
+	fd := mustParseFile(t, `
+		name: "protoknown_test.proto"
+		package: "protoknown.test"
+		message_type: [{
+			name: "Decimal"
+			field: [{name: "value" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING}]
+		}, {
+			name: "Holder"
+			field: [
+				{name: "single" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".protoknown.test.Decimal" json_name: "single"},
+				{name: "list" number: 2 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".protoknown.test.Decimal" json_name: "list"}
+			]
+		}]
+	`)
+	decimal := fd.Messages().ByName("Decimal")
+	holder := fd.Messages().ByName("Holder")
+	valueField := decimal.Fields().ByName("value")
+	behaviors := new(protoknown.Behaviors)
+	err := behaviors.Register(decimal.FullName(), protoknown.Behavior{
+		Format: func(m protoreflect.Message) (string, error) {
+			return m.Get(valueField).String(), nil
+		},
+		Parse: func(s string, m protoreflect.Message) error {
+			m.Set(valueField, protoreflect.ValueOfString(s))
+			return nil
+		},
+		Validate: func(m protoreflect.Message) error {
+			if _, err := strconv.ParseFloat(m.Get(valueField).String(), 64); err != nil {
+				return errors.New("not a decimal number")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newDecimal := func(s string) protoreflect.Value {
+		m := dynamicpb.NewMessage(decimal)
+		m.Set(valueField, protoreflect.ValueOfString(s))
+		return protoreflect.ValueOfMessage(m)
+	}
+	m := dynamicpb.NewMessage(holder)
+	m.Set(holder.Fields().ByName("single"), newDecimal("12.50"))
+	list := m.Mutable(holder.Fields().ByName("list")).List()
+	list.Append(newDecimal("1"))
+	list.Append(newDecimal("2.5"))
+
+	jsonMarshal := protojson.MarshalOptions{Behaviors: behaviors}
+	jsonUnmarshal := protojson.UnmarshalOptions{Behaviors: behaviors}
+	textMarshal := prototext.MarshalOptions{Behaviors: behaviors}
+	textUnmarshal := prototext.UnmarshalOptions{Behaviors: behaviors}
+
+	t.Run("protojson", func(t *testing.T) {
+		b, err := jsonMarshal.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"single":"12.50","list":["1","2.5"]}`
+		if got := strings.ReplaceAll(string(b), " ", ""); got != want {
+			t.Errorf("Marshal() = %s, want %s", got, want)
+		}
+		got := dynamicpb.NewMessage(holder)
+		if err := jsonUnmarshal.Unmarshal(b, got); err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(got, m) {
+			t.Errorf("Unmarshal() = %v, want %v", got, m)
+		}
+		if err := jsonUnmarshal.Unmarshal([]byte(`{"single":"x"}`), dynamicpb.NewMessage(holder)); err == nil {
+			t.Errorf("Unmarshal() of invalid value succeeded, want error")
+		}
+		if err := jsonUnmarshal.Unmarshal([]byte(`{"single":{"value":"1"}}`), dynamicpb.NewMessage(holder)); err == nil {
+			t.Errorf("Unmarshal() of object succeeded, want error")
+		}
+	})
+
+	t.Run("prototext", func(t *testing.T) {
+		b, err := textMarshal.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `single:"12.50" list:"1" list:"2.5"`
+		if got := strings.Join(strings.Fields(string(b)), " "); strings.ReplaceAll(got, ": ", ":") != want {
+			t.Errorf("Marshal() = %s, want %s", got, want)
+		}
+		got := dynamicpb.NewMessage(holder)
+		if err := textUnmarshal.Unmarshal(b, got); err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(got, m) {
+			t.Errorf("Unmarshal() = %v, want %v", got, m)
+		}
+
+		// The regular message form is accepted and validated.
+		got = dynamicpb.NewMessage(holder)
+		if err := textUnmarshal.Unmarshal([]byte(`single {value: "12.50"} list: ["1", {value: "2.5"}]`), got); err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(got, m) {
+			t.Errorf("Unmarshal() = %v, want %v", got, m)
+		}
+		if err := textUnmarshal.Unmarshal([]byte(`single {value: "x"}`), dynamicpb.NewMessage(holder)); err == nil {
+			t.Errorf("Unmarshal() of invalid message succeeded, want error")
+		}
+		if err := textUnmarshal.Unmarshal([]byte(`single: "x"`), dynamicpb.NewMessage(holder)); err == nil {
+			t.Errorf("Unmarshal() of invalid value succeeded, want error")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		// Without the option, the message is encoded as usual.
+		b, err := protojson.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), `"value"`) {
+			t.Errorf("protojson.Marshal() = %s, want the fields of the message", b)
+		}
+		if err := protojson.Unmarshal([]byte(`{"single":"12.50"}`), dynamicpb.NewMessage(holder)); err == nil {
+			t.Errorf("protojson.Unmarshal() of string succeeded, want error")
+		}
+		if err := prototext.Unmarshal([]byte(`single {value: "x"}`), dynamicpb.NewMessage(holder)); err != nil {
+			t.Errorf("prototext.Unmarshal() error: %v", err)
+		}
+	})
+
+	// Invalid messages are not marshaled.
+	invalid := dynamicpb.NewMessage(holder)
+	invalid.Set(holder.Fields().ByName("single"), newDecimal("x"))
+	if _, err := jsonMarshal.Marshal(invalid); err == nil {
+		t.Errorf("protojson.Marshal() of invalid value succeeded, want error")
+	}
+	if _, err := textMarshal.Marshal(invalid); err == nil {
+		t.Errorf("prototext.Marshal() of invalid value succeeded, want error")
+	}
+
+	// Nor are invalid messages nested in an Any, which prototext
+	// would otherwise marshal in the regular form of an Any.
+	var files protoregistry.Files
+	var types protoregistry.Types
+	if err := files.RegisterFile(fd); err != nil {
+		t.Fatal(err)
+	}
+	if err := types.RegisterMessage(dynamicpb.NewMessageType(holder)); err != nil {
+		t.Fatal(err)
+	}
+	a, err := anypb.New(invalid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (protojson.MarshalOptions{Behaviors: behaviors, Resolver: &types}).Marshal(a); err == nil {
+		t.Errorf("protojson.Marshal() of Any with invalid value succeeded, want error")
+	}
+	if _, err := (prototext.MarshalOptions{Behaviors: behaviors, Resolver: &types}).Marshal(a); err == nil {
+		t.Errorf("prototext.Marshal() of Any with invalid value succeeded, want error")
+	}
+}
+
+func TestRegisterErrors(t *testing.T) {
+	valid := protoknown.Behavior{
+		Format: func(protoreflect.Message) (string, error) { return "", nil },
+		Parse:  func(string, protoreflect.Message) error { return nil },
+	}
+	var behaviors protoknown.Behaviors
+	if err := behaviors.Register("protoknown.test.Twice", valid); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name protoreflect.FullName
+		b    protoknown.Behavior
+	}{
+		{"protoknown.test.Twice", valid},
+		{"google.protobuf.Duration", valid},
+		{"invalid name", valid},
+		{"protoknown.test.NoParse", protoknown.Behavior{Format: valid.Format}},
+	} {
+		if err := behaviors.Register(tt.name, tt.b); err == nil {
+			t.Errorf("Register(%v) succeeded, want error", tt.name)
+		}
+	}
+	if _, ok := behaviors.Lookup("protoknown.test.NoParse"); ok {
+		t.Errorf("Lookup() found a behavior whose registration failed")
+	}
+	if _, ok := (*protoknown.Behaviors)(nil).Lookup("protoknown.test.Twice"); ok {
+		t.Errorf("Lookup() of nil set found a behavior")
+	}
+}
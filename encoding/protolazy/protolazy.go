@@ -0,0 +1,467 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protolazy marshals and unmarshals wire-format messages whose large
+// bytes fields are kept in external storage, such as a file, and only loaded
+// into memory when accessed.
+//
+// A lazily loaded field is represented by a [Bytes] handle in the [Fields]
+// that accompany a message, while the field itself is unpopulated in the
+// message. Only singular, non-required bytes fields that are not members of
+// a oneof can be loaded lazily.
+package protolazy
+
+import (
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Bytes is a handle to a bytes value stored in an [io.ReaderAt],
+// which is only read when the value is loaded or written.
+// It is safe for concurrent use.
+type Bytes struct {
+	r   io.ReaderAt
+	off int64
+	n   int64
+
+	mu     sync.Mutex
+	loaded bool
+	b      []byte
+}
+
+// NewBytes returns a handle to the n bytes of r at offset off.
+func NewBytes(r io.ReaderAt, off, n int64) *Bytes {
+	return &Bytes{r: r, off: off, n: n}
+}
+
+// FileBytes returns a handle to the current contents of f.
+// The file must not be closed or modified while the handle is in use.
+func FileBytes(f *os.File) (*Bytes, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return NewBytes(f, 0, fi.Size()), nil
+}
+
+// Len returns the length of the value.
+func (b *Bytes) Len() int64 {
+	return b.n
+}
+
+// Load reads the value into memory, if it has not been loaded yet,
+// and returns it. The returned slice must not be modified.
+func (b *Bytes) Load() ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.loaded {
+		return b.b, nil
+	}
+	v := make([]byte, b.n)
+	if err := readAt(b.r, v, b.off); err != nil {
+		return nil, err
+	}
+	b.b, b.loaded = v, true
+	return v, nil
+}
+
+// Loaded reports whether the value has been loaded into memory.
+func (b *Bytes) Loaded() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.loaded
+}
+
+// WriteTo writes the value to w. Unless it has already been loaded,
+// the value is copied from its storage without being loaded into memory.
+func (b *Bytes) WriteTo(w io.Writer) (int64, error) {
+	b.mu.Lock()
+	loaded, v := b.loaded, b.b
+	b.mu.Unlock()
+	if loaded {
+		n, err := w.Write(v)
+		return int64(n), err
+	}
+	n, err := io.Copy(w, io.NewSectionReader(b.r, b.off, b.n))
+	if err == nil && n < b.n {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// Fields are the lazily loaded fields of a message by field number.
+type Fields map[protoreflect.FieldNumber]*Bytes
+
+// Load populates the fields of m with the values of fs, loading them into
+// memory as needed. The fields share the loaded values with the handles,
+// so they must not be modified.
+func (fs Fields) Load(m proto.Message) error {
+	mr := m.ProtoReflect()
+	for _, num := range fs.numbers() {
+		fd, err := lazyField(mr.Descriptor(), num)
+		if err != nil {
+			return err
+		}
+		v, err := fs[num].Load()
+		if err != nil {
+			return err
+		}
+		mr.Set(fd, protoreflect.ValueOfBytes(v))
+	}
+	return nil
+}
+
+// numbers returns the field numbers of fs in increasing order.
+func (fs Fields) numbers() []protoreflect.FieldNumber {
+	nums := make([]protoreflect.FieldNumber, 0, len(fs))
+	for num := range fs {
+		nums = append(nums, num)
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	return nums
+}
+
+// lazyField returns the field of md with the given number,
+// or an error if the field cannot be loaded lazily.
+func lazyField(md protoreflect.MessageDescriptor, num protoreflect.FieldNumber) (protoreflect.FieldDescriptor, error) {
+	fd := md.Fields().ByNumber(num)
+	if fd == nil {
+		return nil, errors.New("%v has no field with number %d", md.FullName(), num)
+	}
+	if !isLazyField(fd) {
+		return nil, errors.New("field %v cannot be loaded lazily", fd.FullName())
+	}
+	return fd, nil
+}
+
+func isLazyField(fd protoreflect.FieldDescriptor) bool {
+	od := fd.ContainingOneof()
+	return fd.Kind() == protoreflect.BytesKind &&
+		fd.Cardinality() == protoreflect.Optional &&
+		(od == nil || od.IsSynthetic())
+}
+
+// MarshalOptions is a configurable marshaler of messages with lazily
+// loaded fields.
+type MarshalOptions struct{ proto.MarshalOptions }
+
+// MarshalTo writes the wire-format encoding of m to w, followed by
+// the fields in fs, which must be unpopulated in m.
+// The values of the fields are written as by [Bytes.WriteTo].
+// If w returns an error, MarshalTo returns it unchanged.
+func (o MarshalOptions) MarshalTo(w io.Writer, m proto.Message, fs Fields) (int64, error) {
+	nums, err := checkFields(m, fs)
+	if err != nil {
+		return 0, err
+	}
+	b, err := o.MarshalOptions.Marshal(m)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(b)
+	written := int64(n)
+	if err != nil {
+		return written, err
+	}
+	for _, num := range nums {
+		v := fs[num]
+		b = protowire.AppendTag(b[:0], num, protowire.BytesType)
+		b = protowire.AppendVarint(b, uint64(v.Len()))
+		n, err := w.Write(b)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+		n64, err := v.WriteTo(w)
+		written += n64
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Size returns the size in bytes of the output of [MarshalOptions.MarshalTo].
+// It returns 0 if the fields are invalid for m.
+func (o MarshalOptions) Size(m proto.Message, fs Fields) int64 {
+	nums, err := checkFields(m, fs)
+	if err != nil {
+		return 0
+	}
+	size := int64(o.MarshalOptions.Size(m))
+	for _, num := range nums {
+		n := fs[num].Len()
+		size += int64(protowire.SizeTag(num)+protowire.SizeVarint(uint64(n))) + n
+	}
+	return size
+}
+
+// MarshalTo writes the wire-format encoding of m and fs to w
+// with the default options.
+//
+// See the documentation for [MarshalOptions.MarshalTo].
+func MarshalTo(w io.Writer, m proto.Message, fs Fields) (int64, error) {
+	return MarshalOptions{}.MarshalTo(w, m, fs)
+}
+
+// checkFields validates fs for m and returns its field numbers
+// in increasing order.
+func checkFields(m proto.Message, fs Fields) ([]protoreflect.FieldNumber, error) {
+	mr := m.ProtoReflect()
+	nums := fs.numbers()
+	for _, num := range nums {
+		fd, err := lazyField(mr.Descriptor(), num)
+		if err != nil {
+			return nil, err
+		}
+		if mr.Has(fd) {
+			return nil, errors.New("field %v is populated in the message", fd.FullName())
+		}
+	}
+	return nums, nil
+}
+
+// defaultMinSize is the default for UnmarshalOptions.MinSize.
+const defaultMinSize = 64 << 10
+
+// UnmarshalOptions is a configurable unmarshaler of messages with lazily
+// loaded fields.
+type UnmarshalOptions struct {
+	proto.UnmarshalOptions
+
+	// MinSize is the minimum length of the value of a field for it to be
+	// loaded lazily. Shorter values are unmarshaled into the message.
+	// A zero MinSize will default to 64 KiB.
+	MinSize int64
+}
+
+// UnmarshalFrom unmarshals the wire-format message of the given size at
+// the start of r into m, except for the values of fields that are loaded
+// lazily, which are returned as handles that refer to r.
+// Therefore, r must remain readable and unmodified while they are in use.
+//
+// As when unmarshaling, the last occurrence of a field takes precedence.
+func (o UnmarshalOptions) UnmarshalFrom(r io.ReaderAt, size int64, m proto.Message) (Fields, error) {
+	minSize := o.MinSize
+	if minSize == 0 {
+		minSize = defaultMinSize
+	}
+	md := m.ProtoReflect().Descriptor()
+	s := &scanner{r: r, size: size}
+	var buf []byte // the fields that are not loaded lazily
+	var fs Fields
+	var start int64 // start of the fields to append to buf
+	for pos := int64(0); pos < size; {
+		num, typ, n, err := s.tag(pos)
+		if err != nil {
+			return nil, err
+		}
+		fd := md.Fields().ByNumber(num)
+		if fd != nil && typ == protowire.BytesType && isLazyField(fd) {
+			l, ln, err := s.varint(pos + n)
+			if err != nil {
+				return nil, err
+			}
+			off := pos + n + ln
+			if l > uint64(size-off) {
+				return nil, errors.New("field %v at offset %d extends past the end of the message", fd.FullName(), pos)
+			}
+			if int64(l) >= minSize {
+				if buf, err = s.appendRange(buf, start, pos); err != nil {
+					return nil, err
+				}
+				if fs == nil {
+					fs = make(Fields)
+				}
+				fs[num] = NewBytes(r, off, int64(l))
+				pos = off + int64(l)
+				start = pos
+				continue
+			}
+		}
+		// A later occurrence of a field overrides a lazily loaded value.
+		delete(fs, num)
+		if pos, err = s.skipField(pos, 0); err != nil {
+			return nil, err
+		}
+	}
+	buf, err := s.appendRange(buf, start, size)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal allowing partial messages, since the lazily loaded fields
+	// are cleared afterwards.
+	uo := o.UnmarshalOptions
+	uo.AllowPartial = true
+	if err := uo.Unmarshal(buf, m); err != nil {
+		return nil, err
+	}
+	mr := m.ProtoReflect()
+	for num := range fs {
+		mr.Clear(md.Fields().ByNumber(num))
+	}
+	if !o.AllowPartial {
+		if err := proto.CheckInitialized(m); err != nil {
+			return nil, err
+		}
+	}
+	return fs, nil
+}
+
+// UnmarshalFrom unmarshals the wire-format message of the given size from r
+// with the default options.
+//
+// See the documentation for [UnmarshalOptions.UnmarshalFrom].
+func UnmarshalFrom(r io.ReaderAt, size int64, m proto.Message) (Fields, error) {
+	return UnmarshalOptions{}.UnmarshalFrom(r, size, m)
+}
+
+// scanner reads the wire format from an io.ReaderAt
+// through a window of buffered input.
+type scanner struct {
+	r    io.ReaderAt
+	size int64
+	off  int64  // offset of buf in the input
+	buf  []byte // buffered input
+}
+
+// windowSize is the minimum size of the input read into the window.
+const windowSize = 4 << 10
+
+// peek returns the next n bytes of input at pos,
+// or fewer if the input ends before.
+func (s *scanner) peek(pos int64, n int) ([]byte, error) {
+	end := min(pos+int64(n), s.size)
+	if pos < s.off || end > s.off+int64(len(s.buf)) {
+		wend := min(pos+max(int64(n), windowSize), s.size)
+		if int64(cap(s.buf)) < wend-pos {
+			s.buf = make([]byte, wend-pos)
+		}
+		s.buf = s.buf[:wend-pos]
+		s.off = pos
+		if err := readAt(s.r, s.buf, pos); err != nil {
+			return nil, err
+		}
+	}
+	return s.buf[pos-s.off : end-s.off], nil
+}
+
+// tag parses the tag at pos.
+func (s *scanner) tag(pos int64) (protowire.Number, protowire.Type, int64, error) {
+	b, err := s.peek(pos, protowire.SizeVarint(1<<64-1))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	num, typ, n := protowire.ConsumeTag(b)
+	if n < 0 {
+		return 0, 0, 0, errors.Wrap(protowire.ParseError(n), "invalid field at offset %d", pos)
+	}
+	return num, typ, int64(n), nil
+}
+
+// varint parses the varint at pos.
+func (s *scanner) varint(pos int64) (uint64, int64, error) {
+	b, err := s.peek(pos, protowire.SizeVarint(1<<64-1))
+	if err != nil {
+		return 0, 0, err
+	}
+	v, n := protowire.ConsumeVarint(b)
+	if n < 0 {
+		return 0, 0, errors.Wrap(protowire.ParseError(n), "invalid varint at offset %d", pos)
+	}
+	return v, int64(n), nil
+}
+
+// skipField returns the offset of the end of the field at pos,
+// which is within a group nested depth levels deep.
+func (s *scanner) skipField(pos int64, depth int) (int64, error) {
+	num, typ, n, err := s.tag(pos)
+	if err != nil {
+		return 0, err
+	}
+	end := pos + n
+	switch typ {
+	case protowire.VarintType:
+		_, n, err := s.varint(end)
+		if err != nil {
+			return 0, err
+		}
+		end += n
+	case protowire.Fixed32Type:
+		end += int64(protowire.SizeFixed32())
+	case protowire.Fixed64Type:
+		end += int64(protowire.SizeFixed64())
+	case protowire.BytesType:
+		l, n, err := s.varint(end)
+		if err != nil {
+			return 0, err
+		}
+		if l > uint64(s.size-end-n) {
+			return 0, errors.New("field %d at offset %d extends past the end of the message", num, pos)
+		}
+		end += n + int64(l)
+	case protowire.StartGroupType:
+		if depth >= protowire.DefaultRecursionLimit {
+			return 0, errors.New("exceeded max recursion depth")
+		}
+		for {
+			if end >= s.size {
+				return 0, errors.New("group %d at offset %d is not terminated", num, pos)
+			}
+			num2, typ2, n2, err := s.tag(end)
+			if err != nil {
+				return 0, err
+			}
+			if typ2 == protowire.EndGroupType {
+				if num2 != num {
+					return 0, errors.New("mismatching end of group %d at offset %d", num, end)
+				}
+				end += n2
+				break
+			}
+			if end, err = s.skipField(end, depth+1); err != nil {
+				return 0, err
+			}
+		}
+	default:
+		return 0, errors.New("unexpected wire type %d of field %d at offset %d", typ, num, pos)
+	}
+	if end > s.size {
+		return 0, errors.New("field %d at offset %d extends past the end of the message", num, pos)
+	}
+	return end, nil
+}
+
+// appendRange appends the input in [start, end) to b.
+func (s *scanner) appendRange(b []byte, start, end int64) ([]byte, error) {
+	if start == end {
+		return b, nil
+	}
+	n := len(b)
+	b = append(b, make([]byte, end-start)...)
+	if err := readAt(s.r, b[n:], start); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// readAt reads exactly len(b) bytes of r at offset off into b.
+func readAt(r io.ReaderAt, b []byte, off int64) error {
+	n, err := r.ReadAt(b, off)
+	if n == len(b) {
+		return nil
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
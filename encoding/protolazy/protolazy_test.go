@@ -0,0 +1,206 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protolazy_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protolazy"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protopack"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestRoundTrip(t *testing.T) {
+	large := bytes.Repeat([]byte("artifact"), 1<<14)
+	want := &testpb.TestAllTypes{
+		OptionalInt32: proto.Int32(1),
+		OptionalBytes: large,
+		Optionalgroup: &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(2)},
+		RepeatedBytes: [][]byte{large},
+		OneofField:    &testpb.TestAllTypes_OneofBytes{OneofBytes: large},
+	}
+	b, err := proto.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := new(testpb.TestAllTypes)
+	fs, err := protolazy.UnmarshalFrom(bytes.NewReader(b), int64(len(b)), m)
+	if err != nil {
+		t.Fatalf("UnmarshalFrom() error: %v", err)
+	}
+	if len(fs) != 1 || fs[15] == nil {
+		t.Fatalf("UnmarshalFrom() fields = %v, want only field 15", fs)
+	}
+	if m.OptionalBytes != nil {
+		t.Errorf("lazily loaded field is populated in the message")
+	}
+	wantEager := proto.Clone(want).(*testpb.TestAllTypes)
+	wantEager.OptionalBytes = nil
+	if !proto.Equal(m, wantEager) {
+		t.Errorf("UnmarshalFrom() message mismatch:\ngot:  %v\nwant: %v", m, wantEager)
+	}
+
+	// Marshaling writes the lazily loaded fields without loading them.
+	var out bytes.Buffer
+	n, err := protolazy.MarshalTo(&out, m, fs)
+	if err != nil {
+		t.Fatalf("MarshalTo() error: %v", err)
+	}
+	if n != int64(out.Len()) || n != (protolazy.MarshalOptions{}).Size(m, fs) {
+		t.Errorf("MarshalTo() = %d, Size() = %d, want %d", n, (protolazy.MarshalOptions{}).Size(m, fs), out.Len())
+	}
+	if fs[15].Loaded() {
+		t.Errorf("MarshalTo() loaded the field")
+	}
+	got := new(testpb.TestAllTypes)
+	if err := proto.Unmarshal(out.Bytes(), got); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("MarshalTo() output mismatch")
+	}
+
+	if err := fs.Load(m); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !fs[15].Loaded() || !proto.Equal(m, want) {
+		t.Errorf("Load() did not populate the field")
+	}
+	if _, err := protolazy.MarshalTo(new(bytes.Buffer), m, fs); err == nil {
+		t.Errorf("MarshalTo() with a populated lazily loaded field succeeded, want error")
+	}
+}
+
+func TestLastOccurrence(t *testing.T) {
+	large := bytes.Repeat([]byte("x"), 100)
+	opts := protolazy.UnmarshalOptions{MinSize: 100}
+	tests := []struct {
+		desc     string
+		input    protopack.Message
+		wantLazy bool
+		want     []byte
+	}{{
+		desc: "small after large",
+		input: protopack.Message{
+			protopack.Tag{Number: 15, Type: protopack.BytesType}, protopack.Bytes(large),
+			protopack.Tag{Number: 15, Type: protopack.BytesType}, protopack.Bytes("small"),
+		},
+		want: []byte("small"),
+	}, {
+		desc: "large after small",
+		input: protopack.Message{
+			protopack.Tag{Number: 15, Type: protopack.BytesType}, protopack.Bytes("small"),
+			protopack.Tag{Number: 15, Type: protopack.BytesType}, protopack.Bytes(large),
+		},
+		wantLazy: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b := tt.input.Marshal()
+			m := new(testpb.TestAllTypes)
+			fs, err := opts.UnmarshalFrom(bytes.NewReader(b), int64(len(b)), m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (fs[15] != nil) != tt.wantLazy {
+				t.Errorf("field 15 loaded lazily = %v, want %v", fs[15] != nil, tt.wantLazy)
+			}
+			if !bytes.Equal(m.OptionalBytes, tt.want) {
+				t.Errorf("OptionalBytes = %q, want %q", m.OptionalBytes, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	large := bytes.Repeat([]byte("x"), 100)
+	opts := protolazy.UnmarshalOptions{MinSize: 10}
+	for _, tt := range []struct {
+		desc  string
+		input []byte
+	}{{
+		desc: "truncated lazily loaded field",
+		input: protopack.Message{
+			protopack.Tag{Number: 15, Type: protopack.BytesType}, protopack.Bytes(large),
+		}.Marshal()[:50],
+	}, {
+		desc: "truncated field",
+		input: protopack.Message{
+			protopack.Tag{Number: 14, Type: protopack.BytesType}, protopack.String("hello"),
+		}.Marshal()[:4],
+	}, {
+		desc: "unterminated group",
+		input: protopack.Message{
+			protopack.Tag{Number: 16, Type: protopack.StartGroupType},
+			protopack.Tag{Number: 17, Type: protopack.VarintType}, protopack.Varint(1),
+		}.Marshal(),
+	}, {
+		desc: "mismatching end group",
+		input: protopack.Message{
+			protopack.Tag{Number: 16, Type: protopack.StartGroupType},
+			protopack.Tag{Number: 17, Type: protopack.EndGroupType},
+		}.Marshal(),
+	}} {
+		m := new(testpb.TestAllTypes)
+		if _, err := opts.UnmarshalFrom(bytes.NewReader(tt.input), int64(len(tt.input)), m); err == nil {
+			t.Errorf("%s: UnmarshalFrom() succeeded, want error", tt.desc)
+		}
+	}
+
+	// The size must not exceed the input.
+	b := protopack.Message{
+		protopack.Tag{Number: 15, Type: protopack.BytesType}, protopack.Bytes(large),
+	}.Marshal()
+	if _, err := opts.UnmarshalFrom(bytes.NewReader(b), int64(len(b)+1), new(testpb.TestAllTypes)); err == nil {
+		t.Errorf("UnmarshalFrom() with size past the end of input succeeded, want error")
+	}
+
+	// Required fields are checked.
+	req := protopack.Message{
+		protopack.Tag{Number: 2, Type: protopack.BytesType}, protopack.Bytes(large),
+	}.Marshal()
+	if _, err := opts.UnmarshalFrom(bytes.NewReader(req), int64(len(req)), new(testpb.TestRequired)); err == nil {
+		t.Errorf("UnmarshalFrom() with missing required field succeeded, want error")
+	}
+}
+
+func TestFileBytes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "artifact")
+	if err := os.WriteFile(path, []byte("contents"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	v, err := protolazy.FileBytes(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if _, err := protolazy.MarshalTo(&out, &testpb.TestAllTypes{OptionalInt32: proto.Int32(1)}, protolazy.Fields{15: v}); err != nil {
+		t.Fatalf("MarshalTo() error: %v", err)
+	}
+	got := new(testpb.TestAllTypes)
+	if err := proto.Unmarshal(out.Bytes(), got); err != nil {
+		t.Fatal(err)
+	}
+	want := &testpb.TestAllTypes{OptionalInt32: proto.Int32(1), OptionalBytes: []byte("contents")}
+	if !proto.Equal(got, want) {
+		t.Errorf("MarshalTo() output = %v, want %v", got, want)
+	}
+
+	if _, err := protolazy.MarshalTo(&out, new(testpb.TestAllTypes), protolazy.Fields{45: v}); err == nil {
+		t.Errorf("MarshalTo() with repeated field succeeded, want error")
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protolog renders messages as structured values for [log/slog].
+//
+// A message wrapped with [Value] implements [slog.LogValuer], so it is only
+// rendered when a handler actually processes the record, and logging a
+// message at a disabled level costs nothing beyond the wrapper itself:
+//
+//	logger.Debug("received request", "request", protolog.Value(req))
+//
+// Each populated field becomes an attribute named after the field as in the
+// text format, and nested messages, lists, and maps become groups.
+// The values of sensitive fields, as determined by [protoredact.Options],
+// are replaced by [Redacted].
+package protolog
+
+import (
+	"log/slog"
+	"strconv"
+
+	"google.golang.org/protobuf/internal/order"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/proto/protoredact"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Redacted is the value logged in place of a redacted field.
+const Redacted = "[REDACTED]"
+
+// Options configures how messages are rendered.
+type Options struct {
+	// Redaction determines which fields are sensitive, using
+	// [protoredact.Options.IsSensitive]. By default, fields marked with
+	// the debug_redact field option are sensitive. The values of sensitive
+	// fields are replaced by [Redacted], regardless of Redaction.Placeholder.
+	Redaction protoredact.Options
+
+	// MaxDepth limits how deeply nested messages are rendered.
+	// Messages nested beneath the limit are logged as "...".
+	// If zero, messages are rendered to any depth.
+	MaxDepth int
+}
+
+// Value returns a [slog.LogValuer] that renders m using the default options.
+func Value(m proto.Message) slog.LogValuer {
+	return Options{}.Value(m)
+}
+
+// Value returns a [slog.LogValuer] that renders m according to o.
+// The message is not accessed until it is logged,
+// so it must not be modified until the logging call has returned.
+func (o Options) Value(m proto.Message) slog.LogValuer {
+	return messageValue{o: o, m: m}
+}
+
+type messageValue struct {
+	o Options
+	m proto.Message
+}
+
+// LogValue implements [slog.LogValuer].
+func (v messageValue) LogValue() slog.Value {
+	if v.m == nil {
+		return slog.GroupValue()
+	}
+	r := renderer{opts: v.o}
+	return r.message(v.m.ProtoReflect(), 0)
+}
+
+type renderer struct {
+	opts      Options
+	sensitive map[protoreflect.FieldDescriptor]bool // cached results of isRedacted
+}
+
+func (r *renderer) message(m protoreflect.Message, depth int) slog.Value {
+	if !m.IsValid() {
+		return slog.GroupValue()
+	}
+	if r.opts.MaxDepth > 0 && depth >= r.opts.MaxDepth {
+		return slog.StringValue("...")
+	}
+	var attrs []slog.Attr
+	order.RangeFields(m, order.IndexNameFieldOrder, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := fd.TextName()
+		if r.isRedacted(fd) {
+			attrs = append(attrs, slog.String(name, Redacted))
+			return true
+		}
+		attrs = append(attrs, slog.Attr{Key: name, Value: r.field(fd, v, depth)})
+		return true
+	})
+	return slog.GroupValue(attrs...)
+}
+
+func (r *renderer) isRedacted(fd protoreflect.FieldDescriptor) bool {
+	sensitive, ok := r.sensitive[fd]
+	if !ok {
+		if r.sensitive == nil {
+			r.sensitive = make(map[protoreflect.FieldDescriptor]bool)
+		}
+		sensitive = r.opts.Redaction.IsSensitive(fd)
+		r.sensitive[fd] = sensitive
+	}
+	return sensitive
+}
+
+func (r *renderer) field(fd protoreflect.FieldDescriptor, v protoreflect.Value, depth int) slog.Value {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		attrs := make([]slog.Attr, list.Len())
+		for i := range attrs {
+			attrs[i] = slog.Attr{Key: strconv.Itoa(i), Value: r.singular(fd, list.Get(i), depth)}
+		}
+		return slog.GroupValue(attrs...)
+	case fd.IsMap():
+		var attrs []slog.Attr
+		order.RangeEntries(v.Map(), order.GenericKeyOrder, func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			attrs = append(attrs, slog.Attr{Key: k.String(), Value: r.singular(fd.MapValue(), v, depth)})
+			return true
+		})
+		return slog.GroupValue(attrs...)
+	default:
+		return r.singular(fd, v, depth)
+	}
+}
+
+func (r *renderer) singular(fd protoreflect.FieldDescriptor, v protoreflect.Value, depth int) slog.Value {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return slog.BoolValue(v.Bool())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return slog.Int64Value(v.Int())
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return slog.Uint64Value(v.Uint())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return slog.Float64Value(v.Float())
+	case protoreflect.StringKind:
+		return slog.StringValue(v.String())
+	case protoreflect.BytesKind:
+		return slog.AnyValue(v.Bytes())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return slog.StringValue(string(ev.Name()))
+		}
+		return slog.Int64Value(int64(v.Enum()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return r.message(v.Message(), depth+1)
+	default:
+		return slog.AnyValue(v.Interface())
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protolog_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protolog"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/proto/protoredact"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+// logJSON logs v with a JSON handler and returns the logged attribute.
+func logJSON(v slog.LogValuer) string {
+	var b bytes.Buffer
+	h := slog.NewJSONHandler(&b, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key != "m" {
+				return slog.Attr{} // omit time, level, and msg
+			}
+			return a
+		},
+	})
+	slog.New(h).Info("", "m", v)
+	return strings.TrimSpace(b.String())
+}
+
+func TestValue(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32:      proto.Int32(-1),
+		OptionalUint64:     proto.Uint64(2),
+		OptionalDouble:     proto.Float64(1.5),
+		OptionalBool:       proto.Bool(true),
+		OptionalString:     proto.String("s"),
+		OptionalBytes:      []byte("b"),
+		OptionalNestedEnum: testpb.TestAllTypes_BAR.Enum(),
+		Optionalgroup:      &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(3)},
+		RepeatedInt32:      []int32{4, 5},
+		MapStringString:    map[string]string{"k2": "v2", "k1": "v1"},
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			A:           proto.Int32(6),
+			Corecursive: &testpb.TestAllTypes{OptionalInt32: proto.Int32(7)},
+		},
+	}
+
+	tests := []struct {
+		desc string
+		v    slog.LogValuer
+		want string
+	}{{
+		desc: "all kinds",
+		v:    protolog.Value(m),
+		want: `{"m":{"optional_int32":-1,"optional_uint64":2,"optional_double":1.5,"optional_bool":true,"optional_string":"s","optional_bytes":"Yg==",` +
+			`"OptionalGroup":{"a":3},"optional_nested_message":{"a":6,"corecursive":{"optional_int32":7}},"optional_nested_enum":"BAR",` +
+			`"repeated_int32":{"0":4,"1":5},"map_string_string":{"k1":"v1","k2":"v2"}}}`,
+	}, {
+		desc: "max depth",
+		v:    protolog.Options{MaxDepth: 2}.Value(m.OptionalNestedMessage),
+		want: `{"m":{"a":6,"corecursive":{"optional_int32":7}}}`,
+	}, {
+		desc: "max depth exceeded",
+		v:    protolog.Options{MaxDepth: 1}.Value(m.OptionalNestedMessage),
+		want: `{"m":{"a":6,"corecursive":"..."}}`,
+	}, {
+		desc: "redacted fields",
+		v: protolog.Options{Redaction: protoredact.Options{Fields: []protoreflect.FullName{
+			"goproto.proto.test.TestAllTypes.optional_string",
+			"goproto.proto.test.TestAllTypes.NestedMessage.a",
+		}}}.Value(&testpb.TestAllTypes{
+			OptionalString:        proto.String("secret"),
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)},
+		}),
+		want: `{"m":{"optional_string":"[REDACTED]","optional_nested_message":{"a":"[REDACTED]"}}}`,
+	}, {
+		desc: "nil message",
+		v:    protolog.Value(nil),
+		want: `{}`,
+	}, {
+		desc: "empty message",
+		v:    protolog.Value(&testpb.TestAllTypes{}),
+		want: `{}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := logJSON(tt.v); got != tt.want {
+				t.Errorf("logged:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDebugRedact(t *testing.T) {
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("redact.proto"),
+		Package: proto.String("redact"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Credentials"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("user"),
+				Number:   proto.Int32(1),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				JsonName: proto.String("user"),
+			}, {
+				Name:     proto.String("password"),
+				Number:   proto.Int32(2),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				JsonName: proto.String("password"),
+				Options:  &descriptorpb.FieldOptions{DebugRedact: proto.Bool(true)},
+			}},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := dynamicpb.NewMessage(fd.Messages().Get(0))
+	if err := prototext.Unmarshal([]byte(`user: "gopher" password: "hunter2"`), m); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := logJSON(protolog.Value(m)), `{"m":{"user":"gopher","password":"[REDACTED]"}}`; got != want {
+		t.Errorf("logged %s, want %s", got, want)
+	}
+	if got, want := logJSON(protolog.Options{Redaction: protoredact.Options{IgnoreDebugRedact: true}}.Value(m)), `{"m":{"user":"gopher","password":"hunter2"}}`; got != want {
+		t.Errorf("logged with IgnoreDebugRedact %s, want %s", got, want)
+	}
+	// Placeholder only applies to redacted messages, not to logged values.
+	if got, want := logJSON(protolog.Options{Redaction: protoredact.Options{Placeholder: "***"}}.Value(m)), `{"m":{"user":"gopher","password":"[REDACTED]"}}`; got != want {
+		t.Errorf("logged with Placeholder %s, want %s", got, want)
+	}
+}
+
+func TestLazy(t *testing.T) {
+	// The message is only rendered once the value is logged.
+	m := &testpb.TestAllTypes{OptionalInt32: proto.Int32(1)}
+	v := protolog.Value(m)
+	m.OptionalInt32 = proto.Int32(2)
+	if got, want := logJSON(v), `{"m":{"optional_int32":2}}`; got != want {
+		t.Errorf("logged %s, want %s", got, want)
+	}
+}
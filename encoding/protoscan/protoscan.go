@@ -0,0 +1,138 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoscan extracts individual fields from wire-format messages
+// without unmarshaling the entire message.
+//
+// Fields are identified by a path of field numbers, where every number but
+// the last identifies a sub-message (or group) field, and the last
+// identifies the field to extract. For example, the path (2, 1) identifies
+// field 1 within the message in field 2.
+//
+// As when unmarshaling, the last occurrence of a field takes precedence,
+// and occurrences of a sub-message field along the path are searched as if
+// they were merged. The functions in this package do not merge the
+// occurrences of a sub-message field that is itself extracted, and do not
+// unpack packed repeated fields.
+//
+// The input is validated only as far as it is scanned.
+package protoscan
+
+import (
+	"errors"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	protoerrors "google.golang.org/protobuf/internal/errors"
+)
+
+// Find returns the layout of the last occurrence of the field at path
+// within the wire-format message b. The offsets of the returned field
+// are relative to the start of b.
+// It reports false if the field is not present.
+func Find(b []byte, path ...protowire.Number) (f protowire.Field, found bool, err error) {
+	if len(path) == 0 {
+		return f, false, protoerrors.New("empty field path")
+	}
+	return find(b, 0, path)
+}
+
+// find scans the message b, which is located at offset base of the
+// original input.
+func find(b []byte, base int, path []protowire.Number) (f protowire.Field, found bool, err error) {
+	s := protowire.NewScanner(b)
+	for s.Next() {
+		g := s.Field()
+		if g.Number != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			g.Offset += base
+			g.ValueOffset += base
+			g.End += base
+			f, found = g, true
+			continue
+		}
+		if g.Type != protowire.BytesType && g.Type != protowire.StartGroupType {
+			return f, false, protoerrors.New("field %d at offset %d has wire type %d, want a message", g.Number, base+g.Offset, g.Type)
+		}
+		if g2, ok, err := find(g.Value(b), base+g.ValueOffset, path[1:]); err != nil {
+			return f, false, err
+		} else if ok {
+			f, found = g2, true
+		}
+	}
+	if err := s.Err(); err != nil {
+		var e *protowire.Error
+		if errors.As(err, &e) {
+			e2 := *e
+			e2.Offset += base
+			err = &e2
+		}
+		return f, false, err
+	}
+	return f, found, nil
+}
+
+// Bytes returns the value of the length-delimited field at path,
+// such as a bytes, string, or message field. The returned slice
+// aliases b.
+func Bytes(b []byte, path ...protowire.Number) ([]byte, bool, error) {
+	f, ok, err := findType(b, path, protowire.BytesType)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	return f.Value(b), true, nil
+}
+
+// String returns the value of the length-delimited field at path
+// as a string. It does not validate that the value is valid UTF-8.
+func String(b []byte, path ...protowire.Number) (string, bool, error) {
+	v, ok, err := Bytes(b, path...)
+	return string(v), ok, err
+}
+
+// Varint returns the value of the varint field at path. The value is
+// the raw unsigned encoding; use [protowire.DecodeZigZag] for sint32 and
+// sint64 fields, and [protowire.DecodeBool] for bool fields.
+func Varint(b []byte, path ...protowire.Number) (uint64, bool, error) {
+	f, ok, err := findType(b, path, protowire.VarintType)
+	if !ok || err != nil {
+		return 0, false, err
+	}
+	v, _ := protowire.ConsumeVarint(f.Value(b))
+	return v, true, nil
+}
+
+// Fixed32 returns the value of the 32-bit fixed-width field at path,
+// such as a fixed32, sfixed32, or float field.
+func Fixed32(b []byte, path ...protowire.Number) (uint32, bool, error) {
+	f, ok, err := findType(b, path, protowire.Fixed32Type)
+	if !ok || err != nil {
+		return 0, false, err
+	}
+	v, _ := protowire.ConsumeFixed32(f.Value(b))
+	return v, true, nil
+}
+
+// Fixed64 returns the value of the 64-bit fixed-width field at path,
+// such as a fixed64, sfixed64, or double field.
+func Fixed64(b []byte, path ...protowire.Number) (uint64, bool, error) {
+	f, ok, err := findType(b, path, protowire.Fixed64Type)
+	if !ok || err != nil {
+		return 0, false, err
+	}
+	v, _ := protowire.ConsumeFixed64(f.Value(b))
+	return v, true, nil
+}
+
+func findType(b []byte, path []protowire.Number, typ protowire.Type) (protowire.Field, bool, error) {
+	f, ok, err := Find(b, path...)
+	if !ok || err != nil {
+		return f, false, err
+	}
+	if f.Type != typ {
+		return f, false, protoerrors.New("field %d at offset %d has wire type %d, want %d", f.Number, f.Offset, f.Type, typ)
+	}
+	return f, true, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoscan_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protoscan"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestExtract(t *testing.T) {
+	b, err := proto.Marshal(&testpb.TestAllTypes{
+		OptionalInt32:   proto.Int32(5),
+		OptionalSint64:  proto.Int64(-3),
+		OptionalFixed32: proto.Uint32(7),
+		OptionalDouble:  proto.Float64(1.5),
+		OptionalString:  proto.String("key"),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			A: proto.Int32(1),
+			Corecursive: &testpb.TestAllTypes{
+				OptionalString: proto.String("inner"),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A later occurrence of the nested message is merged with the earlier one.
+	b, err = proto.MarshalOptions{}.MarshalAppend(b, &testpb.TestAllTypes{
+		OptionalInt32: proto.Int32(6),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			A: proto.Int32(2),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok, err := protoscan.Varint(b, 1); err != nil || !ok || v != 6 {
+		t.Errorf("Varint(1) = (%v, %v, %v), want (6, true, nil)", v, ok, err)
+	}
+	if v, ok, err := protoscan.Varint(b, 6); err != nil || !ok || protowire.DecodeZigZag(v) != -3 {
+		t.Errorf("Varint(6) = (%v, %v, %v), want zigzag -3", v, ok, err)
+	}
+	if v, ok, err := protoscan.Fixed32(b, 7); err != nil || !ok || v != 7 {
+		t.Errorf("Fixed32(7) = (%v, %v, %v), want (7, true, nil)", v, ok, err)
+	}
+	if v, ok, err := protoscan.Fixed64(b, 12); err != nil || !ok || math.Float64frombits(v) != 1.5 {
+		t.Errorf("Fixed64(12) = (%v, %v, %v), want 1.5", v, ok, err)
+	}
+	if v, ok, err := protoscan.String(b, 14); err != nil || !ok || v != "key" {
+		t.Errorf("String(14) = (%q, %v, %v), want (key, true, nil)", v, ok, err)
+	}
+	if v, ok, err := protoscan.Varint(b, 18, 1); err != nil || !ok || v != 2 {
+		t.Errorf("Varint(18, 1) = (%v, %v, %v), want (2, true, nil)", v, ok, err)
+	}
+	if v, ok, err := protoscan.String(b, 18, 2, 14); err != nil || !ok || v != "inner" {
+		t.Errorf("String(18, 2, 14) = (%q, %v, %v), want (inner, true, nil)", v, ok, err)
+	}
+	if _, ok, err := protoscan.Bytes(b, 15); err != nil || ok {
+		t.Errorf("Bytes(15) = (%v, %v), want (false, nil)", ok, err)
+	}
+	if _, ok, err := protoscan.Bytes(b, 18, 3, 1); err != nil || ok {
+		t.Errorf("Bytes(18, 3, 1) = (%v, %v), want (false, nil)", ok, err)
+	}
+
+	// The returned layout is relative to the start of the input.
+	f, ok, err := protoscan.Find(b, 18, 2, 14)
+	if err != nil || !ok {
+		t.Fatalf("Find(18, 2, 14) = (%v, %v)", ok, err)
+	}
+	if v := string(f.Value(b)); v != "inner" {
+		t.Errorf("Find(18, 2, 14).Value() = %q, want %q", v, "inner")
+	}
+
+	for _, path := range [][]protowire.Number{
+		{},
+		{14},    // wrong wire type
+		{1, 1},  // not a message
+		{18, 1}, // wrong wire type within a message
+	} {
+		if _, _, err := protoscan.Fixed32(b, path...); err == nil {
+			t.Errorf("Fixed32(%v) succeeded, want error", path)
+		}
+	}
+}
+
+func TestExtractError(t *testing.T) {
+	// Field 18 contains a truncated varint.
+	b := []byte{0x08, 0x01, 0x92, 0x01, 0x02, 0x08, 0x80}
+	_, _, err := protoscan.Varint(b, 18, 1)
+	var e *protowire.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("Varint() error = %v, want *protowire.Error", err)
+	}
+	if e.Kind != protowire.ErrorTruncated || e.Offset != 6 {
+		t.Errorf("Varint() error = %+v, want truncation at offset 6", *e)
+	}
+}
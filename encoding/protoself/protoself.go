@@ -0,0 +1,265 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoself marshals and unmarshals self-describing messages,
+// which carry the descriptors of their message type along with the message,
+// so that they may be decoded without access to the original .proto files
+// or generated code.
+//
+// A self-describing message is encoded as the wire-format message
+//
+//	message SelfDescribingMessage {
+//		// The file declaring the message type and all of its dependencies.
+//		google.protobuf.FileDescriptorSet descriptor_set = 1;
+//		// The message, whose type is found in the descriptor set.
+//		google.protobuf.Any message = 2;
+//	}
+//
+// as described in the "Self-describing Messages" section of
+// the protocol buffers documentation.
+package protoself
+
+import (
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Field numbers of the SelfDescribingMessage.
+const (
+	descriptorSetField protowire.Number = 1
+	messageField       protowire.Number = 2
+)
+
+// Marshal returns the self-describing encoding of m.
+//
+// See the documentation for [MarshalOptions.Marshal].
+func Marshal(m proto.Message) ([]byte, error) {
+	return MarshalOptions{}.Marshal(m)
+}
+
+// MarshalOptions is a configurable self-describing message marshaler.
+// The embedded options are used to marshal the message itself.
+type MarshalOptions struct{ proto.MarshalOptions }
+
+// Marshal returns the self-describing encoding of m.
+//
+// The descriptor set includes the file declaring the type of m, the files
+// declaring any extensions populated within m, and all of their transitive
+// dependencies, with each file listed after its dependencies.
+// The types of messages nested within google.protobuf.Any fields are not
+// included, since their descriptors may not be available.
+func (o MarshalOptions) Marshal(m proto.Message) ([]byte, error) {
+	if m == nil {
+		return nil, errors.New("invalid nil message")
+	}
+	a := new(anypb.Any)
+	if err := anypb.MarshalFrom(a, m, o.MarshalOptions); err != nil {
+		return nil, err
+	}
+	set := &descriptorpb.FileDescriptorSet{File: fileClosure(m.ProtoReflect())}
+
+	opts := proto.MarshalOptions{Deterministic: o.Deterministic}
+	setBytes, err := opts.Marshal(set)
+	if err != nil {
+		return nil, err
+	}
+	anyBytes, err := opts.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	b := protowire.AppendTag(nil, descriptorSetField, protowire.BytesType)
+	b = protowire.AppendBytes(b, setBytes)
+	b = protowire.AppendTag(b, messageField, protowire.BytesType)
+	b = protowire.AppendBytes(b, anyBytes)
+	return b, nil
+}
+
+// fileClosure returns the files that the descriptors of m are declared in,
+// in dependency order.
+func fileClosure(m protoreflect.Message) []*descriptorpb.FileDescriptorProto {
+	var files []*descriptorpb.FileDescriptorProto
+	seen := make(map[string]bool)
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		files = append(files, protodesc.ToFileDescriptorProto(fd))
+	}
+	add(m.Descriptor().ParentFile())
+
+	// Extensions are visited in an unspecified order,
+	// so sort their files to produce a deterministic result.
+	extFiles := make(map[string]protoreflect.FileDescriptor)
+	addExtensionFiles(m, extFiles)
+	paths := make([]string, 0, len(extFiles))
+	for path := range extFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		add(extFiles[path])
+	}
+	return files
+}
+
+// addExtensionFiles adds the files declaring extensions populated
+// within m to files, keyed by path.
+func addExtensionFiles(m protoreflect.Message, files map[string]protoreflect.FileDescriptor) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.IsExtension() {
+			files[fd.ParentFile().Path()] = fd.ParentFile()
+		}
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+					addExtensionFiles(v.Message(), files)
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				l := v.List()
+				for i := 0; i < l.Len(); i++ {
+					addExtensionFiles(l.Get(i).Message(), files)
+				}
+			}
+		case fd.Message() != nil:
+			addExtensionFiles(v.Message(), files)
+		}
+		return true
+	})
+}
+
+// Envelope is a parsed self-describing message.
+type Envelope struct {
+	// Files are the files of the descriptor set.
+	Files *protoregistry.Files
+
+	// TypeURL is the type URL of the message,
+	// whose last path segment is the full name of its type.
+	TypeURL string
+
+	// Value is the wire-format encoding of the message.
+	Value []byte
+}
+
+// Parse parses the self-describing message b and builds the descriptors
+// of its descriptor set, without unmarshaling the message itself.
+func Parse(b []byte) (*Envelope, error) {
+	set := new(descriptorpb.FileDescriptorSet)
+	a := new(anypb.Any)
+	opts := proto.UnmarshalOptions{Merge: true}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ == protowire.BytesType && (num == descriptorSetField || num == messageField) {
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			b = b[n:]
+			var err error
+			if num == descriptorSetField {
+				err = opts.Unmarshal(v, set)
+			} else {
+				err = opts.Unmarshal(v, a)
+			}
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	if a.TypeUrl == "" {
+		return nil, errors.New("self-describing message has no message type")
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid descriptor set")
+	}
+	return &Envelope{Files: files, TypeURL: a.TypeUrl, Value: a.Value}, nil
+}
+
+// MessageName returns the full name of the type of the message.
+func (e *Envelope) MessageName() protoreflect.FullName {
+	return (&anypb.Any{TypeUrl: e.TypeURL}).MessageName()
+}
+
+// Descriptor returns the descriptor of the type of the message,
+// as found in the descriptor set.
+func (e *Envelope) Descriptor() (protoreflect.MessageDescriptor, error) {
+	name := e.MessageName()
+	d, err := e.Files.FindDescriptorByName(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "message type %v", name)
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, errors.New("%v is not a message type", name)
+	}
+	return md, nil
+}
+
+// Unmarshal parses the self-describing message b and returns
+// a dynamic message of the type described by it.
+//
+// See the documentation for [UnmarshalOptions.Unmarshal].
+func Unmarshal(b []byte) (proto.Message, error) {
+	return UnmarshalOptions{}.Unmarshal(b)
+}
+
+// UnmarshalOptions is a configurable self-describing message unmarshaler.
+// The embedded options are used to unmarshal the message itself.
+type UnmarshalOptions struct{ proto.UnmarshalOptions }
+
+// Unmarshal parses the self-describing message b and returns
+// a [dynamicpb.Message] of the type described by it.
+//
+// The message is always built from the descriptors in b, even if the type
+// is linked into the program, so that it is decoded as it was written.
+// Use [proto.Marshal] and [proto.Unmarshal] to convert it to a generated
+// message type. If the embedded options have no resolver, extensions and
+// google.protobuf.Any fields are resolved using the descriptor set.
+func (o UnmarshalOptions) Unmarshal(b []byte) (proto.Message, error) {
+	e, err := Parse(b)
+	if err != nil {
+		return nil, err
+	}
+	md, err := e.Descriptor()
+	if err != nil {
+		return nil, err
+	}
+	if o.Resolver == nil {
+		o.Resolver = dynamicpb.NewTypes(e.Files)
+	}
+	m := dynamicpb.NewMessage(md)
+	if err := o.UnmarshalOptions.Unmarshal(e.Value, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
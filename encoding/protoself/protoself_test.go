@@ -0,0 +1,111 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoself_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protoself"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	test3pb "google.golang.org/protobuf/internal/testprotos/test3"
+	testeditionspb "google.golang.org/protobuf/internal/testprotos/testeditions"
+)
+
+func TestRoundTrip(t *testing.T) {
+	// The extension is declared in a file that the extended message's file
+	// does not import, so it must be added to the descriptor set separately.
+	ext := &testeditionspb.TestFeatureResolution{}
+	proto.SetExtension(ext, testeditionspb.E_OtherFileGlobalPackedExtension, []int32{1, 2})
+	for _, m := range []proto.Message{
+		&test3pb.TestAllTypes{
+			SingularString: "hello",
+			SingularNestedMessage: &test3pb.TestAllTypes_NestedMessage{
+				A: 1,
+			},
+			RepeatedInt32:   []int32{1, 2, 3},
+			MapStringString: map[string]string{"k": "v"},
+		},
+		ext,
+	} {
+		b, err := protoself.MarshalOptions{MarshalOptions: proto.MarshalOptions{Deterministic: true}}.Marshal(m)
+		if err != nil {
+			t.Fatalf("Marshal(%T) error: %v", m, err)
+		}
+		got, err := protoself.Unmarshal(b)
+		if err != nil {
+			t.Fatalf("Unmarshal error: %v", err)
+		}
+		if _, ok := got.(*dynamicpb.Message); !ok {
+			t.Errorf("Unmarshal returned %T, want *dynamicpb.Message", got)
+		}
+		// Convert the dynamic message back to the generated type to compare.
+		want := m.ProtoReflect().Type().New().Interface()
+		gotBytes, err := proto.Marshal(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := proto.Unmarshal(gotBytes, want); err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(want, m) {
+			t.Errorf("round trip of %T mismatch:\ngot  %v\nwant %v", m, want, m)
+		}
+		if got.ProtoReflect().GetUnknown() != nil {
+			t.Errorf("Unmarshal left unknown fields %x; want extensions resolved from the descriptor set", got.ProtoReflect().GetUnknown())
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	m := &test3pb.TestAllTypes{SingularInt32: 1}
+	b, err := protoself.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := protoself.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.MessageName(), m.ProtoReflect().Descriptor().FullName(); got != want {
+		t.Errorf("MessageName() = %v, want %v", got, want)
+	}
+	md, err := e.Descriptor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md == m.ProtoReflect().Descriptor() {
+		t.Errorf("Descriptor() returned the linked descriptor, want one built from the descriptor set")
+	}
+	if _, err := e.Files.FindFileByPath("internal/testprotos/test3/test_import.proto"); err != nil {
+		t.Errorf("descriptor set is missing a dependency: %v", err)
+	}
+
+	got := new(test3pb.TestAllTypes)
+	if err := proto.Unmarshal(e.Value, got); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, m) {
+		t.Errorf("Parse().Value = %v, want %v", got, m)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		in   []byte
+	}{
+		{"truncated", []byte{0x0a, 0x05}},
+		{"missing message", nil},
+		{"unknown type", protowire.AppendBytes(protowire.AppendTag(nil, 2, protowire.BytesType),
+			protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), "type.googleapis.com/foo.Bar"))},
+	} {
+		if _, err := protoself.Unmarshal(tt.in); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded, want error", tt.desc)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"unicode/utf8"
 
+	"google.golang.org/protobuf/encoding/protoknown"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/internal/encoding/messageset"
 	"google.golang.org/protobuf/internal/encoding/text"
 	"google.golang.org/protobuf/internal/errors"
@@ -42,6 +44,28 @@ type UnmarshalOptions struct {
 	// By default, unmarshal rejects unknown fields as an error.
 	DiscardUnknown bool
 
+	// ParseUnknown specifies whether to parse fields identified by a field
+	// number that does not resolve to a known or extension field into the
+	// unknown fields of the message, as emitted by MarshalOptions.EmitUnknown.
+	// The wire type of each field is determined by the form of its value:
+	//   - a decimal integer is a varint;
+	//   - a hexadecimal integer with exactly 8 or 16 digits
+	//     (e.g., 0x00000047) is a fixed32 or fixed64 value, respectively,
+	//     and any other integer is a varint;
+	//   - a string is a length-delimited value;
+	//   - a message of fields identified by number is a group.
+	//
+	// A list of such values is parsed as repeated fields.
+	// The output of MarshalOptions.EmitParsableUnknown is parsed back
+	// into the same unknown fields. DiscardUnknown takes precedence.
+	ParseUnknown bool
+
+	// Behaviors specifies custom behaviors of message types, which are
+	// unmarshaled either from strings parsed by the behavior or from their
+	// fields, and are then validated by the behavior. If nil, no custom
+	// behaviors are applied.
+	Behaviors *protoknown.Behaviors
+
 	// Resolver is used for looking up types when unmarshaling
 	// google.protobuf.Any messages or extension fields.
 	// If nil, this defaults to using protoregistry.GlobalTypes.
@@ -112,7 +136,15 @@ func (d decoder) unmarshalMessage(m protoreflect.Message, checkDelims bool) erro
 	if messageDesc.FullName() == genid.Any_message_fullname {
 		return d.unmarshalAny(m, checkDelims)
 	}
+	if b, ok := d.opts.Behaviors.Lookup(messageDesc.FullName()); ok {
+		return d.unmarshalCustom(b, m, checkDelims)
+	}
+	return d.unmarshalFields(m, checkDelims)
+}
 
+// unmarshalFields unmarshals the fields of the given protoreflect.Message.
+func (d decoder) unmarshalFields(m protoreflect.Message, checkDelims bool) error {
+	messageDesc := m.Descriptor()
 	if checkDelims {
 		tok, err := d.Read()
 		if err != nil {
@@ -197,6 +229,14 @@ func (d decoder) unmarshalMessage(m protoreflect.Message, checkDelims bool) erro
 				d.skipValue()
 				continue
 			}
+			if isFieldNumberName && d.opts.ParseUnknown {
+				b, err := d.unmarshalUnknown(m.GetUnknown(), protowire.Number(tok.FieldNumber()))
+				if err != nil {
+					return err
+				}
+				m.SetUnknown(b)
+				continue
+			}
 			return d.newError(tok.Pos(), "unknown field: %v", tok.RawString())
 		}
 
@@ -381,7 +421,7 @@ func (d decoder) unmarshalList(fd protoreflect.FieldDescriptor, list protoreflec
 				case text.ListClose:
 					d.Read()
 					return nil
-				case text.MessageOpen:
+				case text.MessageOpen, text.Scalar:
 					pval := list.NewElement()
 					if err := d.unmarshalMessage(pval.Message(), true); err != nil {
 						return err
@@ -392,7 +432,7 @@ func (d decoder) unmarshalList(fd protoreflect.FieldDescriptor, list protoreflec
 				}
 			}
 
-		case text.MessageOpen:
+		case text.MessageOpen, text.Scalar:
 			pval := list.NewElement()
 			if err := d.unmarshalMessage(pval.Message(), true); err != nil {
 				return err
@@ -716,6 +756,121 @@ func (d decoder) unmarshalExpandedAny(typeURL string, pos int) ([]byte, error) {
 	return b, nil
 }
 
+// unmarshalCustom unmarshals a message type with a custom behavior in
+// the Behaviors option. Its value is either a string parsed by the behavior
+// or a message, which is validated by the behavior.
+func (d decoder) unmarshalCustom(b protoknown.Behavior, m protoreflect.Message, checkDelims bool) error {
+	name := m.Descriptor().FullName()
+	if checkDelims {
+		tok, err := d.Peek()
+		if err != nil {
+			return err
+		}
+		if tok.Kind() == text.Scalar {
+			d.Read()
+			s, ok := tok.String()
+			if !ok {
+				return d.unexpectedTokenError(tok)
+			}
+			if err := b.Unmarshal(s, m); err != nil {
+				return d.newError(tok.Pos(), "invalid %v value %v: %v", name, tok.RawString(), err)
+			}
+			return nil
+		}
+	}
+	if err := d.unmarshalFields(m, checkDelims); err != nil {
+		return err
+	}
+	if b.Validate != nil {
+		if err := b.Validate(m); err != nil {
+			return errors.New("invalid %v value: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// unmarshalUnknown parses the value of the unknown field with the given
+// number and appends its wire-format encoding to b.
+func (d decoder) unmarshalUnknown(b []byte, num protowire.Number) ([]byte, error) {
+	tok, err := d.Read()
+	if err != nil {
+		return nil, err
+	}
+	if tok.Kind() != text.ListOpen {
+		return d.unmarshalUnknownValue(b, num, tok)
+	}
+	for {
+		tok, err := d.Read()
+		if err != nil {
+			return nil, err
+		}
+		if tok.Kind() == text.ListClose {
+			return b, nil
+		}
+		if b, err = d.unmarshalUnknownValue(b, num, tok); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// unmarshalUnknownValue appends the wire-format encoding of the unknown
+// field with the given number and the value starting with tok to b.
+func (d decoder) unmarshalUnknownValue(b []byte, num protowire.Number, tok text.Token) ([]byte, error) {
+	switch tok.Kind() {
+	case text.Scalar:
+		if s, ok := tok.String(); ok {
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			return protowire.AppendString(b, s), nil
+		}
+		if raw := tok.RawString(); len(raw) > 2 && (raw[:2] == "0x" || raw[:2] == "0X") {
+			switch len(raw) - 2 {
+			case 8:
+				if v, ok := tok.Uint32(); ok {
+					b = protowire.AppendTag(b, num, protowire.Fixed32Type)
+					return protowire.AppendFixed32(b, v), nil
+				}
+			case 16:
+				if v, ok := tok.Uint64(); ok {
+					b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+					return protowire.AppendFixed64(b, v), nil
+				}
+			}
+		}
+		if v, ok := tok.Uint64(); ok {
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			return protowire.AppendVarint(b, v), nil
+		}
+		if v, ok := tok.Int64(); ok {
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			return protowire.AppendVarint(b, uint64(v)), nil
+		}
+		return nil, d.newError(tok.Pos(), "invalid value for unknown field %d: %v", num, tok.RawString())
+
+	case text.MessageOpen:
+		b = protowire.AppendTag(b, num, protowire.StartGroupType)
+		for {
+			tok, err := d.Read()
+			if err != nil {
+				return nil, err
+			}
+			if tok.Kind() == text.MessageClose {
+				return protowire.AppendTag(b, num, protowire.EndGroupType), nil
+			}
+			if tok.Kind() != text.Name || tok.NameKind() != text.FieldNumber {
+				return nil, d.newError(tok.Pos(), "invalid field in unknown group %d: %v", num, tok.RawString())
+			}
+			n := protowire.Number(tok.FieldNumber())
+			if !n.IsValid() {
+				return nil, d.newError(tok.Pos(), "invalid field number: %d", n)
+			}
+			if b, err = d.unmarshalUnknown(b, n); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, d.unexpectedTokenError(tok)
+}
+
 // skipValue makes the decoder parse a field value in order to advance the read
 // to the next field. It relies on Read returning an error if the types are not
 // in valid sequence.
@@ -13,6 +13,7 @@ import (
 	"google.golang.org/protobuf/internal/flags"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/testing/protopack"
 
 	testpb "google.golang.org/protobuf/internal/testprotos/test"
 	weakpb "google.golang.org/protobuf/internal/testprotos/test/weak1"
@@ -369,6 +370,71 @@ s_string: "谷歌"
 		inputMessage: &pb3.Scalars{},
 		inputText:    "1: true",
 		wantErr:      "cannot specify field by number",
+	}, {
+		desc:         "ParseUnknown",
+		umo:          prototext.UnmarshalOptions{ParseUnknown: true},
+		inputMessage: &pb2.Scalars{},
+		inputText: `opt_int32: 1
+101: 255
+102: -1
+103: 0x00000047
+104: 0x0000000000000047
+105: 0x47
+106: "hello"
+107: [1, "two"]
+108: {
+  1: 0
+  2 { 3: "inside" }
+}`,
+		wantMessage: func() proto.Message {
+			m := &pb2.Scalars{OptInt32: proto.Int32(1)}
+			m.ProtoReflect().SetUnknown(protopack.Message{
+				protopack.Tag{Number: 101, Type: protopack.VarintType}, protopack.Varint(255),
+				protopack.Tag{Number: 102, Type: protopack.VarintType}, protopack.Varint(-1),
+				protopack.Tag{Number: 103, Type: protopack.Fixed32Type}, protopack.Uint32(0x47),
+				protopack.Tag{Number: 104, Type: protopack.Fixed64Type}, protopack.Uint64(0x47),
+				protopack.Tag{Number: 105, Type: protopack.VarintType}, protopack.Varint(0x47),
+				protopack.Tag{Number: 106, Type: protopack.BytesType}, protopack.String("hello"),
+				protopack.Tag{Number: 107, Type: protopack.VarintType}, protopack.Varint(1),
+				protopack.Tag{Number: 107, Type: protopack.BytesType}, protopack.String("two"),
+				protopack.Tag{Number: 108, Type: protopack.StartGroupType},
+				protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(0),
+				protopack.Tag{Number: 2, Type: protopack.StartGroupType},
+				protopack.Tag{Number: 3, Type: protopack.BytesType}, protopack.String("inside"),
+				protopack.Tag{Number: 2, Type: protopack.EndGroupType},
+				protopack.Tag{Number: 108, Type: protopack.EndGroupType},
+			}.Marshal())
+			return m
+		}(),
+	}, {
+		desc:         "ParseUnknown with known field number",
+		umo:          prototext.UnmarshalOptions{ParseUnknown: true},
+		inputMessage: &pb2.Scalars{},
+		inputText:    "1: true",
+		wantErr:      "cannot specify field by number",
+	}, {
+		desc:         "ParseUnknown with DiscardUnknown",
+		umo:          prototext.UnmarshalOptions{ParseUnknown: true, DiscardUnknown: true},
+		inputMessage: &pb2.Scalars{},
+		inputText:    `101: 1 102: { 1: "x" }`,
+		wantMessage:  &pb2.Scalars{},
+	}, {
+		desc:         "ParseUnknown with invalid value",
+		umo:          prototext.UnmarshalOptions{ParseUnknown: true},
+		inputMessage: &pb2.Scalars{},
+		inputText:    "101: 1.5",
+		wantErr:      "invalid value for unknown field 101: 1.5",
+	}, {
+		desc:         "ParseUnknown with named field in group",
+		umo:          prototext.UnmarshalOptions{ParseUnknown: true},
+		inputMessage: &pb2.Scalars{},
+		inputText:    `101: { opt_int32: 1 }`,
+		wantErr:      "invalid field in unknown group 101: opt_int32",
+	}, {
+		desc:         "ParseUnknown without option",
+		inputMessage: &pb2.Scalars{},
+		inputText:    "101: 1",
+		wantErr:      "unknown field: 101",
 	}, {
 		desc:         "invalid bool value",
 		inputMessage: &pb3.Scalars{},
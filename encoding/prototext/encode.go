@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"unicode/utf8"
 
+	"google.golang.org/protobuf/encoding/protoknown"
 	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/internal/encoding/messageset"
 	"google.golang.org/protobuf/internal/encoding/text"
@@ -61,6 +62,12 @@ type MarshalOptions struct {
 	// as opposed to using UTF-8 encoding when possible.
 	EmitASCII bool
 
+	// Stable specifies that the output is stable across builds of the
+	// program, such as for comparison with golden files: the marshaler
+	// does not insert the random whitespace that it otherwise does to
+	// discourage depending on the exact output.
+	Stable bool
+
 	// allowInvalidUTF8 specifies whether to permit the encoding of strings
 	// with invalid UTF-8. This is unexported as it is intended to only
 	// be specified by the Format method.
@@ -76,6 +83,20 @@ type MarshalOptions struct {
 	// The default is to exclude unknown fields.
 	EmitUnknown bool
 
+	// EmitParsableUnknown specifies whether to emit unknown fields in a form
+	// that UnmarshalOptions.ParseUnknown parses back into the same unknown
+	// fields. It implies EmitUnknown. Unlike with EmitUnknown alone,
+	// fixed32 and fixed64 values are written as hexadecimal numbers with
+	// 8 and 16 digits (e.g., 0x00000047), which distinguishes them from
+	// each other.
+	EmitParsableUnknown bool
+
+	// Behaviors specifies custom behaviors of message types, which are
+	// validated by the behavior and marshaled as strings produced by the
+	// behavior, except at the top level. If nil, no custom behaviors
+	// are applied.
+	Behaviors *protoknown.Behaviors
+
 	// Resolver is used for looking up types when expanding google.protobuf.Any
 	// messages. If nil, this defaults to using protoregistry.GlobalTypes.
 	Resolver interface {
@@ -101,9 +122,9 @@ func (o MarshalOptions) Format(m proto.Message) string {
 }
 
 // Marshal writes the given [proto.Message] in textproto format using options in
-// MarshalOptions object. Do not depend on the output being stable. Its output
-// will change across different builds of your program, even when using the
-// same version of the protobuf module.
+// MarshalOptions object. Unless Stable is set, do not depend on the output
+// being stable. Its output will change across different builds of your
+// program, even when using the same version of the protobuf module.
 func (o MarshalOptions) Marshal(m proto.Message) ([]byte, error) {
 	return o.marshal(nil, m)
 }
@@ -126,11 +147,17 @@ func (o MarshalOptions) marshal(b []byte, m proto.Message) ([]byte, error) {
 	if o.Resolver == nil {
 		o.Resolver = protoregistry.GlobalTypes
 	}
+	if o.EmitParsableUnknown {
+		o.EmitUnknown = true
+	}
 
 	internalEnc, err := text.NewEncoder(b, o.Indent, delims, o.EmitASCII)
 	if err != nil {
 		return nil, err
 	}
+	if o.Stable {
+		internalEnc.SetStable()
+	}
 
 	// Treat nil message interface as an empty message,
 	// in which case there is nothing to output.
@@ -165,6 +192,24 @@ func (e encoder) marshalMessage(m protoreflect.Message, inclDelims bool) error {
 		return errors.New("no support for proto1 MessageSets")
 	}
 
+	// Handle message types with custom behaviors, which are marshaled
+	// as strings except at the top level.
+	if b, ok := e.opts.Behaviors.Lookup(messageDesc.FullName()); ok {
+		if inclDelims {
+			s, err := b.Marshal(m)
+			if err != nil {
+				return behaviorError{errors.New("%v: %v", messageDesc.FullName(), err)}
+			}
+			e.WriteString(s)
+			return nil
+		}
+		if b.Validate != nil {
+			if err := b.Validate(m); err != nil {
+				return behaviorError{errors.New("%v: %v", messageDesc.FullName(), err)}
+			}
+		}
+	}
+
 	if inclDelims {
 		e.StartMessage()
 		defer e.EndMessage()
@@ -172,8 +217,8 @@ func (e encoder) marshalMessage(m protoreflect.Message, inclDelims bool) error {
 
 	// Handle Any expansion.
 	if messageDesc.FullName() == genid.Any_message_fullname {
-		if e.marshalAny(m) {
-			return nil
+		if ok, err := e.marshalAny(m); ok || err != nil {
+			return err
 		}
 		// If unable to expand, continue on to marshal Any as a regular message.
 	}
@@ -318,11 +363,19 @@ func (e encoder) marshalUnknown(b []byte) {
 		case protowire.Fixed32Type:
 			var v uint32
 			v, n = protowire.ConsumeFixed32(b)
-			e.WriteLiteral("0x" + strconv.FormatUint(uint64(v), hex))
+			if e.opts.EmitParsableUnknown {
+				e.WriteLiteral(fmt.Sprintf("0x%08x", v))
+			} else {
+				e.WriteLiteral("0x" + strconv.FormatUint(uint64(v), hex))
+			}
 		case protowire.Fixed64Type:
 			var v uint64
 			v, n = protowire.ConsumeFixed64(b)
-			e.WriteLiteral("0x" + strconv.FormatUint(v, hex))
+			if e.opts.EmitParsableUnknown {
+				e.WriteLiteral(fmt.Sprintf("0x%016x", v))
+			} else {
+				e.WriteLiteral("0x" + strconv.FormatUint(v, hex))
+			}
 		case protowire.BytesType:
 			var v []byte
 			v, n = protowire.ConsumeBytes(b)
@@ -341,16 +394,25 @@ func (e encoder) marshalUnknown(b []byte) {
 	}
 }
 
+// A behaviorError is an error reported by a custom behavior.
+type behaviorError struct{ err error }
+
+func (e behaviorError) Error() string { return e.err.Error() }
+func (e behaviorError) Unwrap() error { return e.err }
+
 // marshalAny marshals the given google.protobuf.Any message in expanded form.
-// It returns true if it was able to marshal, else false.
-func (e encoder) marshalAny(any protoreflect.Message) bool {
+// It returns true if it was able to marshal, else false. It reports the
+// errors of custom behaviors of the embedded message or the messages nested
+// within it, which must not be bypassed by marshaling the Any as a regular
+// message.
+func (e encoder) marshalAny(any protoreflect.Message) (bool, error) {
 	// Construct the embedded message.
 	fds := any.Descriptor().Fields()
 	fdType := fds.ByNumber(genid.Any_TypeUrl_field_number)
 	typeURL := any.Get(fdType).String()
 	mt, err := e.opts.Resolver.FindMessageByURL(typeURL)
 	if err != nil {
-		return false
+		return false, nil
 	}
 	m := mt.New().Interface()
 
@@ -362,7 +424,7 @@ func (e encoder) marshalAny(any protoreflect.Message) bool {
 		Resolver:     e.opts.Resolver,
 	}.Unmarshal(value.Bytes(), m)
 	if err != nil {
-		return false
+		return false, nil
 	}
 
 	// Get current encoder position. If marshaling fails, reset encoder output
@@ -372,9 +434,12 @@ func (e encoder) marshalAny(any protoreflect.Message) bool {
 	// Field name is the proto field name enclosed in [].
 	e.WriteName("[" + typeURL + "]")
 	err = e.marshalMessage(m.ProtoReflect(), true)
+	if _, ok := err.(behaviorError); ok {
+		return false, err
+	}
 	if err != nil {
 		e.Reset(pos)
-		return false
+		return false, nil
 	}
-	return true
+	return true, nil
 }
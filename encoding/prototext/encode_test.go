@@ -1201,6 +1201,22 @@ req_nested: {}
   101: 0
   102: "inside a group"
 }
+`,
+	}, {
+		desc: "EmitParsableUnknown",
+		mo:   prototext.MarshalOptions{EmitParsableUnknown: true},
+		input: func() proto.Message {
+			m := new(pb2.Scalars)
+			m.ProtoReflect().SetUnknown(protopack.Message{
+				protopack.Tag{Number: 101, Type: protopack.VarintType}, protopack.Varint(0x47),
+				protopack.Tag{Number: 102, Type: protopack.Fixed32Type}, protopack.Uint32(0x47),
+				protopack.Tag{Number: 103, Type: protopack.Fixed64Type}, protopack.Uint64(0x47),
+			}.Marshal())
+			return m
+		}(),
+		want: `101: 71
+102: 0x00000047
+103: 0x0000000000000047
 `,
 	}, {
 		desc: "unknown unpack repeated field",
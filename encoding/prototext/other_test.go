@@ -5,11 +5,13 @@
 package prototext_test
 
 import (
+	"bytes"
 	"testing"
 
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/testing/protopack"
 
 	pb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -236,3 +238,32 @@ func TestRoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestRoundTripUnknown(t *testing.T) {
+	m := &pb2.Scalars{OptString: proto.String("known")}
+	m.ProtoReflect().SetUnknown(protopack.Message{
+		protopack.Tag{Number: 101, Type: protopack.VarintType}, protopack.Varint(-1),
+		protopack.Tag{Number: 102, Type: protopack.Fixed32Type}, protopack.Uint32(0),
+		protopack.Tag{Number: 103, Type: protopack.Fixed64Type}, protopack.Uint64(1 << 40),
+		protopack.Tag{Number: 104, Type: protopack.BytesType}, protopack.Bytes("\x00\xff"),
+		protopack.Tag{Number: 105, Type: protopack.StartGroupType},
+		protopack.Tag{Number: 1, Type: protopack.Fixed32Type}, protopack.Uint32(1),
+		protopack.Tag{Number: 105, Type: protopack.EndGroupType},
+		protopack.Tag{Number: 101, Type: protopack.VarintType}, protopack.Varint(2),
+	}.Marshal())
+
+	b, err := prototext.MarshalOptions{EmitParsableUnknown: true}.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	got := new(pb2.Scalars)
+	if err := (prototext.UnmarshalOptions{ParseUnknown: true}).Unmarshal(b, got); err != nil {
+		t.Fatalf("Unmarshal() error: %v\n%s", err, b)
+	}
+	if !proto.Equal(got, m) {
+		t.Errorf("round trip mismatch:\ngot:  %v\nwant: %v", got, m)
+	}
+	if !bytes.Equal(got.ProtoReflect().GetUnknown(), m.ProtoReflect().GetUnknown()) {
+		t.Errorf("round trip unknown fields mismatch:\ngot:  %x\nwant: %x", got.ProtoReflect().GetUnknown(), m.ProtoReflect().GetUnknown())
+	}
+}
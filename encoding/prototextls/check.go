@@ -0,0 +1,255 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prototextls
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/internal/pragma"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Check reports the errors in the file with respect to the message
+// descriptor md, using the default options.
+func Check(f *File, md protoreflect.MessageDescriptor) []Diagnostic {
+	return Options{}.Check(f, md)
+}
+
+// Options configures the schema-aware analysis of a file.
+type Options struct {
+	pragma.NoUnkeyedLiterals
+
+	// Resolver is used for looking up extension fields and the types
+	// of expanded google.protobuf.Any messages.
+	// If nil, this defaults to using protoregistry.GlobalTypes.
+	Resolver interface {
+		protoregistry.MessageTypeResolver
+		protoregistry.ExtensionTypeResolver
+	}
+}
+
+// Check reports the errors in the file with respect to the message
+// descriptor md. Syntax errors, which are reported by [Parse],
+// are not included. Missing required fields are reported as warnings.
+func (o Options) Check(f *File, md protoreflect.MessageDescriptor) []Diagnostic {
+	c := &checker{o: o}
+	c.checkMessage(f.Root, md)
+	return c.diags
+}
+
+func (o Options) resolver() interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+} {
+	if o.Resolver == nil {
+		return protoregistry.GlobalTypes
+	}
+	return o.Resolver
+}
+
+// resolveField resolves the field of a message of type md.
+// For the type URL of an expanded google.protobuf.Any message,
+// it returns the descriptor of the expanded message instead.
+func (o Options) resolveField(md protoreflect.MessageDescriptor, f *Field) (protoreflect.FieldDescriptor, protoreflect.MessageDescriptor, error) {
+	switch {
+	case f.IsBracketed && md.FullName() == genid.Any_message_fullname && strings.Contains(f.Name, "/"):
+		mt, err := o.resolver().FindMessageByURL(f.Name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to resolve type %q", f.Name)
+		}
+		return nil, mt.Descriptor(), nil
+	case f.IsBracketed:
+		xt, err := o.resolver().FindExtensionByName(protoreflect.FullName(f.Name))
+		if err != nil {
+			return nil, nil, fmt.Errorf("unknown extension %q", f.Name)
+		}
+		xd := xt.TypeDescriptor()
+		if xd.ContainingMessage().FullName() != md.FullName() {
+			return nil, nil, fmt.Errorf("extension %q does not extend %v", f.Name, md.FullName())
+		}
+		return xd, nil, nil
+	}
+	var fd protoreflect.FieldDescriptor
+	if n, err := strconv.ParseInt(f.Name, 10, 32); err == nil {
+		fd = md.Fields().ByNumber(protoreflect.FieldNumber(n))
+	} else {
+		fd = md.Fields().ByTextName(f.Name)
+	}
+	if fd == nil {
+		return nil, nil, fmt.Errorf("unknown field %q in %v", f.Name, md.FullName())
+	}
+	return fd, nil, nil
+}
+
+type checker struct {
+	o     Options
+	diags []Diagnostic
+}
+
+func (c *checker) report(s Span, sev Severity, format string, args ...any) {
+	c.diags = append(c.diags, Diagnostic{Span: s, Severity: sev, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) checkMessage(m *Message, md protoreflect.MessageDescriptor) {
+	seen := make(map[protoreflect.FullName]bool)
+	oneofs := make(map[protoreflect.FullName]*Field)
+	for _, f := range m.Fields {
+		fd, expanded, err := c.o.resolveField(md, f)
+		if err != nil {
+			c.report(f.NameSpan, Error, "%v", err)
+			continue
+		}
+		if expanded != nil {
+			if len(m.Fields) > 1 {
+				c.report(f.NameSpan, Error, "expanded %v must be the only field of the message", md.FullName())
+			}
+			if f.Value != nil && f.Value.Kind != MessageValue {
+				c.report(f.Value.Span, Error, "expanded %v requires a message value", md.FullName())
+			} else if f.Value != nil {
+				c.checkMessage(f.Value.Message, expanded)
+			}
+			continue
+		}
+
+		if seen[fd.FullName()] && !fd.IsList() && !fd.IsMap() {
+			c.report(f.NameSpan, Error, "non-repeated field %q is repeated", f.Name)
+		}
+		seen[fd.FullName()] = true
+		if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+			if prev := oneofs[od.FullName()]; prev != nil && prev.Name != f.Name {
+				c.report(f.NameSpan, Error, "field %q is already set for oneof %q by field %q", f.Name, od.Name(), prev.Name)
+			}
+			oneofs[od.FullName()] = f
+		}
+		c.checkField(fd, f)
+	}
+
+	for i, fds := 0, md.Fields(); i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		if fd.Cardinality() == protoreflect.Required && !seen[fd.FullName()] {
+			s := Span{m.Start, m.Start}
+			if m.Start < m.End {
+				s.End = m.Start + 1
+			}
+			c.report(s, Warning, "required field %q is not set", fd.TextName())
+		}
+	}
+}
+
+func (c *checker) checkField(fd protoreflect.FieldDescriptor, f *Field) {
+	v := f.Value
+	switch {
+	case v == nil:
+		return // reported as a syntax error
+	case v.Kind == ListValue:
+		if !fd.IsList() && !fd.IsMap() {
+			c.report(v.Span, Error, "non-repeated field %q cannot have a list value", f.Name)
+			return
+		}
+		for _, e := range v.List {
+			c.checkValue(fd, f, e)
+		}
+	default:
+		c.checkValue(fd, f, v)
+	}
+}
+
+func (c *checker) checkValue(fd protoreflect.FieldDescriptor, f *Field, v *Value) {
+	if fd.Message() != nil {
+		if v.Kind != MessageValue {
+			c.report(v.Span, Error, "field %q requires a message value", f.Name)
+			return
+		}
+		c.checkMessage(v.Message, fd.Message())
+		return
+	}
+	if v.Kind != ScalarValue {
+		c.report(v.Span, Error, "field %q requires a %v value", f.Name, fd.Kind())
+		return
+	}
+	if v.Tokens[len(v.Tokens)-1].Kind == Invalid {
+		return // reported as a syntax error
+	}
+	if err := checkScalar(fd, v); err != nil {
+		c.report(v.Span, Error, "invalid value for field %q: %v", f.Name, err)
+	}
+}
+
+// checkScalar reports whether the scalar value is valid for the field.
+func checkScalar(fd protoreflect.FieldDescriptor, v *Value) error {
+	s := v.Text()
+	isString := v.Tokens[0].Kind == String
+	switch kind := fd.Kind(); kind {
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		if !isString {
+			return fmt.Errorf("%s is not a string", s)
+		}
+		return nil
+	case protoreflect.BoolKind:
+		switch s {
+		case "true", "True", "t", "false", "False", "f", "0", "1":
+			return nil
+		}
+		return fmt.Errorf("%s is not a bool", s)
+	case protoreflect.EnumKind:
+		if v.Tokens[0].Kind == Identifier {
+			if fd.Enum().Values().ByName(protoreflect.Name(s)) == nil {
+				return fmt.Errorf("%s is not a value of enum %v", s, fd.Enum().FullName())
+			}
+			return nil
+		}
+		if !isString && isInteger(s) {
+			if _, err := strconv.ParseInt(s, 0, 32); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s is not a value of enum %v", s, fd.Enum().FullName())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		switch strings.ToLower(strings.TrimPrefix(s, "-")) {
+		case "inf", "infinity", "nan":
+			return nil
+		}
+		if !isString && !strings.ContainsAny(s, "_xX") {
+			t := strings.TrimRight(s, "fF")
+			if _, err := strconv.ParseFloat(t, 64); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s is not a %v", s, kind)
+	default:
+		if !isString && isInteger(s) {
+			var err error
+			switch kind {
+			case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+				_, err = strconv.ParseInt(s, 0, 32)
+			case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+				_, err = strconv.ParseInt(s, 0, 64)
+			case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+				_, err = strconv.ParseUint(s, 0, 32)
+			case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+				_, err = strconv.ParseUint(s, 0, 64)
+			}
+			if err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s is not a %v", s, kind)
+	}
+}
+
+// isInteger reports whether s has the syntax of an integer in the text
+// format, which is a subset of the syntax accepted by strconv with base 0.
+func isInteger(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+		return s != "" && strings.Trim(s, "0123456789abcdefABCDEF") == ""
+	}
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
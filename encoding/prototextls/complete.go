@@ -0,0 +1,210 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prototextls
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CompletionKind is the kind of a [Completion].
+type CompletionKind int
+
+const (
+	FieldCompletion CompletionKind = iota + 1 // the name of a field
+	ValueCompletion                           // a value of a field, such as an enum name
+)
+
+// A Completion is a suggestion of text to insert at a position.
+type Completion struct {
+	Kind CompletionKind
+
+	// Label is the text to insert.
+	Label string
+
+	// Detail describes the suggestion,
+	// such as the type of a field or the enum of a value.
+	Detail string
+
+	// Replace is the part of the input that the label replaces,
+	// which is the partial name or value at the position.
+	Replace Span
+}
+
+// Complete suggests the field names or values that may be inserted
+// at the offset in the file, which is a message of type md,
+// using the default options.
+func Complete(f *File, md protoreflect.MessageDescriptor, offset int) []Completion {
+	return Options{}.Complete(f, md, offset)
+}
+
+// Complete suggests the field names or values that may be inserted
+// at the offset in the file, which is a message of type md.
+// Field names are suggested at the start of a field, excluding non-repeated
+// fields that are already set. Names of enum values and the literals
+// true and false are suggested for the values of enum and bool fields.
+func (o Options) Complete(f *File, md protoreflect.MessageDescriptor, offset int) []Completion {
+	m := f.Root
+	for {
+		next, nextMD := o.enclosingMessage(m, md, offset)
+		if next == nil {
+			break
+		}
+		if nextMD == nil {
+			return nil // within a message of an unknown type
+		}
+		m, md = next, nextMD
+	}
+
+	for i, fld := range m.Fields {
+		if fld.NameSpan.Contains(offset) && !fld.IsBracketed {
+			prefix := fld.Name[:offset-fld.NameSpan.Start]
+			return fieldCompletions(m, md, fld, prefix, fld.NameSpan)
+		}
+
+		// Determine the scalar value at the offset, if any.
+		v := fld.Value
+		switch {
+		case v == nil:
+			// A missing value extends up to the next field.
+			limit := m.End
+			if i+1 < len(m.Fields) {
+				limit = m.Fields[i+1].Start
+			}
+			if fld.Colon < 0 || offset <= fld.Colon || offset > limit {
+				continue
+			}
+		case v.Kind == ListValue:
+			if offset <= v.Start || !v.Span.Contains(offset) {
+				continue
+			}
+			v = nil
+			for _, e := range fld.Value.List {
+				if e.Kind == ScalarValue && e.Span.Contains(offset) {
+					v = e
+				}
+			}
+		case v.Kind != ScalarValue || !v.Span.Contains(offset):
+			continue
+		}
+
+		fd, _, err := o.resolveField(md, fld)
+		if err != nil || fd == nil {
+			return nil
+		}
+		if v == nil {
+			return valueCompletions(fd, "", Span{offset, offset})
+		}
+		return valueCompletions(fd, string(f.Source[v.Start:offset]), v.Span)
+	}
+	return fieldCompletions(m, md, nil, "", Span{offset, offset})
+}
+
+// enclosingMessage returns the message value within m that contains
+// the offset and the descriptor of its type, which is nil if the type
+// cannot be resolved. It returns a nil message if there is none.
+func (o Options) enclosingMessage(m *Message, md protoreflect.MessageDescriptor, offset int) (*Message, protoreflect.MessageDescriptor) {
+	for _, fld := range m.Fields {
+		if fld.Value == nil {
+			continue
+		}
+		values := []*Value{fld.Value}
+		if fld.Value.Kind == ListValue {
+			values = fld.Value.List
+		}
+		for _, v := range values {
+			if v.Kind != MessageValue || !v.Message.encloses(offset) {
+				continue
+			}
+			fd, expanded, err := o.resolveField(md, fld)
+			switch {
+			case err != nil:
+				return v.Message, nil
+			case expanded != nil:
+				return v.Message, expanded
+			default:
+				return v.Message, fd.Message()
+			}
+		}
+	}
+	return nil, nil
+}
+
+// encloses reports whether the offset is between the delimiters of m.
+func (m *Message) encloses(offset int) bool {
+	return m.Start < offset && (offset < m.End || (offset == m.End && !m.closed))
+}
+
+func fieldCompletions(m *Message, md protoreflect.MessageDescriptor, current *Field, prefix string, replace Span) []Completion {
+	set := make(map[protoreflect.FullName]bool)
+	for _, fld := range m.Fields {
+		if fld == current || fld.IsBracketed {
+			continue
+		}
+		if fd := md.Fields().ByTextName(fld.Name); fd != nil {
+			set[fd.FullName()] = true
+			if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+				set[od.FullName()] = true
+			}
+		}
+	}
+	var cs []Completion
+	for i, fds := 0, md.Fields(); i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		if !fd.IsList() && !fd.IsMap() && set[fd.FullName()] {
+			continue
+		}
+		if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() && set[od.FullName()] {
+			continue
+		}
+		if name := fd.TextName(); strings.HasPrefix(name, prefix) {
+			cs = append(cs, Completion{Kind: FieldCompletion, Label: name, Detail: fieldType(fd), Replace: replace})
+		}
+	}
+	return cs
+}
+
+func valueCompletions(fd protoreflect.FieldDescriptor, prefix string, replace Span) []Completion {
+	var cs []Completion
+	add := func(label, detail string) {
+		if strings.HasPrefix(label, prefix) {
+			cs = append(cs, Completion{Kind: ValueCompletion, Label: label, Detail: detail, Replace: replace})
+		}
+	}
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		ed := fd.Enum()
+		for i, vds := 0, ed.Values(); i < vds.Len(); i++ {
+			add(string(vds.Get(i).Name()), string(ed.FullName()))
+		}
+	case protoreflect.BoolKind:
+		add("true", "bool")
+		add("false", "bool")
+	}
+	return cs
+}
+
+// fieldType describes the type of the field,
+// as it is declared in a .proto file.
+func fieldType(fd protoreflect.FieldDescriptor) string {
+	if fd.IsMap() {
+		return fmt.Sprintf("map<%s, %s>", fieldType(fd.MapKey()), fieldType(fd.MapValue()))
+	}
+	var s string
+	switch {
+	case fd.Enum() != nil:
+		s = string(fd.Enum().FullName())
+	case fd.Message() != nil:
+		s = string(fd.Message().FullName())
+	default:
+		s = fd.Kind().String()
+	}
+	if fd.IsList() {
+		s = "repeated " + s
+	}
+	return s
+}
@@ -0,0 +1,341 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prototextls
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity is the severity of a [Diagnostic].
+type Severity int
+
+const (
+	Error Severity = iota + 1
+	Warning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	default:
+		return fmt.Sprintf("<unknown:%d>", int(s))
+	}
+}
+
+// A Diagnostic is a problem found in a part of the input.
+type Diagnostic struct {
+	Span
+	Severity Severity
+	Message  string
+}
+
+// A File is the syntax tree of a text format file.
+type File struct {
+	Source []byte
+
+	// Tokens are all tokens of the file, including comments.
+	Tokens []Token
+
+	// Root is the message formed by the top-level fields of the file.
+	Root *Message
+
+	// Diagnostics are the syntax errors of the file.
+	Diagnostics []Diagnostic
+}
+
+// A Message is a list of fields, either at the top level of a file
+// or enclosed in braces or angle brackets.
+type Message struct {
+	// Span is the extent of the message, including its delimiters.
+	// If the closing delimiter is missing, the message extends to
+	// the end of the file.
+	Span
+	Fields []*Field
+
+	closed bool // whether the closing delimiter is present
+}
+
+// A Field is a field name and its value.
+type Field struct {
+	Span
+
+	// Name is the name of the field. For names enclosed in brackets,
+	// which are the names of extension fields or the type URLs of
+	// expanded google.protobuf.Any messages, it is the text between the
+	// brackets with whitespace removed, and IsBracketed is set.
+	Name        string
+	NameSpan    Span
+	IsBracketed bool
+
+	// Colon is the offset of the colon after the name, or -1 if there is none.
+	Colon int
+
+	// Value is the value of the field, or nil if it is missing.
+	Value *Value
+}
+
+// ValueKind is the kind of a [Value].
+type ValueKind int
+
+const (
+	ScalarValue  ValueKind = iota + 1 // a literal, such as a number or a string
+	MessageValue                      // a message enclosed in delimiters
+	ListValue                         // a list of values enclosed in brackets
+)
+
+// A Value is the value of a field or an element of a list.
+type Value struct {
+	Span
+	Kind ValueKind
+
+	// Tokens are the tokens of a scalar value. These are a single number or
+	// identifier, optionally preceded by a minus sign, or one or more
+	// adjacent strings, which are concatenated.
+	Tokens []Token
+
+	// Message is the message of a message value.
+	Message *Message
+
+	// List are the elements of a list value.
+	List []*Value
+}
+
+// Text returns the text of a scalar value with any space
+// between a minus sign and the number removed.
+func (v *Value) Text() string {
+	if len(v.Tokens) > 1 && v.Tokens[0].Text == "-" {
+		return "-" + v.Tokens[1].Text
+	}
+	var ss []string
+	for _, tok := range v.Tokens {
+		ss = append(ss, tok.Text)
+	}
+	return strings.Join(ss, "")
+}
+
+// Parse parses the text format input into a syntax tree.
+// Syntax errors are reported in the Diagnostics of the file,
+// and the remainder of the input is parsed after each of them.
+func Parse(b []byte) *File {
+	f := &File{Source: b, Tokens: Tokenize(b)}
+	p := &parser{f: f, end: len(b)}
+	for _, tok := range f.Tokens {
+		switch {
+		case tok.Kind == Invalid && (tok.Text[0] == '"' || tok.Text[0] == '\''):
+			p.errorf(tok.Span, "unterminated string")
+		case tok.Kind == Invalid:
+			p.errorf(tok.Span, "invalid character %q", tok.Text)
+		}
+		if tok.Kind != Comment {
+			p.toks = append(p.toks, tok)
+		}
+	}
+	f.Root = p.parseMessage(0, "")
+	f.Root.Span = Span{0, len(b)}
+	sort.SliceStable(f.Diagnostics, func(i, j int) bool {
+		return f.Diagnostics[i].Start < f.Diagnostics[j].Start
+	})
+	return f
+}
+
+type parser struct {
+	f    *File
+	toks []Token
+	end  int
+}
+
+func (p *parser) peek() (Token, bool) {
+	if len(p.toks) == 0 {
+		return Token{Span: Span{p.end, p.end}}, false
+	}
+	return p.toks[0], true
+}
+
+func (p *parser) next() Token {
+	tok := p.toks[0]
+	p.toks = p.toks[1:]
+	return tok
+}
+
+// isPunct reports whether the next token is the punctuation s.
+func (p *parser) isPunct(s string) bool {
+	tok, ok := p.peek()
+	return ok && tok.Kind == Punctuation && tok.Text == s
+}
+
+func (p *parser) errorf(s Span, format string, args ...any) {
+	p.f.Diagnostics = append(p.f.Diagnostics, Diagnostic{Span: s, Severity: Error, Message: fmt.Sprintf(format, args...)})
+}
+
+// parseMessage parses fields up to the closing delimiter,
+// which is empty for the top level of the file.
+func (p *parser) parseMessage(start int, closing string) *Message {
+	m := &Message{Span: Span{start, p.end}}
+	for {
+		tok, ok := p.peek()
+		switch {
+		case !ok:
+			if closing != "" {
+				p.errorf(Span{p.end, p.end}, "missing %q", closing)
+			}
+			return m
+		case tok.Kind == Punctuation && (tok.Text == "}" || tok.Text == ">"):
+			if closing == "" {
+				p.errorf(tok.Span, "unexpected %q", tok.Text)
+				p.next()
+				continue
+			}
+			if tok.Text != closing {
+				p.errorf(tok.Span, "mismatched %q, want %q", tok.Text, closing)
+			}
+			p.next()
+			m.End = tok.End
+			m.closed = true
+			return m
+		}
+		if fd := p.parseField(); fd != nil {
+			m.Fields = append(m.Fields, fd)
+		}
+	}
+}
+
+// parseField parses a field and its trailing separator,
+// or skips a token that cannot start a field.
+func (p *parser) parseField() *Field {
+	tok := p.next()
+	fd := &Field{Span: tok.Span, Name: tok.Text, NameSpan: tok.Span, Colon: -1}
+	switch {
+	case tok.Kind == Identifier || tok.Kind == Number:
+	case tok.Kind == Punctuation && tok.Text == "[":
+		fd.IsBracketed = true
+		fd.Name = ""
+		for {
+			t, ok := p.peek()
+			if !ok || t.Kind == Punctuation && (t.Text == ":" || t.Text == "{" || t.Text == "<" || t.Text == "}" || t.Text == ">") {
+				p.errorf(t.Span, "missing %q", "]")
+				break
+			}
+			p.next()
+			fd.NameSpan.End = t.End
+			if t.Kind == Punctuation && t.Text == "]" {
+				break
+			}
+			fd.Name += t.Text
+		}
+	default:
+		if tok.Kind != Invalid {
+			p.errorf(tok.Span, "unexpected %s %q, want a field name", tok.Kind, tok.Text)
+		}
+		return nil
+	}
+	fd.End = fd.NameSpan.End
+
+	if p.isPunct(":") {
+		colon := p.next()
+		fd.Colon = colon.Start
+		fd.End = colon.End
+	}
+	tok, ok := p.peek()
+	switch {
+	case ok && tok.Kind == Punctuation && (tok.Text == "{" || tok.Text == "<" || tok.Text == "["):
+		fd.Value = p.parseValue()
+	case ok && (tok.Kind == Identifier || tok.Kind == Number || tok.Kind == String || tok.Kind == Invalid || tok.Kind == Punctuation && tok.Text == "-"):
+		if fd.Colon < 0 {
+			p.errorf(tok.Span, "missing %q after field name %q", ":", fd.Name)
+		}
+		fd.Value = p.parseValue()
+	default:
+		p.errorf(Span{fd.End, fd.End}, "missing value for field %q", fd.Name)
+	}
+	if fd.Value != nil {
+		fd.End = fd.Value.End
+	}
+	if p.isPunct(",") || p.isPunct(";") {
+		p.next()
+	}
+	return fd
+}
+
+// parseValue parses a value, which starts with the next token.
+func (p *parser) parseValue() *Value {
+	tok := p.next()
+	v := &Value{Span: tok.Span}
+	switch {
+	case tok.Kind == Punctuation && tok.Text == "{":
+		v.Kind = MessageValue
+		v.Message = p.parseMessage(tok.Start, "}")
+	case tok.Kind == Punctuation && tok.Text == "<":
+		v.Kind = MessageValue
+		v.Message = p.parseMessage(tok.Start, ">")
+	case tok.Kind == Punctuation && tok.Text == "[":
+		v.Kind = ListValue
+		v.End = p.parseList(v)
+		return v
+	case tok.Kind == Punctuation && tok.Text == "-":
+		v.Kind = ScalarValue
+		v.Tokens = []Token{tok}
+		if t, ok := p.peek(); ok && (t.Kind == Number || t.Kind == Identifier) {
+			v.Tokens = append(v.Tokens, p.next())
+			v.End = t.End
+		} else {
+			p.errorf(t.Span, "missing number after %q", "-")
+		}
+		return v
+	case tok.Kind == String:
+		v.Kind = ScalarValue
+		v.Tokens = []Token{tok}
+		for {
+			t, ok := p.peek()
+			if !ok || t.Kind != String {
+				break
+			}
+			v.Tokens = append(v.Tokens, p.next())
+			v.End = t.End
+		}
+		return v
+	default:
+		v.Kind = ScalarValue
+		v.Tokens = []Token{tok}
+		return v
+	}
+	v.End = v.Message.End
+	return v
+}
+
+// parseList parses the elements of a list up to the closing bracket,
+// returning the end of the list.
+func (p *parser) parseList(v *Value) int {
+	for {
+		tok, ok := p.peek()
+		switch {
+		case !ok:
+			p.errorf(tok.Span, "missing %q", "]")
+			return p.end
+		case tok.Kind == Punctuation && tok.Text == "]":
+			p.next()
+			return tok.End
+		case tok.Kind == Punctuation && (tok.Text == "{" || tok.Text == "<" || tok.Text == "-") ||
+			tok.Kind == Identifier || tok.Kind == Number || tok.Kind == String || tok.Kind == Invalid:
+			v.List = append(v.List, p.parseValue())
+		default:
+			p.errorf(tok.Span, "unexpected %s %q in list", tok.Kind, tok.Text)
+			p.next()
+			if tok.Kind == Punctuation && (tok.Text == "}" || tok.Text == ">") {
+				return tok.End
+			}
+			continue
+		}
+		if p.isPunct(",") {
+			p.next()
+		} else if t, ok := p.peek(); ok && !p.isPunct("]") {
+			p.errorf(t.Span, "missing %q between list elements", ",")
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prototextls_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/prototextls"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestTokenize(t *testing.T) {
+	in := `a: -1.5e-3 # comment
+b: "x\"y" c <0x1F> [d.e] 'bad`
+	var got []string
+	for _, tok := range prototextls.Tokenize([]byte(in)) {
+		got = append(got, fmt.Sprintf("%v:%s", tok.Kind, tok.Text))
+		if in[tok.Start:tok.End] != tok.Text {
+			t.Errorf("token %q has span %v, which is %q", tok.Text, tok.Span, in[tok.Start:tok.End])
+		}
+	}
+	want := []string{
+		"identifier:a", "punctuation::", "punctuation:-", "number:1.5e-3", "comment:# comment",
+		"identifier:b", "punctuation::", `string:"x\"y"`, "identifier:c",
+		"punctuation:<", "number:0x1F", "punctuation:>",
+		"punctuation:[", "identifier:d", "punctuation:.", "identifier:e", "punctuation:]",
+		"invalid:'bad",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Tokenize() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParse(t *testing.T) {
+	in := `a: 1, b { c: "x" "y" } d: [1, -2] [e.f]: <g: h>`
+	f := prototextls.Parse([]byte(in))
+	if len(f.Diagnostics) > 0 {
+		t.Fatalf("Parse() reported %v", f.Diagnostics)
+	}
+	var names []string
+	for _, fd := range f.Root.Fields {
+		names = append(names, fd.Name)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "d", "e.f"}, names); diff != "" {
+		t.Errorf("field names mismatch (-want +got):\n%s", diff)
+	}
+	if got := f.Root.Fields[1].Value.Message.Fields[0].Value.Text(); got != `"x""y"` {
+		t.Errorf("concatenated string = %s", got)
+	}
+	if got := f.Root.Fields[2].Value.List[1].Text(); got != "-2" {
+		t.Errorf("negative list element = %s", got)
+	}
+	if !f.Root.Fields[3].IsBracketed || f.Root.Fields[3].Value.Kind != prototextls.MessageValue {
+		t.Errorf("extension field = %+v", f.Root.Fields[3])
+	}
+
+	for _, tt := range []struct {
+		in   string
+		want []string
+	}{
+		{`a 1`, []string{`missing ":" after field name "a"`}},
+		{`a:`, []string{`missing value for field "a"`}},
+		{`a { b: 1`, []string{`missing "}"`}},
+		{`a { b: 1 > c: 2`, []string{`mismatched ">", want "}"`}},
+		{`} a: 1`, []string{`unexpected "}"`}},
+		{`a: [1 2]`, []string{`missing "," between list elements`}},
+		{`a: "x`, []string{"unterminated string"}},
+		{`: a: 1 @`, []string{`unexpected punctuation ":", want a field name`, `invalid character "@"`}},
+	} {
+		f := prototextls.Parse([]byte(tt.in))
+		var got []string
+		for _, d := range f.Diagnostics {
+			got = append(got, d.Message)
+		}
+		// Parsing continues after each error.
+		if diff := cmp.Diff(tt.want, got, cmp.Comparer(strings.HasPrefix)); diff != "" {
+			t.Errorf("Parse(%q) diagnostics mismatch (-want +got):\n%s", tt.in, diff)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	md := (*testpb.TestAllTypes)(nil).ProtoReflect().Descriptor()
+	for _, tt := range []struct {
+		in   string
+		want []string
+	}{{
+		in: `optional_int32: -5 optional_uint64: 0x10 optional_float: 1.5f optional_double: -inf
+			optional_bool: t optional_string: "s" optional_nested_enum: BAR
+			repeated_int32: [1, 2] repeated_int32: 3
+			optional_nested_message { a: 1 corecursive {} }
+			map_string_nested_message { key: "k" value { a: 2 } }
+			OptionalGroup { a: 1 }
+			oneof_uint32: 7`,
+	}, {
+		in:   `unknown: 1 optional_int32: 1 optional_int32: 2`,
+		want: []string{`unknown field "unknown" in goproto.proto.test.TestAllTypes`, `non-repeated field "optional_int32" is repeated`},
+	}, {
+		in: `optional_int32: 3000000000 optional_uint32: -1 optional_int64: 1.5 optional_bool: yes
+			optional_string: 1 optional_nested_enum: QUX optional_double: 0x1`,
+		want: []string{
+			`invalid value for field "optional_int32": 3000000000 is not a int32`,
+			`invalid value for field "optional_uint32": -1 is not a uint32`,
+			`invalid value for field "optional_int64": 1.5 is not a int64`,
+			`invalid value for field "optional_bool": yes is not a bool`,
+			`invalid value for field "optional_string": 1 is not a string`,
+			`invalid value for field "optional_nested_enum": QUX is not a value of enum goproto.proto.test.TestAllTypes.NestedEnum`,
+			`invalid value for field "optional_double": 0x1 is not a double`,
+		},
+	}, {
+		in: `optional_int32: [1] optional_nested_message: 1 optional_string { }
+			oneof_uint32: 1 oneof_string: "x" optional_nested_message { a: "x" }`,
+		want: []string{
+			`non-repeated field "optional_int32" cannot have a list value`,
+			`field "optional_nested_message" requires a message value`,
+			`field "optional_string" requires a string value`,
+			`field "oneof_string" is already set for oneof "oneof_field" by field "oneof_uint32"`,
+			`non-repeated field "optional_nested_message" is repeated`,
+			`invalid value for field "a": "x" is not a int32`,
+		},
+	}} {
+		f := prototextls.Parse([]byte(tt.in))
+		if len(f.Diagnostics) > 0 {
+			t.Fatalf("Parse(%q) reported %v", tt.in, f.Diagnostics)
+		}
+		var got []string
+		for _, d := range prototextls.Check(f, md) {
+			got = append(got, d.Message)
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Check(%q) mismatch (-want +got):\n%s", tt.in, diff)
+		}
+	}
+}
+
+func TestCheckRequired(t *testing.T) {
+	f := prototextls.Parse([]byte(``))
+	got := prototextls.Check(f, (*testpb.TestRequired)(nil).ProtoReflect().Descriptor())
+	if len(got) != 1 || got[0].Severity != prototextls.Warning || got[0].Message != `required field "required_field" is not set` {
+		t.Errorf("Check() = %v, want a warning for the missing required field", got)
+	}
+}
+
+func TestCheckAny(t *testing.T) {
+	md := (*anypb.Any)(nil).ProtoReflect().Descriptor()
+	f := prototextls.Parse([]byte(`[type.googleapis.com/goproto.proto.test.TestAllTypes] { optional_int32: "x" }`))
+	got := prototextls.Check(f, md)
+	if len(got) != 1 || got[0].Message != `invalid value for field "optional_int32": "x" is not a int32` {
+		t.Errorf("Check() = %v, want an error within the expanded message", got)
+	}
+	f = prototextls.Parse([]byte(`[type.googleapis.com/unknown.Message] {}`))
+	got = prototextls.Check(f, md)
+	if len(got) != 1 || got[0].Message != `unable to resolve type "type.googleapis.com/unknown.Message"` {
+		t.Errorf("Check() = %v, want an error for the unknown type", got)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	md := (*testpb.TestAllTypes)(nil).ProtoReflect().Descriptor()
+	for _, tt := range []struct {
+		in   string // the position to complete is marked by "|"
+		want []string
+	}{
+		{`optional_nested_m|`, []string{"optional_nested_message"}},
+		{`optional_int32: 1 optional_in|`, []string{"optional_int64"}},
+		{`repeated_int32: 1 repeated_in|`, []string{"repeated_int32", "repeated_int64"}},
+		{`oneof_uint32: 1 oneof_str|`, nil},
+		{`optional_nested_enum: |`, []string{"FOO", "BAR", "BAZ", "NEG"}},
+		{`optional_nested_enum: B|`, []string{"BAR", "BAZ"}},
+		{`repeated_nested_enum: [FOO, |]`, []string{"FOO", "BAR", "BAZ", "NEG"}},
+		{`optional_bool: |`, []string{"true", "false"}},
+		{`optional_nested_message { |`, []string{"a", "corecursive"}},
+		{`optional_nested_message { a: 1 | }`, []string{"corecursive"}},
+		{`optional_nested_message { corecursive { optional_nested_enum: N| } }`, []string{"NEG"}},
+		{`unknown { | }`, nil},
+	} {
+		offset := strings.Index(tt.in, "|")
+		in := tt.in[:offset] + tt.in[offset+1:]
+		var got []string
+		for _, c := range prototextls.Complete(prototextls.Parse([]byte(in)), md, offset) {
+			got = append(got, c.Label)
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Complete(%q) mismatch (-want +got):\n%s", tt.in, diff)
+		}
+	}
+}
+
+func TestPosition(t *testing.T) {
+	in := []byte("a: 1\nbé: 2")
+	if line, col := prototextls.Position(in, strings.LastIndex(string(in), ":")); line != 2 || col != 3 {
+		t.Errorf("Position() = %d:%d, want 2:3", line, col)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package prototextls provides support for editor tooling, such as language
+// servers, for files in the protocol buffer text format.
+//
+// Unlike the prototext package, which stops at the first error,
+// this package tolerates malformed and incomplete input so that it can be
+// used while a file is being edited. [Tokenize] splits the input into
+// tokens for syntax highlighting, [Parse] produces a syntax tree along with
+// syntax errors, [Check] reports errors with respect to the schema of
+// a message, and [Complete] suggests field names and values at a position.
+//
+// All positions are byte offsets into the input. Use [Position] to convert
+// an offset to a line and column number.
+package prototextls
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// A Span is the range of byte offsets [Start, End) of a part of the input.
+type Span struct {
+	Start, End int
+}
+
+// Contains reports whether the offset is within s.
+// The end of the span is considered to be within it, so that a span
+// contains the position of a cursor placed right after it.
+func (s Span) Contains(offset int) bool {
+	return s.Start <= offset && offset <= s.End
+}
+
+// TokenKind is the kind of a [Token].
+type TokenKind int
+
+const (
+	Invalid     TokenKind = iota // a character or string that cannot be tokenized
+	Comment                      // a comment starting with '#'
+	Identifier                   // a field name or an identifier value, such as an enum name
+	Number                       // an unsigned number literal
+	String                       // a quoted string literal
+	Punctuation                  // a single punctuation character, such as '{' or ':'
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case Invalid:
+		return "invalid"
+	case Comment:
+		return "comment"
+	case Identifier:
+		return "identifier"
+	case Number:
+		return "number"
+	case String:
+		return "string"
+	case Punctuation:
+		return "punctuation"
+	default:
+		return fmt.Sprintf("<unknown:%d>", int(k))
+	}
+}
+
+// A Token is a lexical element of the input.
+type Token struct {
+	Kind TokenKind
+	Span
+	Text string // the raw text of the token in the input
+}
+
+// Tokenize splits the input into tokens.
+// Whitespace is omitted, while comments are included.
+// Tokenize never fails: input that cannot be tokenized is reported
+// as tokens of the [Invalid] kind.
+func Tokenize(b []byte) []Token {
+	var toks []Token
+	for i := 0; i < len(b); {
+		c := b[i]
+		start := i
+		kind := Invalid
+		switch {
+		case c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\v' || c == '\f':
+			i++
+			continue
+		case c == '#':
+			kind = Comment
+			for i < len(b) && b[i] != '\n' {
+				i++
+			}
+		case isIdentStart(c):
+			kind = Identifier
+			for i < len(b) && isIdentChar(b[i]) {
+				i++
+			}
+		case isDigit(c) || (c == '.' && i+1 < len(b) && isDigit(b[i+1])):
+			kind = Number
+			i = scanNumber(b, i)
+		case c == '"' || c == '\'':
+			kind, i = scanString(b, i)
+		case bytes.IndexByte([]byte(":,;{}<>[]/.-"), c) >= 0:
+			kind = Punctuation
+			i++
+		default:
+			_, n := utf8.DecodeRune(b[i:])
+			i += n
+		}
+		toks = append(toks, Token{Kind: kind, Span: Span{start, i}, Text: string(b[start:i])})
+	}
+	return toks
+}
+
+// scanNumber returns the end of the number literal starting at b[i].
+func scanNumber(b []byte, i int) int {
+	hex := len(b) > i+1 && b[i] == '0' && (b[i+1] == 'x' || b[i+1] == 'X')
+	for i < len(b) {
+		c := b[i]
+		switch {
+		case isIdentChar(c) || c == '.':
+		case (c == '+' || c == '-') && !hex && (b[i-1] == 'e' || b[i-1] == 'E'):
+		default:
+			return i
+		}
+		i++
+	}
+	return i
+}
+
+// scanString returns the end of the string literal starting at b[i],
+// which is [Invalid] if it is not terminated on the same line.
+func scanString(b []byte, i int) (TokenKind, int) {
+	quote := b[i]
+	for i++; i < len(b); i++ {
+		switch b[i] {
+		case quote:
+			return String, i + 1
+		case '\n':
+			return Invalid, i
+		case '\\':
+			if i+1 < len(b) && b[i+1] != '\n' {
+				i++
+			}
+		}
+	}
+	return Invalid, i
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// Position returns the 1-based line and column number of the offset in b,
+// where the column is counted in runes.
+func Position(b []byte, offset int) (line, column int) {
+	b = b[:offset]
+	line = bytes.Count(b, []byte("\n")) + 1
+	if i := bytes.LastIndexByte(b, '\n'); i >= 0 {
+		b = b[i+1:]
+	}
+	return line, utf8.RuneCount(b) + 1
+}
@@ -0,0 +1,215 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protounknown inspects and manipulates the unknown fields of messages.
+//
+// Unknown fields are fields encountered while unmarshaling that do not
+// correspond to any field known to the message type. They are retained as
+// raw wire-format bytes (see [protoreflect.Message.GetUnknown]), which
+// this package parses into structured records and serializes back.
+//
+// The [Delete] and [Retain] functions operate on a message's unknown fields
+// directly and leave the encoding of the remaining fields untouched,
+// which makes them suitable for proxies that must strip particular fields
+// before forwarding a message.
+package protounknown
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Field is a single unknown field.
+type Field struct {
+	Number protowire.Number
+	Type   protowire.Type
+
+	// Value is the value of a field of type VarintType, Fixed32Type,
+	// or Fixed64Type. Varints are stored as-is, without zig-zag decoding.
+	Value uint64
+
+	// Bytes is the value of a field of type BytesType.
+	Bytes []byte
+
+	// Group is the contents of a field of type StartGroupType.
+	Group Fields
+}
+
+// Fields is a list of unknown fields in the order they appear on the wire.
+type Fields []Field
+
+// Parse parses the wire-format encoding of a sequence of fields.
+// The returned Fields may alias b.
+func Parse(b []byte) (Fields, error) {
+	var fs Fields
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+		f := Field{Number: num, Type: typ}
+		switch typ {
+		case protowire.VarintType:
+			f.Value, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(b)
+			f.Value = uint64(v)
+		case protowire.Fixed64Type:
+			f.Value, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			f.Bytes, n = protowire.ConsumeBytes(b)
+		case protowire.StartGroupType:
+			var v []byte
+			v, n = protowire.ConsumeGroup(num, b)
+			if n >= 0 {
+				group, err := Parse(v)
+				if err != nil {
+					return nil, err
+				}
+				f.Group = group
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+// Get parses the unknown fields of m.
+func Get(m proto.Message) (Fields, error) {
+	return Parse(m.ProtoReflect().GetUnknown())
+}
+
+// Set replaces the unknown fields of m with fs.
+func Set(m proto.Message, fs Fields) {
+	m.ProtoReflect().SetUnknown(fs.Marshal())
+}
+
+// Marshal returns the wire-format encoding of fs.
+//
+// Varints are encoded in their shortest form, so the result may differ
+// from the bytes fs was parsed from if those used a longer encoding.
+func (fs Fields) Marshal() protoreflect.RawFields {
+	return fs.appendTo(nil)
+}
+
+func (fs Fields) appendTo(b []byte) []byte {
+	for _, f := range fs {
+		b = protowire.AppendTag(b, f.Number, f.Type)
+		switch f.Type {
+		case protowire.VarintType:
+			b = protowire.AppendVarint(b, f.Value)
+		case protowire.Fixed32Type:
+			b = protowire.AppendFixed32(b, uint32(f.Value))
+		case protowire.Fixed64Type:
+			b = protowire.AppendFixed64(b, f.Value)
+		case protowire.BytesType:
+			b = protowire.AppendBytes(b, f.Bytes)
+		case protowire.StartGroupType:
+			b = f.Group.appendTo(b)
+			b = protowire.AppendTag(b, f.Number, protowire.EndGroupType)
+		}
+	}
+	return b
+}
+
+// Find returns the fields in fs with the given field number.
+func (fs Fields) Find(num protowire.Number) Fields {
+	var out Fields
+	for _, f := range fs {
+		if f.Number == num {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Delete returns the fields in fs whose field number is not in nums.
+func (fs Fields) Delete(nums ...protowire.Number) Fields {
+	set := numberSet(nums)
+	return fs.filter(func(num protowire.Number) bool { return !set[num] })
+}
+
+// Retain returns the fields in fs whose field number is in nums.
+func (fs Fields) Retain(nums ...protowire.Number) Fields {
+	set := numberSet(nums)
+	return fs.filter(func(num protowire.Number) bool { return set[num] })
+}
+
+func (fs Fields) filter(keep func(protowire.Number) bool) Fields {
+	var out Fields
+	for _, f := range fs {
+		if keep(f.Number) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Delete removes the unknown fields of m whose field number is in nums.
+// The encoding of the remaining unknown fields is preserved as is.
+// It reports an error if the unknown fields of m are malformed,
+// in which case m is left unmodified.
+//
+// Only the unknown fields of m itself are affected;
+// those of any submessages of m are not.
+func Delete(m proto.Message, nums ...protowire.Number) error {
+	set := numberSet(nums)
+	return filterRaw(m, func(num protowire.Number) bool { return !set[num] })
+}
+
+// Retain removes the unknown fields of m whose field number is not in nums.
+// The encoding of the remaining unknown fields is preserved as is.
+// It reports an error if the unknown fields of m are malformed,
+// in which case m is left unmodified.
+//
+// Only the unknown fields of m itself are affected;
+// those of any submessages of m are not.
+func Retain(m proto.Message, nums ...protowire.Number) error {
+	set := numberSet(nums)
+	return filterRaw(m, func(num protowire.Number) bool { return set[num] })
+}
+
+func filterRaw(m proto.Message, keep func(protowire.Number) bool) error {
+	mr := m.ProtoReflect()
+	b := mr.GetUnknown()
+	var out protoreflect.RawFields
+	changed := false
+	for i := 0; i < len(b); {
+		num, typ, n := protowire.ConsumeTag(b[i:])
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		v := protowire.ConsumeFieldValue(num, typ, b[i+n:])
+		if v < 0 {
+			return protowire.ParseError(v)
+		}
+		if keep(num) {
+			out = append(out, b[i:i+n+v]...)
+		} else {
+			changed = true
+		}
+		i += n + v
+	}
+	if changed {
+		mr.SetUnknown(out)
+	}
+	return nil
+}
+
+func numberSet(nums []protowire.Number) map[protowire.Number]bool {
+	set := make(map[protowire.Number]bool, len(nums))
+	for _, num := range nums {
+		set[num] = true
+	}
+	return set
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protounknown_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/protounknown"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/testing/protopack"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+var testWire = protopack.Message{
+	protopack.Tag{1001, protopack.VarintType}, protopack.Varint(-1),
+	protopack.Tag{1002, protopack.Fixed32Type}, protopack.Uint32(32),
+	protopack.Tag{1003, protopack.Fixed64Type}, protopack.Uint64(64),
+	protopack.Tag{1004, protopack.BytesType}, protopack.String("hello"),
+	protopack.Tag{1005, protopack.StartGroupType},
+	protopack.Tag{1, protopack.VarintType}, protopack.Varint(1),
+	protopack.Tag{1005, protopack.EndGroupType},
+	protopack.Tag{1001, protopack.VarintType}, protopack.Varint(2),
+}.Marshal()
+
+func TestParse(t *testing.T) {
+	got, err := protounknown.Parse(testWire)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := protounknown.Fields{
+		{Number: 1001, Type: protowire.VarintType, Value: 1<<64 - 1},
+		{Number: 1002, Type: protowire.Fixed32Type, Value: 32},
+		{Number: 1003, Type: protowire.Fixed64Type, Value: 64},
+		{Number: 1004, Type: protowire.BytesType, Bytes: []byte("hello")},
+		{Number: 1005, Type: protowire.StartGroupType, Group: protounknown.Fields{
+			{Number: 1, Type: protowire.VarintType, Value: 1},
+		}},
+		{Number: 1001, Type: protowire.VarintType, Value: 2},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+	}
+	if b := got.Marshal(); !bytes.Equal(b, testWire) {
+		t.Errorf("Marshal() = %x, want %x", b, testWire)
+	}
+
+	if diff := cmp.Diff(want[2:3], got.Find(1003)); diff != "" {
+		t.Errorf("Find(1003) mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(append(want[1:2:2], want[3:5]...), got.Delete(1001, 1003)); diff != "" {
+		t.Errorf("Delete(1001, 1003) mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(protounknown.Fields{want[0], want[5]}, got.Retain(1001)); diff != "" {
+		t.Errorf("Retain(1001) mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, b := range [][]byte{
+		protopack.Message{protopack.Tag{1, protopack.BytesType}}.Marshal(),
+		protopack.Message{protopack.Tag{1, protopack.StartGroupType}}.Marshal(),
+		protopack.Message{protopack.Tag{1, protopack.EndGroupType}}.Marshal(),
+		protopack.Message{protopack.Tag{1, protopack.StartGroupType},
+			protopack.Tag{2, protopack.BytesType},
+			protopack.Tag{1, protopack.EndGroupType}}.Marshal(),
+		{0x80},
+	} {
+		if _, err := protounknown.Parse(b); err == nil {
+			t.Errorf("Parse(%x) succeeded, want error", b)
+		}
+		m := &testpb.TestAllTypes{}
+		m.ProtoReflect().SetUnknown(b)
+		if err := protounknown.Delete(m, 1); err == nil {
+			t.Errorf("Delete with unknown fields %x succeeded, want error", b)
+		}
+		if got := m.ProtoReflect().GetUnknown(); !bytes.Equal(got, b) {
+			t.Errorf("failed Delete modified unknown fields to %x, want %x", got, b)
+		}
+	}
+}
+
+func TestDeleteRetain(t *testing.T) {
+	// A non-minimal varint encoding of 2 must be preserved by Delete.
+	overlong := protopack.Message{
+		protopack.Tag{1001, protopack.VarintType}, protopack.Denormalized{Count: 2, Value: protopack.Uvarint(2)},
+	}.Marshal()
+	m := &testpb.TestAllTypes{}
+	m.ProtoReflect().SetUnknown(append(append([]byte(nil), testWire...), overlong...))
+
+	if err := protounknown.Delete(m, 1002, 1003, 1004); err != nil {
+		t.Fatal(err)
+	}
+	want := append(protopack.Message{
+		protopack.Tag{1001, protopack.VarintType}, protopack.Varint(-1),
+		protopack.Tag{1005, protopack.StartGroupType},
+		protopack.Tag{1, protopack.VarintType}, protopack.Varint(1),
+		protopack.Tag{1005, protopack.EndGroupType},
+		protopack.Tag{1001, protopack.VarintType}, protopack.Varint(2),
+	}.Marshal(), overlong...)
+	if got := m.ProtoReflect().GetUnknown(); !bytes.Equal(got, want) {
+		t.Errorf("after Delete, unknown fields = %x, want %x", got, want)
+	}
+
+	if err := protounknown.Retain(m, 1005); err != nil {
+		t.Fatal(err)
+	}
+	fs, err := protounknown.Get(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 1 || fs[0].Number != 1005 {
+		t.Errorf("after Retain(1005), unknown fields = %v, want only field 1005", fs)
+	}
+
+	protounknown.Set(m, fs.Delete(1005))
+	if got := m.ProtoReflect().GetUnknown(); len(got) != 0 {
+		t.Errorf("after Set, unknown fields = %x, want none", got)
+	}
+}
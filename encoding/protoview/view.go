@@ -0,0 +1,539 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoview provides read-only views of messages that read fields
+// directly from their wire-format encoding.
+//
+// A [ViewMessage] implements [protoreflect.Message] on top of an encoded
+// message without unmarshaling it into a Go struct. The encoding is
+// scanned once when a field is first accessed, and the value of a field is
+// decoded each time it is requested. This suits read-mostly workloads that
+// inspect a few fields of large messages.
+//
+// This package is EXPERIMENTAL and its API may change.
+package protoview
+
+import (
+	"math"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// A ViewMessage is a read-only view of an encoded message.
+//
+// ViewMessage implements the [google.golang.org/protobuf/proto.Message] and
+// [protoreflect.Message] interfaces, and methods that modify the message
+// panic. Its Type and New methods refer to the [dynamicpb] message type of
+// the descriptor, so functions such as proto.Clone produce a mutable
+// [dynamicpb.Message]. Marshaling a ViewMessage produces the encoded message.
+//
+// The values of bytes fields alias the encoded message, which must not be
+// modified while the view is in use. The values of string fields are not
+// validated as UTF-8. Extension fields are not resolved and are reported as
+// unknown fields.
+//
+// A ViewMessage is safe for concurrent use.
+type ViewMessage struct {
+	desc protoreflect.MessageDescriptor
+	b    []byte // nil for an invalid message
+
+	once    sync.Once
+	records map[protowire.Number][]record
+	unknown protoreflect.RawFields
+}
+
+// A record is a single field of the encoded message.
+type record struct {
+	pos int            // offset of the field in the encoded message
+	typ protowire.Type // wire type of the field
+	val []byte         // encoded value, excluding any length prefix or end group tag
+}
+
+var (
+	_ protoreflect.Message      = (*ViewMessage)(nil)
+	_ protoreflect.ProtoMessage = (*ViewMessage)(nil)
+)
+
+// New returns a view of the message of type md encoded in b.
+// It reports an error if b or the encoding of any message it contains
+// is not a well-formed sequence of fields, but the values of other fields
+// are not validated.
+func New(md protoreflect.MessageDescriptor, b []byte) (*ViewMessage, error) {
+	if err := validate(md, b, 0, protowire.DefaultRecursionLimit); err != nil {
+		return nil, err
+	}
+	return newView(md, b), nil
+}
+
+// validate checks that the message of type md encoded in b, which is
+// located at offset base of the input, and the messages it contains
+// are well-formed sequences of fields.
+func validate(md protoreflect.MessageDescriptor, b []byte, base, depth int) error {
+	if depth < 0 {
+		return errors.New("%v: exceeded maximum recursion depth", md.FullName())
+	}
+	fds := md.Fields()
+	for pos := 0; pos < len(b); {
+		num, typ, n := protowire.ConsumeField(b[pos:])
+		if n < 0 {
+			return errors.New("%v: invalid wire data at offset %d: %v", md.FullName(), base+pos, protowire.ParseError(n))
+		}
+		if fd := fds.ByNumber(num); fd != nil && fd.Message() != nil && acceptsType(fd, typ) {
+			_, _, tagLen := protowire.ConsumeTag(b[pos:])
+			off := pos + tagLen
+			var val []byte
+			switch typ {
+			case protowire.BytesType:
+				val, _ = protowire.ConsumeBytes(b[off:])
+				off += protowire.SizeVarint(uint64(len(val)))
+			case protowire.StartGroupType:
+				val, _ = protowire.ConsumeGroup(num, b[off:])
+			}
+			if err := validate(fd.Message(), val, base+off, depth-1); err != nil {
+				return err
+			}
+		}
+		pos += n
+	}
+	return nil
+}
+
+// newView returns a view of b, which is known to be well-formed.
+func newView(md protoreflect.MessageDescriptor, b []byte) *ViewMessage {
+	if b == nil {
+		b = []byte{}
+	}
+	return &ViewMessage{desc: md, b: b}
+}
+
+// index scans the encoded message for the records of each known field.
+func (m *ViewMessage) index() {
+	m.once.Do(func() {
+		m.records = make(map[protowire.Number][]record)
+		fds := m.desc.Fields()
+		for pos := 0; pos < len(m.b); {
+			b := m.b[pos:]
+			num, typ, tagLen := protowire.ConsumeTag(b)
+			valLen := -1
+			if tagLen > 0 {
+				valLen = protowire.ConsumeFieldValue(num, typ, b[tagLen:])
+			}
+			if valLen < 0 {
+				// New validates the encoding, so this only happens
+				// if the encoded message is modified.
+				m.unknown = append(m.unknown, b...)
+				break
+			}
+			field, val := b[:tagLen+valLen], b[tagLen:tagLen+valLen]
+			switch typ {
+			case protowire.BytesType:
+				val, _ = protowire.ConsumeBytes(val)
+			case protowire.StartGroupType:
+				val, _ = protowire.ConsumeGroup(num, val)
+			}
+			if fd := fds.ByNumber(num); fd != nil && acceptsType(fd, typ) {
+				m.records[num] = append(m.records[num], record{pos, typ, val[:len(val):len(val)]})
+			} else {
+				m.unknown = append(m.unknown, field...)
+			}
+			pos += len(field)
+		}
+	})
+}
+
+// recordsOf returns the records of the field. For a member of a oneof,
+// only the records after the last record of another member are returned,
+// since setting a member clears the others.
+func (m *ViewMessage) recordsOf(fd protoreflect.FieldDescriptor) []record {
+	if fd.IsExtension() {
+		return nil
+	}
+	m.index()
+	rs := m.records[fd.Number()]
+	od := fd.ContainingOneof()
+	if od == nil || od.IsSynthetic() || len(rs) == 0 {
+		return rs
+	}
+	last := -1
+	for i, fds := 0, od.Fields(); i < fds.Len(); i++ {
+		if other := fds.Get(i); other != fd {
+			if ors := m.records[other.Number()]; len(ors) > 0 && ors[len(ors)-1].pos > last {
+				last = ors[len(ors)-1].pos
+			}
+		}
+	}
+	for len(rs) > 0 && rs[0].pos < last {
+		rs = rs[1:]
+	}
+	return rs
+}
+
+// ProtoReflect returns m.
+func (m *ViewMessage) ProtoReflect() protoreflect.Message { return m }
+
+// Interface returns m.
+func (m *ViewMessage) Interface() protoreflect.ProtoMessage { return m }
+
+// Descriptor returns the message descriptor.
+func (m *ViewMessage) Descriptor() protoreflect.MessageDescriptor { return m.desc }
+
+// Type returns the dynamic message type of the message descriptor.
+func (m *ViewMessage) Type() protoreflect.MessageType { return dynamicpb.NewMessageType(m.desc) }
+
+// New returns a newly allocated, empty, mutable dynamic message.
+func (m *ViewMessage) New() protoreflect.Message { return dynamicpb.NewMessage(m.desc) }
+
+// IsValid reports whether the message is valid.
+// The message of an unpopulated message field is invalid.
+func (m *ViewMessage) IsValid() bool { return m.b != nil }
+
+// Range visits every populated field in undefined order.
+func (m *ViewMessage) Range(f func(protoreflect.FieldDescriptor, protoreflect.Value) bool) {
+	for i, fds := 0, m.desc.Fields(); i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		if m.Has(fd) && !f(fd, m.Get(fd)) {
+			return
+		}
+	}
+}
+
+// Has reports whether a field is populated.
+func (m *ViewMessage) Has(fd protoreflect.FieldDescriptor) bool {
+	m.checkField(fd)
+	rs := m.recordsOf(fd)
+	switch {
+	case len(rs) == 0:
+		return false
+	case fd.IsList():
+		return m.Get(fd).List().Len() > 0
+	case fd.IsMap() || fd.HasPresence():
+		return true
+	default:
+		return !isZero(m.Get(fd))
+	}
+}
+
+// Get retrieves the value for a field. For an unpopulated field,
+// it returns the default value for scalars and an empty, read-only value
+// for composite types.
+func (m *ViewMessage) Get(fd protoreflect.FieldDescriptor) protoreflect.Value {
+	m.checkField(fd)
+	rs := m.recordsOf(fd)
+	switch {
+	case fd.IsMap():
+		return protoreflect.ValueOfMap(newMap(fd, rs))
+	case fd.IsList():
+		return protoreflect.ValueOfList(newList(fd, rs))
+	case fd.Message() != nil:
+		switch len(rs) {
+		case 0:
+			return protoreflect.ValueOfMessage(&ViewMessage{desc: fd.Message()})
+		case 1:
+			return protoreflect.ValueOfMessage(newView(fd.Message(), rs[0].val))
+		}
+		// The records of a message field are merged,
+		// which is equivalent to concatenating their encodings.
+		var b []byte
+		for _, r := range rs {
+			b = append(b, r.val...)
+		}
+		return protoreflect.ValueOfMessage(newView(fd.Message(), b))
+	case len(rs) == 0:
+		return fd.Default()
+	default:
+		// The last record of a scalar field takes precedence.
+		return singular(fd, rs[len(rs)-1])
+	}
+}
+
+// WhichOneof reports which field within the oneof is populated,
+// returning nil if none are populated.
+func (m *ViewMessage) WhichOneof(od protoreflect.OneofDescriptor) protoreflect.FieldDescriptor {
+	for i, fds := 0, od.Fields(); i < fds.Len(); i++ {
+		if fd := fds.Get(i); m.Has(fd) {
+			return fd
+		}
+	}
+	return nil
+}
+
+// GetUnknown returns the fields that are not known fields of the message,
+// including extension fields.
+func (m *ViewMessage) GetUnknown() protoreflect.RawFields {
+	m.index()
+	return m.unknown
+}
+
+// Clear panics, since the message is read-only.
+func (m *ViewMessage) Clear(fd protoreflect.FieldDescriptor) { m.readOnly() }
+
+// Set panics, since the message is read-only.
+func (m *ViewMessage) Set(fd protoreflect.FieldDescriptor, v protoreflect.Value) { m.readOnly() }
+
+// Mutable panics, since the message is read-only.
+func (m *ViewMessage) Mutable(fd protoreflect.FieldDescriptor) protoreflect.Value {
+	m.readOnly()
+	return protoreflect.Value{}
+}
+
+// NewField returns a new value for the field, as for a dynamic message.
+func (m *ViewMessage) NewField(fd protoreflect.FieldDescriptor) protoreflect.Value {
+	return dynamicpb.NewMessage(m.desc).NewField(fd)
+}
+
+// SetUnknown panics, since the message is read-only.
+func (m *ViewMessage) SetUnknown(protoreflect.RawFields) { m.readOnly() }
+
+// ProtoMethods is an internal detail of the [protoreflect.Message] interface.
+// Users should never call this directly.
+func (m *ViewMessage) ProtoMethods() *protoiface.Methods {
+	return &viewMethods
+}
+
+var viewMethods = protoiface.Methods{
+	Size: func(in protoiface.SizeInput) protoiface.SizeOutput {
+		return protoiface.SizeOutput{Size: len(in.Message.(*ViewMessage).b)}
+	},
+	Marshal: func(in protoiface.MarshalInput) (protoiface.MarshalOutput, error) {
+		return protoiface.MarshalOutput{Buf: append(in.Buf, in.Message.(*ViewMessage).b...)}, nil
+	},
+}
+
+func (m *ViewMessage) readOnly() {
+	panic(errors.New("%v: modification of read-only message view", m.desc.FullName()))
+}
+
+func (m *ViewMessage) checkField(fd protoreflect.FieldDescriptor) {
+	if fd.IsExtension() && fd.ContainingMessage().FullName() == m.desc.FullName() {
+		return
+	}
+	fields := m.desc.Fields()
+	index := fd.Index()
+	if fd.IsExtension() || index >= fields.Len() || fields.Get(index) != fd {
+		panic(errors.New("%v: field descriptor does not belong to this message", fd.FullName()))
+	}
+}
+
+// A viewList is a read-only list of the decoded elements of a repeated field.
+type viewList struct {
+	fd   protoreflect.FieldDescriptor
+	vals []protoreflect.Value
+}
+
+func newList(fd protoreflect.FieldDescriptor, rs []record) *viewList {
+	l := &viewList{fd: fd}
+	for _, r := range rs {
+		switch {
+		case r.typ != protowire.BytesType || fd.Kind() == protoreflect.StringKind ||
+			fd.Kind() == protoreflect.BytesKind || fd.Message() != nil:
+			l.vals = append(l.vals, singular(fd, r))
+		default:
+			// The elements of a packed field.
+			for b := r.val; len(b) > 0; {
+				v, n := number(fd.Kind(), b)
+				if n < 0 {
+					break
+				}
+				l.vals = append(l.vals, v)
+				b = b[n:]
+			}
+		}
+	}
+	return l
+}
+
+func (l *viewList) Len() int                     { return len(l.vals) }
+func (l *viewList) Get(i int) protoreflect.Value { return l.vals[i] }
+func (l *viewList) Set(int, protoreflect.Value)  { l.readOnly() }
+func (l *viewList) Append(protoreflect.Value)    { l.readOnly() }
+func (l *viewList) Truncate(int)                 { l.readOnly() }
+func (l *viewList) IsValid() bool                { return true }
+func (l *viewList) NewElement() protoreflect.Value {
+	return dynamicpb.NewMessage(l.fd.ContainingMessage()).NewField(l.fd).List().NewElement()
+}
+func (l *viewList) AppendMutable() protoreflect.Value {
+	l.readOnly()
+	return protoreflect.Value{}
+}
+
+func (l *viewList) readOnly() {
+	panic(errors.New("%v: modification of read-only list", l.fd.FullName()))
+}
+
+// A viewMap is a read-only map of the decoded entries of a map field.
+type viewMap struct {
+	fd   protoreflect.FieldDescriptor
+	keys []protoreflect.MapKey
+	vals map[any]protoreflect.Value
+}
+
+func newMap(fd protoreflect.FieldDescriptor, rs []record) *viewMap {
+	mp := &viewMap{fd: fd, vals: make(map[any]protoreflect.Value)}
+	for _, r := range rs {
+		entry := newView(fd.Message(), r.val)
+		k := entry.Get(fd.MapKey()).MapKey()
+		if _, ok := mp.vals[k.Interface()]; !ok {
+			mp.keys = append(mp.keys, k)
+		}
+		// The last entry with a key takes precedence.
+		mp.vals[k.Interface()] = entry.Get(fd.MapValue())
+	}
+	return mp
+}
+
+func (mp *viewMap) Len() int { return len(mp.keys) }
+func (mp *viewMap) Range(f func(protoreflect.MapKey, protoreflect.Value) bool) {
+	for _, k := range mp.keys {
+		if !f(k, mp.vals[k.Interface()]) {
+			return
+		}
+	}
+}
+func (mp *viewMap) Has(k protoreflect.MapKey) bool {
+	_, ok := mp.vals[k.Interface()]
+	return ok
+}
+func (mp *viewMap) Get(k protoreflect.MapKey) protoreflect.Value { return mp.vals[k.Interface()] }
+func (mp *viewMap) Clear(protoreflect.MapKey)                    { mp.readOnly() }
+func (mp *viewMap) Set(protoreflect.MapKey, protoreflect.Value)  { mp.readOnly() }
+func (mp *viewMap) IsValid() bool                                { return true }
+func (mp *viewMap) NewValue() protoreflect.Value {
+	return dynamicpb.NewMessage(mp.fd.ContainingMessage()).NewField(mp.fd).Map().NewValue()
+}
+func (mp *viewMap) Mutable(protoreflect.MapKey) protoreflect.Value {
+	mp.readOnly()
+	return protoreflect.Value{}
+}
+
+func (mp *viewMap) readOnly() {
+	panic(errors.New("%v: modification of read-only map", mp.fd.FullName()))
+}
+
+// singular decodes the value of a single record of the field.
+func singular(fd protoreflect.FieldDescriptor, r record) protoreflect.Value {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(string(r.val))
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(r.val)
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return protoreflect.ValueOfMessage(newView(fd.Message(), r.val))
+	}
+	v, _ := number(fd.Kind(), r.val)
+	return v
+}
+
+// number decodes a value of a numeric kind from the start of b,
+// returning the value and the number of bytes consumed,
+// which is negative if b is malformed.
+func number(k protoreflect.Kind, b []byte) (protoreflect.Value, int) {
+	switch wireType(k) {
+	case protowire.VarintType:
+		x, n := protowire.ConsumeVarint(b)
+		switch k {
+		case protoreflect.BoolKind:
+			return protoreflect.ValueOfBool(protowire.DecodeBool(x)), n
+		case protoreflect.EnumKind:
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(int32(x))), n
+		case protoreflect.Int32Kind:
+			return protoreflect.ValueOfInt32(int32(x)), n
+		case protoreflect.Sint32Kind:
+			return protoreflect.ValueOfInt32(int32(protowire.DecodeZigZag(x & math.MaxUint32))), n
+		case protoreflect.Uint32Kind:
+			return protoreflect.ValueOfUint32(uint32(x)), n
+		case protoreflect.Int64Kind:
+			return protoreflect.ValueOfInt64(int64(x)), n
+		case protoreflect.Sint64Kind:
+			return protoreflect.ValueOfInt64(protowire.DecodeZigZag(x)), n
+		default:
+			return protoreflect.ValueOfUint64(x), n
+		}
+	case protowire.Fixed32Type:
+		x, n := protowire.ConsumeFixed32(b)
+		switch k {
+		case protoreflect.Sfixed32Kind:
+			return protoreflect.ValueOfInt32(int32(x)), n
+		case protoreflect.FloatKind:
+			return protoreflect.ValueOfFloat32(math.Float32frombits(x)), n
+		default:
+			return protoreflect.ValueOfUint32(x), n
+		}
+	case protowire.Fixed64Type:
+		x, n := protowire.ConsumeFixed64(b)
+		switch k {
+		case protoreflect.Sfixed64Kind:
+			return protoreflect.ValueOfInt64(int64(x)), n
+		case protoreflect.DoubleKind:
+			return protoreflect.ValueOfFloat64(math.Float64frombits(x)), n
+		default:
+			return protoreflect.ValueOfUint64(x), n
+		}
+	}
+	return protoreflect.Value{}, -1
+}
+
+// wireType returns the wire type of a single value of kind k.
+func wireType(k protoreflect.Kind) protowire.Type {
+	switch k {
+	case protoreflect.BoolKind, protoreflect.EnumKind,
+		protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Uint32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Uint64Kind:
+		return protowire.VarintType
+	case protoreflect.Sfixed32Kind, protoreflect.Fixed32Kind, protoreflect.FloatKind:
+		return protowire.Fixed32Type
+	case protoreflect.Sfixed64Kind, protoreflect.Fixed64Kind, protoreflect.DoubleKind:
+		return protowire.Fixed64Type
+	case protoreflect.GroupKind:
+		return protowire.StartGroupType
+	default:
+		return protowire.BytesType
+	}
+}
+
+// acceptsType reports whether a record of the field may have wire type typ.
+// Records with other wire types are unknown fields.
+func acceptsType(fd protoreflect.FieldDescriptor, typ protowire.Type) bool {
+	if fd.IsMap() {
+		return typ == protowire.BytesType
+	}
+	want := wireType(fd.Kind())
+	if typ == want {
+		return true
+	}
+	// Repeated fields of numeric kinds may be packed.
+	return fd.IsList() && typ == protowire.BytesType && want != protowire.StartGroupType
+}
+
+// isZero reports whether v is the zero value of a scalar field
+// with implicit presence.
+func isZero(v protoreflect.Value) bool {
+	switch x := v.Interface().(type) {
+	case bool:
+		return !x
+	case int32:
+		return x == 0
+	case int64:
+		return x == 0
+	case uint32:
+		return x == 0
+	case uint64:
+		return x == 0
+	case float32:
+		return math.Float32bits(x) == 0
+	case float64:
+		return math.Float64bits(x) == 0
+	case string:
+		return x == ""
+	case []byte:
+		return len(x) == 0
+	case protoreflect.EnumNumber:
+		return x == 0
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoview_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protoview"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protopack"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+	test3pb "google.golang.org/protobuf/internal/testprotos/test3"
+)
+
+func TestView(t *testing.T) {
+	for _, want := range []proto.Message{
+		&testpb.TestAllTypes{
+			OptionalInt32:         proto.Int32(-1),
+			OptionalSint64:        proto.Int64(-2),
+			OptionalFixed32:       proto.Uint32(3),
+			OptionalDouble:        proto.Float64(4.5),
+			OptionalBool:          proto.Bool(true),
+			OptionalString:        proto.String("string"),
+			OptionalBytes:         []byte("bytes"),
+			OptionalNestedEnum:    testpb.TestAllTypes_BAR.Enum(),
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(5)},
+			Optionalgroup:         &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(6)},
+			RepeatedSint32:        []int32{-7, 8},
+			RepeatedString:        []string{"a", "b"},
+			RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{{A: proto.Int32(9)}, {}},
+			MapInt32Int32:         map[int32]int32{10: 11, 12: 13},
+			MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"x": {A: proto.Int32(14)},
+			},
+			OneofField: &testpb.TestAllTypes_OneofString{OneofString: "oneof"},
+		},
+		&test3pb.TestAllTypes{
+			SingularInt32:  1,
+			SingularString: "",
+			RepeatedFloat:  []float32{1.5, -2.5},
+			RepeatedUint64: []uint64{1 << 60, 0},
+		},
+	} {
+		b, err := proto.Marshal(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := protoview.New(want.ProtoReflect().Descriptor(), b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(got, want) {
+			t.Errorf("view of %T is not equal to the message", want)
+		}
+		if clone := proto.Clone(got); !proto.Equal(clone, want) {
+			t.Errorf("clone of the view of %T is not equal to the message", want)
+		}
+		if b2, err := proto.Marshal(got); err != nil || string(b2) != string(b) {
+			t.Errorf("Marshal(view) = %x, %v; want %x", b2, err, b)
+		}
+	}
+}
+
+func TestViewMerge(t *testing.T) {
+	// Scalars take the last value, messages are merged, oneofs take the
+	// last member, and unknown fields are retained.
+	b := protopack.Message{
+		protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+		protopack.Tag{Number: 18, Type: protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(2),
+		},
+		protopack.Tag{Number: 111, Type: protopack.VarintType}, protopack.Uvarint(3),
+		protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(4),
+		protopack.Tag{Number: 18, Type: protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Tag{Number: 2, Type: protopack.BytesType}, protopack.LengthPrefix{},
+		},
+		protopack.Tag{Number: 113, Type: protopack.BytesType}, protopack.String("x"),
+		protopack.Tag{Number: 31, Type: protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Varint(5), protopack.Varint(6),
+		},
+		protopack.Tag{Number: 31, Type: protopack.VarintType}, protopack.Varint(7),
+		protopack.Tag{Number: 1, Type: protopack.Fixed32Type}, protopack.Int32(8),
+		protopack.Tag{Number: 50000, Type: protopack.VarintType}, protopack.Varint(9),
+	}.Marshal()
+	want := &testpb.TestAllTypes{}
+	if err := proto.Unmarshal(b, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := protoview.New(want.ProtoReflect().Descriptor(), b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("view is not equal to the unmarshaled message %v", want)
+	}
+	wantUnknown := protopack.Message{
+		protopack.Tag{Number: 1, Type: protopack.Fixed32Type}, protopack.Int32(8),
+		protopack.Tag{Number: 50000, Type: protopack.VarintType}, protopack.Varint(9),
+	}.Marshal()
+	if got := got.GetUnknown(); string(got) != string(wantUnknown) {
+		t.Errorf("GetUnknown() = %x, want %x", got, wantUnknown)
+	}
+}
+
+func TestViewErrors(t *testing.T) {
+	md := (*testpb.TestAllTypes)(nil).ProtoReflect().Descriptor()
+	for _, b := range [][]byte{
+		protowire.AppendTag(nil, 1, protowire.BytesType),
+		// A nested message containing a truncated field.
+		[]byte("\x92\x01\x010"),
+		protopack.Message{
+			protopack.Tag{Number: 18, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Tag{Number: 2, Type: protopack.BytesType}, protopack.LengthPrefix{
+					protopack.Tag{Number: 1, Type: protopack.BytesType},
+				},
+			},
+		}.Marshal(),
+		protopack.Message{
+			protopack.Tag{Number: 48, Type: protopack.BytesType}, protopack.LengthPrefix{},
+			protopack.Tag{Number: 48, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Tag{Number: 2, Type: protopack.BytesType}, protopack.LengthPrefix{
+					protopack.Tag{Number: 16, Type: protopack.StartGroupType},
+					protopack.Tag{Number: 17, Type: protopack.VarintType},
+				},
+			},
+		}.Marshal(),
+		protopack.Message{
+			protopack.Tag{Number: 71, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Tag{Number: 1, Type: protopack.BytesType}, protopack.String("k"),
+				protopack.Tag{Number: 2, Type: protopack.BytesType}, protopack.Bytes{0x08},
+			},
+		}.Marshal(),
+	} {
+		if m, err := protoview.New(md, b); err == nil {
+			t.Errorf("New(%x) succeeded, want error", b)
+			// Accessing the fields of the view must not panic.
+			m.Range(func(protoreflect.FieldDescriptor, protoreflect.Value) bool { return true })
+		}
+	}
+	m, err := protoview.New(md, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd := md.Fields().ByName("optional_nested_message")
+	if v := m.Get(fd).Message(); v.IsValid() {
+		t.Errorf("Get(%v) of empty view is valid, want invalid", fd.Name())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Set() did not panic")
+		}
+	}()
+	m.Set(md.Fields().ByName("optional_int32"), m.Get(md.Fields().ByName("optional_int32")))
+}
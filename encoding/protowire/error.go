@@ -0,0 +1,279 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protowire
+
+import (
+	"io"
+
+	"google.golang.org/protobuf/internal/errors"
+)
+
+// ErrorKind classifies the reason that a wire encoding is malformed.
+type ErrorKind int8
+
+const (
+	_ ErrorKind = iota
+
+	// ErrorTruncated reports that the input ended before the element
+	// was complete.
+	ErrorTruncated
+	// ErrorOverflow reports that a varint is longer than 10 bytes
+	// or overflows a uint64.
+	ErrorOverflow
+	// ErrorFieldNumber reports that a tag has a field number outside the
+	// range of [MinValidNumber] to [MaxValidNumber].
+	ErrorFieldNumber
+	// ErrorReservedNumber reports that a tag has a field number in the range
+	// of [FirstReservedNumber] to [LastReservedNumber], which are reserved
+	// for the protobuf implementation and cannot be declared by any message.
+	ErrorReservedNumber
+	// ErrorWireType reports that a tag has a reserved wire type.
+	ErrorWireType
+	// ErrorEndGroup reports an end group marker that does not match
+	// the start of a group.
+	ErrorEndGroup
+	// ErrorRecursionDepth reports that groups are nested more deeply than
+	// [DefaultRecursionLimit].
+	ErrorRecursionDepth
+)
+
+// String returns a description of the kind of error.
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorTruncated:
+		return "unexpected EOF"
+	case ErrorOverflow:
+		return "variable length integer overflow"
+	case ErrorFieldNumber:
+		return "invalid field number"
+	case ErrorReservedNumber:
+		return "reserved field number"
+	case ErrorWireType:
+		return "cannot parse reserved wire type"
+	case ErrorEndGroup:
+		return "mismatching end group marker"
+	case ErrorRecursionDepth:
+		return "exceeded maximum recursion depth"
+	default:
+		return "parse error"
+	}
+}
+
+var (
+	errReservedNumber = errors.New("reserved field number")
+	errRecursionDepth = errors.New("exceeded maximum recursion depth")
+)
+
+// Error describes a malformed wire encoding.
+// It is returned by the Consume functions with a "Checked" suffix.
+//
+// Use [errors.Is] with [io.ErrUnexpectedEOF] to detect truncated input.
+type Error struct {
+	Kind ErrorKind
+
+	// Offset is the position within the input of the start of the malformed
+	// element, which is either a tag, or a field value whose tag has already
+	// been parsed.
+	Offset int
+
+	// Number and Type are the field number and wire type from the tag of
+	// the malformed element. For errors in a field value, they are those
+	// of the field being parsed. They are zero if the tag is unknown.
+	Number Number
+	Type   Type
+}
+
+func (e *Error) Error() string {
+	if e.Number != 0 {
+		return errors.New("%v at offset %d (field %d, wire type %d)", e.Kind, e.Offset, e.Number, e.Type).Error()
+	}
+	return errors.New("%v at offset %d", e.Kind, e.Offset).Error()
+}
+
+// Unwrap returns the error that [ParseError] reports for the same kind of
+// malformed input, if any.
+func (e *Error) Unwrap() error {
+	switch e.Kind {
+	case ErrorTruncated:
+		return io.ErrUnexpectedEOF
+	case ErrorOverflow:
+		return errOverflow
+	case ErrorFieldNumber:
+		return errFieldNumber
+	case ErrorReservedNumber:
+		return errReservedNumber
+	case ErrorWireType:
+		return errReserved
+	case ErrorEndGroup:
+		return errEndGroup
+	case ErrorRecursionDepth:
+		return errRecursionDepth
+	default:
+		return errParse
+	}
+}
+
+// codeError converts an error code into an *Error.
+func codeError(code, off int, num Number, typ Type) *Error {
+	e := &Error{Offset: off, Number: num, Type: typ}
+	switch code {
+	case errCodeTruncated:
+		e.Kind = ErrorTruncated
+	case errCodeFieldNumber:
+		e.Kind = ErrorFieldNumber
+	case errCodeOverflow:
+		e.Kind = ErrorOverflow
+	case errCodeReserved:
+		e.Kind = ErrorWireType
+	case errCodeEndGroup:
+		e.Kind = ErrorEndGroup
+	case errCodeRecursionDepth:
+		e.Kind = ErrorRecursionDepth
+	}
+	return e
+}
+
+// ConsumeTagChecked parses b as a varint-encoded tag, reporting its length.
+// It is like [ConsumeTag], but reports an [*Error] upon failure.
+//
+// Unlike [ConsumeTag], it also rejects tags with a field number above
+// [MaxValidNumber] or within the reserved range, and tags with a reserved
+// wire type, since such tags never occur in a valid message.
+func ConsumeTagChecked(b []byte) (Number, Type, int, error) {
+	return consumeTagChecked(b, 0)
+}
+
+func consumeTagChecked(b []byte, off int) (Number, Type, int, error) {
+	v, n := ConsumeVarint(b)
+	if n < 0 {
+		return 0, 0, 0, codeError(n, off, 0, 0)
+	}
+	num, typ := DecodeTag(v)
+	var kind ErrorKind
+	switch {
+	case num < MinValidNumber || num > MaxValidNumber:
+		kind = ErrorFieldNumber
+	case FirstReservedNumber <= num && num <= LastReservedNumber:
+		kind = ErrorReservedNumber
+	case typ > Fixed32Type:
+		kind = ErrorWireType
+	default:
+		return num, typ, n, nil
+	}
+	return 0, 0, 0, &Error{Kind: kind, Offset: off, Number: num, Type: typ}
+}
+
+// ConsumeVarintChecked parses b as a varint-encoded uint64, reporting its length.
+// It is like [ConsumeVarint], but reports an [*Error] upon failure.
+func ConsumeVarintChecked(b []byte) (v uint64, n int, err error) {
+	v, n = ConsumeVarint(b)
+	if n < 0 {
+		return 0, 0, codeError(n, 0, 0, 0)
+	}
+	return v, n, nil
+}
+
+// ConsumeFixed32Checked parses b as a little-endian uint32, reporting its length.
+// It is like [ConsumeFixed32], but reports an [*Error] upon failure.
+func ConsumeFixed32Checked(b []byte) (v uint32, n int, err error) {
+	v, n = ConsumeFixed32(b)
+	if n < 0 {
+		return 0, 0, codeError(n, 0, 0, 0)
+	}
+	return v, n, nil
+}
+
+// ConsumeFixed64Checked parses b as a little-endian uint64, reporting its length.
+// It is like [ConsumeFixed64], but reports an [*Error] upon failure.
+func ConsumeFixed64Checked(b []byte) (v uint64, n int, err error) {
+	v, n = ConsumeFixed64(b)
+	if n < 0 {
+		return 0, 0, codeError(n, 0, 0, 0)
+	}
+	return v, n, nil
+}
+
+// ConsumeBytesChecked parses b as a length-prefixed bytes value, reporting its length.
+// It is like [ConsumeBytes], but reports an [*Error] upon failure.
+func ConsumeBytesChecked(b []byte) (v []byte, n int, err error) {
+	v, n = ConsumeBytes(b)
+	if n < 0 {
+		return nil, 0, codeError(n, 0, 0, 0)
+	}
+	return v, n, nil
+}
+
+// ConsumeGroupChecked parses b as a group value until the trailing end group
+// marker, reporting its length.
+// It is like [ConsumeGroup], but reports an [*Error] upon failure.
+// Fields within the group are checked as by [ConsumeFieldChecked].
+func ConsumeGroupChecked(num Number, b []byte) (v []byte, n int, err error) {
+	if _, err := consumeFieldValueChecked(num, StartGroupType, b, 0, DefaultRecursionLimit); err != nil {
+		return nil, 0, err
+	}
+	v, n = ConsumeGroup(num, b)
+	return v, n, nil
+}
+
+// ConsumeFieldChecked parses an entire field record (both tag and value) and
+// returns the field number, the wire type, and the total length.
+// It is like [ConsumeField], but reports an [*Error] upon failure,
+// and checks tags as by [ConsumeTagChecked].
+func ConsumeFieldChecked(b []byte) (Number, Type, int, error) {
+	num, typ, n, err := ConsumeTagChecked(b)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if typ == EndGroupType {
+		return 0, 0, 0, &Error{Kind: ErrorEndGroup, Number: num, Type: typ}
+	}
+	m, err := consumeFieldValueChecked(num, typ, b[n:], n, DefaultRecursionLimit)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return num, typ, n + m, nil
+}
+
+// ConsumeFieldValueChecked parses a field value and returns its length.
+// It is like [ConsumeFieldValue], but reports an [*Error] upon failure,
+// and checks the tags of fields within groups as by [ConsumeTagChecked].
+func ConsumeFieldValueChecked(num Number, typ Type, b []byte) (n int, err error) {
+	return consumeFieldValueChecked(num, typ, b, 0, DefaultRecursionLimit)
+}
+
+// consumeFieldValueChecked parses the field value at b,
+// where off is the offset of b within the original input.
+func consumeFieldValueChecked(num Number, typ Type, b []byte, off, depth int) (n int, err error) {
+	if typ != StartGroupType {
+		n = consumeFieldValueD(num, typ, b, depth)
+		if n < 0 {
+			return 0, codeError(n, off, num, typ)
+		}
+		return n, nil
+	}
+
+	if depth < 0 {
+		return 0, &Error{Kind: ErrorRecursionDepth, Offset: off, Number: num, Type: typ}
+	}
+	for {
+		num2, typ2, m, err := consumeTagChecked(b[n:], off+n)
+		if err != nil {
+			return 0, err
+		}
+		if typ2 == EndGroupType {
+			if num != num2 {
+				return 0, &Error{Kind: ErrorEndGroup, Offset: off + n, Number: num2, Type: typ2}
+			}
+			return n + m, nil
+		}
+		n += m
+
+		m, err = consumeFieldValueChecked(num2, typ2, b[n:], off+n, depth-1)
+		if err != nil {
+			return 0, err
+		}
+		n += m
+	}
+}
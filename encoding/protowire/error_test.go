@@ -0,0 +1,148 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protowire
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestConsumeFieldChecked(t *testing.T) {
+	tests := []struct {
+		desc    string
+		in      []byte
+		wantNum Number
+		wantCnt int
+		wantErr *Error
+	}{{
+		desc:    "varint",
+		in:      dhex("089601"),
+		wantNum: 1,
+		wantCnt: 3,
+	}, {
+		desc:    "group",
+		in:      dhex("0b10010c"),
+		wantNum: 1,
+		wantCnt: 4,
+	}, {
+		desc:    "fixed32",
+		in:      dhex("0d01000000"),
+		wantNum: 1,
+		wantCnt: 5,
+	}, {
+		desc:    "empty input",
+		in:      nil,
+		wantErr: &Error{Kind: ErrorTruncated},
+	}, {
+		desc:    "truncated varint value",
+		in:      dhex("0896"),
+		wantErr: &Error{Kind: ErrorTruncated, Offset: 1, Number: 1, Type: VarintType},
+	}, {
+		desc:    "truncated bytes value",
+		in:      dhex("12056869"),
+		wantErr: &Error{Kind: ErrorTruncated, Offset: 1, Number: 2, Type: BytesType},
+	}, {
+		desc:    "overflowing varint value",
+		in:      dhex("08ffffffffffffffffff02"),
+		wantErr: &Error{Kind: ErrorOverflow, Offset: 1, Number: 1, Type: VarintType},
+	}, {
+		desc:    "zero field number",
+		in:      dhex("00"),
+		wantErr: &Error{Kind: ErrorFieldNumber, Number: 0, Type: VarintType},
+	}, {
+		desc:    "field number too large",
+		in:      AppendTag(nil, MaxValidNumber+1, VarintType),
+		wantErr: &Error{Kind: ErrorFieldNumber, Number: MaxValidNumber + 1, Type: VarintType},
+	}, {
+		desc:    "reserved field number",
+		in:      AppendVarint(AppendTag(nil, FirstReservedNumber, VarintType), 0),
+		wantErr: &Error{Kind: ErrorReservedNumber, Number: FirstReservedNumber, Type: VarintType},
+	}, {
+		desc:    "reserved wire type",
+		in:      dhex("0e"),
+		wantErr: &Error{Kind: ErrorWireType, Number: 1, Type: 6},
+	}, {
+		desc:    "unexpected end group",
+		in:      dhex("0c"),
+		wantErr: &Error{Kind: ErrorEndGroup, Number: 1, Type: EndGroupType},
+	}, {
+		desc:    "mismatching end group",
+		in:      dhex("0b100114"),
+		wantErr: &Error{Kind: ErrorEndGroup, Offset: 3, Number: 2, Type: EndGroupType},
+	}, {
+		desc:    "truncated group",
+		in:      dhex("0b1001"),
+		wantErr: &Error{Kind: ErrorTruncated, Offset: 3},
+	}, {
+		desc:    "error within nested group",
+		in:      dhex("0b131880"),
+		wantErr: &Error{Kind: ErrorTruncated, Offset: 3, Number: 3, Type: VarintType},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			num, _, n, err := ConsumeFieldChecked(tt.in)
+			if tt.wantErr == nil {
+				if err != nil || num != tt.wantNum || n != tt.wantCnt {
+					t.Errorf("ConsumeFieldChecked() = (%v, %v, %v), want (%v, %v, nil)", num, n, err, tt.wantNum, tt.wantCnt)
+				}
+				return
+			}
+			var got *Error
+			if !errors.As(err, &got) {
+				t.Fatalf("ConsumeFieldChecked() error = %v, want %v", err, tt.wantErr)
+			}
+			if *got != *tt.wantErr {
+				t.Errorf("ConsumeFieldChecked() error = %+v, want %+v", *got, *tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestErrorUnwrap(t *testing.T) {
+	_, _, err := ConsumeVarintChecked(dhex("80"))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ConsumeVarintChecked() error = %v, want io.ErrUnexpectedEOF", err)
+	}
+	for code := errCodeTruncated; code >= errCodeEndGroup; code-- {
+		if err := codeError(code, 0, 0, 0); !errors.Is(err, ParseError(code)) {
+			t.Errorf("codeError(%d) = %v, want error matching %v", code, err, ParseError(code))
+		}
+	}
+}
+
+func FuzzConsumeFieldChecked(f *testing.F) {
+	for _, s := range []string{
+		"089601",
+		"120568656c6c6f",
+		"0b10010c",
+		"0d00000000",
+		"090000000000000000",
+		"0b1318800c",
+	} {
+		f.Add(dhex(s))
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		num, typ, n, err := ConsumeFieldChecked(b)
+		wantNum, wantType, wantCnt := ConsumeField(b)
+		if err == nil {
+			if num != wantNum || typ != wantType || n != wantCnt {
+				t.Fatalf("ConsumeFieldChecked() = (%v, %v, %v), but ConsumeField() = (%v, %v, %v)", num, typ, n, wantNum, wantType, wantCnt)
+			}
+			return
+		}
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Fatalf("ConsumeFieldChecked() error = %v, want *Error", err)
+		}
+		if e.Offset < 0 || e.Offset > len(b) {
+			t.Fatalf("ConsumeFieldChecked() error offset = %v, out of range for input of length %v", e.Offset, len(b))
+		}
+		if e.Kind != ErrorFieldNumber && e.Kind != ErrorReservedNumber && e.Kind != ErrorWireType && wantCnt >= 0 {
+			t.Fatalf("ConsumeFieldChecked() error = %v, but ConsumeField() succeeded", err)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protowire
+
+// Field describes the layout of a field record within a wire-format message.
+// All offsets are relative to the start of the message.
+type Field struct {
+	Number Number
+	Type   Type
+
+	// Offset is the offset of the tag.
+	Offset int
+
+	// ValueOffset and ValueLen locate the contents of the field value.
+	// For BytesType fields, they exclude the length prefix,
+	// and for StartGroupType fields, they exclude the end group marker.
+	ValueOffset int
+	ValueLen    int
+
+	// End is the offset immediately following the field record.
+	End int
+}
+
+// Value returns the contents of the field value within b,
+// which must be the message that the field was scanned from.
+func (f Field) Value(b []byte) []byte {
+	return b[f.ValueOffset : f.ValueOffset+f.ValueLen]
+}
+
+// Scanner walks the field records of a wire-format message,
+// reporting the layout of each field without copying or allocating.
+//
+// Every field is validated as by [ConsumeFieldChecked] before it is reported.
+// Typical usage:
+//
+//	s := protowire.NewScanner(b)
+//	for s.Next() {
+//		f := s.Field()
+//		...
+//	}
+//	if err := s.Err(); err != nil {
+//		...
+//	}
+type Scanner struct {
+	b     []byte
+	off   int
+	field Field
+	err   error
+}
+
+// NewScanner returns a scanner over the wire-format message b.
+func NewScanner(b []byte) *Scanner {
+	return &Scanner{b: b}
+}
+
+// Next advances to the next field record, which is reported by [Scanner.Field].
+// It returns false when the end of the message is reached
+// or a malformed field is encountered, in which case [Scanner.Err]
+// reports the error.
+func (s *Scanner) Next() bool {
+	if s.err != nil || s.off >= len(s.b) {
+		return false
+	}
+	off := s.off
+	num, typ, n, err := consumeTagChecked(s.b[off:], off)
+	if err != nil {
+		s.err = err
+		return false
+	}
+	if typ == EndGroupType {
+		s.err = &Error{Kind: ErrorEndGroup, Offset: off, Number: num, Type: typ}
+		return false
+	}
+	m, err := consumeFieldValueChecked(num, typ, s.b[off+n:], off+n, DefaultRecursionLimit)
+	if err != nil {
+		s.err = err
+		return false
+	}
+
+	f := Field{Number: num, Type: typ, Offset: off, ValueOffset: off + n, ValueLen: m, End: off + n + m}
+	switch typ {
+	case BytesType:
+		_, p := ConsumeVarint(s.b[f.ValueOffset:])
+		f.ValueOffset += p
+		f.ValueLen -= p
+	case StartGroupType:
+		f.ValueLen = groupValueLen(num, s.b[f.ValueOffset:f.End])
+	}
+	s.field = f
+	s.off = f.End
+	return true
+}
+
+// Field returns the field record most recently reported by [Scanner.Next].
+func (s *Scanner) Field() Field {
+	return s.field
+}
+
+// Err returns the error that stopped the scan, if any.
+// The error is an [*Error] reporting the offset of the malformed element.
+func (s *Scanner) Err() error {
+	return s.err
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protowire
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConsumeOffset(t *testing.T) {
+	b := dhex("0568656c6c6f")
+	off, length, n := ConsumeBytesOffset(b)
+	if off != 1 || length != 5 || n != 6 {
+		t.Errorf("ConsumeBytesOffset() = (%v, %v, %v), want (1, 5, 6)", off, length, n)
+	}
+	if _, _, n := ConsumeBytesOffset(dhex("0568")); ParseError(n) == nil {
+		t.Errorf("ConsumeBytesOffset(truncated) = %v, want error", n)
+	}
+
+	// Group with a denormalized end marker.
+	b = dhex("2dc3d2e1f0ac808000")
+	length, n = ConsumeGroupOffset(5, b)
+	if length != 5 || n != 9 {
+		t.Errorf("ConsumeGroupOffset() = (%v, %v), want (5, 9)", length, n)
+	}
+	if _, n := ConsumeGroupOffset(5, dhex("2dc3")); ParseError(n) == nil {
+		t.Errorf("ConsumeGroupOffset(truncated) = %v, want error", n)
+	}
+}
+
+func TestScanner(t *testing.T) {
+	var b []byte
+	b = AppendTag(b, 1, VarintType)
+	b = AppendVarint(b, 150)
+	b = AppendTag(b, 2, BytesType)
+	b = AppendString(b, "hello")
+	b = AppendTag(b, 3, StartGroupType)
+	b = AppendTag(b, 4, Fixed32Type)
+	b = AppendFixed32(b, 1)
+	b = AppendTag(b, 3, EndGroupType)
+	b = AppendTag(b, 5, Fixed64Type)
+	b = AppendFixed64(b, 2)
+
+	want := []Field{
+		{Number: 1, Type: VarintType, Offset: 0, ValueOffset: 1, ValueLen: 2, End: 3},
+		{Number: 2, Type: BytesType, Offset: 3, ValueOffset: 5, ValueLen: 5, End: 10},
+		{Number: 3, Type: StartGroupType, Offset: 10, ValueOffset: 11, ValueLen: 5, End: 17},
+		{Number: 5, Type: Fixed64Type, Offset: 17, ValueOffset: 18, ValueLen: 8, End: 26},
+	}
+	var got []Field
+	s := NewScanner(b)
+	for s.Next() {
+		got = append(got, s.Field())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Scanner.Err() = %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("scanned fields mismatch (-want +got):\n%s", diff)
+	}
+	if v := string(got[1].Value(b)); v != "hello" {
+		t.Errorf("Field.Value() = %q, want %q", v, "hello")
+	}
+	if v := got[2].Value(b); !cmp.Equal(v, dhex("2501000000")) {
+		t.Errorf("Field.Value() of group = %x, want 2501000000", v)
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		s := NewScanner(b)
+		for s.Next() {
+		}
+	})
+	if allocs > 0 {
+		t.Errorf("scanning performed %v allocations, want 0", allocs)
+	}
+}
+
+func TestScannerErrors(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		in       []byte
+		wantNums []Number
+		wantErr  *Error
+	}{{
+		desc:     "truncated second field",
+		in:       dhex("0801" + "1205"),
+		wantNums: []Number{1},
+		wantErr:  &Error{Kind: ErrorTruncated, Offset: 3, Number: 2, Type: BytesType},
+	}, {
+		desc:    "unexpected end group",
+		in:      dhex("0c"),
+		wantErr: &Error{Kind: ErrorEndGroup, Number: 1, Type: EndGroupType},
+	}, {
+		desc:     "reserved wire type",
+		in:       dhex("0801" + "0e"),
+		wantNums: []Number{1},
+		wantErr:  &Error{Kind: ErrorWireType, Offset: 2, Number: 1, Type: 6},
+	}} {
+		t.Run(tt.desc, func(t *testing.T) {
+			var nums []Number
+			s := NewScanner(tt.in)
+			for s.Next() {
+				nums = append(nums, s.Field().Number)
+			}
+			if !cmp.Equal(nums, tt.wantNums) {
+				t.Errorf("scanned fields %v, want %v", nums, tt.wantNums)
+			}
+			var got *Error
+			if !errors.As(s.Err(), &got) {
+				t.Fatalf("Scanner.Err() = %v, want %v", s.Err(), tt.wantErr)
+			}
+			if *got != *tt.wantErr {
+				t.Errorf("Scanner.Err() = %+v, want %+v", *got, *tt.wantErr)
+			}
+			if s.Next() {
+				t.Errorf("Scanner.Next() after error = true, want false")
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protowire
+
+// Report summarizes the structure of a wire-format message
+// checked by [Validate].
+type Report struct {
+	// Fields is the number of field records, including those within groups.
+	Fields int
+
+	// Groups is the number of group fields.
+	Groups int
+
+	// MaxDepth is the deepest nesting of groups,
+	// which is zero if the message contains no groups.
+	MaxDepth int
+
+	// Valid is the length of the longest prefix of the input which consists
+	// of complete, well-formed field records. If the input is malformed,
+	// it is the offset of the top-level field record containing the error.
+	Valid int
+}
+
+// Validate checks that b is a well-formed sequence of field records, as may
+// be parsed by [ConsumeFieldChecked], without interpreting the field values.
+// Length-delimited values are not checked, since whether they contain
+// a message cannot be determined without the message descriptor.
+//
+// If b is malformed, Validate reports an [*Error] classifying the problem
+// and locating the malformed element, along with a report of the fields
+// preceding it. Validate never panics, whatever the input.
+func Validate(b []byte) (Report, error) {
+	var r Report
+	s := NewScanner(b)
+	for s.Next() {
+		f := s.Field()
+		r.addField(f.Type, f.Value(b), 1)
+		r.Valid = f.End
+	}
+	return r, s.Err()
+}
+
+// addField counts a well-formed field with the given value,
+// which is at the given depth of group nesting.
+func (r *Report) addField(typ Type, v []byte, depth int) {
+	r.Fields++
+	if typ != StartGroupType {
+		return
+	}
+	r.Groups++
+	r.MaxDepth = max(r.MaxDepth, depth)
+	s := NewScanner(v)
+	for s.Next() {
+		f := s.Field()
+		r.addField(f.Type, f.Value(v), depth+1)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protowire
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		desc       string
+		in         []byte
+		wantReport Report
+		wantErr    *Error
+	}{{
+		desc: "empty input",
+	}, {
+		desc:       "scalars",
+		in:         dhex("089601" + "0d01000000" + "120568656c6c6f"),
+		wantReport: Report{Fields: 3, Valid: 15},
+	}, {
+		desc:       "nested groups",
+		in:         dhex("0b" + "13" + "1801" + "14" + "1001" + "0c" + "2001"),
+		wantReport: Report{Fields: 5, Groups: 2, MaxDepth: 2, Valid: 10},
+	}, {
+		desc:       "truncated varint after valid field",
+		in:         dhex("0801" + "1096"),
+		wantReport: Report{Fields: 1, Valid: 2},
+		wantErr:    &Error{Kind: ErrorTruncated, Offset: 3, Number: 2, Type: VarintType},
+	}, {
+		desc:       "truncated group",
+		in:         dhex("0801" + "0b1001"),
+		wantReport: Report{Fields: 1, Valid: 2},
+		wantErr:    &Error{Kind: ErrorTruncated, Offset: 5},
+	}, {
+		desc:    "mismatching end group",
+		in:      dhex("0b100114"),
+		wantErr: &Error{Kind: ErrorEndGroup, Offset: 3, Number: 2, Type: EndGroupType},
+	}, {
+		desc:       "unexpected end group",
+		in:         dhex("08010c"),
+		wantReport: Report{Fields: 1, Valid: 2},
+		wantErr:    &Error{Kind: ErrorEndGroup, Offset: 2, Number: 1, Type: EndGroupType},
+	}, {
+		desc:    "reserved wire type",
+		in:      dhex("0e"),
+		wantErr: &Error{Kind: ErrorWireType, Number: 1, Type: 6},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			r, err := Validate(tt.in)
+			if r != tt.wantReport {
+				t.Errorf("Validate() report = %+v, want %+v", r, tt.wantReport)
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			var got *Error
+			if !errors.As(err, &got) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			if *got != *tt.wantErr {
+				t.Errorf("Validate() error = %+v, want %+v", *got, *tt.wantErr)
+			}
+		})
+	}
+}
+
+func FuzzValidate(f *testing.F) {
+	for _, s := range []string{
+		"089601",
+		"120568656c6c6f",
+		"0b13180114100c",
+		"0d00000000",
+		"090000000000000000",
+		"0b1318800c",
+	} {
+		f.Add(dhex(s))
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		r, err := Validate(b)
+		if r.Valid < 0 || r.Valid > len(b) {
+			t.Fatalf("Validate() report.Valid = %d, out of range for %d bytes", r.Valid, len(b))
+		}
+		// The valid prefix must be a sequence of well-formed fields.
+		for p := b[:r.Valid]; len(p) > 0; {
+			_, _, n, err := ConsumeFieldChecked(p)
+			if err != nil {
+				t.Fatalf("valid prefix %x is malformed: %v", b[:r.Valid], err)
+			}
+			p = p[n:]
+		}
+		if err == nil {
+			if r.Valid != len(b) {
+				t.Fatalf("Validate() succeeded, but report.Valid = %d, want %d", r.Valid, len(b))
+			}
+			return
+		}
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Fatalf("Validate() error = %v, want *Error", err)
+		}
+		if e.Offset < r.Valid || e.Offset > len(b) {
+			t.Fatalf("Validate() error offset %d, want in [%d, %d]", e.Offset, r.Valid, len(b))
+		}
+	})
+}
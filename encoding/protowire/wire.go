@@ -444,6 +444,22 @@ func ConsumeBytes(b []byte) (v []byte, n int) {
 	return b[n:][:m], n + int(m)
 }
 
+// ConsumeBytesOffset parses b as a length-prefixed bytes value.
+// It is like [ConsumeBytes], but rather than a sub-slice of b, it reports
+// the value as the offset and length of its contents within b,
+// such that the value is b[off:off+length].
+// This returns a negative total length n upon an error (see [ParseError]).
+func ConsumeBytesOffset(b []byte) (off, length, n int) {
+	m, n := ConsumeVarint(b)
+	if n < 0 {
+		return 0, 0, n // forward error code
+	}
+	if m > uint64(len(b[n:])) {
+		return 0, 0, errCodeTruncated
+	}
+	return n, int(m), n + int(m)
+}
+
 // SizeBytes returns the encoded size of a length-prefixed bytes value,
 // given only the length.
 func SizeBytes(n int) int {
@@ -473,12 +489,29 @@ func AppendGroup(b []byte, num Number, v []byte) []byte {
 // does not contain the end marker, while the length does contain the end marker.
 // This returns a negative length upon an error (see [ParseError]).
 func ConsumeGroup(num Number, b []byte) (v []byte, n int) {
-	n = ConsumeFieldValue(num, StartGroupType, b)
+	length, n := ConsumeGroupOffset(num, b)
 	if n < 0 {
 		return nil, n // forward error code
 	}
-	b = b[:n]
+	return b[:length], n
+}
+
+// ConsumeGroupOffset parses b as a group value until the trailing end group
+// marker. It is like [ConsumeGroup], but rather than a sub-slice of b,
+// it reports the length of the value excluding the end marker,
+// such that the value is b[:length].
+// This returns a negative total length n upon an error (see [ParseError]).
+func ConsumeGroupOffset(num Number, b []byte) (length, n int) {
+	n = ConsumeFieldValue(num, StartGroupType, b)
+	if n < 0 {
+		return 0, n // forward error code
+	}
+	return groupValueLen(num, b[:n]), n
+}
 
+// groupValueLen returns the length of the group value b
+// excluding its trailing end group marker.
+func groupValueLen(num Number, b []byte) int {
 	// Truncate off end group marker, but need to handle denormalized varints.
 	// Assuming end marker is never 0 (which is always the case since
 	// EndGroupType is non-zero), we can truncate all trailing bytes where the
@@ -486,8 +519,7 @@ func ConsumeGroup(num Number, b []byte) (v []byte, n int) {
 	for len(b) > 0 && b[len(b)-1]&0x7f == 0 {
 		b = b[:len(b)-1]
 	}
-	b = b[:len(b)-SizeTag(num)]
-	return b, n
+	return len(b) - SizeTag(num)
 }
 
 // SizeGroup returns the encoded size of a group, given only the length.
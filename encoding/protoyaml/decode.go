@@ -0,0 +1,450 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoyaml
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protoknown"
+	"google.golang.org/protobuf/internal/encoding/json"
+	"google.golang.org/protobuf/internal/encoding/yaml"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/internal/pragma"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Unmarshal reads the given YAML document into the given [proto.Message].
+// The provided message must be mutable (e.g., a non-nil pointer to a message).
+func Unmarshal(b []byte, m proto.Message) error {
+	return UnmarshalOptions{}.Unmarshal(b, m)
+}
+
+// UnmarshalOptions is a configurable YAML format parser.
+type UnmarshalOptions struct {
+	pragma.NoUnkeyedLiterals
+
+	// If AllowPartial is set, input for messages that will result in missing
+	// required fields will not return an error.
+	AllowPartial bool
+
+	// If DiscardUnknown is set, unknown fields and enum name values are ignored.
+	DiscardUnknown bool
+
+	// AllowFieldNumbers accepts decimal field numbers as mapping keys,
+	// in addition to field names.
+	AllowFieldNumbers bool
+
+	// Resolver is used for looking up types when unmarshaling
+	// google.protobuf.Any messages or extension fields.
+	// If nil, this defaults to using protoregistry.GlobalTypes.
+	Resolver interface {
+		protoregistry.MessageTypeResolver
+		protoregistry.ExtensionTypeResolver
+	}
+
+	// Behaviors specifies custom behaviors of message types,
+	// as for protojson.UnmarshalOptions.Behaviors.
+	Behaviors *protoknown.Behaviors
+
+	// RecursionLimit limits how deeply messages may be nested.
+	// If zero, a default limit is applied.
+	RecursionLimit int
+}
+
+// Unmarshal reads the given YAML document and populates the given
+// [proto.Message] using options in the UnmarshalOptions object.
+// It will clear the message first before setting the fields.
+// If it returns an error, the given message may be partially set.
+// The provided message must be mutable (e.g., a non-nil pointer to a message).
+func (o UnmarshalOptions) Unmarshal(b []byte, m proto.Message) error {
+	return o.unmarshal(b, m)
+}
+
+// unmarshal is a centralized function that all unmarshal operations go through.
+// For profiling purposes, avoid changing the name of this function or
+// introducing other code paths for unmarshal that do not go through this.
+func (o UnmarshalOptions) unmarshal(b []byte, m proto.Message) error {
+	proto.Reset(m)
+
+	n, err := yaml.Parse(b)
+	if err != nil {
+		return errors.New("%v", err)
+	}
+	if o.Resolver == nil {
+		o.Resolver = protoregistry.GlobalTypes
+	}
+
+	c := converter{opts: o}
+	if n.Kind == yaml.Scalar && n.Resolve() == yaml.Null {
+		// An empty document is an empty message.
+		n = &yaml.Node{Kind: yaml.Mapping, Line: n.Line, Column: n.Column}
+	}
+	c.convertMessage(n, m.ProtoReflect().Descriptor())
+
+	err = protojson.UnmarshalOptions{
+		AllowPartial:      o.AllowPartial,
+		DiscardUnknown:    o.DiscardUnknown,
+		AllowFieldNumbers: o.AllowFieldNumbers,
+		Resolver:          o.Resolver,
+		Behaviors:         o.Behaviors,
+		RecursionLimit:    o.RecursionLimit,
+	}.Unmarshal(c.buf, m)
+	return c.translateError(err)
+}
+
+// converter converts a YAML node to JSON according to the JSON mapping of
+// the message it is unmarshaled into. This is needed because the type of
+// a plain YAML scalar is ambiguous: the scalar 123 for a string field is
+// the JSON string "123", but for an int32 field it is the JSON number 123.
+//
+// The output contains one JSON token per line, and pos records the position
+// of the YAML node for each line, so that the errors reported by protojson
+// can be translated to positions in the YAML document.
+type converter struct {
+	opts UnmarshalOptions
+	buf  []byte
+	pos  []*yaml.Node
+}
+
+// write writes a JSON token for the node on a line of its own.
+func (c *converter) write(n *yaml.Node, tok string) {
+	c.buf = append(c.buf, tok...)
+	c.buf = append(c.buf, '\n')
+	c.pos = append(c.pos, n)
+}
+
+// writeComma terminates the previous line with a comma.
+func (c *converter) writeComma() {
+	c.buf = append(c.buf[:len(c.buf)-1], ",\n"...)
+}
+
+func (c *converter) writeString(n *yaml.Node, s string) {
+	e, _ := json.NewEncoder(nil, "")
+	e.WriteString(s) // YAML documents are valid UTF-8
+	c.write(n, string(e.Bytes()))
+}
+
+// convertMessage converts a node representing a message of type md.
+func (c *converter) convertMessage(n *yaml.Node, md protoreflect.MessageDescriptor) {
+	switch c.wellKnownType(md.FullName()) {
+	case anyType:
+		c.convertAny(n)
+		return
+	case stringType:
+		c.convertString(n)
+		return
+	case untypedType:
+		c.convertUntyped(n)
+		return
+	case wrapperType:
+		c.convertSingular(n, md.Fields().ByNumber(genid.WrapperValue_Value_field_number))
+		return
+	}
+	if n.Kind != yaml.Mapping {
+		c.convertUntyped(n)
+		return
+	}
+	c.write(n, "{")
+	for i := 0; i < len(n.Content); i += 2 {
+		if i > 0 {
+			c.writeComma()
+		}
+		c.convertField(n.Content[i], n.Content[i+1], md)
+	}
+	c.write(n, "}")
+}
+
+// convertField converts a mapping entry of a message of type md.
+func (c *converter) convertField(key, val *yaml.Node, md protoreflect.MessageDescriptor) {
+	if key.Kind != yaml.Scalar {
+		c.convertUntyped(key)
+		return
+	}
+	c.writeString(key, key.Value)
+	c.buf[len(c.buf)-1] = ':'
+	c.buf = append(c.buf, '\n')
+
+	fd := c.findField(key.Value, md)
+	switch {
+	case fd == nil:
+		c.convertUntyped(val)
+	case isNull(val):
+		c.write(val, "null")
+	case fd.IsList() && val.Kind == yaml.Sequence:
+		c.write(val, "[")
+		for i, item := range val.Content {
+			if i > 0 {
+				c.writeComma()
+			}
+			c.convertSingular(item, fd)
+		}
+		c.write(val, "]")
+	case fd.IsMap() && val.Kind == yaml.Mapping:
+		c.write(val, "{")
+		for i := 0; i < len(val.Content); i += 2 {
+			if i > 0 {
+				c.writeComma()
+			}
+			k, v := val.Content[i], val.Content[i+1]
+			if k.Kind != yaml.Scalar {
+				c.convertUntyped(k)
+			} else {
+				c.writeString(k, k.Value)
+				c.buf[len(c.buf)-1] = ':'
+				c.buf = append(c.buf, '\n')
+			}
+			c.convertSingular(v, fd.MapValue())
+		}
+		c.write(val, "}")
+	case fd.IsList(), fd.IsMap():
+		// Let protojson report the type mismatch.
+		c.convertUntyped(val)
+	default:
+		c.convertSingular(val, fd)
+	}
+}
+
+// findField finds the field of md named by the mapping key s
+// in the same way as protojson.
+func (c *converter) findField(s string, md protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		xt, err := c.opts.Resolver.FindExtensionByName(protoreflect.FullName(s[1 : len(s)-1]))
+		if err != nil {
+			return nil
+		}
+		return xt.TypeDescriptor()
+	}
+	if fd := md.Fields().ByJSONName(s); fd != nil {
+		return fd
+	}
+	if fd := md.Fields().ByTextName(s); fd != nil {
+		return fd
+	}
+	if c.opts.AllowFieldNumbers {
+		if num, err := strconv.ParseInt(s, 10, 32); err == nil {
+			if fd := md.Fields().ByNumber(protoreflect.FieldNumber(num)); fd != nil {
+				return fd
+			}
+			if xt, err := c.opts.Resolver.FindExtensionByNumber(md.FullName(), protoreflect.FieldNumber(num)); err == nil {
+				return xt.TypeDescriptor()
+			}
+		}
+	}
+	return nil
+}
+
+// convertAny converts a google.protobuf.Any message, whose fields are those
+// of the message type named by its "@type" entry.
+func (c *converter) convertAny(n *yaml.Node) {
+	if n.Kind != yaml.Mapping {
+		c.convertUntyped(n)
+		return
+	}
+	var md protoreflect.MessageDescriptor
+	for i := 0; i < len(n.Content); i += 2 {
+		if k, v := n.Content[i], n.Content[i+1]; k.Value == "@type" && v.Kind == yaml.Scalar {
+			if mt, err := c.opts.Resolver.FindMessageByURL(v.Value); err == nil {
+				md = mt.Descriptor()
+			}
+		}
+	}
+	if md == nil {
+		// Let protojson report the missing or unresolvable type.
+		c.convertUntyped(n)
+		return
+	}
+	c.write(n, "{")
+	for i := 0; i < len(n.Content); i += 2 {
+		if i > 0 {
+			c.writeComma()
+		}
+		k, v := n.Content[i], n.Content[i+1]
+		switch {
+		case k.Value == "@type":
+			c.convertUntyped(k)
+			c.buf[len(c.buf)-1] = ':'
+			c.buf = append(c.buf, '\n')
+			c.convertString(v)
+		case k.Value == "value" && c.wellKnownType(md.FullName()) != fieldsType:
+			c.convertUntyped(k)
+			c.buf[len(c.buf)-1] = ':'
+			c.buf = append(c.buf, '\n')
+			c.convertMessage(v, md)
+		default:
+			c.convertField(k, v, md)
+		}
+	}
+	c.write(n, "}")
+}
+
+// Representations of message types with a custom JSON mapping.
+const (
+	fieldsType  = iota // a JSON object of the fields
+	anyType            // google.protobuf.Any
+	stringType         // a JSON string
+	untypedType        // any JSON value, as for google.protobuf.Value
+	wrapperType        // the JSON value of the wrapped field
+)
+
+// wellKnownType reports how messages of the named type are represented
+// in the JSON mapping.
+func (c *converter) wellKnownType(name protoreflect.FullName) int {
+	if name.Parent() == genid.GoogleProtobuf_package {
+		switch name.Name() {
+		case genid.Any_message_name:
+			return anyType
+		case genid.Timestamp_message_name,
+			genid.Duration_message_name,
+			genid.FieldMask_message_name:
+			return stringType
+		case genid.BoolValue_message_name,
+			genid.Int32Value_message_name,
+			genid.Int64Value_message_name,
+			genid.UInt32Value_message_name,
+			genid.UInt64Value_message_name,
+			genid.FloatValue_message_name,
+			genid.DoubleValue_message_name,
+			genid.StringValue_message_name,
+			genid.BytesValue_message_name:
+			return wrapperType
+		case genid.Struct_message_name,
+			genid.ListValue_message_name,
+			genid.Value_message_name,
+			// google.protobuf.Empty is an empty JSON object, but it is
+			// embedded in google.protobuf.Any as a "value" entry.
+			genid.Empty_message_name:
+			return untypedType
+		}
+	}
+	if _, ok := c.opts.Behaviors.Lookup(name); ok {
+		return stringType
+	}
+	return fieldsType
+}
+
+// convertSingular converts a singular value of the field fd.
+func (c *converter) convertSingular(n *yaml.Node, fd protoreflect.FieldDescriptor) {
+	if n.Kind != yaml.Scalar {
+		if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+			c.convertMessage(n, fd.Message())
+		} else {
+			c.convertUntyped(n)
+		}
+		return
+	}
+	typ := n.Resolve()
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		c.convertMessage(n, fd.Message())
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		if typ == yaml.Null {
+			c.write(n, "null")
+		} else {
+			c.writeString(n, n.Value)
+		}
+	case protoreflect.EnumKind:
+		if typ == yaml.Int {
+			c.convertUntyped(n)
+		} else {
+			c.convertString(n)
+		}
+	case protoreflect.BoolKind:
+		c.convertUntyped(n)
+	default:
+		if typ == yaml.Int || typ == yaml.Float {
+			c.convertUntyped(n)
+		} else {
+			c.convertString(n)
+		}
+	}
+}
+
+// convertString converts a scalar as a JSON string, except for null.
+func (c *converter) convertString(n *yaml.Node) {
+	if n.Kind != yaml.Scalar || n.Resolve() == yaml.Null {
+		c.convertUntyped(n)
+		return
+	}
+	c.writeString(n, n.Value)
+}
+
+// convertUntyped converts a node according to the types of its scalars,
+// for nodes not subject to the message schema, such as those representing
+// google.protobuf.Value messages.
+func (c *converter) convertUntyped(n *yaml.Node) {
+	switch n.Kind {
+	case yaml.Mapping:
+		c.write(n, "{")
+		for i := 0; i < len(n.Content); i += 2 {
+			if i > 0 {
+				c.writeComma()
+			}
+			c.writeString(n.Content[i], n.Content[i].Value)
+			c.buf[len(c.buf)-1] = ':'
+			c.buf = append(c.buf, '\n')
+			c.convertUntyped(n.Content[i+1])
+		}
+		c.write(n, "}")
+	case yaml.Sequence:
+		c.write(n, "[")
+		for i, item := range n.Content {
+			if i > 0 {
+				c.writeComma()
+			}
+			c.convertUntyped(item)
+		}
+		c.write(n, "]")
+	default:
+		switch n.Resolve() {
+		case yaml.Null:
+			c.write(n, "null")
+		case yaml.Bool:
+			c.write(n, strconv.FormatBool(n.Bool()))
+		case yaml.Int, yaml.Float:
+			if v, ok := n.Number(); ok {
+				c.write(n, v)
+			} else {
+				c.writeString(n, n.NonFinite())
+			}
+		default:
+			c.writeString(n, n.Value)
+		}
+	}
+}
+
+func isNull(n *yaml.Node) bool {
+	return n.Kind == yaml.Scalar && n.Resolve() == yaml.Null
+}
+
+var linePattern = regexp.MustCompile(`\(line (\d+):\d+\)`)
+
+// translateError translates the JSON position reported by a protojson error
+// to the position of the corresponding YAML node.
+func (c *converter) translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	loc := linePattern.FindStringSubmatchIndex(msg)
+	if loc == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(msg[loc[2]:loc[3]])
+	if line < 1 || line > len(c.pos) {
+		return err
+	}
+	n := c.pos[line-1]
+	start := loc[0]
+	if i := strings.LastIndex(msg[:start], "syntax error "); i >= 0 {
+		start = i
+	}
+	return errors.New("%s(line %d:%d)%s", msg[start:loc[0]], n.Line, n.Column, msg[loc[1]:])
+}
@@ -0,0 +1,252 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoyaml_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/protoyaml"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test3"
+	pb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestUnmarshal(t *testing.T) {
+	nested, err := anypb.New(&pb2.Nested{OptString: proto.String("in any")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := anypb.New(wrapperspb.String("07"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		desc  string
+		umo   protoyaml.UnmarshalOptions
+		in    string
+		input proto.Message
+		want  proto.Message
+	}{{
+		desc:  "empty document",
+		in:    "# nothing here\n",
+		input: &testpb.TestAllTypes{},
+		want:  &testpb.TestAllTypes{},
+	}, {
+		desc: "scalars typed by the schema",
+		in: `
+singularInt32: 0x10
+singular_int64: -007
+singularUint32: 3
+singularUint64: "18446744073709551615"
+singularFloat: .inf
+singularDouble: 1.
+singularString: 123
+singularBytes: aGVsbG8=
+singularNestedEnum: BAZ
+singularForeignEnum: 5
+`,
+		input: &testpb.TestAllTypes{},
+		want: &testpb.TestAllTypes{
+			SingularInt32:       16,
+			SingularInt64:       -7,
+			SingularUint32:      3,
+			SingularUint64:      math.MaxUint64,
+			SingularFloat:       float32(math.Inf(1)),
+			SingularDouble:      1,
+			SingularString:      "123",
+			SingularBytes:       []byte("hello"),
+			SingularNestedEnum:  testpb.TestAllTypes_BAZ,
+			SingularForeignEnum: testpb.ForeignEnum_FOREIGN_BAR,
+		},
+	}, {
+		desc:  "bool and null",
+		in:    "oneofBool: True\nsingularString: ~\n",
+		input: &testpb.TestAllTypes{},
+		want:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofBool{OneofBool: true}},
+	}, {
+		desc: "collections",
+		in: `
+repeated_int32: [1, 2, 3]
+repeatedString:
+  - yes
+  - "no"
+mapInt32Int32: {1: 2, -3: 4}
+mapStringString:
+  true: "false"
+  key: |
+    multi
+    line
+repeatedNestedMessage:
+  - a: 1
+  - a: 2
+    corecursive:
+      singularString: deep
+`,
+		input: &testpb.TestAllTypes{},
+		want: &testpb.TestAllTypes{
+			RepeatedInt32:   []int32{1, 2, 3},
+			RepeatedString:  []string{"yes", "no"},
+			MapInt32Int32:   map[int32]int32{1: 2, -3: 4},
+			MapStringString: map[string]string{"true": "false", "key": "multi\nline\n"},
+			RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+				{A: 1},
+				{A: 2, Corecursive: &testpb.TestAllTypes{SingularString: "deep"}},
+			},
+		},
+	}, {
+		desc:  "field numbers",
+		umo:   protoyaml.UnmarshalOptions{AllowFieldNumbers: true},
+		in:    "87: 42\n94: 0x2a\n",
+		input: &testpb.TestAllTypes{},
+		want:  &testpb.TestAllTypes{SingularFixed32: 42, SingularString: "0x2a"},
+	}, {
+		desc:  "extensions",
+		in:    "'[pb2.opt_ext_bool]': true\n\"[pb2.opt_ext_string]\": 1e3\n",
+		input: &pb2.Extensions{},
+		want: func() proto.Message {
+			m := &pb2.Extensions{}
+			proto.SetExtension(m, pb2.E_OptExtBool, true)
+			proto.SetExtension(m, pb2.E_OptExtString, "1e3")
+			return m
+		}(),
+	}, {
+		desc: "well-known types",
+		in: `
+optBool: false
+optInt64: 12
+optString: 0012
+optBytes: AQI=
+optDuration: 1.5s
+optTimestamp: 2024-01-02T03:04:05Z
+optStruct:
+  number: 0x10
+  string: "0x10"
+  list: [true, null, .nan]
+optValue: hello
+optNull: null
+optEmpty: {}
+optAny:
+  "@type": type.googleapis.com/pb2.Nested
+  optString: in any
+optFieldmask: optBool,optStruct.number
+`,
+		input: &pb2.KnownTypes{},
+		want: &pb2.KnownTypes{
+			OptBool:      wrapperspb.Bool(false),
+			OptInt64:     wrapperspb.Int64(12),
+			OptString:    wrapperspb.String("0012"),
+			OptBytes:     wrapperspb.Bytes([]byte{1, 2}),
+			OptDuration:  durationpb.New(1500e6),
+			OptTimestamp: &timestamppb.Timestamp{Seconds: 1704164645},
+			OptStruct: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"number": structpb.NewNumberValue(16),
+				"string": structpb.NewStringValue("0x10"),
+				"list": structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{
+					structpb.NewBoolValue(true),
+					structpb.NewNullValue(),
+					structpb.NewStringValue("NaN"),
+				}}),
+			}},
+			OptValue:     structpb.NewStringValue("hello"),
+			OptNull:      structpb.NullValue_NULL_VALUE.Enum(),
+			OptEmpty:     &emptypb.Empty{},
+			OptAny:       nested,
+			OptFieldmask: &fieldmaskpb.FieldMask{Paths: []string{"opt_bool", "opt_struct.number"}},
+		},
+	}, {
+		desc:  "Any of a well-known type",
+		in:    "optAny:\n  '@type': type.googleapis.com/google.protobuf.StringValue\n  value: 07\n",
+		input: &pb2.KnownTypes{},
+		want:  &pb2.KnownTypes{OptAny: wrapped},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := tt.input
+			if err := tt.umo.Unmarshal([]byte(tt.in), got); err != nil {
+				t.Fatalf("Unmarshal() error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("Unmarshal() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		umo     protoyaml.UnmarshalOptions
+		in      string
+		input   proto.Message
+		wantErr string
+	}{{
+		desc:    "syntax error",
+		in:      "a: [1,\n",
+		input:   &testpb.TestAllTypes{},
+		wantErr: "unexpected end of document",
+	}, {
+		desc:    "unknown field",
+		in:      "singularInt32: 1\n\nnested:\n  a: 1\n",
+		input:   &testpb.TestAllTypes{},
+		wantErr: `(line 3:1): unknown field "nested"`,
+	}, {
+		desc:    "invalid scalar",
+		in:      "singularNestedMessage:\n  a: on\n",
+		input:   &testpb.TestAllTypes{},
+		wantErr: "(line 2:6): invalid value for int32 field a: \"on\"",
+	}, {
+		desc:    "invalid bool",
+		in:      "oneofBool: yes\n",
+		input:   &testpb.TestAllTypes{},
+		wantErr: "(line 1:12): invalid value for bool field oneofBool: \"yes\"",
+	}, {
+		desc:    "invalid enum",
+		in:      "repeatedNestedEnum:\n- FOO\n- QUX\n",
+		input:   &testpb.TestAllTypes{},
+		wantErr: "(line 3:3): invalid value for enum field repeatedNestedEnum",
+	}, {
+		desc:    "mapping for scalar",
+		in:      "singularString:\n  x: 1\n",
+		input:   &testpb.TestAllTypes{},
+		wantErr: "(line 2:3): invalid value for string field singularString: {",
+	}, {
+		desc:    "duplicate field",
+		in:      "singularInt32: 1\nsingular_int32: 2\n",
+		input:   &testpb.TestAllTypes{},
+		wantErr: "(line 2:1): duplicate field",
+	}, {
+		desc:    "invalid timestamp",
+		in:      "optTimestamp: yesterday\n",
+		input:   &pb2.KnownTypes{},
+		wantErr: "(line 1:15): invalid google.protobuf.Timestamp value",
+	}, {
+		desc:    "required field",
+		in:      "{}",
+		input:   &pb2.PartialRequired{},
+		wantErr: "required field (pb2.PartialRequired).req_string not set",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := tt.umo.Unmarshal([]byte(tt.in), tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Unmarshal() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoyaml marshals and unmarshals protocol buffer messages as YAML
+// documents, such as configuration files.
+//
+// A message is represented by the YAML equivalent of its JSON representation,
+// as implemented by package protojson, including the special representations
+// of well-known types such as google.protobuf.Timestamp and
+// google.protobuf.Struct. Mapping keys may be either the lowerCamelCase JSON
+// name or the proto field name of a field. Extension fields are keyed by
+// their full name in brackets, which must be quoted since a bracket otherwise
+// starts a flow sequence (e.g., "[pkg.ext]": 1).
+//
+// Since YAML scalars are typed according to the message rather than their
+// syntax, quoting is rarely necessary. For example, the plain scalar 123 is
+// the string "123" for a string field. Scalars are resolved by the YAML 1.2
+// core schema, so yes and on are not accepted for bool fields:
+//
+//	name: my-service
+//	version: 1.10     # a string field
+//	replicas: 3
+//	timeout: 2.5s     # a google.protobuf.Duration field
+//	labels:
+//	  tier: backend
+//	ports:
+//	  - name: http
+//	    port: 8080
+//
+// The unmarshaler supports the subset of YAML 1.2 that is commonly used for
+// configuration: block and flow collections, plain, quoted, and block
+// scalars, and comments. Anchors, aliases, tags, complex mapping keys, and
+// streams of multiple documents are rejected. Errors report the line and
+// column of the YAML node at fault.
+package protoyaml
@@ -0,0 +1,161 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoyaml
+
+import (
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protoknown"
+	"google.golang.org/protobuf/internal/encoding/json"
+	"google.golang.org/protobuf/internal/encoding/yaml"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/pragma"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Format formats the message as a YAML document.
+// This method is only intended for human consumption and ignores errors.
+// Do not depend on the output being stable. Its output will change across
+// different builds of your program, even when using the same version of the
+// protobuf module.
+func Format(m proto.Message) string {
+	return MarshalOptions{}.Format(m)
+}
+
+// Marshal writes the given [proto.Message] as a YAML document using
+// default options.
+func Marshal(m proto.Message) ([]byte, error) {
+	return MarshalOptions{}.Marshal(m)
+}
+
+// MarshalOptions is a configurable YAML format marshaler.
+type MarshalOptions struct {
+	pragma.NoUnkeyedLiterals
+
+	// AllowPartial allows messages that have missing required fields to marshal
+	// without returning an error. If AllowPartial is false (the default),
+	// Marshal will return error if there are any missing required fields.
+	AllowPartial bool
+
+	// UseProtoNames uses proto field name instead of lowerCamelCase name in
+	// mapping keys.
+	UseProtoNames bool
+
+	// UseEnumNumbers emits enum values as numbers.
+	UseEnumNumbers bool
+
+	// EmitUnpopulated specifies whether to emit unpopulated fields,
+	// as for protojson.MarshalOptions.EmitUnpopulated.
+	EmitUnpopulated bool
+
+	// EmitDefaultValues specifies whether to emit default-valued primitive
+	// fields, empty lists, and empty maps,
+	// as for protojson.MarshalOptions.EmitDefaultValues.
+	EmitDefaultValues bool
+
+	// Behaviors specifies custom behaviors of message types,
+	// as for protojson.MarshalOptions.Behaviors.
+	Behaviors *protoknown.Behaviors
+
+	// Resolver is used for looking up types when expanding google.protobuf.Any
+	// messages. If nil, this defaults to using protoregistry.GlobalTypes.
+	Resolver interface {
+		protoregistry.ExtensionTypeResolver
+		protoregistry.MessageTypeResolver
+	}
+}
+
+// Format formats the message as a YAML document.
+// This method is only intended for human consumption and ignores errors.
+// Do not depend on the output being stable. Its output will change across
+// different builds of your program, even when using the same version of the
+// protobuf module.
+func (o MarshalOptions) Format(m proto.Message) string {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return "<nil>" // invalid syntax, but okay since this is for debugging
+	}
+	o.AllowPartial = true
+	b, _ := o.Marshal(m)
+	return string(b)
+}
+
+// Marshal marshals the given [proto.Message] as a YAML document using options
+// in MarshalOptions. Do not depend on the output being stable. Its output will
+// change across different builds of your program, even when using the same
+// version of the protobuf module.
+func (o MarshalOptions) Marshal(m proto.Message) ([]byte, error) {
+	return o.marshal(m)
+}
+
+// marshal is a centralized function that all marshal operations go through.
+// For profiling purposes, avoid changing the name of this function or
+// introducing other code paths for marshal that do not go through this.
+func (o MarshalOptions) marshal(m proto.Message) ([]byte, error) {
+	b, err := protojson.MarshalOptions{
+		AllowPartial:      o.AllowPartial,
+		UseProtoNames:     o.UseProtoNames,
+		UseEnumNumbers:    o.UseEnumNumbers,
+		EmitUnpopulated:   o.EmitUnpopulated,
+		EmitDefaultValues: o.EmitDefaultValues,
+		Resolver:          o.Resolver,
+		Behaviors:         o.Behaviors,
+	}.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	n, err := parseJSON(json.NewDecoder(b))
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(n), nil
+}
+
+// parseJSON parses the JSON value produced by protojson into a YAML node.
+// JSON strings are represented by quoted scalars, so they are quoted in the
+// output if they would otherwise be read as another type, such as the
+// strings representing 64-bit integers.
+func parseJSON(d *json.Decoder) (*yaml.Node, error) {
+	tok, err := d.Read()
+	if err != nil {
+		return nil, err
+	}
+	switch tok.Kind() {
+	case json.ObjectOpen:
+		n := &yaml.Node{Kind: yaml.Mapping}
+		for {
+			tok, err := d.Read()
+			if err != nil {
+				return nil, err
+			}
+			if tok.Kind() == json.ObjectClose {
+				return n, nil
+			}
+			val, err := parseJSON(d)
+			if err != nil {
+				return nil, err
+			}
+			key := &yaml.Node{Kind: yaml.Scalar, Style: yaml.Quoted, Value: tok.Name()}
+			n.Content = append(n.Content, key, val)
+		}
+	case json.ArrayOpen:
+		n := &yaml.Node{Kind: yaml.Sequence}
+		for {
+			if tok, _ := d.Peek(); tok.Kind() == json.ArrayClose {
+				d.Read()
+				return n, nil
+			}
+			item, err := parseJSON(d)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, item)
+		}
+	case json.String:
+		return &yaml.Node{Kind: yaml.Scalar, Style: yaml.Quoted, Value: tok.ParsedString()}, nil
+	case json.Number, json.Bool, json.Null:
+		return &yaml.Node{Kind: yaml.Scalar, Value: tok.RawString()}, nil
+	}
+	return nil, errors.New("unexpected token %s", tok.RawString())
+}
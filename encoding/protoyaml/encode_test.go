@@ -0,0 +1,125 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoyaml_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/protoyaml"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test3"
+	pb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		desc  string
+		mo    protoyaml.MarshalOptions
+		input proto.Message
+		want  string
+	}{{
+		desc:  "empty message",
+		input: &testpb.TestAllTypes{},
+		want:  "{}\n",
+	}, {
+		desc: "scalars",
+		input: &testpb.TestAllTypes{
+			SingularInt32:      -1,
+			SingularInt64:      1 << 40,
+			SingularDouble:     1.5,
+			SingularString:     "true",
+			SingularBytes:      []byte("hi"),
+			SingularNestedEnum: testpb.TestAllTypes_BAR,
+			OneofField:         &testpb.TestAllTypes_OneofString{OneofString: "plain text"},
+		},
+		want: `singularInt32: -1
+singularInt64: "1099511627776"
+singularDouble: 1.5
+singularString: "true"
+singularBytes: aGk=
+singularNestedEnum: BAR
+oneofString: plain text
+`,
+	}, {
+		desc: "collections",
+		mo:   protoyaml.MarshalOptions{UseProtoNames: true, UseEnumNumbers: true},
+		input: &testpb.TestAllTypes{
+			RepeatedInt32:      []int32{1, 2},
+			RepeatedNestedEnum: []testpb.TestAllTypes_NestedEnum{testpb.TestAllTypes_BAZ},
+			MapStringString:    map[string]string{"b": "line\nbreak", "a": ""},
+			RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+				{A: 1, Corecursive: &testpb.TestAllTypes{RepeatedString: []string{"x"}}},
+				{},
+			},
+		},
+		want: `repeated_int32:
+  - 1
+  - 2
+repeated_nested_message:
+  - a: 1
+    corecursive:
+      repeated_string:
+        - x
+  - {}
+repeated_nested_enum:
+  - 2
+map_string_string:
+  a: ""
+  b: "line\nbreak"
+`,
+	}, {
+		desc: "well-known types",
+		input: &pb2.KnownTypes{
+			OptInt32:    wrapperspb.Int32(7),
+			OptDuration: durationpb.New(90e9),
+			OptStruct: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"null": structpb.NewNullValue(),
+				"list": structpb.NewListValue(&structpb.ListValue{}),
+			}},
+		},
+		want: `optInt32: 7
+optDuration: 90s
+optStruct:
+  list: []
+  "null": null
+`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.mo.Marshal(tt.input)
+			if err != nil {
+				t.Fatalf("Marshal() error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, string(b)); diff != "" {
+				t.Errorf("Marshal() mismatch (-want +got):\n%s", diff)
+			}
+
+			got := tt.input.ProtoReflect().New().Interface()
+			if err := protoyaml.Unmarshal(b, got); err != nil {
+				t.Fatalf("Unmarshal() error: %v", err)
+			}
+			if diff := cmp.Diff(tt.input, got, protocmp.Transform()); diff != "" {
+				t.Errorf("round trip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMarshalRequired(t *testing.T) {
+	if _, err := protoyaml.Marshal(&pb2.PartialRequired{}); err == nil {
+		t.Error("Marshal() of message with unset required field succeeded, want error")
+	}
+	b, err := protoyaml.MarshalOptions{AllowPartial: true}.Marshal(&pb2.PartialRequired{})
+	if err != nil || string(b) != "{}\n" {
+		t.Errorf("Marshal() with AllowPartial = %q, %v, want %q", b, err, "{}\n")
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package schemaregistry resolves message types from a schema registry
+// and frames messages with the registry's wire header.
+//
+// The registry is accessed using the REST API implemented by the Confluent
+// Schema Registry and compatible registries (such as the Buf Schema Registry),
+// and schemas are retrieved as serialized FileDescriptorProto messages.
+// Messages are framed as produced and consumed by the Confluent Kafka
+// serializers: a header identifying the schema and message type
+// (see [AppendHeader]) followed by the wire-format message.
+package schemaregistry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Client resolves schemas from a schema registry.
+// Resolved schemas are cached for the lifetime of the client.
+//
+// A Client is safe for concurrent use and must not be copied after first use.
+type Client struct {
+	// URL is the base URL of the schema registry (e.g., "http://localhost:8081").
+	URL string
+
+	// HTTPClient is used to make requests to the registry.
+	// If nil, [http.DefaultClient] is used.
+	HTTPClient *http.Client
+
+	// Files resolves imports that are not listed as references of a schema,
+	// such as the well-known types.
+	// If nil, [protoregistry.GlobalFiles] is used.
+	Files *protoregistry.Files
+
+	mu      sync.Mutex
+	schemas map[int32]*schema
+	refs    map[reference]*schemaResponse // responses for referenced schemas
+}
+
+// schema is a resolved schema along with the files it depends on.
+type schema struct {
+	file  protoreflect.FileDescriptor
+	types *dynamicpb.Types
+}
+
+// reference is a reference from a schema to another schema in the registry.
+type reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// schemaResponse is the response to requests for a schema.
+type schemaResponse struct {
+	Schema     string      `json:"schema"`
+	SchemaType string      `json:"schemaType"`
+	References []reference `json:"references"`
+}
+
+// File returns the file descriptor for the schema with the given ID.
+// The file is resolved along with the schemas it references.
+func (c *Client) File(ctx context.Context, id int32) (protoreflect.FileDescriptor, error) {
+	s, err := c.schema(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.file, nil
+}
+
+// MessageType returns a dynamic message type for the message identified by
+// indexes within the schema with the given ID. See [MessageIndexes].
+func (c *Client) MessageType(ctx context.Context, id int32, indexes []int) (protoreflect.MessageType, error) {
+	s, err := c.schema(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	md, err := findMessage(s.file, indexes)
+	if err != nil {
+		return nil, err
+	}
+	return dynamicpb.NewMessageType(md), nil
+}
+
+// Unmarshal parses a message framed with the wire header,
+// returning a dynamic message of the type identified by the header.
+func (c *Client) Unmarshal(ctx context.Context, b []byte) (proto.Message, error) {
+	id, indexes, n, err := ParseHeader(b)
+	if err != nil {
+		return nil, err
+	}
+	s, err := c.schema(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	md, err := findMessage(s.file, indexes)
+	if err != nil {
+		return nil, err
+	}
+	m := dynamicpb.NewMessage(md)
+	if err := (proto.UnmarshalOptions{Resolver: s.types}).Unmarshal(b[n:], m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func findMessage(fd protoreflect.FileDescriptor, indexes []int) (protoreflect.MessageDescriptor, error) {
+	if len(indexes) == 0 {
+		indexes = []int{0}
+	}
+	var md protoreflect.MessageDescriptor
+	mds := fd.Messages()
+	for _, i := range indexes {
+		if i < 0 || i >= mds.Len() {
+			return nil, errors.New("schema %v: no message at indexes %v", fd.Path(), indexes)
+		}
+		md = mds.Get(i)
+		mds = md.Messages()
+	}
+	return md, nil
+}
+
+func (c *Client) schema(ctx context.Context, id int32) (*schema, error) {
+	c.mu.Lock()
+	s := c.schemas[id]
+	c.mu.Unlock()
+	if s != nil {
+		return s, nil
+	}
+
+	var resp schemaResponse
+	if err := c.get(ctx, fmt.Sprintf("/schemas/ids/%d?format=serialized", id), &resp); err != nil {
+		return nil, errors.Wrap(err, "schema %d", id)
+	}
+	fdp, err := decodeSchema(&resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "schema %d", id)
+	}
+	if fdp.GetName() == "" {
+		fdp.Name = proto.String(fmt.Sprintf("schema-%d.proto", id))
+	}
+
+	files := new(protoregistry.Files)
+	r := resolver{local: files, global: c.Files}
+	if r.global == nil {
+		r.global = protoregistry.GlobalFiles
+	}
+	if err := c.resolveReferences(ctx, r, resp.References); err != nil {
+		return nil, errors.Wrap(err, "schema %d", id)
+	}
+	fd, err := protodesc.NewFile(fdp, r)
+	if err != nil {
+		return nil, errors.Wrap(err, "schema %d", id)
+	}
+	if err := files.RegisterFile(fd); err != nil {
+		return nil, errors.Wrap(err, "schema %d", id)
+	}
+	s = &schema{file: fd, types: dynamicpb.NewTypes(files)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s2 := c.schemas[id]; s2 != nil {
+		return s2, nil // resolved concurrently
+	}
+	if c.schemas == nil {
+		c.schemas = make(map[int32]*schema)
+	}
+	c.schemas[id] = s
+	return s, nil
+}
+
+// resolveReferences registers the referenced schemas and their
+// transitive references in r.local.
+func (c *Client) resolveReferences(ctx context.Context, r resolver, refs []reference) error {
+	for _, ref := range refs {
+		if _, err := r.local.FindFileByPath(ref.Name); err == nil {
+			continue
+		}
+		fdp, refs, err := c.reference(ctx, ref)
+		if err != nil {
+			return err
+		}
+		if err := c.resolveReferences(ctx, r, refs); err != nil {
+			return err
+		}
+		fd, err := protodesc.NewFile(fdp, r)
+		if err != nil {
+			return err
+		}
+		if err := r.local.RegisterFile(fd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reference returns the schema for ref, along with its own references.
+func (c *Client) reference(ctx context.Context, ref reference) (*descriptorpb.FileDescriptorProto, []reference, error) {
+	c.mu.Lock()
+	resp := c.refs[ref]
+	c.mu.Unlock()
+	if resp == nil {
+		resp = new(schemaResponse)
+		path := fmt.Sprintf("/subjects/%s/versions/%d?format=serialized", url.PathEscape(ref.Subject), ref.Version)
+		if err := c.get(ctx, path, resp); err != nil {
+			return nil, nil, errors.Wrap(err, "reference %q", ref.Name)
+		}
+		c.mu.Lock()
+		if c.refs == nil {
+			c.refs = make(map[reference]*schemaResponse)
+		}
+		c.refs[ref] = resp
+		c.mu.Unlock()
+	}
+	fdp, err := decodeSchema(resp)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "reference %q", ref.Name)
+	}
+	// The file is imported by the name of the reference.
+	fdp.Name = proto.String(ref.Name)
+	return fdp, resp.References, nil
+}
+
+func decodeSchema(resp *schemaResponse) (*descriptorpb.FileDescriptorProto, error) {
+	if resp.SchemaType != "PROTOBUF" {
+		return nil, errors.New("unsupported schema type %q", resp.SchemaType)
+	}
+	b, err := base64.StdEncoding.DecodeString(resp.Schema)
+	if err != nil {
+		return nil, err
+	}
+	fdp := new(descriptorpb.FileDescriptorProto)
+	if err := proto.Unmarshal(b, fdp); err != nil {
+		return nil, err
+	}
+	return fdp, nil
+}
+
+// get makes a GET request to the registry and decodes the JSON response into v.
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.URL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json, application/json")
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &e) == nil && e.Message != "" {
+			return errors.New("registry returned %v: %v", resp.Status, e.Message)
+		}
+		return errors.New("registry returned %v", resp.Status)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// resolver resolves descriptors from the references of a schema,
+// falling back to a global set of files.
+type resolver struct {
+	local, global *protoregistry.Files
+}
+
+func (r resolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.local.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return r.global.FindFileByPath(path)
+}
+
+func (r resolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.local.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return r.global.FindDescriptorByName(name)
+}
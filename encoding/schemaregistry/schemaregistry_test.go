@@ -0,0 +1,223 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schemaregistry_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/encoding/schemaregistry"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestHeader(t *testing.T) {
+	for _, tt := range []struct {
+		id      int32
+		indexes []int
+		want    []byte
+	}{
+		{1, []int{0}, []byte{0, 0, 0, 0, 1, 0}},
+		{1, nil, []byte{0, 0, 0, 0, 1, 0}},
+		{0x01020304, []int{1}, []byte{0, 1, 2, 3, 4, 2, 2}},
+		{7, []int{0, 2}, []byte{0, 0, 0, 0, 7, 4, 0, 4}},
+	} {
+		b := schemaregistry.AppendHeader(nil, tt.id, tt.indexes)
+		if !cmp.Equal(b, tt.want) {
+			t.Errorf("AppendHeader(%d, %v) = %v, want %v", tt.id, tt.indexes, b, tt.want)
+		}
+		id, indexes, n, err := schemaregistry.ParseHeader(append(b, 0xff))
+		if err != nil {
+			t.Errorf("ParseHeader(%v): %v", b, err)
+			continue
+		}
+		wantIndexes := tt.indexes
+		if wantIndexes == nil {
+			wantIndexes = []int{0}
+		}
+		if id != tt.id || !cmp.Equal(indexes, wantIndexes) || n != len(b) {
+			t.Errorf("ParseHeader(%v) = %d, %v, %d; want %d, %v, %d", b, id, indexes, n, tt.id, wantIndexes, len(b))
+		}
+	}
+
+	for _, b := range [][]byte{
+		nil,
+		{0, 0, 0, 0},
+		{1, 0, 0, 0, 1, 0},
+		{0, 0, 0, 0, 1},
+		{0, 0, 0, 0, 1, 0x80},
+		{0, 0, 0, 0, 1, 1},
+		{0, 0, 0, 0, 1, 4, 0},
+		{0, 0, 0, 0, 1, 2, 1},
+	} {
+		if _, _, _, err := schemaregistry.ParseHeader(b); err == nil {
+			t.Errorf("ParseHeader(%v) succeeded, want error", b)
+		}
+	}
+}
+
+const commonProto = `
+	name: "common.proto"
+	package: "test.common"
+	syntax: "proto3"
+	message_type: [{
+		name: "Money"
+		field: [{name: "units" number: 1 label: LABEL_OPTIONAL type: TYPE_INT64 json_name: "units"}]
+	}]
+`
+
+const orderProto = `
+	name: "order.proto"
+	package: "test.order"
+	syntax: "proto3"
+	dependency: ["common.proto", "google/protobuf/timestamp.proto"]
+	message_type: [{
+		name: "Order"
+		field: [
+			{name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id"},
+			{name: "price" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".test.common.Money" json_name: "price"},
+			{name: "time" number: 3 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Timestamp" json_name: "time"}
+		]
+		nested_type: [{
+			name: "Item"
+			field: [{name: "sku" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "sku"}]
+		}]
+	}]
+`
+
+func serialize(t *testing.T, s string) string {
+	fdp := new(descriptorpb.FileDescriptorProto)
+	if err := prototext.Unmarshal([]byte(s), fdp); err != nil {
+		t.Fatal(err)
+	}
+	b, err := proto.Marshal(fdp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func newRegistry(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	responses := map[string]any{
+		"/schemas/ids/1": map[string]any{
+			"schemaType": "PROTOBUF",
+			"schema":     serialize(t, orderProto),
+			"references": []any{
+				map[string]any{"name": "common.proto", "subject": "common/v1", "version": 3},
+			},
+		},
+		"/subjects/common%2Fv1/versions/3": map[string]any{
+			"schemaType": "PROTOBUF",
+			"schema":     serialize(t, commonProto),
+		},
+		"/schemas/ids/2": map[string]any{
+			"schema": `{"type": "record"}`,
+		},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Query().Get("format") != "serialized" {
+			t.Errorf("request for %v without format=serialized", r.URL)
+		}
+		resp, ok := responses[r.URL.EscapedPath()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]any{"error_code": 40403, "message": "Schema not found"})
+			return
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClient(t *testing.T) {
+	var requests atomic.Int32
+	srv := newRegistry(t, &requests)
+	c := &schemaregistry.Client{URL: srv.URL}
+	ctx := context.Background()
+
+	fd, err := c.File(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fd.Messages().Get(0).FullName(), protoreflect.FullName("test.order.Order"); got != want {
+		t.Errorf("File(1) has message %v, want %v", got, want)
+	}
+
+	mt, err := c.MessageType(ctx, 1, []int{0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := mt.Descriptor().FullName(), protoreflect.FullName("test.order.Order.Item"); got != want {
+		t.Errorf("MessageType(1, [0 0]) = %v, want %v", got, want)
+	}
+	if _, err := c.MessageType(ctx, 1, []int{1}); err == nil {
+		t.Errorf("MessageType(1, [1]) succeeded, want error")
+	}
+
+	// Round-trip a message through the wire header.
+	md := fd.Messages().Get(0)
+	m := dynamicpb.NewMessage(md)
+	m.Set(md.Fields().ByName("id"), protoreflect.ValueOfString("order-1"))
+	price := m.Mutable(md.Fields().ByName("price")).Message()
+	price.Set(price.Descriptor().Fields().ByName("units"), protoreflect.ValueOfInt64(42))
+	time := m.Mutable(md.Fields().ByName("time")).Message()
+	proto.Merge(time.Interface(), timestamppb.New(timestamppb.Now().AsTime()))
+	b, err := schemaregistry.Marshal(1, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.Unmarshal(ctx, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Unmarshal(Marshal(m)) mismatch (-want +got):\n%s", diff)
+	}
+
+	// Schemas are cached after the first lookup.
+	if got, want := requests.Load(), int32(2); got != want {
+		t.Errorf("registry received %d requests, want %d", got, want)
+	}
+
+	for _, tt := range []struct {
+		id   int32
+		want string
+	}{
+		{2, "unsupported schema type"},
+		{3, "Schema not found"},
+	} {
+		if _, err := c.File(ctx, tt.id); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("File(%d) = %v, want error containing %q", tt.id, err, tt.want)
+		}
+	}
+}
+
+func TestMessageIndexes(t *testing.T) {
+	md := (&descriptorpb.FieldDescriptorProto{}).ProtoReflect().Descriptor()
+	want := []int{md.Index()}
+	if got := schemaregistry.MessageIndexes(md); !cmp.Equal(got, want) {
+		t.Errorf("MessageIndexes(%v) = %v, want %v", md.FullName(), got, want)
+	}
+	nested := (&descriptorpb.DescriptorProto_ExtensionRange{}).ProtoReflect().Descriptor()
+	want = []int{nested.Parent().Index(), nested.Index()}
+	if got := schemaregistry.MessageIndexes(nested); !cmp.Equal(got, want) {
+		t.Errorf("MessageIndexes(%v) = %v, want %v", nested.FullName(), got, want)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schemaregistry
+
+import (
+	"encoding/binary"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// magicByte is the first byte of every framed message.
+const magicByte = 0
+
+// AppendHeader appends the wire header identifying the schema id and
+// the message within it to b.
+//
+// The header consists of a zero magic byte, the schema ID as a 4-byte
+// big-endian integer, and the message indexes as a zigzag varint count
+// followed by each index as a zigzag varint. The common case of the first
+// message in the schema (indexes [0]) is encoded as a single zero byte.
+func AppendHeader(b []byte, id int32, indexes []int) []byte {
+	b = append(b, magicByte)
+	b = binary.BigEndian.AppendUint32(b, uint32(id))
+	if len(indexes) == 0 || (len(indexes) == 1 && indexes[0] == 0) {
+		return append(b, 0)
+	}
+	b = protowire.AppendVarint(b, protowire.EncodeZigZag(int64(len(indexes))))
+	for _, i := range indexes {
+		b = protowire.AppendVarint(b, protowire.EncodeZigZag(int64(i)))
+	}
+	return b
+}
+
+// ParseHeader parses the wire header at the start of b.
+// It returns the schema ID, the message indexes, and the length of the header.
+func ParseHeader(b []byte) (id int32, indexes []int, n int, err error) {
+	if len(b) < 5 {
+		return 0, nil, 0, errors.New("message header truncated")
+	}
+	if b[0] != magicByte {
+		return 0, nil, 0, errors.New("unknown magic byte %#02x", b[0])
+	}
+	id = int32(binary.BigEndian.Uint32(b[1:]))
+	n = 5
+
+	count, m := consumeZigZag(b[n:])
+	if m < 0 {
+		return 0, nil, 0, errors.New("invalid message indexes")
+	}
+	n += m
+	if count == 0 {
+		return id, []int{0}, n, nil
+	}
+	if count < 0 || count > int64(len(b)-n) {
+		return 0, nil, 0, errors.New("invalid message index count %d", count)
+	}
+	indexes = make([]int, count)
+	for i := range indexes {
+		v, m := consumeZigZag(b[n:])
+		if m < 0 || v < 0 {
+			return 0, nil, 0, errors.New("invalid message indexes")
+		}
+		indexes[i] = int(v)
+		n += m
+	}
+	return id, indexes, n, nil
+}
+
+func consumeZigZag(b []byte) (int64, int) {
+	v, n := protowire.ConsumeVarint(b)
+	if n < 0 {
+		return 0, n
+	}
+	return protowire.DecodeZigZag(v), n
+}
+
+// MessageIndexes returns the path of md within its file as a list of indexes,
+// starting with the index of the top-level message in the file and followed
+// by the index of each nested message.
+func MessageIndexes(md protoreflect.MessageDescriptor) []int {
+	var indexes []int
+	for d := protoreflect.Descriptor(md); ; d = d.Parent() {
+		if _, ok := d.(protoreflect.MessageDescriptor); !ok {
+			break
+		}
+		indexes = append([]int{d.Index()}, indexes...)
+	}
+	return indexes
+}
+
+// Marshal returns the wire-format encoding of m prefixed with the
+// header for schema id and the message indexes of m.
+func Marshal(id int32, m proto.Message) ([]byte, error) {
+	b := AppendHeader(nil, id, MessageIndexes(m.ProtoReflect().Descriptor()))
+	return proto.MarshalOptions{}.MarshalAppend(b, m)
+}
@@ -144,6 +144,7 @@ func TestIntegration(t *testing.T) {
 		if goVersion == golangLatest {
 			runGo("ProtoLegacyRace", command{}, "go", "test", "-race", "-tags", "protolegacy", "./...")
 			runGo("ProtoLegacy", command{}, "go", "test", "-tags", "protolegacy", "./...")
+			runGo("ProtoAllocs", command{}, "go", "test", "-tags", "protoallocs", "./proto/...", "./testing/protoallocs/...")
 			runGo("ProtocGenGo", command{Dir: "cmd/protoc-gen-go/testdata"}, "go", "test")
 			runGo("Conformance", command{Dir: "internal/conformance"}, "go", "test", "-execute")
 
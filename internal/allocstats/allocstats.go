@@ -0,0 +1,101 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package allocstats records the heap allocations of marshal and unmarshal
+// operations by message type. It is only used if the program is built
+// with the "protoallocs" tag.
+package allocstats
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Op is the kind of a recorded operation.
+type Op int
+
+const (
+	Marshal Op = iota + 1
+	Unmarshal
+)
+
+func (op Op) String() string {
+	switch op {
+	case Marshal:
+		return "marshal"
+	case Unmarshal:
+		return "unmarshal"
+	default:
+		return fmt.Sprintf("<unknown:%d>", int(op))
+	}
+}
+
+// Stats are the allocations of the operations of one kind
+// on messages of one type.
+type Stats struct {
+	MessageName protoreflect.FullName
+	Op          Op
+	Calls       uint64 // number of operations
+	Allocs      uint64 // number of heap objects allocated
+	Bytes       uint64 // number of heap bytes allocated
+}
+
+type key struct {
+	name protoreflect.FullName
+	op   Op
+}
+
+var (
+	mu    sync.Mutex
+	stats = make(map[key]*Stats)
+)
+
+// Sample is a reading of the allocation counters of the runtime.
+type Sample struct {
+	allocs, bytes uint64
+}
+
+// Start returns the allocation counters at the start of an operation.
+func Start() Sample {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return Sample{ms.Mallocs, ms.TotalAlloc}
+}
+
+// Record records the allocations of an operation on a message
+// of the named type since start.
+func Record(op Op, name protoreflect.FullName, start Sample) {
+	end := Start()
+	mu.Lock()
+	defer mu.Unlock()
+	s := stats[key{name, op}]
+	if s == nil {
+		s = &Stats{MessageName: name, Op: op}
+		stats[key{name, op}] = s
+	}
+	s.Calls++
+	s.Allocs += end.allocs - start.allocs
+	s.Bytes += end.bytes - start.bytes
+}
+
+// Snapshot returns a copy of the recorded statistics in an unspecified order.
+func Snapshot() []Stats {
+	mu.Lock()
+	defer mu.Unlock()
+	ss := make([]Stats, 0, len(stats))
+	for _, s := range stats {
+		ss = append(ss, *s)
+	}
+	return ss
+}
+
+// Reset discards the recorded statistics.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	stats = make(map[key]*Stats)
+}
@@ -0,0 +1,375 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// benchcmp runs the benchmarks of this module at two revisions
+// and reports the change in each measurement.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const modulePath = "google.golang.org/protobuf"
+
+var (
+	benchFlag    = flag.String("bench", ".", "run only the benchmarks matching the regular expression")
+	countFlag    = flag.Int("count", 5, "number of times to run each benchmark")
+	benchtime    = flag.String("benchtime", "", "run each benchmark for the given duration or iterations")
+	pkgsFlag     = flag.String("pkgs", "./internal/benchmarks/...,./proto", "comma-separated list of packages to benchmark")
+	upstreamFlag = flag.String("upstream", "", "compare against the given version of the upstream module instead of OLD")
+	verbose      = flag.Bool("v", false, "print the commands being run")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetOutput(os.Stderr)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS]... OLD [NEW]\n\n%s\n\n", filepath.Base(os.Args[0]), strings.Join([]string{
+			"Run the benchmarks of this module at the git revisions OLD and NEW",
+			"and report the change in time, bytes, and allocations per operation.",
+			"If NEW is not specified, the current working tree is used.",
+			"If -upstream is set, OLD is not specified and the benchmarks are",
+			"instead run against the given version of " + modulePath + ".",
+			"",
+			"Benchmarks are grouped by operation (e.g., Wire/Unmarshal) and",
+			"reported for each message shape (e.g., google_message1_proto2).",
+			"Benchmarks that are missing at either revision are skipped.",
+		}, "\n"))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := benchcmp(flag.Args()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func benchcmp(args []string) error {
+	repoRoot, err := gitOutput(".", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return err
+	}
+
+	var oldDir, oldName string
+	switch {
+	case *upstreamFlag != "" && len(args) <= 1:
+		oldName = modulePath + "@" + *upstreamFlag
+		oldDir, err = setupUpstream(repoRoot, *upstreamFlag)
+	case *upstreamFlag == "" && len(args) >= 1 && len(args) <= 2:
+		oldName = args[0]
+		oldDir, err = setupWorktree(repoRoot, args[0])
+		args = args[1:]
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	defer cleanup(repoRoot, oldDir)
+	if err != nil {
+		return err
+	}
+	newName, newDir := "working tree", repoRoot
+	if len(args) > 0 {
+		newName = args[0]
+		newDir, err = setupWorktree(repoRoot, args[0])
+		defer cleanup(repoRoot, newDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "benchmarking %v\n", oldName)
+	oldResults, err := runBenchmarks(oldDir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "benchmarking %v\n", newName)
+	newResults, err := runBenchmarks(newDir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("old: %v\nnew: %v\n\n", oldName, newName)
+	writeComparison(os.Stdout, oldResults, newResults)
+	return nil
+}
+
+// setupWorktree checks out the given revision into a temporary worktree
+// and returns its path.
+func setupWorktree(repoRoot, rev string) (string, error) {
+	dir, err := os.MkdirTemp("", "benchcmp")
+	if err != nil {
+		return "", err
+	}
+	if _, err := gitOutput(repoRoot, "worktree", "add", "--detach", dir, rev); err != nil {
+		return dir, err
+	}
+	return dir, linkBenchdata(repoRoot, dir)
+}
+
+// setupUpstream copies the given version of the upstream module into a
+// temporary directory and returns its path.
+//
+// The copy is made into a git repository since the benchmarks locate
+// their datasets relative to the repository root.
+func setupUpstream(repoRoot, version string) (string, error) {
+	cmd := exec.Command("go", "mod", "download", "-json", modulePath+"@"+version)
+	cmd.Dir = repoRoot
+	out, err := run(cmd)
+	if err != nil {
+		return "", err
+	}
+	var mod struct{ Dir, Error string }
+	if err := json.Unmarshal(out, &mod); err != nil {
+		return "", err
+	}
+	if mod.Error != "" {
+		return "", fmt.Errorf("go mod download: %v", mod.Error)
+	}
+
+	dir, err := os.MkdirTemp("", "benchcmp")
+	if err != nil {
+		return "", err
+	}
+	if _, err := run(exec.Command("cp", "-R", mod.Dir+"/.", dir)); err != nil {
+		return dir, err
+	}
+	// Files in the module cache are read-only.
+	if _, err := run(exec.Command("chmod", "-R", "u+w", dir)); err != nil {
+		return dir, err
+	}
+	if _, err := gitOutput(dir, "init", "-q"); err != nil {
+		return dir, err
+	}
+	return dir, linkBenchdata(repoRoot, dir)
+}
+
+// linkBenchdata makes the benchmark datasets of the main repository
+// available in dir, if any have been downloaded.
+func linkBenchdata(repoRoot, dir string) error {
+	src := filepath.Join(repoRoot, ".cache", "benchdata")
+	if _, err := os.Stat(src); err != nil {
+		return nil
+	}
+	dst := filepath.Join(dir, ".cache", "benchdata")
+	if err := os.MkdirAll(filepath.Dir(dst), 0775); err != nil {
+		return err
+	}
+	return os.Symlink(src, dst)
+}
+
+func cleanup(repoRoot, dir string) {
+	if dir == "" || dir == repoRoot {
+		return
+	}
+	// Removing a directory that is not a worktree fails harmlessly.
+	gitOutput(repoRoot, "worktree", "remove", "--force", dir)
+	os.RemoveAll(dir)
+}
+
+// runBenchmarks runs the benchmarks in the module rooted at dir.
+func runBenchmarks(dir string) (map[string]*result, error) {
+	args := []string{"test", "-run=^$", "-bench=" + *benchFlag, "-benchmem", "-count=" + strconv.Itoa(*countFlag), "-timeout=0"}
+	if *benchtime != "" {
+		args = append(args, "-benchtime="+*benchtime)
+	}
+	args = append(args, strings.Split(*pkgsFlag, ",")...)
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	out, err := run(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return parseBenchmarks(bytes.NewReader(out))
+}
+
+// result is the set of measurements for a benchmark across all runs.
+type result struct {
+	name    string
+	samples map[string][]float64 // keyed by unit (e.g., "ns/op")
+}
+
+// parseBenchmarks parses the output of "go test -bench".
+// Benchmark names are stripped of the GOMAXPROCS suffix.
+func parseBenchmarks(r io.Reader) (map[string]*result, error) {
+	results := make(map[string]*result)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		f := strings.Fields(s.Text())
+		if len(f) < 4 || !strings.HasPrefix(f[0], "Benchmark") || len(f)%2 != 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(f[1]); err != nil {
+			continue // not a result line (e.g., a log line)
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(f[0], "Benchmark"), "/")
+		if i := strings.LastIndexByte(name, '-'); i >= 0 {
+			if _, err := strconv.Atoi(name[i+1:]); err == nil {
+				name = name[:i]
+			}
+		}
+		res := results[name]
+		if res == nil {
+			res = &result{name: name, samples: make(map[string][]float64)}
+			results[name] = res
+		}
+		for i := 2; i < len(f); i += 2 {
+			v, err := strconv.ParseFloat(f[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid measurement %q in line %q", f[i], s.Text())
+			}
+			res.samples[f[i+1]] = append(res.samples[f[i+1]], v)
+		}
+	}
+	return results, s.Err()
+}
+
+// splitName splits a benchmark name into the operation and the message shape,
+// which is the last element of the name.
+func splitName(name string) (op, shape string) {
+	i := strings.LastIndexByte(name, '/')
+	if i < 0 {
+		return name, ""
+	}
+	return name[:i], name[i+1:]
+}
+
+// median returns the median of the samples.
+func median(xs []float64) float64 {
+	xs = append([]float64(nil), xs...)
+	sort.Float64s(xs)
+	if n := len(xs); n%2 == 0 {
+		return (xs[n/2-1] + xs[n/2]) / 2
+	}
+	return xs[len(xs)/2]
+}
+
+// units are the measurements reported, in order.
+var units = []string{"ns/op", "B/op", "allocs/op"}
+
+// writeComparison writes a table of the change in the median of each
+// measurement for the benchmarks present in both sets of results.
+func writeComparison(w io.Writer, oldResults, newResults map[string]*result) {
+	var names []string
+	for name := range oldResults {
+		if newResults[name] != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		opi, shapei := splitName(names[i])
+		opj, shapej := splitName(names[j])
+		if opi != opj {
+			return opi < opj
+		}
+		return shapei < shapej
+	})
+
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+	for _, unit := range units {
+		fmt.Fprintf(tw, "%v\told\tnew\tdelta\t\n", unit)
+		var lastOp string
+		var ratios []float64
+		for _, name := range names {
+			xs, ys := oldResults[name].samples[unit], newResults[name].samples[unit]
+			if len(xs) == 0 || len(ys) == 0 {
+				continue
+			}
+			op, shape := splitName(name)
+			if op != lastOp {
+				fmt.Fprintf(tw, "%v\t\t\t\t\n", op)
+				lastOp = op
+			}
+			x, y := median(xs), median(ys)
+			label := shape
+			if label == "" {
+				label = op
+			}
+			fmt.Fprintf(tw, "  %v\t%v\t%v\t%v\t\n", label, formatValue(x), formatValue(y), formatDelta(x, y))
+			if x > 0 && y > 0 {
+				ratios = append(ratios, y/x)
+			}
+		}
+		if len(ratios) > 0 {
+			fmt.Fprintf(tw, "geomean\t\t\t%v\t\n", formatDelta(1, geomean(ratios)))
+		}
+		fmt.Fprintf(tw, "\t\t\t\t\n")
+	}
+	tw.Flush()
+
+	// Trim the padding of the last column.
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if line != "" {
+			io.WriteString(w, strings.TrimRight(line, " \n")+"\n")
+		}
+	}
+}
+
+func geomean(xs []float64) float64 {
+	var sum float64
+	for _, x := range xs {
+		sum += math.Log(x)
+	}
+	return math.Exp(sum / float64(len(xs)))
+}
+
+// formatValue formats v with at most two decimal places,
+// or none for values large enough that they would only be noise.
+func formatValue(v float64) string {
+	prec := 2
+	if math.Abs(v) >= 100 {
+		prec = 0
+	}
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+func formatDelta(x, y float64) string {
+	switch {
+	case x == y:
+		return "~"
+	case x == 0:
+		return "+inf%"
+	default:
+		return fmt.Sprintf("%+.2f%%", (y-x)/x*100)
+	}
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := run(cmd)
+	return strings.TrimSpace(string(out)), err
+}
+
+// run runs cmd and returns its standard output.
+func run(cmd *exec.Cmd) ([]byte, error) {
+	if *verbose {
+		fmt.Fprintf(os.Stderr, "executing (%v): %v\n", cmd.Dir, strings.Join(cmd.Args, " "))
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("executing (%v): %v: %v\n%s%s", cmd.Dir, strings.Join(cmd.Args, " "), err, out, stderr.Bytes())
+	}
+	return out, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseBenchmarks(t *testing.T) {
+	const out = `goos: linux
+goarch: amd64
+pkg: google.golang.org/protobuf/internal/benchmarks
+BenchmarkWire/Unmarshal/google_message1_proto2-8   	 1000	      1200 ns/op	     640 B/op	      10 allocs/op
+BenchmarkWire/Unmarshal/google_message1_proto2-8   	 1000	      1000 ns/op	     640 B/op	      10 allocs/op
+BenchmarkWire/Unmarshal/google_message1_proto2-8   	 1000	      1100 ns/op	     640 B/op	      10 allocs/op
+Benchmark/Clone/google_message1_proto3-8           	 2000	       500 ns/op
+BenchmarkEmptyMessage/Wire/Marshal                 	 5000	      80.5 ns/op
+BenchmarkLogging-8 some log output
+PASS
+ok  	google.golang.org/protobuf/internal/benchmarks	1.234s
+`
+	got, err := parseBenchmarks(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string][]float64{
+		"Wire/Unmarshal/google_message1_proto2": {
+			"ns/op":     {1200, 1000, 1100},
+			"B/op":      {640, 640, 640},
+			"allocs/op": {10, 10, 10},
+		},
+		"Clone/google_message1_proto3": {"ns/op": {500}},
+		"EmptyMessage/Wire/Marshal":    {"ns/op": {80.5}},
+	}
+	gotSamples := make(map[string]map[string][]float64)
+	for name, res := range got {
+		gotSamples[name] = res.samples
+	}
+	if diff := cmp.Diff(want, gotSamples); diff != "" {
+		t.Errorf("parseBenchmarks() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := parseBenchmarks(strings.NewReader("BenchmarkFoo 1 bad ns/op\n")); err == nil {
+		t.Errorf("parseBenchmarks() with an invalid measurement succeeded, want error")
+	}
+}
+
+func TestWriteComparison(t *testing.T) {
+	oldResults, err := parseBenchmarks(strings.NewReader(`
+BenchmarkWire/Marshal/a-8  100  200 ns/op  64 B/op  2 allocs/op
+BenchmarkWire/Marshal/b-8  100  50 ns/op  0 B/op  0 allocs/op
+BenchmarkWire/Size/a-8  100  20 ns/op  0 B/op  0 allocs/op
+BenchmarkRemoved-8  100  20 ns/op  0 B/op  0 allocs/op
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newResults, err := parseBenchmarks(strings.NewReader(`
+BenchmarkWire/Marshal/a-8  100  100 ns/op  64 B/op  1 allocs/op
+BenchmarkWire/Marshal/b-8  100  50 ns/op  0 B/op  0 allocs/op
+BenchmarkWire/Size/a-8  100  25 ns/op  0 B/op  0 allocs/op
+BenchmarkAdded-8  100  20 ns/op  0 B/op  0 allocs/op
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got strings.Builder
+	writeComparison(&got, oldResults, newResults)
+	want := `ns/op         old  new  delta
+Wire/Marshal
+  a           200  100  -50.00%
+  b           50   50   ~
+Wire/Size
+  a           20   25   +25.00%
+geomean                 -14.50%
+
+B/op          old  new  delta
+Wire/Marshal
+  a           64   64   ~
+  b           0    0    ~
+Wire/Size
+  a           0    0    ~
+geomean                 ~
+
+allocs/op     old  new  delta
+Wire/Marshal
+  a           2    1    -50.00%
+  b           0    0    ~
+Wire/Size
+  a           0    0    ~
+geomean                 -50.00%
+
+`
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("writeComparison() mismatch (-want +got):\n%s", diff)
+	}
+}
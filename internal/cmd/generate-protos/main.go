@@ -16,11 +16,14 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/gendiff"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/internal/detrand"
 	"google.golang.org/protobuf/internal/editionssupport"
@@ -47,11 +50,17 @@ func init() {
 		// This is reasonable since we fully control the output.
 		detrand.Disable()
 
-		protogen.Options{}.Run(func(gen *protogen.Plugin) error {
+		var flags flag.FlagSet
+		var opts gengo.Options
+		opts.RegisterFlags(&flags)
+		protogen.Options{
+			ParamFunc:         flags.Set,
+			ImportRewriteFunc: opts.GenProtoRedirects.Rewrite,
+		}.Run(func(gen *protogen.Plugin) error {
 			for _, file := range gen.Files {
 				if file.Generate {
 					gengo.GenerateVersionMarkers = false
-					gengo.GenerateFile(gen, file)
+					opts.GenerateFile(gen, file)
 					generateIdentifiers(gen, file)
 					generateSourceContextStringer(gen, file)
 				}
@@ -65,6 +74,10 @@ func init() {
 
 var (
 	run        bool
+	parallel   int
+	color      bool
+	changesOut string
+	allChanges []*gendiff.Change // changes reported by syncOutput
 	protoRoot  string
 	repoRoot   string
 	modulePath string
@@ -82,6 +95,9 @@ var (
 func main() {
 	flag.BoolVar(&run, "execute", false, "Write generated files to destination.")
 	flag.StringVar(&protoRoot, "protoroot", os.Getenv("PROTOBUF_ROOT"), "The root of the protobuf source tree.")
+	flag.IntVar(&parallel, "parallel", runtime.NumCPU(), "The maximum number of concurrent protoc invocations.")
+	flag.BoolVar(&color, "color", false, "Color the differences reported without -execute.")
+	flag.StringVar(&changesOut, "changes", "", "Write the list of changed files as JSON to this file.")
 	flag.Parse()
 	protocPath, err := exec.LookPath("protoc")
 	if err != nil {
@@ -98,6 +114,12 @@ func main() {
 	generateLocalProtos()
 	generateRemoteProtos()
 	generateEditionsDefaults()
+
+	if changesOut != "" {
+		var b bytes.Buffer
+		check(gendiff.WriteJSON(&b, allChanges))
+		check(os.WriteFile(changesOut, b.Bytes(), 0664))
+	}
 }
 
 func generateEditionsDefaults() {
@@ -134,6 +156,7 @@ func generateLocalProtos() {
 		path     string
 		pkgPaths map[string]string // mapping of .proto path to Go package path
 		annotate map[string]bool   // .proto files to annotate
+		genOpts  map[string]string // additional generator options for .proto files
 		exclude  map[string]bool   // .proto files to exclude from generation
 	}{{
 		path: "cmd/protoc-gen-go/testdata",
@@ -141,6 +164,21 @@ func generateLocalProtos() {
 			"cmd/protoc-gen-go/testdata/nopackage/nopackage.proto": "google.golang.org/protobuf/cmd/protoc-gen-go/testdata/nopackage",
 		},
 		annotate: map[string]bool{"cmd/protoc-gen-go/testdata/annotations/annotations.proto": true},
+		genOpts: map[string]string{
+			"cmd/protoc-gen-go/testdata/copyaccessors/copyaccessors.proto":       "copy_accessors=true",
+			"cmd/protoc-gen-go/testdata/deprecationhook/deprecationhook.proto":   "copy_accessors=true,deprecation_hook=google.golang.org/protobuf/cmd/protoc-gen-go/testdata/deprecationhook/telemetry.Count",
+			"cmd/protoc-gen-go/testdata/enumspkg/enumspkg.proto":                 "enums_package=true",
+			"cmd/protoc-gen-go/testdata/extaccessors/extaccessors.proto":         "extension_accessors=true",
+			"cmd/protoc-gen-go/testdata/fielddescs/fielddescs.proto":             "field_descriptors=true",
+			"cmd/protoc-gen-go/testdata/fieldnums/fieldnums.proto":               "fieldnums=true",
+			"cmd/protoc-gen-go/testdata/genprotoredirect/genprotoredirect.proto": "Mgoogle/protobuf/field_mask.proto=google.golang.org/genproto/protobuf/field_mask;field_mask,genproto_redirect=google.golang.org/genproto/googleapis/example=google.golang.org/protobuf/cmd/protoc-gen-go/testdata/genprotoredirect",
+			"cmd/protoc-gen-go/testdata/gofix/gofix.proto":                       "go_fix=true",
+			"cmd/protoc-gen-go/testdata/jsontags/jsontags.proto":                 "json_tags=camel",
+			"cmd/protoc-gen-go/testdata/mapaccessors/mapaccessors.proto":         "map_accessors=true",
+			"cmd/protoc-gen-go/testdata/omitlegacy/omitlegacy.proto":             "omit_legacy_methods=true",
+			"cmd/protoc-gen-go/testdata/presence/presence.proto":                 "presence_accessors=true",
+			"cmd/protoc-gen-go/testdata/validate/validate.proto":                 "validate_methods=true",
+		},
 	}, {
 		path:    "internal/testprotos",
 		exclude: map[string]bool{"internal/testprotos/irregular/irregular.proto": true},
@@ -148,6 +186,7 @@ func generateLocalProtos() {
 		path: "src/",
 	}}
 	excludeRx := regexp.MustCompile(`legacy/.*/`)
+	r := newProtocRunner()
 	for _, d := range dirs {
 		subDirs := map[string]bool{}
 
@@ -174,7 +213,10 @@ func generateLocalProtos() {
 			if d.annotate[filepath.ToSlash(relPath)] {
 				opts += ",annotate_code"
 			}
-			protoc("-I"+filepath.Join(repoRoot, "src"), "-I"+filepath.Join(protoRoot, "src"), "-I"+repoRoot, "--go_out="+opts+":"+tmpDir, filepath.Join(repoRoot, relPath))
+			if genOpts := d.genOpts[filepath.ToSlash(relPath)]; genOpts != "" {
+				opts += "," + genOpts
+			}
+			r.protoc("-I"+filepath.Join(repoRoot, "src"), "-I"+filepath.Join(protoRoot, "src"), "-I"+repoRoot, "--go_out="+opts+":"+tmpDir, filepath.Join(repoRoot, relPath))
 			return nil
 		})
 
@@ -196,9 +238,11 @@ func generateLocalProtos() {
 			}...), "\n")
 			b, err := format.Source([]byte(s))
 			check(err)
+			check(os.MkdirAll(filepath.Join(tmpDir, filepath.FromSlash(d.path)), 0775))
 			check(os.WriteFile(filepath.Join(tmpDir, filepath.FromSlash(d.path+"/gen_test.go")), b, 0664))
 		}
 	}
+	r.wait()
 
 	syncOutput(repoRoot, tmpDir)
 }
@@ -267,27 +311,63 @@ func generateRemoteProtos() {
 			opts += fmt.Sprintf(",M%v=%v", file.path, file.goPkgPath)
 		}
 	}
+	r := newProtocRunner()
 	for _, f := range files {
-		protoc("-I"+protoRoot, "-I"+filepath.Join(protoRoot, f.prefix), "--go_out="+opts+":"+tmpDir, f.path)
+		r.protoc("-I"+protoRoot, "-I"+filepath.Join(protoRoot, f.prefix), "--go_out="+opts+":"+tmpDir, f.path)
 	}
+	r.wait()
 
 	syncOutput(repoRoot, tmpDir)
 }
 
-func protoc(args ...string) {
-	// TODO: Remove --experimental_allow_proto3_optional flag.
-	cmd := exec.Command(
-		"protoc",
-		"--plugin=protoc-gen-go="+os.Args[0],
-		"--experimental_allow_proto3_optional",
-		"--experimental_editions")
-	cmd.Args = append(cmd.Args, args...)
-	cmd.Env = append(os.Environ(), "RUN_AS_PROTOC_PLUGIN=1")
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		fmt.Printf("executing: %v\n%s\n", strings.Join(cmd.Args, " "), out)
+// protocRunner runs protoc concurrently, with at most parallel invocations
+// running at a time. Invocations must write to distinct output files.
+type protocRunner struct {
+	wg   sync.WaitGroup
+	sema chan struct{}
+
+	mu       sync.Mutex
+	failures []string // output of the failed invocations
+}
+
+func newProtocRunner() *protocRunner {
+	return &protocRunner{sema: make(chan struct{}, max(parallel, 1))}
+}
+
+// protoc starts an invocation of protoc with the given arguments.
+func (r *protocRunner) protoc(args ...string) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.sema <- struct{}{}
+		defer func() { <-r.sema }()
+
+		// TODO: Remove --experimental_allow_proto3_optional flag.
+		cmd := exec.Command(
+			"protoc",
+			"--plugin=protoc-gen-go="+os.Args[0],
+			"--experimental_allow_proto3_optional",
+			"--experimental_editions")
+		cmd.Args = append(cmd.Args, args...)
+		cmd.Env = append(os.Environ(), "RUN_AS_PROTOC_PLUGIN=1")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			r.mu.Lock()
+			r.failures = append(r.failures, fmt.Sprintf("executing: %v\n%s%v\n", strings.Join(cmd.Args, " "), out, err))
+			r.mu.Unlock()
+		}
+	}()
+}
+
+// wait waits for all invocations to complete,
+// reporting the output of all failed invocations together.
+func (r *protocRunner) wait() {
+	r.wg.Wait()
+	if len(r.failures) > 0 {
+		sort.Strings(r.failures)
+		fmt.Print(strings.Join(r.failures, "\n"))
+		panic(fmt.Sprintf("%d protoc invocations failed", len(r.failures)))
 	}
-	check(err)
 }
 
 // generateIdentifiers generates an internal package for descriptor.proto
@@ -458,7 +538,10 @@ func generateSourceContextStringer(gen *protogen.Plugin, file *protogen.File) {
 	}
 }
 
+// syncOutput copies the generated files in srcDir to dstDir if -execute is set,
+// and otherwise prints the differences between them as a single report.
 func syncOutput(dstDir, srcDir string) {
+	var changes []*gendiff.Change
 	filepath.Walk(srcDir, func(srcPath string, _ os.FileInfo, _ error) error {
 		if !strings.HasSuffix(srcPath, ".go") && !strings.HasSuffix(srcPath, ".meta") {
 			return nil
@@ -472,12 +555,24 @@ func syncOutput(dstDir, srcDir string) {
 				fmt.Println("#", relPath)
 			}
 		} else {
-			cmd := exec.Command("diff", dstPath, srcPath, "-N", "-u")
-			cmd.Stdout = os.Stdout
-			cmd.Run()
+			src, err := os.ReadFile(srcPath)
+			check(err)
+			dst, err := os.ReadFile(dstPath)
+			if os.IsNotExist(err) {
+				dst, err = nil, nil
+			}
+			check(err)
+			if c := gendiff.Compare(filepath.ToSlash(relPath), dst, src); c != nil {
+				changes = append(changes, c)
+			}
 		}
 		return nil
 	})
+	allChanges = append(allChanges, changes...)
+	if len(changes) > 0 {
+		check(gendiff.ReportOptions{Color: color}.Write(os.Stdout, changes))
+		fmt.Printf("# %d generated files differ; run with -execute to update them\n", len(changes))
+	}
 }
 
 func copyFile(dstPath, srcPath string) (changed bool) {
@@ -236,10 +236,10 @@ var ProtoKinds = []ProtoKind{
 	{
 		Name:       "String",
 		WireType:   WireBytes,
-		ToValue:    "protoreflect.ValueOfString(string(v))",
+		ToValue:    "protoreflect.ValueOfString(opts.internString(v))",
 		FromValue:  "v.String()",
 		GoType:     GoString,
-		ToGoType:   "string(v)",
+		ToGoType:   "opts.internString(v)",
 		FromGoType: "v",
 	},
 	{
@@ -297,7 +297,11 @@ func (o UnmarshalOptions) unmarshalScalar(b []byte, wtyp protowire.Type, fd prot
 			return protoreflect.Value{}, 0, errors.InvalidUTF8(string(fd.FullName()))
 		}
 		{{end -}}
+		{{if (eq .Name "String") -}}
+		return protoreflect.ValueOfString(o.internString(v)), n, nil
+		{{- else -}}
 		return {{.ToValue}}, n, nil
+		{{- end}}
 	{{- end}}
 	default:
 		return val, 0, errUnknown
@@ -347,6 +351,8 @@ func (o UnmarshalOptions) unmarshalList(b []byte, wtyp protowire.Type, list prot
 			return 0, err
 		}
 		list.Append(m)
+		{{- else if (eq .Name "String") -}}
+		list.Append(protoreflect.ValueOfString(o.internString(v)))
 		{{- else -}}
 		list.Append({{.ToValue}})
 		{{- end}}
@@ -13,17 +13,19 @@ import (
 	"encoding/binary"
 	"hash/fnv"
 	"os"
+	"sync/atomic"
 )
 
 // Disable disables detrand such that all functions returns the zero value.
-// This function is not concurrent-safe and must be called during program init.
+// It is safe for concurrent use, but values already derived from earlier
+// calls to the functions of this package are not affected.
 func Disable() {
-	randSeed = 0
+	randSeed.Store(0)
 }
 
 // Bool returns a deterministically random boolean.
 func Bool() bool {
-	return randSeed%2 == 1
+	return randSeed.Load()%2 == 1
 }
 
 // Intn returns a deterministically random integer between 0 and n-1, inclusive.
@@ -31,11 +33,15 @@ func Intn(n int) int {
 	if n <= 0 {
 		panic("must be positive")
 	}
-	return int(randSeed % uint64(n))
+	return int(randSeed.Load() % uint64(n))
 }
 
 // randSeed is a best-effort at an approximate hash of the Go binary.
-var randSeed = binaryHash()
+var randSeed atomic.Uint64
+
+func init() {
+	randSeed.Store(binaryHash())
+}
 
 func binaryHash() uint64 {
 	// Open the Go binary.
@@ -32,6 +32,7 @@ const (
 // responsible for producing valid sequences of JSON constructs and values.
 type Encoder struct {
 	indent   string
+	stable   bool
 	lastKind kind
 	indents  []byte
 	out      []byte
@@ -54,6 +55,12 @@ func NewEncoder(buf []byte, indent string) (*Encoder, error) {
 	return e, nil
 }
 
+// SetStable makes the output stable across builds of the program
+// by not inserting random whitespace.
+func (e *Encoder) SetStable() {
+	e.stable = true
+}
+
 // Bytes returns the content of the written bytes.
 func (e *Encoder) Bytes() []byte {
 	return e.out
@@ -187,6 +194,13 @@ func (e *Encoder) WriteUint(n uint64) {
 	e.out = strconv.AppendUint(e.out, n, 10)
 }
 
+// WriteNumber writes out the given JSON number literal as is,
+// such as the raw text of a Number token. It must be a valid JSON number.
+func (e *Encoder) WriteNumber(s string) {
+	e.prepareNext(scalar)
+	e.out = append(e.out, s...)
+}
+
 // StartObject writes out the '{' symbol.
 func (e *Encoder) StartObject() {
 	e.prepareNext(objectOpen)
@@ -238,7 +252,7 @@ func (e *Encoder) prepareNext(next kind) {
 			e.out = append(e.out, ',')
 			// For single-line output, add a random extra space after each
 			// comma to make output unstable.
-			if detrand.Bool() {
+			if !e.stable && detrand.Bool() {
 				e.out = append(e.out, ' ')
 			}
 		}
@@ -271,7 +285,7 @@ func (e *Encoder) prepareNext(next kind) {
 		e.out = append(e.out, ' ')
 		// For multi-line output, add a random extra space after key: to make
 		// output unstable.
-		if detrand.Bool() {
+		if !e.stable && detrand.Bool() {
 			e.out = append(e.out, ' ')
 		}
 	}
@@ -6,6 +6,7 @@ package text_test
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"strings"
 	"testing"
@@ -17,7 +18,9 @@ import (
 	"google.golang.org/protobuf/internal/flags"
 )
 
-var eofErr = text.ErrUnexpectedEOF.Error()
+// eofErr is the text of text.ErrUnexpectedEOF without its "proto:" prefix,
+// which changes when detrand is disabled by the init function of encode_test.go.
+var eofErr = io.ErrUnexpectedEOF.Error()
 
 type R struct {
 	// K is expected Kind of the returned Token object from calling Decoder.Read.
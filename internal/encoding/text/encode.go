@@ -34,6 +34,7 @@ type Encoder struct {
 	indent      string
 	delims      [2]byte
 	outputASCII bool
+	stable      bool
 }
 
 type encoderState struct {
@@ -76,6 +77,12 @@ func NewEncoder(buf []byte, indent string, delims [2]byte, outputASCII bool) (*E
 	return e, nil
 }
 
+// SetStable makes the output stable across builds of the program
+// by not inserting random whitespace.
+func (e *Encoder) SetStable() {
+	e.stable = true
+}
+
 // Bytes returns the content of the written bytes.
 func (e *Encoder) Bytes() []byte {
 	return e.out
@@ -226,7 +233,7 @@ func (e *Encoder) prepareNext(next encType) {
 		if e.lastType&(scalar|messageClose) != 0 && next == name {
 			e.out = append(e.out, ' ')
 			// Add a random extra space to make output unstable.
-			if detrand.Bool() {
+			if !e.stable && detrand.Bool() {
 				e.out = append(e.out, ' ')
 			}
 		}
@@ -238,7 +245,7 @@ func (e *Encoder) prepareNext(next encType) {
 	case e.lastType == name:
 		e.out = append(e.out, ' ')
 		// Add a random extra space after name: to make output unstable.
-		if detrand.Bool() {
+		if !e.stable && detrand.Bool() {
 			e.out = append(e.out, ' ')
 		}
 
@@ -0,0 +1,660 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package yaml
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/internal/errors"
+)
+
+// Parse parses a YAML document. An empty document is parsed as
+// a null scalar.
+func Parse(b []byte) (*Node, error) {
+	if !utf8.Valid(b) {
+		return nil, errors.New("invalid UTF-8 in YAML document")
+	}
+	s := strings.ReplaceAll(string(b), "\r\n", "\n")
+	s = strings.TrimPrefix(s, "\ufeff")
+	// The final line break terminates the last line rather than
+	// beginning an empty one.
+	p := &parser{lines: strings.Split(strings.TrimSuffix(s, "\n"), "\n")}
+
+	if err := p.nextContent(); err != nil {
+		return nil, err
+	}
+	if p.ln < len(p.lines) {
+		if line := p.lines[p.ln]; strings.HasPrefix(line, "%") {
+			return nil, p.newError(p.ln, 0, "directives are not supported")
+		}
+		if p.isMarker("---") {
+			p.col = 3
+			if err := p.skipSpace(); err != nil {
+				return nil, err
+			}
+			if p.atEOL() {
+				if err := p.nextLine(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	var n *Node
+	if p.ln >= len(p.lines) || p.isMarker("...") {
+		n = &Node{Kind: Scalar, Line: p.ln + 1, Column: 1}
+	} else {
+		var err error
+		if n, err = p.parseNode(-1, p.col); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.ln < len(p.lines) && p.isMarker("...") {
+		if err := p.nextLine(); err != nil {
+			return nil, err
+		}
+	}
+	if p.ln < len(p.lines) {
+		if p.isMarker("---") {
+			return nil, p.newError(p.ln, 0, "multiple documents are not supported")
+		}
+		return nil, p.newError(p.ln, p.col, "unexpected content")
+	}
+	return n, nil
+}
+
+type parser struct {
+	lines []string
+	ln    int // index of the current line
+	col   int // byte offset within the current line
+}
+
+func (p *parser) newError(ln, col int, f string, x ...any) error {
+	line := ""
+	if ln < len(p.lines) {
+		line = p.lines[ln]
+	}
+	column := utf8.RuneCountInString(line[:min(col, len(line))]) + 1
+	return errors.New("(line %d:%d): "+f, append([]any{ln + 1, column}, x...)...)
+}
+
+// rest returns the remainder of the current line.
+func (p *parser) rest() string {
+	if p.ln >= len(p.lines) {
+		return ""
+	}
+	return p.lines[p.ln][p.col:]
+}
+
+// isMarker reports whether the current line starts with the document marker.
+func (p *parser) isMarker(marker string) bool {
+	line := p.lines[p.ln]
+	return strings.HasPrefix(line, marker) && (len(line) == 3 || line[3] == ' ' || line[3] == '\t')
+}
+
+// skipSpace skips spaces and tabs within the current line.
+func (p *parser) skipSpace() error {
+	for p.col < len(p.lines[p.ln]) && (p.lines[p.ln][p.col] == ' ' || p.lines[p.ln][p.col] == '\t') {
+		p.col++
+	}
+	return nil
+}
+
+// atEOL reports whether the remainder of the current line is only
+// whitespace and a comment.
+func (p *parser) atEOL() bool {
+	s := strings.TrimLeft(p.rest(), " \t")
+	return s == "" || s[0] == '#'
+}
+
+// nextLine advances to the next line with content.
+func (p *parser) nextLine() error {
+	p.ln++
+	return p.nextContent()
+}
+
+// nextContent advances to the first content of the current or
+// a following line, skipping blank lines and comments.
+func (p *parser) nextContent() error {
+	for ; p.ln < len(p.lines); p.ln++ {
+		line := p.lines[p.ln]
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if s := strings.TrimLeft(line, " \t"); s == "" || s[0] == '#' {
+			continue
+		}
+		if line[indent] == '\t' {
+			return p.newError(p.ln, indent, "tabs are not allowed in indentation")
+		}
+		p.col = indent
+		return nil
+	}
+	p.col = 0
+	return nil
+}
+
+// indent returns the indentation of the current line.
+func (p *parser) indent() int {
+	line := p.lines[p.ln]
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// parseNode parses the node starting at the current position, which is
+// at column c of the current line. The node is nested within a block
+// collection at the given indentation. Afterwards, the parser is positioned
+// at the first content following the node.
+func (p *parser) parseNode(parent, c int) (*Node, error) {
+	s := p.rest()
+	switch {
+	case s[0] == '-' && (len(s) == 1 || s[1] == ' ' || s[1] == '\t'):
+		return p.parseSequence(c)
+	case s[0] == '|' || s[0] == '>':
+		return p.parseBlockScalar(parent)
+	case strings.IndexByte("&*!?", s[0]) >= 0:
+		return nil, p.newError(p.ln, p.col, "anchors, aliases, tags, and complex keys are not supported")
+	}
+	col := p.col
+	if _, ok, err := p.scanKey(); err != nil {
+		return nil, err
+	} else if ok {
+		p.col = col
+		return p.parseMapping(c)
+	}
+
+	n, err := p.parseFlowNode(true)
+	if err != nil {
+		return nil, err
+	}
+	if !p.atEOL() {
+		return nil, p.newError(p.ln, p.col, "unexpected content after value")
+	}
+	return n, p.nextLine()
+}
+
+// parseValue parses the value of a mapping entry or sequence item
+// whose indicator was just consumed. If the value is not on the same line,
+// it must be indented more than the collection, except that a sequence
+// may be at the same indentation as the key of a mapping entry.
+func (p *parser) parseValue(c int, allowSequence bool) (*Node, error) {
+	ln, col := p.ln, p.col
+	if err := p.skipSpace(); err != nil {
+		return nil, err
+	}
+	if !p.atEOL() {
+		// Block collections cannot start on the line of a mapping key.
+		if s := p.rest(); allowSequence && s[0] == '-' && (len(s) == 1 || s[1] == ' ') {
+			return nil, p.newError(p.ln, p.col, "unexpected sequence item")
+		}
+		if allowSequence {
+			col := p.col
+			if _, ok, err := p.scanKey(); err != nil {
+				return nil, err
+			} else if ok {
+				return nil, p.newError(p.ln, p.col-1, "unexpected mapping value")
+			}
+			p.col = col
+		}
+		return p.parseNode(c, p.col)
+	}
+	if err := p.nextLine(); err != nil {
+		return nil, err
+	}
+	if p.ln < len(p.lines) && !p.isMarker("---") && !p.isMarker("...") {
+		ind := p.indent()
+		s := p.rest()
+		isSeq := s[0] == '-' && (len(s) == 1 || s[1] == ' ')
+		if ind > c || (ind == c && allowSequence && isSeq) {
+			return p.parseNode(c, ind)
+		}
+	}
+	// The value is empty.
+	return &Node{Kind: Scalar, Line: ln + 1, Column: col + 1}, nil
+}
+
+// parseSequence parses a block sequence whose items are at column c.
+func (p *parser) parseSequence(c int) (*Node, error) {
+	n := &Node{Kind: Sequence, Line: p.ln + 1, Column: c + 1}
+	for {
+		p.col++ // consume "-"
+		item, err := p.parseValue(c, false)
+		if err != nil {
+			return nil, err
+		}
+		n.Content = append(n.Content, item)
+
+		if p.ln >= len(p.lines) || p.isMarker("---") || p.isMarker("...") {
+			return n, nil
+		}
+		switch ind := p.indent(); {
+		case ind > c:
+			return nil, p.newError(p.ln, ind, "unexpected indentation")
+		case ind < c:
+			return n, nil
+		}
+		if s := p.rest(); !(s[0] == '-' && (len(s) == 1 || s[1] == ' ')) {
+			return n, nil
+		}
+	}
+}
+
+// parseMapping parses a block mapping whose keys are at column c.
+func (p *parser) parseMapping(c int) (*Node, error) {
+	n := &Node{Kind: Mapping, Line: p.ln + 1, Column: c + 1}
+	for {
+		key, ok, err := p.scanKey()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, p.newError(p.ln, p.col, "expected a mapping key")
+		}
+		val, err := p.parseValue(c, true)
+		if err != nil {
+			return nil, err
+		}
+		n.Content = append(n.Content, key, val)
+
+		if p.ln >= len(p.lines) || p.isMarker("---") || p.isMarker("...") {
+			return n, nil
+		}
+		switch ind := p.indent(); {
+		case ind > c:
+			return nil, p.newError(p.ln, ind, "unexpected indentation")
+		case ind < c:
+			return n, nil
+		}
+		if s := p.rest(); s[0] == '-' && (len(s) == 1 || s[1] == ' ') {
+			return nil, p.newError(p.ln, p.col, "unexpected sequence item in mapping")
+		}
+	}
+}
+
+// scanKey scans a mapping key followed by a colon at the current position.
+// If there is a key, it consumes it along with the colon.
+func (p *parser) scanKey() (*Node, bool, error) {
+	s := p.rest()
+	key := &Node{Kind: Scalar, Line: p.ln + 1, Column: p.col + 1}
+	var n int
+	switch s[0] {
+	case '"', '\'':
+		// Quoted keys must be on a single line.
+		ln, col := p.ln, p.col
+		v, err := p.parseQuoted()
+		if err != nil || p.ln != ln {
+			p.ln, p.col = ln, col
+			return nil, false, nil
+		}
+		key.Value, key.Style = v, Quoted
+		n = p.col - col
+		p.col = col
+		rest := strings.TrimLeft(s[n:], " \t")
+		if !strings.HasPrefix(rest, ":") || (len(rest) > 1 && rest[1] != ' ' && rest[1] != '\t') {
+			return nil, false, nil
+		}
+		n = len(s) - len(rest)
+	case '[', '{', '#', '-':
+		if s[0] != '-' || len(s) == 1 || s[1] == ' ' {
+			return nil, false, nil
+		}
+		fallthrough
+	default:
+		for i := 0; i < len(s); i++ {
+			if s[i] == '#' && i > 0 && (s[i-1] == ' ' || s[i-1] == '\t') {
+				return nil, false, nil
+			}
+			if s[i] == ':' && (i+1 == len(s) || s[i+1] == ' ' || s[i+1] == '\t') {
+				n = i
+				key.Value = strings.TrimRight(s[:i], " \t")
+				break
+			}
+		}
+		if n == 0 {
+			return nil, false, nil
+		}
+	}
+	p.col += n + 1 // consume the key and colon
+	return key, true, nil
+}
+
+// parseBlockScalar parses a literal or folded block scalar whose content
+// is indented more than parent.
+func (p *parser) parseBlockScalar(parent int) (*Node, error) {
+	n := &Node{Kind: Scalar, Style: Quoted, Line: p.ln + 1, Column: p.col + 1}
+	folded := p.rest()[0] == '>'
+	p.col++
+	chomp := byte(0)
+	if s := p.rest(); s != "" && (s[0] == '-' || s[0] == '+') {
+		chomp = s[0]
+		p.col++
+	}
+	if !p.atEOL() {
+		return nil, p.newError(p.ln, p.col, "unsupported block scalar header")
+	}
+
+	// Collect the content lines, whose indentation is that of the first
+	// non-empty line.
+	var lines []string
+	indent := -1
+	p.ln++
+	for ; p.ln < len(p.lines); p.ln++ {
+		line := p.lines[p.ln]
+		trimmed := strings.TrimLeft(line, " ")
+		ind := len(line) - len(trimmed)
+		if trimmed == "" {
+			lines = append(lines, "")
+			continue
+		}
+		if indent < 0 {
+			if ind <= parent {
+				break
+			}
+			indent = ind
+		}
+		if ind < indent {
+			break
+		}
+		lines = append(lines, line[indent:])
+	}
+
+	// Trailing empty lines are subject to chomping.
+	var trailing int
+	for trailing < len(lines) && lines[len(lines)-1-trailing] == "" {
+		trailing++
+	}
+	content := lines[:len(lines)-trailing]
+
+	var b strings.Builder
+	for i, line := range content {
+		if i > 0 {
+			prev := content[i-1]
+			switch {
+			case !folded, line == "":
+				b.WriteByte('\n')
+			case prev == "":
+				// The line break preceding empty lines is discarded,
+				// except next to more-indented lines.
+				if strings.HasPrefix(line, " ") {
+					b.WriteByte('\n')
+				}
+			case strings.HasPrefix(line, " "), strings.HasPrefix(prev, " "):
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(line)
+	}
+	switch {
+	case len(content) == 0:
+		if chomp == '+' {
+			b.WriteString(strings.Repeat("\n", trailing))
+		}
+	case chomp == '-':
+	case chomp == '+':
+		b.WriteString(strings.Repeat("\n", trailing+1))
+	default:
+		b.WriteByte('\n')
+	}
+	n.Value = b.String()
+	return n, p.nextContent()
+}
+
+// parseFlowNode parses a flow collection or scalar. Plain scalars are
+// terminated by flow indicators only within flow collections.
+func (p *parser) parseFlowNode(block bool) (*Node, error) {
+	if err := p.skipFlowSpace(); err != nil {
+		return nil, err
+	}
+	if p.ln >= len(p.lines) {
+		return nil, p.newError(p.ln, 0, "unexpected end of document")
+	}
+	n := &Node{Line: p.ln + 1, Column: p.col + 1}
+	s := p.rest()
+	switch s[0] {
+	case '[':
+		n.Kind = Sequence
+		p.col++
+		for {
+			if err := p.skipFlowSpace(); err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(p.rest(), "]") {
+				p.col++
+				return n, nil
+			}
+			item, err := p.parseFlowNode(false)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, item)
+			if err := p.flowSeparator(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		n.Kind = Mapping
+		p.col++
+		for {
+			if err := p.skipFlowSpace(); err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(p.rest(), "}") {
+				p.col++
+				return n, nil
+			}
+			key, err := p.parseFlowNode(false)
+			if err != nil {
+				return nil, err
+			}
+			if key.Kind != Scalar {
+				return nil, p.newError(key.Line-1, key.Column-1, "complex keys are not supported")
+			}
+			if err := p.skipFlowSpace(); err != nil {
+				return nil, err
+			}
+			var val *Node
+			if strings.HasPrefix(p.rest(), ":") {
+				p.col++
+				if err := p.skipFlowSpace(); err != nil {
+					return nil, err
+				}
+				if s := p.rest(); s != "" && (s[0] == ',' || s[0] == '}') {
+					val = &Node{Kind: Scalar, Line: p.ln + 1, Column: p.col + 1}
+				} else if val, err = p.parseFlowNode(false); err != nil {
+					return nil, err
+				}
+			} else {
+				val = &Node{Kind: Scalar, Line: p.ln + 1, Column: p.col + 1}
+			}
+			n.Content = append(n.Content, key, val)
+			if err := p.flowSeparator('}'); err != nil {
+				return nil, err
+			}
+		}
+	case '"', '\'':
+		v, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
+		}
+		n.Kind, n.Style, n.Value = Scalar, Quoted, v
+		return n, nil
+	case ']', '}', ',', '#', '|', '>', '&', '*', '!', '%', '@', '`':
+		return nil, p.newError(p.ln, p.col, "unexpected %q", s[0])
+	}
+
+	// Plain scalar.
+	n.Kind = Scalar
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c == '#' && i > 0 && (s[i-1] == ' ' || s[i-1] == '\t') {
+			break
+		}
+		if c == ':' && (i+1 == len(s) || s[i+1] == ' ' || s[i+1] == '\t' || (!block && strings.IndexByte(",[]{}", s[i+1]) >= 0)) {
+			if block {
+				return nil, p.newError(p.ln, p.col+i, "unexpected mapping value")
+			}
+			break
+		}
+		if !block && strings.IndexByte(",[]{}", c) >= 0 {
+			break
+		}
+	}
+	n.Value = strings.TrimRight(s[:i], " \t")
+	p.col += i
+	return n, nil
+}
+
+// flowSeparator consumes the comma following an item of a flow
+// collection, or checks for the closing bracket.
+func (p *parser) flowSeparator(end byte) error {
+	if err := p.skipFlowSpace(); err != nil {
+		return err
+	}
+	s := p.rest()
+	switch {
+	case s == "":
+		return p.newError(p.ln, p.col, "unexpected end of document")
+	case s[0] == ',':
+		p.col++
+		return nil
+	case s[0] == end:
+		return nil
+	default:
+		return p.newError(p.ln, p.col, "expected ',' or %q", end)
+	}
+}
+
+// skipFlowSpace skips whitespace, line breaks, and comments within
+// a flow collection.
+func (p *parser) skipFlowSpace() error {
+	for p.ln < len(p.lines) {
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if !p.atEOL() {
+			return nil
+		}
+		p.ln++
+		p.col = 0
+	}
+	return nil
+}
+
+// parseQuoted parses a single- or double-quoted scalar, which may span
+// multiple lines.
+func (p *parser) parseQuoted() (string, error) {
+	ln, col := p.ln, p.col
+	quote := p.rest()[0]
+	p.col++
+	var b strings.Builder
+	for {
+		line := p.lines[p.ln]
+		for p.col < len(line) {
+			c := line[p.col]
+			switch {
+			case c == quote && quote == '\'' && strings.HasPrefix(line[p.col:], "''"):
+				b.WriteByte('\'')
+				p.col += 2
+			case c == quote:
+				p.col++
+				return b.String(), nil
+			case c == '\\' && quote == '"':
+				if p.col+1 == len(line) {
+					// An escaped line break is removed along with
+					// the leading whitespace of the next line.
+					p.col++
+					goto nextLine
+				}
+				n, err := p.unescape(&b, line[p.col:])
+				if err != nil {
+					return "", err
+				}
+				p.col += n
+			default:
+				b.WriteByte(c)
+				p.col++
+			}
+		}
+
+		// Fold the line break: trailing whitespace is removed, and the
+		// break is replaced with a space, or with the following empty lines.
+		{
+			s := strings.TrimRight(b.String(), " \t")
+			b.Reset()
+			b.WriteString(s)
+		}
+		if p.ln+1 < len(p.lines) && strings.TrimSpace(p.lines[p.ln+1]) == "" {
+			for p.ln+1 < len(p.lines) && strings.TrimSpace(p.lines[p.ln+1]) == "" {
+				b.WriteByte('\n')
+				p.ln++
+			}
+		} else {
+			b.WriteByte(' ')
+		}
+	nextLine:
+		p.ln++
+		if p.ln >= len(p.lines) {
+			return "", p.newError(ln, col, "unterminated quoted scalar")
+		}
+		line = p.lines[p.ln]
+		p.col = len(line) - len(strings.TrimLeft(line, " \t"))
+	}
+}
+
+// unescape decodes the escape sequence at the start of s into b,
+// returning its length.
+func (p *parser) unescape(b *strings.Builder, s string) (int, error) {
+	if len(s) < 2 {
+		return 0, p.newError(p.ln, p.col, "invalid escape sequence")
+	}
+	if r, ok := simpleEscapes[s[1]]; ok {
+		b.WriteString(r)
+		return 2, nil
+	}
+	var n int
+	switch s[1] {
+	case 'x':
+		n = 2
+	case 'u':
+		n = 4
+	case 'U':
+		n = 8
+	default:
+		return 0, p.newError(p.ln, p.col, "invalid escape sequence %q", s[:2])
+	}
+	if len(s) < 2+n {
+		return 0, p.newError(p.ln, p.col, "invalid escape sequence %q", s)
+	}
+	v, err := strconv.ParseUint(s[2:2+n], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(v)) {
+		return 0, p.newError(p.ln, p.col, "invalid escape sequence %q", s[:2+n])
+	}
+	b.WriteRune(rune(v))
+	return 2 + n, nil
+}
+
+var simpleEscapes = map[byte]string{
+	'0':  "\x00",
+	'a':  "\a",
+	'b':  "\b",
+	't':  "\t",
+	'\t': "\t",
+	'n':  "\n",
+	'v':  "\v",
+	'f':  "\f",
+	'r':  "\r",
+	'e':  "\x1b",
+	' ':  " ",
+	'"':  "\"",
+	'/':  "/",
+	'\\': "\\",
+	'N':  "\u0085",
+	'_':  " ",
+	'L':  " ",
+	'P':  " ",
+}
@@ -0,0 +1,180 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package yaml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// format formats a node compactly for comparison, with plain scalars
+// unquoted and quoted scalars quoted.
+func format(n *Node) string {
+	switch n.Kind {
+	case Scalar:
+		if n.Style == Quoted {
+			return strconv.Quote(n.Value)
+		}
+		if n.Value == "" {
+			return "~"
+		}
+		return n.Value
+	case Sequence:
+		var s []string
+		for _, c := range n.Content {
+			s = append(s, format(c))
+		}
+		return "[" + strings.Join(s, " ") + "]"
+	case Mapping:
+		var s []string
+		for i := 0; i < len(n.Content); i += 2 {
+			s = append(s, format(n.Content[i])+": "+format(n.Content[i+1]))
+		}
+		return "{" + strings.Join(s, " ") + "}"
+	}
+	return fmt.Sprintf("<kind %d>", n.Kind)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "~"},
+		{"# comment only\n", "~"},
+		{"hello", "hello"},
+		{"---\nhello\n...\n", "hello"},
+		{"--- hello", "hello"},
+		{"a: 1\nb: two\n", "{a: 1 b: two}"},
+		{"a:\n  b: 1\n  c:\n    d: 2\ne: 3\n", "{a: {b: 1 c: {d: 2}} e: 3}"},
+		{"- a\n- b\n", "[a b]"},
+		{"a:\n- 1\n- 2\nb: 3", "{a: [1 2] b: 3}"},
+		{"a:\n  - 1\n  - 2\n", "{a: [1 2]}"},
+		{"- a: 1\n  b: 2\n- c: 3\n", "[{a: 1 b: 2} {c: 3}]"},
+		{"- - a\n  - b\n- c\n", "[[a b] c]"},
+		{"a:\nb: ~\n", "{a: ~ b: ~}"},
+		{"-\n- x\n", "[~ x]"},
+		{"a: [1, 2, [3]]\n", "{a: [1 2 [3]]}"},
+		{"a: {b: 1, c: [x, y], d: }\n", "{a: {b: 1 c: [x y] d: ~}}"},
+		{"a: [\n  1, # one\n  2,\n]\n", "{a: [1 2]}"},
+		{"a: 'it''s'\nb: \"tab\\there\\u00e9\"\n", `{a: "it's" b: "tab\thereé"}`},
+		{"'a b': 1\n\"c:d\": 2\n", `{"a b": 1 "c:d": 2}`},
+		{"url: http://example.com:80/x # comment\n", "{url: http://example.com:80/x}"},
+		{"a: b:c\n", "{a: b:c}"},
+		{"a: \"one\n  two\n\n  three\"\n", `{a: "one two\nthree"}`},
+		{"a: \"x\\\n  y\"\n", `{a: "xy"}`},
+		{"a: |\n  line 1\n  line 2\n\nb: 1\n", `{a: "line 1\nline 2\n" b: 1}`},
+		{"a: |-\n  line 1\n   indented\n", `{a: "line 1\n indented"}`},
+		{"a: |+\n  x\n\n", `{a: "x\n\n"}`},
+		{"a: >\n  folded\n  text\n\n  para\n", `{a: "folded text\npara\n"}`},
+		{"- |\n  # not a comment\n- b\n", `["# not a comment\n" b]`},
+		{"a: 1\r\nb: 2\r\n", "{a: 1 b: 2}"},
+		{"-1: x\n", "{-1: x}"},
+	}
+	for _, tt := range tests {
+		n, err := Parse([]byte(tt.in))
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got := format(n); got != tt.want {
+			t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParsePositions(t *testing.T) {
+	n, err := Parse([]byte("a:\n  - x\n  - k: é\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	seq := n.Content[1]
+	item := seq.Content[1]
+	val := item.Content[1]
+	for _, tt := range []struct {
+		n            *Node
+		line, column int
+	}{
+		{n, 1, 1},
+		{n.Content[0], 1, 1},
+		{seq, 2, 3},
+		{seq.Content[0], 2, 5},
+		{item, 3, 5},
+		{val, 3, 8},
+	} {
+		if tt.n.Line != tt.line || tt.n.Column != tt.column {
+			t.Errorf("position of %v = %d:%d, want %d:%d", format(tt.n), tt.n.Line, tt.n.Column, tt.line, tt.column)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr string
+	}{
+		{"a: 1\n  b: 2\n", "(line 2:3)"},
+		{"a: 1\n- b\n", "(line 2:1): unexpected sequence item"},
+		{"a: [1, 2\n", "unexpected end of document"},
+		{"a: [\"a\" \"b\"]\n", "(line 1:9): expected ','"},
+		{"a: - b\n", "(line 1:4): unexpected sequence item"},
+		{"a: &x 1\n", "(line 1:4): anchors"},
+		{"a: !!str 1\n", "(line 1:4): anchors"},
+		{"a: \"x\n", "(line 1:4): unterminated"},
+		{"a: \"\\q\"\n", "invalid escape"},
+		{"a: 1\n---\nb: 2\n", "(line 2:1): multiple documents"},
+		{"%YAML 1.2\n---\n", "directives"},
+		{"a:\n\t- b\n", "(line 2:1): tabs"},
+		{"a: b: c\n", "(line 1:5): unexpected mapping value"},
+		{"\xff", "invalid UTF-8"},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.in))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Parse(%q) error = %v, want error containing %q", tt.in, err, tt.wantErr)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	for _, tt := range []struct {
+		in         string
+		want       Type
+		wantNumber string
+	}{
+		{"~", Null, ""},
+		{"null", Null, ""},
+		{"True", Bool, ""},
+		{"false", Bool, ""},
+		{"yes", String, ""},
+		{"12", Int, "12"},
+		{"+12", Int, "12"},
+		{"-007", Int, "-7"},
+		{"0x1F", Int, "31"},
+		{"0o17", Int, "15"},
+		{"1.5", Float, "1.5"},
+		{".5", Float, "0.5"},
+		{"1.", Float, "1"},
+		{"-1e3", Float, "-1e3"},
+		{".inf", Float, ""},
+		{"-.Inf", Float, ""},
+		{".nan", Float, ""},
+		{"1.2.3", String, ""},
+		{"0x", String, ""},
+	} {
+		n := &Node{Kind: Scalar, Value: tt.in}
+		if got := n.Resolve(); got != tt.want {
+			t.Errorf("Resolve(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+		if tt.want == Int || tt.want == Float {
+			got, ok := n.Number()
+			if ok != (tt.wantNumber != "") || got != tt.wantNumber {
+				t.Errorf("Number(%q) = (%q, %v), want %q", tt.in, got, ok, tt.wantNumber)
+			}
+		}
+	}
+}
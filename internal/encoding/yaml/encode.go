@@ -0,0 +1,115 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package yaml
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Marshal formats the node as a YAML document using block collections
+// indented by two spaces. Empty collections are formatted as flow
+// collections, and plain scalars as they are. Quoted scalars are
+// formatted as plain scalars unless that would change their value.
+func Marshal(n *Node) []byte {
+	var e encoder
+	e.writeBlock(n, 0, false)
+	return e.buf
+}
+
+type encoder struct {
+	buf []byte
+}
+
+// isInline reports whether the node is formatted on a single line.
+func isInline(n *Node) bool {
+	return n.Kind == Scalar || len(n.Content) == 0
+}
+
+// writeBlock writes n as a block node whose lines are indented by indent.
+// If indented is set, the indentation of the first line has been written.
+func (e *encoder) writeBlock(n *Node, indent int, indented bool) {
+	if isInline(n) {
+		if !indented {
+			e.writeIndent(indent)
+		}
+		e.writeInline(n)
+		e.buf = append(e.buf, '\n')
+		return
+	}
+	switch n.Kind {
+	case Mapping:
+		for i := 0; i < len(n.Content); i += 2 {
+			if i > 0 || !indented {
+				e.writeIndent(indent)
+			}
+			key, val := n.Content[i], n.Content[i+1]
+			e.writeInline(key)
+			e.buf = append(e.buf, ':')
+			if isInline(val) {
+				e.buf = append(e.buf, ' ')
+				e.writeInline(val)
+				e.buf = append(e.buf, '\n')
+			} else {
+				e.buf = append(e.buf, '\n')
+				e.writeBlock(val, indent+2, false)
+			}
+		}
+	case Sequence:
+		for i, item := range n.Content {
+			if i > 0 || !indented {
+				e.writeIndent(indent)
+			}
+			e.buf = append(e.buf, "- "...)
+			e.writeBlock(item, indent+2, true)
+		}
+	}
+}
+
+func (e *encoder) writeIndent(indent int) {
+	for i := 0; i < indent; i++ {
+		e.buf = append(e.buf, ' ')
+	}
+}
+
+// writeInline writes a scalar or empty collection.
+func (e *encoder) writeInline(n *Node) {
+	switch {
+	case n.Kind == Mapping:
+		e.buf = append(e.buf, "{}"...)
+	case n.Kind == Sequence:
+		e.buf = append(e.buf, "[]"...)
+	case n.Style == Plain && n.Value == "":
+		e.buf = append(e.buf, "null"...)
+	case n.Style == Plain || !needsQuotes(n.Value):
+		e.buf = append(e.buf, n.Value...)
+	default:
+		// The escape sequences produced by strconv.Quote are a subset of
+		// those of YAML double-quoted scalars.
+		e.buf = strconv.AppendQuote(e.buf, n.Value)
+	}
+}
+
+// needsQuotes reports whether the string s must be quoted,
+// since it is not parsed as the same string when written as a plain scalar.
+func needsQuotes(s string) bool {
+	if s == "" || (&Node{Kind: Scalar, Value: s}).Resolve() != String {
+		return true
+	}
+	if strings.IndexByte("-?:,[]{}#&*!|>'\"%@` \t", s[0]) >= 0 {
+		return true
+	}
+	if strings.HasSuffix(s, " ") || strings.HasSuffix(s, ":") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") {
+		return true
+	}
+	for _, r := range s {
+		if r == '\t' || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package yaml
+
+import "testing"
+
+func TestMarshal(t *testing.T) {
+	str := func(s string) *Node { return &Node{Kind: Scalar, Style: Quoted, Value: s} }
+	plain := func(s string) *Node { return &Node{Kind: Scalar, Value: s} }
+	seq := func(c ...*Node) *Node { return &Node{Kind: Sequence, Content: c} }
+	mapping := func(c ...*Node) *Node { return &Node{Kind: Mapping, Content: c} }
+
+	tests := []struct {
+		in   *Node
+		want string
+	}{
+		{plain(""), "null\n"},
+		{plain("12"), "12\n"},
+		{str("12"), "\"12\"\n"},
+		{str("hello world"), "hello world\n"},
+		{mapping(), "{}\n"},
+		{seq(), "[]\n"},
+		{
+			mapping(str("a"), plain("1"), str("b"), mapping(str("c"), str("true"), str("d"), seq())),
+			"a: 1\nb:\n  c: \"true\"\n  d: []\n",
+		},
+		{
+			seq(str("x"), mapping(str("a"), plain("1"), str("b"), plain("2")), seq(plain("1"), plain("2"))),
+			"- x\n- a: 1\n  b: 2\n- - 1\n  - 2\n",
+		},
+		{
+			mapping(str("list"), seq(mapping(str("k"), seq(str("v"))))),
+			"list:\n  - k:\n      - v\n",
+		},
+		{
+			mapping(str("key: value"), str(""), str("-x"), str("a #b"), str("line\nbreak"), str("tab\t")),
+			"\"key: value\": \"\"\n\"-x\": \"a #b\"\n\"line\\nbreak\": \"tab\\t\"\n",
+		},
+	}
+	for _, tt := range tests {
+		got := string(Marshal(tt.in))
+		if got != tt.want {
+			t.Errorf("Marshal(%v) = %q, want %q", format(tt.in), got, tt.want)
+			continue
+		}
+		n, err := Parse([]byte(got))
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", got, err)
+			continue
+		}
+		if format(n) != format(tt.in) && !equalValues(n, tt.in) {
+			t.Errorf("Parse(Marshal(%v)) = %v", format(tt.in), format(n))
+		}
+	}
+}
+
+// equalValues reports whether two nodes have the same structure and
+// scalar values, regardless of the style of scalars.
+func equalValues(x, y *Node) bool {
+	if x.Kind == Scalar && x.Resolve() == Null && y.Resolve() == Null {
+		return true
+	}
+	if x.Kind != y.Kind || x.Value != y.Value || len(x.Content) != len(y.Content) {
+		return false
+	}
+	if x.Kind == Scalar && x.Style != y.Style && (x.Resolve() != String || y.Resolve() != String) {
+		return false
+	}
+	for i := range x.Content {
+		if !equalValues(x.Content[i], y.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
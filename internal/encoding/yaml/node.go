@@ -0,0 +1,162 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package yaml implements the subset of YAML 1.2 used for configuration
+// files: block and flow collections, plain, quoted, and block scalars,
+// and comments. Anchors, aliases, tags, complex keys, and multiple
+// documents are not supported.
+package yaml
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of a node.
+type Kind uint8
+
+const (
+	Scalar Kind = iota + 1
+	Sequence
+	Mapping
+)
+
+// Style is the style of a scalar node.
+type Style uint8
+
+const (
+	// Plain scalars are unquoted. Their type is determined by their value,
+	// as reported by [Node.Resolve].
+	Plain Style = iota
+	// Quoted scalars are single-quoted, double-quoted, or block scalars,
+	// and are always strings.
+	Quoted
+)
+
+// Node is a node of a YAML document.
+type Node struct {
+	Kind  Kind
+	Style Style // for scalars
+
+	// Value is the value of a scalar node.
+	Value string
+
+	// Content holds the items of a sequence node, or the alternating keys
+	// and values of a mapping node.
+	Content []*Node
+
+	// Line and Column are the 1-based position of the node in the input.
+	Line, Column int
+}
+
+// Type is the type of a resolved scalar.
+type Type uint8
+
+const (
+	Null Type = iota + 1
+	Bool
+	Int
+	Float
+	String
+)
+
+var (
+	intPattern   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	octPattern   = regexp.MustCompile(`^0o[0-7]+$`)
+	hexPattern   = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+	floatPattern = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+
+	jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+)
+
+// Resolve reports the type of a scalar node according to the YAML 1.2
+// core schema. Quoted scalars are always strings.
+func (n *Node) Resolve() Type {
+	if n.Kind != Scalar {
+		return 0
+	}
+	if n.Style == Quoted {
+		return String
+	}
+	switch v := n.Value; {
+	case v == "", v == "~", v == "null", v == "Null", v == "NULL":
+		return Null
+	case v == "true", v == "True", v == "TRUE", v == "false", v == "False", v == "FALSE":
+		return Bool
+	case intPattern.MatchString(v), octPattern.MatchString(v), hexPattern.MatchString(v):
+		return Int
+	case floatPattern.MatchString(v), isInfOrNaN(v):
+		return Float
+	}
+	return String
+}
+
+func isInfOrNaN(v string) bool {
+	switch strings.TrimLeft(v, "+-") {
+	case ".inf", ".Inf", ".INF":
+		return true
+	case ".nan", ".NaN", ".NAN":
+		return v[0] == '.'
+	}
+	return false
+}
+
+// Bool returns the value of a scalar that resolves to a bool.
+func (n *Node) Bool() bool {
+	return n.Value[0] == 't' || n.Value[0] == 'T'
+}
+
+// Number returns the value of a scalar that resolves to an integer or
+// a float in the syntax of a JSON number, or reports false if the value
+// is not representable as a JSON number, as for infinities and NaN.
+// Octal and hexadecimal integers are converted to decimal.
+func (n *Node) Number() (string, bool) {
+	v := strings.TrimPrefix(n.Value, "+")
+	switch {
+	case isInfOrNaN(n.Value):
+		return "", false
+	case jsonNumberPattern.MatchString(v):
+		return v, true
+	case octPattern.MatchString(v), hexPattern.MatchString(v):
+		base := 8
+		if v[1] == 'x' {
+			base = 16
+		}
+		if u, err := strconv.ParseUint(v[2:], base, 64); err == nil {
+			return strconv.FormatUint(u, 10), true
+		}
+	case intPattern.MatchString(v):
+		// Remove leading zeros, which are not permitted in JSON.
+		neg := strings.HasPrefix(v, "-")
+		v = strings.TrimLeft(strings.TrimPrefix(v, "-"), "0")
+		if v == "" {
+			v = "0"
+		}
+		if neg {
+			v = "-" + v
+		}
+		return v, true
+	default:
+		// Normalize forms such as ".5" and "1." that are not valid JSON.
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return strconv.FormatFloat(f, 'g', -1, 64), true
+		}
+	}
+	// The value is out of range. Let the consumer report it.
+	return v, true
+}
+
+// NonFinite returns the JSON mapping string ("Infinity", "-Infinity", or
+// "NaN") for a float scalar for which [Node.Number] reports false.
+func (n *Node) NonFinite() string {
+	switch {
+	case strings.HasSuffix(strings.ToLower(n.Value), "nan"):
+		return "NaN"
+	case n.Value[0] == '-':
+		return "-Infinity"
+	default:
+		return "Infinity"
+	}
+}
@@ -23,7 +23,7 @@ func New(f string, x ...any) error {
 
 type prefixError struct{ s string }
 
-var prefix = func() string {
+func prefix() string {
 	// Deliberately introduce instability into the error message string to
 	// discourage users from performing error string comparisons.
 	if detrand.Bool() {
@@ -31,10 +31,10 @@ var prefix = func() string {
 	} else {
 		return "proto: " // use regular spaces (U+0020)
 	}
-}()
+}
 
 func (e *prefixError) Error() string {
-	return prefix + e.s
+	return prefix() + e.s
 }
 
 func (e *prefixError) Unwrap() error {
@@ -56,7 +56,7 @@ type wrapError struct {
 }
 
 func (e *wrapError) Error() string {
-	return format("%v%v: %v", prefix, e.s, e.err)
+	return format("%v%v: %v", prefix(), e.s, e.err)
 }
 
 func (e *wrapError) Unwrap() error {
@@ -44,10 +44,10 @@ func TestErrors(t *testing.T) {
 		wantText: "text: foreign",
 		is:       []error{foreign},
 	}} {
-		if got, want := test.err.Error(), prefix; !strings.HasPrefix(got, want) {
+		if got, want := test.err.Error(), prefix(); !strings.HasPrefix(got, want) {
 			t.Errorf("%v.Error() = %q, want prefix %q", test.what, got, want)
 		}
-		if got, want := test.err.Error(), prefix+test.wantText; got != want {
+		if got, want := test.err.Error(), prefix()+test.wantText; got != want {
 			t.Errorf("%v.Error() = %q, want %q", test.what, got, want)
 		}
 		if got, want := Is(test.err, Error), true; got != want {
@@ -87,6 +87,30 @@ func (p *EnumRanges) Has(n protoreflect.EnumNumber) bool {
 	}
 	return false
 }
+func (p *EnumRanges) HasRange(r [2]protoreflect.EnumNumber) bool {
+	// Use int64 arithmetic to avoid overflow at the end of the number range.
+	n, end := int64(r[0]), int64(r[1])
+	for _, q := range p.lazyInit().sorted {
+		switch {
+		case n > end:
+			return true
+		case int64(q[1]) < n:
+			continue // range below the numbers left to cover
+		case int64(q[0]) > n:
+			return false // gap before the range
+		}
+		n = int64(q[1]) + 1
+	}
+	return n > end
+}
+func (p *EnumRanges) Overlaps(r [2]protoreflect.EnumNumber) bool {
+	for _, q := range p.lazyInit().sorted {
+		if q[0] <= r[1] && r[0] <= q[1] {
+			return true
+		}
+	}
+	return false
+}
 func (p *EnumRanges) Format(s fmt.State, r rune)          { descfmt.FormatList(s, r, p) }
 func (p *EnumRanges) ProtoInternal(pragma.DoNotImplement) {}
 func (p *EnumRanges) lazyInit() *EnumRanges {
@@ -149,6 +173,29 @@ func (p *FieldRanges) Has(n protoreflect.FieldNumber) bool {
 	}
 	return false
 }
+func (p *FieldRanges) HasRange(r [2]protoreflect.FieldNumber) bool {
+	n, end := r[0], r[1]
+	for _, q := range p.lazyInit().sorted {
+		switch {
+		case n >= end:
+			return true
+		case q[1] <= n:
+			continue // range below the numbers left to cover
+		case q[0] > n:
+			return false // gap before the range
+		}
+		n = q[1]
+	}
+	return n >= end
+}
+func (p *FieldRanges) Overlaps(r [2]protoreflect.FieldNumber) bool {
+	for _, q := range p.lazyInit().sorted {
+		if q[0] < r[1] && r[0] < q[1] {
+			return true
+		}
+	}
+	return false
+}
 func (p *FieldRanges) Format(s fmt.State, r rune)          { descfmt.FormatList(s, r, p) }
 func (p *FieldRanges) ProtoInternal(pragma.DoNotImplement) {}
 func (p *FieldRanges) lazyInit() *FieldRanges {
@@ -298,6 +345,11 @@ type SourceLocations struct {
 	// is a child of this file descriptor.
 	File protoreflect.FileDescriptor
 
+	// Raw is the wire-encoded google.protobuf.SourceCodeInfo message
+	// that List was derived from. It is only populated when the original
+	// message must be reproduced verbatim (e.g., to retain unknown fields).
+	Raw []byte
+
 	once   sync.Once
 	byPath map[pathKey]int
 }
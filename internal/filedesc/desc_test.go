@@ -6,6 +6,7 @@ package filedesc_test
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -950,3 +951,48 @@ func TestMapsAreNotDelimited(t *testing.T) {
 		})
 	}
 }
+
+func TestRanges(t *testing.T) {
+	fieldRanges := &filedesc.FieldRanges{List: [][2]protoreflect.FieldNumber{{100, 200}, {200, 300}, {400, 401}}}
+	for _, tt := range []struct {
+		r                 [2]protoreflect.FieldNumber
+		hasRange, overlap bool
+	}{
+		{r: [2]protoreflect.FieldNumber{100, 300}, hasRange: true, overlap: true},
+		{r: [2]protoreflect.FieldNumber{150, 250}, hasRange: true, overlap: true},
+		{r: [2]protoreflect.FieldNumber{400, 401}, hasRange: true, overlap: true},
+		{r: [2]protoreflect.FieldNumber{5, 5}, hasRange: true, overlap: false},
+		{r: [2]protoreflect.FieldNumber{99, 101}, hasRange: false, overlap: true},
+		{r: [2]protoreflect.FieldNumber{250, 401}, hasRange: false, overlap: true},
+		{r: [2]protoreflect.FieldNumber{300, 400}, hasRange: false, overlap: false},
+		{r: [2]protoreflect.FieldNumber{1, 100}, hasRange: false, overlap: false},
+	} {
+		if got := fieldRanges.HasRange(tt.r); got != tt.hasRange {
+			t.Errorf("FieldRanges.HasRange(%v) = %v, want %v", tt.r, got, tt.hasRange)
+		}
+		if got := fieldRanges.Overlaps(tt.r); got != tt.overlap {
+			t.Errorf("FieldRanges.Overlaps(%v) = %v, want %v", tt.r, got, tt.overlap)
+		}
+	}
+
+	enumRanges := &filedesc.EnumRanges{List: [][2]protoreflect.EnumNumber{{10, 19}, {20, 29}, {math.MaxInt32, math.MaxInt32}}}
+	for _, tt := range []struct {
+		r                 [2]protoreflect.EnumNumber
+		hasRange, overlap bool
+	}{
+		{r: [2]protoreflect.EnumNumber{10, 29}, hasRange: true, overlap: true},
+		{r: [2]protoreflect.EnumNumber{15, 15}, hasRange: true, overlap: true},
+		{r: [2]protoreflect.EnumNumber{math.MaxInt32, math.MaxInt32}, hasRange: true, overlap: true},
+		{r: [2]protoreflect.EnumNumber{5, 4}, hasRange: true, overlap: false},
+		{r: [2]protoreflect.EnumNumber{9, 10}, hasRange: false, overlap: true},
+		{r: [2]protoreflect.EnumNumber{29, 30}, hasRange: false, overlap: true},
+		{r: [2]protoreflect.EnumNumber{30, math.MaxInt32 - 1}, hasRange: false, overlap: false},
+	} {
+		if got := enumRanges.HasRange(tt.r); got != tt.hasRange {
+			t.Errorf("EnumRanges.HasRange(%v) = %v, want %v", tt.r, got, tt.hasRange)
+		}
+		if got := enumRanges.Overlaps(tt.r); got != tt.overlap {
+			t.Errorf("EnumRanges.Overlaps(%v) = %v, want %v", tt.r, got, tt.overlap)
+		}
+	}
+}
@@ -22,3 +22,11 @@ const ProtoLegacy = protoLegacy
 // extension fields at unmarshal time, but defers creating the message
 // structure until the extension is first accessed.
 const LazyUnmarshalExtensions = ProtoLegacy
+
+// ProtoAllocs specifies whether to record the heap allocations of marshal
+// and unmarshal operations by message type, which are reported by the
+// testing/protoallocs package.
+//
+// This is disabled by default unless built with the "protoallocs" tag,
+// since recording the allocations of an operation stops the world.
+const ProtoAllocs = protoAllocs
@@ -0,0 +1,25 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package genid
+
+// GenProtoModule is the module that formerly hosted the generated packages
+// for several of the well-known types.
+const GenProtoModule = "google.golang.org/genproto"
+
+// GenProtoPackage describes a generated package of the genproto module
+// that was moved to this module.
+type GenProtoPackage struct {
+	ProtoPath    string // path of the .proto file (e.g., "google/protobuf/api.proto")
+	GenProtoPath string // import path of the package in the genproto module
+	GoImportPath string // import path of the package in this module
+}
+
+// GenProtoPackages lists the generated packages moved from the genproto module.
+var GenProtoPackages = []GenProtoPackage{
+	{File_google_protobuf_field_mask_proto, GenProtoModule + "/protobuf/field_mask", "google.golang.org/protobuf/types/known/fieldmaskpb"},
+	{File_google_protobuf_api_proto, GenProtoModule + "/protobuf/api", "google.golang.org/protobuf/types/known/apipb"},
+	{File_google_protobuf_type_proto, GenProtoModule + "/protobuf/ptype", "google.golang.org/protobuf/types/known/typepb"},
+	{File_google_protobuf_source_context_proto, GenProtoModule + "/protobuf/source_context", "google.golang.org/protobuf/types/known/sourcecontextpb"},
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package impl
+
+import (
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/encoding/protodelta"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Bytes fields with the (braincorp.protobuf.delta.encoded) option are
+// generated as a slice of numbers, which is stored in the field in the
+// encoding of the protodelta package.
+
+var (
+	int32SliceType   = reflect.TypeOf([]int32(nil))
+	int64SliceType   = reflect.TypeOf([]int64(nil))
+	uint32SliceType  = reflect.TypeOf([]uint32(nil))
+	uint64SliceType  = reflect.TypeOf([]uint64(nil))
+	float32SliceType = reflect.TypeOf([]float32(nil))
+	float64SliceType = reflect.TypeOf([]float64(nil))
+)
+
+// isDeltaType reports whether a bytes field with the Go type t
+// holds a delta encoded sequence of numbers.
+func isDeltaType(t reflect.Type) bool {
+	switch t {
+	case int32SliceType, int64SliceType, uint32SliceType, uint64SliceType, float32SliceType, float64SliceType:
+		return true
+	}
+	return false
+}
+
+// deltaFieldCoder returns pointer functions for a delta encoded bytes field.
+func deltaFieldCoder(fd protoreflect.FieldDescriptor, ft reflect.Type) (pointerCoderFuncs, bool) {
+	switch ft {
+	case int32SliceType:
+		return makeDeltaFieldCoder(fd, pointer.Int32Slice), true
+	case int64SliceType:
+		return makeDeltaFieldCoder(fd, pointer.Int64Slice), true
+	case uint32SliceType:
+		return makeDeltaFieldCoder(fd, pointer.Uint32Slice), true
+	case uint64SliceType:
+		return makeDeltaFieldCoder(fd, pointer.Uint64Slice), true
+	case float32SliceType:
+		return makeDeltaFieldCoder(fd, pointer.Float32Slice), true
+	case float64SliceType:
+		return makeDeltaFieldCoder(fd, pointer.Float64Slice), true
+	}
+	return pointerCoderFuncs{}, false
+}
+
+func makeDeltaFieldCoder[T protodelta.Number](fd protoreflect.FieldDescriptor, slice func(pointer) *[]T) pointerCoderFuncs {
+	// Without presence, the empty sequence is the zero value and is not encoded.
+	// With presence, a non-nil empty sequence is encoded as empty bytes.
+	noZero := !fd.HasPresence()
+	return pointerCoderFuncs{
+		size: func(p pointer, f *coderFieldInfo, opts marshalOptions) int {
+			v := *slice(p)
+			if noZero && len(v) == 0 {
+				return 0
+			}
+			return f.tagsize + protowire.SizeBytes(protodelta.Size(v))
+		},
+		marshal: func(b []byte, p pointer, f *coderFieldInfo, opts marshalOptions) ([]byte, error) {
+			v := *slice(p)
+			if noZero && len(v) == 0 {
+				return b, nil
+			}
+			b = protowire.AppendVarint(b, f.wiretag)
+			b = protowire.AppendVarint(b, uint64(protodelta.Size(v)))
+			return protodelta.Append(b, v), nil
+		},
+		unmarshal: func(b []byte, p pointer, wtyp protowire.Type, f *coderFieldInfo, opts unmarshalOptions) (out unmarshalOutput, err error) {
+			if wtyp != protowire.BytesType {
+				return out, errUnknown
+			}
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return out, errDecode
+			}
+			s, err := protodelta.Decode[T](v)
+			if err != nil {
+				return out, errDecode
+			}
+			if s == nil && !noZero {
+				s = []T{} // preserve presence
+			}
+			*slice(p) = s
+			out.n = n
+			return out, nil
+		},
+		merge: func(dst, src pointer, f *coderFieldInfo, opts mergeOptions) {
+			v := *slice(src)
+			if noZero && len(v) == 0 {
+				return
+			}
+			*slice(dst) = append([]T{}, v...)
+		},
+	}
+}
+
+// newDeltaConverter returns a converter between the Go type t of a
+// delta encoded bytes field and the encoded bytes, or nil if t is not
+// the type of a delta encoded field.
+func newDeltaConverter(t reflect.Type) Converter {
+	switch t {
+	case int32SliceType:
+		return deltaConverter[int32]{t}
+	case int64SliceType:
+		return deltaConverter[int64]{t}
+	case uint32SliceType:
+		return deltaConverter[uint32]{t}
+	case uint64SliceType:
+		return deltaConverter[uint64]{t}
+	case float32SliceType:
+		return deltaConverter[float32]{t}
+	case float64SliceType:
+		return deltaConverter[float64]{t}
+	}
+	return nil
+}
+
+type deltaConverter[T protodelta.Number] struct {
+	goType reflect.Type
+}
+
+func (c deltaConverter[T]) PBValueOf(v reflect.Value) protoreflect.Value {
+	if v.Type() != c.goType {
+		panic(fmt.Sprintf("invalid type: got %v, want %v", v.Type(), c.goType))
+	}
+	return protoreflect.ValueOfBytes(protodelta.Append(nil, v.Interface().([]T)))
+}
+func (c deltaConverter[T]) GoValueOf(v protoreflect.Value) reflect.Value {
+	s, err := protodelta.Decode[T](v.Bytes())
+	if err != nil {
+		panic(fmt.Sprintf("invalid value for %v: %v", c.goType, err))
+	}
+	return reflect.ValueOf(s)
+}
+func (c deltaConverter[T]) IsValidPB(v protoreflect.Value) bool {
+	b, ok := v.Interface().([]byte)
+	return ok && protodelta.Valid(b)
+}
+func (c deltaConverter[T]) IsValidGo(v reflect.Value) bool {
+	return v.IsValid() && v.Type() == c.goType
+}
+func (c deltaConverter[T]) New() protoreflect.Value  { return bytesZero }
+func (c deltaConverter[T]) Zero() protoreflect.Value { return bytesZero }
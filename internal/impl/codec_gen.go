@@ -5026,7 +5026,7 @@ func consumeString(b []byte, p pointer, wtyp protowire.Type, f *coderFieldInfo,
 	if n < 0 {
 		return out, errDecode
 	}
-	*p.String() = string(v)
+	*p.String() = opts.internString(v)
 	out.n = n
 	return out, nil
 }
@@ -5061,7 +5061,7 @@ func consumeStringValidateUTF8(b []byte, p pointer, wtyp protowire.Type, f *code
 	if !utf8.Valid(v) {
 		return out, errInvalidUTF8{}
 	}
-	*p.String() = string(v)
+	*p.String() = opts.internString(v)
 	out.n = n
 	return out, nil
 }
@@ -5153,7 +5153,7 @@ func consumeStringPtr(b []byte, p pointer, wtyp protowire.Type, f *coderFieldInf
 	if *vp == nil {
 		*vp = new(string)
 	}
-	**vp = string(v)
+	**vp = opts.internString(v)
 	out.n = n
 	return out, nil
 }
@@ -5193,7 +5193,7 @@ func consumeStringPtrValidateUTF8(b []byte, p pointer, wtyp protowire.Type, f *c
 	if *vp == nil {
 		*vp = new(string)
 	}
-	**vp = string(v)
+	**vp = opts.internString(v)
 	out.n = n
 	return out, nil
 }
@@ -5234,7 +5234,7 @@ func consumeStringSlice(b []byte, p pointer, wtyp protowire.Type, f *coderFieldI
 	if n < 0 {
 		return out, errDecode
 	}
-	*sp = append(*sp, string(v))
+	*sp = append(*sp, opts.internString(v))
 	out.n = n
 	return out, nil
 }
@@ -5272,7 +5272,7 @@ func consumeStringSliceValidateUTF8(b []byte, p pointer, wtyp protowire.Type, f
 		return out, errInvalidUTF8{}
 	}
 	sp := p.StringSlice()
-	*sp = append(*sp, string(v))
+	*sp = append(*sp, opts.internString(v))
 	out.n = n
 	return out, nil
 }
@@ -5306,7 +5306,7 @@ func consumeStringValue(b []byte, _ protoreflect.Value, _ protowire.Number, wtyp
 		return protoreflect.Value{}, out, errDecode
 	}
 	out.n = n
-	return protoreflect.ValueOfString(string(v)), out, nil
+	return protoreflect.ValueOfString(opts.internString(v)), out, nil
 }
 
 var coderStringValue = valueCoderFuncs{
@@ -5339,7 +5339,7 @@ func consumeStringValueValidateUTF8(b []byte, _ protoreflect.Value, _ protowire.
 		return protoreflect.Value{}, out, errInvalidUTF8{}
 	}
 	out.n = n
-	return protoreflect.ValueOfString(string(v)), out, nil
+	return protoreflect.ValueOfString(opts.internString(v)), out, nil
 }
 
 var coderStringValueValidateUTF8 = valueCoderFuncs{
@@ -5380,7 +5380,7 @@ func consumeStringSliceValue(b []byte, listv protoreflect.Value, _ protowire.Num
 	if n < 0 {
 		return protoreflect.Value{}, out, errDecode
 	}
-	list.Append(protoreflect.ValueOfString(string(v)))
+	list.Append(protoreflect.ValueOfString(opts.internString(v)))
 	out.n = n
 	return listv, out, nil
 }
@@ -32,6 +32,15 @@ type coderMessageInfo struct {
 	needsInitCheck     bool
 	isMessageSet       bool
 	numRequiredFields  uint8
+
+	// supportsReplace reports whether the message can be replaced in place
+	// by replacePointer, as used by proto.CloneInto.
+	supportsReplace bool
+
+	// supportsIntern reports whether the message is unmarshaled by
+	// unmarshalPointer, which can intern strings as used by
+	// proto.UnmarshalOptions.Interner.
+	supportsIntern bool
 }
 
 type coderFieldInfo struct {
@@ -64,6 +73,7 @@ func (mi *MessageInfo) makeCoderMethods(t reflect.Type, si structInfo) {
 	}
 
 	mi.coderFields = make(map[protowire.Number]*coderFieldInfo)
+	hasWeakFields := false
 	fields := mi.Desc.Fields()
 	preallocFields := make([]coderFieldInfo, fields.Len())
 	for i := 0; i < fields.Len(); i++ {
@@ -111,6 +121,7 @@ func (mi *MessageInfo) makeCoderMethods(t reflect.Type, si structInfo) {
 		case fd.IsWeak():
 			fieldOffset = si.weakOffset
 			funcs = makeWeakMessageFieldCoder(fd)
+			hasWeakFields = true
 		default:
 			fieldOffset = offsetOf(fs, mi.Exporter)
 			childMessage, funcs = fieldCoder(fd, ft)
@@ -181,12 +192,14 @@ func (mi *MessageInfo) makeCoderMethods(t reflect.Type, si structInfo) {
 	}
 	if mi.methods.Unmarshal == nil {
 		mi.methods.Flags |= protoiface.SupportUnmarshalDiscardUnknown
+		mi.supportsIntern = true
 		mi.methods.Unmarshal = mi.unmarshal
 	}
 	if mi.methods.CheckInitialized == nil {
 		mi.methods.CheckInitialized = mi.checkInitialized
 	}
 	if mi.methods.Merge == nil {
+		mi.supportsReplace = !hasWeakFields
 		mi.methods.Merge = mi.merge
 	}
 	if mi.methods.Equal == nil {
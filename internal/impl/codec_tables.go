@@ -38,6 +38,11 @@ func fieldCoder(fd protoreflect.FieldDescriptor, ft reflect.Type) (*MessageInfo,
 	switch {
 	case fd.IsMap():
 		return encoderFuncsForMap(fd, ft)
+	case fd.Kind() == protoreflect.BytesKind && fd.Cardinality() != protoreflect.Repeated && isDeltaType(ft):
+		// Delta encoded fields (see codec_delta.go).
+		if funcs, ok := deltaFieldCoder(fd, ft); ok {
+			return nil, funcs
+		}
 	case fd.Cardinality() == protoreflect.Repeated && !fd.IsPacked():
 		// Repeated fields (not packed).
 		if ft.Kind() != reflect.Slice {
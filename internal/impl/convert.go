@@ -131,6 +131,9 @@ func newSingularConverter(t reflect.Type, fd protoreflect.FieldDescriptor) Conve
 		if t.Kind() == reflect.String || (t.Kind() == reflect.Slice && t.Elem() == byteType) {
 			return &bytesConverter{t, defVal(fd, bytesZero)}
 		}
+		if c := newDeltaConverter(t); c != nil {
+			return c
+		}
 	case protoreflect.EnumKind:
 		// Handle enums, which must be a named int32 type.
 		if t.Kind() == reflect.Int32 {
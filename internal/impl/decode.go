@@ -25,7 +25,13 @@ type unmarshalOptions struct {
 		FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error)
 		FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error)
 	}
-	depth int
+	depth    int
+	interner stringInterner
+}
+
+// stringInterner converts the bytes of string values to strings.
+type stringInterner = interface {
+	InternString(b []byte) string
 }
 
 func (o unmarshalOptions) Options() proto.UnmarshalOptions {
@@ -34,6 +40,7 @@ func (o unmarshalOptions) Options() proto.UnmarshalOptions {
 		AllowPartial:   true,
 		DiscardUnknown: o.DiscardUnknown(),
 		Resolver:       o.resolver,
+		Interner:       o.interner,
 	}
 }
 
@@ -42,7 +49,15 @@ func (o unmarshalOptions) DiscardUnknown() bool {
 }
 
 func (o unmarshalOptions) IsDefault() bool {
-	return o.flags == 0 && o.resolver == protoregistry.GlobalTypes
+	return o.flags == 0 && o.resolver == protoregistry.GlobalTypes && o.interner == nil
+}
+
+// internString converts the bytes of a string value to a string.
+func (o unmarshalOptions) internString(b []byte) string {
+	if o.interner != nil {
+		return o.interner.InternString(b)
+	}
+	return string(b)
 }
 
 var lazyUnmarshalOptions = unmarshalOptions{
@@ -77,6 +92,48 @@ func (mi *MessageInfo) unmarshal(in protoiface.UnmarshalInput) (protoiface.Unmar
 	}, err
 }
 
+// ProtoUnmarshalInterned is a pseudo-internal API used by the proto package
+// to unmarshal the message with strings converted by interner. It reports
+// false if the message does not support interning, in which case it is
+// left unmodified.
+//
+// WARNING: This method is exempt from the compatibility promise and
+// may be removed in the future without warning.
+func (m *messageState) ProtoUnmarshalInterned(in protoiface.UnmarshalInput, interner stringInterner) (protoiface.UnmarshalOutput, bool, error) {
+	return m.messageInfo().unmarshalInterned(m.pointer(), in, interner)
+}
+
+// ProtoUnmarshalInterned is a pseudo-internal API used by the proto package
+// to unmarshal the message with strings converted by interner. It reports
+// false if the message does not support interning, in which case it is
+// left unmodified.
+//
+// WARNING: This method is exempt from the compatibility promise and
+// may be removed in the future without warning.
+func (m *messageReflectWrapper) ProtoUnmarshalInterned(in protoiface.UnmarshalInput, interner stringInterner) (protoiface.UnmarshalOutput, bool, error) {
+	return m.messageInfo().unmarshalInterned(m.pointer(), in, interner)
+}
+
+func (mi *MessageInfo) unmarshalInterned(p pointer, in protoiface.UnmarshalInput, interner stringInterner) (protoiface.UnmarshalOutput, bool, error) {
+	mi.init()
+	if !mi.supportsIntern {
+		return protoiface.UnmarshalOutput{}, false, nil
+	}
+	out, err := mi.unmarshalPointer(in.Buf, p, 0, unmarshalOptions{
+		flags:    in.Flags,
+		resolver: in.Resolver,
+		depth:    in.Depth,
+		interner: interner,
+	})
+	var flags protoiface.UnmarshalOutputFlags
+	if out.initialized {
+		flags |= protoiface.UnmarshalInitialized
+	}
+	return protoiface.UnmarshalOutput{
+		Flags: flags,
+	}, true, err
+}
+
 // errUnknown is returned during unmarshaling to indicate a parse error that
 // should result in a field being placed in the unknown fields section (for example,
 // when the wire type doesn't match) as opposed to the entire unmarshal operation
@@ -5,9 +5,7 @@
 package impl
 
 import (
-	"bytes"
-
-	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/encoding/unknownfields"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/runtime/protoiface"
 )
@@ -128,7 +126,7 @@ func equalMessage(mx, my protoreflect.Message) bool {
 		}
 	}
 
-	return equalUnknown(mx.GetUnknown(), my.GetUnknown())
+	return unknownfields.Equal(mx.GetUnknown(), my.GetUnknown())
 }
 
 func equalValue(fd protoreflect.FieldDescriptor, vx, vy protoreflect.Value) bool {
@@ -186,39 +184,3 @@ func equalMessageList(lx, ly protoreflect.List) bool {
 	}
 	return true
 }
-
-// equalUnknown compares unknown fields by direct comparison on the raw bytes
-// of each individual field number.
-// Copied from protoreflect.equalUnknown.
-func equalUnknown(x, y protoreflect.RawFields) bool {
-	if len(x) != len(y) {
-		return false
-	}
-	if bytes.Equal([]byte(x), []byte(y)) {
-		return true
-	}
-
-	mx := make(map[protoreflect.FieldNumber]protoreflect.RawFields)
-	my := make(map[protoreflect.FieldNumber]protoreflect.RawFields)
-	for len(x) > 0 {
-		fnum, _, n := protowire.ConsumeField(x)
-		mx[fnum] = append(mx[fnum], x[:n]...)
-		x = x[n:]
-	}
-	for len(y) > 0 {
-		fnum, _, n := protowire.ConsumeField(y)
-		my[fnum] = append(my[fnum], y[:n]...)
-		y = y[n:]
-	}
-	if len(mx) != len(my) {
-		return false
-	}
-
-	for k, v1 := range mx {
-		if v2, ok := my[k]; !ok || !bytes.Equal([]byte(v1), []byte(v2)) {
-			return false
-		}
-	}
-
-	return true
-}
@@ -33,6 +33,41 @@ func (mi *MessageInfo) merge(in protoiface.MergeInput) protoiface.MergeOutput {
 	return protoiface.MergeOutput{Flags: protoiface.MergeComplete}
 }
 
+// ProtoReplace is a pseudo-internal API used by proto.CloneInto to replace
+// the contents of the message with a deep copy of src, reusing the memory
+// already allocated within the message. It reports false if the message
+// does not support being replaced, in which case it is left unmodified.
+//
+// WARNING: This method is exempt from the compatibility promise and
+// may be removed in the future without warning.
+func (m *messageState) ProtoReplace(src protoreflect.Message) bool {
+	return m.messageInfo().replace(m.pointer(), src)
+}
+
+// ProtoReplace is a pseudo-internal API used by proto.CloneInto to replace
+// the contents of the message with a deep copy of src, reusing the memory
+// already allocated within the message. It reports false if the message
+// does not support being replaced, in which case it is left unmodified.
+//
+// WARNING: This method is exempt from the compatibility promise and
+// may be removed in the future without warning.
+func (m *messageReflectWrapper) ProtoReplace(src protoreflect.Message) bool {
+	return m.messageInfo().replace(m.pointer(), src)
+}
+
+func (mi *MessageInfo) replace(dst pointer, src protoreflect.Message) bool {
+	mi.init()
+	if !mi.supportsReplace {
+		return false
+	}
+	sp, ok := mi.getPointer(src)
+	if !ok {
+		return false
+	}
+	mi.replacePointer(dst, sp, mergeOptions{})
+	return true
+}
+
 func (mi *MessageInfo) mergePointer(dst, src pointer, opts mergeOptions) {
 	mi.init()
 	if dst.IsNil() {
@@ -52,32 +87,150 @@ func (mi *MessageInfo) mergePointer(dst, src pointer, opts mergeOptions) {
 		f.funcs.merge(dst.Apply(f.offset), sfptr, f, opts)
 	}
 	if mi.extensionOffset.IsValid() {
-		sext := src.Apply(mi.extensionOffset).Extensions()
-		dext := dst.Apply(mi.extensionOffset).Extensions()
-		if *dext == nil {
-			*dext = make(map[int32]ExtensionField)
-		}
-		for num, sx := range *sext {
-			xt := sx.Type()
-			xi := getExtensionFieldInfo(xt)
-			if xi.funcs.merge == nil {
-				continue
+		mergeExtensions(dst.Apply(mi.extensionOffset).Extensions(), src.Apply(mi.extensionOffset).Extensions(), opts)
+	}
+	if mi.unknownOffset.IsValid() {
+		su := mi.getUnknownBytes(src)
+		if su != nil && len(*su) > 0 {
+			du := mi.mutableUnknownBytes(dst)
+			*du = append(*du, *su...)
+		}
+	}
+}
+
+func mergeExtensions(dext, sext *map[int32]ExtensionField, opts mergeOptions) {
+	if *dext == nil {
+		*dext = make(map[int32]ExtensionField)
+	}
+	for num, sx := range *sext {
+		xt := sx.Type()
+		xi := getExtensionFieldInfo(xt)
+		if xi.funcs.merge == nil {
+			continue
+		}
+		dx := (*dext)[num]
+		var dv protoreflect.Value
+		if dx.Type() == sx.Type() {
+			dv = dx.Value()
+		}
+		if !dv.IsValid() && xi.unmarshalNeedsValue {
+			dv = xt.New()
+		}
+		dv = xi.funcs.merge(dv, sx.Value(), opts)
+		dx.Set(sx.Type(), dv)
+		(*dext)[num] = dx
+	}
+}
+
+// replacePointer replaces the contents of dst with a deep copy of src,
+// reusing the sub-messages, slices, and maps already allocated within dst.
+// The message must support being replaced (see supportsReplace).
+func (mi *MessageInfo) replacePointer(dst, src pointer, opts mergeOptions) {
+	mi.init()
+	if dst.IsNil() {
+		panic(fmt.Sprintf("invalid value: merging into nil message"))
+	}
+	if dst == src {
+		return
+	}
+	if src.IsNil() {
+		src = pointerOfValue(reflect.New(mi.GoReflectType.Elem()))
+	}
+	for _, f := range mi.orderedCoderFields {
+		if f.funcs.merge == nil {
+			continue
+		}
+		df, sf := dst.Apply(f.offset), src.Apply(f.offset)
+		if f.isPointer && sf.Elem().IsNil() && df.Elem().IsNil() {
+			continue
+		}
+		switch ft := f.ft; {
+		case f.mi != nil && ft.Kind() == reflect.Ptr:
+			// Singular message.
+			switch f.mi.init(); {
+			case sf.Elem().IsNil():
+				df.SetPointer(pointer{})
+			case df.Elem().IsNil() || !f.mi.supportsReplace:
+				df.SetPointer(pointerOfValue(reflect.New(f.mi.GoReflectType.Elem())))
+				f.mi.mergePointer(df.Elem(), sf.Elem(), opts)
+			default:
+				f.mi.replacePointer(df.Elem(), sf.Elem(), opts)
+			}
+		case f.mi != nil && ft.Kind() == reflect.Slice:
+			// Repeated message.
+			replaceMessageSlice(df, sf, f, opts)
+		case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Uint8:
+			// Singular bytes, where nil is distinct from empty for fields with presence.
+			switch sb, db := *sf.Bytes(), df.Bytes(); {
+			case sb == nil:
+				*db = nil
+			case *db == nil:
+				*db = append(emptyBuf[:], sb...)
+			default:
+				*db = append((*db)[:0], sb...)
 			}
-			dx := (*dext)[num]
-			var dv protoreflect.Value
-			if dx.Type() == sx.Type() {
-				dv = dx.Value()
+		case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Slice:
+			// Repeated bytes.
+			ss, ds := *sf.BytesSlice(), df.BytesSlice()
+			old := *ds
+			*ds = (*ds)[:0]
+			for i, sb := range ss {
+				db := emptyBuf[:]
+				if i < len(old) && old[i] != nil {
+					db = old[i][:0]
+				}
+				*ds = append(*ds, append(db, sb...))
 			}
-			if !dv.IsValid() && xi.unmarshalNeedsValue {
-				dv = xt.New()
+		case ft.Kind() == reflect.Slice:
+			// Repeated scalar.
+			dv, sv := df.AsValueOf(ft).Elem(), sf.AsValueOf(ft).Elem()
+			if n := sv.Len(); dv.Cap() < n {
+				dv.Set(reflect.MakeSlice(ft, n, n))
+			} else {
+				dv.SetLen(n)
 			}
-			dv = xi.funcs.merge(dv, sx.Value(), opts)
-			dx.Set(sx.Type(), dv)
-			(*dext)[num] = dx
+			reflect.Copy(dv, sv)
+		case ft.Kind() == reflect.Ptr && ft.Elem().Kind() != reflect.Struct:
+			// Singular scalar with presence.
+			dv, sv := df.AsValueOf(ft).Elem(), sf.AsValueOf(ft).Elem()
+			switch {
+			case sv.IsNil():
+				dv.Set(reflect.Zero(ft))
+			case dv.IsNil():
+				f.funcs.merge(df, sf, f, opts)
+			default:
+				dv.Elem().Set(sv.Elem())
+			}
+		case ft.Kind() == reflect.Map && f.mi != nil:
+			// Map with message values.
+			replaceMapOfMessage(df, sf, f, opts)
+		case ft.Kind() == reflect.Map:
+			dv := df.AsValueOf(ft).Elem()
+			if !dv.IsNil() {
+				dv.Clear()
+			}
+			f.funcs.merge(df, sf, f, opts)
+		case ft.Kind() == reflect.Interface || ft.Kind() == reflect.Ptr:
+			// Oneof, or a message without a MessageInfo.
+			df.AsValueOf(ft).Elem().Set(reflect.Zero(ft))
+			if !sf.Elem().IsNil() {
+				f.funcs.merge(df, sf, f, opts)
+			}
+		default:
+			// Singular scalar without presence.
+			df.AsValueOf(ft).Elem().Set(sf.AsValueOf(ft).Elem())
 		}
 	}
+	if mi.extensionOffset.IsValid() {
+		dext := dst.Apply(mi.extensionOffset).Extensions()
+		clear(*dext)
+		mergeExtensions(dext, src.Apply(mi.extensionOffset).Extensions(), opts)
+	}
 	if mi.unknownOffset.IsValid() {
 		su := mi.getUnknownBytes(src)
+		if du := mi.getUnknownBytes(dst); du != nil {
+			*du = (*du)[:0]
+		}
 		if su != nil && len(*su) > 0 {
 			du := mi.mutableUnknownBytes(dst)
 			*du = append(*du, *su...)
@@ -85,6 +238,66 @@ func (mi *MessageInfo) mergePointer(dst, src pointer, opts mergeOptions) {
 	}
 }
 
+// replaceMessageSlice replaces the repeated message field dst with a copy of src,
+// reusing the messages within dst.
+func replaceMessageSlice(dst, src pointer, f *coderFieldInfo, opts mergeOptions) {
+	ss := src.PointerSlice()
+	dv := dst.AsValueOf(f.ft).Elem()
+	old := dv.Len()
+	if dv.Cap() < len(ss) {
+		nv := reflect.MakeSlice(f.ft, len(ss), len(ss))
+		reflect.Copy(nv, dv)
+		dv.Set(nv)
+	} else {
+		// Release the messages beyond the new length.
+		ds := dst.PointerSlice()
+		for i := len(ss); i < old; i++ {
+			ds[i] = pointer{}
+		}
+		dv.SetLen(len(ss))
+	}
+	ds := dst.PointerSlice()
+	for i, sp := range ss {
+		if i < old && !ds[i].IsNil() && f.mi.supportsReplace {
+			f.mi.replacePointer(ds[i], sp, opts)
+			continue
+		}
+		dp := pointerOfValue(reflect.New(f.mi.GoReflectType.Elem()))
+		f.mi.mergePointer(dp, sp, opts)
+		ds[i] = dp
+	}
+}
+
+// replaceMapOfMessage replaces the map field dst with a copy of src,
+// reusing the message values of entries with the same key.
+func replaceMapOfMessage(dst, src pointer, f *coderFieldInfo, opts mergeOptions) {
+	dstm := dst.AsValueOf(f.ft).Elem()
+	srcm := src.AsValueOf(f.ft).Elem()
+	if f.mi.init(); dstm.Len() == 0 || !f.mi.supportsReplace {
+		if !dstm.IsNil() {
+			dstm.Clear()
+		}
+		mergeMapOfMessage(dst, src, f, opts)
+		return
+	}
+	iter := mapRange(dstm)
+	for iter.Next() {
+		if !srcm.MapIndex(iter.Key()).IsValid() {
+			dstm.SetMapIndex(iter.Key(), reflect.Value{})
+		}
+	}
+	iter = mapRange(srcm)
+	for iter.Next() {
+		if dv := dstm.MapIndex(iter.Key()); dv.IsValid() && !dv.IsNil() {
+			f.mi.replacePointer(pointerOfValue(dv), pointerOfValue(iter.Value()), opts)
+			continue
+		}
+		val := reflect.New(f.ft.Elem().Elem())
+		f.mi.mergePointer(pointerOfValue(val), pointerOfValue(iter.Value()), opts)
+		dstm.SetMapIndex(iter.Key(), val)
+	}
+}
+
 func mergeScalarValue(dst, src protoreflect.Value, opts mergeOptions) protoreflect.Value {
 	return src
 }
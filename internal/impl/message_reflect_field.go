@@ -256,6 +256,7 @@ func fieldInfoForScalar(fd protoreflect.FieldDescriptor, fs reflect.StructField,
 	ft := fs.Type
 	nullable := fd.HasPresence()
 	isBytes := ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Uint8
+	isDelta := fd.Kind() == protoreflect.BytesKind && isDeltaType(ft)
 	if nullable {
 		if ft.Kind() != reflect.Ptr && ft.Kind() != reflect.Slice {
 			// This never occurs for generated message types.
@@ -331,6 +332,9 @@ func fieldInfoForScalar(fd protoreflect.FieldDescriptor, fs reflect.StructField,
 					rv.Set(nilBytes) // do not preserve presence
 				}
 			}
+			if isDelta && rv.Len() == 0 && nullable {
+				rv.Set(reflect.MakeSlice(ft, 0, 0)) // preserve presence
+			}
 		},
 		newField: func() protoreflect.Value {
 			return conv.New()
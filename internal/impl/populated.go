@@ -0,0 +1,62 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package impl
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ProtoPopulatedFields is a pseudo-internal API used by the proto package
+// to compute the set of populated known fields without materializing values.
+// The result is stored in bits if it is large enough.
+//
+// WARNING: This method is exempt from the compatibility promise and
+// may be removed in the future without warning.
+func (m *messageState) ProtoPopulatedFields(bits []uint64) []uint64 {
+	return m.messageInfo().populated(m.pointer(), bits)
+}
+
+// ProtoPopulatedFields is a pseudo-internal API used by the proto package
+// to compute the set of populated known fields without materializing values.
+// The result is stored in bits if it is large enough.
+//
+// WARNING: This method is exempt from the compatibility promise and
+// may be removed in the future without warning.
+func (m *messageReflectWrapper) ProtoPopulatedFields(bits []uint64) []uint64 {
+	return m.messageInfo().populated(m.pointer(), bits)
+}
+
+func (mi *MessageInfo) populated(p pointer, bits []uint64) []uint64 {
+	mi.init()
+	n := (mi.Desc.Fields().Len() + 63) / 64
+	if cap(bits) < n {
+		bits = make([]uint64, n)
+	} else {
+		bits = bits[:n]
+		clear(bits)
+	}
+	if p.IsNil() {
+		return bits
+	}
+	for _, ri := range mi.rangeInfos {
+		var fd protoreflect.FieldDescriptor
+		switch ri := ri.(type) {
+		case *fieldInfo:
+			if !ri.has(p) {
+				continue
+			}
+			fd = ri.fieldDesc
+		case *oneofInfo:
+			n := ri.which(p)
+			if n == 0 {
+				continue
+			}
+			fd = ri.oneofDesc.Fields().ByNumber(n)
+		}
+		i := fd.Index()
+		bits[i/64] |= 1 << (i % 64)
+	}
+	return bits
+}
@@ -11,6 +11,7 @@ import (
 	"reflect"
 	"unicode/utf8"
 
+	"google.golang.org/protobuf/encoding/protodelta"
 	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/internal/encoding/messageset"
 	"google.golang.org/protobuf/internal/flags"
@@ -103,6 +104,7 @@ const (
 	validationTypeFixed64
 	validationTypeBytes
 	validationTypeUTF8String
+	validationTypeDelta
 	validationTypeMessageSetItem
 )
 
@@ -216,6 +218,9 @@ func newValidationInfo(fd protoreflect.FieldDescriptor, ft reflect.Type) validat
 				vi.typ = validationTypeFixed64
 			case protowire.BytesType:
 				vi.typ = validationTypeBytes
+				if isDeltaType(ft) {
+					vi.typ = validationTypeDelta
+				}
 			}
 		}
 	}
@@ -354,7 +359,7 @@ State:
 					ok = wtyp == protowire.Fixed32Type
 				case validationTypeFixed64:
 					ok = wtyp == protowire.Fixed64Type
-				case validationTypeBytes, validationTypeUTF8String, validationTypeMessage:
+				case validationTypeBytes, validationTypeUTF8String, validationTypeDelta, validationTypeMessage:
 					ok = wtyp == protowire.BytesType
 				case validationTypeGroup:
 					ok = wtyp == protowire.StartGroupType
@@ -482,6 +487,10 @@ State:
 					if !utf8.Valid(v) {
 						return out, ValidationInvalid
 					}
+				case validationTypeDelta:
+					if !protodelta.Valid(v) {
+						return out, ValidationInvalid
+					}
 				}
 			case protowire.Fixed32Type:
 				if len(b) < 4 {
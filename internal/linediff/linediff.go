@@ -0,0 +1,234 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package linediff computes line-based differences between two texts
+// and formats them in the unified diff format.
+package linediff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the operation of an edit.
+type Op byte
+
+const (
+	Equal  Op = ' '
+	Delete Op = '-'
+	Insert Op = '+'
+)
+
+// Edit is a line in an edit script.
+type Edit struct {
+	Op   Op
+	Line string
+}
+
+// Split splits s into lines, each of which retains its line terminator
+// except for a last line that lacks one.
+func Split(s string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// MaxEdits is the maximum number of inserted and deleted lines for which
+// [Lines] computes a minimal edit script. If a and b differ by more lines,
+// the differing lines following the common prefix and suffix are reported
+// as deleted and inserted in their entirety, which bounds the cost of
+// computing the edit script for dissimilar inputs.
+const MaxEdits = 1000
+
+// Lines returns an edit script transforming a into b using the algorithm
+// described in "An O(ND) Difference Algorithm and Its Variations"
+// by Eugene W. Myers. The edit script is minimal unless a and b differ
+// by more than [MaxEdits] lines.
+func Lines(a, b []string) []Edit {
+	// Trim the common prefix and suffix, which is typically most of the text.
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var edits []Edit
+	for _, line := range a[:prefix] {
+		edits = append(edits, Edit{Equal, line})
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if e := myers(ma, mb); e != nil || len(ma)+len(mb) == 0 {
+		edits = append(edits, e...)
+	} else {
+		for _, line := range ma {
+			edits = append(edits, Edit{Delete, line})
+		}
+		for _, line := range mb {
+			edits = append(edits, Edit{Insert, line})
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, Edit{Equal, line})
+	}
+	return edits
+}
+
+// myers returns a minimal edit script transforming a into b,
+// or nil if it requires more than MaxEdits insertions and deletions.
+func myers(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	maxD := min(n+m, MaxEdits)
+	offset := maxD + 1
+	v := make([]int, 2*offset+1)
+
+	// trace[d] holds the furthest reaching x on each diagonal k in [-d, d]
+	// after d edits, indexed by k+d.
+	var trace [][]int
+	for d := 0; ; d++ {
+		if d > maxD {
+			return nil
+		}
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // insertion
+			} else {
+				x = v[offset+k-1] + 1 // deletion
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				done = true
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		if done {
+			break
+		}
+	}
+
+	// Walk back through the trace to recover the edits in reverse order.
+	var edits []Edit
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1] // indexed by k+d-1
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && prev[k-1+d-1] < prev[k+1+d-1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := prev[prevK+d-1]
+		prevY := prevX - prevK
+
+		// The edit moves from (prevX, prevY) to (midX, midY),
+		// which is followed by a snake of equal lines to (x, y).
+		midX, midY := prevX+1, prevY
+		if prevK == k+1 {
+			midX, midY = prevX, prevY+1
+		}
+		for x > midX && y > midY {
+			edits = append(edits, Edit{Equal, a[x-1]})
+			x, y = x-1, y-1
+		}
+		if prevK == k+1 {
+			edits = append(edits, Edit{Insert, b[prevY]})
+		} else {
+			edits = append(edits, Edit{Delete, a[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		edits = append(edits, Edit{Equal, a[x-1]})
+		x, y = x-1, y-1
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// Unified formats the edits in the unified diff format with the given
+// number of unchanged context lines around each change. The header names
+// the old and new files as oldName and newName.
+//
+// Lines that lack a line terminator are marked as such, which permits
+// distinguishing texts that only differ in their final line terminator.
+func Unified(oldName, newName string, edits []Edit, context int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			i++
+			continue
+		}
+		// Extend the hunk until the changes are separated by more than
+		// twice the number of context lines.
+		start := max(i-context, 0)
+		end := i
+		for j := i; j < len(edits); j++ {
+			if edits[j].Op != Equal {
+				end = j + 1
+			} else if j-end >= 2*context {
+				break
+			}
+		}
+		end = min(end+context, len(edits))
+
+		oldLine, newLine := 1, 1
+		for _, e := range edits[:start] {
+			if e.Op != Insert {
+				oldLine++
+			}
+			if e.Op != Delete {
+				newLine++
+			}
+		}
+		var oldCount, newCount int
+		for _, e := range edits[start:end] {
+			if e.Op != Insert {
+				oldCount++
+			}
+			if e.Op != Delete {
+				newCount++
+			}
+		}
+		// An empty range is identified by the line preceding it.
+		if oldCount == 0 {
+			oldLine--
+		}
+		if newCount == 0 {
+			newLine--
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+		for _, e := range edits[start:end] {
+			b.WriteByte(byte(e.Op))
+			b.WriteString(e.Line)
+			if !strings.HasSuffix(e.Line, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return b.String()
+}
+
+func hunkRange(line, count int) string {
+	if count == 1 {
+		return fmt.Sprint(line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linediff
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		x, y string
+		want string
+	}{
+		{"", "", ""},
+		{"a", "", "-a"},
+		{"", "a", "+a"},
+		{"a b c", "a b c", " a b c"},
+		{"a b c", "a x c", " a-b+x c"},
+		{"a b c d", "b d e", "-a b-c d+e"},
+	}
+	for _, tt := range tests {
+		var got strings.Builder
+		for _, e := range Lines(strings.Fields(tt.x), strings.Fields(tt.y)) {
+			got.WriteByte(byte(e.Op))
+			got.WriteString(e.Line)
+		}
+		if got.String() != tt.want {
+			t.Errorf("Lines(%q, %q) = %q, want %q", tt.x, tt.y, got.String(), tt.want)
+		}
+	}
+}
+
+func TestLinesMinimal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randLines := func() []string {
+		lines := make([]string, r.Intn(12))
+		for i := range lines {
+			lines[i] = string(rune('a' + r.Intn(3)))
+		}
+		return lines
+	}
+	for i := 0; i < 1000; i++ {
+		a, b := randLines(), randLines()
+		var gotA, gotB []string
+		var numEdits int
+		for _, e := range Lines(a, b) {
+			if e.Op != Insert {
+				gotA = append(gotA, e.Line)
+			}
+			if e.Op != Delete {
+				gotB = append(gotB, e.Line)
+			}
+			if e.Op != Equal {
+				numEdits++
+			}
+		}
+		if strings.Join(gotA, ",") != strings.Join(a, ",") || strings.Join(gotB, ",") != strings.Join(b, ",") {
+			t.Fatalf("Lines(%q, %q) does not reconstruct its inputs: got %q, %q", a, b, gotA, gotB)
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); numEdits != want {
+			t.Fatalf("Lines(%q, %q) has %d edits, want %d", a, b, numEdits, want)
+		}
+	}
+}
+
+// lcsLength returns the length of the longest common subsequence of a and b.
+func lcsLength(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+	return dp[0][0]
+}
+
+func TestLinesBounded(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 2*MaxEdits; i++ {
+		a = append(a, fmt.Sprintf("a%d\n", i))
+		b = append(b, fmt.Sprintf("b%d\n", i))
+	}
+	a = append([]string{"same\n"}, append(a, "same\n")...)
+	b = append([]string{"same\n"}, append(b, "same\n")...)
+	edits := Lines(a, b)
+	if len(edits) != len(a)+len(b)-2 {
+		t.Fatalf("Lines() returned %d edits, want %d", len(edits), len(a)+len(b)-2)
+	}
+	for i, e := range edits[1 : len(edits)-1] {
+		want := Delete
+		if i >= len(a)-2 {
+			want = Insert
+		}
+		if e.Op != want {
+			t.Fatalf("edit %d = %q, want %q", i+1, e.Op, want)
+		}
+	}
+}
+
+func TestUnified(t *testing.T) {
+	edits := Lines(Split("a\nb\nc\n"), Split("a\nx\nc"))
+	got := Unified("a/f", "b/f", edits, 3)
+	want := `--- a/f
++++ b/f
+@@ -1,3 +1,3 @@
+ a
+-b
+-c
++x
++c
+\ No newline at end of file
+`
+	if got != want {
+		t.Errorf("Unified() = %q, want %q", got, want)
+	}
+}
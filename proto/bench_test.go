@@ -40,6 +40,29 @@ func BenchmarkEncode(b *testing.B) {
 	}
 }
 
+// BenchmarkSizeAndEncode benchmarks computing the size of all the test
+// messages followed by encoding them, with and without reusing the sizes.
+func BenchmarkSizeAndEncode(b *testing.B) {
+	for _, cached := range []bool{false, true} {
+		for _, test := range testValidMessages {
+			for _, want := range test.decodeTo {
+				opts := proto.MarshalOptions{AllowPartial: *allowPartial}
+				b.Run(fmt.Sprintf("UseCachedSize=%v/%s (%T)", cached, test.desc, want), func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						opts.UseCachedSize = false
+						opts.Size(want)
+						opts.UseCachedSize = cached
+						_, err := opts.Marshal(want)
+						if err != nil && !test.partial {
+							b.Fatal(err)
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
 // BenchmarkDecode benchmarks decoding all the test messages.
 func BenchmarkDecode(b *testing.B) {
 	for _, test := range testValidMessages {
@@ -5,12 +5,18 @@
 package proto
 
 import (
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/internal/encoding/text"
 	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/order"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/runtime/protoiface"
 )
 
 // CheckInitialized returns an error if any required fields in m are not set.
+// The error is a [*RequiredNotSetError] that lists every such field.
 func CheckInitialized(m Message) error {
 	// Treat a nil message interface as an "untyped" empty message,
 	// which we assume to have no required fields.
@@ -18,7 +24,170 @@ func CheckInitialized(m Message) error {
 		return nil
 	}
 
-	return checkInitialized(m.ProtoReflect())
+	return checkRequired(m.ProtoReflect())
+}
+
+// RequiredNotSetError is the error returned when required fields are not set
+// by CheckInitialized, and by Marshal, Unmarshal, and Sanitize unless partial
+// messages are allowed.
+type RequiredNotSetError struct {
+	// Fields are the required fields that are not set. The fields of a
+	// message are listed before the fields of its sub-messages, and both
+	// the fields and the sub-messages are ordered by field number,
+	// with list elements in index order and map entries in key order.
+	Fields []MissingField
+}
+
+// MissingField is a required field that is not set.
+type MissingField struct {
+	// Path is the path from the checked message to the field.
+	// Its last step accesses Field.
+	Path []PathStep
+
+	// Field is the descriptor of the field.
+	Field protoreflect.FieldDescriptor
+}
+
+// PathStep is a step of the path to a [MissingField]. It either accesses
+// a field, or indexes into the list or map accessed by the previous step.
+type PathStep struct {
+	// Field is the accessed field. It is nil for an index step.
+	Field protoreflect.FieldDescriptor
+
+	// Key is the list index, as an int64, or the map key of an index step.
+	Key protoreflect.MapKey
+}
+
+// String formats the path to the field in the syntax of
+// [google.golang.org/protobuf/reflect/protopath.Path.String],
+// such as "(pkg.Message).list[0].field".
+func (f MissingField) String() string {
+	md := f.Field.ContainingMessage()
+	if len(f.Path) > 0 {
+		md = f.Path[0].Field.ContainingMessage()
+	}
+	b := append([]byte{'('}, md.FullName()...)
+	b = append(b, ')')
+	var prev protoreflect.FieldDescriptor
+	for _, s := range f.Path {
+		if s.Field == nil {
+			b = append(b, '[')
+			if prev.IsList() {
+				b = strconv.AppendInt(b, s.Key.Int(), 10)
+			} else {
+				b = appendMapKey(b, s.Key)
+			}
+			b = append(b, ']')
+			continue
+		}
+		b = append(b, '.')
+		if s.Field.IsExtension() {
+			b = append(b, '(')
+			b = append(b, strings.Trim(s.Field.TextName(), "[]")...)
+			b = append(b, ')')
+		} else {
+			b = append(b, s.Field.TextName()...)
+		}
+		prev = s.Field
+	}
+	return string(b)
+}
+
+func appendMapKey(b []byte, k protoreflect.MapKey) []byte {
+	switch v := k.Interface().(type) {
+	case bool:
+		return strconv.AppendBool(b, v)
+	case int32:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(b, v, 10)
+	case uint32:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint64:
+		return strconv.AppendUint(b, v, 10)
+	case string:
+		return text.AppendString(b, v)
+	}
+	return b
+}
+
+func (e *RequiredNotSetError) Error() string {
+	if len(e.Fields) == 0 {
+		return errors.New("required fields not set").Error()
+	}
+	err := errors.RequiredNotSet(e.Fields[0].String())
+	if len(e.Fields) == 1 {
+		return err.Error()
+	}
+	paths := make([]string, len(e.Fields)-1)
+	for i, f := range e.Fields[1:] {
+		paths[i] = f.String()
+	}
+	return errors.New("%v, and %d more: %v", err, len(paths), strings.Join(paths, ", ")).Error()
+}
+
+// Unwrap returns the sentinel [Error].
+func (e *RequiredNotSetError) Unwrap() error {
+	return errors.Error
+}
+
+// checkRequired is like checkInitialized, but reports every required field
+// that is not set in a RequiredNotSetError.
+func checkRequired(m protoreflect.Message) error {
+	err := checkInitialized(m)
+	if err == nil {
+		return nil
+	}
+	if missing := appendMissingFields(nil, m, nil); len(missing) > 0 {
+		return &RequiredNotSetError{Fields: missing}
+	}
+	return err
+}
+
+// appendMissingFields appends the required fields that are not set in m,
+// which is located at path, and in its sub-messages.
+func appendMissingFields(missing []MissingField, m protoreflect.Message, path []PathStep) []MissingField {
+	md := m.Descriptor()
+	fds := md.Fields()
+	for i, nums := 0, md.RequiredNumbers(); i < nums.Len(); i++ {
+		fd := fds.ByNumber(nums.Get(i))
+		if !m.Has(fd) {
+			missing = append(missing, MissingField{Path: appendStep(path, PathStep{Field: fd}), Field: fd})
+		}
+	}
+	order.RangeFields(m, order.NumberFieldOrder, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		p := appendStep(path, PathStep{Field: fd})
+		switch {
+		case fd.IsList():
+			if fd.Message() == nil {
+				return true
+			}
+			for i, list := 0, v.List(); i < list.Len(); i++ {
+				missing = appendMissingFields(missing, list.Get(i).Message(), appendStep(p, PathStep{Key: protoreflect.ValueOfInt64(int64(i)).MapKey()}))
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() == nil {
+				return true
+			}
+			order.RangeEntries(v.Map(), order.GenericKeyOrder, func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				missing = appendMissingFields(missing, v.Message(), appendStep(p, PathStep{Key: k}))
+				return true
+			})
+		default:
+			if fd.Message() == nil {
+				return true
+			}
+			missing = appendMissingFields(missing, v.Message(), p)
+		}
+		return true
+	})
+	return missing
+}
+
+// appendStep returns a copy of path with the step appended,
+// which does not share storage with path.
+func appendStep(path []PathStep, s PathStep) []PathStep {
+	return append(path[:len(path):len(path)], s)
 }
 
 // CheckInitialized returns an error if any required fields in m are not set.
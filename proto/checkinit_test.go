@@ -5,10 +5,13 @@
 package proto_test
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/internal/flags"
 	"google.golang.org/protobuf/proto"
@@ -26,12 +29,12 @@ func TestCheckInitializedErrors(t *testing.T) {
 	}
 	tests := []test{{
 		m:    &testpb.TestRequired{},
-		want: `goproto.proto.test.TestRequired.required_field`,
+		want: `(goproto.proto.test.TestRequired).required_field`,
 	}, {
 		m: &testpb.TestRequiredForeign{
 			OptionalMessage: &testpb.TestRequired{},
 		},
-		want: `goproto.proto.test.TestRequired.required_field`,
+		want: `(goproto.proto.test.TestRequiredForeign).optional_message.required_field`,
 	}, {
 		m: &testpb.TestRequiredForeign{
 			RepeatedMessage: []*testpb.TestRequired{
@@ -39,22 +42,22 @@ func TestCheckInitializedErrors(t *testing.T) {
 				{},
 			},
 		},
-		want: `goproto.proto.test.TestRequired.required_field`,
+		want: `(goproto.proto.test.TestRequiredForeign).repeated_message[1].required_field`,
 	}, {
 		m: &testpb.TestRequiredForeign{
 			MapMessage: map[int32]*testpb.TestRequired{
 				1: {},
 			},
 		},
-		want: `goproto.proto.test.TestRequired.required_field`,
+		want: `(goproto.proto.test.TestRequiredForeign).map_message[1].required_field`,
 	}, {
 		m:    &testeditionspb.TestRequired{},
-		want: `goproto.proto.testeditions.TestRequired.required_field`,
+		want: `(goproto.proto.testeditions.TestRequired).required_field`,
 	}, {
 		m: &testeditionspb.TestRequiredForeign{
 			OptionalMessage: &testeditionspb.TestRequired{},
 		},
-		want: `goproto.proto.testeditions.TestRequired.required_field`,
+		want: `(goproto.proto.testeditions.TestRequiredForeign).optional_message.required_field`,
 	}, {
 		m: &testeditionspb.TestRequiredForeign{
 			RepeatedMessage: []*testeditionspb.TestRequired{
@@ -62,14 +65,14 @@ func TestCheckInitializedErrors(t *testing.T) {
 				{},
 			},
 		},
-		want: `goproto.proto.testeditions.TestRequired.required_field`,
+		want: `(goproto.proto.testeditions.TestRequiredForeign).repeated_message[1].required_field`,
 	}, {
 		m: &testeditionspb.TestRequiredForeign{
 			MapMessage: map[int32]*testeditionspb.TestRequired{
 				1: {},
 			},
 		},
-		want: `goproto.proto.testeditions.TestRequired.required_field`,
+		want: `(goproto.proto.testeditions.TestRequiredForeign).map_message[1].required_field`,
 	}, {
 		m:    &testpb.TestWeak{},
 		want: `<nil>`,
@@ -80,7 +83,7 @@ func TestCheckInitializedErrors(t *testing.T) {
 			m.SetWeakMessage1(&weakpb.WeakImportMessage1{})
 			return m
 		}(),
-		want: `goproto.proto.test.weak.WeakImportMessage1.a`,
+		want: `(goproto.proto.test.TestWeak).weak_message1.a`,
 		skip: !flags.ProtoLegacy,
 	}, {
 		m: func() proto.Message {
@@ -111,3 +114,78 @@ func TestCheckInitializedErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckInitializedAllFields(t *testing.T) {
+	ext := &testpb.TestAllExtensions{}
+	proto.SetExtension(ext, testpb.E_TestRequired_Single, &testpb.TestRequired{})
+	tests := []struct {
+		m    proto.Message
+		want []string
+	}{{
+		m: &testpb.TestRequiredForeign{
+			OptionalMessage: &testpb.TestRequired{},
+			RepeatedMessage: []*testpb.TestRequired{
+				{RequiredField: proto.Int32(1)},
+				{},
+			},
+			MapMessage: map[int32]*testpb.TestRequired{
+				2: {},
+				1: {},
+				3: {RequiredField: proto.Int32(1)},
+			},
+		},
+		want: []string{
+			"(goproto.proto.test.TestRequiredForeign).optional_message.required_field",
+			"(goproto.proto.test.TestRequiredForeign).repeated_message[1].required_field",
+			"(goproto.proto.test.TestRequiredForeign).map_message[1].required_field",
+			"(goproto.proto.test.TestRequiredForeign).map_message[2].required_field",
+		},
+	}, {
+		m: &testpb.TestRequiredGroupFields{
+			Optionalgroup: &testpb.TestRequiredGroupFields_OptionalGroup{},
+			Repeatedgroup: []*testpb.TestRequiredGroupFields_RepeatedGroup{{}},
+		},
+		want: []string{
+			"(goproto.proto.test.TestRequiredGroupFields).OptionalGroup.a",
+			"(goproto.proto.test.TestRequiredGroupFields).RepeatedGroup[0].a",
+		},
+	}, {
+		m: ext,
+		want: []string{
+			"(goproto.proto.test.TestAllExtensions).(goproto.proto.test.TestRequired.single).required_field",
+		},
+	}}
+	for _, tt := range tests {
+		for _, err := range []error{
+			proto.CheckInitialized(tt.m),
+			func() error { _, err := proto.Marshal(tt.m); return err }(),
+		} {
+			var rerr *proto.RequiredNotSetError
+			if !errors.As(err, &rerr) {
+				t.Errorf("error for %T = %v, want *RequiredNotSetError", tt.m, err)
+				continue
+			}
+			if !errors.Is(err, proto.Error) {
+				t.Errorf("error for %T does not match proto.Error", tt.m)
+			}
+			var got []string
+			for _, f := range rerr.Fields {
+				got = append(got, f.String())
+				if last := f.Path[len(f.Path)-1]; last.Field != f.Field {
+					t.Errorf("path %v does not end with field %v", f, f.Field.FullName())
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("paths for %T mismatch (-want +got):\n%s", tt.m, diff)
+			}
+			if want := "required field " + tt.want[0] + " not set"; !strings.Contains(err.Error(), want) {
+				t.Errorf("error %q does not contain %q", err, want)
+			}
+			for _, p := range tt.want[1:] {
+				if !strings.Contains(err.Error(), p) {
+					t.Errorf("error %q does not contain %q", err, p)
+				}
+			}
+		}
+	}
+}
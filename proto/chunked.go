@@ -0,0 +1,274 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+
+	"google.golang.org/protobuf/internal/encoding/unknownfields"
+	"google.golang.org/protobuf/internal/pragma"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ChunkedOptions configures the cooperative variants of [Clone] and [Equal],
+// which divide their work into bounded chunks and yield between chunks.
+// They avoid stalling the calling goroutine (for example, a server's event
+// loop) for a long time when operating on very large messages, and stop
+// early if the context is canceled.
+//
+// The cooperative variants always operate on messages using protobuf
+// reflection, so they are slower than [Clone] and [Equal] overall.
+type ChunkedOptions struct {
+	pragma.NoUnkeyedLiterals
+
+	// ChunkSize is the number of values visited in each chunk, where a value
+	// is a populated field, a list element, or a map entry.
+	// If zero, a default of 4096 is used.
+	ChunkSize int
+
+	// Yield is called between chunks, after checking that the context
+	// is not done. If Yield returns an error, the operation is aborted
+	// and the error is returned.
+	// If nil, [runtime.Gosched] is called instead.
+	Yield func(context.Context) error
+}
+
+const defaultChunkSize = 4096
+
+// CloneContext is like [Clone], but divides its work into chunks
+// as described by [ChunkedOptions].
+// It returns ctx.Err() if ctx is done before the copy is complete.
+func CloneContext(ctx context.Context, m Message) (Message, error) {
+	return ChunkedOptions{}.Clone(ctx, m)
+}
+
+// EqualContext is like [Equal], but divides its work into chunks
+// as described by [ChunkedOptions].
+// It returns ctx.Err() if ctx is done before the comparison is complete.
+func EqualContext(ctx context.Context, x, y Message) (bool, error) {
+	return ChunkedOptions{}.Equal(ctx, x, y)
+}
+
+// Clone returns a deep copy of m, as with [Clone].
+func (o ChunkedOptions) Clone(ctx context.Context, m Message) (Message, error) {
+	if m == nil {
+		return nil, nil
+	}
+	src := m.ProtoReflect()
+	if !src.IsValid() {
+		return src.Type().Zero().Interface(), nil
+	}
+	dst := src.New()
+	c := chunker{ctx: ctx, opts: o}
+	if err := c.cloneMessage(dst, src); err != nil {
+		return nil, err
+	}
+	return dst.Interface(), nil
+}
+
+// Equal reports whether two messages are equal, as with [Equal].
+func (o ChunkedOptions) Equal(ctx context.Context, x, y Message) (bool, error) {
+	if x == nil || y == nil {
+		return x == nil && y == nil, nil
+	}
+	if reflect.TypeOf(x).Kind() == reflect.Ptr && x == y {
+		return true, nil
+	}
+	mx, my := x.ProtoReflect(), y.ProtoReflect()
+	if mx.IsValid() != my.IsValid() {
+		return false, nil
+	}
+	c := chunker{ctx: ctx, opts: o}
+	return c.equalMessage(mx, my)
+}
+
+// chunker counts the values visited by an operation,
+// yielding after each chunk.
+type chunker struct {
+	ctx  context.Context
+	opts ChunkedOptions
+	n    int
+}
+
+// step records a visited value, and yields if the chunk is complete.
+func (c *chunker) step() error {
+	c.n++
+	size := c.opts.ChunkSize
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+	if c.n < size {
+		return nil
+	}
+	c.n = 0
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+	if c.opts.Yield != nil {
+		return c.opts.Yield(c.ctx)
+	}
+	runtime.Gosched()
+	return nil
+}
+
+func (c *chunker) cloneMessage(dst, src protoreflect.Message) (err error) {
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if err = c.step(); err != nil {
+			return false
+		}
+		switch {
+		case fd.IsList():
+			err = c.cloneList(dst.Mutable(fd).List(), v.List(), fd)
+		case fd.IsMap():
+			err = c.cloneMap(dst.Mutable(fd).Map(), v.Map(), fd.MapValue())
+		case fd.Message() != nil:
+			err = c.cloneMessage(dst.Mutable(fd).Message(), v.Message())
+		case fd.Kind() == protoreflect.BytesKind:
+			dst.Set(fd, MergeOptions{}.cloneBytes(v))
+		default:
+			dst.Set(fd, v)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(src.GetUnknown()) > 0 {
+		dst.SetUnknown(append(protoreflect.RawFields(nil), src.GetUnknown()...))
+	}
+	return nil
+}
+
+func (c *chunker) cloneList(dst, src protoreflect.List, fd protoreflect.FieldDescriptor) error {
+	for i, n := 0, src.Len(); i < n; i++ {
+		if err := c.step(); err != nil {
+			return err
+		}
+		switch v := src.Get(i); {
+		case fd.Message() != nil:
+			dstv := dst.NewElement()
+			if err := c.cloneMessage(dstv.Message(), v.Message()); err != nil {
+				return err
+			}
+			dst.Append(dstv)
+		case fd.Kind() == protoreflect.BytesKind:
+			dst.Append(MergeOptions{}.cloneBytes(v))
+		default:
+			dst.Append(v)
+		}
+	}
+	return nil
+}
+
+func (c *chunker) cloneMap(dst, src protoreflect.Map, fd protoreflect.FieldDescriptor) (err error) {
+	src.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		if err = c.step(); err != nil {
+			return false
+		}
+		switch {
+		case fd.Message() != nil:
+			dstv := dst.NewValue()
+			if err = c.cloneMessage(dstv.Message(), v.Message()); err != nil {
+				return false
+			}
+			dst.Set(k, dstv)
+		case fd.Kind() == protoreflect.BytesKind:
+			dst.Set(k, MergeOptions{}.cloneBytes(v))
+		default:
+			dst.Set(k, v)
+		}
+		return true
+	})
+	return err
+}
+
+func (c *chunker) equalMessage(mx, my protoreflect.Message) (equal bool, err error) {
+	if mx.Descriptor() != my.Descriptor() {
+		return false, nil
+	}
+	nx := 0
+	equal = true
+	mx.Range(func(fd protoreflect.FieldDescriptor, vx protoreflect.Value) bool {
+		nx++
+		if err = c.step(); err != nil {
+			return false
+		}
+		if !my.Has(fd) {
+			equal = false
+			return false
+		}
+		equal, err = c.equalField(fd, vx, my.Get(fd))
+		return equal && err == nil
+	})
+	if !equal || err != nil {
+		return false, err
+	}
+	ny := 0
+	my.Range(func(protoreflect.FieldDescriptor, protoreflect.Value) bool {
+		ny++
+		return true
+	})
+	if nx != ny {
+		return false, nil
+	}
+	return unknownfields.Equal(mx.GetUnknown(), my.GetUnknown()), nil
+}
+
+func (c *chunker) equalField(fd protoreflect.FieldDescriptor, x, y protoreflect.Value) (bool, error) {
+	switch {
+	case fd.IsList():
+		return c.equalList(fd, x.List(), y.List())
+	case fd.IsMap():
+		return c.equalMap(fd.MapValue(), x.Map(), y.Map())
+	default:
+		return c.equalValue(fd, x, y)
+	}
+}
+
+func (c *chunker) equalValue(fd protoreflect.FieldDescriptor, x, y protoreflect.Value) (bool, error) {
+	if fd.Message() != nil {
+		return c.equalMessage(x.Message(), y.Message())
+	}
+	return x.Equal(y), nil
+}
+
+func (c *chunker) equalList(fd protoreflect.FieldDescriptor, x, y protoreflect.List) (bool, error) {
+	if x.Len() != y.Len() {
+		return false, nil
+	}
+	for i := 0; i < x.Len(); i++ {
+		if err := c.step(); err != nil {
+			return false, err
+		}
+		if eq, err := c.equalValue(fd, x.Get(i), y.Get(i)); !eq || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (c *chunker) equalMap(fd protoreflect.FieldDescriptor, x, y protoreflect.Map) (equal bool, err error) {
+	if x.Len() != y.Len() {
+		return false, nil
+	}
+	equal = true
+	x.Range(func(k protoreflect.MapKey, vx protoreflect.Value) bool {
+		if err = c.step(); err != nil {
+			return false
+		}
+		if !y.Has(k) {
+			equal = false
+			return false
+		}
+		equal, err = c.equalValue(fd, vx, y.Get(k))
+		return equal && err == nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return equal, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestChunkedClone(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range testValidMessages {
+		for _, want := range test.decodeTo {
+			t.Run(fmt.Sprintf("%s (%T)", test.desc, want), func(t *testing.T) {
+				got, err := proto.ChunkedOptions{ChunkSize: 1}.Clone(ctx, want)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !proto.Equal(got, want) {
+					t.Errorf("Clone() mismatch (-want +got):\n%v", cmp.Diff(want, got, protocmp.Transform()))
+				}
+			})
+		}
+	}
+}
+
+func largeMessage(n int) *testpb.TestAllTypes {
+	m := &testpb.TestAllTypes{MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{}}
+	for i := 0; i < n; i++ {
+		m.RepeatedInt32 = append(m.RepeatedInt32, int32(i))
+		m.RepeatedNestedMessage = append(m.RepeatedNestedMessage, &testpb.TestAllTypes_NestedMessage{A: proto.Int32(int32(i))})
+		m.MapStringNestedMessage[fmt.Sprint(i)] = &testpb.TestAllTypes_NestedMessage{A: proto.Int32(int32(i))}
+	}
+	return m
+}
+
+func TestChunkedYield(t *testing.T) {
+	m := largeMessage(100)
+	var yields int
+	opts := proto.ChunkedOptions{
+		ChunkSize: 10,
+		Yield: func(context.Context) error {
+			yields++
+			return nil
+		},
+	}
+	ctx := context.Background()
+
+	c, err := opts.Clone(ctx, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(c, m) {
+		t.Errorf("Clone() mismatch (-want +got):\n%v", cmp.Diff(m, c, protocmp.Transform()))
+	}
+	// 3 fields, 300 elements and entries, and 200 nested fields.
+	if want := 503 / 10; yields != want {
+		t.Errorf("Clone() yielded %v times, want %v", yields, want)
+	}
+
+	yields = 0
+	if eq, err := opts.Equal(ctx, m, c); !eq || err != nil {
+		t.Errorf("Equal() = %v, %v; want true, nil", eq, err)
+	}
+	if want := 503 / 10; yields != want {
+		t.Errorf("Equal() yielded %v times, want %v", yields, want)
+	}
+}
+
+func TestChunkedCancel(t *testing.T) {
+	m := largeMessage(100)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts := proto.ChunkedOptions{ChunkSize: 10}
+	if _, err := opts.Clone(ctx, m); !errors.Is(err, context.Canceled) {
+		t.Errorf("Clone() with canceled context error = %v, want %v", err, context.Canceled)
+	}
+	if _, err := opts.Equal(ctx, m, proto.Clone(m)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Equal() with canceled context error = %v, want %v", err, context.Canceled)
+	}
+
+	// An error from Yield aborts the operation.
+	errStop := errors.New("stop")
+	opts.Yield = func(context.Context) error { return errStop }
+	if _, err := opts.Clone(context.Background(), m); err != errStop {
+		t.Errorf("Clone() with failing Yield error = %v, want %v", err, errStop)
+	}
+
+	// Small messages complete without checking the context.
+	small := &testpb.TestAllTypes{OptionalInt32: proto.Int32(1)}
+	if got, err := proto.CloneContext(ctx, small); err != nil || !proto.Equal(got, small) {
+		t.Errorf("CloneContext(small) = %v, %v", got, err)
+	}
+	if eq, err := proto.EqualContext(ctx, small, small); !eq || err != nil {
+		t.Errorf("EqualContext(small, small) = %v, %v", eq, err)
+	}
+}
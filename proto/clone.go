@@ -0,0 +1,143 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CloneInto makes dst a deep copy of src, which must be a message with the
+// same descriptor, reusing the memory already allocated within dst where
+// possible.
+//
+// Singular messages, list elements, and map values populated in both dst
+// and src are overwritten in place rather than reallocated, and the capacity
+// of existing lists and bytes fields is reused. Fields populated in dst but
+// not in src are cleared. This reduces allocations when repeatedly cloning
+// messages of similar shapes into the same destination.
+//
+// Since the memory of dst is overwritten, dst must not share any memory
+// with messages or values that are still in use elsewhere
+// (e.g., a sub-message of dst that was also set on another message).
+// If src is invalid, dst is reset.
+func CloneInto(dst, src Message) {
+	dstMsg, srcMsg := dst.ProtoReflect(), src.ProtoReflect()
+	if dstMsg.Descriptor() != srcMsg.Descriptor() {
+		if got, want := dstMsg.Descriptor().FullName(), srcMsg.Descriptor().FullName(); got != want {
+			panic(fmt.Sprintf("descriptor mismatch: %v != %v", got, want))
+		}
+		panic("descriptor mismatch")
+	}
+	if !srcMsg.IsValid() {
+		Reset(dst)
+		return
+	}
+	if r, ok := dstMsg.(replacer); ok && r.ProtoReplace(srcMsg) {
+		return
+	}
+	cloneMessageInto(dstMsg, srcMsg)
+}
+
+// replacer is implemented by messages of the internal/impl package,
+// which can replace their contents without going through reflection.
+type replacer interface {
+	ProtoReplace(protoreflect.Message) bool
+}
+
+func cloneMessageInto(dst, src protoreflect.Message) {
+	if !dst.IsValid() {
+		panic(fmt.Sprintf("cannot clone into invalid %v message", dst.Descriptor().FullName()))
+	}
+	if dst.Interface() == src.Interface() {
+		return
+	}
+
+	// Clear fields that are not populated in src.
+	dst.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if !src.Has(fd) {
+			dst.Clear(fd)
+		}
+		return true
+	})
+
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			cloneListInto(dst.Mutable(fd).List(), v.List(), fd)
+		case fd.IsMap():
+			cloneMapInto(dst.Mutable(fd).Map(), v.Map(), fd.MapValue())
+		case fd.Message() != nil:
+			cloneMessageInto(dst.Mutable(fd).Message(), v.Message())
+		case fd.Kind() == protoreflect.BytesKind:
+			var b []byte
+			if dst.Has(fd) {
+				b = dst.Get(fd).Bytes()[:0]
+			}
+			dst.Set(fd, protoreflect.ValueOfBytes(append(b, v.Bytes()...)))
+		default:
+			dst.Set(fd, v)
+		}
+		return true
+	})
+
+	if u := src.GetUnknown(); len(u) > 0 {
+		dst.SetUnknown(append(dst.GetUnknown()[:0], u...))
+	} else if len(dst.GetUnknown()) > 0 {
+		dst.SetUnknown(nil)
+	}
+}
+
+func cloneListInto(dst, src protoreflect.List, fd protoreflect.FieldDescriptor) {
+	n := src.Len()
+	if fd.Message() == nil {
+		dst.Truncate(0)
+		for i := 0; i < n; i++ {
+			v := src.Get(i)
+			if fd.Kind() == protoreflect.BytesKind {
+				v = protoreflect.ValueOfBytes(append([]byte{}, v.Bytes()...))
+			}
+			dst.Append(v)
+		}
+		return
+	}
+	if dst.Len() > n {
+		dst.Truncate(n)
+	}
+	for i := 0; i < n; i++ {
+		if i < dst.Len() {
+			cloneMessageInto(dst.Get(i).Message(), src.Get(i).Message())
+			continue
+		}
+		v := dst.NewElement()
+		cloneMessageInto(v.Message(), src.Get(i).Message())
+		dst.Append(v)
+	}
+}
+
+func cloneMapInto(dst, src protoreflect.Map, fd protoreflect.FieldDescriptor) {
+	dst.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		if !src.Has(k) {
+			dst.Clear(k)
+		}
+		return true
+	})
+	src.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		switch {
+		case fd.Message() != nil && dst.Has(k):
+			cloneMessageInto(dst.Mutable(k).Message(), v.Message())
+		case fd.Message() != nil:
+			dv := dst.NewValue()
+			cloneMessageInto(dv.Message(), v.Message())
+			dst.Set(k, dv)
+		case fd.Kind() == protoreflect.BytesKind:
+			dst.Set(k, protoreflect.ValueOfBytes(append([]byte{}, v.Bytes()...)))
+		default:
+			dst.Set(k, v)
+		}
+		return true
+	})
+}
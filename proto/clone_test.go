@@ -0,0 +1,114 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/internal/protobuild"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestCloneInto(t *testing.T) {
+	for _, tt := range testMerges {
+		for _, mt := range templateMessages(tt.types...) {
+			t.Run(fmt.Sprintf("%s (%v)", tt.desc, mt.Descriptor().FullName()), func(t *testing.T) {
+				src := mt.New().Interface()
+				tt.src.Build(src.ProtoReflect())
+				want := proto.Clone(src)
+
+				for _, dst := range []proto.Message{mt.New().Interface(), dynamicpb.NewMessage(mt.Descriptor())} {
+					// Clone into both a populated and an empty destination.
+					for _, init := range []protobuild.Message{tt.dst, tt.want, nil} {
+						// Populate dst by merging so that it does not share
+						// memory with the test case, which CloneInto overwrites.
+						m := mt.New().Interface()
+						init.Build(m.ProtoReflect())
+						proto.Reset(dst)
+						proto.Merge(dst, m)
+						proto.CloneInto(dst, src)
+						if !proto.Equal(dst, want) {
+							t.Fatalf("CloneInto() into %T mismatch (-want +got):\n%v", dst, cmp.Diff(want, dst, protocmp.Transform()))
+						}
+					}
+				}
+
+				// The source is cloned first, since the unknown fields
+				// built from the test case are shared with other tests.
+				src = proto.Clone(src)
+				dst := mt.New().Interface()
+				proto.CloneInto(dst, src)
+				mutateValue(protoreflect.ValueOfMessage(src.ProtoReflect()))
+				if !proto.Equal(dst, want) {
+					t.Fatalf("mutation observed after modifying source (-want +got):\n%v", cmp.Diff(want, dst, protocmp.Transform()))
+				}
+			})
+		}
+	}
+}
+
+func TestCloneIntoClears(t *testing.T) {
+	dst := &testpb.TestAllTypes{
+		OptionalInt32:         proto.Int32(1),
+		OptionalBytes:         []byte("abc"),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)},
+		RepeatedInt32:         []int32{1, 2, 3},
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{{A: proto.Int32(1)}, {A: proto.Int32(2)}},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"a": {A: proto.Int32(1)},
+			"b": {A: proto.Int32(2)},
+		},
+		OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1},
+	}
+	dst.ProtoReflect().SetUnknown(protoreflect.RawFields{0x80, 0x7f, 0x01})
+
+	src := &testpb.TestAllTypes{
+		OptionalBytes:          []byte("x"),
+		OptionalNestedMessage:  &testpb.TestAllTypes_NestedMessage{},
+		RepeatedNestedMessage:  []*testpb.TestAllTypes_NestedMessage{{A: proto.Int32(3)}},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{"b": {}},
+		OneofField:             &testpb.TestAllTypes_OneofString{OneofString: "x"},
+	}
+
+	nested := dst.OptionalNestedMessage
+	elem := dst.RepeatedNestedMessage[0]
+	value := dst.MapStringNestedMessage["b"]
+	proto.CloneInto(dst, src)
+	if !proto.Equal(dst, src) {
+		t.Fatalf("CloneInto() mismatch (-want +got):\n%v", cmp.Diff(src, dst, protocmp.Transform()))
+	}
+	if dst.OptionalNestedMessage != nested || dst.RepeatedNestedMessage[0] != elem || dst.MapStringNestedMessage["b"] != value {
+		t.Errorf("CloneInto() reallocated messages that were populated in the destination")
+	}
+}
+
+func TestCloneIntoAllocs(t *testing.T) {
+	src := &testpb.TestAllTypes{
+		OptionalInt32:         proto.Int32(1),
+		OptionalString:        proto.String("abc"),
+		OptionalBytes:         []byte("abc"),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)},
+		RepeatedInt32:         []int32{1, 2, 3},
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{{A: proto.Int32(1)}, {A: proto.Int32(2)}},
+	}
+	dst := proto.Clone(src).(*testpb.TestAllTypes)
+	cloneAllocs := testing.AllocsPerRun(10, func() {
+		proto.Clone(src)
+	})
+	cloneIntoAllocs := testing.AllocsPerRun(10, func() {
+		proto.CloneInto(dst, src)
+	})
+	if cloneIntoAllocs >= cloneAllocs {
+		t.Errorf("CloneInto() performed %v allocations, want fewer than the %v of Clone()", cloneIntoAllocs, cloneAllocs)
+	}
+}
@@ -6,6 +6,7 @@ package proto
 
 import (
 	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/allocstats"
 	"google.golang.org/protobuf/internal/encoding/messageset"
 	"google.golang.org/protobuf/internal/errors"
 	"google.golang.org/protobuf/internal/flags"
@@ -47,6 +48,25 @@ type UnmarshalOptions struct {
 	// RecursionLimit limits how deeply messages may be nested.
 	// If zero, a default limit is applied.
 	RecursionLimit int
+
+	// Limits are the resource limits on the input.
+	// Input that exceeds them is rejected with a [*LimitError].
+	Limits Limits
+
+	// InternStrings specifies that identical values of string fields,
+	// including map keys, share memory within a single call to Unmarshal.
+	// This reduces the memory used by messages with many repeated strings
+	// at the cost of a map lookup for each string decoded.
+	// It has no effect if Interner is set.
+	InternStrings bool
+
+	// Interner, if non-nil, is used to convert the bytes of string fields,
+	// including map keys, to strings. A [StringInterner] may be shared
+	// between calls to Unmarshal so that identical strings share memory
+	// across messages.
+	Interner interface {
+		InternString(b []byte) string
+	}
 }
 
 // Unmarshal parses the wire-format message in b and places the result in m.
@@ -54,6 +74,9 @@ type UnmarshalOptions struct {
 //
 // See the [UnmarshalOptions] type if you need more control.
 func Unmarshal(b []byte, m Message) error {
+	if flags.ProtoAllocs {
+		defer allocstats.Record(allocstats.Unmarshal, m.ProtoReflect().Descriptor().FullName(), allocstats.Start())
+	}
 	_, err := UnmarshalOptions{RecursionLimit: protowire.DefaultRecursionLimit}.unmarshal(b, m.ProtoReflect())
 	return err
 }
@@ -64,6 +87,14 @@ func (o UnmarshalOptions) Unmarshal(b []byte, m Message) error {
 	if o.RecursionLimit == 0 {
 		o.RecursionLimit = protowire.DefaultRecursionLimit
 	}
+	if flags.ProtoAllocs {
+		defer allocstats.Record(allocstats.Unmarshal, m.ProtoReflect().Descriptor().FullName(), allocstats.Start())
+	}
+	if o.Limits.any() {
+		if err := o.checkLimits(b, m.ProtoReflect().Descriptor()); err != nil {
+			return err
+		}
+	}
 	_, err := o.unmarshal(b, m.ProtoReflect())
 	return err
 }
@@ -76,6 +107,11 @@ func (o UnmarshalOptions) UnmarshalState(in protoiface.UnmarshalInput) (protoifa
 	if o.RecursionLimit == 0 {
 		o.RecursionLimit = protowire.DefaultRecursionLimit
 	}
+	if o.Limits.any() {
+		if err := o.checkLimits(in.Buf, in.Message.Descriptor()); err != nil {
+			return protoiface.UnmarshalOutput{}, err
+		}
+	}
 	return o.unmarshal(in.Buf, in.Message)
 }
 
@@ -86,6 +122,9 @@ func (o UnmarshalOptions) unmarshal(b []byte, m protoreflect.Message) (out proto
 	if o.Resolver == nil {
 		o.Resolver = protoregistry.GlobalTypes
 	}
+	if o.InternStrings && o.Interner == nil {
+		o.Interner = new(StringInterner)
+	}
 	if !o.Merge {
 		Reset(m.Interface())
 	}
@@ -93,6 +132,7 @@ func (o UnmarshalOptions) unmarshal(b []byte, m protoreflect.Message) (out proto
 	o.Merge = true
 	o.AllowPartial = true
 	methods := protoMethods(m)
+	var done bool
 	if methods != nil && methods.Unmarshal != nil &&
 		!(o.DiscardUnknown && methods.Flags&protoiface.SupportUnmarshalDiscardUnknown == 0) {
 		in := protoiface.UnmarshalInput{
@@ -104,8 +144,14 @@ func (o UnmarshalOptions) unmarshal(b []byte, m protoreflect.Message) (out proto
 		if o.DiscardUnknown {
 			in.Flags |= protoiface.UnmarshalDiscardUnknown
 		}
-		out, err = methods.Unmarshal(in)
-	} else {
+		if o.Interner == nil {
+			out, err = methods.Unmarshal(in)
+			done = true
+		} else if u, ok := m.(internUnmarshaler); ok {
+			out, done, err = u.ProtoUnmarshalInterned(in, o.Interner)
+		}
+	}
+	if !done {
 		o.RecursionLimit--
 		if o.RecursionLimit < 0 {
 			return out, errors.New("exceeded max recursion depth")
@@ -118,7 +164,23 @@ func (o UnmarshalOptions) unmarshal(b []byte, m protoreflect.Message) (out proto
 	if allowPartial || (out.Flags&protoiface.UnmarshalInitialized != 0) {
 		return out, nil
 	}
-	return out, checkInitialized(m)
+	return out, checkRequired(m)
+}
+
+// internUnmarshaler is implemented by messages of the internal/impl package,
+// which can intern strings while unmarshaling on the fast path.
+// Messages that do not implement it are unmarshaled on the slow path
+// when an Interner is used.
+type internUnmarshaler interface {
+	ProtoUnmarshalInterned(protoiface.UnmarshalInput, interface{ InternString([]byte) string }) (protoiface.UnmarshalOutput, bool, error)
+}
+
+// internString converts the bytes of a string value to a string.
+func (o UnmarshalOptions) internString(b []byte) string {
+	if o.Interner != nil {
+		return o.Interner.InternString(b)
+	}
+	return string(b)
 }
 
 func (o UnmarshalOptions) unmarshalMessage(b []byte, m protoreflect.Message) error {
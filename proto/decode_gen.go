@@ -158,7 +158,7 @@ func (o UnmarshalOptions) unmarshalScalar(b []byte, wtyp protowire.Type, fd prot
 		if strs.EnforceUTF8(fd) && !utf8.Valid(v) {
 			return protoreflect.Value{}, 0, errors.InvalidUTF8(string(fd.FullName()))
 		}
-		return protoreflect.ValueOfString(string(v)), n, nil
+		return protoreflect.ValueOfString(o.internString(v)), n, nil
 	case protoreflect.BytesKind:
 		if wtyp != protowire.BytesType {
 			return val, 0, errUnknown
@@ -554,7 +554,7 @@ func (o UnmarshalOptions) unmarshalList(b []byte, wtyp protowire.Type, list prot
 		if strs.EnforceUTF8(fd) && !utf8.Valid(v) {
 			return 0, errors.InvalidUTF8(string(fd.FullName()))
 		}
-		list.Append(protoreflect.ValueOfString(string(v)))
+		list.Append(protoreflect.ValueOfString(o.internString(v)))
 		return n, nil
 	case protoreflect.BytesKind:
 		if wtyp != protowire.BytesType {
@@ -0,0 +1,78 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FieldChange is a field whose value differs between two messages.
+type FieldChange struct {
+	// Path is the path to the field from the root message in the syntax
+	// of a google.protobuf.FieldMask path, which is the names of
+	// the fields separated by dots (e.g., "foo.bar").
+	Path string
+
+	// Field is the descriptor of the last field in the path.
+	Field protoreflect.FieldDescriptor
+
+	// Old and New are the values of the field in the old and new message.
+	// The value is invalid if the field is not populated.
+	Old, New protoreflect.Value
+}
+
+// Diff reports the fields that differ between the old and new messages,
+// which must be messages with the same descriptor.
+//
+// Fields are compared as by [Equal] and reported in the order they are
+// declared in the message. Singular message fields populated in both
+// messages are compared recursively, reporting the fields that differ
+// within them. Other fields, including repeated and map fields,
+// are reported as a whole.
+//
+// The paths of the changes form a valid field mask for the message type,
+// which may be used to update the old message to the new message.
+// Extension fields and unknown fields cannot be named in a field mask
+// and are ignored.
+func Diff(old, new Message) []FieldChange {
+	oldMsg, newMsg := old.ProtoReflect(), new.ProtoReflect()
+	if oldMsg.Descriptor() != newMsg.Descriptor() {
+		if got, want := oldMsg.Descriptor().FullName(), newMsg.Descriptor().FullName(); got != want {
+			panic(fmt.Sprintf("descriptor mismatch: %v != %v", got, want))
+		}
+		panic("descriptor mismatch")
+	}
+	return appendDiff(nil, "", oldMsg, newMsg)
+}
+
+func appendDiff(changes []FieldChange, prefix string, old, new protoreflect.Message) []FieldChange {
+	fields := old.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		hasOld, hasNew := old.Has(fd), new.Has(fd)
+		if !hasOld && !hasNew {
+			continue
+		}
+		path := prefix + fd.TextName() // the name of a group field is its message name
+		if hasOld && hasNew && fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+			changes = appendDiff(changes, path+".", old.Get(fd).Message(), new.Get(fd).Message())
+			continue
+		}
+		var oldVal, newVal protoreflect.Value
+		if hasOld {
+			oldVal = old.Get(fd)
+		}
+		if hasNew {
+			newVal = new.Get(fd)
+		}
+		if hasOld && hasNew && oldVal.Equal(newVal) {
+			continue
+		}
+		changes = append(changes, FieldChange{Path: path, Field: fd, Old: oldVal, New: newVal})
+	}
+	return changes
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestDiff(t *testing.T) {
+	type change struct {
+		Path     string
+		Old, New interface{}
+	}
+	tests := []struct {
+		desc     string
+		old, new proto.Message
+		want     []change
+	}{{
+		desc: "equal",
+		old:  &testpb.TestAllTypes{OptionalInt32: proto.Int32(1), OptionalDouble: proto.Float64(math.NaN())},
+		new:  &testpb.TestAllTypes{OptionalInt32: proto.Int32(1), OptionalDouble: proto.Float64(math.NaN())},
+	}, {
+		desc: "scalars",
+		old:  &testpb.TestAllTypes{OptionalInt32: proto.Int32(1), OptionalString: proto.String("a")},
+		new:  &testpb.TestAllTypes{OptionalInt32: proto.Int32(2), OptionalBytes: []byte("b")},
+		want: []change{
+			{Path: "optional_int32", Old: int32(1), New: int32(2)},
+			{Path: "optional_string", Old: "a"},
+			{Path: "optional_bytes", New: []byte("b")},
+		},
+	}, {
+		desc: "nested messages",
+		old: &testpb.TestAllTypes{
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A:           proto.Int32(1),
+				Corecursive: &testpb.TestAllTypes{OptionalInt64: proto.Int64(2)},
+			},
+			Optionalgroup: &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(3)},
+		},
+		new: &testpb.TestAllTypes{
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A:           proto.Int32(1),
+				Corecursive: &testpb.TestAllTypes{OptionalInt64: proto.Int64(4)},
+			},
+		},
+		want: []change{
+			{Path: "OptionalGroup", Old: &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(3)}},
+			{Path: "optional_nested_message.corecursive.optional_int64", Old: int64(2), New: int64(4)},
+		},
+	}, {
+		desc: "repeated and map fields",
+		old: &testpb.TestAllTypes{
+			RepeatedInt32: []int32{1, 2},
+			MapInt32Int32: map[int32]int32{1: 1},
+		},
+		new: &testpb.TestAllTypes{
+			RepeatedInt32: []int32{1, 2},
+			MapInt32Int32: map[int32]int32{1: 2},
+		},
+		want: []change{
+			{Path: "map_int32_int32", Old: map[int32]int32{1: 1}, New: map[int32]int32{1: 2}},
+		},
+	}, {
+		desc: "oneof",
+		old:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+		new:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofString{OneofString: "a"}},
+		want: []change{
+			{Path: "oneof_uint32", Old: uint32(1)},
+			{Path: "oneof_string", New: "a"},
+		},
+	}, {
+		desc: "nil message",
+		old:  (*testpb.TestAllTypes)(nil),
+		new:  &testpb.TestAllTypes{OptionalInt32: proto.Int32(1)},
+		want: []change{
+			{Path: "optional_int32", New: int32(1)},
+		},
+	}}
+	toInterface := func(v protoreflect.Value) interface{} {
+		switch x := v.Interface().(type) {
+		case protoreflect.Message:
+			return x.Interface()
+		case protoreflect.Map:
+			// Only maps of int32 to int32 are used in the tests.
+			m := make(map[int32]int32)
+			x.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				m[int32(k.Int())] = int32(v.Int())
+				return true
+			})
+			return m
+		default:
+			return x
+		}
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var got []change
+			for _, c := range proto.Diff(tt.old, tt.new) {
+				if name := c.Field.TextName(); c.Path[len(c.Path)-len(name):] != name {
+					t.Errorf("change to %q has field %v", c.Path, c.Field.FullName())
+				}
+				got = append(got, change{Path: c.Path, Old: toInterface(c.Old), New: toInterface(c.New)})
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(proto.Equal)); diff != "" {
+				t.Errorf("Diff() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffDynamic(t *testing.T) {
+	old := &testpb.TestAllTypes{
+		OptionalInt32:         proto.Int32(1),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)},
+	}
+	new := &testpb.TestAllTypes{
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)},
+		RepeatedString:        []string{"a"},
+	}
+	toDynamic := func(m proto.Message) proto.Message {
+		b, err := proto.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dm := dynamicpb.NewMessage(m.ProtoReflect().Descriptor())
+		if err := proto.Unmarshal(b, dm); err != nil {
+			t.Fatal(err)
+		}
+		return dm
+	}
+	paths := func(changes []proto.FieldChange) (ps []string) {
+		for _, c := range changes {
+			ps = append(ps, c.Path)
+		}
+		return ps
+	}
+	want := paths(proto.Diff(old, new))
+	got := paths(proto.Diff(toDynamic(old), toDynamic(new)))
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Diff() of dynamic messages mismatch (-want +got):\n%s", diff)
+	}
+}
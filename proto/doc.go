@@ -30,6 +30,10 @@
 //
 //   - [Clone] makes a deep copy of a message.
 //
+//     Generated messages must not be copied by value, since they hold
+//     internal state. Each generated message contains a no-copy marker,
+//     so the copylocks check of go vet reports such copies.
+//
 //   - [Merge] merges the content of a message into another.
 //
 //   - [Equal] compares two messages. For more control over comparisons
@@ -9,7 +9,9 @@ import (
 	"fmt"
 
 	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/allocstats"
 	"google.golang.org/protobuf/internal/encoding/messageset"
+	"google.golang.org/protobuf/internal/flags"
 	"google.golang.org/protobuf/internal/order"
 	"google.golang.org/protobuf/internal/pragma"
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -106,6 +108,9 @@ func Marshal(m Message) ([]byte, error) {
 	if m == nil {
 		return nil, nil
 	}
+	if flags.ProtoAllocs {
+		defer allocstats.Record(allocstats.Marshal, m.ProtoReflect().Descriptor().FullName(), allocstats.Start())
+	}
 
 	out, err := MarshalOptions{}.marshal(nil, m.ProtoReflect())
 	if len(out.Buf) == 0 && err == nil {
@@ -120,6 +125,9 @@ func (o MarshalOptions) Marshal(m Message) ([]byte, error) {
 	if m == nil {
 		return nil, nil
 	}
+	if flags.ProtoAllocs {
+		defer allocstats.Record(allocstats.Marshal, m.ProtoReflect().Descriptor().FullName(), allocstats.Start())
+	}
 
 	out, err := o.marshal(nil, m.ProtoReflect())
 	if len(out.Buf) == 0 && err == nil {
@@ -154,6 +162,9 @@ func (o MarshalOptions) MarshalAppend(b []byte, m Message) ([]byte, error) {
 	if m == nil {
 		return b, nil
 	}
+	if flags.ProtoAllocs {
+		defer allocstats.Record(allocstats.Marshal, m.ProtoReflect().Descriptor().FullName(), allocstats.Start())
+	}
 
 	out, err := o.marshal(b, m.ProtoReflect())
 	return out.Buf, err
@@ -205,7 +216,7 @@ func (o MarshalOptions) marshal(b []byte, m protoreflect.Message) (out protoifac
 	if allowPartial {
 		return out, nil
 	}
-	return out, checkInitialized(m)
+	return out, checkRequired(m)
 }
 
 func (o MarshalOptions) marshalMessage(b []byte, m protoreflect.Message) ([]byte, error) {
@@ -94,6 +94,36 @@ func TestEncodeDeterministic(t *testing.T) {
 	}
 }
 
+func TestEncodeCachedSize(t *testing.T) {
+	for _, test := range testValidMessages {
+		for _, want := range test.decodeTo {
+			t.Run(fmt.Sprintf("%s (%T)", test.desc, want), func(t *testing.T) {
+				opts := proto.MarshalOptions{
+					Deterministic: true,
+					AllowPartial:  test.partial,
+				}
+				wire, err := opts.Marshal(want)
+				if err != nil {
+					t.Fatalf("Marshal error: %v\nMessage:\n%v", err, prototext.Format(want))
+				}
+
+				size := opts.Size(want)
+				opts.UseCachedSize = true
+				wire2, err := opts.Marshal(want)
+				if err != nil {
+					t.Fatalf("Marshal with UseCachedSize error: %v\nMessage:\n%v", err, prototext.Format(want))
+				}
+				if size != len(wire2) {
+					t.Errorf("Size and marshal with UseCachedSize disagree: Size(m)=%v; len(Marshal(m))=%v\nMessage:\n%v", size, len(wire2), prototext.Format(want))
+				}
+				if !bytes.Equal(wire, wire2) {
+					t.Errorf("Marshal with UseCachedSize returned different output:\n%v", cmp.Diff(wire, wire2))
+				}
+			})
+		}
+	}
+}
+
 func TestEncodeRequiredFieldChecks(t *testing.T) {
 	for _, test := range testValidMessages {
 		if !test.partial {
@@ -0,0 +1,101 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// UnmarshalAny parses b as a wire-format google.protobuf.Any message and
+// returns a newly created message of the type named by its type URL,
+// populated from its value.
+//
+// See [UnmarshalOptions.UnmarshalAny] if you need more control.
+func UnmarshalAny(b []byte) (Message, error) {
+	return UnmarshalOptions{}.UnmarshalAny(b)
+}
+
+// UnmarshalAny parses b as a wire-format google.protobuf.Any message and
+// returns a newly created message of the type named by its type URL,
+// populated from its value.
+//
+// It is equivalent to unmarshaling b into an anypb.Any and calling
+// anypb.UnmarshalNew, but does not allocate the intermediate message.
+// The message type is resolved according to o.Resolver,
+// which should implement [protoregistry.MessageTypeResolver].
+// For example, a [google.golang.org/protobuf/types/dynamicpb.Types] may be
+// used to produce dynamic messages for types that are not linked in.
+func (o UnmarshalOptions) UnmarshalAny(b []byte) (Message, error) {
+	return o.UnmarshalEnvelope(b, genid.Any_TypeUrl_field_number, genid.Any_Value_field_number)
+}
+
+// UnmarshalEnvelope parses b as a wire-format envelope message, which is any
+// message with a string field naming the type of its payload and a bytes
+// field holding the wire-format payload. It returns a newly created message
+// of the named type, populated from the payload.
+//
+// The typeField and valueField are the field numbers of the type and payload
+// fields. All other fields of the envelope are ignored. The type may be
+// either a type URL or a full message name, and is resolved as for
+// [UnmarshalOptions.UnmarshalAny]. A missing payload field is treated as an
+// empty message. The payload is unmarshaled as by [UnmarshalOptions.Unmarshal],
+// so o.Limits apply to the payload.
+func (o UnmarshalOptions) UnmarshalEnvelope(b []byte, typeField, valueField protowire.Number) (Message, error) {
+	var typeURL string
+	var value []byte
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, errDecode
+		}
+		b = b[n:]
+		if typ == protowire.BytesType && (num == typeField || num == valueField) {
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return nil, errDecode
+			}
+			// Singular scalar fields use the last value on the wire.
+			if num == typeField {
+				typeURL = string(v)
+			} else {
+				value = v
+			}
+			b = b[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return nil, errDecode
+		}
+		b = b[n:]
+	}
+	if typeURL == "" {
+		return nil, errors.New("invalid empty type URL")
+	}
+
+	var resolver any = o.Resolver
+	if o.Resolver == nil {
+		resolver = protoregistry.GlobalTypes
+	}
+	r, ok := resolver.(protoregistry.MessageTypeResolver)
+	if !ok {
+		return nil, errors.New("unsupported resolver type %T: does not implement protoregistry.MessageTypeResolver", resolver)
+	}
+	mt, err := r.FindMessageByURL(typeURL)
+	if err != nil {
+		if err == protoregistry.NotFound {
+			return nil, err
+		}
+		return nil, errors.New("could not resolve %q: %v", typeURL, err)
+	}
+	m := mt.New().Interface()
+	if err := o.Unmarshal(value, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
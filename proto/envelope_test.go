@@ -0,0 +1,155 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestUnmarshalAny(t *testing.T) {
+	want := &testpb.TestAllTypes{
+		OptionalInt32:         proto.Int32(1),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)},
+	}
+	a, err := anypb.New(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := proto.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := proto.UnmarshalAny(b)
+	if err != nil {
+		t.Fatalf("UnmarshalAny() error: %v", err)
+	}
+	if _, ok := got.(*testpb.TestAllTypes); !ok || !proto.Equal(got, want) {
+		t.Errorf("UnmarshalAny() = %T %v, want %T %v", got, got, want, want)
+	}
+
+	// Resolve the type as a dynamic message.
+	files := new(protoregistry.Files)
+	if err := files.RegisterFile(testpb.File_internal_testprotos_test_test_proto); err != nil {
+		t.Fatal(err)
+	}
+	got, err = proto.UnmarshalOptions{Resolver: dynamicpb.NewTypes(files)}.UnmarshalAny(b)
+	if err != nil {
+		t.Fatalf("UnmarshalAny() with dynamic types error: %v", err)
+	}
+	if _, ok := got.(*dynamicpb.Message); !ok || !proto.Equal(got, dynamicCopy(want)) {
+		t.Errorf("UnmarshalAny() with dynamic types = %T %v, want dynamic %v", got, got, want)
+	}
+}
+
+func dynamicCopy(m proto.Message) proto.Message {
+	dm := dynamicpb.NewMessage(m.ProtoReflect().Descriptor())
+	proto.Merge(dm, m)
+	return dm
+}
+
+func TestUnmarshalEnvelope(t *testing.T) {
+	want := &testpb.TestAllTypes{OptionalString: proto.String("payload")}
+	payload, err := proto.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An envelope with the type name in field 3, the payload in field 5,
+	// and unrelated metadata fields.
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, 12345)
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendString(b, "goproto.proto.test.OldType")
+	b = protowire.AppendTag(b, 5, protowire.BytesType)
+	b = protowire.AppendBytes(b, payload)
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendString(b, string(want.ProtoReflect().Descriptor().FullName()))
+	b = protowire.AppendTag(b, 7, protowire.BytesType)
+	b = protowire.AppendString(b, "metadata")
+
+	got, err := proto.UnmarshalOptions{}.UnmarshalEnvelope(b, 3, 5)
+	if err != nil {
+		t.Fatalf("UnmarshalEnvelope() error: %v", err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("UnmarshalEnvelope() = %v, want %v", got, want)
+	}
+}
+
+func TestUnmarshalAnyErrors(t *testing.T) {
+	valid, err := proto.Marshal(&anypb.Any{TypeUrl: "type.googleapis.com/goproto.proto.test.TestAllTypes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		desc    string
+		in      []byte
+		opts    proto.UnmarshalOptions
+		wantErr string
+	}{{
+		desc: "empty type URL",
+		in:   nil,
+	}, {
+		desc: "unknown type",
+		in:   protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), "type.googleapis.com/unknown.Message"),
+	}, {
+		desc: "truncated input",
+		in:   valid[:len(valid)-1],
+	}, {
+		desc: "invalid payload",
+		in:   protowire.AppendBytes(protowire.AppendTag(valid, 2, protowire.BytesType), []byte{0xff}),
+	}, {
+		desc: "resolver without message types",
+		in:   valid,
+		opts: proto.UnmarshalOptions{Resolver: struct {
+			protoregistry.ExtensionTypeResolver
+		}{protoregistry.GlobalTypes}},
+		wantErr: "unsupported resolver type",
+	}}
+	for _, tt := range tests {
+		m, err := tt.opts.UnmarshalAny(tt.in)
+		if err == nil {
+			t.Errorf("%v: UnmarshalAny() = %v, want error", tt.desc, m)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%v: UnmarshalAny() error = %v, want error containing %q", tt.desc, err, tt.wantErr)
+		}
+	}
+}
+
+func TestUnmarshalEnvelopeLimits(t *testing.T) {
+	payload, err := proto.Marshal(&testpb.TestAllTypes{OptionalString: proto.String("abcd")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, "goproto.proto.test.TestAllTypes")
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendBytes(b, payload)
+
+	opts := proto.UnmarshalOptions{Limits: proto.Limits{MaxStringLength: 3}}
+	m, err := opts.UnmarshalEnvelope(b, 1, 2)
+	var lerr *proto.LimitError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("UnmarshalEnvelope() = %v, %v, want *LimitError", m, err)
+	}
+	if lerr.Limit != "MaxStringLength" || lerr.Field != "goproto.proto.test.TestAllTypes.optional_string" || lerr.Size != 4 {
+		t.Errorf("UnmarshalEnvelope() error = %+v, want MaxStringLength of optional_string with size 4", lerr)
+	}
+}
@@ -5,6 +5,7 @@
 package proto_test
 
 import (
+	"context"
 	"math"
 	"testing"
 
@@ -1000,6 +1001,9 @@ func TestEqual(t *testing.T) {
 		if eq := proto.Equal(tt.x, tt.y); eq != tt.eq {
 			t.Errorf("Equal(x, y) = %v, want %v\n==== x ====\n%v==== y ====\n%v", eq, tt.eq, prototext.Format(tt.x), prototext.Format(tt.y))
 		}
+		if eq, err := (proto.ChunkedOptions{ChunkSize: 1}).Equal(context.Background(), tt.x, tt.y); eq != tt.eq || err != nil {
+			t.Errorf("ChunkedOptions.Equal(x, y) = %v, %v; want %v\n==== x ====\n%v==== y ====\n%v", eq, err, tt.eq, prototext.Format(tt.x), prototext.Format(tt.y))
+		}
 	}
 }
 
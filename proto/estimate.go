@@ -0,0 +1,270 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// UnboundedSize is the Max of a [SizeEstimate] that has no upper bound.
+const UnboundedSize = -1
+
+// SizeEstimate is a prediction of the size in bytes of a wire-format encoding.
+type SizeEstimate struct {
+	Min     int // smallest possible size
+	Typical int // expected size given the hints
+	Max     int // largest possible size, or UnboundedSize
+}
+
+// FieldSizeEstimate is the predicted size of the encoding of a field,
+// including its tags.
+type FieldSizeEstimate struct {
+	Field protoreflect.FieldDescriptor
+	SizeEstimate
+}
+
+// MessageSizeEstimate is the predicted size of the encoding of a message.
+type MessageSizeEstimate struct {
+	SizeEstimate
+
+	// Fields are the estimates for each field of the message,
+	// in the order of the message descriptor's field list.
+	Fields []FieldSizeEstimate
+}
+
+// SizeHints describe the expected contents of messages for [EstimateSize].
+type SizeHints struct {
+	// Fields are hints about individual fields,
+	// identified by their full names (e.g., "example.User.name").
+	Fields map[protoreflect.FullName]FieldSizeHint
+
+	// Count is the typical number of elements of repeated and map fields
+	// without a hint. If zero, it is 1.
+	Count int
+
+	// Length is the typical length in bytes of string and bytes values
+	// without a hint. If zero, it is 16.
+	Length int
+}
+
+// FieldSizeHint describes the expected values of a field.
+// Zero values mean that no hint is given.
+type FieldSizeHint struct {
+	// Absent specifies that the field is typically not populated.
+	Absent bool
+
+	// Count and MaxCount are the typical and maximum number of elements
+	// of a repeated or map field. Without MaxCount, the maximum size
+	// of the field is unbounded.
+	Count, MaxCount int
+
+	// Size and MaxSize are the typical and maximum size in bytes of each
+	// value of the field. For string and bytes fields, this is the length
+	// of the value; without MaxSize, the maximum size of the field is
+	// unbounded. For fields encoded as varints, this is the size of the
+	// encoded value; the typical size is 1 for bools and enums and 2 otherwise.
+	// The hint is ignored for fields of other kinds and for map fields,
+	// whose keys and values are hinted by the fields of the map entry.
+	Size, MaxSize int
+}
+
+// EstimateSize predicts the size of the wire-format encoding of messages
+// of type md, such as for capacity planning before real data is available.
+//
+// The minimum size is that of a message with only its required fields
+// populated with the smallest values. The typical size is that of a message
+// with every field populated, except for those hinted to be absent,
+// where only the largest member of a oneof is populated.
+// Fields of recursive messages are considered absent when typical,
+// and make the maximum size unbounded. Extensions and unknown fields
+// are not accounted for.
+func EstimateSize(md protoreflect.MessageDescriptor, hints SizeHints) MessageSizeEstimate {
+	if hints.Count == 0 {
+		hints.Count = 1
+	}
+	if hints.Length == 0 {
+		hints.Length = 16
+	}
+	e := sizeEstimator{hints: hints, active: make(map[protoreflect.FullName]bool)}
+	s, fields := e.message(md)
+	return MessageSizeEstimate{SizeEstimate: s, Fields: fields}
+}
+
+type sizeEstimator struct {
+	hints  SizeHints
+	active map[protoreflect.FullName]bool // messages being estimated
+}
+
+func (e *sizeEstimator) message(md protoreflect.MessageDescriptor) (SizeEstimate, []FieldSizeEstimate) {
+	if e.active[md.FullName()] {
+		return SizeEstimate{Max: UnboundedSize}, nil
+	}
+	e.active[md.FullName()] = true
+	defer delete(e.active, md.FullName())
+
+	var s SizeEstimate
+	fds := md.Fields()
+	fields := make([]FieldSizeEstimate, fds.Len())
+	for i := range fields {
+		fd := fds.Get(i)
+		fields[i] = FieldSizeEstimate{Field: fd, SizeEstimate: e.field(fd)}
+		if od := fd.ContainingOneof(); od == nil || od.IsSynthetic() {
+			s = s.add(fields[i].SizeEstimate)
+		}
+	}
+	ods := md.Oneofs()
+	for i := 0; i < ods.Len(); i++ {
+		od := ods.Get(i)
+		if od.IsSynthetic() {
+			continue
+		}
+		var largest SizeEstimate
+		for j := 0; j < od.Fields().Len(); j++ {
+			f := fields[od.Fields().Get(j).Index()]
+			largest.Typical = max(largest.Typical, f.Typical)
+			if largest.Max != UnboundedSize && (f.Max == UnboundedSize || f.Max > largest.Max) {
+				largest.Max = f.Max
+			}
+		}
+		s = s.add(largest)
+	}
+	return s, fields
+}
+
+func (e *sizeEstimator) field(fd protoreflect.FieldDescriptor) SizeEstimate {
+	h := e.hints.Fields[fd.FullName()]
+	tag := protowire.SizeTag(fd.Number())
+	var s SizeEstimate
+	switch {
+	case fd.IsMap():
+		// Each entry is a message with the key and value fields populated.
+		k, v := fd.MapKey(), fd.MapValue()
+		entry := fixedSize(protowire.SizeTag(k.Number())).add(e.value(k))
+		entry = entry.add(fixedSize(protowire.SizeTag(v.Number()))).add(e.value(v))
+		s = fixedSize(tag).add(entry.lengthPrefixed()).repeat(e.count(h))
+	case fd.IsPacked():
+		v := e.value(fd).repeat(e.count(h))
+		s = fixedSize(tag).add(v.lengthPrefixed())
+	case fd.IsList():
+		s = fixedSize(tag).add(e.value(fd)).repeat(e.count(h))
+	default:
+		s = fixedSize(tag).add(e.value(fd))
+	}
+	if fd.IsList() || fd.IsMap() || fd.Cardinality() != protoreflect.Required {
+		s.Min = 0
+	}
+	if h.Absent || e.isRecursive(fd) {
+		s.Typical = 0
+	}
+	return s
+}
+
+// isRecursive reports whether the values of fd are messages
+// of a type that is being estimated.
+func (e *sizeEstimator) isRecursive(fd protoreflect.FieldDescriptor) bool {
+	if fd.IsMap() {
+		fd = fd.MapValue()
+	}
+	return fd.Message() != nil && e.active[fd.Message().FullName()]
+}
+
+// count returns the estimate for the number of elements of a repeated field.
+func (e *sizeEstimator) count(h FieldSizeHint) SizeEstimate {
+	n := SizeEstimate{Typical: e.hints.Count, Max: UnboundedSize}
+	if h.Count > 0 {
+		n.Typical = h.Count
+	}
+	if h.MaxCount > 0 {
+		n.Max = h.MaxCount
+		n.Typical = min(n.Typical, n.Max)
+	}
+	return n
+}
+
+// value returns the estimate for the encoding of a single value of fd,
+// excluding its tag.
+func (e *sizeEstimator) value(fd protoreflect.FieldDescriptor) SizeEstimate {
+	h := e.hints.Fields[fd.FullName()]
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return e.varint(h, 1, 1)
+	case protoreflect.EnumKind:
+		return e.varint(h, 1, protowire.SizeVarint(1<<64-1))
+	case protoreflect.Int32Kind, protoreflect.Int64Kind, protoreflect.Uint64Kind, protoreflect.Sint64Kind:
+		return e.varint(h, 2, protowire.SizeVarint(1<<64-1))
+	case protoreflect.Uint32Kind, protoreflect.Sint32Kind:
+		return e.varint(h, 2, protowire.SizeVarint(1<<32-1))
+	case protoreflect.Fixed32Kind, protoreflect.Sfixed32Kind, protoreflect.FloatKind:
+		return fixedSize(protowire.SizeFixed32())
+	case protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind, protoreflect.DoubleKind:
+		return fixedSize(protowire.SizeFixed64())
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		n := SizeEstimate{Typical: e.hints.Length, Max: UnboundedSize}
+		if h.Size > 0 {
+			n.Typical = h.Size
+		}
+		if h.MaxSize > 0 {
+			n.Max = h.MaxSize
+			n.Typical = min(n.Typical, n.Max)
+		}
+		return n.lengthPrefixed()
+	case protoreflect.GroupKind:
+		s, _ := e.message(fd.Message())
+		return s.add(fixedSize(protowire.SizeTag(fd.Number())))
+	default: // protoreflect.MessageKind
+		s, _ := e.message(fd.Message())
+		return s.lengthPrefixed()
+	}
+}
+
+func (e *sizeEstimator) varint(h FieldSizeHint, typical, limit int) SizeEstimate {
+	if h.Size > 0 {
+		typical = min(h.Size, limit)
+	}
+	if h.MaxSize > 0 {
+		limit = min(h.MaxSize, limit)
+		typical = min(typical, limit)
+	}
+	return SizeEstimate{Min: 1, Typical: typical, Max: limit}
+}
+
+func fixedSize(n int) SizeEstimate {
+	return SizeEstimate{Min: n, Typical: n, Max: n}
+}
+
+func (s SizeEstimate) add(t SizeEstimate) SizeEstimate {
+	s.Min += t.Min
+	s.Typical += t.Typical
+	if s.Max == UnboundedSize || t.Max == UnboundedSize {
+		s.Max = UnboundedSize
+	} else {
+		s.Max += t.Max
+	}
+	return s
+}
+
+// repeat returns the estimate for n values with the size of s.
+func (s SizeEstimate) repeat(n SizeEstimate) SizeEstimate {
+	s.Min *= n.Min
+	s.Typical *= n.Typical
+	if s.Max != UnboundedSize && n.Max != UnboundedSize {
+		s.Max *= n.Max
+	} else if s.Max != 0 && n.Max != 0 {
+		s.Max = UnboundedSize
+	}
+	return s
+}
+
+// lengthPrefixed returns the estimate for a length-prefixed value
+// whose contents have the size of s.
+func (s SizeEstimate) lengthPrefixed() SizeEstimate {
+	s.Min += protowire.SizeVarint(uint64(s.Min))
+	s.Typical += protowire.SizeVarint(uint64(s.Typical))
+	if s.Max != UnboundedSize {
+		s.Max += protowire.SizeVarint(uint64(s.Max))
+	}
+	return s
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestEstimateSize(t *testing.T) {
+	fdp := new(descriptorpb.FileDescriptorProto)
+	if err := prototext.Unmarshal([]byte(`
+		syntax: "proto2"
+		name: "estimate.proto"
+		package: "estimate"
+		message_type: [{
+			name: "M"
+			field: [
+				{name: "id" number: 1 label: LABEL_REQUIRED type: TYPE_INT32},
+				{name: "name" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING},
+				{name: "vals" number: 3 label: LABEL_REPEATED type: TYPE_FIXED32 options: {packed: true}},
+				{name: "child" number: 4 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".estimate.M"},
+				{name: "b" number: 5 label: LABEL_OPTIONAL type: TYPE_BOOL oneof_index: 0},
+				{name: "d" number: 6 label: LABEL_OPTIONAL type: TYPE_DOUBLE oneof_index: 0},
+				{name: "m" number: 7 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".estimate.M.MEntry"}
+			]
+			oneof_decl: [{name: "o"}]
+			nested_type: [{
+				name: "MEntry"
+				field: [
+					{name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING},
+					{name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_INT32}
+				]
+				options: {map_entry: true}
+			}]
+		}, {
+			name: "N"
+			field: [
+				{name: "x" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32},
+				{name: "y" number: 2 label: LABEL_OPTIONAL type: TYPE_BYTES},
+				{name: "z" number: 3 label: LABEL_REPEATED type: TYPE_SINT32}
+			]
+		}]
+	`), fdp); err != nil {
+		t.Fatal(err)
+	}
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	md := fd.Messages().ByName("M")
+	got := proto.EstimateSize(md, proto.SizeHints{
+		Fields: map[protoreflect.FullName]proto.FieldSizeHint{
+			"estimate.M.name": {Size: 5, MaxSize: 10},
+			"estimate.M.vals": {Count: 4, MaxCount: 8},
+		},
+	})
+	want := proto.SizeEstimate{Min: 2, Typical: 60, Max: proto.UnboundedSize}
+	if got.SizeEstimate != want {
+		t.Errorf("EstimateSize(M) = %+v, want %+v", got.SizeEstimate, want)
+	}
+	wantFields := map[protoreflect.Name]proto.SizeEstimate{
+		"id":    {Min: 2, Typical: 3, Max: 11},
+		"name":  {Min: 0, Typical: 7, Max: 12},
+		"vals":  {Min: 0, Typical: 18, Max: 34},
+		"child": {Min: 0, Typical: 0, Max: proto.UnboundedSize},
+		"b":     {Min: 0, Typical: 2, Max: 2},
+		"d":     {Min: 0, Typical: 9, Max: 9},
+		"m":     {Min: 0, Typical: 23, Max: proto.UnboundedSize},
+	}
+	for _, f := range got.Fields {
+		if want := wantFields[f.Field.Name()]; f.SizeEstimate != want {
+			t.Errorf("EstimateSize(M) field %v = %+v, want %+v", f.Field.Name(), f.SizeEstimate, want)
+		}
+	}
+
+	// A message populated with values of the hinted sizes
+	// has the typical size.
+	m := dynamicpb.NewMessage(md)
+	s := `id: 300 name: "abcde" vals: [1, 2, 3, 4] d: 1 m: {key: "` + strings.Repeat("k", 16) + `" value: 300}`
+	if err := prototext.Unmarshal([]byte(s), m); err != nil {
+		t.Fatal(err)
+	}
+	if n := proto.Size(m); n != want.Typical {
+		t.Errorf("Size() of typical message = %d, want %d", n, want.Typical)
+	}
+
+	got = proto.EstimateSize(fd.Messages().ByName("N"), proto.SizeHints{
+		Fields: map[protoreflect.FullName]proto.FieldSizeHint{
+			"estimate.N.y": {MaxSize: 100},
+			"estimate.N.z": {Absent: true, MaxCount: 2, Size: 1, MaxSize: 3},
+		},
+		Length: 20,
+	})
+	want = proto.SizeEstimate{Min: 0, Typical: 3 + 22, Max: 11 + 102 + 8}
+	if got.SizeEstimate != want {
+		t.Errorf("EstimateSize(N) = %+v, want %+v", got.SizeEstimate, want)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ApplyFieldMask updates the fields of dst named by the paths of a field mask
+// to their values in src, such as to implement an update request with
+// a google.protobuf.FieldMask. The messages must have the same descriptor.
+//
+// Each path is a sequence of field names separated by dots (e.g., "foo.bar"),
+// where group fields are named by the name of their message.
+// The intermediate fields of a path must be singular message fields.
+// The last field of a path is replaced with a copy of its value in src,
+// or cleared in dst if it is not populated in src.
+// Repeated and map fields are replaced as a whole.
+//
+// Oneofs are handled as follows:
+//
+//   - Setting a member of a oneof clears the other members in dst,
+//     as does updating a field within a message-typed member.
+//
+//   - Clearing a member of a oneof leaves the oneof unchanged
+//     if another member is set in dst.
+//
+//   - The last element of a path may be the name of a oneof,
+//     which selects the member that is set in src. That member is copied,
+//     or the oneof is cleared in dst if no member is set in src.
+//
+// All paths are checked to be valid for the message type before dst is
+// modified. Extension fields cannot be named by a path.
+func ApplyFieldMask(dst, src Message, paths ...string) error {
+	dstMsg, srcMsg := dst.ProtoReflect(), src.ProtoReflect()
+	if dstMsg.Descriptor() != srcMsg.Descriptor() {
+		if got, want := dstMsg.Descriptor().FullName(), srcMsg.Descriptor().FullName(); got != want {
+			panic(fmt.Sprintf("descriptor mismatch: %v != %v", got, want))
+		}
+		panic("descriptor mismatch")
+	}
+	md := dstMsg.Descriptor()
+	for _, path := range paths {
+		if err := checkMaskPath(md, path); err != nil {
+			return err
+		}
+	}
+	for _, path := range paths {
+		applyMaskPath(dstMsg, srcMsg, path)
+	}
+	return nil
+}
+
+// maskPathElem resolves the name of a field, or of a oneof,
+// within a field mask path.
+func maskPathElem(md protoreflect.MessageDescriptor, name string) (protoreflect.FieldDescriptor, protoreflect.OneofDescriptor) {
+	fd := md.Fields().ByName(protoreflect.Name(name))
+	// The name of a group field is the name of its message.
+	if fd == nil {
+		gd := md.Fields().ByName(protoreflect.Name(strings.ToLower(name)))
+		if gd != nil && gd.Kind() == protoreflect.GroupKind && string(gd.Message().Name()) == name {
+			fd = gd
+		}
+	} else if fd.Kind() == protoreflect.GroupKind && string(fd.Message().Name()) != name {
+		fd = nil
+	}
+	if fd != nil {
+		return fd, nil
+	}
+	if od := md.Oneofs().ByName(protoreflect.Name(name)); od != nil && !od.IsSynthetic() {
+		return nil, od
+	}
+	return nil, nil
+}
+
+// checkMaskPath reports an error if the path is not valid for md.
+func checkMaskPath(md protoreflect.MessageDescriptor, path string) error {
+	for rest := path; ; {
+		name, next, nested := strings.Cut(rest, ".")
+		fd, od := maskPathElem(md, name)
+		switch {
+		case fd == nil && od == nil:
+			return errors.New("invalid field mask path %q: %v has no field %q", path, md.FullName(), name)
+		case nested && (od != nil || fd.IsList() || fd.IsMap() || fd.Message() == nil):
+			return errors.New("invalid field mask path %q: %q is not a singular message field", path, name)
+		case !nested:
+			return nil
+		}
+		md, rest = fd.Message(), next
+	}
+}
+
+func applyMaskPath(dst, src protoreflect.Message, path string) {
+	name, rest, nested := strings.Cut(path, ".")
+	fd, od := maskPathElem(dst.Descriptor(), name)
+	switch {
+	case od != nil:
+		if fd := src.WhichOneof(od); fd != nil {
+			copyField(dst, src, fd)
+		} else if fd := dst.WhichOneof(od); fd != nil {
+			dst.Clear(fd)
+		}
+	case !nested:
+		copyField(dst, src, fd)
+	case src.Has(fd) || dst.Has(fd):
+		// An unpopulated field in src is treated as an empty message,
+		// while an unpopulated field in dst is populated,
+		// clearing the other members of its oneof.
+		applyMaskPath(dst.Mutable(fd).Message(), src.Get(fd).Message(), rest)
+	}
+}
+
+// copyField replaces the value of fd in dst with a copy of its value in src,
+// clearing it if it is not populated in src.
+func copyField(dst, src protoreflect.Message, fd protoreflect.FieldDescriptor) {
+	if !src.Has(fd) {
+		if od := fd.ContainingOneof(); od == nil || dst.WhichOneof(od) == fd {
+			dst.Clear(fd)
+		}
+		return
+	}
+	var o MergeOptions
+	dst.Clear(fd)
+	v := src.Get(fd)
+	switch {
+	case fd.IsList():
+		o.mergeList(dst.Mutable(fd).List(), v.List(), fd)
+	case fd.IsMap():
+		o.mergeMap(dst.Mutable(fd).Map(), v.Map(), fd.MapValue())
+	case fd.Message() != nil:
+		o.mergeMessage(dst.Mutable(fd).Message(), v.Message())
+	case fd.Kind() == protoreflect.BytesKind:
+		dst.Set(fd, o.cloneBytes(v))
+	default:
+		dst.Set(fd, v)
+	}
+}
+
+// ClearFields clears the fields of m named by the paths of a field mask,
+// such as to redact the fields of a message before returning it.
+// Paths are interpreted as for [ApplyFieldMask].
+//
+// Intermediate fields of a path that are not populated are left unpopulated.
+// Repeated and map fields are cleared as a whole.
+// The last element of a path may be the name of a oneof,
+// which clears whichever member of the oneof is set.
+//
+// All paths are checked to be valid for the message type before m is
+// modified.
+func ClearFields(m Message, paths ...string) error {
+	mr := m.ProtoReflect()
+	for _, path := range paths {
+		if err := checkMaskPath(mr.Descriptor(), path); err != nil {
+			return err
+		}
+	}
+	for _, path := range paths {
+		clearMaskPath(mr, path)
+	}
+	return nil
+}
+
+func clearMaskPath(m protoreflect.Message, path string) {
+	name, rest, nested := strings.Cut(path, ".")
+	fd, od := maskPathElem(m.Descriptor(), name)
+	switch {
+	case od != nil:
+		if fd := m.WhichOneof(od); fd != nil {
+			m.Clear(fd)
+		}
+	case !nested:
+		m.Clear(fd)
+	case m.Has(fd):
+		clearMaskPath(m.Mutable(fd).Message(), rest)
+	}
+}
+
+// KeepFields clears all fields of m except those named by the paths of
+// a field mask, such as to implement the read mask of a request.
+// Paths are interpreted as for [ApplyFieldMask].
+//
+// A field named by a path is kept as a whole, including any repeated or map
+// field. A message field that is an intermediate field of a path is kept
+// only if it is populated, with only the fields named by the remainder of
+// its paths. The last element of a path may be the name of a oneof,
+// which keeps whichever member of the oneof is set.
+// Extension fields and unknown fields are always cleared.
+//
+// All paths are checked to be valid for the message type before m is
+// modified.
+func KeepFields(m Message, paths ...string) error {
+	mr := m.ProtoReflect()
+	for _, path := range paths {
+		if err := checkMaskPath(mr.Descriptor(), path); err != nil {
+			return err
+		}
+	}
+	keepMaskPaths(mr, paths)
+	return nil
+}
+
+func keepMaskPaths(m protoreflect.Message, paths []string) {
+	md := m.Descriptor()
+	keep := make(map[protoreflect.FieldDescriptor]bool)
+	keepOneof := make(map[protoreflect.OneofDescriptor]bool)
+	nested := make(map[protoreflect.FieldDescriptor][]string)
+	for _, path := range paths {
+		name, rest, ok := strings.Cut(path, ".")
+		switch fd, od := maskPathElem(md, name); {
+		case od != nil:
+			keepOneof[od] = true
+		case !ok:
+			keep[fd] = true
+		default:
+			nested[fd] = append(nested[fd], rest)
+		}
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		switch od := fd.ContainingOneof(); {
+		case keep[fd] || (od != nil && keepOneof[od]):
+		case nested[fd] != nil:
+			keepMaskPaths(m.Mutable(fd).Message(), nested[fd])
+		default:
+			m.Clear(fd)
+		}
+		return true
+	})
+	if len(m.GetUnknown()) > 0 {
+		m.SetUnknown(nil)
+	}
+}
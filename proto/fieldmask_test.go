@@ -0,0 +1,298 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestApplyFieldMask(t *testing.T) {
+	nested := func(a int32) *testpb.TestAllTypes_NestedMessage {
+		return &testpb.TestAllTypes_NestedMessage{A: proto.Int32(a)}
+	}
+	tests := []struct {
+		desc     string
+		dst, src *testpb.TestAllTypes
+		paths    []string
+		want     *testpb.TestAllTypes
+	}{{
+		desc:  "scalars",
+		dst:   &testpb.TestAllTypes{OptionalInt32: proto.Int32(1), OptionalString: proto.String("a"), OptionalInt64: proto.Int64(3)},
+		src:   &testpb.TestAllTypes{OptionalInt32: proto.Int32(2), OptionalInt64: proto.Int64(4)},
+		paths: []string{"optional_int32", "optional_string"},
+		want:  &testpb.TestAllTypes{OptionalInt32: proto.Int32(2), OptionalInt64: proto.Int64(3)},
+	}, {
+		desc:  "messages are replaced",
+		dst:   &testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1), Corecursive: &testpb.TestAllTypes{}}},
+		src:   &testpb.TestAllTypes{OptionalNestedMessage: nested(2)},
+		paths: []string{"optional_nested_message"},
+		want:  &testpb.TestAllTypes{OptionalNestedMessage: nested(2)},
+	}, {
+		desc:  "nested fields",
+		dst:   &testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1), Corecursive: &testpb.TestAllTypes{}}},
+		src:   &testpb.TestAllTypes{OptionalNestedMessage: nested(2), Optionalgroup: &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(3)}},
+		paths: []string{"optional_nested_message.a", "OptionalGroup.a", "optional_foreign_message.c"},
+		want: &testpb.TestAllTypes{
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2), Corecursive: &testpb.TestAllTypes{}},
+			Optionalgroup:         &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(3)},
+		},
+	}, {
+		desc:  "nested fields cleared",
+		dst:   &testpb.TestAllTypes{OptionalNestedMessage: nested(1)},
+		src:   &testpb.TestAllTypes{},
+		paths: []string{"optional_nested_message.a"},
+		want:  &testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{}},
+	}, {
+		desc:  "repeated and map fields are replaced",
+		dst:   &testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}, MapStringString: map[string]string{"a": "1", "b": "2"}},
+		src:   &testpb.TestAllTypes{RepeatedInt32: []int32{3}, MapStringString: map[string]string{"c": "3"}},
+		paths: []string{"repeated_int32", "map_string_string"},
+		want:  &testpb.TestAllTypes{RepeatedInt32: []int32{3}, MapStringString: map[string]string{"c": "3"}},
+	}, {
+		desc:  "setting oneof member clears siblings",
+		dst:   &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+		src:   &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofString{OneofString: "a"}},
+		paths: []string{"oneof_string"},
+		want:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofString{OneofString: "a"}},
+	}, {
+		desc:  "clearing unset oneof member",
+		dst:   &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+		src:   &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofString{OneofString: "a"}},
+		paths: []string{"oneof_bytes"},
+		want:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+	}, {
+		desc:  "clearing set oneof member",
+		dst:   &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+		src:   &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofString{OneofString: "a"}},
+		paths: []string{"oneof_uint32"},
+		want:  &testpb.TestAllTypes{},
+	}, {
+		desc:  "nested field in oneof member",
+		dst:   &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+		src:   &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{OneofNestedMessage: nested(2)}},
+		paths: []string{"oneof_nested_message.a"},
+		want:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{OneofNestedMessage: nested(2)}},
+	}, {
+		desc:  "nested field in unset oneof member",
+		dst:   &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+		src:   &testpb.TestAllTypes{},
+		paths: []string{"oneof_nested_message.a"},
+		want:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+	}, {
+		desc:  "oneof name selects set member",
+		dst:   &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+		src:   &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{OneofNestedMessage: nested(2)}},
+		paths: []string{"oneof_field"},
+		want:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{OneofNestedMessage: nested(2)}},
+	}, {
+		desc:  "oneof name clears oneof",
+		dst:   &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+		src:   &testpb.TestAllTypes{},
+		paths: []string{"oneof_field"},
+		want:  &testpb.TestAllTypes{},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			src := proto.Clone(tt.src)
+			if err := proto.ApplyFieldMask(tt.dst, tt.src, tt.paths...); err != nil {
+				t.Fatalf("ApplyFieldMask() error: %v", err)
+			}
+			if !proto.Equal(tt.dst, tt.want) {
+				t.Errorf("ApplyFieldMask() mismatch:\ngot:  %v\nwant: %v", tt.dst, tt.want)
+			}
+			if !proto.Equal(tt.src, src) {
+				t.Errorf("ApplyFieldMask() modified src")
+			}
+		})
+	}
+
+	// Values are copied rather than shared.
+	dst := &testpb.TestAllTypes{}
+	src := &testpb.TestAllTypes{OptionalNestedMessage: nested(1), OptionalBytes: []byte("a")}
+	if err := proto.ApplyFieldMask(dst, src, "optional_nested_message", "optional_bytes"); err != nil {
+		t.Fatal(err)
+	}
+	if dst.OptionalNestedMessage == src.OptionalNestedMessage || &dst.OptionalBytes[0] == &src.OptionalBytes[0] {
+		t.Errorf("ApplyFieldMask() shares values between src and dst")
+	}
+}
+
+func TestApplyFieldMaskErrors(t *testing.T) {
+	for _, path := range []string{
+		"",
+		"no_such_field",
+		"optional_int32.a",
+		"optionalgroup",
+		"repeated_nested_message.a",
+		"map_string_nested_message.a",
+		"oneof_field.oneof_uint32",
+		"optional_nested_message.",
+		"optional_nested_message.no_such_field",
+	} {
+		dst := &testpb.TestAllTypes{OptionalInt32: proto.Int32(1)}
+		src := &testpb.TestAllTypes{}
+		if err := proto.ApplyFieldMask(dst, src, "optional_int32", path); err == nil {
+			t.Errorf("ApplyFieldMask(%q) succeeded, want error", path)
+		}
+		if dst.OptionalInt32 == nil {
+			t.Errorf("ApplyFieldMask(%q) modified dst despite invalid path", path)
+		}
+	}
+}
+
+func TestClearFields(t *testing.T) {
+	nested := func(a int32) *testpb.TestAllTypes_NestedMessage {
+		return &testpb.TestAllTypes_NestedMessage{A: proto.Int32(a)}
+	}
+	tests := []struct {
+		desc  string
+		m     *testpb.TestAllTypes
+		paths []string
+		want  *testpb.TestAllTypes
+	}{{
+		desc:  "scalars",
+		m:     &testpb.TestAllTypes{OptionalInt32: proto.Int32(1), OptionalString: proto.String("a"), OptionalInt64: proto.Int64(3)},
+		paths: []string{"optional_int32", "optional_string"},
+		want:  &testpb.TestAllTypes{OptionalInt64: proto.Int64(3)},
+	}, {
+		desc:  "nested fields",
+		m:     &testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1), Corecursive: &testpb.TestAllTypes{}}},
+		paths: []string{"optional_nested_message.a", "OptionalGroup.a"},
+		want:  &testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{Corecursive: &testpb.TestAllTypes{}}},
+	}, {
+		desc:  "repeated and map fields",
+		m:     &testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}, MapStringString: map[string]string{"a": "1"}, RepeatedString: []string{"a"}},
+		paths: []string{"repeated_int32", "map_string_string"},
+		want:  &testpb.TestAllTypes{RepeatedString: []string{"a"}},
+	}, {
+		desc:  "unset oneof member",
+		m:     &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+		paths: []string{"oneof_string", "oneof_nested_message.a"},
+		want:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+	}, {
+		desc:  "nested field in oneof member",
+		m:     &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{OneofNestedMessage: nested(1)}},
+		paths: []string{"oneof_nested_message.a"},
+		want:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{OneofNestedMessage: &testpb.TestAllTypes_NestedMessage{}}},
+	}, {
+		desc:  "oneof name",
+		m:     &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+		paths: []string{"oneof_field"},
+		want:  &testpb.TestAllTypes{},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := proto.ClearFields(tt.m, tt.paths...); err != nil {
+				t.Fatalf("ClearFields() error: %v", err)
+			}
+			if !proto.Equal(tt.m, tt.want) {
+				t.Errorf("ClearFields() mismatch:\ngot:  %v\nwant: %v", tt.m, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepFields(t *testing.T) {
+	nested := func(a int32) *testpb.TestAllTypes_NestedMessage {
+		return &testpb.TestAllTypes_NestedMessage{A: proto.Int32(a)}
+	}
+	tests := []struct {
+		desc  string
+		m     *testpb.TestAllTypes
+		paths []string
+		want  *testpb.TestAllTypes
+	}{{
+		desc:  "scalars",
+		m:     &testpb.TestAllTypes{OptionalInt32: proto.Int32(1), OptionalString: proto.String("a"), OptionalInt64: proto.Int64(3)},
+		paths: []string{"optional_int32", "optional_string"},
+		want:  &testpb.TestAllTypes{OptionalInt32: proto.Int32(1), OptionalString: proto.String("a")},
+	}, {
+		desc:  "nested fields",
+		m:     &testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1), Corecursive: &testpb.TestAllTypes{}}},
+		paths: []string{"optional_nested_message.a", "OptionalGroup.a"},
+		want:  &testpb.TestAllTypes{OptionalNestedMessage: nested(1)},
+	}, {
+		desc: "whole field and nested field",
+		m: &testpb.TestAllTypes{
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1), Corecursive: &testpb.TestAllTypes{}},
+		},
+		paths: []string{"optional_nested_message.a", "optional_nested_message"},
+		want: &testpb.TestAllTypes{
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1), Corecursive: &testpb.TestAllTypes{}},
+		},
+	}, {
+		desc:  "repeated and map fields",
+		m:     &testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}, MapStringString: map[string]string{"a": "1"}, RepeatedString: []string{"a"}},
+		paths: []string{"repeated_int32", "map_string_string"},
+		want:  &testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}, MapStringString: map[string]string{"a": "1"}},
+	}, {
+		desc:  "oneof member",
+		m:     &testpb.TestAllTypes{OptionalInt32: proto.Int32(1), OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+		paths: []string{"oneof_uint32", "oneof_string"},
+		want:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+	}, {
+		desc:  "other oneof member",
+		m:     &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 1}},
+		paths: []string{"oneof_string"},
+		want:  &testpb.TestAllTypes{},
+	}, {
+		desc:  "nested field in oneof member",
+		m:     &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{OneofNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1), Corecursive: &testpb.TestAllTypes{}}}},
+		paths: []string{"oneof_nested_message.a"},
+		want:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofNestedMessage{OneofNestedMessage: nested(1)}},
+	}, {
+		desc:  "oneof name",
+		m:     &testpb.TestAllTypes{OptionalInt32: proto.Int32(1), OneofField: &testpb.TestAllTypes_OneofString{OneofString: "a"}},
+		paths: []string{"oneof_field"},
+		want:  &testpb.TestAllTypes{OneofField: &testpb.TestAllTypes_OneofString{OneofString: "a"}},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := proto.KeepFields(tt.m, tt.paths...); err != nil {
+				t.Fatalf("KeepFields() error: %v", err)
+			}
+			if !proto.Equal(tt.m, tt.want) {
+				t.Errorf("KeepFields() mismatch:\ngot:  %v\nwant: %v", tt.m, tt.want)
+			}
+		})
+	}
+
+	// Extension and unknown fields are cleared.
+	m := &testpb.TestAllExtensions{}
+	proto.SetExtension(m, testpb.E_OptionalInt32, int32(1))
+	m.ProtoReflect().SetUnknown(protoreflect.RawFields{0x10, 0x01})
+	if err := proto.KeepFields(m); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(m, &testpb.TestAllExtensions{}) {
+		t.Errorf("KeepFields() did not clear extension and unknown fields: %v", m)
+	}
+}
+
+func TestClearAndKeepFieldsErrors(t *testing.T) {
+	for _, path := range []string{
+		"no_such_field",
+		"optional_int32.a",
+		"repeated_nested_message.a",
+	} {
+		for name, f := range map[string]func(proto.Message, ...string) error{
+			"ClearFields": proto.ClearFields,
+			"KeepFields":  proto.KeepFields,
+		} {
+			m := &testpb.TestAllTypes{OptionalInt32: proto.Int32(1), OptionalInt64: proto.Int64(1)}
+			if err := f(m, "optional_int32", path); err == nil {
+				t.Errorf("%v(%q) succeeded, want error", name, path)
+			}
+			if m.OptionalInt32 == nil || m.OptionalInt64 == nil {
+				t.Errorf("%v(%q) modified message despite invalid path", name, path)
+			}
+		}
+	}
+}
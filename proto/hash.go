@@ -0,0 +1,209 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/order"
+	"google.golang.org/protobuf/internal/pragma"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// HashOptions configures the hashing of messages.
+//
+// Example usage:
+//
+//	h := proto.HashOptions{IgnoreUnknown: true}.Hash(m)
+type HashOptions struct {
+	pragma.NoUnkeyedLiterals
+
+	// IgnoreUnknown specifies whether to ignore unknown fields,
+	// such that messages which differ only in their unknown fields
+	// have the same hash.
+	IgnoreUnknown bool
+}
+
+// Hash returns a 64-bit hash of the contents of m.
+// It is equivalent to HashOptions{}.Hash(m).
+func Hash(m Message) uint64 {
+	return HashOptions{}.Hash(m)
+}
+
+// Hash returns a 64-bit hash of the contents of m.
+//
+// Messages that are equal according to [Equal] have the same hash,
+// regardless of their concrete Go type. For example, a generated message
+// and a dynamic message with the same field values have the same hash.
+// The order of map entries, the order in which fields were set, and
+// the way the message would be serialized do not affect the hash.
+//
+// The hash is computed with the FNV-1a function over a canonical encoding
+// of the message contents and is stable across processes and platforms,
+// making it suitable for persistent caches and change detection.
+// It is not a cryptographic hash and must not be used where collisions
+// may be chosen by an adversary.
+func (o HashOptions) Hash(m Message) uint64 {
+	h := fnv.New64a()
+	o.hash(h, m)
+	return h.Sum64()
+}
+
+// Hash128 returns a 128-bit hash of the contents of m,
+// with the same properties as the 64-bit hash returned by [HashOptions.Hash].
+func (o HashOptions) Hash128(m Message) [16]byte {
+	h := fnv.New128a()
+	o.hash(h, m)
+	var b [16]byte
+	h.Sum(b[:0])
+	return b
+}
+
+// Markers distinguishing the parts of the canonical encoding of a message.
+const (
+	hashInvalid = iota
+	hashField
+	hashUnknown
+	hashEnd
+)
+
+func (o HashOptions) hash(h hash.Hash, m Message) {
+	w := hashWriter{o: o, h: h}
+	if m == nil || !m.ProtoReflect().IsValid() {
+		w.buf = append(w.buf, hashInvalid)
+	} else {
+		w.message(m.ProtoReflect())
+	}
+	w.flush()
+}
+
+// hashWriter writes the canonical encoding of a message to a hash,
+// buffering small writes.
+type hashWriter struct {
+	o   HashOptions
+	h   hash.Hash
+	buf []byte
+}
+
+func (w *hashWriter) flush() {
+	w.h.Write(w.buf)
+	w.buf = w.buf[:0]
+}
+
+func (w *hashWriter) message(m protoreflect.Message) {
+	order.RangeFields(m, order.NumberFieldOrder, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if len(w.buf) > 4096 {
+			w.flush()
+		}
+		w.buf = append(w.buf, hashField)
+		w.buf = protowire.AppendVarint(w.buf, uint64(fd.Number()))
+		w.field(fd, v)
+		return true
+	})
+	if !w.o.IgnoreUnknown {
+		w.unknown(m.GetUnknown())
+	}
+	w.buf = append(w.buf, hashEnd)
+}
+
+func (w *hashWriter) field(fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+	switch {
+	case fd.IsList():
+		l := v.List()
+		w.buf = protowire.AppendVarint(w.buf, uint64(l.Len()))
+		for i := 0; i < l.Len(); i++ {
+			w.value(fd, l.Get(i))
+		}
+	case fd.IsMap():
+		mp := v.Map()
+		w.buf = protowire.AppendVarint(w.buf, uint64(mp.Len()))
+		order.RangeEntries(mp, order.GenericKeyOrder, func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			w.value(fd.MapKey(), k.Value())
+			w.value(fd.MapValue(), v)
+			return true
+		})
+	default:
+		w.value(fd, v)
+	}
+}
+
+func (w *hashWriter) value(fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		var b byte
+		if v.Bool() {
+			b = 1
+		}
+		w.buf = append(w.buf, b)
+	case protoreflect.EnumKind:
+		w.buf = binary.LittleEndian.AppendUint64(w.buf, uint64(v.Enum()))
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		w.buf = binary.LittleEndian.AppendUint64(w.buf, uint64(v.Int()))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		w.buf = binary.LittleEndian.AppendUint64(w.buf, v.Uint())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		// Equal treats all NaNs as equal and -0 as equal to +0,
+		// so both are hashed in a canonical form.
+		f := v.Float()
+		switch {
+		case math.IsNaN(f):
+			f = math.NaN()
+		case f == 0:
+			f = 0
+		}
+		w.buf = binary.LittleEndian.AppendUint64(w.buf, math.Float64bits(f))
+	case protoreflect.StringKind:
+		w.bytes([]byte(v.String()))
+	case protoreflect.BytesKind:
+		w.bytes(v.Bytes())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		w.message(v.Message())
+	}
+}
+
+func (w *hashWriter) bytes(b []byte) {
+	w.buf = protowire.AppendVarint(w.buf, uint64(len(b)))
+	if len(b) > 1024 {
+		w.flush()
+		w.h.Write(b)
+		return
+	}
+	w.buf = append(w.buf, b...)
+}
+
+// unknown writes the unknown fields grouped by field number,
+// which is how Equal compares them.
+func (w *hashWriter) unknown(b protoreflect.RawFields) {
+	if len(b) == 0 {
+		return
+	}
+	fields := make(map[protoreflect.FieldNumber][]byte)
+	var nums []protoreflect.FieldNumber
+	for len(b) > 0 {
+		num, _, n := protowire.ConsumeField(b)
+		if n < 0 {
+			// Hash malformed data as is.
+			num, n = 0, len(b)
+		}
+		if _, ok := fields[num]; !ok {
+			nums = append(nums, num)
+		}
+		fields[num] = append(fields[num], b[:n]...)
+		b = b[n:]
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	for _, num := range nums {
+		w.buf = append(w.buf, hashUnknown)
+		w.buf = protowire.AppendVarint(w.buf, uint64(num))
+		w.bytes(fields[num])
+	}
+}
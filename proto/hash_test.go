@@ -0,0 +1,157 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"math"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protopack"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestHash(t *testing.T) {
+	unknown := func(m *testpb.TestAllTypes, fields protopack.Message) *testpb.TestAllTypes {
+		m.ProtoReflect().SetUnknown(fields.Marshal())
+		return m
+	}
+	tests := []struct {
+		desc string
+		x, y proto.Message
+		opts proto.HashOptions
+		want bool // whether the hashes are equal
+	}{{
+		desc: "empty",
+		x:    &testpb.TestAllTypes{},
+		y:    &testpb.TestAllTypes{},
+		want: true,
+	}, {
+		desc: "nil and empty",
+		x:    (*testpb.TestAllTypes)(nil),
+		y:    &testpb.TestAllTypes{},
+	}, {
+		desc: "unset and zero",
+		x:    &testpb.TestAllTypes{},
+		y:    &testpb.TestAllTypes{OptionalInt32: proto.Int32(0)},
+	}, {
+		desc: "different values",
+		x:    &testpb.TestAllTypes{OptionalInt32: proto.Int32(1)},
+		y:    &testpb.TestAllTypes{OptionalInt32: proto.Int32(2)},
+	}, {
+		desc: "different fields",
+		x:    &testpb.TestAllTypes{OptionalInt32: proto.Int32(1)},
+		y:    &testpb.TestAllTypes{OptionalInt64: proto.Int64(1)},
+	}, {
+		desc: "NaN",
+		x:    &testpb.TestAllTypes{OptionalDouble: proto.Float64(math.NaN())},
+		y:    &testpb.TestAllTypes{OptionalDouble: proto.Float64(math.Float64frombits(0x7ff8000000000001))},
+		want: true,
+	}, {
+		desc: "negative zero",
+		x:    &testpb.TestAllTypes{OptionalFloat: proto.Float32(0)},
+		y:    &testpb.TestAllTypes{OptionalFloat: proto.Float32(float32(math.Copysign(0, -1)))},
+		want: true,
+	}, {
+		desc: "split strings",
+		x:    &testpb.TestAllTypes{RepeatedString: []string{"ab", "c"}},
+		y:    &testpb.TestAllTypes{RepeatedString: []string{"a", "bc"}},
+	}, {
+		desc: "list order",
+		x:    &testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}},
+		y:    &testpb.TestAllTypes{RepeatedInt32: []int32{2, 1}},
+	}, {
+		desc: "maps",
+		x:    &testpb.TestAllTypes{MapStringString: map[string]string{"a": "1", "b": "2", "c": "3"}},
+		y:    &testpb.TestAllTypes{MapStringString: map[string]string{"c": "3", "b": "2", "a": "1"}},
+		want: true,
+	}, {
+		desc: "map values",
+		x:    &testpb.TestAllTypes{MapInt32Int32: map[int32]int32{1: 2}},
+		y:    &testpb.TestAllTypes{MapInt32Int32: map[int32]int32{2: 1}},
+	}, {
+		desc: "nested messages",
+		x:    &testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)}},
+		y:    &testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{Corecursive: &testpb.TestAllTypes{}}},
+	}, {
+		desc: "unknown fields",
+		x:    &testpb.TestAllTypes{},
+		y:    unknown(&testpb.TestAllTypes{}, protopack.Message{protopack.Tag{Number: 1000, Type: protopack.VarintType}, protopack.Varint(1)}),
+	}, {
+		desc: "ignored unknown fields",
+		x:    &testpb.TestAllTypes{},
+		y:    unknown(&testpb.TestAllTypes{}, protopack.Message{protopack.Tag{Number: 1000, Type: protopack.VarintType}, protopack.Varint(1)}),
+		opts: proto.HashOptions{IgnoreUnknown: true},
+		want: true,
+	}, {
+		desc: "unknown field order",
+		x: unknown(&testpb.TestAllTypes{}, protopack.Message{
+			protopack.Tag{Number: 1000, Type: protopack.VarintType}, protopack.Varint(1),
+			protopack.Tag{Number: 1001, Type: protopack.VarintType}, protopack.Varint(2),
+		}),
+		y: unknown(&testpb.TestAllTypes{}, protopack.Message{
+			protopack.Tag{Number: 1001, Type: protopack.VarintType}, protopack.Varint(2),
+			protopack.Tag{Number: 1000, Type: protopack.VarintType}, protopack.Varint(1),
+		}),
+		want: true,
+	}, {
+		desc: "extensions",
+		x: func() proto.Message {
+			m := &testpb.TestAllExtensions{}
+			proto.SetExtension(m, testpb.E_OptionalInt32, int32(1))
+			return m
+		}(),
+		y: func() proto.Message {
+			m := &testpb.TestAllExtensions{}
+			proto.SetExtension(m, testpb.E_OptionalInt32, int32(2))
+			return m
+		}(),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if eq := proto.Equal(tt.x, tt.y); eq && !tt.want {
+				t.Fatalf("test case is inconsistent with Equal")
+			}
+			hx, hy := tt.opts.Hash(tt.x), tt.opts.Hash(tt.y)
+			if got := hx == hy; got != tt.want {
+				t.Errorf("Hash(x) == Hash(y) = %v, want %v\nx: %v\ny: %v", got, tt.want, tt.x, tt.y)
+			}
+			hx128, hy128 := tt.opts.Hash128(tt.x), tt.opts.Hash128(tt.y)
+			if got := hx128 == hy128; got != tt.want {
+				t.Errorf("Hash128(x) == Hash128(y) = %v, want %v\nx: %v\ny: %v", got, tt.want, tt.x, tt.y)
+			}
+		})
+	}
+}
+
+func TestHashDynamic(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32:          proto.Int32(1),
+		OptionalString:         proto.String("hello"),
+		RepeatedBytes:          [][]byte{[]byte("a"), []byte("b")},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{"x": {A: proto.Int32(2)}},
+		Optionalgroup:          &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(3)},
+	}
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dm := dynamicpb.NewMessage(m.ProtoReflect().Descriptor())
+	if err := proto.Unmarshal(b, dm); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := proto.Hash(dm), proto.Hash(m); got != want {
+		t.Errorf("Hash(dynamic) = %#x, want %#x", got, want)
+	}
+
+	// The hash is stable across processes and must not change,
+	// since it may be persisted.
+	m = &testpb.TestAllTypes{OptionalInt32: proto.Int32(1), OptionalString: proto.String("hello")}
+	if got, want := proto.Hash(m), uint64(0x998e5f5002c5013d); got != want {
+		t.Errorf("Hash() = %#x, want %#x", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import "sync"
+
+// StringInterner is a table of strings used by [UnmarshalOptions.Interner]
+// so that identical strings decoded from the wire share memory.
+//
+// A StringInterner retains every string it returns, so a table that is
+// shared between many calls to Unmarshal should be discarded once the
+// set of distinct strings may have grown too large.
+// It is safe for concurrent use. The zero value is an empty table.
+type StringInterner struct {
+	mu      sync.Mutex
+	strings map[string]string
+}
+
+// InternString returns a string with the contents of b,
+// which is the same string for all calls with the same contents.
+func (t *StringInterner) InternString(b []byte) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// The conversion in the map index does not allocate.
+	if s, ok := t.strings[string(b)]; ok {
+		return s
+	}
+	if t.strings == nil {
+		t.strings = make(map[string]string)
+	}
+	s := string(b)
+	t.strings[s] = s
+	return s
+}
+
+// Len reports the number of distinct strings in the table.
+func (t *StringInterner) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.strings)
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestInternStrings(t *testing.T) {
+	b, err := proto.Marshal(&testpb.TestAllTypes{
+		OptionalString:  proto.String("value"),
+		RepeatedString:  []string{"value", "value", "other"},
+		MapStringString: map[string]string{"value": "value"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	same := func(a, b string) bool {
+		return unsafe.StringData(a) == unsafe.StringData(b)
+	}
+
+	m := &testpb.TestAllTypes{}
+	if err := (proto.UnmarshalOptions{InternStrings: true}).Unmarshal(b, m); err != nil {
+		t.Fatal(err)
+	}
+	s := m.GetOptionalString()
+	if !same(s, m.RepeatedString[0]) || !same(s, m.RepeatedString[1]) || !same(s, m.MapStringString["value"]) {
+		t.Errorf("identical strings do not share memory")
+	}
+	for k := range m.MapStringString {
+		if !same(s, k) {
+			t.Errorf("map key does not share memory with identical string")
+		}
+	}
+
+	// A shared interner is used across calls, including for dynamic messages
+	// decoded without the fast-path implementation.
+	interner := new(proto.StringInterner)
+	m1 := &testpb.TestAllTypes{}
+	m2 := dynamicpb.NewMessage(m1.ProtoReflect().Descriptor())
+	if err := (proto.UnmarshalOptions{Interner: interner}).Unmarshal(b, m1); err != nil {
+		t.Fatal(err)
+	}
+	if err := (proto.UnmarshalOptions{Interner: interner}).Unmarshal(b, m2); err != nil {
+		t.Fatal(err)
+	}
+	fd := m2.Descriptor().Fields().ByName("optional_string")
+	if !same(m1.GetOptionalString(), m2.Get(fd).String()) {
+		t.Errorf("strings decoded with a shared interner do not share memory")
+	}
+	if got, want := interner.Len(), 2; got != want {
+		t.Errorf("interner.Len() = %v, want %v", got, want)
+	}
+
+	// Without interning, the strings are distinct.
+	m = &testpb.TestAllTypes{}
+	if err := proto.Unmarshal(b, m); err != nil {
+		t.Fatal(err)
+	}
+	if same(m.GetOptionalString(), m.RepeatedString[0]) {
+		t.Errorf("strings share memory without interning")
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/encoding/messageset"
+	"google.golang.org/protobuf/internal/pragma"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Limits are resource limits on untrusted wire-format input,
+// as used by [UnmarshalOptions] and [SanitizeOptions].
+// A zero limit is not enforced.
+//
+// MaxMessageSize only compares the length of the input. Enforcing
+// MaxElements or MaxStringLength takes an extra pass over the input before
+// it is unmarshaled, which decodes every tag and length prefix and looks up
+// the field of each, so that no memory is allocated for input that would
+// be rejected. Leave them unset if the input is trusted.
+type Limits struct {
+	pragma.NoUnkeyedLiterals
+
+	// MaxMessageSize is the maximum size in bytes of the input.
+	MaxMessageSize int
+
+	// MaxElements is the maximum number of elements of a repeated or map field
+	// within the encoding of a single message, counting each element of
+	// a packed field.
+	MaxElements int
+
+	// MaxStringLength is the maximum length in bytes of a string or bytes
+	// value, including map keys and values.
+	MaxStringLength int
+}
+
+func (l Limits) any() bool {
+	return l.MaxMessageSize > 0 || l.MaxElements > 0 || l.MaxStringLength > 0
+}
+
+// LimitError is the error returned when the input exceeds a resource limit
+// of [Limits]. It matches [Error] when compared with errors.Is.
+type LimitError struct {
+	// Limit is the name of the exceeded limit, such as "MaxElements".
+	Limit string
+
+	// Field is the field whose value exceeds the limit, or the message
+	// containing the field if it is unknown.
+	// It is empty for limits on the size of the input.
+	Field protoreflect.FullName
+
+	// Size is the size or count which exceeds the limit.
+	Size int
+}
+
+func (e *LimitError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("proto: input of %d bytes exceeds %v", e.Size, e.Limit)
+	}
+	return fmt.Sprintf("proto: field %v: %d exceeds %v", e.Field, e.Size, e.Limit)
+}
+
+func (e *LimitError) Is(target error) bool {
+	return target == Error
+}
+
+// checkLimits reports an error if the encoding of a message of type md
+// exceeds the resource limits of the options. It does not otherwise
+// validate the input, which is left to the unmarshaler.
+func (o UnmarshalOptions) checkLimits(b []byte, md protoreflect.MessageDescriptor) error {
+	l := o.Limits
+	if l.MaxMessageSize > 0 && len(b) > l.MaxMessageSize {
+		return &LimitError{Limit: "MaxMessageSize", Size: len(b)}
+	}
+	if l.MaxElements <= 0 && l.MaxStringLength <= 0 {
+		return nil
+	}
+	if o.Resolver == nil {
+		o.Resolver = protoregistry.GlobalTypes
+	}
+	return o.checkMessageLimits(b, md, o.RecursionLimit)
+}
+
+func (o UnmarshalOptions) checkMessageLimits(b []byte, md protoreflect.MessageDescriptor, depth int) error {
+	depth--
+	if depth < 0 || messageset.IsMessageSet(md) {
+		// Leave reporting these to the unmarshaler.
+		return nil
+	}
+	l := o.Limits
+	fields := md.Fields()
+	var counts map[protoreflect.FieldNumber]int
+	for len(b) > 0 {
+		num, wtyp, tagLen := protowire.ConsumeTag(b)
+		if tagLen < 0 {
+			return errDecode
+		}
+		b = b[tagLen:]
+		valLen := protowire.ConsumeFieldValue(num, wtyp, b)
+		if valLen < 0 {
+			return errDecode
+		}
+		v := b[:valLen]
+		b = b[valLen:]
+
+		fd := fields.ByNumber(num)
+		if fd == nil && md.ExtensionRanges().Has(num) {
+			if xt, err := o.Resolver.FindExtensionByNumber(md.FullName(), num); err == nil {
+				fd = xt.TypeDescriptor()
+			}
+		}
+		if fd == nil {
+			continue
+		}
+
+		if l.MaxElements > 0 && (fd.IsList() || fd.IsMap()) {
+			n := 1
+			if wtyp == protowire.BytesType && fd.IsList() && wireTypes[fd.Kind()] != protowire.BytesType {
+				n = packedLen(v, fd)
+			}
+			if counts == nil {
+				counts = make(map[protoreflect.FieldNumber]int)
+			}
+			counts[num] += n
+			if counts[num] > l.MaxElements {
+				return &LimitError{Limit: "MaxElements", Field: fd.FullName(), Size: counts[num]}
+			}
+		}
+
+		switch {
+		case wtyp == protowire.StartGroupType && fd.Message() != nil:
+			v, _ := protowire.ConsumeGroup(num, v)
+			if err := o.checkMessageLimits(v, fd.Message(), depth); err != nil {
+				return err
+			}
+		case wtyp != protowire.BytesType:
+		case fd.Message() != nil:
+			v, _ := protowire.ConsumeBytes(v)
+			if err := o.checkMessageLimits(v, fd.Message(), depth); err != nil {
+				return err
+			}
+		case fd.Kind() == protoreflect.StringKind || fd.Kind() == protoreflect.BytesKind:
+			if v, _ := protowire.ConsumeBytes(v); l.MaxStringLength > 0 && len(v) > l.MaxStringLength {
+				return &LimitError{Limit: "MaxStringLength", Field: fd.FullName(), Size: len(v)}
+			}
+		}
+	}
+	return nil
+}
+
+// packedLen returns the number of elements in the packed encoding v
+// of a repeated field, including its length prefix.
+func packedLen(v []byte, fd protoreflect.FieldDescriptor) (n int) {
+	v, _ = protowire.ConsumeBytes(v)
+	switch {
+	case wireTypes[fd.Kind()] == protowire.Fixed32Type:
+		return len(v) / 4
+	case wireTypes[fd.Kind()] == protowire.Fixed64Type:
+		return len(v) / 8
+	}
+	// Each varint ends with a byte without the continuation bit.
+	for _, c := range v {
+		if c < 0x80 {
+			n++
+		}
+	}
+	return n
+}
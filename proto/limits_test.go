@@ -0,0 +1,128 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestUnmarshalLimits(t *testing.T) {
+	tests := []struct {
+		desc      string
+		opts      proto.UnmarshalOptions
+		m         proto.Message
+		wantLimit string
+		wantField protoreflect.FullName
+		wantSize  int
+	}{{
+		desc: "within limits",
+		opts: proto.UnmarshalOptions{Limits: proto.Limits{MaxMessageSize: 100, MaxElements: 3, MaxStringLength: 3}},
+		m: &testpb.TestAllTypes{
+			OptionalString:  proto.String("abc"),
+			RepeatedInt32:   []int32{1, 200, 3},
+			MapStringString: map[string]string{"a": "b"},
+		},
+	}, {
+		desc: "packed within limits",
+		opts: proto.UnmarshalOptions{Limits: proto.Limits{MaxElements: 3}},
+		m:    &testpb.TestPackedTypes{PackedInt32: []int32{1, 200, 3}, PackedDouble: []float64{1, 2, 3}},
+	}, {
+		desc:      "message size",
+		opts:      proto.UnmarshalOptions{Limits: proto.Limits{MaxMessageSize: 4}},
+		m:         &testpb.TestAllTypes{OptionalString: proto.String("abcde")},
+		wantLimit: "MaxMessageSize",
+		wantSize:  7,
+	}, {
+		desc:      "unpacked elements",
+		opts:      proto.UnmarshalOptions{Limits: proto.Limits{MaxElements: 2}},
+		m:         &testpb.TestAllTypes{RepeatedString: []string{"a", "b", "c"}},
+		wantLimit: "MaxElements",
+		wantField: "goproto.proto.test.TestAllTypes.repeated_string",
+		wantSize:  3,
+	}, {
+		desc:      "packed elements",
+		opts:      proto.UnmarshalOptions{Limits: proto.Limits{MaxElements: 2}},
+		m:         &testpb.TestPackedTypes{PackedInt32: []int32{1, 200, 3}},
+		wantLimit: "MaxElements",
+		wantField: "goproto.proto.test.TestPackedTypes.packed_int32",
+		wantSize:  3,
+	}, {
+		desc:      "packed fixed elements",
+		opts:      proto.UnmarshalOptions{Limits: proto.Limits{MaxElements: 2}},
+		m:         &testpb.TestPackedTypes{PackedDouble: []float64{1, 2, 3}},
+		wantLimit: "MaxElements",
+		wantField: "goproto.proto.test.TestPackedTypes.packed_double",
+		wantSize:  3,
+	}, {
+		desc:      "map entries",
+		opts:      proto.UnmarshalOptions{Limits: proto.Limits{MaxElements: 1}},
+		m:         &testpb.TestAllTypes{MapInt32Int32: map[int32]int32{1: 1, 2: 2}},
+		wantLimit: "MaxElements",
+		wantField: "goproto.proto.test.TestAllTypes.map_int32_int32",
+		wantSize:  2,
+	}, {
+		desc:      "nested string",
+		opts:      proto.UnmarshalOptions{Limits: proto.Limits{MaxStringLength: 3}},
+		m:         &testpb.TestAllTypes{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{Corecursive: &testpb.TestAllTypes{OptionalBytes: []byte("abcd")}}},
+		wantLimit: "MaxStringLength",
+		wantField: "goproto.proto.test.TestAllTypes.optional_bytes",
+		wantSize:  4,
+	}, {
+		desc:      "map key",
+		opts:      proto.UnmarshalOptions{Limits: proto.Limits{MaxStringLength: 3}},
+		m:         &testpb.TestAllTypes{MapStringString: map[string]string{"abcd": ""}},
+		wantLimit: "MaxStringLength",
+		wantField: "goproto.proto.test.TestAllTypes.MapStringStringEntry.key",
+		wantSize:  4,
+	}, {
+		desc:      "group",
+		opts:      proto.UnmarshalOptions{Limits: proto.Limits{MaxStringLength: 3}},
+		m:         &testpb.TestAllTypes{Optionalgroup: &testpb.TestAllTypes_OptionalGroup{OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{Corecursive: &testpb.TestAllTypes{OptionalString: proto.String("abcd")}}}},
+		wantLimit: "MaxStringLength",
+		wantField: "goproto.proto.test.TestAllTypes.optional_string",
+		wantSize:  4,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := proto.Marshal(tt.m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, m := range []proto.Message{
+				tt.m.ProtoReflect().Type().New().Interface(),
+				dynamicpb.NewMessage(tt.m.ProtoReflect().Descriptor()),
+			} {
+				err := tt.opts.Unmarshal(b, m)
+				if tt.wantLimit == "" {
+					if err != nil {
+						t.Errorf("Unmarshal() error: %v", err)
+					}
+					continue
+				}
+				var lerr *proto.LimitError
+				if !errors.As(err, &lerr) {
+					t.Fatalf("Unmarshal() error = %v, want *LimitError", err)
+				}
+				if lerr.Limit != tt.wantLimit || lerr.Field != tt.wantField || lerr.Size != tt.wantSize {
+					t.Errorf("Unmarshal() error = %+v, want {Limit:%v Field:%v Size:%v}", *lerr, tt.wantLimit, tt.wantField, tt.wantSize)
+				}
+				if !errors.Is(err, proto.Error) {
+					t.Errorf("Unmarshal() error does not match proto.Error")
+				}
+				if !strings.Contains(err.Error(), tt.wantLimit) {
+					t.Errorf("Unmarshal() error %q does not mention %v", err, tt.wantLimit)
+				}
+			}
+		})
+	}
+}
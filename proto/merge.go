@@ -7,6 +7,7 @@ package proto
 import (
 	"fmt"
 
+	"google.golang.org/protobuf/internal/pragma"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/runtime/protoiface"
 )
@@ -23,6 +24,41 @@ import (
 // It is semantically equivalent to unmarshaling the encoded form of src
 // into dst with the [UnmarshalOptions.Merge] option specified.
 func Merge(dst, src Message) {
+	MergeOptions{}.Merge(dst, src)
+}
+
+// MergeOptions configures the merger.
+//
+// The zero value merges with the same semantics as [Merge].
+// The options apply to all messages nested within the merged message.
+//
+// Example usage:
+//
+//	proto.MergeOptions{ReplaceLists: true}.Merge(dst, src)
+type MergeOptions struct {
+	pragma.NoUnkeyedLiterals
+
+	// ReplaceLists specifies that each non-empty list field in src replaces
+	// the corresponding list field in dst, rather than being appended to it.
+	ReplaceLists bool
+
+	// DeepMergeMaps specifies that a message value of a map entry in src is
+	// merged into the value of the entry with the same key in dst,
+	// rather than replacing it. Entries with scalar values are always replaced.
+	DeepMergeMaps bool
+
+	// ClearEmptyScalars specifies that singular scalar fields without presence
+	// (e.g., proto3 fields not marked optional) that hold the zero value in src
+	// clear the corresponding fields in dst. By default, such fields are left
+	// unchanged since the zero value is indistinguishable from being unset.
+	// Fields with presence are copied to dst whenever they are populated in src,
+	// regardless of this option.
+	ClearEmptyScalars bool
+}
+
+// Merge merges src into dst, which must be a message with the same descriptor,
+// according to the policies specified by o.
+func (o MergeOptions) Merge(dst, src Message) {
 	// TODO: Should nil src be treated as semantically equivalent to a
 	// untyped, read-only, empty message? What about a nil dst?
 
@@ -33,7 +69,7 @@ func Merge(dst, src Message) {
 		}
 		panic("descriptor mismatch")
 	}
-	mergeOptions{}.mergeMessage(dstMsg, srcMsg)
+	o.mergeMessage(dstMsg, srcMsg)
 }
 
 // Clone returns a deep copy of m.
@@ -55,17 +91,14 @@ func Clone(m Message) Message {
 		return src.Type().Zero().Interface()
 	}
 	dst := src.New()
-	mergeOptions{}.mergeMessage(dst, src)
+	MergeOptions{}.mergeMessage(dst, src)
 	return dst.Interface()
 }
 
-// mergeOptions provides a namespace for merge functions, and can be
-// exported in the future if we add user-visible merge options.
-type mergeOptions struct{}
-
-func (o mergeOptions) mergeMessage(dst, src protoreflect.Message) {
+func (o MergeOptions) mergeMessage(dst, src protoreflect.Message) {
+	// The fast-path merge methods only implement the default policies.
 	methods := protoMethods(dst)
-	if methods != nil && methods.Merge != nil {
+	if methods != nil && methods.Merge != nil && o == (MergeOptions{}) {
 		in := protoiface.MergeInput{
 			Destination: dst,
 			Source:      src,
@@ -80,9 +113,22 @@ func (o mergeOptions) mergeMessage(dst, src protoreflect.Message) {
 		panic(fmt.Sprintf("cannot merge into invalid %v message", dst.Descriptor().FullName()))
 	}
 
+	if o.ClearEmptyScalars {
+		fds := dst.Descriptor().Fields()
+		for i := 0; i < fds.Len(); i++ {
+			fd := fds.Get(i)
+			if !fd.HasPresence() && !fd.IsList() && !fd.IsMap() && fd.Message() == nil && !src.Has(fd) {
+				dst.Clear(fd)
+			}
+		}
+	}
+
 	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
 		switch {
 		case fd.IsList():
+			if o.ReplaceLists {
+				dst.Clear(fd)
+			}
 			o.mergeList(dst.Mutable(fd).List(), v.List(), fd)
 		case fd.IsMap():
 			o.mergeMap(dst.Mutable(fd).Map(), v.Map(), fd.MapValue())
@@ -101,7 +147,7 @@ func (o mergeOptions) mergeMessage(dst, src protoreflect.Message) {
 	}
 }
 
-func (o mergeOptions) mergeList(dst, src protoreflect.List, fd protoreflect.FieldDescriptor) {
+func (o MergeOptions) mergeList(dst, src protoreflect.List, fd protoreflect.FieldDescriptor) {
 	// Merge semantics appends to the end of the existing list.
 	for i, n := 0, src.Len(); i < n; i++ {
 		switch v := src.Get(i); {
@@ -117,10 +163,13 @@ func (o mergeOptions) mergeList(dst, src protoreflect.List, fd protoreflect.Fiel
 	}
 }
 
-func (o mergeOptions) mergeMap(dst, src protoreflect.Map, fd protoreflect.FieldDescriptor) {
-	// Merge semantics replaces, rather than merges into existing entries.
+func (o MergeOptions) mergeMap(dst, src protoreflect.Map, fd protoreflect.FieldDescriptor) {
+	// Merge semantics replaces, rather than merges into existing entries,
+	// unless deep merging is requested.
 	src.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
 		switch {
+		case fd.Message() != nil && o.DeepMergeMaps && dst.Has(k):
+			o.mergeMessage(dst.Mutable(k).Message(), v.Message())
 		case fd.Message() != nil:
 			dstv := dst.NewValue()
 			o.mergeMessage(dstv.Message(), v.Message())
@@ -134,6 +183,6 @@ func (o mergeOptions) mergeMap(dst, src protoreflect.Map, fd protoreflect.FieldD
 	})
 }
 
-func (o mergeOptions) cloneBytes(v protoreflect.Value) protoreflect.Value {
+func (o MergeOptions) cloneBytes(v protoreflect.Value) protoreflect.Value {
 	return protoreflect.ValueOfBytes(append([]byte{}, v.Bytes()...))
 }
@@ -651,6 +651,139 @@ func TestMerge(t *testing.T) {
 	}
 }
 
+func TestMergeOptions(t *testing.T) {
+	tests := []struct {
+		desc  string
+		opts  proto.MergeOptions
+		dst   protobuild.Message
+		src   protobuild.Message
+		want  protobuild.Message
+		types []proto.Message
+	}{{
+		desc: "replace lists",
+		opts: proto.MergeOptions{ReplaceLists: true},
+		dst: protobuild.Message{
+			"repeated_int32":          []int32{1, 2, 3},
+			"repeated_string":         []string{"a"},
+			"repeated_nested_message": []protobuild.Message{{"a": 1}},
+		},
+		src: protobuild.Message{
+			"repeated_int32":          []int32{4},
+			"repeated_nested_message": []protobuild.Message{{"a": 2}, {"a": 3}},
+		},
+		want: protobuild.Message{
+			"repeated_int32":          []int32{4},
+			"repeated_string":         []string{"a"},
+			"repeated_nested_message": []protobuild.Message{{"a": 2}, {"a": 3}},
+		},
+	}, {
+		desc: "replace lists in nested messages",
+		opts: proto.MergeOptions{ReplaceLists: true},
+		dst: protobuild.Message{
+			"optional_nested_message": protobuild.Message{
+				"corecursive": protobuild.Message{
+					"repeated_int32": []int32{1, 2},
+				},
+			},
+		},
+		src: protobuild.Message{
+			"optional_nested_message": protobuild.Message{
+				"corecursive": protobuild.Message{
+					"repeated_int32": []int32{3},
+				},
+			},
+		},
+		want: protobuild.Message{
+			"optional_nested_message": protobuild.Message{
+				"corecursive": protobuild.Message{
+					"repeated_int32": []int32{3},
+				},
+			},
+		},
+	}, {
+		desc: "deep merge maps",
+		opts: proto.MergeOptions{DeepMergeMaps: true},
+		dst: protobuild.Message{
+			"map_int32_int32": map[int32]int32{1: 1, 2: 2},
+			"map_string_nested_message": map[string]protobuild.Message{
+				"a": {"a": 1},
+				"b": {"a": 2},
+			},
+		},
+		src: protobuild.Message{
+			"map_int32_int32": map[int32]int32{2: 3},
+			"map_string_nested_message": map[string]protobuild.Message{
+				"a": {"corecursive": protobuild.Message{"optional_int32": 5}},
+				"c": {"a": 3},
+			},
+		},
+		want: protobuild.Message{
+			"map_int32_int32": map[int32]int32{1: 1, 2: 3},
+			"map_string_nested_message": map[string]protobuild.Message{
+				"a": {"a": 1, "corecursive": protobuild.Message{"optional_int32": 5}},
+				"b": {"a": 2},
+				"c": {"a": 3},
+			},
+		},
+		types: []proto.Message{&testpb.TestAllTypes{}, &test3pb.TestAllTypes{}},
+	}, {
+		desc: "clear empty scalars",
+		opts: proto.MergeOptions{ClearEmptyScalars: true},
+		dst: protobuild.Message{
+			"singular_int32":          1,
+			"singular_string":         "a",
+			"optional_int32":          2,
+			"repeated_int32":          []int32{1},
+			"singular_nested_message": protobuild.Message{"a": 1, "corecursive": protobuild.Message{"singular_int64": 4}},
+		},
+		src: protobuild.Message{
+			"singular_string":         "b",
+			"singular_nested_message": protobuild.Message{"corecursive": protobuild.Message{}},
+		},
+		want: protobuild.Message{
+			"singular_string":         "b",
+			"optional_int32":          2,
+			"repeated_int32":          []int32{1},
+			"singular_nested_message": protobuild.Message{"corecursive": protobuild.Message{}},
+		},
+		types: []proto.Message{&test3pb.TestAllTypes{}},
+	}, {
+		desc: "clear empty scalars with presence",
+		opts: proto.MergeOptions{ClearEmptyScalars: true},
+		dst: protobuild.Message{
+			"optional_int32":  1,
+			"optional_string": "a",
+		},
+		src: protobuild.Message{
+			"optional_string": "",
+		},
+		want: protobuild.Message{
+			"optional_int32":  1,
+			"optional_string": "",
+		},
+		types: []proto.Message{&testpb.TestAllTypes{}, &test3pb.TestAllTypes{}},
+	}}
+
+	for _, tt := range tests {
+		for _, mt := range templateMessages(tt.types...) {
+			t.Run(fmt.Sprintf("%s (%v)", tt.desc, mt.Descriptor().FullName()), func(t *testing.T) {
+				src := mt.New().Interface()
+				tt.src.Build(src.ProtoReflect())
+				want := mt.New().Interface()
+				tt.want.Build(want.ProtoReflect())
+
+				for _, dst := range []proto.Message{mt.New().Interface(), dynamicpb.NewMessage(mt.Descriptor())} {
+					tt.dst.Build(dst.ProtoReflect())
+					tt.opts.Merge(dst, src)
+					if !proto.Equal(dst, want) {
+						t.Errorf("Merge() into %T mismatch (-want +got):\n%v", dst, cmp.Diff(want, dst, protocmp.Transform()))
+					}
+				}
+			})
+		}
+	}
+}
+
 func TestMergeFromNil(t *testing.T) {
 	dst := &testpb.TestAllTypes{}
 	proto.Merge(dst, (*testpb.TestAllTypes)(nil))
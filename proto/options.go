@@ -0,0 +1,70 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// GetOptionByName retrieves the value of the custom option with the given
+// full name, such as "my.pkg.option_name", from the options of desc.
+// The extension is resolved using r, which defaults to
+// [protoregistry.GlobalTypes] if nil.
+//
+// Options for extensions that were not linked into the program when desc
+// was built are stored as unknown fields of the options message. These are
+// decoded using the extension type found in r, which may be constructed
+// dynamically (e.g., with [google.golang.org/protobuf/types/dynamicpb]).
+//
+// If the option is unpopulated, it returns the default value as
+// [GetExtension] does. It returns an error wrapping [protoregistry.NotFound]
+// if r has no such extension, and an error if the extension does not extend
+// the options message of desc.
+func GetOptionByName(desc protoreflect.Descriptor, name protoreflect.FullName, r protoregistry.ExtensionTypeResolver) (protoreflect.Value, error) {
+	if r == nil {
+		r = protoregistry.GlobalTypes
+	}
+	xt, err := r.FindExtensionByName(name)
+	if err != nil {
+		return protoreflect.Value{}, errors.Wrap(err, "option %v", name)
+	}
+	xd := xt.TypeDescriptor()
+
+	opts := desc.Options()
+	if opts == nil {
+		return protoreflect.Value{}, errors.New("%v has no options", desc.FullName())
+	}
+	m := opts.ProtoReflect()
+	if got, want := xd.ContainingMessage().FullName(), m.Descriptor().FullName(); got != want {
+		return protoreflect.Value{}, errors.New("option %v extends %v, not %v", name, got, want)
+	}
+	if m.Has(xd) {
+		return m.Get(xd), nil
+	}
+
+	// Decode the unknown fields with the extension's number into
+	// a new options message, which need not include the other fields.
+	var b []byte
+	for u := m.GetUnknown(); len(u) > 0; {
+		num, _, n := protowire.ConsumeField(u)
+		if n < 0 {
+			return protoreflect.Value{}, errors.New("option %v: %v", name, protowire.ParseError(n))
+		}
+		if num == xd.Number() {
+			b = append(b, u[:n]...)
+		}
+		u = u[n:]
+	}
+	m2 := m.New()
+	if len(b) > 0 {
+		if err := (UnmarshalOptions{Merge: true, Resolver: r}).Unmarshal(b, m2.Interface()); err != nil {
+			return protoreflect.Value{}, errors.Wrap(err, "option %v", name)
+		}
+	}
+	return m2.Get(xd), nil
+}
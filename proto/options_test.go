@@ -0,0 +1,75 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	descpb "google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestGetOptionByName(t *testing.T) {
+	// The option my.pkg.option_name is not linked into the program,
+	// so it is stored as an unknown field of the options of M.
+	setOpts := &descpb.MessageOptions{}
+	setOpts.ProtoReflect().SetUnknown(protowire.AppendString(protowire.AppendTag(nil, 50000, protowire.BytesType), "hello"))
+	fd, err := protodesc.NewFile(&descpb.FileDescriptorProto{
+		Name:       proto.String("my/pkg/opt.proto"),
+		Package:    proto.String("my.pkg"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		MessageType: []*descpb.DescriptorProto{
+			{Name: proto.String("Set"), Options: setOpts},
+			{Name: proto.String("Unset")},
+		},
+		Extension: []*descpb.FieldDescriptorProto{{
+			Name:         proto.String("option_name"),
+			Number:       proto.Int32(50000),
+			Label:        descpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:         descpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Extendee:     proto.String(".google.protobuf.MessageOptions"),
+			DefaultValue: proto.String("default"),
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := new(protoregistry.Types)
+	if err := types.RegisterExtension(dynamicpb.NewExtensionType(fd.Extensions().Get(0))); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		desc protoreflect.Descriptor
+		name protoreflect.FullName
+		want string
+	}{
+		{fd.Messages().ByName("Set"), "my.pkg.option_name", "hello"},
+		{fd.Messages().ByName("Unset"), "my.pkg.option_name", "default"},
+	} {
+		v, err := proto.GetOptionByName(tt.desc, tt.name, types)
+		if err != nil {
+			t.Errorf("GetOptionByName(%v, %v) error: %v", tt.desc.FullName(), tt.name, err)
+			continue
+		}
+		if got := v.String(); got != tt.want {
+			t.Errorf("GetOptionByName(%v, %v) = %q, want %q", tt.desc.FullName(), tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := proto.GetOptionByName(fd.Messages().ByName("Set"), "my.pkg.missing", types); !errors.Is(err, protoregistry.NotFound) {
+		t.Errorf("GetOptionByName(missing) error = %v, want %v", err, protoregistry.NotFound)
+	}
+	if _, err := proto.GetOptionByName(fd, "my.pkg.option_name", types); err == nil {
+		t.Errorf("GetOptionByName on a file descriptor succeeded, want error for mismatched options message")
+	}
+}
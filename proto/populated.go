@@ -0,0 +1,131 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"math/bits"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FieldSet is a set of the known fields of a message represented as a bitset.
+// Bit i corresponds to the field at index i in the message descriptor's
+// field list (i.e., md.Fields().Get(i)). Extension fields are never included.
+//
+// The zero value is an empty set.
+type FieldSet []uint64
+
+// Has reports whether the field at index i is in the set.
+func (s FieldSet) Has(i int) bool {
+	return i/64 < len(s) && s[i/64]&(1<<(i%64)) != 0
+}
+
+// Set adds the field at index i to the set.
+// It panics if i is out of range for the set.
+func (s FieldSet) Set(i int) {
+	s[i/64] |= 1 << (i % 64)
+}
+
+// Clear removes the field at index i from the set.
+// It is a no-op if i is out of range for the set.
+func (s FieldSet) Clear(i int) {
+	if i/64 < len(s) {
+		s[i/64] &^= 1 << (i % 64)
+	}
+}
+
+// Len reports the number of fields in the set.
+func (s FieldSet) Len() int {
+	var n int
+	for _, w := range s {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Next returns the smallest field index in the set that is greater than
+// or equal to i, or -1 if there is none. It can be used to iterate over
+// the set in field index order:
+//
+//	for i := s.Next(0); i >= 0; i = s.Next(i + 1) {
+//		fd := md.Fields().Get(i)
+//		...
+//	}
+func (s FieldSet) Next(i int) int {
+	if i < 0 {
+		i = 0
+	}
+	for w := i / 64; w < len(s); w++ {
+		word := s[w]
+		if w == i/64 {
+			word &^= (1 << (i % 64)) - 1
+		}
+		if word != 0 {
+			return w*64 + bits.TrailingZeros64(word)
+		}
+	}
+	return -1
+}
+
+// PopulatedFields returns the set of known fields populated in m.
+// A field is populated if [protoreflect.Message.Has] reports true for it.
+//
+// For generated messages, this is faster than calling
+// [protoreflect.Message.Range] since field values are never materialized
+// and no allocations are performed.
+func PopulatedFields(m Message) FieldSet {
+	return LoadPopulatedFields(nil, m)
+}
+
+// LoadPopulatedFields is like [PopulatedFields], but reuses the storage of s
+// if it is large enough to hold the result.
+func LoadPopulatedFields(s FieldSet, m Message) FieldSet {
+	if m == nil {
+		return s[:0]
+	}
+	mr := m.ProtoReflect()
+	if pf, ok := mr.(populatedFielder); ok {
+		return pf.ProtoPopulatedFields(s)
+	}
+	return populatedFieldsSlow(s, mr)
+}
+
+// ClearFieldSet clears every known field of m that is in s.
+// It is the counterpart of [PopulatedFields]: ClearFieldSet(m, PopulatedFields(m))
+// clears all known fields of m, leaving extension and unknown fields intact.
+// It panics if s contains an index out of range for the fields of m.
+func ClearFieldSet(m Message, s FieldSet) {
+	if s.Len() == 0 {
+		return
+	}
+	mr := m.ProtoReflect()
+	fds := mr.Descriptor().Fields()
+	for i := s.Next(0); i >= 0; i = s.Next(i + 1) {
+		mr.Clear(fds.Get(i))
+	}
+}
+
+// populatedFielder is implemented by messages of the internal/impl package,
+// which can report populated fields without materializing their values.
+type populatedFielder interface {
+	ProtoPopulatedFields([]uint64) []uint64
+}
+
+func populatedFieldsSlow(s FieldSet, m protoreflect.Message) FieldSet {
+	n := (m.Descriptor().Fields().Len() + 63) / 64
+	if cap(s) < n {
+		s = make(FieldSet, n)
+	} else {
+		s = s[:n]
+		clear(s)
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if !fd.IsExtension() {
+			s.Set(fd.Index())
+		}
+		return true
+	})
+	return s
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestPopulatedFields(t *testing.T) {
+	for _, test := range testValidMessages {
+		for _, m := range test.decodeTo {
+			if test.partial {
+				continue
+			}
+			t.Run(fmt.Sprintf("%s (%T)", test.desc, m), func(t *testing.T) {
+				for _, m := range []proto.Message{m, dynamicpb.NewMessage(m.ProtoReflect().Descriptor())} {
+					opts := test.unmarshalOptions
+					opts.AllowPartial = true
+					got := m.ProtoReflect().New().Interface()
+					if err := opts.Unmarshal(test.wire, got); err != nil {
+						t.Fatalf("Unmarshal error: %v", err)
+					}
+					want := make(map[int]bool)
+					got.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+						if !fd.IsExtension() {
+							want[fd.Index()] = true
+						}
+						return true
+					})
+					set := proto.PopulatedFields(got)
+					if set.Len() != len(want) {
+						t.Errorf("%T: PopulatedFields().Len() = %v, want %v", got, set.Len(), len(want))
+					}
+					for i := set.Next(0); i >= 0; i = set.Next(i + 1) {
+						if !want[i] {
+							t.Errorf("%T: PopulatedFields() unexpectedly has field %v", got, got.ProtoReflect().Descriptor().Fields().Get(i).FullName())
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestFieldSet(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32: proto.Int32(1),
+		OneofField:    &testpb.TestAllTypes_OneofString{OneofString: "x"},
+	}
+	fds := m.ProtoReflect().Descriptor().Fields()
+	i32 := fds.ByName("optional_int32").Index()
+	oneof := fds.ByName("oneof_string").Index()
+	unset := fds.ByName("optional_int64").Index()
+
+	s := proto.PopulatedFields(m)
+	if !s.Has(i32) || !s.Has(oneof) || s.Has(unset) {
+		t.Errorf("PopulatedFields() = %v, want fields %d and %d", s, i32, oneof)
+	}
+	s.Clear(i32)
+	if s.Has(i32) || s.Len() != 1 {
+		t.Errorf("after Clear(%d): Has = %v, Len = %d; want false, 1", i32, s.Has(i32), s.Len())
+	}
+	if got := s.Next(0); got != oneof {
+		t.Errorf("Next(0) = %d, want %d", got, oneof)
+	}
+	if got := s.Next(oneof + 1); got != -1 {
+		t.Errorf("Next(%d) = %d, want -1", oneof+1, got)
+	}
+
+	reused := proto.LoadPopulatedFields(s, &testpb.TestAllTypes{})
+	if reused.Len() != 0 || &reused[0] != &s[0] {
+		t.Errorf("LoadPopulatedFields did not reuse and clear the provided set")
+	}
+	if got := proto.PopulatedFields(nil); got.Len() != 0 {
+		t.Errorf("PopulatedFields(nil).Len() = %d, want 0", got.Len())
+	}
+	if got := proto.PopulatedFields((*testpb.TestAllTypes)(nil)); got.Len() != 0 {
+		t.Errorf("PopulatedFields(typed nil).Len() = %d, want 0", got.Len())
+	}
+}
+
+func TestClearFieldSet(t *testing.T) {
+	for _, m := range []proto.Message{
+		&testpb.TestAllTypes{},
+		dynamicpb.NewMessage((&testpb.TestAllTypes{}).ProtoReflect().Descriptor()),
+	} {
+		mr := m.ProtoReflect()
+		fds := mr.Descriptor().Fields()
+		i32 := fds.ByName("optional_int32")
+		str := fds.ByName("repeated_string")
+		oneof := fds.ByName("oneof_string")
+		mr.Set(i32, protoreflect.ValueOfInt32(1))
+		mr.Mutable(str).List().Append(protoreflect.ValueOfString("a"))
+		mr.Set(oneof, protoreflect.ValueOfString("x"))
+		mr.SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 10000, protowire.VarintType), 1))
+
+		s := make(proto.FieldSet, (fds.Len()+63)/64)
+		s.Set(i32.Index())
+		s.Set(oneof.Index())
+		proto.ClearFieldSet(m, s)
+		if got := proto.PopulatedFields(m); got.Len() != 1 || !got.Has(str.Index()) {
+			t.Errorf("%T: after ClearFieldSet, PopulatedFields() = %v, want only %v", m, got, str.Name())
+		}
+
+		proto.ClearFieldSet(m, proto.PopulatedFields(m))
+		if got := proto.PopulatedFields(m); got.Len() != 0 {
+			t.Errorf("%T: after clearing populated fields, PopulatedFields() = %v, want empty", m, got)
+		}
+		if len(mr.GetUnknown()) == 0 {
+			t.Errorf("%T: ClearFieldSet cleared unknown fields", m)
+		}
+		proto.ClearFieldSet(m, nil)
+	}
+}
+
+func BenchmarkPopulatedFields(b *testing.B) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32:  proto.Int32(1),
+		RepeatedString: []string{"a"},
+	}
+	b.Run("PopulatedFields", func(b *testing.B) {
+		var s proto.FieldSet
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			s = proto.LoadPopulatedFields(s, m)
+		}
+	})
+	b.Run("Range", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m.ProtoReflect().Range(func(protoreflect.FieldDescriptor, protoreflect.Value) bool { return true })
+		}
+	})
+}
@@ -5,6 +5,7 @@
 package proto
 
 import (
+	"google.golang.org/protobuf/internal/detrand"
 	"google.golang.org/protobuf/internal/errors"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
@@ -35,6 +36,24 @@ func init() {
 	Error = errors.Error
 }
 
+// DisableOutputRandomization makes the output of this module stable across
+// builds of a program, for use in controlled environments such as golden
+// tests and reproducible builds.
+//
+// By default, the output of the prototext and protojson marshalers,
+// the formatting of messages and descriptors, and the text of errors
+// deliberately vary between builds of a program, to discourage depending
+// on the exact output, which may change across versions of this module.
+// Disabling this randomization does not make the output stable across
+// versions of this module.
+//
+// It is safe for concurrent use, but should be called early in the program,
+// such as in an init function or TestMain, since some of the variation is
+// fixed when a message type is first used.
+func DisableOutputRandomization() {
+	detrand.Disable()
+}
+
 // MessageName returns the full name of m.
 // If m is nil, it returns an empty string.
 func MessageName(m Message) protoreflect.FullName {
@@ -0,0 +1,46 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestDisableOutputRandomization(t *testing.T) {
+	proto.DisableOutputRandomization()
+
+	m := &testpb.TestAllTypes{
+		OptionalInt32:  proto.Int32(1),
+		OptionalString: proto.String("a"),
+	}
+	if got, want := protojson.Format(m), "{\n  \"optionalInt32\": 1,\n  \"optionalString\": \"a\"\n}"; got != want {
+		t.Errorf("protojson.Format() = %q, want %q", got, want)
+	}
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"optionalInt32":1,"optionalString":"a"}`; got != want {
+		t.Errorf("protojson.Marshal() = %q, want %q", got, want)
+	}
+	b, err = prototext.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `optional_int32:1 optional_string:"a"`; got != want {
+		t.Errorf("prototext.Marshal() = %q, want %q", got, want)
+	}
+
+	_, err = proto.Marshal(&testpb.TestRequired{})
+	if got, want := err.Error(), "proto: required field (goproto.proto.test.TestRequired).required_field not set"; got != want {
+		t.Errorf("error = %q, want %q", got, want)
+	}
+}
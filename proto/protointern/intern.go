@@ -0,0 +1,183 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protointern deduplicates identical messages so that each distinct
+// value is stored in memory only once.
+//
+// Interning is useful for large, long-lived structures that contain many
+// equal submessages, such as metadata blocks repeated across records.
+// Since interned messages are shared, they must be treated as immutable:
+// neither the messages passed to an [Interner] nor the messages it returns
+// may be modified afterwards.
+package protointern
+
+import (
+	"hash/maphash"
+	"reflect"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DefaultLimit is the default maximum number of canonical messages
+// held by an [Interner].
+const DefaultLimit = 1 << 16
+
+// Interner is a set of canonical messages.
+// The zero value is an empty set ready for use.
+// It is safe for concurrent use by multiple goroutines.
+type Interner struct {
+	// Limit is the maximum number of canonical messages in the set.
+	// Once the set is full, messages that are not equal to a canonical
+	// message are returned unchanged instead of being added to the set.
+	// Canonical messages are never evicted since they may already be
+	// shared; use [Interner.Reset] to release them.
+	//
+	// If zero, [DefaultLimit] is used. If negative, the set is unbounded.
+	// The limit must not be changed once the Interner is in use.
+	Limit int
+
+	mu    sync.Mutex
+	seed  maphash.Seed
+	byKey map[key][]proto.Message // canonical messages by fingerprint
+	canon map[proto.Message]bool  // set of canonical messages
+}
+
+// key is the fingerprint of a message. Messages are only considered equal
+// if they have the same Go type and the same message descriptor,
+// so that interning never changes the concrete type of a message.
+type key struct {
+	goType reflect.Type
+	name   protoreflect.FullName
+	hash   uint64
+}
+
+// Intern returns the canonical message equal to m.
+// If no equal message has been interned before, m itself becomes the
+// canonical message and is returned, unless the set is already full.
+// Messages are compared using [proto.Equal] and are not interned if
+// they cannot be marshaled.
+//
+// Only m itself is interned; use [Interner.InternFields] to also
+// deduplicate its submessages.
+func (in *Interner) Intern(m proto.Message) proto.Message {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return m
+	}
+	b, err := proto.MarshalOptions{Deterministic: true, AllowPartial: true}.Marshal(m)
+	if err != nil {
+		return m
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if in.byKey == nil {
+		in.seed = maphash.MakeSeed()
+		in.byKey = make(map[key][]proto.Message)
+		in.canon = make(map[proto.Message]bool)
+	}
+	if in.canon[m] {
+		return m
+	}
+	k := key{
+		goType: reflect.TypeOf(m),
+		name:   m.ProtoReflect().Descriptor().FullName(),
+		hash:   maphash.Bytes(in.seed, b),
+	}
+	for _, c := range in.byKey[k] {
+		if proto.Equal(c, m) {
+			return c
+		}
+	}
+	if limit := in.limit(); limit >= 0 && len(in.canon) >= limit {
+		return m
+	}
+	in.byKey[k] = append(in.byKey[k], m)
+	in.canon[m] = true
+	return m
+}
+
+func (in *Interner) limit() int {
+	if in.Limit == 0 {
+		return DefaultLimit
+	}
+	return in.Limit
+}
+
+// InternFields replaces every submessage within m, including list elements
+// and map values, with its canonical message. Submessages are interned
+// bottom-up, so equal subtrees at any depth end up sharing memory.
+// The message m itself is modified in place but is not interned.
+func (in *Interner) InternFields(m proto.Message) {
+	in.internFields(m.ProtoReflect())
+}
+
+func (in *Interner) internFields(m protoreflect.Message) {
+	// Collect the fields first since m must not be mutated during Range.
+	var fds []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if fd.Message() != nil {
+			fds = append(fds, fd)
+		}
+		return true
+	})
+	for _, fd := range fds {
+		switch v := m.Get(fd); {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				list.Set(i, in.internValue(list.Get(i)))
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() == nil {
+				continue
+			}
+			mp := v.Map()
+			var keys []protoreflect.MapKey
+			mp.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, k)
+				return true
+			})
+			for _, k := range keys {
+				mp.Set(k, in.internValue(mp.Get(k)))
+			}
+		default:
+			m.Set(fd, in.internValue(v))
+		}
+	}
+}
+
+// internValue interns the fields of the message value v,
+// then returns the canonical message for v.
+func (in *Interner) internValue(v protoreflect.Value) protoreflect.Value {
+	if in.isCanonical(v.Message().Interface()) {
+		return v // already interned, so it must not be modified
+	}
+	in.internFields(v.Message())
+	c := in.Intern(v.Message().Interface())
+	return protoreflect.ValueOfMessage(c.ProtoReflect())
+}
+
+// Len reports the number of canonical messages in the set.
+func (in *Interner) Len() int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return len(in.canon)
+}
+
+func (in *Interner) isCanonical(m proto.Message) bool {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.canon[m]
+}
+
+// Reset removes all canonical messages from the set, allowing them to be
+// garbage collected once they are no longer referenced elsewhere.
+func (in *Interner) Reset() {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.byKey = nil
+	in.canon = nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protointern_test
+
+import (
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/proto/protointern"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestIntern(t *testing.T) {
+	var in protointern.Interner
+	m1 := &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)}
+	m2 := &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)}
+	m3 := &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)}
+
+	if got := in.Intern(m1); got != m1 {
+		t.Errorf("Intern(m1) = %p, want m1 %p", got, m1)
+	}
+	if got := in.Intern(m2); got != m1 {
+		t.Errorf("Intern(m2) = %p, want m1 %p", got, m1)
+	}
+	if got := in.Intern(m3); got != m3 {
+		t.Errorf("Intern(m3) = %p, want m3 %p", got, m3)
+	}
+	if got := in.Len(); got != 2 {
+		t.Errorf("Len() = %v, want 2", got)
+	}
+
+	// Equal messages of a different Go type are not merged.
+	dm := dynamicpb.NewMessage(m1.ProtoReflect().Descriptor())
+	proto.Merge(dm, m1)
+	if got := in.Intern(dm); got != dm {
+		t.Errorf("Intern(dynamic) = %v, want the dynamic message", got)
+	}
+
+	in.Reset()
+	if got := in.Intern(m2); got != m2 {
+		t.Errorf("after Reset, Intern(m2) = %p, want m2 %p", got, m2)
+	}
+}
+
+func TestInternLimit(t *testing.T) {
+	in := protointern.Interner{Limit: 1}
+	m1 := &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)}
+	m2 := &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)}
+	m3 := &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)}
+
+	in.Intern(m1)
+	if got := in.Intern(m2); got != m2 {
+		t.Errorf("Intern(m2) = %p, want m2 %p", got, m2)
+	}
+	if got := in.Intern(m3); got != m3 {
+		t.Errorf("Intern(m3) = %p, want m3 %p since the set is full", got, m3)
+	}
+	if got := in.Len(); got != 1 {
+		t.Errorf("Len() = %v, want 1", got)
+	}
+	// Canonical messages are still returned once the set is full.
+	if got := in.Intern(&testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)}); got != m1 {
+		t.Errorf("Intern(equal to m1) = %p, want m1 %p", got, m1)
+	}
+}
+
+func TestInternFields(t *testing.T) {
+	newNested := func() *testpb.TestAllTypes_NestedMessage {
+		return &testpb.TestAllTypes_NestedMessage{
+			A:           proto.Int32(1),
+			Corecursive: &testpb.TestAllTypes{OptionalString: proto.String("shared")},
+		}
+	}
+	m := &testpb.TestAllTypes{
+		OptionalNestedMessage: newNested(),
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{newNested(), newNested()},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+			"a": newNested(),
+			"b": newNested(),
+		},
+	}
+	want := proto.Clone(m)
+
+	var in protointern.Interner
+	in.InternFields(m)
+	if !proto.Equal(m, want) {
+		t.Errorf("InternFields changed the message:\ngot  %v\nwant %v", m, want)
+	}
+	canon := m.OptionalNestedMessage
+	for i, n := range m.RepeatedNestedMessage {
+		if n != canon {
+			t.Errorf("RepeatedNestedMessage[%d] is not the canonical message", i)
+		}
+	}
+	for k, n := range m.MapStringNestedMessage {
+		if n != canon {
+			t.Errorf("MapStringNestedMessage[%q] is not the canonical message", k)
+		}
+	}
+	if got := in.Len(); got != 2 {
+		t.Errorf("Len() = %v, want 2", got)
+	}
+
+	// Interning a second structure reuses the canonical messages
+	// without modifying them.
+	m2 := &testpb.TestAllTypes{OptionalNestedMessage: newNested()}
+	in.InternFields(m2)
+	if m2.OptionalNestedMessage != canon {
+		t.Errorf("OptionalNestedMessage of second message is not the canonical message")
+	}
+}
+
+func TestInternConcurrent(t *testing.T) {
+	var in protointern.Interner
+	var wg sync.WaitGroup
+	results := make([]proto.Message, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = in.Intern(&testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)})
+		}(i)
+	}
+	wg.Wait()
+	for i, r := range results {
+		if r != results[0] {
+			t.Errorf("result %d is not the canonical message", i)
+		}
+	}
+}
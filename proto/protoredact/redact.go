@@ -0,0 +1,228 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoredact removes the values of sensitive fields from messages.
+//
+// A field is sensitive if it is marked with the debug_redact field option,
+// or with a custom field option that an organization uses to classify data:
+//
+//	extend google.protobuf.FieldOptions {
+//	  bool sensitive = 50000;
+//	}
+//
+//	message User {
+//	  string name = 1;
+//	  string password = 2 [(sensitive) = true];
+//	}
+//
+// Given the generated extension type for the custom option,
+// a redacted copy of a message is produced with:
+//
+//	opts := protoredact.Options{Extension: mypb.E_Sensitive}
+//	safe := opts.Clone(user)
+//
+// Sensitive fields are found at any depth, including within the contents of
+// google.protobuf.Any messages whose types can be resolved.
+package protoredact
+
+import (
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protopath"
+	"google.golang.org/protobuf/reflect/protorange"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Options configures which fields are redacted and how.
+type Options struct {
+	// Extension is a field option that marks sensitive fields.
+	// A field is sensitive if the option is set to a true boolean,
+	// a non-zero enum number, or any other non-zero value.
+	// The option is also found among the unknown fields of the field's
+	// options, which is where it is stored if the descriptor was built
+	// without the extension type being available.
+	Extension protoreflect.ExtensionType
+
+	// IgnoreDebugRedact does not treat fields marked with the debug_redact
+	// field option as sensitive. By default, they are redacted.
+	IgnoreDebugRedact bool
+
+	// Fields is the set of additional fields to redact,
+	// identified by their full names (e.g., "example.User.password").
+	// Extension fields may also be specified.
+	Fields []protoreflect.FullName
+
+	// Placeholder is the value that replaces sensitive string fields,
+	// including each element of repeated string fields.
+	// If empty, sensitive string fields are cleared like all other fields.
+	Placeholder string
+
+	// KeepUnknown preserves unknown fields. By default, they are discarded
+	// since it is unknown whether they hold sensitive values.
+	KeepUnknown bool
+
+	// Resolver is used for looking up types when expanding google.protobuf.Any
+	// messages. If nil, this defaults to using protoregistry.GlobalTypes.
+	// The contents of Any messages whose types are not found are left as is.
+	Resolver interface {
+		protoregistry.ExtensionTypeResolver
+		protoregistry.MessageTypeResolver
+	}
+}
+
+// Redact redacts m in place using the default options,
+// clearing all fields marked with the debug_redact field option.
+func Redact(m proto.Message) {
+	Options{}.Redact(m)
+}
+
+// Clone returns a redacted deep copy of m using the default options.
+func Clone(m proto.Message) proto.Message {
+	return Options{}.Clone(m)
+}
+
+// Redact redacts m in place according to o.
+func (o Options) Redact(m proto.Message) {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return
+	}
+	r := newRedactor(o)
+	protorange.Options{Resolver: o.Resolver}.Range(m.ProtoReflect(), r.push, nil)
+}
+
+// Clone returns a deep copy of m redacted according to o.
+// The original message is not modified.
+func (o Options) Clone(m proto.Message) proto.Message {
+	m = proto.Clone(m)
+	o.Redact(m)
+	return m
+}
+
+// IsSensitive reports whether the values of fd are redacted according to o.
+func (o Options) IsSensitive(fd protoreflect.FieldDescriptor) bool {
+	return newRedactor(o).isSensitive(fd)
+}
+
+// Text returns the text format of a redacted copy of m.
+// It is a shorthand for mo.Marshal(o.Clone(m)).
+func (o Options) Text(mo prototext.MarshalOptions, m proto.Message) ([]byte, error) {
+	return mo.Marshal(o.Clone(m))
+}
+
+// JSON returns the JSON format of a redacted copy of m.
+// It is a shorthand for mo.Marshal(o.Clone(m)).
+func (o Options) JSON(mo protojson.MarshalOptions, m proto.Message) ([]byte, error) {
+	return mo.Marshal(o.Clone(m))
+}
+
+type redactor struct {
+	opts      Options
+	fields    map[protoreflect.FullName]bool
+	resolver  *protoregistry.Types
+	sensitive map[protoreflect.FieldDescriptor]bool // cached results of isSensitive
+}
+
+func newRedactor(o Options) *redactor {
+	r := &redactor{
+		opts:      o,
+		fields:    make(map[protoreflect.FullName]bool, len(o.Fields)),
+		sensitive: make(map[protoreflect.FieldDescriptor]bool),
+	}
+	for _, name := range o.Fields {
+		r.fields[name] = true
+	}
+	if o.Extension != nil {
+		r.resolver = new(protoregistry.Types)
+		r.resolver.RegisterExtension(o.Extension)
+	}
+	return r
+}
+
+func (r *redactor) push(p protopath.Values) error {
+	last := p.Index(-1)
+	switch last.Step.Kind() {
+	case protopath.UnknownAccessStep:
+		if !r.opts.KeepUnknown {
+			protorange.Clear(p)
+		}
+	case protopath.FieldAccessStep:
+		fd := last.Step.FieldDescriptor()
+		if !r.isSensitive(fd) {
+			return nil
+		}
+		switch {
+		case r.opts.Placeholder == "" || fd.Kind() != protoreflect.StringKind || fd.IsMap():
+			protorange.Clear(p)
+		case fd.IsList():
+			parent := p.Index(-2).Value.Message()
+			list := parent.NewField(fd).List()
+			for i := 0; i < last.Value.List().Len(); i++ {
+				list.Append(protoreflect.ValueOfString(r.opts.Placeholder))
+			}
+			protorange.Set(p, protoreflect.ValueOfList(list))
+		default:
+			protorange.Set(p, protoreflect.ValueOfString(r.opts.Placeholder))
+		}
+	}
+	return nil
+}
+
+func (r *redactor) isSensitive(fd protoreflect.FieldDescriptor) bool {
+	if r.fields[fd.FullName()] {
+		return true
+	}
+	sensitive, ok := r.sensitive[fd]
+	if !ok {
+		sensitive = r.hasSensitiveOption(fd)
+		r.sensitive[fd] = sensitive
+	}
+	return sensitive
+}
+
+func (r *redactor) hasSensitiveOption(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return false
+	}
+	if !r.opts.IgnoreDebugRedact && opts.GetDebugRedact() {
+		return true
+	}
+	xt := r.opts.Extension
+	if xt == nil {
+		return false
+	}
+	if !proto.HasExtension(opts, xt) {
+		// The option may not have been resolved when the descriptor was
+		// built, in which case it is held in the unknown fields.
+		b := opts.ProtoReflect().GetUnknown()
+		if len(b) == 0 {
+			return false
+		}
+		opts = new(descriptorpb.FieldOptions)
+		err := proto.UnmarshalOptions{
+			AllowPartial: true,
+			Resolver:     r.resolver,
+		}.Unmarshal(b, opts)
+		if err != nil || !proto.HasExtension(opts, xt) {
+			return false
+		}
+	}
+	xd := xt.TypeDescriptor()
+	v := opts.ProtoReflect().Get(xd)
+	switch {
+	case xd.IsList():
+		return v.List().Len() > 0
+	case xd.Kind() == protoreflect.BoolKind:
+		return v.Bool()
+	case xd.Kind() == protoreflect.EnumKind:
+		return v.Enum() != 0
+	case xd.Message() != nil:
+		return true
+	default:
+		return !v.Equal(xt.Zero())
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoredact_test
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/proto/protoredact"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// newTestFile returns a file with a (sensitive) custom field option
+// and a User message that uses it. The option on the password field is
+// left unresolved in the unknown fields of its options.
+func newTestFile(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+	fdp := new(descriptorpb.FileDescriptorProto)
+	if err := prototext.Unmarshal([]byte(`
+		name: "redact_test.proto"
+		package: "protoredact.test"
+		dependency: ["google/protobuf/descriptor.proto", "google/protobuf/any.proto"]
+		extension: [{
+			name: "sensitive" number: 50000 label: LABEL_OPTIONAL type: TYPE_BOOL
+			extendee: ".google.protobuf.FieldOptions" json_name: "sensitive"
+		}]
+		message_type: [{
+			name: "User"
+			field: [
+				{name: "name" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "name"},
+				{name: "password" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "password"},
+				{name: "tokens" number: 3 label: LABEL_REPEATED type: TYPE_STRING json_name: "tokens"},
+				{name: "ssn" number: 4 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "ssn" options: {debug_redact: true}},
+				{name: "pin" number: 5 label: LABEL_OPTIONAL type: TYPE_INT32 json_name: "pin"},
+				{name: "friend" number: 6 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".protoredact.test.User" json_name: "friend"},
+				{name: "extra" number: 7 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Any" json_name: "extra"}
+			]
+		}]
+	`), fdp); err != nil {
+		t.Fatal(err)
+	}
+	sensitive := protowire.AppendTag(nil, 50000, protowire.VarintType)
+	sensitive = protowire.AppendVarint(sensitive, 1)
+	for _, i := range []int{1, 2, 4} {
+		opts := new(descriptorpb.FieldOptions)
+		opts.ProtoReflect().SetUnknown(sensitive)
+		fdp.MessageType[0].Field[i].Options = opts
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd
+}
+
+func TestRedact(t *testing.T) {
+	fd := newTestFile(t)
+	md := fd.Messages().ByName("User")
+	xt := dynamicpb.NewExtensionType(fd.Extensions().ByName("sensitive"))
+	types := new(protoregistry.Types)
+	if err := types.RegisterMessage(dynamicpb.NewMessageType(md)); err != nil {
+		t.Fatal(err)
+	}
+
+	newUser := func(s string) proto.Message {
+		m := dynamicpb.NewMessage(md)
+		if err := (prototext.UnmarshalOptions{Resolver: types}).Unmarshal([]byte(s), m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	friend := newUser(`name: "bob" password: "b0b" ssn: "987"`)
+	extra, err := anypb.New(friend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newUser(`
+		name: "alice" password: "hunter2" tokens: ["t1", "t2"] ssn: "123" pin: 1234
+		friend: {name: "bob" password: "b0b" ssn: "987"}
+	`)
+	m.ProtoReflect().Set(md.Fields().ByName("extra"), protoreflect.ValueOfMessage(extra.ProtoReflect()))
+	m.ProtoReflect().SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 100, protowire.VarintType), 1))
+
+	tests := []struct {
+		desc string
+		opts protoredact.Options
+		want proto.Message
+	}{{
+		desc: "debug_redact only",
+		opts: protoredact.Options{Resolver: types},
+		want: newUser(`
+			name: "alice" password: "hunter2" tokens: ["t1", "t2"] pin: 1234
+			friend: {name: "bob" password: "b0b"}
+			extra: {[type.googleapis.com/protoredact.test.User]: {name: "bob" password: "b0b"}}
+		`),
+	}, {
+		desc: "custom option",
+		opts: protoredact.Options{Extension: xt, Resolver: types},
+		want: newUser(`
+			name: "alice"
+			friend: {name: "bob"}
+			extra: {[type.googleapis.com/protoredact.test.User]: {name: "bob"}}
+		`),
+	}, {
+		desc: "custom option with placeholder",
+		opts: protoredact.Options{Extension: xt, Placeholder: "x", IgnoreDebugRedact: true, Resolver: types},
+		want: newUser(`
+			name: "alice" password: "x" tokens: ["x", "x"] ssn: "123"
+			friend: {name: "bob" password: "x" ssn: "987"}
+			extra: {[type.googleapis.com/protoredact.test.User]: {name: "bob" password: "x" ssn: "987"}}
+		`),
+	}, {
+		desc: "additional fields",
+		opts: protoredact.Options{Fields: []protoreflect.FullName{"protoredact.test.User.name"}, Resolver: types},
+		want: newUser(`
+			password: "hunter2" tokens: ["t1", "t2"] pin: 1234
+			friend: {password: "b0b"}
+			extra: {[type.googleapis.com/protoredact.test.User]: {password: "b0b"}}
+		`),
+	}, {
+		desc: "unresolvable Any",
+		opts: protoredact.Options{Resolver: new(protoregistry.Types)},
+		want: func() proto.Message {
+			m := newUser(`
+				name: "alice" password: "hunter2" tokens: ["t1", "t2"] pin: 1234
+				friend: {name: "bob" password: "b0b"}
+			`)
+			m.ProtoReflect().Set(md.Fields().ByName("extra"), protoreflect.ValueOfMessage(extra.ProtoReflect()))
+			return m
+		}(),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			before := proto.Clone(m)
+			got := tt.opts.Clone(m)
+			if !proto.Equal(m, before) {
+				t.Errorf("Clone modified the original message")
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("Clone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	opts := protoredact.Options{Extension: xt, Resolver: types}
+	b, err := opts.JSON(protojson.MarshalOptions{Resolver: types}, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); strings.Contains(s, "hunter2") || strings.Contains(s, "b0b") || !strings.Contains(s, "alice") {
+		t.Errorf("JSON() = %s, want redacted output", s)
+	}
+	b, err = opts.Text(prototext.MarshalOptions{Resolver: types}, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); strings.Contains(s, "hunter2") || strings.Contains(s, "b0b") || !strings.Contains(s, "alice") {
+		t.Errorf("Text() = %s, want redacted output", s)
+	}
+
+	for _, tt := range []struct {
+		name protoreflect.Name
+		opts protoredact.Options
+		want bool
+	}{
+		{"name", opts, false},
+		{"password", opts, true},
+		{"ssn", opts, true},
+		{"ssn", protoredact.Options{IgnoreDebugRedact: true}, false},
+		{"password", protoredact.Options{}, false},
+	} {
+		if got := tt.opts.IsSensitive(md.Fields().ByName(tt.name)); got != tt.want {
+			t.Errorf("IsSensitive(%v) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRedactInPlace(t *testing.T) {
+	fd := newTestFile(t)
+	m := dynamicpb.NewMessage(fd.Messages().ByName("User"))
+	if err := prototext.Unmarshal([]byte(`name: "alice" ssn: "123"`), m); err != nil {
+		t.Fatal(err)
+	}
+	protoredact.Redact(m)
+	want := dynamicpb.NewMessage(fd.Messages().ByName("User"))
+	if err := prototext.Unmarshal([]byte(`name: "alice"`), want); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(m, want) {
+		t.Errorf("Redact() = %v, want %v", m, want)
+	}
+	protoredact.Redact(nil) // must not panic
+}
@@ -0,0 +1,292 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/encoding/messageset"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/internal/pragma"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// SanitizeOptions configures the sanitizer.
+//
+// Example usage:
+//
+//	b, err := proto.SanitizeOptions{
+//		Limits: proto.Limits{MaxMessageSize: 1 << 20},
+//	}.Sanitize(b, md)
+type SanitizeOptions struct {
+	pragma.NoUnkeyedLiterals
+
+	// KeepUnknown specifies whether to keep unknown fields in the output.
+	// By default, unknown fields are dropped.
+	// Unknown fields are only checked to be well-formed.
+	KeepUnknown bool
+
+	// AllowPartial accepts input with missing required fields.
+	// Required fields dropped for being oversized are also considered missing.
+	AllowPartial bool
+
+	// Limits are the resource limits on the input.
+	// Input that exceeds them is rejected with a [*LimitError],
+	// as it would be by [UnmarshalOptions.Unmarshal].
+	Limits Limits
+
+	// MaxFieldSize is the maximum size in bytes of the sanitized encoding
+	// of a single occurrence of a field, including its tag. The size of
+	// message fields is that of their sanitized contents. Larger fields are
+	// rejected with a [*LimitError], unless DropOversized is set.
+	// If zero, the size of fields is not limited.
+	MaxFieldSize int
+
+	// DropOversized specifies that fields larger than MaxFieldSize
+	// are dropped from the output instead of causing an error.
+	DropOversized bool
+
+	// RecursionLimit limits how deeply messages may be nested.
+	// If zero, a default limit is applied.
+	RecursionLimit int
+
+	// Resolver is used for looking up types of extension fields.
+	// Extension fields that cannot be resolved are treated as unknown fields.
+	// If nil, this defaults to using protoregistry.GlobalTypes.
+	Resolver interface {
+		FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error)
+		FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error)
+	}
+}
+
+// Sanitize validates untrusted wire-format data for a message of type md
+// and returns an equivalent normalized encoding.
+// It is equivalent to SanitizeOptions{}.Sanitize(b, md).
+func Sanitize(b []byte, md protoreflect.MessageDescriptor) ([]byte, error) {
+	return SanitizeOptions{}.Sanitize(b, md)
+}
+
+// Sanitize validates untrusted wire-format data for a message of type md
+// and returns an equivalent normalized encoding, such as for a gateway
+// which forwards messages it does not otherwise process.
+//
+// The input is checked as [Unmarshal] would check it, including the
+// validity of UTF-8 strings and the presence of required fields, without
+// requiring a Go type for the message. The output preserves the order of
+// fields and re-encodes every tag and value in its canonical form, so that
+// non-minimal varints and other redundant encodings are not forwarded.
+// Unknown fields are dropped unless KeepUnknown is set.
+// Missing required fields, including those of sub-messages, are reported
+// in a [*RequiredNotSetError] unless AllowPartial is set.
+//
+// Message sets are not supported.
+func (o SanitizeOptions) Sanitize(b []byte, md protoreflect.MessageDescriptor) ([]byte, error) {
+	if o.RecursionLimit == 0 {
+		o.RecursionLimit = protowire.DefaultRecursionLimit
+	}
+	if o.Resolver == nil {
+		o.Resolver = protoregistry.GlobalTypes
+	}
+	if o.Limits.any() {
+		uo := UnmarshalOptions{
+			Resolver:       o.Resolver,
+			RecursionLimit: o.RecursionLimit,
+			Limits:         o.Limits,
+		}
+		if err := uo.checkLimits(b, md); err != nil {
+			return nil, err
+		}
+	}
+	var missing []MissingField
+	out, err := o.sanitizeMessage(nil, b, md, o.RecursionLimit, nil, &missing)
+	if err == nil && len(missing) > 0 {
+		err = &RequiredNotSetError{Fields: missing}
+	}
+	return out, err
+}
+
+// sanitizeMessage appends the sanitized encoding of the message b,
+// which is located at path, and appends the required fields that are not
+// set in it and in its sub-messages to missing.
+func (o SanitizeOptions) sanitizeMessage(out, b []byte, md protoreflect.MessageDescriptor, depth int, path []PathStep, missing *[]MissingField) ([]byte, error) {
+	depth--
+	if depth < 0 {
+		return out, errors.New("exceeded max recursion depth")
+	}
+	if messageset.IsMessageSet(md) {
+		return out, errors.New("%v: cannot sanitize message set", md.FullName())
+	}
+	fields := md.Fields()
+	var seen []protoreflect.FieldNumber // required fields that are present
+	var sub []MissingField              // missing fields of sub-messages
+	var indexes map[protoreflect.FieldNumber]int
+	var mapKey protoreflect.MapKey
+	if md.IsMapEntry() {
+		mapKey = sanitizeMapKey(b, md)
+	}
+	for len(b) > 0 {
+		num, wtyp, tagLen := protowire.ConsumeTag(b)
+		if tagLen < 0 || num > protowire.MaxValidNumber {
+			return out, errDecode
+		}
+
+		fd := fields.ByNumber(num)
+		if fd == nil && md.ExtensionRanges().Has(num) {
+			xt, err := o.Resolver.FindExtensionByNumber(md.FullName(), num)
+			if err != nil && err != protoregistry.NotFound {
+				return out, errors.New("%v: unable to resolve extension %v: %v", md.FullName(), num, err)
+			}
+			if xt != nil {
+				fd = xt.TypeDescriptor()
+			}
+		}
+
+		start, subStart := len(out), len(sub)
+		var valLen int
+		var err error
+		switch {
+		case fd == nil:
+			err = errUnknown
+		case fd.IsList() && wtyp == protowire.BytesType && wireTypes[fd.Kind()] != protowire.BytesType:
+			out, valLen, err = o.sanitizePacked(out, b[tagLen:], fd)
+		default:
+			var v protoreflect.Value
+			v, valLen, err = UnmarshalOptions{}.unmarshalScalar(b[tagLen:], wtyp, fd)
+			if err == nil {
+				var p []PathStep
+				if fd.Message() != nil {
+					switch {
+					case md.IsMapEntry():
+						p = appendStep(path, PathStep{Key: mapKey})
+					case fd.IsList():
+						if indexes == nil {
+							indexes = make(map[protoreflect.FieldNumber]int)
+						}
+						p = appendStep(appendStep(path, PathStep{Field: fd}), PathStep{Key: protoreflect.ValueOfInt64(int64(indexes[num])).MapKey()})
+						indexes[num]++
+					default:
+						p = appendStep(path, PathStep{Field: fd})
+					}
+				}
+				out, err = o.sanitizeValue(out, fd, v, depth, p, &sub)
+			}
+		}
+		if err == errUnknown {
+			valLen = protowire.ConsumeFieldValue(num, wtyp, b[tagLen:])
+			if valLen < 0 {
+				return out, errDecode
+			}
+			if o.KeepUnknown {
+				out = append(out, b[:tagLen+valLen]...)
+			}
+		} else if err != nil {
+			return out, err
+		}
+
+		switch {
+		case o.MaxFieldSize > 0 && len(out)-start > o.MaxFieldSize:
+			if !o.DropOversized {
+				name := md.FullName()
+				if fd != nil {
+					name = fd.FullName()
+				}
+				return out, &LimitError{Limit: "MaxFieldSize", Field: name, Size: len(out) - start}
+			}
+			out, sub = out[:start], sub[:subStart]
+		case fd != nil && fd.Cardinality() == protoreflect.Required && err == nil:
+			seen = append(seen, num)
+		}
+		b = b[tagLen+valLen:]
+	}
+
+	if !o.AllowPartial {
+		reqs := md.RequiredNumbers()
+	required:
+		for i := 0; i < reqs.Len(); i++ {
+			for _, num := range seen {
+				if num == reqs.Get(i) {
+					continue required
+				}
+			}
+			fd := fields.ByNumber(reqs.Get(i))
+			*missing = append(*missing, MissingField{Path: appendStep(path, PathStep{Field: fd}), Field: fd})
+		}
+	}
+	*missing = append(*missing, sub...)
+	return out, nil
+}
+
+// sanitizeMapKey returns the key of the map entry b,
+// which is the default key if the entry has no valid key.
+func sanitizeMapKey(b []byte, md protoreflect.MessageDescriptor) protoreflect.MapKey {
+	fd := md.Fields().ByNumber(genid.MapEntry_Key_field_number)
+	k := fd.Default()
+	for len(b) > 0 {
+		num, wtyp, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			break
+		}
+		b = b[n:]
+		if num == fd.Number() {
+			if v, _, err := (UnmarshalOptions{}).unmarshalScalar(b, wtyp, fd); err == nil {
+				k = v
+			}
+		}
+		if n = protowire.ConsumeFieldValue(num, wtyp, b); n < 0 {
+			break
+		}
+		b = b[n:]
+	}
+	return k.MapKey()
+}
+
+// sanitizeValue appends the canonical encoding of a single value of fd,
+// which is the raw contents of the value for message and group fields.
+// Message values are located at path, and their missing required fields
+// are appended to missing.
+func (o SanitizeOptions) sanitizeValue(out []byte, fd protoreflect.FieldDescriptor, v protoreflect.Value, depth int, path []PathStep, missing *[]MissingField) ([]byte, error) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		out = protowire.AppendTag(out, fd.Number(), protowire.BytesType)
+		out, pos := appendSpeculativeLength(out)
+		out, err := o.sanitizeMessage(out, v.Bytes(), fd.Message(), depth, path, missing)
+		if err != nil {
+			return out, err
+		}
+		return finishSpeculativeLength(out, pos), nil
+	case protoreflect.GroupKind:
+		out = protowire.AppendTag(out, fd.Number(), protowire.StartGroupType)
+		out, err := o.sanitizeMessage(out, v.Bytes(), fd.Message(), depth, path, missing)
+		if err != nil {
+			return out, err
+		}
+		return protowire.AppendTag(out, fd.Number(), protowire.EndGroupType), nil
+	default:
+		out = protowire.AppendTag(out, fd.Number(), wireTypes[fd.Kind()])
+		return MarshalOptions{}.marshalSingular(out, fd, v)
+	}
+}
+
+// sanitizePacked appends the canonical encoding of a packed repeated field.
+func (o SanitizeOptions) sanitizePacked(out, b []byte, fd protoreflect.FieldDescriptor) (_ []byte, n int, err error) {
+	b, n = protowire.ConsumeBytes(b)
+	if n < 0 {
+		return out, 0, errDecode
+	}
+	out = protowire.AppendTag(out, fd.Number(), protowire.BytesType)
+	out, pos := appendSpeculativeLength(out)
+	for len(b) > 0 {
+		v, m, err := UnmarshalOptions{}.unmarshalScalar(b, wireTypes[fd.Kind()], fd)
+		if err != nil {
+			return out, 0, errDecode
+		}
+		if out, err = (MarshalOptions{}).marshalSingular(out, fd, v); err != nil {
+			return out, 0, err
+		}
+		b = b[m:]
+	}
+	return finishSpeculativeLength(out, pos), n, nil
+}
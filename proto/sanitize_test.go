@@ -0,0 +1,251 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protopack"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+	test3pb "google.golang.org/protobuf/internal/testprotos/test3"
+)
+
+func TestSanitize(t *testing.T) {
+	allTypes := (*testpb.TestAllTypes)(nil).ProtoReflect().Descriptor()
+	tests := []struct {
+		desc  string
+		opts  proto.SanitizeOptions
+		md    protoreflect.MessageDescriptor
+		input protopack.Message
+		want  protopack.Message
+	}{{
+		desc: "canonical input",
+		input: protopack.Message{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+			protopack.Tag{Number: 14, Type: protopack.BytesType}, protopack.String("hello"),
+			protopack.Tag{Number: 16, Type: protopack.StartGroupType},
+			protopack.Tag{Number: 17, Type: protopack.VarintType}, protopack.Varint(2),
+			protopack.Tag{Number: 16, Type: protopack.EndGroupType},
+			protopack.Tag{Number: 56, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(3),
+				protopack.Tag{Number: 2, Type: protopack.VarintType}, protopack.Varint(4),
+			},
+		},
+	}, {
+		desc: "denormalized varints",
+		input: protopack.Message{
+			protopack.Denormalized{Count: 2, Value: protopack.Tag{Number: 1, Type: protopack.VarintType}},
+			protopack.Denormalized{Count: 5, Value: protopack.Varint(1)},
+			protopack.Tag{Number: 18, Type: protopack.BytesType}, protopack.Denormalized{Count: 1, Value: protopack.LengthPrefix{
+				protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Denormalized{Count: 3, Value: protopack.Varint(2)},
+			}},
+			protopack.Tag{Number: 31, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Varint(1), protopack.Denormalized{Count: 1, Value: protopack.Varint(2)},
+			},
+		},
+		want: protopack.Message{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+			protopack.Tag{Number: 18, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(2),
+			},
+			protopack.Tag{Number: 31, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Varint(1), protopack.Varint(2),
+			},
+		},
+	}, {
+		desc: "unknown fields dropped",
+		input: protopack.Message{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+			protopack.Tag{Number: 1000, Type: protopack.BytesType}, protopack.String("unknown"),
+			protopack.Tag{Number: 18, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Tag{Number: 1000, Type: protopack.VarintType}, protopack.Varint(2),
+			},
+			protopack.Tag{Number: 14, Type: protopack.VarintType}, protopack.Varint(3),
+		},
+		want: protopack.Message{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+			protopack.Tag{Number: 18, Type: protopack.BytesType}, protopack.LengthPrefix{},
+		},
+	}, {
+		desc: "unknown fields kept",
+		opts: proto.SanitizeOptions{KeepUnknown: true},
+		input: protopack.Message{
+			protopack.Tag{Number: 1000, Type: protopack.BytesType}, protopack.String("unknown"),
+			protopack.Tag{Number: 14, Type: protopack.VarintType}, protopack.Varint(3),
+		},
+	}, {
+		desc: "oversized fields dropped",
+		opts: proto.SanitizeOptions{MaxFieldSize: 8, DropOversized: true},
+		input: protopack.Message{
+			protopack.Tag{Number: 14, Type: protopack.BytesType}, protopack.String("small"),
+			protopack.Tag{Number: 15, Type: protopack.BytesType}, protopack.Bytes("too large"),
+			protopack.Tag{Number: 18, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+				protopack.Tag{Number: 2, Type: protopack.BytesType}, protopack.LengthPrefix{
+					protopack.Tag{Number: 14, Type: protopack.BytesType}, protopack.String("too large"),
+				},
+			},
+		},
+		want: protopack.Message{
+			protopack.Tag{Number: 14, Type: protopack.BytesType}, protopack.String("small"),
+			protopack.Tag{Number: 18, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+				protopack.Tag{Number: 2, Type: protopack.BytesType}, protopack.LengthPrefix{},
+			},
+		},
+	}, {
+		desc: "missing required field allowed",
+		opts: proto.SanitizeOptions{AllowPartial: true},
+		md:   (*testpb.TestRequired)(nil).ProtoReflect().Descriptor(),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			md := tt.md
+			if md == nil {
+				md = allTypes
+			}
+			if tt.want == nil {
+				tt.want = tt.input
+			}
+			got, err := tt.opts.Sanitize(tt.input.Marshal(), md)
+			if err != nil {
+				t.Fatalf("Sanitize() error: %v", err)
+			}
+			if want := tt.want.Marshal(); !bytes.Equal(got, want) {
+				var gotMsg protopack.Message
+				gotMsg.UnmarshalDescriptor(got, md)
+				t.Errorf("Sanitize() mismatch:\ngot:  %+v\nwant: %+v", gotMsg, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeErrors(t *testing.T) {
+	allTypes := (*testpb.TestAllTypes)(nil).ProtoReflect().Descriptor()
+	nested := protopack.Message{}
+	for i := 0; i < 20; i++ {
+		nested = protopack.Message{
+			protopack.Tag{Number: 18, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Tag{Number: 2, Type: protopack.BytesType}, protopack.LengthPrefix(nested),
+			},
+		}
+	}
+	tests := []struct {
+		desc    string
+		opts    proto.SanitizeOptions
+		md      protoreflect.MessageDescriptor
+		input   protopack.Message
+		wantErr string
+	}{{
+		desc: "truncated",
+		input: protopack.Message{
+			protopack.Tag{Number: 14, Type: protopack.BytesType}, protopack.Raw("\x05abc"),
+		},
+		wantErr: "cannot parse invalid wire-format data",
+	}, {
+		desc: "truncated unknown field",
+		input: protopack.Message{
+			protopack.Tag{Number: 1000, Type: protopack.BytesType}, protopack.Raw("\x05abc"),
+		},
+		wantErr: "cannot parse invalid wire-format data",
+	}, {
+		desc: "truncated packed field",
+		input: protopack.Message{
+			protopack.Tag{Number: 31, Type: protopack.BytesType}, protopack.LengthPrefix{protopack.Raw("\x80")},
+		},
+		wantErr: "cannot parse invalid wire-format data",
+	}, {
+		desc: "invalid UTF-8",
+		md:   (*test3pb.TestAllTypes)(nil).ProtoReflect().Descriptor(),
+		input: protopack.Message{
+			protopack.Tag{Number: 14, Type: protopack.BytesType}, protopack.String("\xff"),
+		},
+		wantErr: "invalid UTF-8",
+	}, {
+		desc:    "missing required field",
+		md:      (*testpb.TestRequired)(nil).ProtoReflect().Descriptor(),
+		wantErr: "required field (goproto.proto.test.TestRequired).required_field not set",
+	}, {
+		desc: "oversized required field",
+		opts: proto.SanitizeOptions{MaxFieldSize: 1, DropOversized: true},
+		md:   (*testpb.TestRequired)(nil).ProtoReflect().Descriptor(),
+		input: protopack.Message{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+		},
+		wantErr: "required field (goproto.proto.test.TestRequired).required_field not set",
+	}, {
+		desc: "oversized field rejected",
+		opts: proto.SanitizeOptions{MaxFieldSize: 4},
+		input: protopack.Message{
+			protopack.Tag{Number: 14, Type: protopack.BytesType}, protopack.String("too large"),
+		},
+		wantErr: "field goproto.proto.test.TestAllTypes.optional_string: 11 exceeds MaxFieldSize",
+	}, {
+		desc: "oversized input",
+		opts: proto.SanitizeOptions{Limits: proto.Limits{MaxMessageSize: 4}},
+		input: protopack.Message{
+			protopack.Tag{Number: 14, Type: protopack.BytesType}, protopack.String("too large"),
+		},
+		wantErr: "input of 11 bytes exceeds MaxMessageSize",
+	}, {
+		desc:    "recursion limit",
+		opts:    proto.SanitizeOptions{RecursionLimit: 10},
+		input:   nested,
+		wantErr: "exceeded max recursion depth",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			md := tt.md
+			if md == nil {
+				md = allTypes
+			}
+			_, err := tt.opts.Sanitize(tt.input.Marshal(), md)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Sanitize() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The default recursion limit accepts the same input as Unmarshal.
+	if _, err := proto.Sanitize(nested.Marshal(), allTypes); err != nil {
+		t.Errorf("Sanitize() error: %v", err)
+	}
+}
+
+func TestSanitizeRequiredNotSet(t *testing.T) {
+	// Sanitize reports the same missing fields as CheckInitialized.
+	m := &testpb.TestRequiredForeign{
+		OptionalMessage: &testpb.TestRequired{},
+		RepeatedMessage: []*testpb.TestRequired{
+			{RequiredField: proto.Int32(1)},
+			{},
+		},
+		MapMessage: map[int32]*testpb.TestRequired{
+			1: {},
+		},
+	}
+	b, err := proto.MarshalOptions{AllowPartial: true}.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = proto.Sanitize(b, m.ProtoReflect().Descriptor())
+	var got *proto.RequiredNotSetError
+	if !errors.As(err, &got) {
+		t.Fatalf("Sanitize() error = %v, want *RequiredNotSetError", err)
+	}
+	var want *proto.RequiredNotSetError
+	if !errors.As(proto.CheckInitialized(m), &want) {
+		t.Fatalf("CheckInitialized() did not return a *RequiredNotSetError")
+	}
+	if got.Error() != want.Error() {
+		t.Errorf("Sanitize() error = %q, want %q", got, want)
+	}
+}
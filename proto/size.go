@@ -17,6 +17,11 @@ func Size(m Message) int {
 }
 
 // Size returns the size in bytes of the wire-format encoding of m.
+//
+// Generated messages record the sizes computed by Size, so that a subsequent
+// Marshal with the same options and [MarshalOptions.UseCachedSize] set
+// does not need to compute them again, as long as m is not modified
+// in between.
 func (o MarshalOptions) Size(m Message) int {
 	// Treat a nil message interface as an empty message; nothing to output.
 	if m == nil {
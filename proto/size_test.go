@@ -5,7 +5,11 @@
 package proto_test
 
 import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 // Checking if [Size] returns 0 is an easy way to recognize empty messages:
@@ -16,3 +20,24 @@ func ExampleSize() {
 		// skip processing this message, or return an error, or similar.
 	}
 }
+
+// Calling [MarshalOptions.Size] before marshaling, for example to write a
+// length prefix, does not require computing the sizes twice:
+func ExampleMarshalOptions_Size_cachedSize() {
+	m := &durationpb.Duration{Seconds: 60}
+
+	opts := proto.MarshalOptions{Deterministic: true}
+	size := opts.Size(m)
+
+	// The message is not modified between Size and Marshal,
+	// so the sizes computed by Size can be reused.
+	opts.UseCachedSize = true
+	b := protowire.AppendVarint(nil, uint64(size))
+	b, err := opts.MarshalAppend(b, m)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%d bytes: %x\n", len(b), b)
+
+	// Output: 3 bytes: 02083c
+}
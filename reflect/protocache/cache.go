@@ -0,0 +1,212 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protocache implements a versioned cache file format for sets of
+// resolved file descriptors.
+//
+// Building descriptors with [protodesc.NewFiles] validates and resolves every
+// file up front, which is costly for large descriptor sets. A cache file
+// instead stores descriptors that have already been validated, in dependency
+// order, together with an index of their contents. Loading a cache file with
+// [Unmarshal] only checks that the contents are well-formed, without
+// resolving them, and constructs the descriptors lazily, directly from
+// the cache contents.
+//
+// Since the loaded descriptors reference the cache contents without copying
+// them, the cache file may be memory-mapped read-only and shared by multiple
+// processes on the same host.
+//
+// # File format
+//
+// All integers are encoded as little-endian uint32 values.
+// A cache file begins with a 16-byte header consisting of:
+//
+//   - the magic bytes "PBDC",
+//   - the format version (see [Version]),
+//   - the number of files, and
+//   - the CRC-32 checksum (Castagnoli polynomial) of the rest of the file.
+//
+// The header is followed by an index with a 24-byte entry for each file,
+// consisting of the offset and length of the wire-encoded
+// google.protobuf.FileDescriptorProto message, and the total number of
+// enums, messages, extensions, and services declared in the file.
+// Files appear in the index after all of their imports.
+// The index is followed by the file contents.
+package protocache
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+	"sort"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/filedesc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Version is the version of the cache file format produced by [Marshal].
+const Version = 1
+
+const (
+	magic      = "PBDC"
+	headerSize = 16
+	entrySize  = 24
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Marshal returns a cache file containing all files in the registry.
+// The output is deterministic for a given set of files.
+func Marshal(files *protoregistry.Files) ([]byte, error) {
+	var all []protoreflect.FileDescriptor
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		all = append(all, fd)
+		return true
+	})
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Path() < all[j].Path()
+	})
+
+	// Order the files such that each file appears after its imports.
+	var ordered []protoreflect.FileDescriptor
+	seen := make(map[string]bool)
+	var visit func(fd protoreflect.FileDescriptor)
+	visit = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			if imp, err := files.FindFileByPath(imports.Get(i).Path()); err == nil {
+				visit(imp)
+			}
+		}
+		ordered = append(ordered, fd)
+	}
+	for _, fd := range all {
+		visit(fd)
+	}
+
+	b := make([]byte, headerSize+entrySize*len(ordered))
+	copy(b, magic)
+	binary.LittleEndian.PutUint32(b[4:], Version)
+	binary.LittleEndian.PutUint32(b[8:], uint32(len(ordered)))
+	for i, fd := range ordered {
+		raw, err := proto.MarshalOptions{Deterministic: true}.Marshal(protodesc.ToFileDescriptorProto(fd))
+		if err != nil {
+			return nil, errors.Wrap(err, "%v", fd.Path())
+		}
+		if len(b)+len(raw) > math.MaxUint32 {
+			return nil, errors.New("cache file exceeds 4GiB")
+		}
+		var c counts
+		c.addFile(fd)
+		e := b[headerSize+i*entrySize:]
+		binary.LittleEndian.PutUint32(e[0:], uint32(len(b)))
+		binary.LittleEndian.PutUint32(e[4:], uint32(len(raw)))
+		binary.LittleEndian.PutUint32(e[8:], c.enums)
+		binary.LittleEndian.PutUint32(e[12:], c.messages)
+		binary.LittleEndian.PutUint32(e[16:], c.extensions)
+		binary.LittleEndian.PutUint32(e[20:], c.services)
+		b = append(b, raw...)
+	}
+	binary.LittleEndian.PutUint32(b[12:], crc32.Checksum(b[headerSize:], crcTable))
+	return b, nil
+}
+
+// counts is the number of declarations in a file.
+type counts struct {
+	enums, messages, extensions, services uint32
+}
+
+func (c *counts) addFile(fd protoreflect.FileDescriptor) {
+	c.addDecls(fd.Enums(), fd.Messages(), fd.Extensions())
+	c.services += uint32(fd.Services().Len())
+}
+
+func (c *counts) addDecls(enums protoreflect.EnumDescriptors, messages protoreflect.MessageDescriptors, extensions protoreflect.ExtensionDescriptors) {
+	c.enums += uint32(enums.Len())
+	c.messages += uint32(messages.Len())
+	c.extensions += uint32(extensions.Len())
+	for i := 0; i < messages.Len(); i++ {
+		md := messages.Get(i)
+		c.addDecls(md.Enums(), md.Messages(), md.Extensions())
+	}
+}
+
+// Unmarshal parses a cache file produced by [Marshal] and returns a new
+// registry containing its files.
+//
+// The descriptors are constructed lazily and retain references to b,
+// which must not be modified afterwards. Each file is checked to be
+// well-formed before it is constructed, but references to other descriptors
+// are not resolved and the files are not validated as thoroughly as
+// by [protodesc.NewFiles].
+// Options are resolved using [protoregistry.GlobalTypes].
+func Unmarshal(b []byte) (*protoregistry.Files, error) {
+	if len(b) < headerSize || string(b[:4]) != magic {
+		return nil, errors.New("invalid descriptor cache file")
+	}
+	if v := binary.LittleEndian.Uint32(b[4:]); v != Version {
+		return nil, errors.New("unsupported descriptor cache version %d", v)
+	}
+	if crc32.Checksum(b[headerSize:], crcTable) != binary.LittleEndian.Uint32(b[12:]) {
+		return nil, errors.New("descriptor cache checksum mismatch")
+	}
+	n := uint64(binary.LittleEndian.Uint32(b[8:]))
+	if headerSize+n*entrySize > uint64(len(b)) {
+		return nil, errors.New("descriptor cache index out of bounds")
+	}
+
+	files := new(protoregistry.Files)
+	for i := uint64(0); i < n; i++ {
+		e := b[headerSize+i*entrySize:]
+		off := uint64(binary.LittleEndian.Uint32(e[0:]))
+		size := uint64(binary.LittleEndian.Uint32(e[4:]))
+		if off+size > uint64(len(b)) {
+			return nil, errors.New("descriptor cache entry %d out of bounds", i)
+		}
+		raw := b[off : off+size : off+size]
+		c := counts{
+			enums:      binary.LittleEndian.Uint32(e[8:]),
+			messages:   binary.LittleEndian.Uint32(e[12:]),
+			extensions: binary.LittleEndian.Uint32(e[16:]),
+			services:   binary.LittleEndian.Uint32(e[20:]),
+		}
+		if err := validateFile(raw, c, files); err != nil {
+			return nil, errors.Wrap(err, "descriptor cache entry %d", i)
+		}
+		r := &registry{Files: files}
+		filedesc.Builder{
+			RawDescriptor: raw,
+			NumEnums:      int32(c.enums),
+			NumMessages:   int32(c.messages),
+			NumExtensions: int32(c.extensions),
+			NumServices:   int32(c.services),
+			FileRegistry:  r,
+		}.Build()
+		if r.err != nil {
+			return nil, errors.Wrap(r.err, "descriptor cache entry %d", i)
+		}
+	}
+	return files, nil
+}
+
+// registry records a failure to register a file (e.g., due to a name
+// conflict) instead of reporting it to filedesc.Builder.Build,
+// which would panic.
+type registry struct {
+	*protoregistry.Files
+	err error
+}
+
+func (r *registry) RegisterFile(fd protoreflect.FileDescriptor) error {
+	r.err = r.Files.RegisterFile(fd)
+	return nil
+}
@@ -0,0 +1,300 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocache_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protocache"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	_ "google.golang.org/protobuf/internal/testprotos/test3"
+	_ "google.golang.org/protobuf/internal/testprotos/testeditions"
+)
+
+// testFiles returns a registry with a copy of the test files
+// and their dependencies.
+func testFiles(t testing.TB) *protoregistry.Files {
+	files := new(protoregistry.Files)
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if _, err := files.FindFileByPath(fd.Path()); err == nil {
+			return
+		}
+		for i := 0; i < fd.Imports().Len(); i++ {
+			add(fd.Imports().Get(i).FileDescriptor)
+		}
+		fd, err := protodesc.NewFile(protodesc.ToFileDescriptorProto(fd), files)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := files.RegisterFile(fd); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range []string{
+		"internal/testprotos/test3/test.proto",
+		"internal/testprotos/testeditions/test.proto",
+	} {
+		fd, err := protoregistry.GlobalFiles.FindFileByPath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		add(fd)
+	}
+	return files
+}
+
+func TestRoundTrip(t *testing.T) {
+	want := testFiles(t)
+	b, err := protocache.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b2, err := protocache.Marshal(want); err != nil || !bytes.Equal(b, b2) {
+		t.Errorf("Marshal is not deterministic")
+	}
+
+	got, err := protocache.Unmarshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NumFiles() != want.NumFiles() {
+		t.Errorf("NumFiles() = %v, want %v", got.NumFiles(), want.NumFiles())
+	}
+	want.RangeFiles(func(wantFD protoreflect.FileDescriptor) bool {
+		gotFD, err := got.FindFileByPath(wantFD.Path())
+		if err != nil {
+			t.Errorf("FindFileByPath(%q) error: %v", wantFD.Path(), err)
+			return true
+		}
+		if diff := cmp.Diff(protodesc.ToFileDescriptorProto(wantFD), protodesc.ToFileDescriptorProto(gotFD), protocmp.Transform()); diff != "" {
+			t.Errorf("file %q mismatch (-want +got):\n%s", wantFD.Path(), diff)
+		}
+		return true
+	})
+
+	// Dependencies are resolved within the loaded registry.
+	d, err := got.FindDescriptorByName("goproto.proto.test3.TestAllTypes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd := d.(protoreflect.MessageDescriptor).Fields().ByName("optional_import_message")
+	imp := fd.Message().ParentFile()
+	if gotImp, _ := got.FindFileByPath(imp.Path()); imp != gotImp {
+		t.Errorf("optional_import_message resolved to file %p, want %p from the loaded registry", imp, gotImp)
+	}
+	if fd.Message().IsPlaceholder() {
+		t.Errorf("optional_import_message resolved to a placeholder")
+	}
+
+	// The loaded descriptors can be converted back into validated descriptors.
+	fdset := &descriptorpb.FileDescriptorSet{}
+	got.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		fdset.File = append(fdset.File, protodesc.ToFileDescriptorProto(fd))
+		return true
+	})
+	if _, err := protodesc.NewFiles(fdset); err != nil {
+		t.Errorf("NewFiles() error: %v", err)
+	}
+	if _, err := protocache.Marshal(new(protoregistry.Files)); err != nil {
+		t.Errorf("Marshal(empty) error: %v", err)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	b, err := protocache.Marshal(testFiles(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	modify := func(f func(b []byte) []byte) []byte {
+		return f(append([]byte(nil), b...))
+	}
+	withChecksum := func(b []byte) []byte {
+		binary.LittleEndian.PutUint32(b[12:], crc32.Checksum(b[16:], crc32.MakeTable(crc32.Castagnoli)))
+		return b
+	}
+
+	for _, tt := range []struct {
+		desc string
+		in   []byte
+	}{
+		{"empty", nil},
+		{"bad magic", modify(func(b []byte) []byte { b[0] = 'X'; return b })},
+		{"bad version", modify(func(b []byte) []byte { binary.LittleEndian.PutUint32(b[4:], 99); return b })},
+		{"corrupt contents", modify(func(b []byte) []byte { b[len(b)-1] ^= 0xff; return b })},
+		{"truncated", b[:len(b)-1]},
+		{"index out of bounds", modify(func(b []byte) []byte { binary.LittleEndian.PutUint32(b[8:], 1<<20); return withChecksum(b) })},
+		{"entry out of bounds", modify(func(b []byte) []byte { binary.LittleEndian.PutUint32(b[20:], uint32(len(b))); return withChecksum(b) })},
+		{"duplicate file", modify(func(b []byte) []byte { copy(b[16+24:], b[16:16+24]); return withChecksum(b) })},
+	} {
+		if _, err := protocache.Unmarshal(tt.in); err == nil {
+			t.Errorf("%v: Unmarshal() succeeded, want error", tt.desc)
+		}
+	}
+}
+
+// rawCache returns a cache file with a single file consisting of raw,
+// which declares the given number of messages.
+func rawCache(raw []byte, numMessages uint32) []byte {
+	b := make([]byte, 16+24)
+	copy(b, "PBDC")
+	binary.LittleEndian.PutUint32(b[4:], protocache.Version)
+	binary.LittleEndian.PutUint32(b[8:], 1)
+	binary.LittleEndian.PutUint32(b[16:], uint32(len(b)))
+	binary.LittleEndian.PutUint32(b[20:], uint32(len(raw)))
+	binary.LittleEndian.PutUint32(b[28:], numMessages)
+	b = append(b, raw...)
+	binary.LittleEndian.PutUint32(b[12:], crc32.Checksum(b[16:], crc32.MakeTable(crc32.Castagnoli)))
+	return b
+}
+
+func TestUnmarshalInvalidContents(t *testing.T) {
+	marshal := func(s string) []byte {
+		fd := new(descriptorpb.FileDescriptorProto)
+		if err := prototext.Unmarshal([]byte(s), fd); err != nil {
+			t.Fatal(err)
+		}
+		b, err := proto.MarshalOptions{Deterministic: true}.Marshal(fd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("a.proto"),
+		Syntax:  proto.String("editions"),
+		Edition: descriptorpb.Edition_EDITION_2023.Enum(),
+		Options: &descriptorpb.FileOptions{Features: &descriptorpb.FeatureSet{}},
+	}
+	fd.Options.Features.ProtoReflect().SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 1000, protowire.VarintType), 1))
+	unknownFeature, err := proto.Marshal(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd.Options.Features.ProtoReflect().SetUnknown(protowire.AppendBytes(
+		protowire.AppendTag(nil, 1002, protowire.BytesType),
+		protowire.AppendVarint(protowire.AppendTag(nil, 99, protowire.VarintType), 1),
+	))
+	unknownGoFeature, err := proto.Marshal(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withField := func(b []byte, num protowire.Number, s string) []byte {
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		return protowire.AppendString(b, s)
+	}
+
+	for _, tt := range []struct {
+		desc        string
+		raw         []byte
+		numMessages uint32
+	}{{
+		desc: "malformed",
+		raw:  []byte{0x0a, 0x05, 'a'},
+	}, {
+		desc: "invalid syntax",
+		raw:  marshal(`name: "a.proto" syntax: "proto4"`),
+	}, {
+		desc: "mismatching counts",
+		raw:  marshal(`name: "a.proto" message_type: {name: "M"}`),
+	}, {
+		desc:        "non-contiguous repeated field",
+		raw:         withField(marshal(`name: "a.proto" message_type: {name: "M"} syntax: "proto2"`), 4, "\x0a\x01N"),
+		numMessages: 2,
+	}, {
+		desc: "missing edition",
+		raw:  marshal(`name: "a.proto" syntax: "editions"`),
+	}, {
+		desc: "features without editions",
+		raw:  marshal(`name: "a.proto" syntax: "proto3" options: {features: {field_presence: IMPLICIT}}`),
+	}, {
+		desc: "unknown feature",
+		raw:  unknownFeature,
+	}, {
+		desc: "unknown Go feature",
+		raw:  unknownGoFeature,
+	}, {
+		desc: "malformed options",
+		raw:  withField(marshal(`name: "a.proto"`), 8, "\x0a"),
+	}, {
+		desc: "unqualified type name",
+		raw: marshal(`name: "a.proto" message_type: {name: "M" field: {
+			name: "f" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: "M"
+		}}`),
+		numMessages: 1,
+	}, {
+		desc: "oneof index out of range",
+		raw: marshal(`name: "a.proto" message_type: {name: "M" field: {
+			name: "f" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32 oneof_index: 0
+		}}`),
+		numMessages: 1,
+	}, {
+		desc: "invalid default value",
+		raw: marshal(`name: "a.proto" message_type: {name: "M" field: {
+			name: "f" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32 default_value: "x"
+		}}`),
+		numMessages: 1,
+	}, {
+		desc: "invalid enum default value",
+		raw: marshal(`name: "a.proto" package: "p"
+			enum_type: {name: "E" value: {name: "A" number: 0}}
+			message_type: {name: "M" field: {
+				name: "f" number: 1 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".p.E" default_value: "B"
+			}}`),
+		numMessages: 1,
+	}, {
+		desc: "extension without extendee",
+		raw:  marshal(`name: "a.proto" extension: {name: "x" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32}`),
+	}} {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := protocache.Unmarshal(rawCache(tt.raw, tt.numMessages)); err == nil {
+				t.Errorf("Unmarshal() succeeded, want error")
+			}
+		})
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	files := testFiles(b)
+	cache, err := protocache.Marshal(files)
+	if err != nil {
+		b.Fatal(err)
+	}
+	fdset := &descriptorpb.FileDescriptorSet{}
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		fdset.File = append(fdset.File, protodesc.ToFileDescriptorProto(fd))
+		return true
+	})
+
+	b.Run("Cache", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := protocache.Unmarshal(cache); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("NewFiles", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := protodesc.NewFiles(fdset); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
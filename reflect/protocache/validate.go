@@ -0,0 +1,708 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocache
+
+import (
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/editionssupport"
+	"google.golang.org/protobuf/internal/encoding/defval"
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/internal/strs"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/gofeaturespb"
+)
+
+var fileDescriptorProto = (*descriptorpb.FileDescriptorProto)(nil).ProtoReflect().Descriptor()
+
+// validateFile checks that raw is a file descriptor that the lazy descriptor
+// implementation in internal/filedesc can build without encountering
+// inconsistencies, which it reports by panicking.
+//
+// It does not resolve or validate the file as thoroughly as
+// protodesc.NewFile does. It only checks the properties that the lazy
+// implementation relies upon: the encoding is canonical, the declaration
+// counts match c, the options can be unmarshaled, and all references and
+// default values are well-formed.
+//
+// The file is checked by walking its wire-format encoding, in the same
+// manner as internal/filedesc parses it, without unmarshaling it into
+// a descriptorpb.FileDescriptorProto.
+func validateFile(raw []byte, c counts, files *protoregistry.Files) error {
+	v := &validator{files: files}
+	if err := v.checkFile(raw); err != nil {
+		return err
+	}
+	if v.counts != c {
+		return errors.New("declaration counts do not match the index")
+	}
+	return v.checkEnumDefaults(raw)
+}
+
+type validator struct {
+	files *protoregistry.Files
+	// counts is the number of declarations found in the file.
+	counts counts
+	// hasFeatures reports whether any declaration in the file
+	// specifies edition features.
+	hasFeatures bool
+	// enumDefaults are the default values of enum fields, which are checked
+	// once the whole file has been walked, since the enum may be declared
+	// after the field.
+	enumDefaults []enumDefault
+}
+
+type enumDefault struct {
+	field, typeName, value []byte
+}
+
+// fieldReader reads the fields of a wire-format message of type md,
+// checking that they are well-formed and that they appear in order of
+// increasing field number, with each repeated field encoded contiguously,
+// as produced by deterministic marshaling.
+type fieldReader struct {
+	md  protoreflect.MessageDescriptor
+	b   []byte
+	err error
+
+	num protowire.Number
+	typ protowire.Type
+	fd  protoreflect.FieldDescriptor
+	val uint64 // value of a varint field
+	raw []byte // contents of a length-delimited field
+}
+
+func (r *fieldReader) next() bool {
+	if r.err != nil || len(r.b) == 0 {
+		return false
+	}
+	num, typ, n := protowire.ConsumeTag(r.b)
+	if n < 0 {
+		r.err = protowire.ParseError(n)
+		return false
+	}
+	fd := r.md.Fields().ByNumber(num)
+	if num < r.num || (num == r.num && (fd == nil || fd.Cardinality() != protoreflect.Repeated)) {
+		r.err = errors.New("%v: field %d is not in canonical order", r.md.FullName(), num)
+		return false
+	}
+	b := r.b[n:]
+	m := protowire.ConsumeFieldValue(num, typ, b)
+	if m < 0 {
+		r.err = protowire.ParseError(m)
+		return false
+	}
+	r.num, r.typ, r.fd = num, typ, fd
+	r.val, r.raw = 0, nil
+	switch typ {
+	case protowire.VarintType:
+		r.val, _ = protowire.ConsumeVarint(b)
+	case protowire.BytesType:
+		r.raw, _ = protowire.ConsumeBytes(b)
+	}
+	r.b = b[m:]
+	return true
+}
+
+// bytes reports whether the current field is the length-delimited field num.
+func (r *fieldReader) bytes(num protowire.Number) bool {
+	return r.num == num && r.typ == protowire.BytesType
+}
+
+// varint reports whether the current field is the varint field num.
+func (r *fieldReader) varint(num protowire.Number) bool {
+	return r.num == num && r.typ == protowire.VarintType
+}
+
+// enum returns the value of the current enum field and reports whether
+// it is a value of the enum, since unknown values of closed enums are
+// unmarshaled as unknown fields.
+func (r *fieldReader) enum() (protoreflect.EnumNumber, bool) {
+	n := protoreflect.EnumNumber(int32(r.val))
+	return n, r.fd.Enum().Values().ByNumber(n) != nil
+}
+
+// skip checks the encoding of the current field if it is a message,
+// which internal/filedesc may parse by hand.
+func (r *fieldReader) skip() error {
+	if r.fd == nil || r.fd.Message() == nil || r.typ != protowire.BytesType {
+		return nil
+	}
+	return checkWireOrder(r.raw, r.fd.Message())
+}
+
+// checkWireOrder checks that the message in b and its sub-messages are
+// encoded in canonical order.
+func checkWireOrder(b []byte, md protoreflect.MessageDescriptor) error {
+	r := fieldReader{md: md, b: b}
+	for r.next() {
+		if err := r.skip(); err != nil {
+			return err
+		}
+	}
+	return r.err
+}
+
+func (v *validator) checkFile(b []byte) error {
+	var syntax []byte
+	var hasEdition bool
+	var numDeps int32
+	checkDep := func(i int32) error {
+		if i < 0 || i >= numDeps {
+			return errors.New("dependency index %d out of range", i)
+		}
+		return nil
+	}
+	r := fieldReader{md: fileDescriptorProto, b: b}
+	for r.next() {
+		var err error
+		switch {
+		case r.bytes(genid.FileDescriptorProto_Dependency_field_number):
+			numDeps++
+		case r.varint(genid.FileDescriptorProto_PublicDependency_field_number),
+			r.varint(genid.FileDescriptorProto_WeakDependency_field_number):
+			err = checkDep(int32(r.val))
+		case r.bytes(genid.FileDescriptorProto_PublicDependency_field_number),
+			r.bytes(genid.FileDescriptorProto_WeakDependency_field_number):
+			for b := r.raw; len(b) > 0 && err == nil; {
+				x, n := protowire.ConsumeVarint(b)
+				if n < 0 {
+					return protowire.ParseError(n)
+				}
+				err = checkDep(int32(x))
+				b = b[n:]
+			}
+		case r.bytes(genid.FileDescriptorProto_MessageType_field_number):
+			err = v.checkMessage(r.raw, r.fd.Message())
+		case r.bytes(genid.FileDescriptorProto_EnumType_field_number):
+			err = v.checkEnum(r.raw, r.fd.Message())
+		case r.bytes(genid.FileDescriptorProto_Service_field_number):
+			err = v.checkService(r.raw, r.fd.Message())
+		case r.bytes(genid.FileDescriptorProto_Extension_field_number):
+			v.counts.extensions++
+			_, err = v.checkField(r.raw, r.fd.Message(), true)
+		case r.bytes(genid.FileDescriptorProto_Options_field_number):
+			err = v.checkOptions(r.raw, r.fd.Message())
+		case r.bytes(genid.FileDescriptorProto_Syntax_field_number):
+			syntax = r.raw
+		case r.varint(genid.FileDescriptorProto_Edition_field_number):
+			edition, ok := r.enum()
+			if !ok {
+				break
+			}
+			if e := descriptorpb.Edition(edition); e < editionssupport.Minimum || e > editionssupport.Maximum {
+				return errors.New("unsupported edition: %v", e)
+			}
+			hasEdition = true
+		default:
+			err = r.skip()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if r.err != nil {
+		return r.err
+	}
+
+	editions := string(syntax) == "editions"
+	switch string(syntax) {
+	case "", "proto2", "proto3", "editions":
+	default:
+		return errors.New("invalid syntax: %q", syntax)
+	}
+	if editions && !hasEdition {
+		return errors.New("missing edition")
+	}
+	if v.hasFeatures && !editions {
+		return errors.New("using edition features in a proto with syntax %s", syntax)
+	}
+	return nil
+}
+
+func (v *validator) checkEnum(b []byte, md protoreflect.MessageDescriptor) error {
+	v.counts.enums++
+	var name []byte
+	r := fieldReader{md: md, b: b}
+	for r.next() {
+		var err error
+		switch {
+		case r.bytes(genid.EnumDescriptorProto_Name_field_number):
+			name = r.raw
+		case r.bytes(genid.EnumDescriptorProto_Value_field_number):
+			err = v.checkEnumValue(r.raw, r.fd.Message())
+		case r.bytes(genid.EnumDescriptorProto_Options_field_number):
+			err = v.checkOptions(r.raw, r.fd.Message())
+		default:
+			err = r.skip()
+		}
+		if err != nil {
+			return errors.Wrap(err, "enum %s", name)
+		}
+	}
+	if r.err != nil {
+		return errors.Wrap(r.err, "enum %s", name)
+	}
+	return nil
+}
+
+func (v *validator) checkEnumValue(b []byte, md protoreflect.MessageDescriptor) error {
+	var name []byte
+	r := fieldReader{md: md, b: b}
+	for r.next() {
+		var err error
+		switch {
+		case r.bytes(genid.EnumValueDescriptorProto_Name_field_number):
+			name = r.raw
+		case r.bytes(genid.EnumValueDescriptorProto_Options_field_number):
+			err = v.checkOptions(r.raw, r.fd.Message())
+		default:
+			err = r.skip()
+		}
+		if err != nil {
+			return errors.Wrap(err, "enum value %s", name)
+		}
+	}
+	return r.err
+}
+
+func (v *validator) checkMessage(b []byte, md protoreflect.MessageDescriptor) error {
+	v.counts.messages++
+	var name []byte
+	// The oneofs are declared after the fields, so the largest oneof index
+	// of a field is only checked once all oneofs are known.
+	var numOneofs, maxOneof int32 = 0, -1
+	var maxOneofField []byte
+	r := fieldReader{md: md, b: b}
+	for r.next() {
+		var err error
+		switch {
+		case r.bytes(genid.DescriptorProto_Name_field_number):
+			name = r.raw
+		case r.bytes(genid.DescriptorProto_Field_field_number):
+			var oneof int32
+			oneof, err = v.checkField(r.raw, r.fd.Message(), false)
+			if oneof > maxOneof {
+				maxOneof, maxOneofField = oneof, r.raw
+			}
+		case r.bytes(genid.DescriptorProto_NestedType_field_number):
+			err = v.checkMessage(r.raw, r.fd.Message())
+		case r.bytes(genid.DescriptorProto_EnumType_field_number):
+			err = v.checkEnum(r.raw, r.fd.Message())
+		case r.bytes(genid.DescriptorProto_ExtensionRange_field_number):
+			err = v.checkExtensionRange(r.raw, r.fd.Message())
+		case r.bytes(genid.DescriptorProto_Extension_field_number):
+			v.counts.extensions++
+			_, err = v.checkField(r.raw, r.fd.Message(), true)
+		case r.bytes(genid.DescriptorProto_Options_field_number):
+			err = v.checkOptions(r.raw, r.fd.Message())
+		case r.bytes(genid.DescriptorProto_OneofDecl_field_number):
+			numOneofs++
+			err = v.checkOneof(r.raw, r.fd.Message())
+		default:
+			err = r.skip()
+		}
+		if err != nil {
+			return errors.Wrap(err, "message %s", name)
+		}
+	}
+	if r.err != nil {
+		return errors.Wrap(r.err, "message %s", name)
+	}
+	if maxOneof >= numOneofs {
+		return errors.New("message %s: field %s: invalid oneof index %d", name, fieldName(maxOneofField), maxOneof)
+	}
+	return nil
+}
+
+func (v *validator) checkOneof(b []byte, md protoreflect.MessageDescriptor) error {
+	var name []byte
+	r := fieldReader{md: md, b: b}
+	for r.next() {
+		var err error
+		switch {
+		case r.bytes(genid.OneofDescriptorProto_Name_field_number):
+			name = r.raw
+		case r.bytes(genid.OneofDescriptorProto_Options_field_number):
+			err = v.checkOptions(r.raw, r.fd.Message())
+		default:
+			err = r.skip()
+		}
+		if err != nil {
+			return errors.Wrap(err, "oneof %s", name)
+		}
+	}
+	return r.err
+}
+
+func (v *validator) checkExtensionRange(b []byte, md protoreflect.MessageDescriptor) error {
+	r := fieldReader{md: md, b: b}
+	for r.next() {
+		var err error
+		if r.bytes(genid.DescriptorProto_ExtensionRange_Options_field_number) {
+			err = v.checkOptions(r.raw, r.fd.Message())
+		} else {
+			err = r.skip()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return r.err
+}
+
+// checkField checks the field or extension in b and returns its oneof index,
+// or -1 if it is not in a oneof.
+func (v *validator) checkField(b []byte, md protoreflect.MessageDescriptor, isExtension bool) (int32, error) {
+	oneof, err := v.checkFieldDetails(b, md, isExtension)
+	if err != nil {
+		return -1, errors.Wrap(err, "field %s", fieldName(b))
+	}
+	return oneof, nil
+}
+
+func (v *validator) checkFieldDetails(b []byte, md protoreflect.MessageDescriptor, isExtension bool) (int32, error) {
+	var kind protoreflect.Kind
+	var typeName, extendee, defaultValue []byte
+	var hasTypeName, hasExtendee, hasDefault, hasOneof bool
+	var oneof int32 = -1
+	r := fieldReader{md: md, b: b}
+	for r.next() {
+		var err error
+		switch {
+		case r.bytes(genid.FieldDescriptorProto_Extendee_field_number):
+			extendee, hasExtendee = r.raw, true
+		case r.varint(genid.FieldDescriptorProto_Type_field_number):
+			if n, ok := r.enum(); ok {
+				kind = protoreflect.Kind(n)
+			}
+		case r.bytes(genid.FieldDescriptorProto_TypeName_field_number):
+			typeName, hasTypeName = r.raw, true
+		case r.bytes(genid.FieldDescriptorProto_DefaultValue_field_number):
+			defaultValue, hasDefault = r.raw, true
+		case r.bytes(genid.FieldDescriptorProto_Options_field_number):
+			err = v.checkOptions(r.raw, r.fd.Message())
+		case r.varint(genid.FieldDescriptorProto_OneofIndex_field_number):
+			oneof, hasOneof = int32(r.val), true
+		default:
+			err = r.skip()
+		}
+		if err != nil {
+			return -1, err
+		}
+	}
+	if r.err != nil {
+		return -1, r.err
+	}
+
+	if kind == 0 {
+		return -1, errors.New("missing type")
+	}
+	switch kind {
+	case protoreflect.EnumKind, protoreflect.MessageKind, protoreflect.GroupKind:
+		if err := checkReference(typeName, hasTypeName, true); err != nil {
+			return -1, err
+		}
+	default:
+		if err := checkReference(typeName, hasTypeName, false); err != nil {
+			return -1, err
+		}
+	}
+	if err := checkReference(extendee, hasExtendee, isExtension); err != nil {
+		return -1, err
+	}
+	if hasOneof && (isExtension || oneof < 0) {
+		return -1, errors.New("invalid oneof index %d", oneof)
+	}
+	if !hasDefault {
+		return oneof, nil
+	}
+	if kind != protoreflect.EnumKind {
+		if _, _, err := defval.Unmarshal(string(defaultValue), kind, nil, defval.Descriptor); err != nil {
+			return -1, err
+		}
+		return oneof, nil
+	}
+	if !protoreflect.Name(defaultValue).IsValid() {
+		return -1, errors.New("invalid default value for enum: %q", defaultValue)
+	}
+	v.enumDefaults = append(v.enumDefaults, enumDefault{field: b, typeName: typeName, value: defaultValue})
+	return oneof, nil
+}
+
+func (v *validator) checkService(b []byte, md protoreflect.MessageDescriptor) error {
+	v.counts.services++
+	var name []byte
+	r := fieldReader{md: md, b: b}
+	for r.next() {
+		var err error
+		switch {
+		case r.bytes(genid.ServiceDescriptorProto_Name_field_number):
+			name = r.raw
+		case r.bytes(genid.ServiceDescriptorProto_Method_field_number):
+			if err := v.checkMethod(r.raw, r.fd.Message()); err != nil {
+				return errors.Wrap(err, "method %s.%s", name, fieldName(r.raw))
+			}
+		case r.bytes(genid.ServiceDescriptorProto_Options_field_number):
+			err = v.checkOptions(r.raw, r.fd.Message())
+		default:
+			err = r.skip()
+		}
+		if err != nil {
+			return errors.Wrap(err, "service %s", name)
+		}
+	}
+	if r.err != nil {
+		return errors.Wrap(r.err, "service %s", name)
+	}
+	return nil
+}
+
+func (v *validator) checkMethod(b []byte, md protoreflect.MessageDescriptor) error {
+	var inputType, outputType []byte
+	var hasInputType, hasOutputType bool
+	r := fieldReader{md: md, b: b}
+	for r.next() {
+		var err error
+		switch {
+		case r.bytes(genid.MethodDescriptorProto_InputType_field_number):
+			inputType, hasInputType = r.raw, true
+		case r.bytes(genid.MethodDescriptorProto_OutputType_field_number):
+			outputType, hasOutputType = r.raw, true
+		case r.bytes(genid.MethodDescriptorProto_Options_field_number):
+			err = v.checkOptions(r.raw, r.fd.Message())
+		default:
+			err = r.skip()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if err := checkReference(inputType, hasInputType, true); err != nil {
+		return err
+	}
+	return checkReference(outputType, hasOutputType, true)
+}
+
+// fieldName returns the name of the field, enum value, or method in b,
+// for use in error messages.
+func fieldName(b []byte) []byte {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			break
+		}
+		b = b[n:]
+		if num == genid.FieldDescriptorProto_Name_field_number && typ == protowire.BytesType {
+			v, _ := protowire.ConsumeBytes(b)
+			return v
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			break
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// checkReference checks that the type reference s is present if required and
+// that it is fully qualified.
+func checkReference(s []byte, present, required bool) error {
+	switch {
+	case !present && required:
+		return errors.New("missing type reference")
+	case present && (len(s) == 0 || s[0] != '.'):
+		return errors.New("type reference %q must be fully qualified", s)
+	}
+	return nil
+}
+
+// Modes of checkOptionsMessage, which determine the unknown fields that are
+// permitted. Options may have any unknown fields, but internal/filedesc
+// parses the features of a declaration, which only permits known features
+// and unknown length-delimited fields (i.e., features for other languages).
+const (
+	optionsMode = iota
+	featuresMode
+	goFeaturesMode
+)
+
+// checkOptions checks that the options in b can be unmarshaled by
+// internal/filedesc, which unmarshals them lazily and panics on failure,
+// and that their features can be parsed.
+func (v *validator) checkOptions(b []byte, md protoreflect.MessageDescriptor) error {
+	return v.checkOptionsMessage(b, md, optionsMode, 0)
+}
+
+func (v *validator) checkOptionsMessage(b []byte, md protoreflect.MessageDescriptor, mode, depth int) error {
+	if depth > protowire.DefaultRecursionLimit {
+		return errors.New("exceeded maximum recursion depth")
+	}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		m := protowire.ConsumeFieldValue(num, typ, b)
+		if m < 0 {
+			return protowire.ParseError(m)
+		}
+		val := b[:m]
+		b = b[m:]
+
+		fd := md.Fields().ByNumber(num)
+		if fd == nil {
+			if xt, err := protoregistry.GlobalTypes.FindExtensionByNumber(md.FullName(), num); err == nil {
+				fd = xt.TypeDescriptor()
+			}
+		}
+		known, err := v.checkOptionsValue(fd, typ, val, mode, depth)
+		if err != nil {
+			return err
+		}
+		switch {
+		case known:
+		case mode == featuresMode && typ != protowire.BytesType:
+			return errors.New("unknown feature %d", num)
+		case mode == goFeaturesMode:
+			return errors.New("unknown Go features")
+		}
+	}
+	return nil
+}
+
+// checkOptionsValue checks the value of the field fd in b, which is encoded
+// with wire type typ, and reports whether the value is unmarshaled into fd
+// rather than as an unknown field.
+func (v *validator) checkOptionsValue(fd protoreflect.FieldDescriptor, typ protowire.Type, b []byte, mode, depth int) (bool, error) {
+	if fd == nil {
+		return false, nil
+	}
+	wtyp := wireType(fd.Kind())
+	if fd.IsList() && typ == protowire.BytesType && wtyp != protowire.BytesType {
+		// Packed repeated scalar field.
+		b, _ := protowire.ConsumeBytes(b)
+		for len(b) > 0 {
+			n := protowire.ConsumeFieldValue(0, wtyp, b)
+			if n < 0 {
+				return false, protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+		return true, nil
+	}
+	if typ != wtyp {
+		return false, nil
+	}
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		x, _ := protowire.ConsumeVarint(b)
+		if ed := fd.Enum(); ed.IsClosed() && ed.Values().ByNumber(protoreflect.EnumNumber(int32(x))) == nil {
+			return false, nil
+		}
+	case protoreflect.StringKind:
+		s, _ := protowire.ConsumeBytes(b)
+		if strs.EnforceUTF8(fd) && !utf8.Valid(s) {
+			return false, errors.InvalidUTF8(string(fd.FullName()))
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		var msg []byte
+		if typ == protowire.BytesType {
+			msg, _ = protowire.ConsumeBytes(b)
+		} else {
+			msg, _ = protowire.ConsumeGroup(fd.Number(), b)
+		}
+		submode := optionsMode
+		switch {
+		case mode == optionsMode && depth == 0 && !fd.IsExtension() && fd.Message().FullName() == genid.FeatureSet_message_fullname:
+			v.hasFeatures = true
+			submode = featuresMode
+		case mode == featuresMode && fd.FullName() == gofeaturespb.E_Go.TypeDescriptor().FullName():
+			submode = goFeaturesMode
+		}
+		return true, v.checkOptionsMessage(msg, fd.Message(), submode, depth+1)
+	}
+	return true, nil
+}
+
+// wireType returns the wire type of a value of kind k.
+func wireType(k protoreflect.Kind) protowire.Type {
+	switch k {
+	case protoreflect.Fixed32Kind, protoreflect.Sfixed32Kind, protoreflect.FloatKind:
+		return protowire.Fixed32Type
+	case protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind, protoreflect.DoubleKind:
+		return protowire.Fixed64Type
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.MessageKind:
+		return protowire.BytesType
+	case protoreflect.GroupKind:
+		return protowire.StartGroupType
+	default:
+		return protowire.VarintType
+	}
+}
+
+// checkEnumDefaults checks that the default values of enum fields are values
+// of their enums, if the enums are declared in the file in b or in the files
+// that were loaded before it. Other enums are resolved lazily.
+func (v *validator) checkEnumDefaults(b []byte) error {
+	if len(v.enumDefaults) == 0 {
+		return nil
+	}
+	enums := make(map[string]map[string]bool)
+	collectEnums(enums, b, fileDescriptorProto, "")
+	for _, d := range v.enumDefaults {
+		if values, ok := enums[string(d.typeName)]; ok {
+			if !values[string(d.value)] {
+				return errors.New("field %s: invalid default value for enum: %q", fieldName(d.field), d.value)
+			}
+		} else if desc, err := v.files.FindDescriptorByName(protoreflect.FullName(d.typeName[1:])); err == nil {
+			if ed, ok := desc.(protoreflect.EnumDescriptor); ok && ed.Values().ByName(protoreflect.Name(d.value)) == nil {
+				return errors.New("field %s: invalid default value for enum: %q", fieldName(d.field), d.value)
+			}
+		}
+	}
+	return nil
+}
+
+// collectEnums adds the enums declared in the file or message in b to enums,
+// which maps the qualified name of each enum (e.g., ".pkg.Enum") to the names
+// of its values. The scope is the qualified name of the enclosing declaration.
+func collectEnums(enums map[string]map[string]bool, b []byte, md protoreflect.MessageDescriptor, scope string) {
+	isFile := md == fileDescriptorProto
+	r := fieldReader{md: md, b: b}
+	for r.next() {
+		if r.fd == nil || r.typ != protowire.BytesType {
+			continue
+		}
+		switch r.fd.Name() {
+		case "package":
+			scope = "." + string(r.raw)
+		case "name":
+			if !isFile {
+				scope += "." + string(r.raw)
+			}
+		case "message_type", "nested_type":
+			collectEnums(enums, r.raw, r.fd.Message(), scope)
+		case "enum_type":
+			values := make(map[string]bool)
+			er := fieldReader{md: r.fd.Message(), b: r.raw}
+			for er.next() {
+				if er.bytes(genid.EnumDescriptorProto_Value_field_number) {
+					values[string(fieldName(er.raw))] = true
+				}
+			}
+			enums[scope+"."+string(fieldName(r.raw))] = values
+		}
+	}
+}
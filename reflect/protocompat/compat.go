@@ -0,0 +1,350 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protocompat checks whether a new version of a set of protobuf
+// schemas is compatible with an old version.
+//
+// Declarations of the old schemas are matched by full name against the new
+// schemas, regardless of the file that declares them, and compared with
+// [protodesc.DiffMessages] and its siblings. Changes that break existing
+// clients of the wire, JSON, or text formats are reported as a [Violation],
+// for instance:
+//
+//   - removing a message, enum, extension, service, or method,
+//   - removing a field or enum value without reserving its number,
+//   - reusing a field number for a field of a different name and type,
+//     or using a number that was previously reserved,
+//   - changing the type or cardinality of a field, and
+//   - renaming a field or enum value, or changing its JSON name.
+//
+// Adding declarations and changing options are not violations.
+package protocompat
+
+import (
+	"fmt"
+	"sort"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Rule identifies the kind of incompatible change reported by a [Violation].
+type Rule int
+
+const (
+	MessageRemoved Rule = iota + 1
+	FieldRemoved
+	FieldNumberReused
+	FieldNameChanged
+	FieldTypeChanged
+	FieldJSONNameChanged
+	FieldOneofChanged
+	EnumRemoved
+	EnumValueRemoved
+	EnumValueNumberReused
+	EnumValueNameChanged
+	ExtensionRemoved
+	ServiceRemoved
+	MethodRemoved
+	MethodSignatureChanged
+)
+
+// String returns the name of the rule in upper snake case
+// (e.g., "FIELD_REMOVED").
+func (r Rule) String() string {
+	switch r {
+	case MessageRemoved:
+		return "MESSAGE_REMOVED"
+	case FieldRemoved:
+		return "FIELD_REMOVED"
+	case FieldNumberReused:
+		return "FIELD_NUMBER_REUSED"
+	case FieldNameChanged:
+		return "FIELD_NAME_CHANGED"
+	case FieldTypeChanged:
+		return "FIELD_TYPE_CHANGED"
+	case FieldJSONNameChanged:
+		return "FIELD_JSON_NAME_CHANGED"
+	case FieldOneofChanged:
+		return "FIELD_ONEOF_CHANGED"
+	case EnumRemoved:
+		return "ENUM_REMOVED"
+	case EnumValueRemoved:
+		return "ENUM_VALUE_REMOVED"
+	case EnumValueNumberReused:
+		return "ENUM_VALUE_NUMBER_REUSED"
+	case EnumValueNameChanged:
+		return "ENUM_VALUE_NAME_CHANGED"
+	case ExtensionRemoved:
+		return "EXTENSION_REMOVED"
+	case ServiceRemoved:
+		return "SERVICE_REMOVED"
+	case MethodRemoved:
+		return "METHOD_REMOVED"
+	case MethodSignatureChanged:
+		return "METHOD_SIGNATURE_CHANGED"
+	default:
+		return fmt.Sprintf("<unknown:%d>", r)
+	}
+}
+
+// Violation is an incompatible change between the old and new schemas.
+type Violation struct {
+	// Rule is the kind of incompatible change.
+	Rule Rule
+
+	// Name is the full name of the declaration that changed.
+	// For removed declarations, it is the name in the old schemas;
+	// otherwise it is the name in the new schemas.
+	Name protoreflect.FullName
+
+	// Old and New are the declarations in the old and new schemas.
+	// Old is nil for added declarations and New is nil for removed ones.
+	Old, New protoreflect.Descriptor
+
+	// Detail describes the change in a humanly readable manner.
+	Detail string
+}
+
+// String formats the violation in a humanly readable manner.
+// The output is not guaranteed to be stable.
+func (v Violation) String() string {
+	return fmt.Sprintf("%v: %v", v.Rule, v.Detail)
+}
+
+// CheckFileDescriptorSets reports the incompatible changes from the schemas
+// in the old set to the schemas in the new set, as described for [Check].
+// It reports an error if either set does not hold valid descriptors.
+func CheckFileDescriptorSets(old, new *descriptorpb.FileDescriptorSet) ([]Violation, error) {
+	oldFiles, err := protodesc.NewFiles(old)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid old file descriptor set")
+	}
+	newFiles, err := protodesc.NewFiles(new)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid new file descriptor set")
+	}
+	return Check(oldFiles, newFiles), nil
+}
+
+// Check reports the incompatible changes from the files in old to the files
+// in new. The violations are reported in a deterministic order: by path of
+// the old file, then in the order of declaration.
+func Check(old, new *protoregistry.Files) []Violation {
+	var files []protoreflect.FileDescriptor
+	old.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		files = append(files, fd)
+		return true
+	})
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path() < files[j].Path()
+	})
+
+	c := checker{old: old, new: new}
+	for _, fd := range files {
+		for i := 0; i < fd.Messages().Len(); i++ {
+			c.checkMessage(fd.Messages().Get(i))
+		}
+		for i := 0; i < fd.Enums().Len(); i++ {
+			c.checkEnum(fd.Enums().Get(i))
+		}
+		for i := 0; i < fd.Extensions().Len(); i++ {
+			c.checkExtension(fd.Extensions().Get(i))
+		}
+		for i := 0; i < fd.Services().Len(); i++ {
+			c.checkService(fd.Services().Get(i))
+		}
+	}
+	return c.violations
+}
+
+type checker struct {
+	old, new   *protoregistry.Files
+	violations []Violation
+}
+
+func (c *checker) report(r Rule, x, y protoreflect.Descriptor, f string, args ...any) {
+	v := Violation{Rule: r, Old: x, New: y, Detail: fmt.Sprintf(f, args...)}
+	if y != nil {
+		v.Name = y.FullName()
+	} else {
+		v.Name = x.FullName()
+	}
+	c.violations = append(c.violations, v)
+}
+
+func (c *checker) checkMessage(x protoreflect.MessageDescriptor) {
+	y, ok := findDescriptor(c.new, x.FullName()).(protoreflect.MessageDescriptor)
+	if !ok {
+		c.report(MessageRemoved, x, nil, "message %v removed", x.FullName())
+		return
+	}
+	c.checkChanges(protodesc.DiffMessages(x, y))
+}
+
+func (c *checker) checkEnum(x protoreflect.EnumDescriptor) {
+	y, ok := findDescriptor(c.new, x.FullName()).(protoreflect.EnumDescriptor)
+	if !ok {
+		c.report(EnumRemoved, x, nil, "enum %v removed", x.FullName())
+		return
+	}
+	c.checkChanges(protodesc.DiffEnums(x, y))
+}
+
+func (c *checker) checkExtension(x protoreflect.ExtensionDescriptor) {
+	y, ok := findDescriptor(c.new, x.FullName()).(protoreflect.ExtensionDescriptor)
+	if !ok || !y.IsExtension() {
+		c.report(ExtensionRemoved, x, nil, "extension %v removed", x.FullName())
+		return
+	}
+	c.checkChanges(protodesc.DiffFields(x, y))
+}
+
+func (c *checker) checkService(x protoreflect.ServiceDescriptor) {
+	y, ok := findDescriptor(c.new, x.FullName()).(protoreflect.ServiceDescriptor)
+	if !ok {
+		c.report(ServiceRemoved, x, nil, "service %v removed", x.FullName())
+		return
+	}
+	for i := 0; i < x.Methods().Len(); i++ {
+		mx := x.Methods().Get(i)
+		my := y.Methods().ByName(mx.Name())
+		if my == nil {
+			c.report(MethodRemoved, mx, nil, "method %v removed", mx.FullName())
+			continue
+		}
+		for _, p := range []struct {
+			name   string
+			ox, oy any
+		}{
+			{"input_type", mx.Input().FullName(), my.Input().FullName()},
+			{"output_type", mx.Output().FullName(), my.Output().FullName()},
+			{"client_streaming", mx.IsStreamingClient(), my.IsStreamingClient()},
+			{"server_streaming", mx.IsStreamingServer(), my.IsStreamingServer()},
+		} {
+			if vx, vy := fmt.Sprint(p.ox), fmt.Sprint(p.oy); vx != vy {
+				c.report(MethodSignatureChanged, mx, my, "method %v changed %v from %q to %q", my.FullName(), p.name, vx, vy)
+			}
+		}
+	}
+}
+
+// checkChanges reports the violations among the structural changes
+// of a declaration.
+func (c *checker) checkChanges(changes []protodesc.Change) {
+	for i := 0; i < len(changes); i++ {
+		ch := changes[i]
+		switch ch.Kind {
+		case protodesc.Removed:
+			c.checkRemoved(ch.Old)
+		case protodesc.Added:
+			c.checkAdded(ch.New)
+		case protodesc.Modified:
+			switch y := ch.New.(type) {
+			case protoreflect.FieldDescriptor:
+				// The modified properties of a field are consecutive.
+				// They are checked together so that a number reused
+				// by an unrelated field is reported only once.
+				j := i + 1
+				for j < len(changes) && changes[j].Kind == protodesc.Modified && changes[j].New == ch.New {
+					j++
+				}
+				c.checkModifiedField(changes[i:j])
+				i = j - 1
+			case protoreflect.EnumValueDescriptor:
+				if ch.Property == "name" {
+					c.report(EnumValueNameChanged, ch.Old, y, "enum value %v renamed from %v to %v", y.FullName(), ch.OldValue, ch.NewValue)
+				}
+			}
+		}
+	}
+}
+
+func (c *checker) checkRemoved(x protoreflect.Descriptor) {
+	switch x := x.(type) {
+	case protoreflect.MessageDescriptor:
+		c.report(MessageRemoved, x, nil, "message %v removed", x.FullName())
+	case protoreflect.EnumDescriptor:
+		c.report(EnumRemoved, x, nil, "enum %v removed", x.FullName())
+	case protoreflect.FieldDescriptor:
+		if x.IsExtension() {
+			c.report(ExtensionRemoved, x, nil, "extension %v removed", x.FullName())
+			return
+		}
+		if y, ok := findDescriptor(c.new, x.Parent().FullName()).(protoreflect.MessageDescriptor); ok && y.ReservedRanges().Has(x.Number()) {
+			return
+		}
+		c.report(FieldRemoved, x, nil, "field %v removed without reserving number %d", x.FullName(), x.Number())
+	case protoreflect.EnumValueDescriptor:
+		if y, ok := findDescriptor(c.new, x.Parent().FullName()).(protoreflect.EnumDescriptor); ok && y.ReservedRanges().Has(x.Number()) {
+			return
+		}
+		c.report(EnumValueRemoved, x, nil, "enum value %v removed without reserving number %d", x.FullName(), x.Number())
+	}
+}
+
+func (c *checker) checkAdded(y protoreflect.Descriptor) {
+	switch y := y.(type) {
+	case protoreflect.FieldDescriptor:
+		if y.IsExtension() {
+			return
+		}
+		if x, ok := findDescriptor(c.old, y.Parent().FullName()).(protoreflect.MessageDescriptor); ok && x.ReservedRanges().Has(y.Number()) {
+			c.report(FieldNumberReused, nil, y, "field %v uses number %d, which was reserved", y.FullName(), y.Number())
+		}
+	case protoreflect.EnumValueDescriptor:
+		if x, ok := findDescriptor(c.old, y.Parent().FullName()).(protoreflect.EnumDescriptor); ok && x.ReservedRanges().Has(y.Number()) {
+			c.report(EnumValueNumberReused, nil, y, "enum value %v uses number %d, which was reserved", y.FullName(), y.Number())
+		}
+	}
+}
+
+// checkModifiedField checks the changes to the properties of a field.
+func (c *checker) checkModifiedField(changes []protodesc.Change) {
+	x := changes[0].Old.(protoreflect.FieldDescriptor)
+	y := changes[0].New.(protoreflect.FieldDescriptor)
+	props := make(map[string]protodesc.Change)
+	for _, ch := range changes {
+		props[ch.Property] = ch
+	}
+	_, renamed := props["name"]
+	var retyped []protodesc.Change
+	for _, p := range []string{"cardinality", "kind", "type_name", "extendee"} {
+		if ch, ok := props[p]; ok {
+			retyped = append(retyped, ch)
+		}
+	}
+
+	switch {
+	case renamed && len(retyped) > 0:
+		c.report(FieldNumberReused, x, y, "number %d of field %v reused by field %v of a different type", y.Number(), x.FullName(), y.FullName())
+		return
+	case renamed:
+		c.report(FieldNameChanged, x, y, "field %v renamed from %v to %v", y.FullName(), x.Name(), y.Name())
+	}
+	for _, ch := range retyped {
+		c.report(FieldTypeChanged, x, y, "field %v changed %v from %q to %q", y.FullName(), ch.Property, ch.OldValue, ch.NewValue)
+	}
+	// Renaming a field already changes its default JSON name.
+	if ch, ok := props["json_name"]; ok && !renamed {
+		c.report(FieldJSONNameChanged, x, y, "field %v changed json_name from %q to %q", y.FullName(), ch.OldValue, ch.NewValue)
+	}
+	if ch, ok := props["oneof"]; ok {
+		c.report(FieldOneofChanged, x, y, "field %v moved from oneof %q to %q", y.FullName(), ch.OldValue, ch.NewValue)
+	}
+}
+
+// findDescriptor returns the descriptor of the given name in r,
+// or nil if there is none.
+func findDescriptor(r *protoregistry.Files, name protoreflect.FullName) protoreflect.Descriptor {
+	d, err := r.FindDescriptorByName(name)
+	if err != nil {
+		return nil
+	}
+	return d
+}
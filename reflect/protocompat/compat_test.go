@@ -0,0 +1,154 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocompat_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protocompat"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func mustParseSet(s string) *descriptorpb.FileDescriptorSet {
+	set := new(descriptorpb.FileDescriptorSet)
+	if err := prototext.Unmarshal([]byte(s), set); err != nil {
+		panic(err)
+	}
+	return set
+}
+
+func TestCheck(t *testing.T) {
+	oldSet := mustParseSet(`file: [{
+		syntax:  "proto3"
+		name:    "compat.proto"
+		package: "test.compat"
+		message_type: [{
+			name: "Message"
+			field: [
+				{name:"keep" number:1 label:LABEL_OPTIONAL type:TYPE_STRING json_name:"keep"},
+				{name:"retype" number:2 label:LABEL_OPTIONAL type:TYPE_INT32 json_name:"retype"},
+				{name:"remove" number:3 label:LABEL_OPTIONAL type:TYPE_BOOL json_name:"remove"},
+				{name:"rename" number:4 label:LABEL_OPTIONAL type:TYPE_BOOL json_name:"rename"},
+				{name:"reuse" number:5 label:LABEL_OPTIONAL type:TYPE_BOOL json_name:"reuse"},
+				{name:"json" number:6 label:LABEL_OPTIONAL type:TYPE_BOOL json_name:"json"},
+				{name:"reserve" number:7 label:LABEL_OPTIONAL type:TYPE_BOOL json_name:"reserve"}
+			]
+			nested_type: [{name:"Nested"}]
+			reserved_range: [{start:10 end:11}]
+		}, {
+			name: "Removed"
+		}]
+		enum_type: [{
+			name: "Enum"
+			value: [{name:"ZERO" number:0}, {name:"ONE" number:1}, {name:"TWO" number:2}, {name:"THREE" number:3}]
+			reserved_range: [{start:10 end:10}]
+		}]
+		service: [{
+			name: "Service"
+			method: [
+				{name:"Get" input_type:".test.compat.Message" output_type:".test.compat.Message"},
+				{name:"Remove" input_type:".test.compat.Message" output_type:".test.compat.Message"}
+			]
+		}]
+	}, {
+		syntax:  "proto2"
+		name:    "moved.proto"
+		package: "test.compat"
+		message_type: [{name:"Moved" extension_range:[{start:100 end:200}]}]
+		extension: [
+			{name:"ext" number:100 label:LABEL_OPTIONAL type:TYPE_INT32 extendee:".test.compat.Moved"},
+			{name:"removed_ext" number:101 label:LABEL_OPTIONAL type:TYPE_INT32 extendee:".test.compat.Moved"}
+		]
+	}]`)
+	newSet := mustParseSet(`file: [{
+		syntax:  "proto3"
+		name:    "compat.proto"
+		package: "test.compat"
+		message_type: [{
+			name: "Message"
+			field: [
+				{name:"keep" number:1 label:LABEL_OPTIONAL type:TYPE_STRING json_name:"keep"},
+				{name:"retype" number:2 label:LABEL_OPTIONAL type:TYPE_INT64 json_name:"retype"},
+				{name:"renamed" number:4 label:LABEL_OPTIONAL type:TYPE_BOOL json_name:"renamed"},
+				{name:"reused" number:5 label:LABEL_REPEATED type:TYPE_STRING json_name:"reused"},
+				{name:"json" number:6 label:LABEL_OPTIONAL type:TYPE_BOOL json_name:"JSON"},
+				{name:"added" number:8 label:LABEL_OPTIONAL type:TYPE_BOOL json_name:"added"},
+				{name:"unreserved" number:10 label:LABEL_OPTIONAL type:TYPE_BOOL json_name:"unreserved"}
+			]
+			reserved_range: [{start:7 end:8}]
+		}]
+		enum_type: [{
+			name: "Enum"
+			value: [{name:"ZERO" number:0}, {name:"UNO" number:1}, {name:"TEN" number:10}]
+			reserved_range: [{start:3 end:3}]
+		}]
+		service: [{
+			name: "Service"
+			method: [
+				{name:"Get" input_type:".test.compat.Message" output_type:".test.compat.Message" server_streaming:true}
+			]
+		}]
+	}, {
+		syntax:  "proto2"
+		name:    "other.proto"
+		package: "test.compat"
+		message_type: [{name:"Moved" extension_range:[{start:100 end:200}]}]
+		extension: [
+			{name:"ext" number:100 label:LABEL_OPTIONAL type:TYPE_INT64 extendee:".test.compat.Moved"}
+		]
+	}]`)
+
+	violations, err := protocompat.CheckFileDescriptorSets(oldSet, newSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.String())
+	}
+	want := []string{
+		`FIELD_TYPE_CHANGED: field test.compat.Message.retype changed kind from "int32" to "int64"`,
+		`FIELD_REMOVED: field test.compat.Message.remove removed without reserving number 3`,
+		`FIELD_NAME_CHANGED: field test.compat.Message.renamed renamed from rename to renamed`,
+		`FIELD_NUMBER_REUSED: number 5 of field test.compat.Message.reuse reused by field test.compat.Message.reused of a different type`,
+		`FIELD_JSON_NAME_CHANGED: field test.compat.Message.json changed json_name from "json" to "JSON"`,
+		`FIELD_NUMBER_REUSED: field test.compat.Message.unreserved uses number 10, which was reserved`,
+		`MESSAGE_REMOVED: message test.compat.Message.Nested removed`,
+		`MESSAGE_REMOVED: message test.compat.Removed removed`,
+		`ENUM_VALUE_NAME_CHANGED: enum value test.compat.UNO renamed from ONE to UNO`,
+		`ENUM_VALUE_REMOVED: enum value test.compat.TWO removed without reserving number 2`,
+		`ENUM_VALUE_NUMBER_REUSED: enum value test.compat.TEN uses number 10, which was reserved`,
+		`METHOD_SIGNATURE_CHANGED: method test.compat.Service.Get changed server_streaming from "false" to "true"`,
+		`METHOD_REMOVED: method test.compat.Service.Remove removed`,
+		`FIELD_TYPE_CHANGED: field test.compat.ext changed kind from "int32" to "int64"`,
+		`EXTENSION_REMOVED: extension test.compat.removed_ext removed`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CheckFileDescriptorSets mismatch (-want +got):\n%s", diff)
+	}
+
+	violations, err = protocompat.CheckFileDescriptorSets(oldSet, oldSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) > 0 {
+		t.Errorf("CheckFileDescriptorSets of identical sets reported violations: %v", violations)
+	}
+}
+
+func TestCheckInvalid(t *testing.T) {
+	valid := mustParseSet(`file: [{syntax:"proto3" name:"a.proto" package:"test"}]`)
+	invalid := mustParseSet(`file: [{syntax:"proto3" name:"a.proto" package:"test" dependency:"missing.proto"}]`)
+	if _, err := protocompat.CheckFileDescriptorSets(invalid, valid); err == nil {
+		t.Errorf("CheckFileDescriptorSets with invalid old set succeeded, want error")
+	}
+	if _, err := protocompat.CheckFileDescriptorSets(valid, invalid); err == nil {
+		t.Errorf("CheckFileDescriptorSets with invalid new set succeeded, want error")
+	}
+}
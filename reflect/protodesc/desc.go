@@ -60,6 +60,13 @@ type FileOptions struct {
 	// then the placeholder will contain an invalid FullName with a "*." prefix,
 	// indicating that the starting prefix of the full name is unknown.
 	AllowUnresolvable bool
+
+	// RetainSourceCodeInfo configures New to keep a verbatim copy of the
+	// google.protobuf.SourceCodeInfo message in addition to the parsed
+	// protoreflect.SourceLocations. When the resulting descriptor is converted
+	// back with [ToFileDescriptorProto], the retained message is emitted as is,
+	// preserving unknown fields and the exact span encoding of every location.
+	RetainSourceCodeInfo bool
 }
 
 // NewFile creates a new [protoreflect.FileDescriptor] from the provided
@@ -179,6 +186,18 @@ func (o FileOptions) New(fd *descriptorpb.FileDescriptorProto, r Resolver) (prot
 		l.TrailingComments = loc.GetTrailingComments()
 		f.L2.Locations.List = append(f.L2.Locations.List, l)
 	}
+	if o.RetainSourceCodeInfo && fd.SourceCodeInfo != nil {
+		b, err := proto.MarshalOptions{AllowPartial: true, Deterministic: true}.Marshal(fd.GetSourceCodeInfo())
+		if err != nil {
+			return nil, err
+		}
+		// Check that the retained message can be decoded again, since
+		// ToFileDescriptorProto has no way to report an error.
+		if _, err := unmarshalSourceCodeInfo(b); err != nil {
+			return nil, errors.New("invalid source code info: %v", err)
+		}
+		f.L2.Locations.Raw = b
+	}
 
 	// Step 1: Allocate and derive the names for all declarations.
 	// This copies all fields from the descriptor proto except:
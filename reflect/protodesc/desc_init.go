@@ -209,6 +209,10 @@ func (r descsByName) initExtensionDeclarations(xds []*descriptorpb.FieldDescript
 		}
 		x.L1.Number = protoreflect.FieldNumber(xd.GetNumber())
 		x.L1.Cardinality = protoreflect.Cardinality(xd.GetLabel())
+		// protoc sets proto3_optional for extensions declared with the
+		// optional keyword in proto3 files. Keep it so that HasOptionalKeyword
+		// reports it and ToFieldDescriptorProto preserves it.
+		x.L2.IsProto3Optional = xd.GetProto3Optional()
 		if xd.Type != nil {
 			x.L1.Kind = protoreflect.Kind(xd.GetType())
 		}
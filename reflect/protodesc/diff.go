@@ -0,0 +1,409 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protodesc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ChangeKind classifies a [Change].
+type ChangeKind int
+
+const (
+	// Added indicates that a declaration only exists in the new descriptor.
+	Added ChangeKind = iota + 1
+	// Removed indicates that a declaration only exists in the old descriptor.
+	Removed
+	// Modified indicates that a property of a declaration differs
+	// between the old and new descriptors.
+	Modified
+)
+
+// String returns the lowercase name of the change kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	default:
+		return fmt.Sprintf("<unknown:%d>", k)
+	}
+}
+
+// Change is a single structural difference between two descriptors
+// as reported by [DiffMessages].
+type Change struct {
+	// Kind is the kind of change.
+	Kind ChangeKind
+
+	// Name is the full name of the declaration that changed.
+	// For removed declarations, it is the name in the old descriptor;
+	// otherwise it is the name in the new descriptor.
+	Name protoreflect.FullName
+
+	// Old and New are the declarations in the old and new descriptors.
+	// Old is nil for added declarations and New is nil for removed ones.
+	Old, New protoreflect.Descriptor
+
+	// Property names the property that differs for modified declarations
+	// (e.g., "kind", "cardinality", "json_name", or "options").
+	// It is empty for added and removed declarations.
+	Property string
+
+	// OldValue and NewValue are textual representations of the property
+	// before and after the change. They are empty for added and
+	// removed declarations.
+	OldValue, NewValue string
+}
+
+// String formats the change in a humanly readable manner.
+// The output is not guaranteed to be stable.
+func (c Change) String() string {
+	d := c.New
+	if d == nil {
+		d = c.Old
+	}
+	s := fmt.Sprintf("%v %v %v", descKindOf(d), c.Name, c.Kind)
+	if c.Kind == Modified {
+		s += fmt.Sprintf(": %v changed from %q to %q", c.Property, c.OldValue, c.NewValue)
+	}
+	return s
+}
+
+// DiffMessages structurally compares the old message descriptor x against
+// the new message descriptor y and reports every difference found.
+// Fields and enum values are matched by number, while nested messages,
+// enums, and oneofs are matched by name. Nested declarations are compared
+// recursively. The names of x and y themselves are not compared.
+//
+// The changes are reported in a deterministic order.
+func DiffMessages(x, y protoreflect.MessageDescriptor) []Change {
+	var d differ
+	d.diffMessage(x, y)
+	return d.changes
+}
+
+// DiffEnums structurally compares the old enum descriptor x against
+// the new enum descriptor y and reports every difference found,
+// as described for [DiffMessages]. Enum values are matched by number.
+func DiffEnums(x, y protoreflect.EnumDescriptor) []Change {
+	var d differ
+	d.diffEnum(x, y)
+	return d.changes
+}
+
+// DiffFields structurally compares the old field descriptor x against
+// the new field descriptor y and reports every difference found,
+// as described for [DiffMessages]. It is typically used for extensions
+// declared at the top level of a file.
+func DiffFields(x, y protoreflect.FieldDescriptor) []Change {
+	var d differ
+	d.diffField(x, y)
+	return d.changes
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) added(y protoreflect.Descriptor) {
+	d.changes = append(d.changes, Change{Kind: Added, Name: y.FullName(), New: y})
+}
+
+func (d *differ) removed(x protoreflect.Descriptor) {
+	d.changes = append(d.changes, Change{Kind: Removed, Name: x.FullName(), Old: x})
+}
+
+func (d *differ) modified(x, y protoreflect.Descriptor, prop string, ox, oy any) {
+	vx, vy := fmt.Sprint(ox), fmt.Sprint(oy)
+	if vx == vy {
+		return
+	}
+	d.changes = append(d.changes, Change{
+		Kind:     Modified,
+		Name:     y.FullName(),
+		Old:      x,
+		New:      y,
+		Property: prop,
+		OldValue: vx,
+		NewValue: vy,
+	})
+}
+
+func (d *differ) options(x, y protoreflect.Descriptor) {
+	ox, oy := x.Options(), y.Options()
+	if !proto.Equal(ox, oy) {
+		d.changes = append(d.changes, Change{
+			Kind:     Modified,
+			Name:     y.FullName(),
+			Old:      x,
+			New:      y,
+			Property: "options",
+			OldValue: formatOptions(ox),
+			NewValue: formatOptions(oy),
+		})
+	}
+}
+
+func (d *differ) diffMessage(x, y protoreflect.MessageDescriptor) {
+	d.options(x, y)
+	d.modified(x, y, "reserved_ranges", formatRanges(x.ReservedRanges()), formatRanges(y.ReservedRanges()))
+	d.modified(x, y, "reserved_names", formatNames(x.ReservedNames()), formatNames(y.ReservedNames()))
+	d.modified(x, y, "extension_ranges", formatRanges(x.ExtensionRanges()), formatRanges(y.ExtensionRanges()))
+
+	xfs, yfs := x.Fields(), y.Fields()
+	for _, num := range unionNumbers(xfs, yfs) {
+		fx, fy := xfs.ByNumber(num), yfs.ByNumber(num)
+		switch {
+		case fx == nil:
+			d.added(fy)
+		case fy == nil:
+			d.removed(fx)
+		default:
+			d.diffField(fx, fy)
+		}
+	}
+
+	xos, yos := x.Oneofs(), y.Oneofs()
+	names := unionNames(
+		xos.Len(), func(i int) protoreflect.Descriptor { return xos.Get(i) },
+		yos.Len(), func(i int) protoreflect.Descriptor { return yos.Get(i) })
+	for _, name := range names {
+		ox, oy := xos.ByName(name), yos.ByName(name)
+		switch {
+		case ox == nil:
+			d.added(oy)
+		case oy == nil:
+			d.removed(ox)
+		default:
+			d.options(ox, oy)
+		}
+	}
+
+	xms, yms := x.Messages(), y.Messages()
+	names = unionNames(
+		xms.Len(), func(i int) protoreflect.Descriptor { return xms.Get(i) },
+		yms.Len(), func(i int) protoreflect.Descriptor { return yms.Get(i) })
+	for _, name := range names {
+		mx, my := xms.ByName(name), yms.ByName(name)
+		switch {
+		case mx == nil:
+			d.added(my)
+		case my == nil:
+			d.removed(mx)
+		default:
+			d.diffMessage(mx, my)
+		}
+	}
+
+	xes, yes := x.Enums(), y.Enums()
+	names = unionNames(
+		xes.Len(), func(i int) protoreflect.Descriptor { return xes.Get(i) },
+		yes.Len(), func(i int) protoreflect.Descriptor { return yes.Get(i) })
+	for _, name := range names {
+		ex, ey := xes.ByName(name), yes.ByName(name)
+		switch {
+		case ex == nil:
+			d.added(ey)
+		case ey == nil:
+			d.removed(ex)
+		default:
+			d.diffEnum(ex, ey)
+		}
+	}
+
+	xxs, yxs := x.Extensions(), y.Extensions()
+	names = unionNames(
+		xxs.Len(), func(i int) protoreflect.Descriptor { return xxs.Get(i) },
+		yxs.Len(), func(i int) protoreflect.Descriptor { return yxs.Get(i) })
+	for _, name := range names {
+		fx, fy := xxs.ByName(name), yxs.ByName(name)
+		switch {
+		case fx == nil:
+			d.added(fy)
+		case fy == nil:
+			d.removed(fx)
+		default:
+			d.diffField(fx, fy)
+		}
+	}
+}
+
+func (d *differ) diffField(x, y protoreflect.FieldDescriptor) {
+	d.modified(x, y, "name", x.Name(), y.Name())
+	d.modified(x, y, "cardinality", x.Cardinality(), y.Cardinality())
+	d.modified(x, y, "kind", x.Kind(), y.Kind())
+	d.modified(x, y, "type_name", typeNameOf(x), typeNameOf(y))
+	d.modified(x, y, "json_name", x.JSONName(), y.JSONName())
+	d.modified(x, y, "has_presence", x.HasPresence(), y.HasPresence())
+	d.modified(x, y, "packed", x.IsPacked(), y.IsPacked())
+	d.modified(x, y, "oneof", oneofNameOf(x), oneofNameOf(y))
+	d.modified(x, y, "default", defaultOf(x), defaultOf(y))
+	if x.IsExtension() && y.IsExtension() {
+		d.modified(x, y, "extendee", x.ContainingMessage().FullName(), y.ContainingMessage().FullName())
+	}
+	d.options(x, y)
+}
+
+func (d *differ) diffEnum(x, y protoreflect.EnumDescriptor) {
+	d.options(x, y)
+	d.modified(x, y, "closed", x.IsClosed(), y.IsClosed())
+	d.modified(x, y, "reserved_ranges", formatEnumRanges(x.ReservedRanges()), formatEnumRanges(y.ReservedRanges()))
+	d.modified(x, y, "reserved_names", formatNames(x.ReservedNames()), formatNames(y.ReservedNames()))
+
+	xvs, yvs := x.Values(), y.Values()
+	seen := make(map[protoreflect.EnumNumber]bool)
+	var nums []protoreflect.EnumNumber
+	for _, vs := range []protoreflect.EnumValueDescriptors{xvs, yvs} {
+		for i := 0; i < vs.Len(); i++ {
+			if n := vs.Get(i).Number(); !seen[n] {
+				seen[n] = true
+				nums = append(nums, n)
+			}
+		}
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	for _, num := range nums {
+		vx, vy := xvs.ByNumber(num), yvs.ByNumber(num)
+		switch {
+		case vx == nil:
+			d.added(vy)
+		case vy == nil:
+			d.removed(vx)
+		default:
+			d.modified(vx, vy, "name", vx.Name(), vy.Name())
+			d.options(vx, vy)
+		}
+	}
+}
+
+// descKindOf returns a short, lowercase name for the kind of descriptor.
+func descKindOf(d protoreflect.Descriptor) string {
+	switch d := d.(type) {
+	case protoreflect.MessageDescriptor:
+		return "message"
+	case protoreflect.FieldDescriptor:
+		if d.IsExtension() {
+			return "extension"
+		}
+		return "field"
+	case protoreflect.OneofDescriptor:
+		return "oneof"
+	case protoreflect.EnumDescriptor:
+		return "enum"
+	case protoreflect.EnumValueDescriptor:
+		return "enum value"
+	default:
+		return "descriptor"
+	}
+}
+
+func typeNameOf(fd protoreflect.FieldDescriptor) protoreflect.FullName {
+	switch {
+	case fd.Message() != nil:
+		return fd.Message().FullName()
+	case fd.Enum() != nil:
+		return fd.Enum().FullName()
+	default:
+		return ""
+	}
+}
+
+func oneofNameOf(fd protoreflect.FieldDescriptor) protoreflect.Name {
+	if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+		return od.Name()
+	}
+	return ""
+}
+
+func defaultOf(fd protoreflect.FieldDescriptor) string {
+	if !fd.HasDefault() {
+		return ""
+	}
+	if ev := fd.DefaultEnumValue(); ev != nil {
+		return string(ev.Name())
+	}
+	return fmt.Sprint(fd.Default().Interface())
+}
+
+func formatOptions(m proto.Message) string {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return ""
+	}
+	b, _ := prototext.MarshalOptions{Stable: true, AllowPartial: true, EmitUnknown: true}.Marshal(m)
+	return string(b)
+}
+
+func formatRanges(rs protoreflect.FieldRanges) string {
+	var ss []string
+	for i := 0; i < rs.Len(); i++ {
+		r := rs.Get(i)
+		ss = append(ss, fmt.Sprintf("%d-%d", r[0], r[1]-1))
+	}
+	return strings.Join(ss, ",")
+}
+
+func formatEnumRanges(rs protoreflect.EnumRanges) string {
+	var ss []string
+	for i := 0; i < rs.Len(); i++ {
+		r := rs.Get(i)
+		ss = append(ss, fmt.Sprintf("%d-%d", r[0], r[1]))
+	}
+	return strings.Join(ss, ",")
+}
+
+func formatNames(ns protoreflect.Names) string {
+	var ss []string
+	for i := 0; i < ns.Len(); i++ {
+		ss = append(ss, string(ns.Get(i)))
+	}
+	sort.Strings(ss)
+	return strings.Join(ss, ",")
+}
+
+// unionNumbers returns the sorted union of field numbers in x and y.
+func unionNumbers(x, y protoreflect.FieldDescriptors) []protoreflect.FieldNumber {
+	seen := make(map[protoreflect.FieldNumber]bool)
+	var nums []protoreflect.FieldNumber
+	for _, fs := range []protoreflect.FieldDescriptors{x, y} {
+		for i := 0; i < fs.Len(); i++ {
+			if n := fs.Get(i).Number(); !seen[n] {
+				seen[n] = true
+				nums = append(nums, n)
+			}
+		}
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	return nums
+}
+
+// unionNames returns the names of the nx declarations of x followed by
+// the names of the ny declarations only in y, each in declaration order.
+func unionNames(nx int, x func(int) protoreflect.Descriptor, ny int, y func(int) protoreflect.Descriptor) []protoreflect.Name {
+	seen := make(map[protoreflect.Name]bool)
+	var names []protoreflect.Name
+	for i := 0; i < nx+ny; i++ {
+		var n protoreflect.Name
+		if i < nx {
+			n = x(i).Name()
+		} else {
+			n = y(i - nx).Name()
+		}
+		if !seen[n] {
+			seen[n] = true
+			names = append(names, n)
+		}
+	}
+	return names
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protodesc
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestDiffMessages(t *testing.T) {
+	oldFile, err := NewFile(mustParseFile(`
+		syntax:  "proto3"
+		name:    "diff.proto"
+		package: "test.diff"
+		message_type: [{
+			name: "Message"
+			field: [
+				{name:"keep" number:1 label:LABEL_OPTIONAL type:TYPE_STRING},
+				{name:"retype" number:2 label:LABEL_OPTIONAL type:TYPE_INT32},
+				{name:"remove" number:3 label:LABEL_OPTIONAL type:TYPE_BOOL},
+				{name:"rename" number:4 label:LABEL_OPTIONAL type:TYPE_BOOL}
+			]
+			nested_type: [{name:"Nested" field:[{name:"a" number:1 label:LABEL_OPTIONAL type:TYPE_INT32}]}]
+			enum_type: [{name:"Enum" value:[{name:"ZERO" number:0}, {name:"ONE" number:1}]}]
+		}]
+	`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newFile, err := NewFile(mustParseFile(`
+		syntax:  "proto3"
+		name:    "diff.proto"
+		package: "test.diff"
+		message_type: [{
+			name: "Message"
+			field: [
+				{name:"keep" number:1 label:LABEL_OPTIONAL type:TYPE_STRING},
+				{name:"retype" number:2 label:LABEL_OPTIONAL type:TYPE_INT64 options:{deprecated:true}},
+				{name:"renamed" number:4 label:LABEL_OPTIONAL type:TYPE_BOOL json_name:"renamed"},
+				{name:"add" number:5 label:LABEL_REPEATED type:TYPE_STRING}
+			]
+			nested_type: [{name:"Nested" field:[{name:"a" number:1 label:LABEL_REPEATED type:TYPE_INT32}]}]
+			enum_type: [{name:"Enum" value:[{name:"ZERO" number:0}, {name:"TWO" number:2}]}]
+			reserved_range: [{start:3 end:4}]
+		}]
+	`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range DiffMessages(oldFile.Messages().Get(0), newFile.Messages().Get(0)) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`message test.diff.Message modified: reserved_ranges changed from "" to "3-3"`,
+		`field test.diff.Message.retype modified: kind changed from "int32" to "int64"`,
+		`field test.diff.Message.retype modified: options changed from "" to "deprecated:true"`,
+		`field test.diff.Message.remove removed`,
+		`field test.diff.Message.renamed modified: name changed from "rename" to "renamed"`,
+		`field test.diff.Message.renamed modified: json_name changed from "rename" to "renamed"`,
+		`field test.diff.Message.add added`,
+		`field test.diff.Message.Nested.a modified: cardinality changed from "optional" to "repeated"`,
+		`field test.diff.Message.Nested.a modified: packed changed from "false" to "true"`,
+		`enum value test.diff.Message.ONE removed`,
+		`enum value test.diff.Message.TWO added`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DiffMessages mismatch (-want +got):\n%s", diff)
+	}
+
+	if changes := DiffMessages(oldFile.Messages().Get(0), oldFile.Messages().Get(0)); len(changes) > 0 {
+		t.Errorf("DiffMessages of identical messages reported changes: %v", changes)
+	}
+}
+
+func TestDiffEnumsAndFields(t *testing.T) {
+	oldFile, err := NewFile(mustParseFile(`
+		syntax:  "proto2"
+		name:    "diff.proto"
+		package: "test.diff"
+		message_type: [{name:"Message" extension_range:[{start:10 end:20}]}]
+		enum_type: [{name:"Enum" value:[{name:"ZERO" number:0}, {name:"ONE" number:1}]}]
+		extension: [{name:"ext" number:10 label:LABEL_OPTIONAL type:TYPE_INT32 extendee:".test.diff.Message"}]
+	`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newFile, err := NewFile(mustParseFile(`
+		syntax:  "proto2"
+		name:    "diff.proto"
+		package: "test.diff"
+		message_type: [{name:"Message" extension_range:[{start:10 end:20}]}]
+		enum_type: [{name:"Enum" value:[{name:"ZERO" number:0}, {name:"UNO" number:1}]}]
+		extension: [{name:"ext" number:10 label:LABEL_REPEATED type:TYPE_INT32 extendee:".test.diff.Message"}]
+	`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range DiffEnums(oldFile.Enums().Get(0), newFile.Enums().Get(0)) {
+		got = append(got, c.String())
+	}
+	for _, c := range DiffFields(oldFile.Extensions().Get(0), newFile.Extensions().Get(0)) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`enum value test.diff.UNO modified: name changed from "ONE" to "UNO"`,
+		`extension test.diff.ext modified: cardinality changed from "optional" to "repeated"`,
+		`extension test.diff.ext modified: has_presence changed from "true" to "false"`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DiffEnums and DiffFields mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiffOptionsWhitespace(t *testing.T) {
+	parseFile := func(value string) protoreflect.FileDescriptor {
+		fd, err := NewFile(mustParseFile(`
+			syntax:  "proto2"
+			name:    "diff.proto"
+			package: "test.diff"
+			message_type: [{
+				name: "Message"
+				options: {uninterpreted_option: [{
+					name: [{name_part:"opt" is_extension:false}]
+					aggregate_value: "`+value+`"
+				}]}
+			}]
+		`), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fd
+	}
+	oldFile, newFile := parseFile("a b"), parseFile("a  b")
+
+	var got []string
+	for _, c := range DiffMessages(oldFile.Messages().Get(0), newFile.Messages().Get(0)) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`message test.diff.Message modified: options changed from ` +
+			`"uninterpreted_option:{name:{name_part:\"opt\" is_extension:false} aggregate_value:\"a b\"}" to ` +
+			`"uninterpreted_option:{name:{name_part:\"opt\" is_extension:false} aggregate_value:\"a  b\"}"`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DiffMessages mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"sync"
 
 	"google.golang.org/protobuf/internal/editiondefaults"
+	"google.golang.org/protobuf/internal/errors"
 	"google.golang.org/protobuf/internal/filedesc"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -143,3 +144,168 @@ func initFileDescFromFeatureSet(fd *filedesc.File, fs *descriptorpb.FeatureSet)
 	// overwrite any options explicitly specified
 	fd.L1.EditionFeatures = mergeEditionFeatures(fd, fs)
 }
+
+// ResolvedFeatures is the editions feature set in effect for a descriptor,
+// together with the descriptors that supplied each feature value.
+// See [ResolveFeatures].
+type ResolvedFeatures struct {
+	// Features is the fully resolved feature set, starting from the defaults
+	// of the file's edition and applying the features of every ancestor
+	// down to and including the descriptor itself.
+	Features *descriptorpb.FeatureSet
+
+	// Sources maps the full name of a feature field to the descriptor whose
+	// options most recently set it. Fields of feature extensions
+	// (e.g., "pb.GoFeatures.legacy_unmarshal_json_enum") are keyed by
+	// their own full name. Features absent from the map hold the default
+	// value for the file's edition.
+	Sources map[protoreflect.FullName]protoreflect.Descriptor
+}
+
+// Source reports the descriptor that supplied the resolved value of the
+// named feature field. It returns nil if the value is the edition default.
+func (r *ResolvedFeatures) Source(name protoreflect.FullName) protoreflect.Descriptor {
+	return r.Sources[name]
+}
+
+// ResolveFeatures resolves the editions features in effect for d by walking
+// from its parent file down to d, applying the features declared in the
+// options of each descriptor along the way. Feature inheritance follows the
+// same rules used by [NewFile] to construct descriptors; in particular,
+// a field inherits from its containing message rather than from its oneof,
+// and the legacy packed field option overrides repeated_field_encoding.
+//
+// Files using the proto2 or proto3 syntax resolve to the defaults of the
+// corresponding legacy edition. The features implied by the syntax of
+// a field are attributed to the field: a required field has LEGACY_REQUIRED
+// presence, a proto3 optional field has EXPLICIT presence, and a group
+// has DELIMITED message encoding.
+func ResolveFeatures(d protoreflect.Descriptor) (*ResolvedFeatures, error) {
+	ed, err := editionOf(d.ParentFile())
+	if err != nil {
+		return nil, err
+	}
+	edpb := toEditionProto(ed)
+	if defaults.GetMinimumEdition() > edpb || defaults.GetMaximumEdition() < edpb {
+		return nil, errors.New("unsupported edition %v", edpb)
+	}
+
+	var chain []protoreflect.Descriptor
+	for p := d; p != nil; p = p.Parent() {
+		chain = append(chain, p)
+	}
+
+	r := &ResolvedFeatures{
+		Features: proto.Clone(getFeatureSetFor(ed)).(*descriptorpb.FeatureSet),
+		Sources:  make(map[protoreflect.FullName]protoreflect.Descriptor),
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		desc := chain[i]
+		fs, err := featuresOf(desc.Options())
+		if err != nil {
+			return nil, errors.Wrap(err, "%v", desc.FullName())
+		}
+		if fs != nil {
+			recordFeatureSources(r.Sources, fs.ProtoReflect(), desc)
+			proto.Merge(r.Features, fs)
+		}
+		if fd, ok := desc.(protoreflect.FieldDescriptor); ok {
+			if opts, ok := fd.Options().(*descriptorpb.FieldOptions); ok && opts != nil && opts.Packed != nil {
+				rfe := descriptorpb.FeatureSet_EXPANDED
+				if opts.GetPacked() {
+					rfe = descriptorpb.FeatureSet_PACKED
+				}
+				r.Features.RepeatedFieldEncoding = rfe.Enum()
+				r.setSource("repeated_field_encoding", desc)
+			}
+			if fd.Syntax() != protoreflect.Editions {
+				r.inferLegacyFeatures(fd)
+			}
+		}
+	}
+	return r, nil
+}
+
+// inferLegacyFeatures sets the features implied by the label, type, and
+// optional keyword of a field in a file using the proto2 or proto3 syntax,
+// as done when the field is built by [NewFile] or internal/filedesc.
+func (r *ResolvedFeatures) inferLegacyFeatures(fd protoreflect.FieldDescriptor) {
+	switch {
+	case fd.Cardinality() == protoreflect.Required:
+		r.Features.FieldPresence = descriptorpb.FeatureSet_LEGACY_REQUIRED.Enum()
+		r.setSource("field_presence", fd)
+	case fd.Syntax() == protoreflect.Proto3 && fd.HasOptionalKeyword():
+		r.Features.FieldPresence = descriptorpb.FeatureSet_EXPLICIT.Enum()
+		r.setSource("field_presence", fd)
+	}
+	if fd.Kind() == protoreflect.GroupKind {
+		r.Features.MessageEncoding = descriptorpb.FeatureSet_DELIMITED.Enum()
+		r.setSource("message_encoding", fd)
+	}
+}
+
+// setSource attributes the feature field with the given name to desc.
+func (r *ResolvedFeatures) setSource(name protoreflect.Name, desc protoreflect.Descriptor) {
+	r.Sources[r.Features.ProtoReflect().Descriptor().Fields().ByName(name).FullName()] = desc
+}
+
+// editionOf reports the edition of the file, mapping the proto2 and proto3
+// syntaxes to their corresponding legacy editions.
+func editionOf(fd protoreflect.FileDescriptor) (filedesc.Edition, error) {
+	if fd == nil {
+		return filedesc.EditionUnknown, errors.New("descriptor has no parent file")
+	}
+	switch fd.Syntax() {
+	case protoreflect.Proto2:
+		return filedesc.EditionProto2, nil
+	case protoreflect.Proto3:
+		return filedesc.EditionProto3, nil
+	case protoreflect.Editions:
+		if fi, ok := fd.(protoreflect.FileImport); ok {
+			fd = fi.FileDescriptor
+		}
+		if ed, ok := fd.(interface{ Edition() int32 }); ok {
+			return filedesc.Edition(ed.Edition()), nil
+		}
+		return filedesc.EditionUnknown, errors.New("%v: unable to determine edition", fd.Path())
+	default:
+		return filedesc.EditionUnknown, errors.New("%v: invalid syntax %v", fd.Path(), fd.Syntax())
+	}
+}
+
+// featuresOf returns the feature set declared in the options message opts,
+// or nil if there is none. The feature set is round-tripped through the
+// wire format so that feature extensions linked into the binary are
+// recognized even if opts holds them as unknown fields.
+func featuresOf(opts protoreflect.ProtoMessage) (*descriptorpb.FeatureSet, error) {
+	if opts == nil {
+		return nil, nil
+	}
+	m := opts.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName("features")
+	if fd == nil || fd.Message() == nil || !m.Has(fd) {
+		return nil, nil
+	}
+	b, err := proto.MarshalOptions{AllowPartial: true}.Marshal(m.Get(fd).Message().Interface())
+	if err != nil {
+		return nil, err
+	}
+	fs := new(descriptorpb.FeatureSet)
+	if err := (proto.UnmarshalOptions{AllowPartial: true}).Unmarshal(b, fs); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// recordFeatureSources attributes every populated field of the feature set m
+// to desc, descending into message-valued feature extensions.
+func recordFeatureSources(sources map[protoreflect.FullName]protoreflect.Descriptor, m protoreflect.Message, desc protoreflect.Descriptor) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+			recordFeatureSources(sources, v.Message(), desc)
+			return true
+		}
+		sources[fd.FullName()] = desc
+		return true
+	})
+}
@@ -0,0 +1,226 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protodesc
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/gofeaturespb"
+)
+
+func TestResolveFeatures(t *testing.T) {
+	fd, err := NewFile(mustParseFile(`
+		syntax:  "editions"
+		edition: EDITION_2023
+		name:    "resolve_features.proto"
+		package: "test.features"
+		options: {features: {field_presence: IMPLICIT}}
+		message_type: [{
+			name:    "Message"
+			options: {features: {utf8_validation: NONE}}
+			field: [
+				{name:"foo" number:1 type:TYPE_STRING},
+				{name:"bar" number:2 type:TYPE_STRING options:{features:{field_presence: EXPLICIT}}},
+				{name:"baz" number:3 label:LABEL_REPEATED type:TYPE_INT32 options:{packed:false}}
+			]
+		}]
+	`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := fd.Messages().Get(0)
+	const (
+		presence = protoreflect.FullName("google.protobuf.FeatureSet.field_presence")
+		utf8     = protoreflect.FullName("google.protobuf.FeatureSet.utf8_validation")
+		encoding = protoreflect.FullName("google.protobuf.FeatureSet.repeated_field_encoding")
+		enumType = protoreflect.FullName("google.protobuf.FeatureSet.enum_type")
+	)
+
+	tests := []struct {
+		desc         protoreflect.Descriptor
+		wantPresence descriptorpb.FeatureSet_FieldPresence
+		wantSources  map[protoreflect.FullName]protoreflect.Descriptor
+	}{{
+		desc:         fd,
+		wantPresence: descriptorpb.FeatureSet_IMPLICIT,
+		wantSources:  map[protoreflect.FullName]protoreflect.Descriptor{presence: fd, utf8: nil, enumType: nil},
+	}, {
+		desc:         md.Fields().ByName("foo"),
+		wantPresence: descriptorpb.FeatureSet_IMPLICIT,
+		wantSources:  map[protoreflect.FullName]protoreflect.Descriptor{presence: fd, utf8: md, enumType: nil},
+	}, {
+		desc:         md.Fields().ByName("bar"),
+		wantPresence: descriptorpb.FeatureSet_EXPLICIT,
+		wantSources:  map[protoreflect.FullName]protoreflect.Descriptor{presence: md.Fields().ByName("bar"), utf8: md},
+	}, {
+		desc:         md.Fields().ByName("baz"),
+		wantPresence: descriptorpb.FeatureSet_IMPLICIT,
+		wantSources:  map[protoreflect.FullName]protoreflect.Descriptor{encoding: md.Fields().ByName("baz")},
+	}}
+	for _, tt := range tests {
+		got, err := ResolveFeatures(tt.desc)
+		if err != nil {
+			t.Errorf("ResolveFeatures(%v) error: %v", tt.desc.FullName(), err)
+			continue
+		}
+		if p := got.Features.GetFieldPresence(); p != tt.wantPresence {
+			t.Errorf("ResolveFeatures(%v).Features.FieldPresence = %v, want %v", tt.desc.FullName(), p, tt.wantPresence)
+		}
+		for name, want := range tt.wantSources {
+			if src := got.Source(name); src != want {
+				t.Errorf("ResolveFeatures(%v).Source(%v) = %v, want %v", tt.desc.FullName(), name, src, want)
+			}
+		}
+	}
+
+	baz, _ := ResolveFeatures(md.Fields().ByName("baz"))
+	if got := baz.Features.GetRepeatedFieldEncoding(); got != descriptorpb.FeatureSet_EXPANDED {
+		t.Errorf("ResolveFeatures(baz).Features.RepeatedFieldEncoding = %v, want EXPANDED", got)
+	}
+}
+
+func TestResolveFeaturesLegacySyntax(t *testing.T) {
+	proto2, err := NewFile(mustParseFile(`
+		syntax:  "proto2"
+		name:    "legacy_proto2.proto"
+		package: "test.legacy"
+		message_type: [{
+			name: "Message"
+			field: [
+				{name:"opt" number:1 label:LABEL_OPTIONAL type:TYPE_INT32},
+				{name:"req" number:2 label:LABEL_REQUIRED type:TYPE_INT32},
+				{name:"group" number:3 label:LABEL_OPTIONAL type:TYPE_GROUP type_name:".test.legacy.Message.Group"},
+				{name:"reqgroup" number:4 label:LABEL_REQUIRED type:TYPE_GROUP type_name:".test.legacy.Message.ReqGroup"}
+			]
+			nested_type: [{name:"Group"}, {name:"ReqGroup"}]
+		}]
+	`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proto3, err := NewFile(mustParseFile(`
+		syntax:  "proto3"
+		name:    "legacy_proto3.proto"
+		package: "test.legacy"
+		message_type: [{
+			name: "Message"
+			field: [
+				{name:"plain" number:1 label:LABEL_OPTIONAL type:TYPE_INT32},
+				{name:"opt" number:2 label:LABEL_OPTIONAL type:TYPE_INT32 oneof_index:0 proto3_optional:true},
+				{name:"msg" number:3 label:LABEL_OPTIONAL type:TYPE_MESSAGE type_name:".test.legacy.Message"}
+			]
+			oneof_decl: [{name:"_opt"}]
+		}]
+	`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		presence = protoreflect.FullName("google.protobuf.FeatureSet.field_presence")
+		encoding = protoreflect.FullName("google.protobuf.FeatureSet.message_encoding")
+	)
+	p2 := proto2.Messages().Get(0).Fields()
+	p3 := proto3.Messages().Get(0).Fields()
+
+	tests := []struct {
+		field        protoreflect.FieldDescriptor
+		wantPresence descriptorpb.FeatureSet_FieldPresence
+		wantEncoding descriptorpb.FeatureSet_MessageEncoding
+		wantSources  map[protoreflect.FullName]protoreflect.Descriptor
+	}{{
+		field:        p2.ByName("opt"),
+		wantPresence: descriptorpb.FeatureSet_EXPLICIT,
+		wantEncoding: descriptorpb.FeatureSet_LENGTH_PREFIXED,
+		wantSources:  map[protoreflect.FullName]protoreflect.Descriptor{presence: nil, encoding: nil},
+	}, {
+		field:        p2.ByName("req"),
+		wantPresence: descriptorpb.FeatureSet_LEGACY_REQUIRED,
+		wantEncoding: descriptorpb.FeatureSet_LENGTH_PREFIXED,
+		wantSources:  map[protoreflect.FullName]protoreflect.Descriptor{presence: p2.ByName("req"), encoding: nil},
+	}, {
+		field:        p2.ByName("group"),
+		wantPresence: descriptorpb.FeatureSet_EXPLICIT,
+		wantEncoding: descriptorpb.FeatureSet_DELIMITED,
+		wantSources:  map[protoreflect.FullName]protoreflect.Descriptor{presence: nil, encoding: p2.ByName("group")},
+	}, {
+		field:        p2.ByName("reqgroup"),
+		wantPresence: descriptorpb.FeatureSet_LEGACY_REQUIRED,
+		wantEncoding: descriptorpb.FeatureSet_DELIMITED,
+		wantSources:  map[protoreflect.FullName]protoreflect.Descriptor{presence: p2.ByName("reqgroup"), encoding: p2.ByName("reqgroup")},
+	}, {
+		field:        p3.ByName("plain"),
+		wantPresence: descriptorpb.FeatureSet_IMPLICIT,
+		wantEncoding: descriptorpb.FeatureSet_LENGTH_PREFIXED,
+		wantSources:  map[protoreflect.FullName]protoreflect.Descriptor{presence: nil, encoding: nil},
+	}, {
+		field:        p3.ByName("opt"),
+		wantPresence: descriptorpb.FeatureSet_EXPLICIT,
+		wantEncoding: descriptorpb.FeatureSet_LENGTH_PREFIXED,
+		wantSources:  map[protoreflect.FullName]protoreflect.Descriptor{presence: p3.ByName("opt"), encoding: nil},
+	}, {
+		field:        p3.ByName("msg"),
+		wantPresence: descriptorpb.FeatureSet_IMPLICIT,
+		wantEncoding: descriptorpb.FeatureSet_LENGTH_PREFIXED,
+		wantSources:  map[protoreflect.FullName]protoreflect.Descriptor{presence: nil, encoding: nil},
+	}}
+	for _, tt := range tests {
+		got, err := ResolveFeatures(tt.field)
+		if err != nil {
+			t.Errorf("ResolveFeatures(%v) error: %v", tt.field.FullName(), err)
+			continue
+		}
+		if p := got.Features.GetFieldPresence(); p != tt.wantPresence {
+			t.Errorf("ResolveFeatures(%v).Features.FieldPresence = %v, want %v", tt.field.FullName(), p, tt.wantPresence)
+		}
+		if e := got.Features.GetMessageEncoding(); e != tt.wantEncoding {
+			t.Errorf("ResolveFeatures(%v).Features.MessageEncoding = %v, want %v", tt.field.FullName(), e, tt.wantEncoding)
+		}
+		for name, want := range tt.wantSources {
+			if src := got.Source(name); src != want {
+				t.Errorf("ResolveFeatures(%v).Source(%v) = %v, want %v", tt.field.FullName(), name, src, want)
+			}
+		}
+	}
+
+	// Descriptors other than fields resolve to the edition defaults.
+	got, err := ResolveFeatures(proto3.Messages().Get(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Sources) != 0 {
+		t.Errorf("Sources = %v, want empty", got.Sources)
+	}
+}
+
+func TestResolveFeaturesGoExtension(t *testing.T) {
+	fd, err := NewFile(mustParseFile(`
+		syntax:  "editions"
+		edition: EDITION_2023
+		name:    "resolve_go_features.proto"
+		package: "test.features"
+		enum_type: [{
+			name:    "Enum"
+			options: {features: {[pb.go]: {legacy_unmarshal_json_enum: true}}}
+			value:   [{name:"ZERO" number:0}]
+		}]
+	`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed := fd.Enums().Get(0)
+	got, err := ResolveFeatures(ed.Values().Get(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goFeatures, _ := got.Features.ProtoReflect().Get(gofeaturespb.E_Go.TypeDescriptor()).Message().Interface().(*gofeaturespb.GoFeatures)
+	if !goFeatures.GetLegacyUnmarshalJsonEnum() {
+		t.Errorf("legacy_unmarshal_json_enum = false, want true")
+	}
+	if src := got.Source("pb.GoFeatures.legacy_unmarshal_json_enum"); src != ed {
+		t.Errorf("Source(legacy_unmarshal_json_enum) = %v, want %v", src, ed)
+	}
+}
@@ -1280,3 +1280,62 @@ func TestToFileDescriptorProtoPlaceHolder(t *testing.T) {
 		t.Errorf("placeholder file descriptor proto is not valid: %s", err)
 	}
 }
+
+func TestRetainSourceCodeInfo(t *testing.T) {
+	in := mustParseFile(`
+		name: "retain.proto"
+		message_type: [{
+			name: "Message"
+			field: [{
+				name:"field" number:1 label:LABEL_OPTIONAL type:TYPE_STRING
+				options: {uninterpreted_option: [{name:[{name_part:"custom" is_extension:true}] identifier_value:"VALUE"}]}
+			}]
+		}]
+		source_code_info: {location: [
+			{path:[4,0] span:[1,0,1,20] leading_comments:" Message comment.\n"},
+			{path:[4,0,2,0] span:[2,2,30] trailing_comments:" Field comment.\n"}
+		]}
+	`)
+	in.SourceCodeInfo.Location[0].ProtoReflect().SetUnknown(protoreflect.RawFields{0xa8, 0x1f, 0x01}) // field 500, varint 1
+
+	for _, retain := range []bool{false, true} {
+		fd, err := FileOptions{RetainSourceCodeInfo: retain}.New(in, nil)
+		if err != nil {
+			t.Fatalf("New(RetainSourceCodeInfo: %v) error: %v", retain, err)
+		}
+		md := fd.Messages().Get(0)
+		if got, want := fd.SourceLocations().ByDescriptor(md).LeadingComments, " Message comment.\n"; got != want {
+			t.Errorf("RetainSourceCodeInfo: %v: message comment = %q, want %q", retain, got, want)
+		}
+		if got, want := fd.SourceLocations().ByDescriptor(md.Fields().Get(0)).TrailingComments, " Field comment.\n"; got != want {
+			t.Errorf("RetainSourceCodeInfo: %v: field comment = %q, want %q", retain, got, want)
+		}
+
+		out := ToFileDescriptorProto(fd)
+		if got, want := proto.Equal(out, in), retain; got != want {
+			t.Errorf("RetainSourceCodeInfo: %v: round-trip equal = %v, want %v\ngot:  %v\nwant: %v", retain, got, want, out, in)
+		}
+		if got := out.GetMessageType()[0].GetField()[0].GetOptions().GetUninterpretedOption(); len(got) != 1 {
+			t.Errorf("RetainSourceCodeInfo: %v: got %d uninterpreted options, want 1", retain, len(got))
+		}
+	}
+}
+
+func TestRetainSourceCodeInfoInvalid(t *testing.T) {
+	in := mustParseFile(`
+		name: "retain.proto"
+		source_code_info: {location: [{path:[] span:[1,0,1]}]}
+	`)
+	// A truncated unknown field cannot be decoded again.
+	in.SourceCodeInfo.ProtoReflect().SetUnknown(protoreflect.RawFields{0xa8})
+	if _, err := (FileOptions{RetainSourceCodeInfo: true}).New(in, nil); err == nil {
+		t.Errorf("New(RetainSourceCodeInfo: true) succeeded, want error")
+	}
+	fd, err := FileOptions{}.New(in, nil)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if got := ToFileDescriptorProto(fd).GetSourceCodeInfo().GetLocation(); len(got) != 1 {
+		t.Errorf("ToFileDescriptorProto() got %d locations, want 1", len(got))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"google.golang.org/protobuf/internal/encoding/defval"
+	"google.golang.org/protobuf/internal/filedesc"
 	"google.golang.org/protobuf/internal/strs"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -36,27 +37,10 @@ func ToFileDescriptorProto(file protoreflect.FileDescriptor) *descriptorpb.FileD
 			p.WeakDependency = append(p.WeakDependency, int32(i))
 		}
 	}
-	for i, locs := 0, file.SourceLocations(); i < locs.Len(); i++ {
-		loc := locs.Get(i)
-		l := &descriptorpb.SourceCodeInfo_Location{}
-		l.Path = append(l.Path, loc.Path...)
-		if loc.StartLine == loc.EndLine {
-			l.Span = []int32{int32(loc.StartLine), int32(loc.StartColumn), int32(loc.EndColumn)}
-		} else {
-			l.Span = []int32{int32(loc.StartLine), int32(loc.StartColumn), int32(loc.EndLine), int32(loc.EndColumn)}
-		}
-		l.LeadingDetachedComments = append([]string(nil), loc.LeadingDetachedComments...)
-		if loc.LeadingComments != "" {
-			l.LeadingComments = proto.String(loc.LeadingComments)
-		}
-		if loc.TrailingComments != "" {
-			l.TrailingComments = proto.String(loc.TrailingComments)
-		}
-		if p.SourceCodeInfo == nil {
-			p.SourceCodeInfo = &descriptorpb.SourceCodeInfo{}
-		}
-		p.SourceCodeInfo.Location = append(p.SourceCodeInfo.Location, l)
-
+	if info := retainedSourceCodeInfo(file); info != nil {
+		p.SourceCodeInfo = info
+	} else {
+		p.SourceCodeInfo = toSourceCodeInfo(file.SourceLocations())
 	}
 	for i, messages := 0, file.Messages(); i < messages.Len(); i++ {
 		p.MessageType = append(p.MessageType, ToDescriptorProto(messages.Get(i)))
@@ -86,6 +70,60 @@ func ToFileDescriptorProto(file protoreflect.FileDescriptor) *descriptorpb.FileD
 	return p
 }
 
+// retainedSourceCodeInfo returns the google.protobuf.SourceCodeInfo message
+// retained by [FileOptions.RetainSourceCodeInfo], or nil if there is none.
+func retainedSourceCodeInfo(file protoreflect.FileDescriptor) *descriptorpb.SourceCodeInfo {
+	locs, ok := file.SourceLocations().(*filedesc.SourceLocations)
+	if !ok || locs.Raw == nil {
+		return nil
+	}
+	// New checked that the retained message can be decoded.
+	info, err := unmarshalSourceCodeInfo(locs.Raw)
+	if err != nil {
+		return nil
+	}
+	return info
+}
+
+// unmarshalSourceCodeInfo decodes a google.protobuf.SourceCodeInfo message
+// retained by [FileOptions.RetainSourceCodeInfo].
+func unmarshalSourceCodeInfo(b []byte) (*descriptorpb.SourceCodeInfo, error) {
+	info := new(descriptorpb.SourceCodeInfo)
+	if err := (proto.UnmarshalOptions{AllowPartial: true}).Unmarshal(b, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// toSourceCodeInfo copies a [protoreflect.SourceLocations] into a
+// google.protobuf.SourceCodeInfo message. It returns nil if there are
+// no source locations.
+func toSourceCodeInfo(locs protoreflect.SourceLocations) *descriptorpb.SourceCodeInfo {
+	var info *descriptorpb.SourceCodeInfo
+	for i := 0; i < locs.Len(); i++ {
+		loc := locs.Get(i)
+		l := &descriptorpb.SourceCodeInfo_Location{}
+		l.Path = append(l.Path, loc.Path...)
+		if loc.StartLine == loc.EndLine {
+			l.Span = []int32{int32(loc.StartLine), int32(loc.StartColumn), int32(loc.EndColumn)}
+		} else {
+			l.Span = []int32{int32(loc.StartLine), int32(loc.StartColumn), int32(loc.EndLine), int32(loc.EndColumn)}
+		}
+		l.LeadingDetachedComments = append([]string(nil), loc.LeadingDetachedComments...)
+		if loc.LeadingComments != "" {
+			l.LeadingComments = proto.String(loc.LeadingComments)
+		}
+		if loc.TrailingComments != "" {
+			l.TrailingComments = proto.String(loc.TrailingComments)
+		}
+		if info == nil {
+			info = &descriptorpb.SourceCodeInfo{}
+		}
+		info.Location = append(info.Location, l)
+	}
+	return info
+}
+
 // ToDescriptorProto copies a [protoreflect.MessageDescriptor] into a
 // google.protobuf.DescriptorProto message.
 func ToDescriptorProto(message protoreflect.MessageDescriptor) *descriptorpb.DescriptorProto {
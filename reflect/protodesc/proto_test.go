@@ -11,6 +11,7 @@ import (
 	"google.golang.org/protobuf/internal/filedesc"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
@@ -108,3 +109,32 @@ func TestProto2Group(t *testing.T) {
 		t.Errorf("ToFieldDescriptor: unexpected diff (-want +got):\n%s", diff)
 	}
 }
+
+func TestProto3OptionalExtension(t *testing.T) {
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test.proto"),
+		Syntax:     proto.String("proto3"),
+		Package:    proto.String("test"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:           proto.String("opt"),
+			JsonName:       proto.String("opt"),
+			Number:         proto.Int32(50000),
+			Label:          descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:           descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+			Extendee:       proto.String(".google.protobuf.FieldOptions"),
+			Proto3Optional: proto.Bool(true),
+		}},
+	}
+	fd, err := NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("NewFile() error: %v", err)
+	}
+	xd := fd.Extensions().Get(0)
+	if !xd.HasOptionalKeyword() {
+		t.Errorf("HasOptionalKeyword() = false, want true")
+	}
+	if diff := cmp.Diff(fdp, ToFileDescriptorProto(fd), protocmp.Transform()); diff != "" {
+		t.Errorf("ToFileDescriptorProto: unexpected diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoorder_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoorder"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func newTestMessage() *testpb.TestAllTypes {
+	return &testpb.TestAllTypes{
+		OneofField:      &testpb.TestAllTypes_OneofUint32{OneofUint32: 1},
+		MapInt32Int32:   map[int32]int32{3: 0, 1: 0, 2: 0},
+		OptionalString:  proto.String("a"),
+		RepeatedInt32:   []int32{4},
+		OptionalInt32:   proto.Int32(5),
+		Optionalgroup:   &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(6)},
+		OptionalFixed32: proto.Uint32(7),
+	}
+}
+
+// rangeNames returns the names of the fields of m in the order.
+func rangeNames(m protoreflect.Message, less protoorder.FieldOrder) []string {
+	var names []string
+	protoorder.RangeFields(m, less, func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		names = append(names, string(fd.Name()))
+		return true
+	})
+	return names
+}
+
+func TestLegacyFieldOrderMatchesMarshal(t *testing.T) {
+	m := newTestMessage()
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	fields := m.ProtoReflect().Descriptor().Fields()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		b = b[n:]
+		b = b[protowire.ConsumeFieldValue(num, typ, b):]
+		if typ == protowire.EndGroupType {
+			continue
+		}
+		name := string(fields.ByNumber(num).Name())
+		if len(got) == 0 || got[len(got)-1] != name {
+			got = append(got, name)
+		}
+	}
+	if diff := cmp.Diff(rangeNames(m.ProtoReflect(), protoorder.LegacyFieldOrder), got); diff != "" {
+		t.Errorf("LegacyFieldOrder differs from deterministic proto.Marshal (-order +marshal):\n%s", diff)
+	}
+}
+
+func TestIndexNameFieldOrderMatchesText(t *testing.T) {
+	m := newTestMessage()
+	var got []string
+	for _, line := range strings.Split(prototext.MarshalOptions{Multiline: true}.Format(m), "\n") {
+		if line == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "}") {
+			continue
+		}
+		name := strings.TrimRight(strings.Fields(line)[0], ":")
+		if len(got) == 0 || got[len(got)-1] != name {
+			got = append(got, name)
+		}
+	}
+	// The text format names groups by their message name.
+	for i, name := range got {
+		if name == "OptionalGroup" {
+			got[i] = "optionalgroup"
+		}
+	}
+	if diff := cmp.Diff(rangeNames(m.ProtoReflect(), protoorder.IndexNameFieldOrder), got); diff != "" {
+		t.Errorf("IndexNameFieldOrder differs from prototext (-order +text):\n%s", diff)
+	}
+}
+
+func TestNumberFieldOrder(t *testing.T) {
+	m := newTestMessage().ProtoReflect()
+	var got []protoreflect.FieldNumber
+	protoorder.RangeFields(m, protoorder.NumberFieldOrder, func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		got = append(got, fd.Number())
+		return true
+	})
+	want := []protoreflect.FieldNumber{1, 7, 14, 16, 31, 56, 111}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NumberFieldOrder mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenericKeyOrder(t *testing.T) {
+	m := newTestMessage().ProtoReflect()
+	mapv := m.Get(m.Descriptor().Fields().ByName("map_int32_int32")).Map()
+	var got []int64
+	protoorder.RangeEntries(mapv, protoorder.GenericKeyOrder, func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		got = append(got, k.Int())
+		return len(got) < 2
+	})
+	if diff := cmp.Diff([]int64{1, 2}, got); diff != "" {
+		t.Errorf("RangeEntries with GenericKeyOrder mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoplan provides encoding plans, which describe how
+// [proto.Marshal] lays out the fields of a message in the wire format.
+//
+// A plan lists the fields of a message type in the order in which they are
+// encoded, together with the tag, wire type, and size strategy of each field.
+// Custom streaming encoders may use a plan to produce output with the same
+// field ordering as [proto.Marshal] without consulting the message descriptor
+// for every field of every message.
+//
+// The output of [proto.Marshal] for a message consists of:
+//
+//   - the extension fields that are set, in increasing order of field number,
+//   - the fields of the plan that are set, in the order of the plan, and
+//   - the unknown fields of the message.
+//
+// The entries of a map field are in an unspecified order unless
+// [proto.MarshalOptions.Deterministic] is set.
+// Plans do not describe the legacy message set wire format.
+package protoplan
+
+import (
+	"sort"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/order"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// A Plan is the encoding plan of a message type.
+type Plan struct {
+	Desc protoreflect.MessageDescriptor
+
+	// Fields are the non-extension fields of the message type,
+	// in the order in which they are encoded.
+	Fields []Field
+}
+
+// A Field is the encoding plan of a field.
+//
+// Each value of a field is encoded as a record consisting of the tag
+// followed by the value, except that the values of a packed field are
+// encoded together as a single length-prefixed record.
+type Field struct {
+	Desc protoreflect.FieldDescriptor
+
+	// Tag is the encoded tag of each record of the field.
+	Tag []byte
+
+	// WireType is the wire type of each record of the field.
+	WireType protowire.Type
+
+	// Packed reports whether the values of a repeated field
+	// are encoded together in a single record.
+	Packed bool
+
+	// Strategy is the way the size of each value is determined.
+	// For a packed field, this is the strategy of each value within the record.
+	Strategy Strategy
+
+	// FixedSize is the size in bytes of each value with the Fixed strategy.
+	FixedSize int
+}
+
+// Strategy is the way the size of an encoded value is determined.
+type Strategy int
+
+const (
+	Fixed          Strategy = iota + 1 // a number of bytes given by Field.FixedSize
+	Varint                             // a varint, whose size depends on the value
+	LengthPrefixed                     // preceded by its length as a varint
+	Delimited                          // terminated by an end group tag
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case Fixed:
+		return "fixed"
+	case Varint:
+		return "varint"
+	case LengthPrefixed:
+		return "length-prefixed"
+	case Delimited:
+		return "delimited"
+	default:
+		return "<unknown>"
+	}
+}
+
+var plans sync.Map // map[protoreflect.MessageDescriptor]*Plan
+
+// Of returns the encoding plan of messages of type md.
+// Plans are computed once for each descriptor and must not be modified.
+func Of(md protoreflect.MessageDescriptor) *Plan {
+	if p, ok := plans.Load(md); ok {
+		return p.(*Plan)
+	}
+	p := &Plan{Desc: md}
+	fds := md.Fields()
+	for i := 0; i < fds.Len(); i++ {
+		p.Fields = append(p.Fields, FieldOf(fds.Get(i)))
+	}
+	sort.SliceStable(p.Fields, func(i, j int) bool {
+		return order.LegacyFieldOrder(p.Fields[i].Desc, p.Fields[j].Desc)
+	})
+	pp, _ := plans.LoadOrStore(md, p)
+	return pp.(*Plan)
+}
+
+// FieldOf returns the encoding plan of the field fd,
+// which may also be an extension field.
+func FieldOf(fd protoreflect.FieldDescriptor) Field {
+	f := Field{Desc: fd}
+	switch {
+	case fd.IsMap():
+		f.WireType = protowire.BytesType
+		f.Strategy = LengthPrefixed
+	default:
+		f.WireType, f.Strategy, f.FixedSize = kindEncoding(fd.Kind())
+		if fd.IsPacked() {
+			f.WireType = protowire.BytesType
+			f.Packed = true
+		}
+	}
+	f.Tag = protowire.AppendTag(nil, fd.Number(), f.WireType)
+	return f
+}
+
+func kindEncoding(k protoreflect.Kind) (protowire.Type, Strategy, int) {
+	switch k {
+	case protoreflect.BoolKind, protoreflect.EnumKind,
+		protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Uint32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Uint64Kind:
+		return protowire.VarintType, Varint, 0
+	case protoreflect.Sfixed32Kind, protoreflect.Fixed32Kind, protoreflect.FloatKind:
+		return protowire.Fixed32Type, Fixed, 4
+	case protoreflect.Sfixed64Kind, protoreflect.Fixed64Kind, protoreflect.DoubleKind:
+		return protowire.Fixed64Type, Fixed, 8
+	case protoreflect.GroupKind:
+		return protowire.StartGroupType, Delimited, 0
+	default:
+		return protowire.BytesType, LengthPrefixed, 0
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoplan_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoplan"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestOrder(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32:   proto.Int32(1),
+		OptionalFixed32: proto.Uint32(2),
+		Optionalgroup:   &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(3)},
+		RepeatedInt32:   []int32{4, 5},
+		MapInt32Int32:   map[int32]int32{6: 7},
+		OneofField:      &testpb.TestAllTypes_OneofUint32{OneofUint32: 8},
+		OneofOptional:   &testpb.TestAllTypes_OneofOptionalUint32{OneofOptionalUint32: 9},
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan := protoplan.Of(m.ProtoReflect().Descriptor())
+	if plan != protoplan.Of(m.ProtoReflect().Descriptor()) {
+		t.Errorf("Of() returned a different plan for the same descriptor")
+	}
+	index := make(map[protowire.Number]int)
+	for i, f := range plan.Fields {
+		index[f.Desc.Number()] = i
+	}
+	last := -1
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeField(b)
+		if n < 0 {
+			t.Fatal(protowire.ParseError(n))
+		}
+		i, ok := index[num]
+		if !ok {
+			t.Fatalf("field %v is not in the plan", num)
+		}
+		if i < last {
+			t.Errorf("field %v is encoded after field %v, but is before it in the plan", num, plan.Fields[last].Desc.Number())
+		}
+		f := plan.Fields[i]
+		if typ != f.WireType || string(b[:len(f.Tag)]) != string(f.Tag) {
+			t.Errorf("field %v has wire type %v, but the plan has %v", num, typ, f.WireType)
+		}
+		last = i
+		b = b[n:]
+	}
+}
+
+func TestFieldOf(t *testing.T) {
+	fieldOf := func(m proto.Message, name protoreflect.Name) protoplan.Field {
+		return protoplan.FieldOf(m.ProtoReflect().Descriptor().Fields().ByName(name))
+	}
+	for _, tt := range []struct {
+		got  protoplan.Field
+		want protoplan.Field
+	}{{
+		got:  fieldOf(&testpb.TestAllTypes{}, "optional_int32"),
+		want: protoplan.Field{WireType: protowire.VarintType, Strategy: protoplan.Varint},
+	}, {
+		got:  fieldOf(&testpb.TestAllTypes{}, "optional_double"),
+		want: protoplan.Field{WireType: protowire.Fixed64Type, Strategy: protoplan.Fixed, FixedSize: 8},
+	}, {
+		got:  fieldOf(&testpb.TestAllTypes{}, "optionalgroup"),
+		want: protoplan.Field{WireType: protowire.StartGroupType, Strategy: protoplan.Delimited},
+	}, {
+		got:  fieldOf(&testpb.TestAllTypes{}, "repeated_string"),
+		want: protoplan.Field{WireType: protowire.BytesType, Strategy: protoplan.LengthPrefixed},
+	}, {
+		got:  fieldOf(&testpb.TestAllTypes{}, "map_int32_int32"),
+		want: protoplan.Field{WireType: protowire.BytesType, Strategy: protoplan.LengthPrefixed},
+	}, {
+		got:  fieldOf(&testpb.TestPackedTypes{}, "packed_float"),
+		want: protoplan.Field{WireType: protowire.BytesType, Packed: true, Strategy: protoplan.Fixed, FixedSize: 4},
+	}} {
+		got := tt.got
+		if string(got.Tag) != string(protowire.AppendTag(nil, got.Desc.Number(), tt.want.WireType)) {
+			t.Errorf("%v: Tag = %x", got.Desc.Name(), got.Tag)
+		}
+		got.Desc, got.Tag = nil, nil
+		if !cmp.Equal(got, tt.want) {
+			t.Errorf("FieldOf(%v) = %+v, want %+v", tt.got.Desc.Name(), got, tt.want)
+		}
+	}
+}
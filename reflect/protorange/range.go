@@ -8,6 +8,7 @@ package protorange
 import (
 	"bytes"
 	"errors"
+	"fmt"
 
 	"google.golang.org/protobuf/internal/genid"
 	"google.golang.org/protobuf/internal/order"
@@ -94,7 +95,9 @@ type Options struct {
 // during to a push, then the effects of the mutation will affect traversal.
 // For example, if the last value is currently a message, and the push function
 // populates a few fields in that message, then the newly modified fields
-// will be traversed.
+// will be traversed. The last value may be replaced using [Set] or
+// removed using [Clear], in which case the replacement value is traversed
+// or the traversal of the children of the removed value is skipped.
 //
 // The [protopath.Values] provided to push functions is only valid until the
 // corresponding pop call and the values provided to a pop call is only valid
@@ -137,8 +140,9 @@ func (o Options) rangeMessage(p *protopath.Values, m protoreflect.Message, push,
 		pushStep(p, protopath.FieldAccess(fd), v)
 		if push != nil {
 			err = amendError(err, push(*p))
+			v = p.Index(-1).Value
 		}
-		if err == nil {
+		if err == nil && v.IsValid() {
 			switch {
 			case fd.IsMap():
 				err = o.rangeMap(p, fd, v.Map(), push, pop)
@@ -246,6 +250,7 @@ func (o Options) rangeList(p *protopath.Values, fd protoreflect.FieldDescriptor,
 		pushStep(p, protopath.ListIndex(i), v)
 		if push != nil {
 			err = amendError(err, push(*p))
+			v = p.Index(-1).Value
 		}
 		if err == nil && fd.Message() != nil {
 			err = o.rangeMessage(p, v.Message(), push, pop)
@@ -271,8 +276,9 @@ func (o Options) rangeMap(p *protopath.Values, fd protoreflect.FieldDescriptor,
 		pushStep(p, protopath.MapIndex(k), v)
 		if push != nil {
 			err = amendError(err, push(*p))
+			v = p.Index(-1).Value
 		}
-		if err == nil && fd.MapValue().Message() != nil {
+		if err == nil && v.IsValid() && fd.MapValue().Message() != nil {
 			err = o.rangeMessage(p, v.Message(), push, pop)
 		}
 		if pop != nil {
@@ -288,6 +294,62 @@ func (o Options) rangeMap(p *protopath.Values, fd protoreflect.FieldDescriptor,
 	return err
 }
 
+// Set replaces the last value of p, which is the value currently being
+// visited, with v in the message, list, or map that contains it.
+// It may only be called from the push or pop function given the same p.
+// If called from a push function, the replacement value is traversed
+// instead of the original value.
+//
+// The last step must be a [protopath.FieldAccess], [protopath.UnknownAccess],
+// [protopath.ListIndex], or [protopath.MapIndex] step, otherwise Set panics.
+// To change a root or expanded google.protobuf.Any message,
+// mutate the message directly.
+func Set(p protopath.Values, v protoreflect.Value) {
+	if len(p.Values) < 2 {
+		panic("protorange: cannot set the root value")
+	}
+	parent := p.Index(-2).Value
+	switch s := p.Index(-1).Step; s.Kind() {
+	case protopath.FieldAccessStep:
+		parent.Message().Set(s.FieldDescriptor(), v)
+	case protopath.UnknownAccessStep:
+		parent.Message().SetUnknown(v.Bytes())
+	case protopath.ListIndexStep:
+		parent.List().Set(s.ListIndex(), v)
+	case protopath.MapIndexStep:
+		parent.Map().Set(s.MapIndex(), v)
+	default:
+		panic(fmt.Sprintf("protorange: cannot set the value of a %v step", s))
+	}
+	p.Values[len(p.Values)-1] = v
+}
+
+// Clear removes the last value of p, which is the value currently being
+// visited, from the message or map that contains it.
+// It may only be called from the push or pop function given the same p.
+// If called from a push function, the children of the value are not traversed.
+//
+// The last step must be a [protopath.FieldAccess], [protopath.UnknownAccess],
+// or [protopath.MapIndex] step, otherwise Clear panics.
+// To remove a list element, use [Set] to replace the list.
+func Clear(p protopath.Values) {
+	if len(p.Values) < 2 {
+		panic("protorange: cannot clear the root value")
+	}
+	parent := p.Index(-2).Value
+	switch s := p.Index(-1).Step; s.Kind() {
+	case protopath.FieldAccessStep:
+		parent.Message().Clear(s.FieldDescriptor())
+	case protopath.UnknownAccessStep:
+		parent.Message().SetUnknown(nil)
+	case protopath.MapIndexStep:
+		parent.Map().Clear(s.MapIndex())
+	default:
+		panic(fmt.Sprintf("protorange: cannot clear the value of a %v step", s))
+	}
+	p.Values[len(p.Values)-1] = protoreflect.Value{}
+}
+
 func pushStep(p *protopath.Values, s protopath.Step, v protoreflect.Value) {
 	p.Path = append(p.Path, s)
 	p.Values = append(p.Values, v)
@@ -251,3 +251,94 @@ func TestRange(t *testing.T) {
 		})
 	}
 }
+
+func TestSetClear(t *testing.T) {
+	m := &newspb.Article{
+		Author: "Brad Fitzpatrick",
+		Date:   timestamppb.New(time.Date(2018, time.February, 16, 0, 0, 0, 0, time.UTC)),
+		Title:  "Go 1.10 is released",
+		Tags:   []string{"go1.10", "release"},
+		Attachments: []*anypb.Any{{
+			TypeUrl: "google.golang.org.KeyValueAttachment",
+			Value: mustMarshal(&newspb.KeyValueAttachment{
+				Name: "checksums.txt",
+				Data: map[string]string{
+					"go1.10.src.tar.gz":       "07cbb9d0091b846c6aea40bf5bc0cea7",
+					"go1.10.darwin-amd64.pkg": "cbb38bb6ff6ea86279e01745984445bf",
+				},
+			}),
+		}},
+	}
+	var gotPaths []string
+	err := Options{Stable: true}.Range(m.ProtoReflect(), func(p protopath.Values) error {
+		gotPaths = append(gotPaths, p.Path[1:].String())
+		switch p.Path[1:].String() {
+		case ".author", `.attachments[0].(google.golang.org.KeyValueAttachment).data["go1.10.src.tar.gz"]`:
+			Set(p, protoreflect.ValueOfString("REDACTED"))
+		case ".tags[1]":
+			Set(p, protoreflect.ValueOfString("redacted"))
+		case ".date", `.attachments[0].(google.golang.org.KeyValueAttachment).data["go1.10.darwin-amd64.pkg"]`:
+			Clear(p)
+		case ".title":
+			Set(p, protoreflect.ValueOfString(p.Index(-1).Value.String()+"!"))
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("Range() error: %v", err)
+	}
+
+	wantPaths := []string{
+		"",
+		".author",
+		".date",
+		".title",
+		".attachments",
+		".attachments[0]",
+		".attachments[0].(google.golang.org.KeyValueAttachment)",
+		".attachments[0].(google.golang.org.KeyValueAttachment).name",
+		".attachments[0].(google.golang.org.KeyValueAttachment).data",
+		`.attachments[0].(google.golang.org.KeyValueAttachment).data["go1.10.darwin-amd64.pkg"]`,
+		`.attachments[0].(google.golang.org.KeyValueAttachment).data["go1.10.src.tar.gz"]`,
+		".tags",
+		".tags[0]",
+		".tags[1]",
+	}
+	if diff := cmp.Diff(wantPaths, gotPaths); diff != "" {
+		t.Errorf("paths mismatch (-want +got):\n%s", diff)
+	}
+	want := &newspb.Article{
+		Author: "REDACTED",
+		Title:  "Go 1.10 is released!",
+		Tags:   []string{"go1.10", "redacted"},
+		Attachments: []*anypb.Any{{
+			TypeUrl: "google.golang.org.KeyValueAttachment",
+			Value: mustMarshal(&newspb.KeyValueAttachment{
+				Name: "checksums.txt",
+				Data: map[string]string{
+					"go1.10.src.tar.gz": "REDACTED",
+				},
+			}),
+		}},
+	}
+	if diff := cmp.Diff(want, m, protocmp.Transform()); diff != "" {
+		t.Errorf("message mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, f := range []func(protopath.Values){
+		func(p protopath.Values) { Set(p, protoreflect.ValueOfMessage(m.ProtoReflect())) },
+		func(p protopath.Values) { Clear(p) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("mutating the root value did not panic")
+				}
+			}()
+			Range(m.ProtoReflect(), func(p protopath.Values) error {
+				f(p)
+				return Terminate
+			})
+		}()
+	}
+}
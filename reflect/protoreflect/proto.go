@@ -397,6 +397,14 @@ type FieldRanges interface {
 	Get(i int) [2]FieldNumber // start inclusive; end exclusive
 	// Has reports whether n is within any of the ranges.
 	Has(n FieldNumber) bool
+	// HasRange reports whether every number in r is within the ranges,
+	// where r is a range of the same form as returned by Get.
+	// The numbers may be covered by several adjacent ranges.
+	// It reports true if r is empty.
+	HasRange(r [2]FieldNumber) bool
+	// Overlaps reports whether any number in r is within the ranges,
+	// where r is a range of the same form as returned by Get.
+	Overlaps(r [2]FieldNumber) bool
 
 	doNotImplement
 }
@@ -412,6 +420,14 @@ type EnumRanges interface {
 	Get(i int) [2]EnumNumber // start inclusive; end inclusive
 	// Has reports whether n is within any of the ranges.
 	Has(n EnumNumber) bool
+	// HasRange reports whether every number in r is within the ranges,
+	// where r is a range of the same form as returned by Get.
+	// The numbers may be covered by several adjacent ranges.
+	// It reports true if r is empty.
+	HasRange(r [2]EnumNumber) bool
+	// Overlaps reports whether any number in r is within the ranges,
+	// where r is a range of the same form as returned by Get.
+	Overlaps(r [2]EnumNumber) bool
 
 	doNotImplement
 }
@@ -8,9 +8,8 @@ import (
 	"bytes"
 	"fmt"
 	"math"
-	"reflect"
 
-	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/internal/encoding/unknownfields"
 )
 
 // Equal reports whether v1 and v2 are recursively equal.
@@ -112,7 +111,7 @@ func equalMessage(mx, my Message) bool {
 		return false
 	}
 
-	return equalUnknown(mx.GetUnknown(), my.GetUnknown())
+	return unknownfields.Equal(mx.GetUnknown(), my.GetUnknown())
 }
 
 // equalList compares two lists.
@@ -141,28 +140,3 @@ func equalMap(x, y Map) bool {
 	})
 	return equal
 }
-
-// equalUnknown compares unknown fields by direct comparison on the raw bytes
-// of each individual field number.
-func equalUnknown(x, y RawFields) bool {
-	if len(x) != len(y) {
-		return false
-	}
-	if bytes.Equal([]byte(x), []byte(y)) {
-		return true
-	}
-
-	mx := make(map[FieldNumber]RawFields)
-	my := make(map[FieldNumber]RawFields)
-	for len(x) > 0 {
-		fnum, _, n := protowire.ConsumeField(x)
-		mx[fnum] = append(mx[fnum], x[:n]...)
-		x = x[n:]
-	}
-	for len(y) > 0 {
-		fnum, _, n := protowire.ConsumeField(y)
-		my[fnum] = append(my[fnum], y[:n]...)
-		y = y[n:]
-	}
-	return reflect.DeepEqual(mx, my)
-}
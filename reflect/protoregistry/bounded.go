@@ -0,0 +1,289 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoregistry
+
+import (
+	"sort"
+	"sync"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// BoundedFiles is a registry of files whose estimated memory use is limited
+// to a budget. It is intended for servers that register descriptors
+// from untrusted sources, such as schemas uploaded by tenants, where the
+// registry must not grow without bound.
+//
+// The size of a file is its [Stats.Size]. When registering a file would
+// exceed the budget, the least recently used files are evicted to make room
+// if Evict is set, otherwise the file is not registered.
+// A file is used when it is registered or when it, or a descriptor within
+// it, is found by a lookup. Files imported by other registered files are never
+// evicted, since their descriptors remain referenced by the importing files.
+//
+// The zero value is an unbounded registry ready for use.
+// The Find and Range methods are safe for concurrent use,
+// and so is RegisterFile, unlike for [Files].
+// The exported fields must not be modified after the first file is registered.
+type BoundedFiles struct {
+	// MaxSize is the maximum total size of the registered files.
+	// If zero, the size is unlimited.
+	MaxSize int
+
+	// Evict specifies whether to evict the least recently used files
+	// when registering a file would exceed MaxSize.
+	// If false, RegisterFile returns an error instead.
+	Evict bool
+
+	// OnEvict, if non-nil, is called with each evicted file.
+	// It is called while the registry is locked,
+	// so it must not call methods on the registry.
+	OnEvict func(protoreflect.FileDescriptor)
+
+	mu       sync.Mutex
+	files    *Files
+	entries  map[string]*boundedEntry // keyed by file path
+	packages map[protoreflect.FullName]int
+	size     int
+	clock    uint64 // incremented on every use of a file
+	evicted  int
+}
+
+type boundedEntry struct {
+	fd       protoreflect.FileDescriptor
+	size     int
+	lastUse  uint64
+	importer int // number of registered files that import this file
+}
+
+// RegisterFile registers the provided file descriptor.
+//
+// It returns an error if the file conflicts with a registered file as for
+// [Files.RegisterFile], if its own size exceeds MaxSize, or if there is
+// not enough room for it and Evict is false or no more files can be evicted.
+// Files are only evicted if the file is then registered.
+func (r *BoundedFiles) RegisterFile(file protoreflect.FileDescriptor) error {
+	var s Stats
+	s.addFile(file)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.files == nil {
+		r.files = new(Files)
+		r.entries = make(map[string]*boundedEntry)
+		r.packages = make(map[protoreflect.FullName]int)
+	}
+	if r.MaxSize > 0 && s.Size > r.MaxSize {
+		return errors.New("file %q of size %d exceeds the registry size limit of %d", file.Path(), s.Size, r.MaxSize)
+	}
+
+	var evict []*boundedEntry
+	if r.MaxSize > 0 && r.size+s.Size > r.MaxSize {
+		if !r.Evict {
+			return errors.New("file %q of size %d exceeds the remaining registry size of %d", file.Path(), s.Size, r.MaxSize-r.size)
+		}
+		evict = r.selectEvictions(file, r.size+s.Size-r.MaxSize)
+		if evict == nil {
+			return errors.New("file %q of size %d exceeds the remaining registry size of %d and no files can be evicted", file.Path(), s.Size, r.MaxSize-r.size)
+		}
+	}
+
+	// The evicted files are removed before the file is registered, since
+	// it may declare the same names. They are restored if that fails.
+	for _, e := range evict {
+		r.unregister(e.fd)
+	}
+	if err := r.files.RegisterFile(file); err != nil {
+		for _, e := range evict {
+			r.files.RegisterFile(e.fd)
+			r.addPackage(e.fd.Package(), 1)
+		}
+		return err
+	}
+	for _, e := range evict {
+		r.removeEntry(e)
+		if r.OnEvict != nil {
+			r.OnEvict(e.fd)
+		}
+	}
+
+	r.clock++
+	r.entries[file.Path()] = &boundedEntry{fd: file, size: s.Size, lastUse: r.clock}
+	r.size += s.Size
+	r.addPackage(file.Package(), 1)
+	r.forEachImport(file, func(e *boundedEntry) { e.importer++ })
+	return nil
+}
+
+// selectEvictions returns the entries of the least recently used files that
+// are not imported by other files or by file and whose total size is
+// at least n, or nil if there are no such files.
+func (r *BoundedFiles) selectEvictions(file protoreflect.FileDescriptor, n int) []*boundedEntry {
+	var candidates []*boundedEntry
+	for path, e := range r.entries {
+		if e.importer == 0 && !imports(file, path) {
+			candidates = append(candidates, e)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].lastUse < candidates[j].lastUse })
+	var evict []*boundedEntry
+	for _, e := range candidates {
+		if n <= 0 {
+			break
+		}
+		evict = append(evict, e)
+		n -= e.size
+	}
+	if n > 0 {
+		return nil
+	}
+	return evict
+}
+
+// unregister removes the file and its declarations from r.files,
+// which Files itself does not support.
+func (r *BoundedFiles) unregister(file protoreflect.FileDescriptor) {
+	f := r.files
+	path := file.Path()
+	f.filesByPath[path] = removeFile(f.filesByPath[path], file)
+	if len(f.filesByPath[path]) == 0 {
+		delete(f.filesByPath, path)
+	}
+	f.numFiles--
+	p := f.descsByName[file.Package()].(*packageDescriptor)
+	p.files = removeFile(p.files, file)
+	rangeTopLevelDescriptors(file, func(d protoreflect.Descriptor) {
+		if f.descsByName[d.FullName()] == d {
+			delete(f.descsByName, d.FullName())
+		}
+	})
+	r.addPackage(file.Package(), -1)
+}
+
+// addPackage adds n to the number of registered files in the package name
+// and its parent packages, and removes the packages without files
+// from r.files, so that their names may be declared by other files.
+func (r *BoundedFiles) addPackage(name protoreflect.FullName, n int) {
+	for ; name != ""; name = name.Parent() {
+		r.packages[name] += n
+		if r.packages[name] == 0 {
+			delete(r.packages, name)
+			delete(r.files.descsByName, name)
+		}
+	}
+}
+
+func (r *BoundedFiles) removeEntry(e *boundedEntry) {
+	r.forEachImport(e.fd, func(e *boundedEntry) { e.importer-- })
+	r.size -= e.size
+	r.evicted++
+	delete(r.entries, e.fd.Path())
+}
+
+// forEachImport calls f with the entry of each registered file imported by fd.
+func (r *BoundedFiles) forEachImport(fd protoreflect.FileDescriptor, f func(*boundedEntry)) {
+	imports := fd.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		if e := r.entries[imports.Get(i).Path()]; e != nil {
+			f(e)
+		}
+	}
+}
+
+// imports reports whether file directly imports the file at path.
+func imports(file protoreflect.FileDescriptor, path string) bool {
+	for i := 0; i < file.Imports().Len(); i++ {
+		if file.Imports().Get(i).Path() == path {
+			return true
+		}
+	}
+	return false
+}
+
+func removeFile(fds []protoreflect.FileDescriptor, fd protoreflect.FileDescriptor) []protoreflect.FileDescriptor {
+	for i, x := range fds {
+		if x == fd {
+			return append(fds[:i], fds[i+1:]...)
+		}
+	}
+	return fds
+}
+
+// use marks the file as recently used.
+func (r *BoundedFiles) use(fd protoreflect.FileDescriptor) {
+	if e := r.entries[fd.Path()]; e != nil {
+		r.clock++
+		e.lastUse = r.clock
+	}
+}
+
+// FindFileByPath looks up a file by the path.
+// See [Files.FindFileByPath] for details.
+func (r *BoundedFiles) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.files == nil {
+		return nil, NotFound
+	}
+	fd, err := r.files.FindFileByPath(path)
+	if err == nil {
+		r.use(fd)
+	}
+	return fd, err
+}
+
+// FindDescriptorByName looks up a descriptor by the full name.
+// See [Files.FindDescriptorByName] for details.
+func (r *BoundedFiles) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.files == nil {
+		return nil, NotFound
+	}
+	d, err := r.files.FindDescriptorByName(name)
+	if err == nil {
+		r.use(d.ParentFile())
+	}
+	return d, err
+}
+
+// RangeFiles iterates over all files registered at the time of the call
+// while f returns true. The iteration order is undefined.
+// Iterating over files does not count as using them.
+func (r *BoundedFiles) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
+	r.mu.Lock()
+	fds := make([]protoreflect.FileDescriptor, 0, len(r.entries))
+	for _, e := range r.entries {
+		fds = append(fds, e.fd)
+	}
+	r.mu.Unlock()
+	for _, fd := range fds {
+		if !f(fd) {
+			return
+		}
+	}
+}
+
+// BoundedStats reports the state of a [BoundedFiles] registry.
+type BoundedStats struct {
+	Files   int // number of registered files
+	Size    int // total size of the registered files
+	MaxSize int // the size limit, or zero if unlimited
+	Evicted int // number of files evicted since the registry was created
+}
+
+// Stats reports the number and size of the registered files
+// and the number of files evicted so far.
+func (r *BoundedFiles) Stats() BoundedStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return BoundedStats{
+		Files:   len(r.entries),
+		Size:    r.size,
+		MaxSize: r.MaxSize,
+		Evicted: r.evicted,
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoregistry_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestBoundedFiles(t *testing.T) {
+	// Each file has a single message and is of the same size.
+	makeFileInPackage := func(r *protoregistry.BoundedFiles, name, pkg, msg string, deps ...string) protoreflect.FileDescriptor {
+		t.Helper()
+		pb := new(descriptorpb.FileDescriptorProto)
+		s := fmt.Sprintf(`syntax: "proto2" name: "%s.proto" package: "%s" message_type: [{name: "%s"}]`, name, pkg, msg)
+		if err := prototext.Unmarshal([]byte(s), pb); err != nil {
+			t.Fatal(err)
+		}
+		for _, dep := range deps {
+			pb.Dependency = append(pb.Dependency, dep+".proto")
+		}
+		fd, err := protodesc.NewFile(pb, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fd
+	}
+	makeFile := func(r *protoregistry.BoundedFiles, name string, deps ...string) protoreflect.FileDescriptor {
+		t.Helper()
+		return makeFileInPackage(r, name, name, "M", deps...)
+	}
+	var size int
+	{
+		var s protoregistry.Files
+		s.RegisterFile(makeFile(nil, "a"))
+		size = s.Stats().Size
+	}
+	paths := func(r *protoregistry.BoundedFiles) []string {
+		var ps []string
+		r.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			ps = append(ps, fd.Path())
+			return true
+		})
+		sort.Strings(ps)
+		return ps
+	}
+
+	t.Run("Unbounded", func(t *testing.T) {
+		var r protoregistry.BoundedFiles
+		for _, name := range []string{"a", "b", "c"} {
+			if err := r.RegisterFile(makeFile(&r, name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want := protoregistry.BoundedStats{Files: 3, Size: 3 * size}
+		if got := r.Stats(); got != want {
+			t.Errorf("Stats() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("NoEvict", func(t *testing.T) {
+		r := &protoregistry.BoundedFiles{MaxSize: 2 * size}
+		for _, name := range []string{"a", "b"} {
+			if err := r.RegisterFile(makeFile(r, name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := r.RegisterFile(makeFile(r, "c")); err == nil {
+			t.Errorf("RegisterFile() over budget succeeded, want error")
+		}
+		if diff := cmp.Diff([]string{"a.proto", "b.proto"}, paths(r)); diff != "" {
+			t.Errorf("files mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		r := &protoregistry.BoundedFiles{MaxSize: size - 1, Evict: true}
+		if err := r.RegisterFile(makeFile(r, "a")); err == nil {
+			t.Errorf("RegisterFile() of file larger than budget succeeded, want error")
+		}
+	})
+
+	t.Run("Evict", func(t *testing.T) {
+		var evicted []string
+		r := &protoregistry.BoundedFiles{
+			MaxSize: 3 * size,
+			Evict:   true,
+			OnEvict: func(fd protoreflect.FileDescriptor) { evicted = append(evicted, fd.Path()) },
+		}
+		for _, name := range []string{"a", "b", "c"} {
+			if err := r.RegisterFile(makeFile(r, name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Using a marks it as recently used, so b is evicted first.
+		if _, err := r.FindDescriptorByName("a.M"); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.RegisterFile(makeFile(r, "d")); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"b.proto"}, evicted); diff != "" {
+			t.Errorf("evicted files mismatch (-want +got):\n%s", diff)
+		}
+		if _, err := r.FindFileByPath("b.proto"); err != protoregistry.NotFound {
+			t.Errorf("FindFileByPath(%q) error = %v, want %v", "b.proto", err, protoregistry.NotFound)
+		}
+		if _, err := r.FindDescriptorByName("b.M"); err != protoregistry.NotFound {
+			t.Errorf("FindDescriptorByName(%q) error = %v, want %v", "b.M", err, protoregistry.NotFound)
+		}
+
+		// Files imported by other files are not evicted, including those
+		// imported by the file being registered.
+		if _, err := r.FindFileByPath("c.proto"); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.RegisterFile(makeFile(r, "e", "c")); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.RegisterFile(makeFile(r, "f", "e")); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{"b.proto", "a.proto", "d.proto"}, evicted); diff != "" {
+			t.Errorf("evicted files mismatch (-want +got):\n%s", diff)
+		}
+		if err := r.RegisterFile(makeFile(r, "g", "f")); err == nil {
+			t.Errorf("RegisterFile() with no evictable files succeeded, want error")
+		}
+		if diff := cmp.Diff([]string{"c.proto", "e.proto", "f.proto"}, paths(r)); diff != "" {
+			t.Errorf("files mismatch (-want +got):\n%s", diff)
+		}
+		want := protoregistry.BoundedStats{Files: 3, Size: 3 * size, MaxSize: 3 * size, Evicted: 3}
+		if got := r.Stats(); got != want {
+			t.Errorf("Stats() = %+v, want %+v", got, want)
+		}
+	})
+	t.Run("EvictNames", func(t *testing.T) {
+		r := &protoregistry.BoundedFiles{MaxSize: 2 * size, Evict: true}
+		for _, name := range []string{"a", "b"} {
+			if err := r.RegisterFile(makeFile(r, name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// The names of an evicted file, including its package,
+		// may be declared by other files.
+		if err := r.RegisterFile(makeFileInPackage(r, "a2", "", "a")); err != nil {
+			t.Fatalf("RegisterFile() of file declaring the package name of an evicted file: %v", err)
+		}
+		if diff := cmp.Diff([]string{"a2.proto", "b.proto"}, paths(r)); diff != "" {
+			t.Errorf("files mismatch (-want +got):\n%s", diff)
+		}
+
+		// If the file conflicts with a file that is not evicted,
+		// the files selected for eviction are retained.
+		if err := r.RegisterFile(makeFileInPackage(r, "c", "", "a")); err == nil {
+			t.Errorf("RegisterFile() of conflicting file succeeded, want error")
+		}
+		if diff := cmp.Diff([]string{"a2.proto", "b.proto"}, paths(r)); diff != "" {
+			t.Errorf("files mismatch (-want +got):\n%s", diff)
+		}
+		if _, err := r.FindDescriptorByName("b.M"); err != nil {
+			t.Errorf("FindDescriptorByName(%q) error: %v", "b.M", err)
+		}
+		if got := r.Stats(); got.Files != 2 || got.Evicted != 1 {
+			t.Errorf("Stats() = %+v, want 2 files and 1 evicted", got)
+		}
+	})
+}
@@ -18,12 +18,14 @@ package protoregistry
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"sync"
 
 	"google.golang.org/protobuf/internal/encoding/messageset"
 	"google.golang.org/protobuf/internal/errors"
 	"google.golang.org/protobuf/internal/flags"
+	"google.golang.org/protobuf/internal/genid"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
@@ -175,45 +177,75 @@ func (r *Files) RegisterFile(file protoreflect.FileDescriptor) error {
 	return nil
 }
 
+// genProtoAliases maps the path of a proto file to the genproto package
+// whose generated code was redirected to another package.
+// It is protected by globalMutex.
+var genProtoAliases = map[string]genProtoAlias{}
+
+type genProtoAlias struct {
+	from, to string
+}
+
+// RegisterGenProtoAlias records that the generated code for the proto file
+// at path, which is provided by the google.golang.org/genproto package
+// at the import path from, is instead provided by the package at to.
+// This is typically called from an init function in the package at to,
+// which must have been generated with the import paths of genproto packages
+// redirected to local packages (see the genproto_redirect option of
+// protoc-gen-go).
+//
+// If the file is registered more than once in [GlobalFiles],
+// the resulting panic explains that the genproto package is still linked
+// into the program alongside its replacement.
+func RegisterGenProtoAlias(path, from, to string) {
+	globalMutex.Lock()
+	defer globalMutex.Unlock()
+	genProtoAliases[path] = genProtoAlias{from, to}
+}
+
 // Several well-known types were hosted in the google.golang.org/genproto module
 // but were later moved to this module. To avoid a weak dependency on the
 // genproto module (and its relatively large set of transitive dependencies),
 // we rely on a registration conflict to determine whether the genproto version
 // is too old (i.e., does not contain aliases to the new type declarations).
+//
+// Similarly, a conflict over a file with a registered genproto alias means
+// that both the genproto package and its replacement are linked in.
 func (r *Files) checkGenProtoConflict(path string) {
 	if r != GlobalFiles {
 		return
 	}
-	var prevPath string
-	const prevModule = "google.golang.org/genproto"
+	if alias, ok := genProtoAliases[path]; ok {
+		panic(fmt.Sprintf(""+
+			"duplicate registration of %q\n"+
+			"\n"+
+			"The generated definition for this file has been redirected:\n"+
+			"\tfrom: %q\n"+
+			"\tto:   %q\n"+
+			"but both packages are linked into the program.\n"+
+			"\n"+
+			"Remove the remaining dependencies on %q,\n"+
+			"or regenerate the packages that import it with the genproto_redirect option.\n",
+			path, alias.from, alias.to, alias.from))
+	}
 	const prevVersion = "cb27e3aa (May 26th, 2020)"
-	switch path {
-	case "google/protobuf/field_mask.proto":
-		prevPath = prevModule + "/protobuf/field_mask"
-	case "google/protobuf/api.proto":
-		prevPath = prevModule + "/protobuf/api"
-	case "google/protobuf/type.proto":
-		prevPath = prevModule + "/protobuf/ptype"
-	case "google/protobuf/source_context.proto":
-		prevPath = prevModule + "/protobuf/source_context"
-	default:
-		return
+	for _, pkg := range genid.GenProtoPackages {
+		if pkg.ProtoPath != path {
+			continue
+		}
+		panic(fmt.Sprintf(""+
+			"duplicate registration of %q\n"+
+			"\n"+
+			"The generated definition for this file has moved:\n"+
+			"\tfrom: %q\n"+
+			"\tto:   %q\n"+
+			"A dependency on the %q module must\n"+
+			"be at version %v or higher.\n"+
+			"\n"+
+			"Upgrade the dependency by running:\n"+
+			"\tgo get -u %v\n",
+			path, pkg.GenProtoPath, pkg.GoImportPath, genid.GenProtoModule, prevVersion, pkg.GenProtoPath))
 	}
-	pkgName := strings.TrimSuffix(strings.TrimPrefix(path, "google/protobuf/"), ".proto")
-	pkgName = strings.Replace(pkgName, "_", "", -1) + "pb" // e.g., "field_mask" => "fieldmaskpb"
-	currPath := "google.golang.org/protobuf/types/known/" + pkgName
-	panic(fmt.Sprintf(""+
-		"duplicate registration of %q\n"+
-		"\n"+
-		"The generated definition for this file has moved:\n"+
-		"\tfrom: %q\n"+
-		"\tto:   %q\n"+
-		"A dependency on the %q module must\n"+
-		"be at version %v or higher.\n"+
-		"\n"+
-		"Upgrade the dependency by running:\n"+
-		"\tgo get -u %v\n",
-		path, prevPath, currPath, prevModule, prevVersion, prevPath))
 }
 
 // FindDescriptorByName looks up a descriptor by the full name.
@@ -267,6 +299,26 @@ func (r *Files) FindDescriptorByName(name protoreflect.FullName) (protoreflect.D
 	return nil, NotFound
 }
 
+// FindFieldByJSONName looks up a field of a message by its JSON name,
+// e.g. "fooBar" for the field foo_bar, as used by the JSON mapping.
+// The message is identified by full name.
+//
+// This returns (nil, [NotFound]) if not found.
+func (r *Files) FindFieldByJSONName(message protoreflect.FullName, name string) (protoreflect.FieldDescriptor, error) {
+	d, err := r.FindDescriptorByName(message)
+	if err != nil {
+		return nil, err
+	}
+	md, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, errors.New("%v is not a message", d.FullName())
+	}
+	if fd := md.Fields().ByJSONName(name); fd != nil {
+		return fd, nil
+	}
+	return nil, NotFound
+}
+
 func findDescriptorInMessage(md protoreflect.MessageDescriptor, suffix nameSuffix) protoreflect.Descriptor {
 	name := suffix.Pop()
 	if suffix == "" {
@@ -477,6 +529,7 @@ var (
 // The Find and Range methods are safe for concurrent use.
 type Types struct {
 	typesByName         typesByName
+	typesByGoType       typesByGoType
 	extensionsByMessage extensionsByMessage
 
 	numEnums      int
@@ -486,6 +539,7 @@ type Types struct {
 
 type (
 	typesByName         map[protoreflect.FullName]any
+	typesByGoType       map[reflect.Type]protoreflect.MessageType // nil if ambiguous
 	extensionsByMessage map[protoreflect.FullName]extensionsByNumber
 	extensionsByNumber  map[protoreflect.FieldNumber]protoreflect.ExtensionType
 )
@@ -497,6 +551,10 @@ func (r *Types) RegisterMessage(mt protoreflect.MessageType) error {
 	// Under rare circumstances getting the descriptor might recursively
 	// examine the registry, so fetch it before locking.
 	md := mt.Descriptor()
+	var goType reflect.Type
+	if m := mt.Zero(); m != nil {
+		goType = reflect.TypeOf(m.Interface())
+	}
 
 	if r == GlobalTypes {
 		globalMutex.Lock()
@@ -506,6 +564,17 @@ func (r *Types) RegisterMessage(mt protoreflect.MessageType) error {
 	if err := r.register("message", md, mt); err != nil {
 		return err
 	}
+	if goType != nil {
+		if r.typesByGoType == nil {
+			r.typesByGoType = make(typesByGoType)
+		}
+		if prev, ok := r.typesByGoType[goType]; ok && (prev == nil || prev.Descriptor().FullName() != md.FullName()) {
+			// Several message types share the Go type,
+			// as is the case for the types of dynamic messages.
+			mt = nil
+		}
+		r.typesByGoType[goType] = mt
+	}
 	r.numMessages++
 	return nil
 }
@@ -657,6 +726,32 @@ func (r *Types) FindMessageByURL(url string) (protoreflect.MessageType, error) {
 	return nil, NotFound
 }
 
+// FindMessageByGoType looks up a message by the Go type of its messages,
+// which is usually a pointer to a generated struct, e.g. the type of
+// (*anypb.Any)(nil). It is an alternative to looking up the full name
+// of the message for code that only has the Go type at hand.
+//
+// This returns (nil, [NotFound]) if not found.
+// This returns an error if multiple message types use the Go type,
+// as is the case for the types of dynamic messages.
+func (r *Types) FindMessageByGoType(t reflect.Type) (protoreflect.MessageType, error) {
+	if r == nil {
+		return nil, NotFound
+	}
+	if r == GlobalTypes {
+		globalMutex.RLock()
+		defer globalMutex.RUnlock()
+	}
+	mt, ok := r.typesByGoType[t]
+	switch {
+	case !ok:
+		return nil, NotFound
+	case mt == nil:
+		return nil, errors.New("multiple message types are registered for Go type %v", t)
+	}
+	return mt, nil
+}
+
 // FindExtensionByName looks up a extension field by the field's full name.
 // Note that this is the full name of the field as determined by
 // where the extension is declared and is unrelated to the full name of the
@@ -6,6 +6,7 @@ package protoregistry_test
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -20,6 +21,7 @@ import (
 
 	testpb "google.golang.org/protobuf/internal/testprotos/registry"
 	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 func mustMakeFile(s string) protoreflect.FileDescriptor {
@@ -332,6 +334,77 @@ func TestFiles(t *testing.T) {
 	}
 }
 
+func TestGenProtoAlias(t *testing.T) {
+	const path = "registry/genproto_alias_test.proto"
+	const from = "google.golang.org/genproto/googleapis/test/alias"
+	const to = "example.com/googleapis/test/aliaspb"
+	if err := protoregistry.GlobalFiles.RegisterFile(mustMakeFile(`syntax:"proto2" name:"` + path + `" package:"test.genproto.alias1"`)); err != nil {
+		t.Fatal(err)
+	}
+	protoregistry.RegisterGenProtoAlias(path, from, to)
+
+	defer func() {
+		got := fmt.Sprint(recover())
+		for _, want := range []string{path, from, to, "genproto_redirect"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("RegisterFile() panic = %q, want it to mention %q", got, want)
+			}
+		}
+	}()
+	protoregistry.GlobalFiles.RegisterFile(mustMakeFile(`syntax:"proto2" name:"` + path + `" package:"test.genproto.alias2"`))
+}
+
+func TestFindFieldByJSONName(t *testing.T) {
+	files := new(protoregistry.Files)
+	if err := files.RegisterFile(mustMakeFile(`
+		syntax:  "proto3"
+		name:    "test.proto"
+		package: "test"
+		message_type: [{
+			name: "Message"
+			field: [
+				{name:"foo_bar" number:1 label:LABEL_OPTIONAL type:TYPE_INT32 json_name:"fooBar"},
+				{name:"custom" number:2 label:LABEL_OPTIONAL type:TYPE_INT32 json_name:"renamed"}
+			]
+			nested_type: [{name:"Nested" field:[{name:"baz_qux" number:1 label:LABEL_OPTIONAL type:TYPE_STRING json_name:"bazQux"}]}]
+		}]
+		enum_type: [{name:"Enum" value:[{name:"ZERO" number:0}]}]
+	`)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		message      protoreflect.FullName
+		name         string
+		want         protoreflect.FullName
+		wantErr      bool
+		wantNotFound bool
+	}{
+		{message: "test.Message", name: "fooBar", want: "test.Message.foo_bar"},
+		{message: "test.Message", name: "renamed", want: "test.Message.custom"},
+		{message: "test.Message.Nested", name: "bazQux", want: "test.Message.Nested.baz_qux"},
+		{message: "test.Message", name: "foo_bar", wantErr: true, wantNotFound: true},
+		{message: "test.Message", name: "custom", wantErr: true, wantNotFound: true},
+		{message: "test.NoSuchMessage", name: "fooBar", wantErr: true, wantNotFound: true},
+		{message: "test.Enum", name: "fooBar", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := files.FindFieldByJSONName(tc.message, tc.name)
+		gotErr := err != nil
+		if gotErr != tc.wantErr {
+			t.Errorf("FindFieldByJSONName(%v, %q) = (_, %v), want error? %t", tc.message, tc.name, err, tc.wantErr)
+			continue
+		}
+		if tc.wantNotFound && err != protoregistry.NotFound {
+			t.Errorf("FindFieldByJSONName(%v, %q) got error: %v, want NotFound error", tc.message, tc.name, err)
+			continue
+		}
+		if err == nil && got.FullName() != tc.want {
+			t.Errorf("FindFieldByJSONName(%v, %q) = %v, want %v", tc.message, tc.name, got.FullName(), tc.want)
+		}
+	}
+}
+
 func TestTypes(t *testing.T) {
 	mt1 := pimpl.Export{}.MessageTypeOf(&testpb.Message1{})
 	et1 := pimpl.Export{}.EnumTypeOf(testpb.Enum1_ONE)
@@ -425,6 +498,57 @@ func TestTypes(t *testing.T) {
 		}
 	})
 
+	t.Run("FindMessageByGoType", func(t *testing.T) {
+		tests := []struct {
+			goType       reflect.Type
+			messageType  protoreflect.MessageType
+			wantErr      bool
+			wantNotFound bool
+		}{{
+			goType:      reflect.TypeOf((*testpb.Message1)(nil)),
+			messageType: mt1,
+		}, {
+			goType:       reflect.TypeOf(testpb.Message1{}),
+			wantErr:      true,
+			wantNotFound: true,
+		}, {
+			goType:       reflect.TypeOf((*testpb.Message2)(nil)),
+			wantErr:      true,
+			wantNotFound: true,
+		}, {
+			goType:       reflect.TypeOf(testpb.Enum1_ONE),
+			wantErr:      true,
+			wantNotFound: true,
+		}}
+		for _, tc := range tests {
+			got, err := registry.FindMessageByGoType(tc.goType)
+			gotErr := err != nil
+			if gotErr != tc.wantErr {
+				t.Errorf("FindMessageByGoType(%v) = (_, %v), want error? %t", tc.goType, err, tc.wantErr)
+				continue
+			}
+			if tc.wantNotFound && err != protoregistry.NotFound {
+				t.Errorf("FindMessageByGoType(%v) got error: %v, want NotFound error", tc.goType, err)
+				continue
+			}
+			if got != tc.messageType {
+				t.Errorf("FindMessageByGoType(%v) got wrong value: %v", tc.goType, got)
+			}
+		}
+
+		// Dynamic messages of all types share a Go type.
+		dynamic := new(protoregistry.Types)
+		dynamic.RegisterMessage(dynamicpb.NewMessageType((&testpb.Message2{}).ProtoReflect().Descriptor()))
+		goType := reflect.TypeOf((*dynamicpb.Message)(nil))
+		if got, err := dynamic.FindMessageByGoType(goType); err != nil || got.Descriptor().FullName() != "testprotos.Message2" {
+			t.Errorf("FindMessageByGoType(%v) = (%v, %v), want testprotos.Message2", goType, got, err)
+		}
+		dynamic.RegisterMessage(dynamicpb.NewMessageType((&testpb.Message3{}).ProtoReflect().Descriptor()))
+		if _, err := dynamic.FindMessageByGoType(goType); err == nil || err == protoregistry.NotFound {
+			t.Errorf("FindMessageByGoType(%v) = (_, %v), want ambiguity error", goType, err)
+		}
+	})
+
 	t.Run("FindEnumByName", func(t *testing.T) {
 		tests := []struct {
 			name         string
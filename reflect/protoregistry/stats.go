@@ -0,0 +1,156 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoregistry
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Stats summarizes the descriptors in a set of files.
+type Stats struct {
+	Files      int
+	Messages   int // including nested messages and map entries
+	Fields     int // excluding extension fields
+	Enums      int // including nested enums
+	EnumValues int
+	Extensions int // including extensions declared within messages
+	Services   int
+	Methods    int
+
+	// Size is a rough estimate of the memory in bytes used by the
+	// descriptors, derived from the number of descriptors and
+	// the lengths of their names.
+	Size int
+}
+
+// Estimated memory overhead in bytes of each kind of descriptor,
+// excluding the storage for its names.
+//
+// Each value is the size of the corresponding struct types of the
+// internal/filedesc package on 64-bit platforms, which implement the
+// descriptors of generated and dynamically built files. For descriptors
+// that are lazily initialized, it includes the size of the lazily allocated
+// struct, such as unsafe.Sizeof(filedesc.Message{}) +
+// unsafe.Sizeof(filedesc.MessageL2{}) for messages. The filedesc package
+// cannot be imported here, so a test checks that the values match.
+const (
+	fileDescSize      = 448 + 120 // File + FileL2
+	messageDescSize   = 216 + 376 // Message + MessageL2
+	fieldDescSize     = 248       // Field
+	oneofDescSize     = 136       // Oneof
+	enumDescSize      = 72 + 176  // Enum + EnumL2
+	enumValueDescSize = 64        // EnumValue
+	extensionDescSize = 96 + 168  // Extension + ExtensionL2
+	serviceDescSize   = 56 + 56   // Service + ServiceL2
+	methodDescSize    = 96        // Method
+)
+
+// Stats reports statistics about all registered files.
+//
+// Computing the statistics fully initializes all descriptors,
+// which may itself increase the memory used by lazily initialized files.
+func (r *Files) Stats() Stats {
+	var s Stats
+	for _, fd := range r.files() {
+		s.addFile(fd)
+	}
+	return s
+}
+
+// PackageStats reports statistics about all registered files,
+// grouped by the proto package of each file.
+//
+// Computing the statistics fully initializes all descriptors,
+// which may itself increase the memory used by lazily initialized files.
+func (r *Files) PackageStats() map[protoreflect.FullName]Stats {
+	m := make(map[protoreflect.FullName]Stats)
+	for _, fd := range r.files() {
+		s := m[fd.Package()]
+		s.addFile(fd)
+		m[fd.Package()] = s
+	}
+	return m
+}
+
+// files returns all registered files.
+// Descriptors are not accessed while holding the lock since lazily
+// initializing them may require looking up dependencies in r.
+func (r *Files) files() []protoreflect.FileDescriptor {
+	var fds []protoreflect.FileDescriptor
+	r.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		fds = append(fds, fd)
+		return true
+	})
+	return fds
+}
+
+// Add adds the statistics in t to s.
+func (s *Stats) Add(t Stats) {
+	s.Files += t.Files
+	s.Messages += t.Messages
+	s.Fields += t.Fields
+	s.Enums += t.Enums
+	s.EnumValues += t.EnumValues
+	s.Extensions += t.Extensions
+	s.Services += t.Services
+	s.Methods += t.Methods
+	s.Size += t.Size
+}
+
+func (s *Stats) addFile(fd protoreflect.FileDescriptor) {
+	s.Files++
+	s.Size += fileDescSize + len(fd.Path()) + len(fd.Package())
+	s.addEnums(fd.Enums())
+	s.addMessages(fd.Messages())
+	s.addExtensions(fd.Extensions())
+	for i := 0; i < fd.Services().Len(); i++ {
+		sd := fd.Services().Get(i)
+		s.Services++
+		s.Size += serviceDescSize + len(sd.FullName())
+		for j := 0; j < sd.Methods().Len(); j++ {
+			s.Methods++
+			s.Size += methodDescSize + len(sd.Methods().Get(j).FullName())
+		}
+	}
+}
+
+func (s *Stats) addMessages(mds protoreflect.MessageDescriptors) {
+	for i := 0; i < mds.Len(); i++ {
+		md := mds.Get(i)
+		s.Messages++
+		s.Size += messageDescSize + len(md.FullName())
+		for j := 0; j < md.Fields().Len(); j++ {
+			fd := md.Fields().Get(j)
+			s.Fields++
+			s.Size += fieldDescSize + len(fd.FullName()) + len(fd.JSONName())
+		}
+		for j := 0; j < md.Oneofs().Len(); j++ {
+			s.Size += oneofDescSize + len(md.Oneofs().Get(j).FullName())
+		}
+		s.addEnums(md.Enums())
+		s.addMessages(md.Messages())
+		s.addExtensions(md.Extensions())
+	}
+}
+
+func (s *Stats) addEnums(eds protoreflect.EnumDescriptors) {
+	for i := 0; i < eds.Len(); i++ {
+		ed := eds.Get(i)
+		s.Enums++
+		s.Size += enumDescSize + len(ed.FullName())
+		for j := 0; j < ed.Values().Len(); j++ {
+			s.EnumValues++
+			s.Size += enumValueDescSize + len(ed.Values().Get(j).FullName())
+		}
+	}
+}
+
+func (s *Stats) addExtensions(xds protoreflect.ExtensionDescriptors) {
+	for i := 0; i < xds.Len(); i++ {
+		xd := xds.Get(i)
+		s.Extensions++
+		s.Size += extensionDescSize + len(xd.FullName()) + len(xd.JSONName())
+	}
+}
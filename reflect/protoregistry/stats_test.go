@@ -0,0 +1,177 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoregistry_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"google.golang.org/protobuf/internal/filedesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func TestStats(t *testing.T) {
+	files := new(protoregistry.Files)
+	for _, fd := range []protoreflect.FileDescriptor{
+		mustMakeFile(`
+			syntax: "proto2"
+			name: "a.proto"
+			package: "foo"
+			message_type: [{
+				name: "M"
+				field: [
+					{name: "a" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32},
+					{name: "b" number: 2 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".foo.M.E"}
+				]
+				enum_type: [{name: "E" value: [{name: "E0" number: 0}, {name: "E1" number: 1}]}]
+				nested_type: [{name: "N"}]
+				extension_range: [{start: 10 end: 20}]
+				extension: [{name: "x" number: 10 label: LABEL_OPTIONAL type: TYPE_STRING extendee: ".foo.M"}]
+			}]
+			service: [{
+				name: "S"
+				method: [
+					{name: "A" input_type: ".foo.M" output_type: ".foo.M"},
+					{name: "B" input_type: ".foo.M" output_type: ".foo.M"}
+				]
+			}]
+		`),
+		mustMakeFile(`syntax: "proto2" name: "b.proto" package: "foo" enum_type: [{name: "F" value: [{name: "F0" number: 0}]}]`),
+		mustMakeFile(`syntax: "proto2" name: "c.proto" package: "bar" message_type: [{name: "M"}]`),
+	} {
+		if err := files.RegisterFile(fd); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ignoreSize := cmpopts.IgnoreFields(protoregistry.Stats{}, "Size")
+	want := map[protoreflect.FullName]protoregistry.Stats{
+		"foo": {Files: 2, Messages: 2, Fields: 2, Enums: 2, EnumValues: 3, Extensions: 1, Services: 1, Methods: 2},
+		"bar": {Files: 1, Messages: 1},
+	}
+	got := files.PackageStats()
+	if diff := cmp.Diff(want, got, ignoreSize); diff != "" {
+		t.Errorf("PackageStats() mismatch (-want +got):\n%s", diff)
+	}
+
+	var sum protoregistry.Stats
+	for _, s := range got {
+		if s.Size <= 0 {
+			t.Errorf("PackageStats() reports a non-positive size: %+v", s)
+		}
+		sum.Add(s)
+	}
+	if diff := cmp.Diff(sum, files.Stats()); diff != "" {
+		t.Errorf("Stats() does not match the sum of PackageStats() (-want +got):\n%s", diff)
+	}
+
+	if got := (*protoregistry.Files)(nil).Stats(); got != (protoregistry.Stats{}) {
+		t.Errorf("Stats() of nil registry = %+v, want zero", got)
+	}
+	if got := protoregistry.GlobalFiles.Stats(); got.Files != protoregistry.GlobalFiles.NumFiles() {
+		t.Errorf("GlobalFiles.Stats().Files = %v, want %v", got.Files, protoregistry.GlobalFiles.NumFiles())
+	}
+}
+
+// TestStatsDescriptorSizes checks that the estimated size of each kind of
+// descriptor matches the size of the internal/filedesc types implementing it.
+// Each test case adds a descriptor to a base file; the difference between the
+// estimated sizes of the files, excluding the names, is the descriptor size.
+func TestStatsDescriptorSizes(t *testing.T) {
+	if unsafe.Sizeof(uintptr(0)) != 8 {
+		t.Skip("descriptor sizes are estimated for 64-bit platforms")
+	}
+	const (
+		file    = `syntax: "proto2" name: "a.proto" package: "p"`
+		message = file + ` message_type: [{name: "M" extension_range: [{start: 10 end: 20}]}]`
+		field   = file + ` message_type: [{name: "M" field: [{name: "f" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32}]}]`
+		oneof   = file + ` message_type: [{name: "M" field: [{name: "f" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32 oneof_index: 0}] oneof_decl: [{name: "o"}]}]`
+		enum    = file + ` enum_type: [{name: "E" value: [{name: "V" number: 0}]}]`
+		service = message + ` service: [{name: "S"}]`
+	)
+	tests := []struct {
+		desc     string
+		base     string
+		file     string
+		names    []string
+		wantSize uintptr
+	}{{
+		desc:     "file",
+		file:     file,
+		names:    []string{"a.proto", "p"},
+		wantSize: unsafe.Sizeof(filedesc.File{}) + unsafe.Sizeof(filedesc.FileL2{}),
+	}, {
+		desc:     "message",
+		base:     file,
+		file:     message,
+		names:    []string{"p.M"},
+		wantSize: unsafe.Sizeof(filedesc.Message{}) + unsafe.Sizeof(filedesc.MessageL2{}),
+	}, {
+		desc:     "field",
+		base:     file + ` message_type: [{name: "M"}]`,
+		file:     field,
+		names:    []string{"p.M.f", "f"},
+		wantSize: unsafe.Sizeof(filedesc.Field{}),
+	}, {
+		desc:     "oneof",
+		base:     field,
+		file:     oneof,
+		names:    []string{"p.M.o"},
+		wantSize: unsafe.Sizeof(filedesc.Oneof{}),
+	}, {
+		desc:     "enum",
+		base:     enum,
+		file:     file + ` enum_type: [{name: "E" value: [{name: "V" number: 0}]}, {name: "F" value: [{name: "W" number: 0}]}]`,
+		names:    []string{"p.F", "p.W"},
+		wantSize: unsafe.Sizeof(filedesc.Enum{}) + unsafe.Sizeof(filedesc.EnumL2{}) + unsafe.Sizeof(filedesc.EnumValue{}),
+	}, {
+		desc:     "enum value",
+		base:     enum,
+		file:     file + ` enum_type: [{name: "E" value: [{name: "V" number: 0}, {name: "W" number: 1}]}]`,
+		names:    []string{"p.W"},
+		wantSize: unsafe.Sizeof(filedesc.EnumValue{}),
+	}, {
+		desc:     "extension",
+		base:     message,
+		file:     message + ` extension: [{name: "x" number: 10 label: LABEL_OPTIONAL type: TYPE_INT32 extendee: ".p.M"}]`,
+		names:    []string{"p.x", "[p.x]"},
+		wantSize: unsafe.Sizeof(filedesc.Extension{}) + unsafe.Sizeof(filedesc.ExtensionL2{}),
+	}, {
+		desc:     "service",
+		base:     message,
+		file:     service,
+		names:    []string{"p.S"},
+		wantSize: unsafe.Sizeof(filedesc.Service{}) + unsafe.Sizeof(filedesc.ServiceL2{}),
+	}, {
+		desc:     "method",
+		base:     service,
+		file:     message + ` service: [{name: "S" method: [{name: "A" input_type: ".p.M" output_type: ".p.M"}]}]`,
+		names:    []string{"p.S.A"},
+		wantSize: unsafe.Sizeof(filedesc.Method{}),
+	}}
+	size := func(s string) int {
+		if s == "" {
+			return 0
+		}
+		files := new(protoregistry.Files)
+		if err := files.RegisterFile(mustMakeFile(s)); err != nil {
+			t.Fatal(err)
+		}
+		return files.Stats().Size
+	}
+	for _, tt := range tests {
+		got := size(tt.file) - size(tt.base)
+		for _, name := range tt.names {
+			got -= len(name)
+		}
+		if got != int(tt.wantSize) {
+			t.Errorf("estimated size of a %v = %v, want %v", tt.desc, got, tt.wantSize)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protoallocs reports the heap allocations of the marshal and
+// unmarshal operations of the proto package by message type.
+// It is a debugging aid to find the message types that dominate the
+// allocations of a program without the use of a profiler.
+//
+// Allocations are only recorded in programs built with the "protoallocs"
+// build tag, since recording the allocations of an operation stops the world.
+// Otherwise, the report is always empty.
+//
+// The allocations of an operation are those of the whole program while it
+// runs, so they are only accurate if no other goroutines allocate meanwhile.
+// Only operations started by [proto.Marshal], [proto.Unmarshal], and the
+// corresponding methods of [proto.MarshalOptions] and [proto.UnmarshalOptions]
+// are recorded, and the allocations of nested messages are attributed to
+// the message type of the operation.
+package protoallocs
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/internal/allocstats"
+	"google.golang.org/protobuf/internal/flags"
+)
+
+// Enabled reports whether allocations are recorded,
+// which is the case if the program is built with the "protoallocs" tag.
+const Enabled = flags.ProtoAllocs
+
+// Op is the kind of a recorded operation.
+type Op = allocstats.Op
+
+const (
+	Marshal   Op = allocstats.Marshal
+	Unmarshal Op = allocstats.Unmarshal
+)
+
+// Stats are the allocations of the operations of one kind
+// on messages of one type.
+type Stats = allocstats.Stats
+
+// Report returns the statistics recorded since the start of the program
+// or the last call to Reset, ordered by decreasing number of bytes allocated.
+func Report() []Stats {
+	ss := allocstats.Snapshot()
+	sort.Slice(ss, func(i, j int) bool {
+		switch {
+		case ss[i].Bytes != ss[j].Bytes:
+			return ss[i].Bytes > ss[j].Bytes
+		case ss[i].MessageName != ss[j].MessageName:
+			return ss[i].MessageName < ss[j].MessageName
+		default:
+			return ss[i].Op < ss[j].Op
+		}
+	})
+	return ss
+}
+
+// Reset discards the recorded statistics.
+func Reset() {
+	allocstats.Reset()
+}
+
+// WriteReport writes the statistics returned by Report to w as a table,
+// including the average allocations per operation.
+func WriteReport(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "MESSAGE\tOP\tCALLS\tALLOCS\tBYTES\tALLOCS/OP\tBYTES/OP\t")
+	for _, s := range Report() {
+		fmt.Fprintf(tw, "%s\t%v\t%d\t%d\t%d\t%d\t%d\t\n",
+			s.MessageName, s.Op, s.Calls, s.Allocs, s.Bytes, s.Allocs/s.Calls, s.Bytes/s.Calls)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protoallocs_test
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protoallocs"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+func TestReport(t *testing.T) {
+	protoallocs.Reset()
+	m := &testpb.TestAllTypes{
+		OptionalString:        proto.String("hello"),
+		RepeatedInt32:         []int32{1, 2, 3},
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)},
+	}
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := proto.Unmarshal(b, new(testpb.TestAllTypes)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := protoallocs.Report()
+	if !protoallocs.Enabled {
+		if len(got) > 0 {
+			t.Errorf("Report() = %v, want empty report without the protoallocs tag", got)
+		}
+		return
+	}
+	calls := map[protoallocs.Op]uint64{}
+	for _, s := range got {
+		if s.MessageName != m.ProtoReflect().Descriptor().FullName() {
+			t.Errorf("Report() contains unexpected message %v", s.MessageName)
+			continue
+		}
+		calls[s.Op] = s.Calls
+		if s.Op == protoallocs.Unmarshal && s.Allocs == 0 {
+			t.Errorf("Report() contains no allocations for %v", s.Op)
+		}
+	}
+	if calls[protoallocs.Marshal] != 1 || calls[protoallocs.Unmarshal] != 2 {
+		t.Errorf("Report() calls = %v, want 1 marshal and 2 unmarshal", calls)
+	}
+
+	var sb strings.Builder
+	if err := protoallocs.WriteReport(&sb); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sb.String(), "goproto.proto.test.TestAllTypes") {
+		t.Errorf("WriteReport() = %q, want it to contain the message name", sb.String())
+	}
+
+	protoallocs.Reset()
+	if got := protoallocs.Report(); len(got) > 0 {
+		t.Errorf("Report() after Reset = %v, want empty report", got)
+	}
+}
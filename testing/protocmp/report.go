@@ -0,0 +1,171 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocmp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/internal/linediff"
+)
+
+// TextReporter is a reporter for use with [cmp.Reporter] that renders
+// differences between messages as a unified diff of their text format,
+// preceded by the paths of the differing fields.
+//
+// Example usage:
+//
+//	var r protocmp.TextReporter
+//	if !cmp.Equal(want, got, protocmp.Transform(), cmp.Reporter(&r)) {
+//		t.Errorf("mismatch (-want +got):\n%s", r.String())
+//	}
+//
+// The diff shows the complete text of the messages that contain differences,
+// so differences that were ignored by other options may also appear in it.
+// The listed field paths only include reported differences.
+//
+// This must be used in conjunction with [Transform].
+type TextReporter struct {
+	path  cmp.Path
+	diffs []*textDiff
+}
+
+// textDiff records the differences within a pair of top-level messages.
+type textDiff struct {
+	root   string // path to the messages, or empty for the top-level values
+	vx, vy reflect.Value
+	fields []string
+}
+
+// PushStep implements the Reporter interface of [cmp.Reporter].
+func (r *TextReporter) PushStep(ps cmp.PathStep) {
+	r.path = append(r.path, ps)
+}
+
+// PopStep implements the Reporter interface of [cmp.Reporter].
+func (r *TextReporter) PopStep() {
+	r.path = r.path[:len(r.path)-1]
+}
+
+// Report implements the Reporter interface of [cmp.Reporter].
+func (r *TextReporter) Report(rs cmp.Result) {
+	if rs.Equal() {
+		return
+	}
+
+	// Find the outermost message that contains the difference.
+	i := 0
+	for i < len(r.path) && r.path[i].Type() != messageReflectType {
+		i++
+	}
+	if i == len(r.path) {
+		vx, vy := r.path.Last().Values()
+		r.diffs = append(r.diffs, &textDiff{root: r.path.GoString(), vx: vx, vy: vy})
+		return
+	}
+	var root string
+	if i > 1 {
+		root = r.path[:i].GoString()
+	}
+	var d *textDiff
+	if n := len(r.diffs); n > 0 && r.diffs[n-1].root == root && r.diffs[n-1].fields != nil {
+		d = r.diffs[n-1]
+	} else {
+		vx, vy := r.path[i].Values()
+		d = &textDiff{root: root, vx: vx, vy: vy, fields: []string{}}
+		r.diffs = append(r.diffs, d)
+	}
+	if f := fieldPath(r.path[i:]); f != "" {
+		d.fields = append(d.fields, f)
+	}
+}
+
+// fieldPath formats the path steps following a message as a field path
+// (e.g., "foo.bar[2].baz").
+func fieldPath(p cmp.Path) string {
+	var b strings.Builder
+	for j := 1; j < len(p); j++ {
+		switch s := p[j].(type) {
+		case cmp.MapIndex:
+			if p[j-1].Type() == messageReflectType {
+				if b.Len() > 0 {
+					b.WriteByte('.')
+				}
+				b.WriteString(s.Key().String())
+			} else {
+				k := s.Key().Interface()
+				if str, ok := k.(string); ok {
+					fmt.Fprintf(&b, "[%q]", str)
+				} else {
+					fmt.Fprintf(&b, "[%v]", k)
+				}
+			}
+		case cmp.SliceIndex:
+			// Report the index in x, unless the element only exists in y.
+			kx, ky := s.SplitKeys()
+			if kx < 0 {
+				kx = ky
+			}
+			fmt.Fprintf(&b, "[%d]", kx)
+		}
+	}
+	return b.String()
+}
+
+// String returns the differences reported so far.
+func (r *TextReporter) String() string {
+	var b strings.Builder
+	for _, d := range r.diffs {
+		if d.fields == nil {
+			fmt.Fprintf(&b, "%s:\n\t-: %v\n\t+: %v\n", d.root, formatValue(d.vx), formatValue(d.vy))
+			continue
+		}
+		if d.root != "" {
+			fmt.Fprintf(&b, "%s:\n", d.root)
+		}
+		if len(d.fields) > 0 {
+			fmt.Fprintf(&b, "differing fields: %s\n", strings.Join(d.fields, ", "))
+		}
+		b.WriteString(linediff.Unified("x", "y", linediff.Lines(textLines(d.vx), textLines(d.vy)), diffContext))
+	}
+	return b.String()
+}
+
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<missing>"
+	}
+	if !v.CanInterface() {
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// textLines returns the lines of the text format of a transformed message.
+func textLines(v reflect.Value) []string {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	m, _ := v.Interface().(Message)
+	if m == nil {
+		return nil
+	}
+	pm := m.Unwrap()
+	if pm == nil {
+		return nil
+	}
+	b, err := prototext.MarshalOptions{Multiline: true, Indent: "  ", AllowPartial: true, EmitUnknown: true}.Marshal(pm)
+	if err != nil {
+		return []string{fmt.Sprintf("<%v>\n", err)}
+	}
+	return linediff.Split(string(b))
+}
+
+// diffContext is the number of unchanged lines around each change.
+const diffContext = 3
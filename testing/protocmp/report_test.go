@@ -0,0 +1,145 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protocmp
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/internal/detrand"
+	"google.golang.org/protobuf/proto"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+)
+
+type reportWrapper struct {
+	M *testpb.TestAllTypes
+	N int
+}
+
+func TestTextReporter(t *testing.T) {
+	detrand.Disable()
+
+	x := &testpb.TestAllTypes{
+		OptionalInt32:  proto.Int32(1),
+		OptionalString: proto.String("a"),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			A:           proto.Int32(5),
+			Corecursive: &testpb.TestAllTypes{OptionalBool: proto.Bool(true)},
+		},
+		RepeatedInt32:   []int32{1, 2, 3},
+		MapStringString: map[string]string{"a": "b"},
+	}
+	y := proto.Clone(x).(*testpb.TestAllTypes)
+	y.OptionalString = proto.String("b")
+	y.OptionalNestedMessage.Corecursive.OptionalBool = proto.Bool(false)
+	y.RepeatedInt32 = []int32{1, 3}
+	y.MapStringString["a"] = "z"
+
+	tests := []struct {
+		desc string
+		x, y any
+		opts cmp.Options
+		want string
+	}{{
+		desc: "equal",
+		x:    x,
+		y:    proto.Clone(x),
+		want: "",
+	}, {
+		desc: "nested differences",
+		x:    x,
+		y:    y,
+		want: `differing fields: map_string_string["a"], optional_nested_message.corecursive.optional_bool, optional_string, repeated_int32[1]
+--- x
++++ y
+@@ -1,15 +1,14 @@
+ optional_int32: 1
+-optional_string: "a"
++optional_string: "b"
+ optional_nested_message: {
+   a: 5
+   corecursive: {
+-    optional_bool: true
++    optional_bool: false
+   }
+ }
+ repeated_int32: 1
+-repeated_int32: 2
+ repeated_int32: 3
+ map_string_string: {
+   key: "a"
+-  value: "b"
++  value: "z"
+ }
+`,
+	}, {
+		desc: "separate hunks",
+		x: &testpb.TestAllTypes{
+			OptionalInt32:  proto.Int32(1),
+			RepeatedString: []string{"a", "b", "c", "d", "e", "f", "g", "h"},
+			DefaultInt32:   proto.Int32(7),
+		},
+		y: &testpb.TestAllTypes{
+			OptionalInt32:  proto.Int32(2),
+			RepeatedString: []string{"a", "b", "c", "d", "e", "f", "g", "h"},
+		},
+		want: `differing fields: default_int32, optional_int32
+--- x
++++ y
+@@ -1,4 +1,4 @@
+-optional_int32: 1
++optional_int32: 2
+ repeated_string: "a"
+ repeated_string: "b"
+ repeated_string: "c"
+@@ -7,4 +7,3 @@
+ repeated_string: "f"
+ repeated_string: "g"
+ repeated_string: "h"
+-default_int32: 7
+`,
+	}, {
+		desc: "messages within a struct",
+		x:    reportWrapper{M: x, N: 1},
+		y:    reportWrapper{M: &testpb.TestAllTypes{}, N: 2},
+		want: `{protocmp.reportWrapper}.M:
+differing fields: map_string_string, optional_int32, optional_nested_message, optional_string, repeated_int32
+--- x
++++ y
+@@ -1,15 +0,0 @@
+-optional_int32: 1
+-optional_string: "a"
+-optional_nested_message: {
+-  a: 5
+-  corecursive: {
+-    optional_bool: true
+-  }
+-}
+-repeated_int32: 1
+-repeated_int32: 2
+-repeated_int32: 3
+-map_string_string: {
+-  key: "a"
+-  value: "b"
+-}
+{protocmp.reportWrapper}.N:
+	-: 1
+	+: 2
+`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var r TextReporter
+			cmp.Equal(tt.x, tt.y, append(tt.opts, Transform(), cmp.Reporter(&r))...)
+			got := r.String()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("String() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
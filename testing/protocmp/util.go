@@ -14,6 +14,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 
+	"google.golang.org/protobuf/internal/genid"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
@@ -129,6 +130,47 @@ func IgnoreMessages(messages ...proto.Message) cmp.Option {
 	return IgnoreDescriptors(ds...)
 }
 
+// IgnoreMessagesOf ignores all messages of the types named by the specified
+// type URLs, wherever they appear in the message tree, including as the
+// contents of a google.protobuf.Any message or as a message set item.
+// A type URL may also be given as a message's full name.
+// Unlike [IgnoreMessages], it does not require the message types to be linked
+// into the program.
+//
+// This must be used in conjunction with [Transform].
+func IgnoreMessagesOf(typeURLs ...string) cmp.Option {
+	f := &nameFilters{names: make(map[protoreflect.FullName]bool)}
+	for _, s := range typeURLs {
+		f.names[mustParseTypeURL(s)] = true
+	}
+	return cmp.FilterPath(f.Filter, cmp.Ignore())
+}
+
+// IgnoreAnyOf ignores all google.protobuf.Any messages whose contents are of
+// the types named by the specified type URLs, regardless of the prefix of the
+// type URL held by the Any message. A type URL may also be given as a message's
+// full name. Any messages are ignored even if their contents cannot be
+// unmarshaled because the type is not resolvable.
+//
+// This must be used in conjunction with [Transform].
+func IgnoreAnyOf(typeURLs ...string) cmp.Option {
+	f := &nameFilters{anyNames: make(map[protoreflect.FullName]bool)}
+	for _, s := range typeURLs {
+		f.anyNames[mustParseTypeURL(s)] = true
+	}
+	return cmp.FilterPath(f.Filter, cmp.Ignore())
+}
+
+// mustParseTypeURL returns the message full name named by the type URL s,
+// which is the part of it after the last slash.
+func mustParseTypeURL(s string) protoreflect.FullName {
+	name := protoreflect.FullName(s[strings.LastIndexByte(s, '/')+1:])
+	if !name.IsValid() {
+		panic(fmt.Sprintf("invalid type URL %q", s))
+	}
+	return name
+}
+
 // IgnoreFields ignores the specified fields in the specified message.
 // It is equivalent to [FilterField](message, name, [cmp.Ignore]()) for each field
 // in the message.
@@ -229,6 +271,10 @@ func findDescriptor(md protoreflect.MessageDescriptor, s protoreflect.Name) prot
 
 type nameFilters struct {
 	names map[protoreflect.FullName]bool
+
+	// anyNames are the full names of the types whose
+	// google.protobuf.Any messages are filtered.
+	anyNames map[protoreflect.FullName]bool
 }
 
 func newNameFilters(descs ...protoreflect.Descriptor) *nameFilters {
@@ -320,10 +366,22 @@ func (f *nameFilters) filterFieldValue(v reflect.Value) bool {
 		return f.filterValue(v)
 	case t.Kind() == reflect.Slice && (t.Elem() == enumReflectType || t.Elem() == messageReflectType):
 		// Check for list field of enum or message type.
-		return f.filterValue(v.Index(0))
+		// The elements of a list of google.protobuf.Any messages may be
+		// filtered individually, so every element must be checked.
+		for i := 0; i < v.Len(); i++ {
+			if !f.filterValue(v.Index(i)) {
+				return false
+			}
+		}
+		return true
 	case t.Kind() == reflect.Map && (t.Elem() == enumReflectType || t.Elem() == messageReflectType):
 		// Check for map field of enum or message type.
-		return f.filterValue(v.MapIndex(v.MapKeys()[0]))
+		for _, k := range v.MapKeys() {
+			if !f.filterValue(v.MapIndex(k)) {
+				return false
+			}
+		}
+		return true
 	}
 	return false
 }
@@ -339,7 +397,17 @@ func (f *nameFilters) filterValue(v reflect.Value) bool {
 	case Enum:
 		return v.Descriptor() != nil && f.names[v.Descriptor().FullName()]
 	case Message:
-		return v.Descriptor() != nil && f.names[v.Descriptor().FullName()]
+		md := v.Descriptor()
+		if md == nil {
+			return false
+		}
+		if len(f.anyNames) > 0 && md.FullName() == genid.Any_message_fullname {
+			s, _ := v[string(genid.Any_TypeUrl_field_name)].(string)
+			if s != "" && f.anyNames[protoreflect.FullName(s[strings.LastIndexByte(s, '/')+1:])] {
+				return true
+			}
+		}
+		return f.names[md.FullName()]
 	}
 	return false
 }
@@ -16,8 +16,10 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/testing/protopack"
 	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
 
 	testpb "google.golang.org/protobuf/internal/testprotos/test"
+	textpb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
 )
 
 func TestEqual(t *testing.T) {
@@ -236,6 +238,33 @@ func TestEqual(t *testing.T) {
 		y:    apply(dynamicpb.NewMessage(allTypesDesc), setField{6, int64(5)}),
 		opts: cmp.Options{Transform(), IgnoreUnknown()},
 		want: true,
+	}, {
+		// Unknown fields in nested messages, as retained by older binaries.
+		x: &testpb.TestAllTypes{
+			OptionalNestedMessage: apply(&testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)}, setUnknown{raw}).(*testpb.TestAllTypes_NestedMessage),
+			RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{
+				apply(&testpb.TestAllTypes_NestedMessage{}, setUnknown{raw}).(*testpb.TestAllTypes_NestedMessage),
+			},
+			MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{
+				"k": apply(&testpb.TestAllTypes_NestedMessage{}, setUnknown{raw}).(*testpb.TestAllTypes_NestedMessage),
+			},
+		},
+		y: &testpb.TestAllTypes{
+			OptionalNestedMessage:  &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)},
+			RepeatedNestedMessage:  []*testpb.TestAllTypes_NestedMessage{{}},
+			MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{"k": {}},
+		},
+		opts: cmp.Options{Transform(), IgnoreUnknown()},
+		want: true,
+	}, {
+		// Unknown fields and explicitly set default scalars together.
+		x: apply(&testpb.TestAllTypes{
+			OptionalInt32:  proto.Int32(0),
+			OptionalSint64: proto.Int64(5),
+		}, setUnknown{raw}),
+		y:    &testpb.TestAllTypes{OptionalSint64: proto.Int64(5)},
+		opts: cmp.Options{Transform(), IgnoreUnknown(), IgnoreDefaultScalars()},
+		want: true,
 	}}...)
 
 	// Test IgnoreDefaultScalars.
@@ -522,6 +551,81 @@ func TestEqual(t *testing.T) {
 		want: false,
 	}}...)
 
+	// Test IgnoreMessagesOf and IgnoreAnyOf.
+	newAny := func(typeURL string, m proto.Message) *anypb.Any {
+		b, err := proto.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &anypb.Any{TypeUrl: typeURL, Value: b}
+	}
+	const nestedURL = "type.googleapis.com/goproto.proto.test.TestAllTypes.NestedMessage"
+	nested1 := &testpb.TestAllTypes_NestedMessage{A: proto.Int32(1)}
+	nested2 := &testpb.TestAllTypes_NestedMessage{A: proto.Int32(2)}
+	foreign1 := &testpb.ForeignMessage{C: proto.Int32(1)}
+	foreign2 := &testpb.ForeignMessage{C: proto.Int32(2)}
+	tests = append(tests, []test{{
+		x:    &testpb.TestAllTypes{OptionalNestedMessage: nested1},
+		y:    &testpb.TestAllTypes{},
+		opts: cmp.Options{Transform(), IgnoreMessagesOf(nestedURL)},
+		want: true,
+	}, {
+		x:    &testpb.TestAllTypes{RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{nested1}},
+		y:    &testpb.TestAllTypes{RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{nested2}},
+		opts: cmp.Options{Transform(), IgnoreMessagesOf("goproto.proto.test.TestAllTypes.NestedMessage")},
+		want: true,
+	}, {
+		x:    &textpb2.KnownTypes{OptAny: newAny(nestedURL, nested1)},
+		y:    &textpb2.KnownTypes{OptAny: newAny(nestedURL, nested2)},
+		opts: cmp.Options{Transform()},
+		want: false,
+	}, {
+		x:    &textpb2.KnownTypes{OptAny: newAny(nestedURL, nested1)},
+		y:    &textpb2.KnownTypes{OptAny: newAny(nestedURL, nested2)},
+		opts: cmp.Options{Transform(), IgnoreMessagesOf(nestedURL)},
+		want: true,
+	}, {
+		x:    &textpb2.KnownTypes{OptAny: newAny(nestedURL, nested1)},
+		y:    &textpb2.KnownTypes{OptAny: newAny("example.com/goproto.proto.test.TestAllTypes.NestedMessage", nested2)},
+		opts: cmp.Options{Transform(), IgnoreMessagesOf(nestedURL)},
+		want: false,
+	}, {
+		x:    &textpb2.KnownTypes{OptAny: newAny(nestedURL, nested1)},
+		y:    &textpb2.KnownTypes{OptAny: newAny("example.com/goproto.proto.test.TestAllTypes.NestedMessage", nested2)},
+		opts: cmp.Options{Transform(), IgnoreAnyOf(nestedURL)},
+		want: true,
+	}, {
+		x:    &textpb2.KnownTypes{OptAny: newAny(nestedURL, nested1)},
+		y:    &textpb2.KnownTypes{},
+		opts: cmp.Options{Transform(), IgnoreAnyOf(nestedURL)},
+		want: true,
+	}, {
+		x:    &textpb2.KnownTypes{OptAny: newAny(nestedURL, nested1)},
+		y:    &textpb2.KnownTypes{OptAny: newAny(nestedURL, foreign1)},
+		opts: cmp.Options{Transform(), IgnoreAnyOf(nestedURL)},
+		want: true,
+	}, {
+		x:    &textpb2.KnownTypes{OptAny: newAny("foo/unknown.Type", nested1)},
+		y:    &textpb2.KnownTypes{OptAny: newAny("foo/unknown.Type", nested2)},
+		opts: cmp.Options{Transform(), IgnoreAnyOf("unknown.Type")},
+		want: true,
+	}, {
+		x:    &textpb2.KnownTypes{OptAny: newAny(nestedURL, nested1)},
+		y:    &textpb2.KnownTypes{OptAny: newAny(nestedURL, nested2)},
+		opts: cmp.Options{Transform(), IgnoreAnyOf("goproto.proto.test.ForeignMessage")},
+		want: false,
+	}, {
+		x:    []*anypb.Any{newAny(nestedURL, nested1), newAny("type.googleapis.com/goproto.proto.test.ForeignMessage", foreign1)},
+		y:    []*anypb.Any{newAny(nestedURL, nested2), newAny("type.googleapis.com/goproto.proto.test.ForeignMessage", foreign1)},
+		opts: cmp.Options{Transform(), IgnoreAnyOf(nestedURL)},
+		want: true,
+	}, {
+		x:    []*anypb.Any{newAny(nestedURL, nested1), newAny("type.googleapis.com/goproto.proto.test.ForeignMessage", foreign1)},
+		y:    []*anypb.Any{newAny(nestedURL, nested2), newAny("type.googleapis.com/goproto.proto.test.ForeignMessage", foreign2)},
+		opts: cmp.Options{Transform(), IgnoreAnyOf(nestedURL)},
+		want: false,
+	}}...)
+
 	// Test IgnoreFields and IgnoreOneofs.
 	tests = append(tests, []test{{
 		x:    &testpb.TestAllTypes{OptionalInt32: proto.Int32(5)},
@@ -0,0 +1,168 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prototest
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Fuzz performs round-trip tests on a [protoreflect.MessageType]
+// implementation using inputs from the fuzzing engine.
+//
+// Each input is unmarshaled as the wire encoding of a message, and inputs
+// that fail to unmarshal are skipped. The message is then checked to survive
+// round-trips through the wire, text, and JSON formats, and to be copied and
+// cleared correctly through the [protoreflect.Message] interface.
+// Since the text and JSON formats cannot represent unknown fields,
+// they are discarded before those round-trips. Messages that cannot be
+// represented in a format at all (e.g., invalid UTF-8 in a proto2 string
+// for JSON) skip the round-trip for that format.
+//
+// Fuzz adds a seed corpus of populated messages to f.
+// Additional seeds may be added with [testing.F.Add] before calling Fuzz.
+//
+// Example usage:
+//
+//	func FuzzMessage(f *testing.F) {
+//		prototest.Message{}.Fuzz(f, (*foopb.Message)(nil).ProtoReflect().Type())
+//	}
+func (test Message) Fuzz(f *testing.F, mt protoreflect.MessageType) {
+	if test.Resolver == nil {
+		test.Resolver = protoregistry.GlobalTypes
+	}
+	md := mt.Descriptor()
+	var extTypes []protoreflect.ExtensionType
+	test.Resolver.RangeExtensionsByMessage(md.FullName(), func(e protoreflect.ExtensionType) bool {
+		extTypes = append(extTypes, e)
+		return true
+	})
+
+	f.Add([]byte{})
+	for _, n := range []seed{1, 2} {
+		m := mt.New()
+		populateMessage(m, n, nil)
+		for _, xt := range extTypes {
+			m.Set(xt.TypeDescriptor(), newValue(m, xt.TypeDescriptor(), n, nil))
+		}
+		b, err := proto.MarshalOptions{AllowPartial: true, Deterministic: true}.Marshal(m.Interface())
+		if err != nil {
+			f.Fatalf("Marshal() = %v, want nil\n%v", err, prototext.Format(m.Interface()))
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		test.fuzz(t, mt, b)
+	})
+}
+
+func (test Message) fuzz(t testing.TB, mt protoreflect.MessageType, b []byte) {
+	var resolver interface {
+		protoregistry.ExtensionTypeResolver
+		protoregistry.MessageTypeResolver
+	} = protoregistry.GlobalTypes
+	if r, ok := test.Resolver.(interface {
+		protoregistry.ExtensionTypeResolver
+		protoregistry.MessageTypeResolver
+	}); ok {
+		resolver = r
+	}
+
+	m := mt.New().Interface()
+	if err := (proto.UnmarshalOptions{AllowPartial: true, Resolver: test.Resolver}).Unmarshal(b, m); err != nil {
+		return
+	}
+
+	// Test round-trip through the wire format.
+	b1, err := proto.MarshalOptions{AllowPartial: true}.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal() = %v, want nil\n%v", err, prototext.Format(m))
+	}
+	if size := proto.Size(m); size != len(b1) {
+		t.Errorf("Size() = %v, want %v\n%v", size, len(b1), prototext.Format(m))
+	}
+	m2 := mt.New().Interface()
+	if err := (proto.UnmarshalOptions{AllowPartial: true, Resolver: test.Resolver}).Unmarshal(b1, m2); err != nil {
+		t.Fatalf("Unmarshal() = %v, want nil\n%v", err, prototext.Format(m))
+	}
+	if !proto.Equal(m, m2) {
+		t.Errorf("round-trip through the wire format did not preserve message\nOriginal:\n%v\nNew:\n%v", prototext.Format(m), prototext.Format(m2))
+	}
+
+	// Test round-trip through the text and JSON formats,
+	// which do not preserve unknown fields.
+	known := proto.Clone(m)
+	discardUnknown(known.ProtoReflect())
+	if b, err := (prototext.MarshalOptions{AllowPartial: true, Resolver: resolver}).Marshal(known); err == nil {
+		m3 := mt.New().Interface()
+		if err := (prototext.UnmarshalOptions{AllowPartial: true, Resolver: resolver}).Unmarshal(b, m3); err != nil {
+			t.Errorf("prototext.Unmarshal() = %v, want nil\n%s", err, b)
+		} else if !proto.Equal(known, m3) {
+			t.Errorf("round-trip through the text format did not preserve message\nOriginal:\n%v\nNew:\n%v", prototext.Format(known), prototext.Format(m3))
+		}
+	}
+	if b, err := (protojson.MarshalOptions{AllowPartial: true, Resolver: resolver}).Marshal(known); err == nil {
+		m4 := mt.New().Interface()
+		if err := (protojson.UnmarshalOptions{AllowPartial: true, Resolver: resolver}).Unmarshal(b, m4); err != nil {
+			t.Errorf("protojson.Unmarshal() = %v, want nil\n%s", err, b)
+		} else if !proto.Equal(known, m4) {
+			t.Errorf("round-trip through the JSON format did not preserve message\nOriginal:\n%v\nNew:\n%v", prototext.Format(known), prototext.Format(m4))
+		}
+	}
+
+	// Test copying and clearing fields through reflection.
+	mr := m.ProtoReflect()
+	m5 := mt.New()
+	var fds []protoreflect.FieldDescriptor
+	mr.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		m5.Set(fd, v)
+		fds = append(fds, fd)
+		return true
+	})
+	m5.SetUnknown(mr.GetUnknown())
+	if !proto.Equal(m, m5.Interface()) {
+		t.Errorf("copying fields with Set did not preserve message\nOriginal:\n%v\nNew:\n%v", prototext.Format(m), prototext.Format(m5.Interface()))
+	}
+	for _, fd := range fds {
+		m5.Clear(fd)
+		if m5.Has(fd) {
+			t.Errorf("after Clear(%v), Has(%v) = true, want false", fd.FullName(), fd.FullName())
+		}
+	}
+	m5.SetUnknown(nil)
+	if size := proto.Size(m5.Interface()); size != 0 {
+		t.Errorf("after clearing all fields, Size() = %v, want 0\n%v", size, prototext.Format(m5.Interface()))
+	}
+	if !proto.Equal(m, m2) {
+		t.Errorf("clearing fields of a copy modified the original message")
+	}
+}
+
+// discardUnknown discards the unknown fields of m and all messages within it.
+func discardUnknown(m protoreflect.Message) {
+	m.SetUnknown(nil)
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			for i := 0; i < v.List().Len(); i++ {
+				discardUnknown(v.List().Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				discardUnknown(v.Message())
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			discardUnknown(v.Message())
+		}
+		return true
+	})
+}
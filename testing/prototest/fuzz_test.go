@@ -0,0 +1,33 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prototest_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/testing/prototest"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	testpb "google.golang.org/protobuf/internal/testprotos/test"
+	test3pb "google.golang.org/protobuf/internal/testprotos/test3"
+	testeditionspb "google.golang.org/protobuf/internal/testprotos/testeditions"
+)
+
+func FuzzTestAllTypes(f *testing.F) {
+	prototest.Message{}.Fuzz(f, (*testpb.TestAllTypes)(nil).ProtoReflect().Type())
+}
+
+func FuzzTestAllTypesProto3(f *testing.F) {
+	prototest.Message{}.Fuzz(f, (*test3pb.TestAllTypes)(nil).ProtoReflect().Type())
+}
+
+func FuzzTestAllExtensions(f *testing.F) {
+	prototest.Message{}.Fuzz(f, (*testeditionspb.TestAllExtensions)(nil).ProtoReflect().Type())
+}
+
+func FuzzDynamicTestAllTypes(f *testing.F) {
+	md := (*testeditionspb.TestAllTypes)(nil).ProtoReflect().Descriptor()
+	prototest.Message{}.Fuzz(f, dynamicpb.NewMessageType(md))
+}
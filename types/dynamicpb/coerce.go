@@ -0,0 +1,465 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynamicpb
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/internal/errors"
+	"google.golang.org/protobuf/internal/genid"
+	"google.golang.org/protobuf/internal/pragma"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CoerceOptions configures the conversion of loosely typed Go values to
+// field values, for tools that construct messages from input such as
+// command-line flags, CSV files, or decoded JSON documents.
+//
+// The following conversions are always performed:
+//
+//   - Go integers and floats to numeric fields, if the value is in the range
+//     of the field and, for integer fields, has no fractional part.
+//   - Go integers, protoreflect.EnumNumber values, and names of values to
+//     enum fields, if the enum declares the value.
+//   - Strings and byte slices to bytes fields.
+//   - time.Time values to google.protobuf.Timestamp fields and
+//     time.Duration values to google.protobuf.Duration fields.
+//   - Values for the wrapped field to wrapper message fields,
+//     such as google.protobuf.Int64Value.
+//   - map[string]any values to message fields, as for [CoerceOptions.SetFields].
+//   - Messages of the same type to message fields, copying them if their
+//     Go types differ, as for generated and dynamic messages.
+//   - Slices and arrays to repeated fields, and Go maps to map fields,
+//     converting each element, key, and value.
+//
+// Other conversions are enabled by the options.
+type CoerceOptions struct {
+	pragma.NoUnkeyedLiterals
+
+	// ParseStrings permits strings for fields of other types.
+	// Strings are parsed as Go integer literals (e.g., "42" or "0x2a"),
+	// floating-point numbers, or bools (as by strconv.ParseBool), as enum
+	// value numbers, as RFC 3339 timestamps for google.protobuf.Timestamp
+	// fields, and as Go durations (e.g., "1.5s" or "1h30m") for
+	// google.protobuf.Duration fields.
+	ParseStrings bool
+
+	// FormatStrings permits numbers and bools for string fields,
+	// which are formatted in decimal and as "true" or "false".
+	FormatStrings bool
+
+	// AllowTruncation permits floats with a fractional part for integer
+	// fields, which are truncated toward zero.
+	AllowTruncation bool
+
+	// AllowUnknownEnumNumbers permits numbers for enum fields that are not
+	// declared in the enum, as for open enums.
+	AllowUnknownEnumNumbers bool
+
+	// DiscardUnknown ignores names that do not name a field of the message
+	// in SetFields and in map[string]any values for message fields.
+	DiscardUnknown bool
+}
+
+// SetFields sets the fields of m named by the keys of fields to the
+// corresponding values using default options.
+// See [CoerceOptions.SetFields].
+func SetFields(m protoreflect.Message, fields map[string]any) error {
+	return CoerceOptions{}.SetFields(m, fields)
+}
+
+// SetFields sets the fields of m named by the keys of fields to the
+// corresponding values, converted as for [CoerceOptions.Set].
+// Fields are named by their name, their JSON name, or, for groups,
+// their text name. Fields are set in the order of their names.
+func (o CoerceOptions) SetFields(m protoreflect.Message, fields map[string]any) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fds := m.Descriptor().Fields()
+	for _, name := range names {
+		fd := fds.ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = fds.ByJSONName(name)
+		}
+		if fd == nil {
+			fd = fds.ByTextName(name)
+		}
+		if fd == nil {
+			if o.DiscardUnknown {
+				continue
+			}
+			return errors.New("%v: unknown field %q", m.Descriptor().FullName(), name)
+		}
+		if err := o.Set(m, fd, fields[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set sets the field fd of m to the value v, converted to the type of the
+// field according to the options. A nil value clears the field.
+// It reports an error if the value cannot be converted.
+func (o CoerceOptions) Set(m protoreflect.Message, fd protoreflect.FieldDescriptor, v any) error {
+	if v == nil {
+		m.Clear(fd)
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	switch {
+	case fd.IsList():
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return errors.New("%v: cannot use %T value for repeated field", fd.FullName(), v)
+		}
+		list := m.NewField(fd).List()
+		for i := 0; i < rv.Len(); i++ {
+			val, err := o.value(fd, rv.Index(i).Interface(), list.NewElement)
+			if err != nil {
+				return err
+			}
+			list.Append(val)
+		}
+		m.Set(fd, protoreflect.ValueOfList(list))
+	case fd.IsMap():
+		if rv.Kind() != reflect.Map {
+			return errors.New("%v: cannot use %T value for map field", fd.FullName(), v)
+		}
+		mmap := m.NewField(fd).Map()
+		iter := rv.MapRange()
+		for iter.Next() {
+			key, err := o.value(fd.MapKey(), iter.Key().Interface(), nil)
+			if err != nil {
+				return err
+			}
+			val, err := o.value(fd.MapValue(), iter.Value().Interface(), mmap.NewValue)
+			if err != nil {
+				return err
+			}
+			mmap.Set(key.MapKey(), val)
+		}
+		m.Set(fd, protoreflect.ValueOfMap(mmap))
+	default:
+		val, err := o.value(fd, v, func() protoreflect.Value { return m.NewField(fd) })
+		if err != nil {
+			return err
+		}
+		m.Set(fd, val)
+	}
+	return nil
+}
+
+// value converts v to a singular value of the field fd.
+// For message fields, newValue returns a new message value.
+func (o CoerceOptions) value(fd protoreflect.FieldDescriptor, v any, newValue func() protoreflect.Value) (protoreflect.Value, error) {
+	if pv, ok := v.(protoreflect.Value); ok {
+		v = pv.Interface()
+	}
+	if m, ok := v.(protoreflect.Message); ok {
+		v = m.Interface()
+	}
+	var val protoreflect.Value
+	var ok bool
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		val, ok = o.toBool(v)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		var n int64
+		if n, ok = o.toInt(v, 32); ok {
+			val = protoreflect.ValueOfInt32(int32(n))
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		var n int64
+		if n, ok = o.toInt(v, 64); ok {
+			val = protoreflect.ValueOfInt64(n)
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		var n uint64
+		if n, ok = o.toUint(v, 32); ok {
+			val = protoreflect.ValueOfUint32(uint32(n))
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		var n uint64
+		if n, ok = o.toUint(v, 64); ok {
+			val = protoreflect.ValueOfUint64(n)
+		}
+	case protoreflect.FloatKind:
+		var f float64
+		if f, ok = o.toFloat(v, 32); ok {
+			val = protoreflect.ValueOfFloat32(float32(f))
+		}
+	case protoreflect.DoubleKind:
+		var f float64
+		if f, ok = o.toFloat(v, 64); ok {
+			val = protoreflect.ValueOfFloat64(f)
+		}
+	case protoreflect.StringKind:
+		val, ok = o.toString(v)
+	case protoreflect.BytesKind:
+		switch v := v.(type) {
+		case []byte:
+			val, ok = protoreflect.ValueOfBytes(v), true
+		case string:
+			val, ok = protoreflect.ValueOfBytes([]byte(v)), true
+		}
+	case protoreflect.EnumKind:
+		val, ok = o.toEnum(fd.Enum(), v)
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return o.toMessage(fd, v, newValue)
+	}
+	if !ok {
+		return protoreflect.Value{}, errors.New("%v: cannot use %T value %v for %v field", fd.FullName(), v, v, fd.Kind())
+	}
+	return val, nil
+}
+
+func (o CoerceOptions) toBool(v any) (protoreflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return protoreflect.ValueOfBool(rv.Bool()), true
+	case reflect.String:
+		if !o.ParseStrings {
+			break
+		}
+		if b, err := strconv.ParseBool(rv.String()); err == nil {
+			return protoreflect.ValueOfBool(b), true
+		}
+	}
+	return protoreflect.Value{}, false
+}
+
+// toInt converts v to a signed integer of the given size.
+func (o CoerceOptions) toInt(v any, bitSize int) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := rv.Int()
+		return n, n == n<<(64-bitSize)>>(64-bitSize)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := rv.Uint()
+		return int64(n), n <= 1<<(bitSize-1)-1
+	case reflect.Float32, reflect.Float64:
+		f, ok := o.truncate(rv.Float())
+		limit := math.Ldexp(1, bitSize-1)
+		return int64(f), ok && f >= -limit && f < limit
+	case reflect.String:
+		if !o.ParseStrings {
+			break
+		}
+		if n, err := strconv.ParseInt(rv.String(), 0, bitSize); err == nil {
+			return n, true
+		}
+		if f, err := strconv.ParseFloat(rv.String(), 64); err == nil {
+			return o.toInt(f, bitSize)
+		}
+	}
+	return 0, false
+}
+
+// toUint converts v to an unsigned integer of the given size.
+func (o CoerceOptions) toUint(v any, bitSize int) (uint64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := rv.Int()
+		return uint64(n), n >= 0 && (bitSize == 64 || uint64(n) < 1<<bitSize)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := rv.Uint()
+		return n, bitSize == 64 || n < 1<<bitSize
+	case reflect.Float32, reflect.Float64:
+		f, ok := o.truncate(rv.Float())
+		return uint64(f), ok && f >= 0 && f < math.Ldexp(1, bitSize)
+	case reflect.String:
+		if !o.ParseStrings {
+			break
+		}
+		if n, err := strconv.ParseUint(rv.String(), 0, bitSize); err == nil {
+			return n, true
+		}
+		if f, err := strconv.ParseFloat(rv.String(), 64); err == nil {
+			return o.toUint(f, bitSize)
+		}
+	}
+	return 0, false
+}
+
+// truncate truncates f toward zero, reporting false if it has a fractional
+// part and AllowTruncation is not set.
+func (o CoerceOptions) truncate(f float64) (float64, bool) {
+	t := math.Trunc(f)
+	return t, t == f || (o.AllowTruncation && !math.IsNaN(f))
+}
+
+// toFloat converts v to a float of the given size.
+func (o CoerceOptions) toFloat(v any, bitSize int) (float64, bool) {
+	var f float64
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f = float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		f = rv.Float()
+	case reflect.String:
+		if !o.ParseStrings {
+			return 0, false
+		}
+		var err error
+		if f, err = strconv.ParseFloat(rv.String(), bitSize); err != nil {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if bitSize == 32 && !math.IsInf(f, 0) && math.Abs(f) > math.MaxFloat32 {
+		return 0, false
+	}
+	return f, true
+}
+
+func (o CoerceOptions) toString(v any) (protoreflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	var s string
+	switch rv.Kind() {
+	case reflect.String:
+		return protoreflect.ValueOfString(rv.String()), true
+	case reflect.Bool:
+		s = strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s = strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32:
+		s = strconv.FormatFloat(rv.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		s = strconv.FormatFloat(rv.Float(), 'g', -1, 64)
+	default:
+		return protoreflect.Value{}, false
+	}
+	return protoreflect.ValueOfString(s), o.FormatStrings
+}
+
+func (o CoerceOptions) toEnum(ed protoreflect.EnumDescriptor, v any) (protoreflect.Value, bool) {
+	var n protoreflect.EnumNumber
+	switch v := v.(type) {
+	case protoreflect.Enum:
+		if v.Descriptor().FullName() != ed.FullName() {
+			return protoreflect.Value{}, false
+		}
+		n = v.Number()
+	case protoreflect.EnumNumber:
+		n = v
+	case string:
+		if evd := ed.Values().ByName(protoreflect.Name(v)); evd != nil {
+			return protoreflect.ValueOfEnum(evd.Number()), true
+		}
+		i, err := strconv.ParseInt(v, 0, 32)
+		if err != nil || !o.ParseStrings {
+			return protoreflect.Value{}, false
+		}
+		n = protoreflect.EnumNumber(i)
+	default:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.String {
+			return protoreflect.Value{}, false
+		}
+		i, ok := o.toInt(v, 32)
+		if !ok {
+			return protoreflect.Value{}, false
+		}
+		n = protoreflect.EnumNumber(i)
+	}
+	if ed.Values().ByNumber(n) == nil && !o.AllowUnknownEnumNumbers {
+		return protoreflect.Value{}, false
+	}
+	return protoreflect.ValueOfEnum(n), true
+}
+
+// toMessage converts v to a message value of the field fd.
+func (o CoerceOptions) toMessage(fd protoreflect.FieldDescriptor, v any, newValue func() protoreflect.Value) (protoreflect.Value, error) {
+	md := fd.Message()
+	switch v := v.(type) {
+	case proto.Message:
+		src := v.ProtoReflect()
+		if src.Descriptor().FullName() != md.FullName() {
+			break
+		}
+		val := newValue()
+		if src.Type() == val.Message().Type() {
+			return protoreflect.ValueOfMessage(src), nil
+		}
+		// Copy the message to the Go type of the field.
+		b, err := proto.MarshalOptions{AllowPartial: true}.Marshal(v)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		if err := (proto.UnmarshalOptions{AllowPartial: true, Merge: true}).Unmarshal(b, val.Message().Interface()); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return val, nil
+	case map[string]any:
+		val := newValue()
+		if err := o.SetFields(val.Message(), v); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return val, nil
+	}
+
+	val := newValue()
+	m := val.Message()
+	fds := md.Fields()
+	switch md.FullName() {
+	case genid.Timestamp_message_fullname:
+		t, ok := v.(time.Time)
+		if s, isString := v.(string); isString && o.ParseStrings {
+			var err error
+			t, err = time.Parse(time.RFC3339Nano, s)
+			ok = err == nil
+		}
+		if ok {
+			m.Set(fds.ByNumber(genid.Timestamp_Seconds_field_number), protoreflect.ValueOfInt64(t.Unix()))
+			m.Set(fds.ByNumber(genid.Timestamp_Nanos_field_number), protoreflect.ValueOfInt32(int32(t.Nanosecond())))
+			return val, nil
+		}
+	case genid.Duration_message_fullname:
+		d, ok := v.(time.Duration)
+		if s, isString := v.(string); isString && o.ParseStrings {
+			var err error
+			d, err = time.ParseDuration(s)
+			ok = err == nil
+		}
+		if ok {
+			m.Set(fds.ByNumber(genid.Duration_Seconds_field_number), protoreflect.ValueOfInt64(int64(d/time.Second)))
+			m.Set(fds.ByNumber(genid.Duration_Nanos_field_number), protoreflect.ValueOfInt32(int32(d%time.Second)))
+			return val, nil
+		}
+	case genid.BoolValue_message_fullname,
+		genid.Int32Value_message_fullname,
+		genid.Int64Value_message_fullname,
+		genid.UInt32Value_message_fullname,
+		genid.UInt64Value_message_fullname,
+		genid.FloatValue_message_fullname,
+		genid.DoubleValue_message_fullname,
+		genid.StringValue_message_fullname,
+		genid.BytesValue_message_fullname:
+		wfd := fds.ByNumber(genid.WrapperValue_Value_field_number)
+		wv, err := o.value(wfd, v, nil)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		m.Set(wfd, wv)
+		return val, nil
+	}
+	return protoreflect.Value{}, errors.New("%v: cannot use %T value for %v field", fd.FullName(), v, md.FullName())
+}
@@ -0,0 +1,237 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynamicpb_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	test3pb "google.golang.org/protobuf/internal/testprotos/test3"
+	pb2 "google.golang.org/protobuf/internal/testprotos/textpb2"
+)
+
+func TestCoerce(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	tests := []struct {
+		desc   string
+		opts   dynamicpb.CoerceOptions
+		fields map[string]any
+		want   proto.Message
+	}{{
+		desc: "exact types",
+		fields: map[string]any{
+			"singular_int32":  int32(-1),
+			"singular_string": "s",
+			"singular_bytes":  []byte("b"),
+			"singular_bool":   true,
+			"oneof_uint64":    uint64(math.MaxUint64),
+		},
+		want: &test3pb.TestAllTypes{
+			SingularInt32:  -1,
+			SingularString: "s",
+			SingularBytes:  []byte("b"),
+			SingularBool:   true,
+			OneofField:     &test3pb.TestAllTypes_OneofUint64{OneofUint64: math.MaxUint64},
+		},
+	}, {
+		desc: "numeric conversions",
+		fields: map[string]any{
+			"singularInt64":  42,
+			"singularUint32": int8(7),
+			"singularFloat":  1,
+			"singularDouble": uint(3),
+			"singularSint32": 2.0,
+		},
+		want: &test3pb.TestAllTypes{
+			SingularInt64:  42,
+			SingularUint32: 7,
+			SingularFloat:  1,
+			SingularDouble: 3,
+			SingularSint32: 2,
+		},
+	}, {
+		desc: "enums",
+		fields: map[string]any{
+			"singular_nested_enum":  "BAZ",
+			"singular_foreign_enum": 5,
+			"repeated_nested_enum":  []any{test3pb.TestAllTypes_BAR, protoreflect.EnumNumber(-1)},
+		},
+		want: &test3pb.TestAllTypes{
+			SingularNestedEnum:  test3pb.TestAllTypes_BAZ,
+			SingularForeignEnum: test3pb.ForeignEnum_FOREIGN_BAR,
+			RepeatedNestedEnum:  []test3pb.TestAllTypes_NestedEnum{test3pb.TestAllTypes_BAR, test3pb.TestAllTypes_NEG},
+		},
+	}, {
+		desc: "parse strings",
+		opts: dynamicpb.CoerceOptions{ParseStrings: true},
+		fields: map[string]any{
+			"singular_int64":       "42",
+			"singular_uint32":      "0x10",
+			"singular_fixed64":     "1e3",
+			"singular_double":      "-Inf",
+			"singular_bool":        "TRUE",
+			"singular_nested_enum": "2",
+			"repeated_int32":       []string{"1", "2"},
+			"map_int32_int32":      map[string]string{"3": "4"},
+		},
+		want: &test3pb.TestAllTypes{
+			SingularInt64:      42,
+			SingularUint32:     16,
+			SingularFixed64:    1000,
+			SingularDouble:     math.Inf(-1),
+			SingularBool:       true,
+			SingularNestedEnum: test3pb.TestAllTypes_BAZ,
+			RepeatedInt32:      []int32{1, 2},
+			MapInt32Int32:      map[int32]int32{3: 4},
+		},
+	}, {
+		desc: "format strings",
+		opts: dynamicpb.CoerceOptions{FormatStrings: true},
+		fields: map[string]any{
+			"singular_string":   1.5,
+			"repeated_string":   []any{1, false, uint8(3)},
+			"map_string_string": map[int]float32{1: 0.1},
+		},
+		want: &test3pb.TestAllTypes{
+			SingularString:  "1.5",
+			RepeatedString:  []string{"1", "false", "3"},
+			MapStringString: map[string]string{"1": "0.1"},
+		},
+	}, {
+		desc: "truncation and unknown enum numbers",
+		opts: dynamicpb.CoerceOptions{AllowTruncation: true, AllowUnknownEnumNumbers: true},
+		fields: map[string]any{
+			"singular_int32":       -2.7,
+			"singular_nested_enum": 10,
+		},
+		want: &test3pb.TestAllTypes{
+			SingularInt32:      -2,
+			SingularNestedEnum: 10,
+		},
+	}, {
+		desc: "messages",
+		fields: map[string]any{
+			"singular_nested_message": map[string]any{"a": 1, "corecursive": map[string]any{"singularString": "x"}},
+			"repeated_nested_message": []any{
+				&test3pb.TestAllTypes_NestedMessage{A: 2},
+				dynamicMessage(&test3pb.TestAllTypes_NestedMessage{A: 3}),
+			},
+			"map_string_nested_message": map[string]any{"k": map[string]any{"a": 4}},
+		},
+		want: &test3pb.TestAllTypes{
+			SingularNestedMessage: &test3pb.TestAllTypes_NestedMessage{A: 1, Corecursive: &test3pb.TestAllTypes{SingularString: "x"}},
+			RepeatedNestedMessage: []*test3pb.TestAllTypes_NestedMessage{{A: 2}, {A: 3}},
+			MapStringNestedMessage: map[string]*test3pb.TestAllTypes_NestedMessage{
+				"k": {A: 4},
+			},
+		},
+	}, {
+		desc: "well-known types",
+		fields: map[string]any{
+			"opt_timestamp": ts,
+			"opt_duration":  1500 * time.Millisecond,
+			"opt_int64":     12,
+			"opt_string":    "s",
+		},
+		want: &pb2.KnownTypes{
+			OptTimestamp: timestamppb.New(ts),
+			OptDuration:  durationpb.New(1500 * time.Millisecond),
+			OptInt64:     wrapperspb.Int64(12),
+			OptString:    wrapperspb.String("s"),
+		},
+	}, {
+		desc: "parse well-known types",
+		opts: dynamicpb.CoerceOptions{ParseStrings: true},
+		fields: map[string]any{
+			"opt_timestamp": "2024-01-02T03:04:05.000000006Z",
+			"opt_duration":  "-1m30s",
+			"opt_uint32":    "9",
+		},
+		want: &pb2.KnownTypes{
+			OptTimestamp: timestamppb.New(ts),
+			OptDuration:  durationpb.New(-90 * time.Second),
+			OptUint32:    wrapperspb.UInt32(9),
+		},
+	}, {
+		desc:   "nil clears",
+		fields: map[string]any{"singular_int32": nil},
+		want:   &test3pb.TestAllTypes{},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Set the fields of both generated and dynamic messages.
+			for _, m := range []proto.Message{
+				tt.want.ProtoReflect().Type().New().Interface(),
+				dynamicpb.NewMessage(tt.want.ProtoReflect().Descriptor()),
+			} {
+				if err := tt.opts.SetFields(m.ProtoReflect(), tt.fields); err != nil {
+					t.Fatalf("SetFields(%T) error: %v", m, err)
+				}
+				got := tt.want.ProtoReflect().Type().New().Interface()
+				b, err := proto.Marshal(m)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := proto.Unmarshal(b, got); err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+					t.Errorf("SetFields(%T) mismatch (-want +got):\n%s", m, diff)
+				}
+			}
+		})
+	}
+}
+
+func dynamicMessage(m proto.Message) proto.Message {
+	dm := dynamicpb.NewMessage(m.ProtoReflect().Descriptor())
+	proto.Merge(dm, m)
+	return dm
+}
+
+func TestCoerceErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		opts    dynamicpb.CoerceOptions
+		fields  map[string]any
+		wantErr string
+	}{
+		{desc: "unknown field", fields: map[string]any{"nope": 1}, wantErr: `unknown field "nope"`},
+		{desc: "int32 overflow", fields: map[string]any{"singular_int32": int64(math.MaxInt32 + 1)}, wantErr: "cannot use int64 value 2147483648 for int32 field"},
+		{desc: "negative uint", fields: map[string]any{"singular_uint64": -1}, wantErr: "for uint64 field"},
+		{desc: "uint32 overflow", fields: map[string]any{"singular_uint32": 1 << 32}, wantErr: "for uint32 field"},
+		{desc: "fraction", fields: map[string]any{"singular_int64": 1.5}, wantErr: "cannot use float64 value 1.5"},
+		{desc: "float32 overflow", fields: map[string]any{"singular_float": 1e300}, wantErr: "for float field"},
+		{desc: "string without ParseStrings", fields: map[string]any{"singular_int32": "1"}, wantErr: "cannot use string value 1"},
+		{desc: "number without FormatStrings", fields: map[string]any{"singular_string": 1}, wantErr: "cannot use int value 1 for string field"},
+		{desc: "bad string", opts: dynamicpb.CoerceOptions{ParseStrings: true}, fields: map[string]any{"singular_int32": "one"}, wantErr: "cannot use string value one"},
+		{desc: "unknown enum name", fields: map[string]any{"singular_nested_enum": "QUX"}, wantErr: "for enum field"},
+		{desc: "unknown enum number", fields: map[string]any{"singular_nested_enum": 10}, wantErr: "for enum field"},
+		{desc: "wrong enum", fields: map[string]any{"singular_nested_enum": test3pb.ForeignEnum_FOREIGN_BAR}, wantErr: "for enum field"},
+		{desc: "wrong message", fields: map[string]any{"singular_nested_message": &test3pb.TestAllTypes{}}, wantErr: "cannot use *test3.TestAllTypes value"},
+		{desc: "scalar for list", fields: map[string]any{"repeated_int32": 1}, wantErr: "cannot use int value for repeated field"},
+		{desc: "nested", fields: map[string]any{"singular_nested_message": map[string]any{"a": "x"}}, wantErr: "NestedMessage.a: cannot use string value x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := tt.opts.SetFields(dynamicpb.NewMessage((&test3pb.TestAllTypes{}).ProtoReflect().Descriptor()), tt.fields)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("SetFields() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -65,11 +65,31 @@ type extensionType struct {
 // Operations which modify a Message are not safe for concurrent use.
 type Message struct {
 	typ     messageType
-	known   map[protoreflect.FieldNumber]protoreflect.Value
-	ext     map[protoreflect.FieldNumber]protoreflect.FieldDescriptor
+	known   []knownField // sorted by field index; nil if read-only
+	ext     map[protoreflect.FieldNumber]extensionField
 	unknown protoreflect.RawFields
 }
 
+// knownField is the value of a known field.
+//
+// Only the fields that have been set have a slot, and clearing a field
+// frees its slot, which keeps messages with many fields but few populated
+// ones small. In exchange, accessing a field takes a binary search over
+// the slots, and setting a field that has no slot yet moves the slots
+// of the fields with a higher index. Fields are usually set in order of
+// increasing field index, such as when unmarshaling, in which case a slot
+// is appended at the end.
+type knownField struct {
+	index int
+	val   protoreflect.Value
+}
+
+// extensionField is a populated extension field.
+type extensionField struct {
+	desc protoreflect.FieldDescriptor
+	val  protoreflect.Value
+}
+
 var (
 	_ protoreflect.Message      = (*Message)(nil)
 	_ protoreflect.ProtoMessage = (*Message)(nil)
@@ -79,9 +99,10 @@ var (
 // NewMessage creates a new message with the provided descriptor.
 func NewMessage(desc protoreflect.MessageDescriptor) *Message {
 	return &Message{
-		typ:   messageType{desc},
-		known: make(map[protoreflect.FieldNumber]protoreflect.Value),
-		ext:   make(map[protoreflect.FieldNumber]protoreflect.FieldDescriptor),
+		typ: messageType{desc},
+		// A non-nil empty slice marks the message as mutable
+		// without allocating.
+		known: make([]knownField, 0),
 	}
 }
 
@@ -100,8 +121,13 @@ func (m *Message) String() string {
 
 // Reset clears the message to be empty, but preserves the dynamic message type.
 func (m *Message) Reset() {
-	m.known = make(map[protoreflect.FieldNumber]protoreflect.Value)
-	m.ext = make(map[protoreflect.FieldNumber]protoreflect.FieldDescriptor)
+	if m.known == nil {
+		m.known = make([]knownField, 0)
+	} else {
+		clear(m.known)
+		m.known = m.known[:0]
+	}
+	m.ext = nil
 	m.unknown = nil
 }
 
@@ -136,15 +162,30 @@ func (m *Message) ProtoMethods() *protoiface.Methods {
 // Range visits every populated field in undefined order.
 // See [protoreflect.Message] for details.
 func (m *Message) Range(f func(protoreflect.FieldDescriptor, protoreflect.Value) bool) {
-	for num, v := range m.known {
-		fd := m.ext[num]
-		if fd == nil {
-			fd = m.Descriptor().Fields().ByNumber(num)
+	if len(m.known) > 0 {
+		fields := m.typ.desc.Fields()
+		for i := 0; i < len(m.known); {
+			k := m.known[i]
+			fd := fields.Get(k.index)
+			if isSet(fd, k.val) && !f(fd, k.val) {
+				return
+			}
+			// Clearing a field in f frees its slot,
+			// so find the slot of the next field again.
+			if i < len(m.known) && m.known[i].index == k.index {
+				i++
+			} else if j, ok := m.findKnown(k.index); ok {
+				i = j + 1
+			} else {
+				i = j
+			}
 		}
-		if !isSet(fd, v) {
+	}
+	for _, x := range m.ext {
+		if !isSet(x.desc, x.val) {
 			continue
 		}
-		if !f(fd, v) {
+		if !f(x.desc, x.val) {
 			return
 		}
 	}
@@ -154,37 +195,37 @@ func (m *Message) Range(f func(protoreflect.FieldDescriptor, protoreflect.Value)
 // See [protoreflect.Message] for details.
 func (m *Message) Has(fd protoreflect.FieldDescriptor) bool {
 	m.checkField(fd)
-	if fd.IsExtension() && m.ext[fd.Number()] != fd {
-		return false
-	}
-	v, ok := m.known[fd.Number()]
-	if !ok {
-		return false
+	if fd.IsExtension() {
+		x, ok := m.ext[fd.Number()]
+		return ok && x.desc == fd && isSet(fd, x.val)
 	}
-	return isSet(fd, v)
+	v := m.getKnown(fd.Index())
+	return v.IsValid() && isSet(fd, v)
 }
 
 // Clear clears a field.
 // See [protoreflect.Message] for details.
 func (m *Message) Clear(fd protoreflect.FieldDescriptor) {
 	m.checkField(fd)
-	num := fd.Number()
-	delete(m.known, num)
-	delete(m.ext, num)
+	if fd.IsExtension() {
+		delete(m.ext, fd.Number())
+		return
+	}
+	m.clearKnown(fd.Index())
 }
 
 // Get returns the value of a field.
 // See [protoreflect.Message] for details.
 func (m *Message) Get(fd protoreflect.FieldDescriptor) protoreflect.Value {
 	m.checkField(fd)
-	num := fd.Number()
 	if fd.IsExtension() {
-		if fd != m.ext[num] {
+		x, ok := m.ext[fd.Number()]
+		if !ok || x.desc != fd {
 			return fd.(protoreflect.ExtensionTypeDescriptor).Type().Zero()
 		}
-		return m.known[num]
+		return x.val
 	}
-	if v, ok := m.known[num]; ok {
+	if v := m.getKnown(fd.Index()); v.IsValid() {
 		switch {
 		case fd.IsMap():
 			if v.Map().Len() > 0 {
@@ -222,23 +263,23 @@ func (m *Message) Mutable(fd protoreflect.FieldDescriptor) protoreflect.Value {
 	if m.known == nil {
 		panic(errors.New("%v: modification of read-only message", fd.FullName()))
 	}
-	num := fd.Number()
 	if fd.IsExtension() {
-		if fd != m.ext[num] {
-			m.ext[num] = fd
-			m.known[num] = fd.(protoreflect.ExtensionTypeDescriptor).Type().New()
+		num := fd.Number()
+		x, ok := m.ext[num]
+		if !ok || x.desc != fd {
+			x = extensionField{fd, fd.(protoreflect.ExtensionTypeDescriptor).Type().New()}
+			m.setExtension(num, x)
 		}
-		return m.known[num]
+		return x.val
 	}
-	if v, ok := m.known[num]; ok {
+	i := fd.Index()
+	if v := m.getKnown(i); v.IsValid() {
 		return v
 	}
 	m.clearOtherOneofFields(fd)
-	m.known[num] = m.NewField(fd)
-	if fd.IsExtension() {
-		m.ext[num] = fd
-	}
-	return m.known[num]
+	v := m.NewField(fd)
+	m.setKnown(i, v)
+	return v
 }
 
 // Set stores a value in a field.
@@ -263,12 +304,73 @@ func (m *Message) Set(fd protoreflect.FieldDescriptor, v protoreflect.Value) {
 		if !isValid {
 			panic(errors.New("%v: assigning invalid type %T", fd.FullName(), v.Interface()))
 		}
-		m.ext[fd.Number()] = fd
-	} else {
-		typecheck(fd, v)
+		m.setExtension(fd.Number(), extensionField{fd, v})
+		return
 	}
+	typecheck(fd, v)
 	m.clearOtherOneofFields(fd)
-	m.known[fd.Number()] = v
+	m.setKnown(fd.Index(), v)
+}
+
+// findKnown returns the position of the slot for the field at index i,
+// and reports whether there is one. If not, it is the position at which
+// the slot would be inserted.
+func (m *Message) findKnown(i int) (int, bool) {
+	n := len(m.known)
+	if n == 0 || m.known[n-1].index < i {
+		return n, false
+	}
+	lo, hi := 0, n
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if m.known[mid].index < i {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, m.known[lo].index == i
+}
+
+// getKnown returns the value of the field at index i,
+// which is invalid if the field has no value.
+func (m *Message) getKnown(i int) protoreflect.Value {
+	if j, ok := m.findKnown(i); ok {
+		return m.known[j].val
+	}
+	return protoreflect.Value{}
+}
+
+func (m *Message) setKnown(i int, v protoreflect.Value) {
+	j, ok := m.findKnown(i)
+	if !ok {
+		if cap(m.known) == 0 {
+			// Avoid growing the slice one slot at a time
+			// when several fields are set.
+			m.known = make([]knownField, 0, min(4, m.typ.desc.Fields().Len()))
+		}
+		m.known = append(m.known, knownField{})
+		copy(m.known[j+1:], m.known[j:])
+		m.known[j].index = i
+	}
+	m.known[j].val = v
+}
+
+// clearKnown clears the field at index i and frees its slot.
+func (m *Message) clearKnown(i int) {
+	if j, ok := m.findKnown(i); ok {
+		n := len(m.known) - 1
+		copy(m.known[j:], m.known[j+1:])
+		m.known[n] = knownField{}
+		m.known = m.known[:n]
+	}
+}
+
+func (m *Message) setExtension(num protoreflect.FieldNumber, x extensionField) {
+	if m.ext == nil {
+		m.ext = make(map[protoreflect.FieldNumber]extensionField)
+	}
+	m.ext[num] = x
 }
 
 func (m *Message) clearOtherOneofFields(fd protoreflect.FieldDescriptor) {
@@ -276,10 +378,10 @@ func (m *Message) clearOtherOneofFields(fd protoreflect.FieldDescriptor) {
 	if od == nil {
 		return
 	}
-	num := fd.Number()
-	for i := 0; i < od.Fields().Len(); i++ {
-		if n := od.Fields().Get(i).Number(); n != num {
-			delete(m.known, n)
+	fields := od.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if f := fields.Get(i); f != fd {
+			m.clearKnown(f.Index())
 		}
 	}
 }
@@ -420,6 +522,11 @@ func (x *dynamicList) Set(n int, v protoreflect.Value) {
 
 func (x *dynamicList) Append(v protoreflect.Value) {
 	typecheckSingular(x.desc, v)
+	if x.list == nil {
+		// Avoid growing the list one element at a time,
+		// such as when unmarshaling it.
+		x.list = make([]protoreflect.Value, 0, 4)
+	}
 	x.list = append(x.list, v)
 }
 
@@ -646,6 +753,13 @@ func newListEntry(fd protoreflect.FieldDescriptor) protoreflect.Value {
 // is determined by these methods, and is therefore equivalent to the Go type
 // used to represent a protoreflect.Value. See the protoreflect.Value
 // documentation for more details.
+//
+// A dynamic ExtensionType may be used with any message that it extends,
+// including generated messages, for instance to set and get extensions
+// declared in a descriptor set that are not linked into the binary.
+// When parsing such messages, the extension fields are only recognized if
+// the extension type is provided by the resolver (e.g., a [Types]);
+// otherwise they are retained as unknown fields.
 func NewExtensionType(desc protoreflect.ExtensionDescriptor) protoreflect.ExtensionType {
 	if xt, ok := desc.(protoreflect.ExtensionTypeDescriptor); ok {
 		desc = xt.Descriptor()
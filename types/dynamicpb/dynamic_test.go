@@ -8,10 +8,13 @@ import (
 	"fmt"
 	"testing"
 
+	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/testing/prototest"
+	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/dynamicpb"
 
 	testpb "google.golang.org/protobuf/internal/testprotos/test"
@@ -31,6 +34,40 @@ func TestConformance(t *testing.T) {
 	}
 }
 
+func TestFieldsSetOutOfOrder(t *testing.T) {
+	md := (*testpb.TestAllTypes)(nil).ProtoReflect().Descriptor()
+	fields := md.Fields()
+	m := dynamicpb.NewMessage(md)
+	for i := fields.Len() - 1; i >= 0; i -= 7 {
+		fd := fields.Get(i)
+		if fd.IsList() || fd.IsMap() || fd.Message() != nil || fd.ContainingOneof() != nil {
+			continue
+		}
+		m.Set(fd, fd.Default())
+	}
+	want := proto.PopulatedFields(m)
+	if want.Len() == 0 {
+		t.Fatal("no fields were set")
+	}
+
+	// Clearing the current field while ranging does not skip other fields.
+	var got int
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if !want.Has(fd.Index()) {
+			t.Errorf("Range visited unset field %v", fd.FullName())
+		}
+		got++
+		m.Clear(fd)
+		return true
+	})
+	if got != want.Len() {
+		t.Errorf("Range visited %d fields, want %d", got, want.Len())
+	}
+	if n := proto.PopulatedFields(m).Len(); n != 0 {
+		t.Errorf("after clearing all fields, %d fields are populated", n)
+	}
+}
+
 func TestDynamicExtensions(t *testing.T) {
 	for _, message := range []proto.Message{
 		(*testpb.TestAllExtensions)(nil),
@@ -79,3 +116,93 @@ func (extResolver) RangeExtensionsByMessage(message protoreflect.FullName, f fun
 		return f(dynamicpb.NewExtensionType(xt.TypeDescriptor().Descriptor()))
 	})
 }
+
+func TestDynamicExtensionOfGeneratedMessage(t *testing.T) {
+	fdp := new(descriptorpb.FileDescriptorProto)
+	if err := prototext.Unmarshal([]byte(`
+		name:       "ext.proto"
+		package:    "dynamicpb.test"
+		dependency: "internal/testprotos/test/test.proto"
+		message_type: [{name:"Value" field:[{name:"a" number:1 label:LABEL_OPTIONAL type:TYPE_INT32}]}]
+		extension: [
+			{name:"count" number:5000 label:LABEL_OPTIONAL type:TYPE_INT32 extendee:".goproto.proto.test.TestAllExtensions"},
+			{name:"values" number:5001 label:LABEL_REPEATED type:TYPE_MESSAGE type_name:".dynamicpb.test.Value" extendee:".goproto.proto.test.TestAllExtensions"}
+		]
+	`), fdp); err != nil {
+		t.Fatal(err)
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := dynamicpb.NewExtensionType(fd.Extensions().ByName("count"))
+	values := dynamicpb.NewExtensionType(fd.Extensions().ByName("values"))
+
+	m := &testpb.TestAllExtensions{}
+	proto.SetExtension(m, count, int32(5))
+	list := m.ProtoReflect().Mutable(values.TypeDescriptor()).List()
+	v := list.NewElement()
+	v.Message().Set(v.Message().Descriptor().Fields().ByName("a"), protoreflect.ValueOfInt32(3))
+	list.Append(v)
+	if got := proto.GetExtension(m, count); got != int32(5) {
+		t.Errorf("GetExtension(count) = %v, want 5", got)
+	}
+
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := new(protoregistry.Files)
+	if err := files.RegisterFile(fd); err != nil {
+		t.Fatal(err)
+	}
+	got := &testpb.TestAllExtensions{}
+	if err := (proto.UnmarshalOptions{Resolver: dynamicpb.NewTypes(files)}).Unmarshal(b, got); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, m) {
+		t.Errorf("Unmarshal with dynamic types:\ngot  %v\nwant %v", got, m)
+	}
+
+	// Without a resolver for the extensions, they are unknown fields.
+	unresolved := &testpb.TestAllExtensions{}
+	if err := proto.Unmarshal(b, unresolved); err != nil {
+		t.Fatal(err)
+	}
+	if proto.HasExtension(unresolved, count) || len(unresolved.ProtoReflect().GetUnknown()) != len(b) {
+		t.Errorf("Unmarshal without dynamic types = %v, want extensions as unknown fields", unresolved)
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32:  proto.Int32(1),
+		OptionalString: proto.String("string"),
+		RepeatedInt64:  []int64{1, 2, 3, 4, 5},
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			A: proto.Int32(2),
+		},
+		MapStringString: map[string]string{"key": "value"},
+	}
+	data, err := proto.Marshal(m)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("Generated", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := proto.Unmarshal(data, new(testpb.TestAllTypes)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Dynamic", func(b *testing.B) {
+		md := m.ProtoReflect().Descriptor()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := proto.Unmarshal(data, dynamicpb.NewMessage(md)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
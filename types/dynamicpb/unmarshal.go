@@ -0,0 +1,110 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynamicpb
+
+import (
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// NewMessageFromJSON returns a new dynamic message of type desc
+// populated from b, which is in the protobuf JSON format.
+// See [NewMessageFromText] for how types are resolved.
+func NewMessageFromJSON(desc protoreflect.MessageDescriptor, b []byte) (*Message, error) {
+	r, err := newResolver(desc)
+	if err != nil {
+		return nil, err
+	}
+	m := NewMessage(desc)
+	if err := (protojson.UnmarshalOptions{Resolver: r}).Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NewMessageFromText returns a new dynamic message of type desc
+// populated from b, which is in the protobuf text format.
+//
+// The types of extension fields and of the contents of google.protobuf.Any
+// fields are resolved as dynamic types from the file of desc and the files
+// it transitively imports, so that they agree with the descriptors of the
+// message. Types not declared in those files are resolved using
+// [protoregistry.GlobalTypes].
+func NewMessageFromText(desc protoreflect.MessageDescriptor, b []byte) (*Message, error) {
+	r, err := newResolver(desc)
+	if err != nil {
+		return nil, err
+	}
+	m := NewMessage(desc)
+	if err := (prototext.UnmarshalOptions{Resolver: r}).Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// resolver resolves types using the dynamic types of a set of files,
+// falling back to the global registry for types not declared in them.
+type resolver struct {
+	types *Types
+}
+
+// newResolver returns a resolver for the file of md
+// and the files it transitively imports.
+func newResolver(md protoreflect.MessageDescriptor) (resolver, error) {
+	files := new(protoregistry.Files)
+	seen := make(map[string]bool)
+	var register func(fd protoreflect.FileDescriptor) error
+	register = func(fd protoreflect.FileDescriptor) error {
+		if seen[fd.Path()] || fd.IsPlaceholder() {
+			return nil
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			if err := register(imports.Get(i).FileDescriptor); err != nil {
+				return err
+			}
+		}
+		return files.RegisterFile(fd)
+	}
+	if err := register(md.ParentFile()); err != nil {
+		return resolver{}, err
+	}
+	return resolver{NewTypes(files)}, nil
+}
+
+func (r resolver) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	mt, err := r.types.FindMessageByName(name)
+	if err == protoregistry.NotFound {
+		return protoregistry.GlobalTypes.FindMessageByName(name)
+	}
+	return mt, err
+}
+
+func (r resolver) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	mt, err := r.types.FindMessageByURL(url)
+	if err == protoregistry.NotFound {
+		return protoregistry.GlobalTypes.FindMessageByURL(url)
+	}
+	return mt, err
+}
+
+func (r resolver) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	xt, err := r.types.FindExtensionByName(field)
+	if err == protoregistry.NotFound {
+		return protoregistry.GlobalTypes.FindExtensionByName(field)
+	}
+	return xt, err
+}
+
+func (r resolver) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	xt, err := r.types.FindExtensionByNumber(message, field)
+	if err == protoregistry.NotFound {
+		return protoregistry.GlobalTypes.FindExtensionByNumber(message, field)
+	}
+	return xt, err
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynamicpb_test
+
+import (
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protoparse"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const eventProto = `
+syntax = "proto2";
+
+package dynamicpb.test;
+
+import "google/protobuf/any.proto";
+import "google/protobuf/duration.proto";
+
+message Event {
+  required string name = 1;
+  optional google.protobuf.Any detail = 2;
+  optional google.protobuf.Duration elapsed = 3;
+  extensions 100 to max;
+}
+
+message Detail {
+  optional int32 code = 1;
+}
+
+extend Event {
+  optional Detail extra = 100;
+}
+`
+
+func eventDescriptor(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+	p := protoparse.Parser{
+		Accessor: func(path string) (io.ReadCloser, error) {
+			if path != "event.proto" {
+				return nil, fs.ErrNotExist
+			}
+			return io.NopCloser(strings.NewReader(eventProto)), nil
+		},
+	}
+	fds, err := p.ParseFiles("event.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fds[0]
+}
+
+func TestNewMessageFrom(t *testing.T) {
+	fd := eventDescriptor(t)
+	md := fd.Messages().ByName("Event")
+
+	fromJSON, err := dynamicpb.NewMessageFromJSON(md, []byte(`{
+		"name": "start",
+		"detail": {"@type": "type.googleapis.com/dynamicpb.test.Detail", "code": 7},
+		"elapsed": "1.5s",
+		"[dynamicpb.test.extra]": {"code": 8}
+	}`))
+	if err != nil {
+		t.Fatalf("NewMessageFromJSON error: %v", err)
+	}
+	fromText, err := dynamicpb.NewMessageFromText(md, []byte(`
+		name: "start"
+		detail: {[type.googleapis.com/dynamicpb.test.Detail]: {code: 7}}
+		elapsed: {seconds: 1 nanos: 500000000}
+		[dynamicpb.test.extra]: {code: 8}
+	`))
+	if err != nil {
+		t.Fatalf("NewMessageFromText error: %v", err)
+	}
+	if !proto.Equal(fromJSON, fromText) {
+		t.Errorf("NewMessageFromJSON and NewMessageFromText disagree:\n%v\n%v", fromJSON, fromText)
+	}
+
+	xd := dynamicpb.NewExtensionType(fd.Extensions().ByName("extra")).TypeDescriptor()
+	if !fromJSON.Has(xd) {
+		t.Fatalf("extension %v is not set", xd.FullName())
+	}
+	code := xd.Message().Fields().ByName("code")
+	if got := fromJSON.Get(xd).Message().Get(code).Int(); got != 8 {
+		t.Errorf("extension %v has code %v, want 8", xd.FullName(), got)
+	}
+}
+
+func TestNewMessageFromErrors(t *testing.T) {
+	md := eventDescriptor(t).Messages().ByName("Event")
+	for _, tt := range []struct {
+		desc string
+		new  func(protoreflect.MessageDescriptor, []byte) (*dynamicpb.Message, error)
+		in   string
+	}{
+		{"JSON missing required field", dynamicpb.NewMessageFromJSON, `{}`},
+		{"JSON unknown field", dynamicpb.NewMessageFromJSON, `{"name": "x", "other": 1}`},
+		{"JSON unresolvable Any", dynamicpb.NewMessageFromJSON, `{"name": "x", "detail": {"@type": "type.googleapis.com/dynamicpb.test.Missing"}}`},
+		{"text missing required field", dynamicpb.NewMessageFromText, ``},
+		{"text syntax error", dynamicpb.NewMessageFromText, `name: `},
+	} {
+		if m, err := tt.new(md, []byte(tt.in)); err == nil {
+			t.Errorf("%v: got %v, want error", tt.desc, m)
+		}
+	}
+}
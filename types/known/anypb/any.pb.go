@@ -47,6 +47,12 @@
 //	}
 //	... // make use of any
 //
+// The type URL of the Any message is the full name of the message type
+// prefixed with "type.googleapis.com/". A different prefix, such as that of
+// a private schema registry, can be used by constructing it with NewWithPrefix:
+//
+//	any, err := anypb.NewWithPrefix(m, "schemas.example.com/")
+//
 // # Unmarshaling an Any
 //
 // With a populated Any message, the underlying message can be serialized into
@@ -93,6 +99,10 @@
 // listed in the case clauses are linked into the Go binary and therefore also
 // registered in the global registry.
 //
+// The message type is resolved from the full name at the end of the type URL,
+// regardless of its prefix. The TypeURLPrefix method reports the prefix,
+// for example to check that the Any message refers to a trusted registry.
+//
 // # Type checking an Any
 //
 // In order to type check whether an Any message represents some other message,
@@ -256,6 +266,17 @@ func New(src proto.Message) (*Any, error) {
 	return dst, nil
 }
 
+// NewWithPrefix marshals src into a new Any instance, whose type URL
+// is the full name of the message type prefixed with prefix.
+// A "/" is appended to prefix if it does not end with one.
+func NewWithPrefix(src proto.Message, prefix string) (*Any, error) {
+	dst := new(Any)
+	if err := MarshalFromWithPrefix(dst, src, prefix, proto.MarshalOptions{}); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
 // MarshalFrom marshals src into dst as the underlying message
 // using the provided marshal options.
 //
@@ -274,6 +295,28 @@ func MarshalFrom(dst *Any, src proto.Message, opts proto.MarshalOptions) error {
 	return nil
 }
 
+// MarshalFromWithPrefix marshals src into dst as the underlying message
+// using the provided marshal options, with a type URL prefixed with prefix
+// as described by NewWithPrefix.
+func MarshalFromWithPrefix(dst *Any, src proto.Message, prefix string, opts proto.MarshalOptions) error {
+	if prefix == "" {
+		return protoimpl.X.NewError("invalid empty type URL prefix")
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if src == nil {
+		return protoimpl.X.NewError("invalid nil source message")
+	}
+	b, err := opts.Marshal(src)
+	if err != nil {
+		return err
+	}
+	dst.TypeUrl = prefix + string(src.ProtoReflect().Descriptor().FullName())
+	dst.Value = b
+	return nil
+}
+
 // UnmarshalTo unmarshals the underlying message from src into dst
 // using the provided unmarshal options.
 // It reports an error if dst is not of the right message type.
@@ -347,6 +390,14 @@ func (x *Any) MessageName() protoreflect.FullName {
 	return name
 }
 
+// TypeURLPrefix reports the prefix of the type URL of x, which precedes
+// the full name of the underlying message and includes the trailing "/".
+// It returns an empty string if the type URL has no prefix.
+func (x *Any) TypeURLPrefix() string {
+	url := x.GetTypeUrl()
+	return url[:strings.LastIndexByte(url, '/')+1]
+}
+
 // MarshalFrom marshals m into x as the underlying message.
 func (x *Any) MarshalFrom(m proto.Message) error {
 	return MarshalFrom(x, m, proto.MarshalOptions{})
@@ -5,6 +5,7 @@
 package anypb_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -182,3 +183,53 @@ func TestRoundtrip(t *testing.T) {
 		}
 	}
 }
+
+func TestPrefix(t *testing.T) {
+	msg := wpb.String("hello, world")
+	for _, prefix := range []string{"schemas.example.com/", "schemas.example.com", "https://example.com/types/"} {
+		any, err := apb.NewWithPrefix(msg, prefix)
+		if err != nil {
+			t.Fatalf("NewWithPrefix(%q) error: %v", prefix, err)
+		}
+		wantPrefix := prefix
+		if !strings.HasSuffix(wantPrefix, "/") {
+			wantPrefix += "/"
+		}
+		wantAny := &apb.Any{
+			TypeUrl: wantPrefix + "google.protobuf.StringValue",
+			Value:   mustMarshal(msg),
+		}
+		if diff := cmp.Diff(wantAny, any, protocmp.Transform()); diff != "" {
+			t.Errorf("NewWithPrefix(%q) output mismatch (-want +got):\n%s", prefix, diff)
+		}
+		if got := any.TypeURLPrefix(); got != wantPrefix {
+			t.Errorf("TypeURLPrefix() = %q, want %q", got, wantPrefix)
+		}
+
+		// The message type is resolved regardless of the prefix.
+		gotPB := new(wpb.StringValue)
+		if err := any.UnmarshalTo(gotPB); err != nil {
+			t.Errorf("UnmarshalTo() error: %v", err)
+		}
+		if diff := cmp.Diff(msg, gotPB, protocmp.Transform()); diff != "" {
+			t.Errorf("UnmarshalTo() output mismatch (-want +got):\n%s", diff)
+		}
+		gotNew, err := any.UnmarshalNew()
+		if err != nil {
+			t.Errorf("UnmarshalNew() error: %v", err)
+		}
+		if diff := cmp.Diff(msg, gotNew, protocmp.Transform()); diff != "" {
+			t.Errorf("UnmarshalNew() output mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	if _, err := apb.NewWithPrefix(msg, ""); err == nil {
+		t.Errorf("NewWithPrefix with empty prefix succeeded, want error")
+	}
+	if _, err := apb.NewWithPrefix(nil, "schemas.example.com/"); err == nil {
+		t.Errorf("NewWithPrefix with nil message succeeded, want error")
+	}
+	if got := (&apb.Any{TypeUrl: "google.protobuf.StringValue"}).TypeURLPrefix(); got != "" {
+		t.Errorf("TypeURLPrefix() without prefix = %q, want empty string", got)
+	}
+}
@@ -58,6 +58,11 @@
 //		... // handle error
 //	}
 //
+// The AsDurationExact method performs the conversion reporting an error
+// for invalid durations instead of silently saturating the result, and
+// the ParseWithPolicy function parses durations with an explicit policy
+// for out-of-range values.
+//
 // Note that the documented limitations in duration.proto does not protect a
 // Duration from overflowing the representable range of a time.Duration in Go.
 // The AsDuration method uses saturation arithmetic such that an overflow clamps
@@ -74,10 +79,13 @@
 package durationpb
 
 import (
+	fmt "fmt"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	math "math"
 	reflect "reflect"
+	strconv "strconv"
+	strings "strings"
 	sync "sync"
 	time "time"
 )
@@ -223,8 +231,9 @@ const (
 	invalidNanosSign
 )
 
+const absDuration = 315576000000 // 10000yr * 365.25day/yr * 24hr/day * 60min/hr * 60sec/min
+
 func (x *Duration) check() uint {
-	const absDuration = 315576000000 // 10000yr * 365.25day/yr * 24hr/day * 60min/hr * 60sec/min
 	secs := x.GetSeconds()
 	nanos := x.GetNanos()
 	switch {
@@ -243,6 +252,152 @@ func (x *Duration) check() uint {
 	}
 }
 
+// RangePolicy specifies how ParseWithPolicy handles durations that are out
+// of the range of -10000 years to +10000 years or that a Duration cannot
+// represent.
+type RangePolicy int
+
+const (
+	// RangeError reports an error for such durations.
+	RangeError RangePolicy = iota
+	// RangeClamp replaces such durations with the nearest valid duration.
+	RangeClamp
+	// RangeSaturate keeps such durations unless a Duration cannot represent
+	// them, in which case they are replaced with the nearest one it can.
+	RangeSaturate
+)
+
+// AsDurationExact converts x to a time.Duration like AsDuration, but reports
+// an error if x is invalid or if a time.Duration cannot represent it.
+//
+// Unlike ParseWithPolicy, it takes no RangePolicy: since the range of
+// a time.Duration (about 292 years) lies within the range of valid durations,
+// both clamping to the range of valid durations and saturating to the range
+// of a time.Duration produce the result of AsDuration.
+func (x *Duration) AsDurationExact() (time.Duration, error) {
+	if err := x.CheckValid(); err != nil {
+		return 0, err
+	}
+	d := x.AsDuration()
+	if y := New(d); y.Seconds != x.Seconds || y.Nanos != x.Nanos {
+		return 0, protoimpl.X.NewError("duration (%v) overflows time.Duration", x)
+	}
+	return d, nil
+}
+
+// Parse parses a duration in the format of the JSON mapping of
+// google.protobuf.Duration, which is a decimal number of seconds with an
+// optional sign and up to nine fractional digits, followed by the suffix
+// "s", such as "3.5s" or "-0.000001s". Unlike time.ParseDuration,
+// it supports the full range of valid durations, which exceeds the
+// range of time.Duration.
+// It reports an error if s is malformed or the duration is invalid.
+func Parse(s string) (*Duration, error) {
+	return ParseWithPolicy(s, RangeError, SubnanosError)
+}
+
+// SubnanosPolicy specifies how ParseWithPolicy handles fractional digits
+// beyond nanosecond precision, such as in "0.0000000015s".
+type SubnanosPolicy int
+
+const (
+	// SubnanosError reports an error for digits beyond nanosecond precision.
+	SubnanosError SubnanosPolicy = iota
+	// SubnanosTruncate discards digits beyond nanosecond precision,
+	// which truncates the duration toward zero.
+	SubnanosTruncate
+	// SubnanosRound rounds the duration to the nearest nanosecond,
+	// rounding halfway cases away from zero.
+	SubnanosRound
+)
+
+// ParseWithPolicy is like Parse, but handles durations out of the range of
+// valid durations and digits beyond nanosecond precision according to the
+// policies. With RangeClamp, out-of-range durations are replaced with the
+// nearest valid duration. With RangeSaturate, they are kept, and seconds
+// beyond the range of an int64 are replaced with the nearest int64.
+// Parse is equivalent to ParseWithPolicy with RangeError and SubnanosError.
+func ParseWithPolicy(s string, rp RangePolicy, sp SubnanosPolicy) (*Duration, error) {
+	x, ok := parse(s, sp)
+	if !ok {
+		return nil, protoimpl.X.NewError("invalid duration %q", s)
+	}
+	switch rp {
+	case RangeClamp:
+		switch {
+		case x.Seconds > +absDuration:
+			x.Seconds, x.Nanos = +absDuration, +999999999
+		case x.Seconds < -absDuration:
+			x.Seconds, x.Nanos = -absDuration, -999999999
+		}
+	case RangeSaturate:
+	default:
+		if err := x.CheckValid(); err != nil {
+			return nil, err
+		}
+	}
+	return x, nil
+}
+
+func parse(s string, sp SubnanosPolicy) (*Duration, bool) {
+	if len(s) < 2 || s[len(s)-1] != 's' {
+		return nil, false
+	}
+	s = s[:len(s)-1]
+	neg := s[0] == '-'
+	if s[0] == '-' || s[0] == '+' {
+		s = s[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	switch {
+	case intPart == "" && fracPart == "",
+		len(intPart) > 1 && intPart[0] == '0',
+		len(fracPart) > 9 && sp != SubnanosTruncate && sp != SubnanosRound,
+		strings.Trim(intPart+fracPart, "0123456789") != "":
+		return nil, false
+	}
+	var secs, nanos int64
+	if intPart != "" {
+		// The digits are valid, so ParseInt only fails if they are out of
+		// range, in which case it returns the nearest int64.
+		secs, _ = strconv.ParseInt(intPart, 10, 64)
+	}
+	for i := 0; i < 9; i++ {
+		nanos *= 10
+		if i < len(fracPart) {
+			nanos += int64(fracPart[i] - '0')
+		}
+	}
+	// Round up by a nanosecond, unless the seconds are already saturated.
+	if len(fracPart) > 9 && sp == SubnanosRound && fracPart[9] >= '5' && secs < math.MaxInt64 {
+		if nanos++; nanos == 1e9 {
+			secs, nanos = secs+1, 0
+		}
+	}
+	if neg {
+		secs, nanos = -secs, -nanos
+	}
+	return &Duration{Seconds: secs, Nanos: int32(nanos)}, true
+}
+
+// Format returns the duration in the format of the JSON mapping of
+// google.protobuf.Duration, such as "3.5s" or "-0.000001s",
+// with 0, 3, 6, or 9 fractional digits as required by its precision.
+// The result is unspecified if x is invalid.
+func (x *Duration) Format() string {
+	secs, nanos := x.GetSeconds(), int64(x.GetNanos())
+	var sign string
+	if secs < 0 || nanos < 0 {
+		sign, secs, nanos = "-", -secs, -nanos
+	}
+	// The conversion to uint64 handles the negation of math.MinInt64.
+	s := fmt.Sprintf("%s%d.%09d", sign, uint64(secs), nanos)
+	s = strings.TrimSuffix(s, "000")
+	s = strings.TrimSuffix(s, "000")
+	s = strings.TrimSuffix(s, ".000")
+	return s + "s"
+}
+
 func (x *Duration) Reset() {
 	*x = Duration{}
 	mi := &file_google_protobuf_duration_proto_msgTypes[0]
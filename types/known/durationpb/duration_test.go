@@ -94,7 +94,114 @@ func TestFromDuration(t *testing.T) {
 	}
 }
 
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    *durpb.Duration
+		format  string // canonical form, if different from in
+		wantErr bool
+	}{
+		{in: "0s", want: &durpb.Duration{}},
+		{in: "3s", want: &durpb.Duration{Seconds: 3}},
+		{in: "3.5s", want: &durpb.Duration{Seconds: 3, Nanos: 5e8}, format: "3.500s"},
+		{in: "3.500s", want: &durpb.Duration{Seconds: 3, Nanos: 5e8}},
+		{in: "-0.000001s", want: &durpb.Duration{Nanos: -1000}},
+		{in: "+1.000000001s", want: &durpb.Duration{Seconds: 1, Nanos: 1}, format: "1.000000001s"},
+		{in: ".5s", want: &durpb.Duration{Nanos: 5e8}, format: "0.500s"},
+		{in: "1.s", want: &durpb.Duration{Seconds: 1}, format: "1s"},
+		{in: "315576000000s", want: &durpb.Duration{Seconds: 315576000000}},
+		{in: "-315576000000.999999999s", want: &durpb.Duration{Seconds: -315576000000, Nanos: -999999999}},
+		{in: "", wantErr: true},
+		{in: "s", wantErr: true},
+		{in: "3", wantErr: true},
+		{in: "-s", wantErr: true},
+		{in: ".s", wantErr: true},
+		{in: "01s", wantErr: true},
+		{in: "1.0000000001s", wantErr: true},
+		{in: "1e3s", wantErr: true},
+		{in: "--1s", wantErr: true},
+		{in: "315576000001s", wantErr: true},
+		{in: "99999999999999999999s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := durpb.Parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("Parse(%q) mismatch (-want +got):\n%s", tt.in, diff)
+		}
+		format := tt.format
+		if format == "" {
+			format = tt.in
+		}
+		if got := got.Format(); got != format {
+			t.Errorf("Format(%v) = %q, want %q", tt.want, got, format)
+		}
+	}
+}
+
 type textError string
 
 func (e textError) Error() string     { return string(e) }
 func (e textError) Is(err error) bool { return err != nil && strings.Contains(err.Error(), e.Error()) }
+
+func TestPolicy(t *testing.T) {
+	maxGoDuration := &durpb.Duration{Seconds: maxGoSeconds, Nanos: int32(math.MaxInt64 % int64(1e9))}
+	for _, tt := range []struct {
+		in      *durpb.Duration
+		want    time.Duration
+		wantErr error
+	}{
+		{in: maxGoDuration, want: math.MaxInt64},
+		{in: &durpb.Duration{Seconds: -1, Nanos: -5}, want: -time.Second - 5},
+		{in: &durpb.Duration{Seconds: maxGoSeconds + 1}, wantErr: textError("overflows time.Duration")},
+		{in: &durpb.Duration{Seconds: minGoSeconds - 1}, wantErr: textError("overflows time.Duration")},
+		{in: &durpb.Duration{Seconds: absSeconds + 1}, wantErr: textError("exceeds +10000 years")},
+		{in: &durpb.Duration{Seconds: 1, Nanos: -1}, wantErr: textError("different signs")},
+		{in: nil, wantErr: textError("invalid nil Duration")},
+	} {
+		got, err := tt.in.AsDurationExact()
+		if diff := cmp.Diff(tt.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+			t.Errorf("AsDurationExact(%v) error mismatch (-want +got):\n%s", tt.in, diff)
+		}
+		if got != tt.want {
+			t.Errorf("AsDurationExact(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		in      string
+		rp      durpb.RangePolicy
+		sp      durpb.SubnanosPolicy
+		want    *durpb.Duration
+		wantErr bool
+	}{
+		{in: "1.0000000015s", sp: durpb.SubnanosError, wantErr: true},
+		{in: "1.0000000015s", sp: durpb.SubnanosTruncate, want: &durpb.Duration{Seconds: 1, Nanos: 1}},
+		{in: "1.0000000015s", sp: durpb.SubnanosRound, want: &durpb.Duration{Seconds: 1, Nanos: 2}},
+		{in: "-1.0000000014s", sp: durpb.SubnanosRound, want: &durpb.Duration{Seconds: -1, Nanos: -1}},
+		{in: "-0.9999999995s", sp: durpb.SubnanosRound, want: &durpb.Duration{Seconds: -1}},
+		{in: "1.00000000x5s", sp: durpb.SubnanosTruncate, wantErr: true},
+		{in: "315576000001s", rp: durpb.RangeError, wantErr: true},
+		{in: "315576000001s", rp: durpb.RangeClamp, want: &durpb.Duration{Seconds: absSeconds, Nanos: 999999999}},
+		{in: "-315576000001s", rp: durpb.RangeClamp, want: &durpb.Duration{Seconds: -absSeconds, Nanos: -999999999}},
+		{in: "315576000001s", rp: durpb.RangeSaturate, want: &durpb.Duration{Seconds: absSeconds + 1}},
+		{in: "99999999999999999999s", rp: durpb.RangeSaturate, want: &durpb.Duration{Seconds: math.MaxInt64}},
+		{in: "99999999999999999999.5s", rp: durpb.RangeSaturate, sp: durpb.SubnanosRound, want: &durpb.Duration{Seconds: math.MaxInt64, Nanos: 5e8}},
+	} {
+		got, err := durpb.ParseWithPolicy(tt.in, tt.rp, tt.sp)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseWithPolicy(%q, %v, %v) error = %v, want error %v", tt.in, tt.rp, tt.sp, err, tt.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("ParseWithPolicy(%q, %v, %v) mismatch (-want +got):\n%s", tt.in, tt.rp, tt.sp, diff)
+		}
+	}
+}
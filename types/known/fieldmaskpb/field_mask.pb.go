@@ -299,6 +299,18 @@ func New(m proto.Message, paths ...string) (*FieldMask, error) {
 	return x, x.Append(m, paths...)
 }
 
+// Diff constructs a field mask of the fields that differ between
+// the old and new messages, as reported by proto.Diff.
+// Applying the fields in the mask from the new message to the old message
+// makes them equal, except for any extension and unknown fields.
+func Diff(old, new proto.Message) *FieldMask {
+	x := &FieldMask{}
+	for _, c := range proto.Diff(old, new) {
+		x.Paths = append(x.Paths, c.Path)
+	}
+	return x
+}
+
 // Union returns the union of all the paths in the input field masks.
 func Union(mx *FieldMask, my *FieldMask, ms ...*FieldMask) *FieldMask {
 	var out []string
@@ -365,6 +377,40 @@ func (x *FieldMask) Append(m proto.Message, paths ...string) error {
 	return nil
 }
 
+// NewFromJSON constructs a field mask from a list of paths in the JSON format,
+// where fields are named by their lowerCamelCase JSON names (e.g., "fooBar.baz"),
+// and verifies that each one is valid according to the specified message type.
+// Fields may also be named by their proto names, such that paths in either
+// format are converted to the canonical format used by the FieldMask message.
+func NewFromJSON(m proto.Message, paths ...string) (*FieldMask, error) {
+	x := new(FieldMask)
+	md := m.ProtoReflect().Descriptor()
+	for _, path := range paths {
+		p, ok := convertPath(md, path, false)
+		if !ok {
+			return x, protoimpl.X.NewError("invalid path %q for message %q", path, md.FullName())
+		}
+		x.Paths = append(x.Paths, p)
+	}
+	return x, nil
+}
+
+// JSONPaths returns the paths in the JSON format, where fields are named by
+// their lowerCamelCase JSON names according to the specified message type.
+// It reports an error if any path is not valid for the message type.
+func (x *FieldMask) JSONPaths(m proto.Message) ([]string, error) {
+	md := m.ProtoReflect().Descriptor()
+	var out []string
+	for _, path := range x.GetPaths() {
+		p, ok := convertPath(md, path, true)
+		if !ok {
+			return nil, protoimpl.X.NewError("invalid path %q for message %q", path, md.FullName())
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
 func numValidPaths(m proto.Message, paths []string) int {
 	md0 := m.ProtoReflect().Descriptor()
 	for i, path := range paths {
@@ -374,16 +420,7 @@ func numValidPaths(m proto.Message, paths []string) int {
 			if md == nil {
 				return false // not within a message
 			}
-			fd := md.Fields().ByName(protoreflect.Name(field))
-			// The real field name of a group is the message name.
-			if fd == nil {
-				gd := md.Fields().ByName(protoreflect.Name(strings.ToLower(field)))
-				if gd != nil && gd.Kind() == protoreflect.GroupKind && string(gd.Message().Name()) == field {
-					fd = gd
-				}
-			} else if fd.Kind() == protoreflect.GroupKind && string(fd.Message().Name()) != field {
-				fd = nil
-			}
+			fd := fieldByName(md, field)
 			if fd == nil {
 				return false // message has does not have this field
 			}
@@ -404,6 +441,56 @@ func numValidPaths(m proto.Message, paths []string) int {
 	return len(paths)
 }
 
+// fieldByName returns the field in the message with the specified name.
+func fieldByName(md protoreflect.MessageDescriptor, field string) protoreflect.FieldDescriptor {
+	fd := md.Fields().ByName(protoreflect.Name(field))
+	// The real field name of a group is the message name.
+	if fd == nil {
+		gd := md.Fields().ByName(protoreflect.Name(strings.ToLower(field)))
+		if gd != nil && gd.Kind() == protoreflect.GroupKind && string(gd.Message().Name()) == field {
+			fd = gd
+		}
+	} else if fd.Kind() == protoreflect.GroupKind && string(fd.Message().Name()) != field {
+		fd = nil
+	}
+	return fd
+}
+
+// convertPath converts a path between the proto names and the JSON names
+// of fields in the specified message type.
+// It reports false if the path is not valid for the message type.
+func convertPath(md protoreflect.MessageDescriptor, path string, toJSON bool) (string, bool) {
+	var b []byte
+	ok := rangeFields(path, func(field string) bool {
+		if md == nil {
+			return false // not within a message
+		}
+		fd := fieldByName(md, field)
+		if fd == nil && !toJSON {
+			fd = md.Fields().ByJSONName(field)
+		}
+		if fd == nil {
+			return false // message does not have this field
+		}
+		if len(b) > 0 {
+			b = append(b, '.')
+		}
+		if toJSON {
+			b = append(b, fd.JSONName()...)
+		} else {
+			b = append(b, fd.TextName()...)
+		}
+
+		// Repeated fields are only allowed at the last position.
+		md = fd.Message() // may be nil
+		if fd.IsList() || fd.IsMap() {
+			md = nil
+		}
+		return true
+	})
+	return string(b), ok
+}
+
 // Normalize converts the mask to its canonical form where all paths are sorted
 // and redundant paths are removed.
 func (x *FieldMask) Normalize() {
@@ -336,3 +336,95 @@ func TestIsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestDiff(t *testing.T) {
+	old := &testpb.TestAllTypes{
+		OptionalInt32: proto.Int32(1),
+		Optionalgroup: &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(1)},
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			Corecursive: &testpb.TestAllTypes{RepeatedInt32: []int32{1}},
+		},
+	}
+	new := &testpb.TestAllTypes{
+		OptionalInt32: proto.Int32(1),
+		Optionalgroup: &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(2)},
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			Corecursive: &testpb.TestAllTypes{RepeatedInt32: []int32{1, 2}},
+		},
+		OptionalString: proto.String("a"),
+	}
+	mask := fmpb.Diff(old, new)
+	want := []string{"optional_string", "OptionalGroup.a", "optional_nested_message.corecursive.repeated_int32"}
+	if diff := cmp.Diff(want, mask.GetPaths()); diff != "" {
+		t.Errorf("Diff() paths mismatch (-want +got):\n%s", diff)
+	}
+	if !mask.IsValid(old) {
+		t.Errorf("Diff() = %v, not valid for %v", mask, old.ProtoReflect().Descriptor().FullName())
+	}
+	if mask := fmpb.Diff(old, old); len(mask.GetPaths()) != 0 {
+		t.Errorf("Diff() of equal messages = %v, want empty", mask)
+	}
+}
+
+func TestJSONPaths(t *testing.T) {
+	tests := []struct {
+		jsonPaths []string
+		paths     []string
+		wantError error
+	}{{
+		jsonPaths: []string{"optionalInt32", "optionalNestedMessage.corecursive.repeatedInt32", "optionalgroup.a", "mapStringString"},
+		paths:     []string{"optional_int32", "optional_nested_message.corecursive.repeated_int32", "OptionalGroup.a", "map_string_string"},
+	}, {
+		jsonPaths: []string{"optional_int32", "optional_nested_message.corecursive", "OptionalGroup"},
+		paths:     []string{"optional_int32", "optional_nested_message.corecursive", "OptionalGroup"},
+	}, {
+		jsonPaths: []string{"optionalInt32", "noSuchField"},
+		paths:     []string{"optional_int32"},
+		wantError: cmpopts.AnyError,
+	}, {
+		jsonPaths: []string{"repeatedNestedMessage.a"},
+		wantError: cmpopts.AnyError,
+	}, {
+		jsonPaths: []string{"optionalInt32."},
+		wantError: cmpopts.AnyError,
+	}}
+	m := (*testpb.TestAllTypes)(nil)
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			mask, err := fmpb.NewFromJSON(m, tt.jsonPaths...)
+			if diff := cmp.Diff(tt.paths, mask.GetPaths(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("NewFromJSON() paths mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantError, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("NewFromJSON() error mismatch (-want +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if !mask.IsValid(m) {
+				t.Errorf("NewFromJSON() = %v, not valid", mask)
+			}
+
+			// Converting back yields the canonical JSON names.
+			got, err := mask.JSONPaths(m)
+			if err != nil {
+				t.Fatalf("JSONPaths() error: %v", err)
+			}
+			again, err := fmpb.NewFromJSON(m, got...)
+			if err != nil {
+				t.Fatalf("NewFromJSON() error: %v", err)
+			}
+			if diff := cmp.Diff(tt.paths, again.GetPaths()); diff != "" {
+				t.Errorf("NewFromJSON(JSONPaths()) mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	got, err := (&fmpb.FieldMask{Paths: []string{"optional_nested_message.a", "OptionalGroup"}}).JSONPaths(m)
+	if diff := cmp.Diff([]string{"optionalNestedMessage.a", "optionalgroup"}, got); diff != "" || err != nil {
+		t.Errorf("JSONPaths() = %v, %v, want no error and (-want +got):\n%s", got, err, diff)
+	}
+	if _, err := (&fmpb.FieldMask{Paths: []string{"optionalInt32"}}).JSONPaths(m); err == nil {
+		t.Errorf("JSONPaths() of JSON path succeeded, want error")
+	}
+}
@@ -122,10 +122,13 @@ import (
 	base64 "encoding/base64"
 	json "encoding/json"
 	protojson "google.golang.org/protobuf/encoding/protojson"
+	proto "google.golang.org/protobuf/proto"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	math "math"
 	reflect "reflect"
+	strconv "strconv"
+	strings "strings"
 	sync "sync"
 	utf8 "unicode/utf8"
 )
@@ -224,6 +227,90 @@ func (x *Struct) AsMap() map[string]any {
 	return vs
 }
 
+// Merge merges the fields of src into x according to the policy.
+// Values that are structs in both x and src are merged recursively.
+// The values of src are copied, so x does not alias src after the merge.
+func (x *Struct) Merge(src *Struct, policy MergePolicy) {
+	if len(src.GetFields()) == 0 {
+		return
+	}
+	if x.Fields == nil {
+		x.Fields = make(map[string]*Value, len(src.GetFields()))
+	}
+	for k, sv := range src.GetFields() {
+		dv, ok := x.Fields[k]
+		switch {
+		case ok && dv.GetStructValue() != nil && sv.GetStructValue() != nil:
+			dv.GetStructValue().Merge(sv.GetStructValue(), policy)
+		case ok && policy == MergeKeepExisting:
+		case ok && policy == MergeAppendLists && dv.GetListValue() != nil && sv.GetListValue() != nil:
+			dl := dv.GetListValue()
+			for _, v := range sv.GetListValue().GetValues() {
+				dl.Values = append(dl.Values, proto.Clone(v).(*Value))
+			}
+		default:
+			x.Fields[k] = proto.Clone(sv).(*Value)
+		}
+	}
+}
+
+// GetPath returns the value at the path within x,
+// or nil if the path is malformed or there is no such value.
+//
+// A path is a sequence of keys separated by periods, each of which may be
+// followed by the indexes of list elements in brackets, such as "a.b[0].c".
+// Keys that contain periods or brackets may be given as quoted strings
+// in brackets, such as `a["b.c"]`.
+func (x *Struct) GetPath(path string) *Value {
+	elems, ok := parsePath(path)
+	if !ok {
+		return nil
+	}
+	v := &Value{Kind: &Value_StructValue{StructValue: x}}
+	for _, e := range elems {
+		if v = e.get(v); v == nil {
+			return nil
+		}
+	}
+	return v
+}
+
+// SetPath sets the value at the path within x to v.
+// The syntax of the path is described by GetPath.
+// Structs and lists are created for elements of the path
+// that are not present, and an index equal to the length of a list
+// appends to the list. It reports an error if the path is malformed
+// or does not match the kinds of the values in x.
+func (x *Struct) SetPath(path string, v *Value) error {
+	elems, ok := parsePath(path)
+	if !ok {
+		return protoimpl.X.NewError("invalid path %q", path)
+	}
+	cur := &Value{Kind: &Value_StructValue{StructValue: x}}
+	for i, e := range elems {
+		next := v
+		if i < len(elems)-1 {
+			if next = e.get(cur); next != nil {
+				cur = next
+				continue
+			}
+			next = &Value{Kind: &Value_StructValue{StructValue: &Struct{}}}
+			if elems[i+1].index >= 0 {
+				next = &Value{Kind: &Value_ListValue{ListValue: &ListValue{}}}
+			}
+		}
+		if !e.set(cur, next) {
+			var start int
+			if i > 0 {
+				start = elems[i-1].end
+			}
+			return protoimpl.X.NewError("invalid path %q: no struct or list for %q at %q", path, path[start:e.end], path[:start])
+		}
+		cur = next
+	}
+	return nil
+}
+
 func (x *Struct) MarshalJSON() ([]byte, error) {
 	return protojson.Marshal(x)
 }
@@ -232,6 +319,112 @@ func (x *Struct) UnmarshalJSON(b []byte) error {
 	return protojson.Unmarshal(b, x)
 }
 
+// MergePolicy specifies how Struct.Merge combines values
+// that are present in both structs.
+type MergePolicy int
+
+const (
+	// MergeReplace replaces existing values with the values being merged.
+	MergeReplace MergePolicy = iota
+	// MergeAppendLists replaces existing values with the values being merged,
+	// except that lists are appended to existing lists.
+	MergeAppendLists
+	// MergeKeepExisting keeps existing values, adding only the values
+	// for keys that are not present.
+	MergeKeepExisting
+)
+
+// pathElem is an element of a path accepted by Struct.GetPath.
+type pathElem struct {
+	key   string
+	index int // index of a list element, or -1 for a key
+	end   int // offset of the end of the element in the path
+}
+
+// parsePath parses a path of the syntax described by Struct.GetPath.
+func parsePath(path string) ([]pathElem, bool) {
+	var elems []pathElem
+	for i := 0; i < len(path); {
+		e := pathElem{index: -1}
+		if path[i] == '[' {
+			rest := path[i+1:]
+			if q, err := strconv.QuotedPrefix(rest); err == nil {
+				e.key, _ = strconv.Unquote(q)
+				rest = rest[len(q):]
+			} else {
+				n := 0
+				for n < len(rest) && '0' <= rest[n] && rest[n] <= '9' {
+					n++
+				}
+				if e.index, err = strconv.Atoi(rest[:n]); err != nil {
+					return nil, false
+				}
+				rest = rest[n:]
+			}
+			if !strings.HasPrefix(rest, "]") {
+				return nil, false
+			}
+			i = len(path) - len(rest) + 1
+		} else {
+			if i > 0 {
+				if path[i] != '.' {
+					return nil, false
+				}
+				i++
+			}
+			n := strings.IndexAny(path[i:], ".[")
+			if n < 0 {
+				n = len(path) - i
+			}
+			if n == 0 {
+				return nil, false
+			}
+			e.key = path[i : i+n]
+			i += n
+		}
+		e.end = i
+		elems = append(elems, e)
+	}
+	return elems, len(elems) > 0
+}
+
+// get returns the element of v, or nil if there is none.
+func (e pathElem) get(v *Value) *Value {
+	if e.index < 0 {
+		return v.GetStructValue().GetFields()[e.key]
+	}
+	if vs := v.GetListValue().GetValues(); e.index < len(vs) {
+		return vs[e.index]
+	}
+	return nil
+}
+
+// set sets the element of v to w. It reports false if v is not a struct
+// for a key, or a list with at least as many elements as the index.
+func (e pathElem) set(v, w *Value) bool {
+	if e.index < 0 {
+		s := v.GetStructValue()
+		if s == nil {
+			return false
+		}
+		if s.Fields == nil {
+			s.Fields = make(map[string]*Value)
+		}
+		s.Fields[e.key] = w
+		return true
+	}
+	l := v.GetListValue()
+	switch {
+	case l == nil || e.index > len(l.Values):
+		return false
+	case e.index == len(l.Values):
+		l.Values = append(l.Values, w)
+	default:
+		l.Values[e.index] = w
+	}
+	return true
+}
+
 func (x *Struct) Reset() {
 	*x = Struct{}
 	mi := &file_google_protobuf_struct_proto_msgTypes[0]
@@ -573,3 +573,144 @@ func TestFromValue(t *testing.T) {
 		}
 	}
 }
+
+func TestMerge(t *testing.T) {
+	mustStruct := func(m map[string]any) *spb.Struct {
+		s, err := spb.NewStruct(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	dst := func() map[string]any {
+		return map[string]any{
+			"a": "x",
+			"b": map[string]any{"c": 1, "d": []any{1}},
+			"e": []any{"e0"},
+		}
+	}
+	src := map[string]any{
+		"a": "y",
+		"b": map[string]any{"d": []any{2}, "f": true},
+		"e": []any{"e1"},
+		"g": nil,
+	}
+	tests := []struct {
+		policy spb.MergePolicy
+		want   map[string]any
+	}{{
+		policy: spb.MergeReplace,
+		want: map[string]any{
+			"a": "y",
+			"b": map[string]any{"c": 1, "d": []any{2}, "f": true},
+			"e": []any{"e1"},
+			"g": nil,
+		},
+	}, {
+		policy: spb.MergeAppendLists,
+		want: map[string]any{
+			"a": "y",
+			"b": map[string]any{"c": 1, "d": []any{1, 2}, "f": true},
+			"e": []any{"e0", "e1"},
+			"g": nil,
+		},
+	}, {
+		policy: spb.MergeKeepExisting,
+		want: map[string]any{
+			"a": "x",
+			"b": map[string]any{"c": 1, "d": []any{1}, "f": true},
+			"e": []any{"e0"},
+			"g": nil,
+		},
+	}}
+
+	for _, tt := range tests {
+		got := mustStruct(dst())
+		s := mustStruct(src)
+		got.Merge(s, tt.policy)
+		if diff := cmp.Diff(mustStruct(tt.want), got, protocmp.Transform()); diff != "" {
+			t.Errorf("Merge with policy %v mismatch (-want +got):\n%s", tt.policy, diff)
+		}
+
+		// The merged struct does not alias the source.
+		got.GetFields()["b"].GetStructValue().GetFields()["f"] = spb.NewBoolValue(false)
+		if !s.GetFields()["b"].GetStructValue().GetFields()["f"].GetBoolValue() {
+			t.Errorf("Merge with policy %v: modifying the result modified the source", tt.policy)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	s, err := spb.NewStruct(map[string]any{
+		"a": map[string]any{
+			"b": []any{
+				map[string]any{"c": "c0"},
+				[]any{1, 2},
+			},
+			"d.e": "quoted",
+		},
+		"f": 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	getTests := []struct {
+		path string
+		want *spb.Value
+	}{
+		{path: "f", want: spb.NewNumberValue(3)},
+		{path: "a.b[0].c", want: spb.NewStringValue("c0")},
+		{path: "a.b[1][1]", want: spb.NewNumberValue(2)},
+		{path: `a["d.e"]`, want: spb.NewStringValue("quoted")},
+		{path: `["a"].b[0]["c"]`, want: spb.NewStringValue("c0")},
+		{path: "a.b[2]"},
+		{path: "a.b.c"},
+		{path: "f.g"},
+		{path: "missing"},
+		{path: ""},
+		{path: ".a"},
+		{path: "a."},
+		{path: "a..b"},
+		{path: "a.b[]"},
+		{path: "a.b[-1]"},
+		{path: "a.b[0"},
+		{path: "a.b[0]c"},
+	}
+	for _, tt := range getTests {
+		got := s.GetPath(tt.path)
+		if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("GetPath(%q) mismatch (-want +got):\n%s", tt.path, diff)
+		}
+	}
+
+	setTests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{path: "f"},
+		{path: "a.b[0].c"},
+		{path: "a.b[2]"},
+		{path: "a.b[1][2]"},
+		{path: `a["x.y"]`},
+		{path: "new.list[0].key"},
+		{path: "a.b[4]", wantErr: true},
+		{path: "f.g", wantErr: true},
+		{path: "a.b.c", wantErr: true},
+		{path: "a..b", wantErr: true},
+	}
+	for _, tt := range setTests {
+		v := spb.NewStringValue(tt.path)
+		err := s.SetPath(tt.path, v)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SetPath(%q) error = %v, want error %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if err == nil && s.GetPath(tt.path) != v {
+			t.Errorf("GetPath(%q) after SetPath = %v, want %v", tt.path, s.GetPath(tt.path), v)
+		}
+	}
+	if got := len(s.GetPath("a.b").GetListValue().GetValues()); got != 3 {
+		t.Errorf("len(GetPath(a.b)) after SetPath = %v, want 3", got)
+	}
+}
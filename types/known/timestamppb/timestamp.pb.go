@@ -58,6 +58,10 @@
 //		... // handle error
 //	}
 //
+// The AsTimeWithPolicy method and the NewWithPolicy function perform
+// the conversion with an explicit policy for invalid timestamps, such as
+// clamping times beyond the year 9999 to the latest valid timestamp.
+//
 // # Conversion from a Go Time
 //
 // The timestamppb.New function can be used to construct a Timestamp message
@@ -75,6 +79,9 @@ package timestamppb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	math "math"
+	bits "math/bits"
 	reflect "reflect"
 	sync "sync"
 	time "time"
@@ -233,9 +240,16 @@ const (
 	invalidNanos
 )
 
+const (
+	minTimestamp = -62135596800  // Seconds between 1970-01-01T00:00:00Z and 0001-01-01T00:00:00Z, inclusive
+	maxTimestamp = +253402300799 // Seconds between 1970-01-01T00:00:00Z and 9999-12-31T23:59:59Z, inclusive
+
+	// maxTimeSeconds is the largest number of seconds since the Unix epoch
+	// that a time.Time can represent.
+	maxTimeSeconds = math.MaxInt64 - 62135596800
+)
+
 func (x *Timestamp) check() uint {
-	const minTimestamp = -62135596800  // Seconds between 1970-01-01T00:00:00Z and 0001-01-01T00:00:00Z, inclusive
-	const maxTimestamp = +253402300799 // Seconds between 1970-01-01T00:00:00Z and 9999-12-31T23:59:59Z, inclusive
 	secs := x.GetSeconds()
 	nanos := x.GetNanos()
 	switch {
@@ -252,6 +266,144 @@ func (x *Timestamp) check() uint {
 	}
 }
 
+// RangePolicy specifies how a conversion handles timestamps that are out of
+// the range of 0001-01-01T00:00:00Z to 9999-12-31T23:59:59.999999999Z.
+type RangePolicy int
+
+const (
+	// RangeError reports an error for out-of-range and otherwise invalid
+	// timestamps, including a nil Timestamp.
+	RangeError RangePolicy = iota
+	// RangeClamp replaces out-of-range timestamps with the nearest valid
+	// timestamp, such as 9999-12-31T23:59:59.999999999Z for the year 10000.
+	RangeClamp
+	// RangeSaturate keeps out-of-range timestamps unless a time.Time cannot
+	// represent them, in which case they are replaced with the nearest
+	// time that it can represent.
+	RangeSaturate
+)
+
+// NewWithPolicy constructs a new Timestamp from the provided time.Time,
+// handling times out of the range of valid timestamps according to the policy.
+// Like a Timestamp, a time.Time does not represent leap seconds,
+// so no adjustment is made for them.
+func NewWithPolicy(t time.Time, policy RangePolicy) (*Timestamp, error) {
+	return New(t).withPolicy(policy)
+}
+
+// AsTimeWithPolicy converts x to a time.Time, handling timestamps out of
+// the range of valid timestamps according to the policy. With RangeClamp and
+// RangeSaturate, a nil Timestamp converts to the Unix epoch and out-of-range
+// nanos are normalized, as with AsTime.
+func (x *Timestamp) AsTimeWithPolicy(policy RangePolicy) (time.Time, error) {
+	y, err := x.withPolicy(policy)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return y.AsTime(), nil
+}
+
+// withPolicy returns x, or the timestamp that replaces x according to the policy.
+func (x *Timestamp) withPolicy(policy RangePolicy) (*Timestamp, error) {
+	var lo, hi int64
+	switch policy {
+	case RangeClamp:
+		lo, hi = minTimestamp, maxTimestamp
+	case RangeSaturate:
+		lo, hi = math.MinInt64, maxTimeSeconds
+	default:
+		if err := x.CheckValid(); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+	t := normalizeTimestamp(x.GetSeconds(), int64(x.GetNanos()))
+	switch {
+	case t.Seconds < lo:
+		return &Timestamp{Seconds: lo}, nil
+	case t.Seconds > hi:
+		return &Timestamp{Seconds: hi, Nanos: 999999999}, nil
+	}
+	return t, nil
+}
+
+// Add returns the timestamp x+d.
+// The result may be invalid if it is out of the range of valid timestamps.
+func (x *Timestamp) Add(d *durationpb.Duration) *Timestamp {
+	return normalizeTimestamp(x.GetSeconds()+d.GetSeconds(), int64(x.GetNanos())+int64(d.GetNanos()))
+}
+
+// Sub returns the duration x-y.
+func (x *Timestamp) Sub(y *Timestamp) *durationpb.Duration {
+	t := normalizeTimestamp(x.GetSeconds()-y.GetSeconds(), int64(x.GetNanos())-int64(y.GetNanos()))
+	secs, nanos := t.Seconds, t.Nanos
+	if secs < 0 && nanos > 0 {
+		// The seconds and nanos of a duration must have the same sign.
+		secs++
+		nanos -= 1e9
+	}
+	return &durationpb.Duration{Seconds: secs, Nanos: nanos}
+}
+
+// Compare compares x and y. It returns -1 if x is before y,
+// +1 if x is after y, and 0 if they are the same instant.
+// A nil Timestamp is treated as the Unix epoch.
+func (x *Timestamp) Compare(y *Timestamp) int {
+	a := normalizeTimestamp(x.GetSeconds(), int64(x.GetNanos()))
+	b := normalizeTimestamp(y.GetSeconds(), int64(y.GetNanos()))
+	switch {
+	case a.Seconds < b.Seconds || (a.Seconds == b.Seconds && a.Nanos < b.Nanos):
+		return -1
+	case a.Seconds > b.Seconds || (a.Seconds == b.Seconds && a.Nanos > b.Nanos):
+		return +1
+	default:
+		return 0
+	}
+}
+
+// Before reports whether x is before y.
+func (x *Timestamp) Before(y *Timestamp) bool {
+	return x.Compare(y) < 0
+}
+
+// After reports whether x is after y.
+func (x *Timestamp) After(y *Timestamp) bool {
+	return x.Compare(y) > 0
+}
+
+// Truncate returns the result of rounding x down to a multiple of d
+// since the Unix epoch. If d <= 0, Truncate returns a copy of x.
+// Unlike time.Time.Truncate, the result does not depend on the
+// leap seconds and the proleptic calendar used by the time package.
+func (x *Timestamp) Truncate(d time.Duration) *Timestamp {
+	t := normalizeTimestamp(x.GetSeconds(), int64(x.GetNanos()))
+	if d <= 0 {
+		return t
+	}
+	// Compute the remainder of secs*1e9+nanos divided by d
+	// using 128-bit arithmetic to avoid overflow.
+	n := uint64(d)
+	secs := t.Seconds % int64(n)
+	if secs < 0 {
+		secs += int64(n)
+	}
+	hi, lo := bits.Mul64(uint64(secs), 1e9)
+	r := (bits.Rem64(hi, lo, n) + uint64(t.Nanos)) % n
+	return normalizeTimestamp(t.Seconds-int64(r/1e9), int64(t.Nanos)-int64(r%1e9))
+}
+
+// normalizeTimestamp returns the timestamp secs+nanos*1e-9
+// with nanos in the range [0, 1e9).
+func normalizeTimestamp(secs, nanos int64) *Timestamp {
+	secs += nanos / 1e9
+	nanos %= 1e9
+	if nanos < 0 {
+		secs--
+		nanos += 1e9
+	}
+	return &Timestamp{Seconds: secs, Nanos: int32(nanos)}
+}
+
 func (x *Timestamp) Reset() {
 	*x = Timestamp{}
 	mi := &file_google_protobuf_timestamp_proto_msgTypes[0]
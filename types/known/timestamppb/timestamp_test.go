@@ -15,6 +15,7 @@ import (
 	"google.golang.org/protobuf/internal/detrand"
 	"google.golang.org/protobuf/testing/protocmp"
 
+	durpb "google.golang.org/protobuf/types/known/durationpb"
 	tspb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -102,3 +103,138 @@ type textError string
 
 func (e textError) Error() string     { return string(e) }
 func (e textError) Is(err error) bool { return err != nil && strings.Contains(err.Error(), e.Error()) }
+
+func TestArithmetic(t *testing.T) {
+	ts := func(secs int64, nanos int32) *tspb.Timestamp {
+		return &tspb.Timestamp{Seconds: secs, Nanos: nanos}
+	}
+	dur := func(secs int64, nanos int32) *durpb.Duration {
+		return &durpb.Duration{Seconds: secs, Nanos: nanos}
+	}
+	opts := protocmp.Transform()
+
+	addTests := []struct {
+		x    *tspb.Timestamp
+		d    *durpb.Duration
+		want *tspb.Timestamp
+	}{
+		{ts(10, 5e8), dur(1, 6e8), ts(12, 1e8)},
+		{ts(10, 5e8), dur(-1, -6e8), ts(8, 9e8)},
+		{ts(0, 0), dur(0, -1), ts(-1, 1e9-1)},
+		{nil, dur(3, 0), ts(3, 0)},
+		{ts(maxTimestamp, 0), nil, ts(maxTimestamp, 0)},
+	}
+	for _, tt := range addTests {
+		if got := tt.x.Add(tt.d); !cmp.Equal(got, tt.want, opts) {
+			t.Errorf("%v.Add(%v) = %v, want %v", tt.x, tt.d, got, tt.want)
+		}
+		if tt.d.GetSeconds() != 0 || tt.d.GetNanos() != 0 {
+			if got := tt.want.Sub(tt.x); !cmp.Equal(got, tt.d, opts) {
+				t.Errorf("%v.Sub(%v) = %v, want %v", tt.want, tt.x, got, tt.d)
+			}
+		}
+	}
+
+	compareTests := []struct {
+		x, y *tspb.Timestamp
+		want int
+	}{
+		{ts(1, 0), ts(1, 0), 0},
+		{ts(1, 0), ts(1, 1), -1},
+		{ts(2, 0), ts(1, 999999999), +1},
+		{nil, ts(0, 0), 0},
+		{ts(-1, 0), nil, -1},
+	}
+	for _, tt := range compareTests {
+		if got := tt.x.Compare(tt.y); got != tt.want {
+			t.Errorf("%v.Compare(%v) = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+		if got := tt.x.Before(tt.y); got != (tt.want < 0) {
+			t.Errorf("%v.Before(%v) = %v", tt.x, tt.y, got)
+		}
+		if got := tt.x.After(tt.y); got != (tt.want > 0) {
+			t.Errorf("%v.After(%v) = %v", tt.x, tt.y, got)
+		}
+	}
+
+	truncateTests := []struct {
+		x    *tspb.Timestamp
+		d    time.Duration
+		want *tspb.Timestamp
+	}{
+		{ts(3661, 5e8), time.Hour, ts(3600, 0)},
+		{ts(3661, 5e8), time.Millisecond, ts(3661, 5e8)},
+		{ts(3661, 123456789), time.Millisecond, ts(3661, 123000000)},
+		{ts(-1, 5e8), time.Second, ts(-1, 0)},
+		{ts(-1, 5e8), time.Minute, ts(-60, 0)},
+		{ts(10, 7), 0, ts(10, 7)},
+		{ts(maxTimestamp, 999999999), 7 * time.Nanosecond, ts(maxTimestamp, 999999998)},
+	}
+	for _, tt := range truncateTests {
+		if got := tt.x.Truncate(tt.d); !cmp.Equal(got, tt.want, opts) {
+			t.Errorf("%v.Truncate(%v) = %v, want %v", tt.x, tt.d, got, tt.want)
+		}
+	}
+	// Compare with time.Time.Truncate for a duration that evenly divides
+	// the offset between the Unix epoch and the zero time.
+	now := time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC)
+	if got, want := tspb.New(now).Truncate(time.Minute), tspb.New(now.Truncate(time.Minute)); !cmp.Equal(got, want, opts) {
+		t.Errorf("Truncate(time.Minute) = %v, want %v", got, want)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	var (
+		minTime    = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+		maxTime    = time.Date(9999, 12, 31, 23, 59, 59, 1e9-1, time.UTC)
+		year10000  = time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)
+		maxGoTime  = time.Unix(math.MaxInt64-62135596800, 1e9-1).UTC()
+		errInvalid = textError("after 9999-12-31")
+	)
+	tests := []struct {
+		in       *tspb.Timestamp
+		policy   tspb.RangePolicy
+		wantTime time.Time
+		wantErr  error
+	}{
+		{in: tspb.New(maxTime), policy: tspb.RangeError, wantTime: maxTime},
+		{in: tspb.New(year10000), policy: tspb.RangeError, wantErr: errInvalid},
+		{in: tspb.New(year10000), policy: tspb.RangeClamp, wantTime: maxTime},
+		{in: tspb.New(year10000), policy: tspb.RangeSaturate, wantTime: year10000},
+		{in: tspb.New(minTime.Add(-time.Nanosecond)), policy: tspb.RangeError, wantErr: textError("before 0001-01-01")},
+		{in: tspb.New(minTime.Add(-time.Nanosecond)), policy: tspb.RangeClamp, wantTime: minTime},
+		{in: &tspb.Timestamp{Seconds: math.MaxInt64}, policy: tspb.RangeClamp, wantTime: maxTime},
+		{in: &tspb.Timestamp{Seconds: math.MaxInt64}, policy: tspb.RangeSaturate, wantTime: maxGoTime},
+		{in: &tspb.Timestamp{Seconds: 1, Nanos: -1}, policy: tspb.RangeError, wantErr: textError("out-of-range nanos")},
+		{in: &tspb.Timestamp{Seconds: 1, Nanos: -1}, policy: tspb.RangeClamp, wantTime: time.Unix(0, 1e9-1).UTC()},
+		{in: nil, policy: tspb.RangeError, wantErr: textError("invalid nil Timestamp")},
+		{in: nil, policy: tspb.RangeSaturate, wantTime: time.Unix(0, 0).UTC()},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.in.AsTimeWithPolicy(tt.policy)
+		if diff := cmp.Diff(tt.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+			t.Errorf("AsTimeWithPolicy(%v, %v) error mismatch (-want +got):\n%s", tt.in, tt.policy, diff)
+		}
+		if diff := cmp.Diff(tt.wantTime, got); diff != "" {
+			t.Errorf("AsTimeWithPolicy(%v, %v) mismatch (-want +got):\n%s", tt.in, tt.policy, diff)
+		}
+	}
+
+	if _, err := tspb.NewWithPolicy(year10000, tspb.RangeError); !cmp.Equal(errInvalid, err, cmpopts.EquateErrors()) {
+		t.Errorf("NewWithPolicy(%v, RangeError) error = %v, want %v", year10000, err, errInvalid)
+	}
+	for _, policy := range []tspb.RangePolicy{tspb.RangeClamp, tspb.RangeSaturate} {
+		want := tspb.New(maxTime)
+		if policy == tspb.RangeSaturate {
+			want = tspb.New(year10000)
+		}
+		got, err := tspb.NewWithPolicy(year10000, policy)
+		if err != nil {
+			t.Errorf("NewWithPolicy(%v, %v) error: %v", year10000, policy, err)
+		}
+		if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("NewWithPolicy(%v, %v) mismatch (-want +got):\n%s", year10000, policy, diff)
+		}
+	}
+}
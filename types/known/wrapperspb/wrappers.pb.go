@@ -536,6 +536,69 @@ func (x *BytesValue) GetValue() []byte {
 	return nil
 }
 
+// Wrapped is a constraint for the Go types of the values of wrapper messages.
+type Wrapped interface {
+	float64 | float32 | int64 | uint64 | int32 | uint32 | bool | string | []byte
+}
+
+// Message is a constraint for pointers to wrapper messages
+// whose values are of type T.
+type Message[T Wrapped] interface {
+	*DoubleValue | *FloatValue | *Int64Value | *UInt64Value | *Int32Value | *UInt32Value | *BoolValue | *StringValue | *BytesValue
+	GetValue() T
+}
+
+// New stores v in a new wrapper message of type M and returns a pointer to it.
+// For example, New[*Int32Value](5) is equivalent to Int32(5).
+func New[M Message[T], T Wrapped](v T) M {
+	var m M
+	switch p := any(&m).(type) {
+	case **DoubleValue:
+		*p = &DoubleValue{Value: any(v).(float64)}
+	case **FloatValue:
+		*p = &FloatValue{Value: any(v).(float32)}
+	case **Int64Value:
+		*p = &Int64Value{Value: any(v).(int64)}
+	case **UInt64Value:
+		*p = &UInt64Value{Value: any(v).(uint64)}
+	case **Int32Value:
+		*p = &Int32Value{Value: any(v).(int32)}
+	case **UInt32Value:
+		*p = &UInt32Value{Value: any(v).(uint32)}
+	case **BoolValue:
+		*p = &BoolValue{Value: any(v).(bool)}
+	case **StringValue:
+		*p = &StringValue{Value: any(v).(string)}
+	case **BytesValue:
+		*p = &BytesValue{Value: any(v).([]byte)}
+	}
+	return m
+}
+
+// Value returns the value of m, or the zero value of T if m is nil.
+func Value[M Message[T], T Wrapped](m M) T {
+	return m.GetValue()
+}
+
+// FromPtr stores *p in a new wrapper message of type M and returns
+// a pointer to it. It returns nil if p is nil.
+func FromPtr[M Message[T], T Wrapped](p *T) M {
+	if p == nil {
+		return nil
+	}
+	return New[M](*p)
+}
+
+// ToPtr returns a pointer to a new variable holding the value of m.
+// It returns nil if m is nil.
+func ToPtr[M Message[T], T Wrapped](m M) *T {
+	if m == nil {
+		return nil
+	}
+	v := m.GetValue()
+	return &v
+}
+
 var File_google_protobuf_wrappers_proto protoreflect.FileDescriptor
 
 var file_google_protobuf_wrappers_proto_rawDesc = []byte{
@@ -0,0 +1,55 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wrapperspb_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	wpb "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestGeneric(t *testing.T) {
+	tests := []struct {
+		got, want proto.Message
+	}{
+		{wpb.New[*wpb.BoolValue](true), wpb.Bool(true)},
+		{wpb.New[*wpb.Int32Value](-5), wpb.Int32(-5)},
+		{wpb.New[*wpb.Int64Value](-5), wpb.Int64(-5)},
+		{wpb.New[*wpb.UInt32Value](5), wpb.UInt32(5)},
+		{wpb.New[*wpb.UInt64Value](5), wpb.UInt64(5)},
+		{wpb.New[*wpb.FloatValue](1.5), wpb.Float(1.5)},
+		{wpb.New[*wpb.DoubleValue](1.5), wpb.Double(1.5)},
+		{wpb.New[*wpb.StringValue]("s"), wpb.String("s")},
+		{wpb.New[*wpb.BytesValue]([]byte("b")), wpb.Bytes([]byte("b"))},
+	}
+	for _, tt := range tests {
+		if !proto.Equal(tt.got, tt.want) {
+			t.Errorf("New = %v, want %v", tt.got, tt.want)
+		}
+	}
+
+	if got := wpb.Value(wpb.Int32(5)); got != 5 {
+		t.Errorf("Value(Int32(5)) = %v, want 5", got)
+	}
+	if got := wpb.Value((*wpb.StringValue)(nil)); got != "" {
+		t.Errorf("Value(nil) = %q, want empty string", got)
+	}
+
+	i := int64(7)
+	if got := wpb.FromPtr[*wpb.Int64Value](&i); got.GetValue() != 7 {
+		t.Errorf("FromPtr(&7) = %v, want value 7", got)
+	}
+	if got := wpb.FromPtr[*wpb.Int64Value](nil); got != nil {
+		t.Errorf("FromPtr(nil) = %v, want nil", got)
+	}
+	if got := wpb.ToPtr(wpb.Bool(true)); got == nil || !*got {
+		t.Errorf("ToPtr(Bool(true)) = %v, want pointer to true", got)
+	}
+	if got := wpb.ToPtr((*wpb.BoolValue)(nil)); got != nil {
+		t.Errorf("ToPtr(nil) = %v, want nil", got)
+	}
+}